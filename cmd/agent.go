@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// agentCmd groups the coding-agent integration: installing Portal's hooks into
+// an agent's settings, and the command those hooks call to report what the
+// agent in a pane is doing.
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Track coding agents running in tmux panes",
+}
+
+// agentIngestCmd records one coding-agent hook event. The agent runs it as a
+// hook command with the event JSON on stdin — or, for Codex's notify program
+// and OpenCode's plugin, as the one argument; $TMUX_PANE names the pane the
+// agent runs in. --agent picks the adapter that reads the payload (Claude
+// Code's when unset, as hooks from before adapters existed expect). The event
+// is normalised to an agent.Update and posted to the state directory's agent
+// inbox, which the daemon drains on its next tick.
+//
+// ingest never fails the hook. A non-zero exit would surface an error in the
+// agent's UI on every tool call, and exit 2 from a PreToolUse hook blocks the
+// tool outright — so every failure is logged under the agent component and
+// ingest exits 0. It also writes nothing to stdout, which Claude Code would
+// read as hook output (for UserPromptSubmit, as added context). Outside tmux
+// there is no pane to attribute the event to and ingest does nothing.
+var agentIngestCmd = &cobra.Command{
+	Use:           "ingest [payload]",
+	Short:         "Record a coding-agent hook event (internal, invoked by agent hooks)",
+	Args:          cobra.MaximumNArgs(1),
+	Hidden:        true,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pane := os.Getenv("TMUX_PANE")
+		if pane == "" {
+			return nil
+		}
+		name, _ := cmd.Flags().GetString("agent")
+		adapter, ok := agent.LookupAdapter(name)
+		if !ok {
+			agentLogger.Warn("ingest: unknown agent", "pane", pane, "agent", name)
+			return nil
+		}
+		payload := cmd.InOrStdin()
+		if len(args) == 1 {
+			payload = strings.NewReader(args[0])
+		}
+		event, err := adapter.Parse(payload)
+		if err != nil {
+			agentLogger.Warn("ingest: unreadable hook event", "pane", pane, "error", err)
+			return nil
+		}
+		dir, err := state.EnsureDir()
+		if err != nil {
+			agentLogger.Warn("ingest: state dir unavailable", "error", err)
+			return nil
+		}
+		if v, _ := cmd.Flags().GetInt("hook-version"); v != agent.HookVersion {
+			agentLogger.Debug("ingest: hooks not current; run xctl agent install", "pane", pane, "hook_version", v)
+		}
+		update := agent.NewUpdate(pane, adapter.Name(), event, time.Now())
+		if err := agent.Post(state.AgentInbox(dir), update); err != nil {
+			agentLogger.Warn("ingest: post update failed", "pane", pane, "event", event.Name, "error", err)
+			return nil
+		}
+		agentLogger.Debug("ingest", "pane", pane, "event", event.Name, "state", string(update.State))
+		return nil
+	},
+}
+
+// agentPingTimeout bounds the picker's per-refresh liveness ping of the
+// daemon. A live daemon answers on a local socket in well under it.
+const agentPingTimeout = 250 * time.Millisecond
+
+// agentStateSource is the picker's tui.AgentSource: it reads agent.json,
+// which the daemon rewrites on every state change, and rolls it up per
+// session.
+//
+// agent.json is only current while the daemon runs — nothing else drains the
+// inbox or drops a closed pane's entry — so a down daemon reports no states
+// rather than glyphs frozen at whatever it last wrote. alive is the liveness
+// seam; nil means daemonAnswers.
+type agentStateSource struct {
+	stateDir string
+	alive    func(dir string) bool
+}
+
+// daemonAnswers reports whether the daemon answers a ping on its control
+// socket.
+func daemonAnswers(dir string) bool {
+	client, err := control.Dial(state.DaemonSocket(dir), agentPingTimeout)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = client.Close() }()
+	return client.Call(control.MethodPing, nil, nil) == nil
+}
+
+// AgentStates returns each tracked session's most urgent agent state, or none
+// while the daemon is down.
+func (a *agentStateSource) AgentStates() (map[string]agent.State, error) {
+	alive := a.alive
+	if alive == nil {
+		alive = daemonAnswers
+	}
+	if !alive(a.stateDir) {
+		return nil, nil
+	}
+	table, err := agent.Load(state.AgentJSON(a.stateDir))
+	if err != nil {
+		return nil, err
+	}
+	return table.BySession(), nil
+}
+
+// agentIntegrations resolves the agents Portal installs hooks for against the
+// user's home directory.
+func agentIntegrations() ([]agent.Integration, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return agent.Integrations(home, os.Getenv), nil
+}
+
+// agentIntegrationNames lists the names agent install, uninstall and status
+// accept.
+func agentIntegrationNames() []string {
+	var names []string
+	for _, in := range agent.Integrations("", func(string) string { return "" }) {
+		names = append(names, in.Name)
+	}
+	return names
+}
+
+// selectIntegrations picks the named agents, or with no names every agent
+// keep reports true for.
+func selectIntegrations(names []string, keep func(agent.Integration) bool) ([]agent.Integration, error) {
+	all, err := agentIntegrations()
+	if err != nil {
+		return nil, err
+	}
+	var out []agent.Integration
+	for _, in := range all {
+		if len(names) == 0 && keep(in) || slices.Contains(names, in.Name) {
+			out = append(out, in)
+		}
+	}
+	return out, nil
+}
+
+var agentInstallCmd = &cobra.Command{
+	Use:   "install [agent...]",
+	Short: "Install Portal's hooks into coding agents' settings",
+	Long: `Install Portal's hooks into each agent's settings file, so the agent reports
+what it is doing in each pane.
+
+With no agent named, every agent whose config directory exists is set up,
+except Codex, whose hook schema is unconfirmed: name it to install it anyway.
+Portal adds one entry per hook event and leaves your own hooks alone. Running
+it again changes nothing; after an upgrade it replaces the entries an older
+Portal wrote.`,
+	ValidArgs: agentIntegrationNames(),
+	Args:      cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, err := selectIntegrations(args, agent.Integration.InstallByDefault)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return errors.New("no supported coding agent found (looked for ~/.claude); name one to install anyway")
+		}
+		w := cmd.OutOrStdout()
+		for _, in := range targets {
+			changed, err := in.Install()
+			if err != nil {
+				return fmt.Errorf("%s: %w", in.Label, err)
+			}
+			verdict := "hooks already current in"
+			if changed {
+				verdict = "installed hooks in"
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s %s\n", in.Label, verdict, in.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var agentUninstallCmd = &cobra.Command{
+	Use:   "uninstall [agent...]",
+	Short: "Remove Portal's hooks from coding agents' settings",
+	Long: `Remove every hook entry Portal installed from each agent's settings file,
+leaving your own hooks alone. With no agent named, every agent found is
+cleaned.`,
+	ValidArgs: agentIntegrationNames(),
+	Args:      cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, err := selectIntegrations(args, agent.Integration.Detected)
+		if err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		for _, in := range targets {
+			removed, err := in.Uninstall()
+			if err != nil {
+				return fmt.Errorf("%s: %w", in.Label, err)
+			}
+			verdict := "no Portal hooks in"
+			if removed > 0 {
+				verdict = fmt.Sprintf("removed %s from", pluralCount(removed, "hook", "hooks"))
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s %s\n", in.Label, verdict, in.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// agentHookRecord is the --json schema for one agent in `agent status`.
+type agentHookRecord struct {
+	Agent    string `json:"agent"`
+	Path     string `json:"path"`
+	Detected bool   `json:"detected"`
+	Status   string `json:"status"`
+	agent.HookStatus
+}
+
+var agentStatusCmd = &cobra.Command{
+	Use:       "status [agent...]",
+	Short:     "Show whether Portal's hooks are installed and current",
+	ValidArgs: agentIntegrationNames(),
+	Args:      cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		targets, err := selectIntegrations(args, func(agent.Integration) bool { return true })
+		if err != nil {
+			return err
+		}
+		records := make([]agentHookRecord, 0, len(targets))
+		for _, in := range targets {
+			st, err := in.Status()
+			if err != nil {
+				return fmt.Errorf("%s: %w", in.Label, err)
+			}
+			records = append(records, agentHookRecord{Agent: in.Name, Path: in.Path, Detected: in.Detected(), Status: st.Summary(), HookStatus: st})
+		}
+		w := cmd.OutOrStdout()
+		if asJSON {
+			return writeJSON(w, records)
+		}
+		for _, r := range records {
+			line := fmt.Sprintf("%s\t%s\t%s", r.Agent, r.Status, r.Path)
+			if !r.Detected {
+				line += "\t(agent not found)"
+			}
+			if len(r.Stale) > 0 {
+				line += "\tstale: " + strings.Join(r.Stale, ", ")
+			}
+			if len(r.Missing) > 0 && len(r.Current) > 0 {
+				line += "\tmissing: " + strings.Join(r.Missing, ", ")
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	agentIngestCmd.Flags().String("agent", agent.DefaultAdapter, "Adapter that reads the payload: "+strings.Join(agent.AdapterNames(), ", "))
+	agentIngestCmd.Flags().Int("hook-version", 0, "Version of the installed hook entry (set by agent install)")
+	agentStatusCmd.Flags().Bool("json", false, "Output as JSON")
+	agentCmd.AddCommand(agentIngestCmd, agentInstallCmd, agentUninstallCmd, agentStatusCmd)
+
+	rootCmd.AddCommand(agentCmd)
+}
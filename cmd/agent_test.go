@@ -0,0 +1,244 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/state"
+)
+
+func runAgentIngest(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(append([]string{"agent", "ingest"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestAgentIngest(t *testing.T) {
+	runner := &recordingRunner{}
+	bootstrapDeps = &BootstrapDeps{Orchestrator: runner}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	t.Run("posts the normalised event for the pane to the inbox", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_STATE_DIR", dir)
+		t.Setenv("TMUX_PANE", "%7")
+
+		out, err := runAgentIngest(t, `{"session_id":"s1","hook_event_name":"Notification","message":"Claude needs your permission to use Bash"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "" {
+			t.Errorf("stdout = %q, want nothing (the agent reads it as hook output)", out)
+		}
+		updates, err := agent.Drain(state.AgentInbox(dir))
+		if err != nil || len(updates) != 1 {
+			t.Fatalf("inbox = %v, %v; want one update", updates, err)
+		}
+		if u := updates[0]; u.PaneID != "%7" || u.Agent != "claude" || u.State != agent.Waiting || u.Event != "Notification" {
+			t.Errorf("update = %+v", u)
+		}
+		if runner.calls != 0 {
+			t.Errorf("orchestrator ran %d times, want agent bootstrap-exempt", runner.calls)
+		}
+	})
+
+	t.Run("--agent picks the adapter and a payload argument replaces stdin", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_STATE_DIR", dir)
+		t.Setenv("TMUX_PANE", "%7")
+
+		if _, err := runAgentIngest(t, "", "--agent", "opencode", `{"type":"permission.updated","properties":{"sessionID":"ses_1","title":"Run rm -rf build"}}`); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		updates, _ := agent.Drain(state.AgentInbox(dir))
+		if len(updates) != 1 || updates[0].Agent != "opencode" || updates[0].State != agent.Waiting || updates[0].Detail != "Run rm -rf build" {
+			t.Errorf("inbox = %+v, want one opencode waiting update", updates)
+		}
+	})
+
+	t.Run("an unknown agent posts nothing and exits zero", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_STATE_DIR", dir)
+		t.Setenv("TMUX_PANE", "%7")
+
+		if _, err := runAgentIngest(t, `{"hook_event_name":"Stop"}`, "--agent", "nope"); err != nil {
+			t.Errorf("err = %v, want nil so the agent's hook never fails", err)
+		}
+		if updates, _ := agent.Drain(state.AgentInbox(dir)); len(updates) != 0 {
+			t.Errorf("inbox = %v, want empty", updates)
+		}
+	})
+
+	t.Run("outside tmux nothing is posted", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_STATE_DIR", dir)
+		t.Setenv("TMUX_PANE", "")
+
+		if _, err := runAgentIngest(t, `{"hook_event_name":"Stop"}`); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if updates, _ := agent.Drain(state.AgentInbox(dir)); len(updates) != 0 {
+			t.Errorf("inbox = %v, want empty", updates)
+		}
+	})
+
+	t.Run("an unreadable event still exits zero", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("PORTAL_STATE_DIR", dir)
+		t.Setenv("TMUX_PANE", "%7")
+
+		if _, err := runAgentIngest(t, `not json`); err != nil {
+			t.Errorf("err = %v, want nil so the agent's hook never fails", err)
+		}
+		if updates, _ := agent.Drain(state.AgentInbox(dir)); len(updates) != 0 {
+			t.Errorf("inbox = %v, want empty", updates)
+		}
+	})
+}
+
+// isolateAgentSettings points every agent integration at a fresh, empty
+// config directory so a test never reads or edits the developer's real agent
+// settings.
+func isolateAgentSettings(t *testing.T) (claudeDir, codexDir string) {
+	t.Helper()
+	claudeDir = filepath.Join(t.TempDir(), "claude")
+	codexDir = filepath.Join(t.TempDir(), "codex")
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+	t.Setenv("CODEX_HOME", codexDir)
+	return claudeDir, codexDir
+}
+
+func runAgentCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(append([]string{"agent"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestAgentStateSource(t *testing.T) {
+	dir := t.TempDir()
+	table := agent.Table{"%1": {PaneID: "%1", Session: "work", State: agent.Waiting}}
+	if err := table.Save(state.AgentJSON(dir)); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	t.Run("reports agent.json while the daemon answers", func(t *testing.T) {
+		src := &agentStateSource{stateDir: dir, alive: func(string) bool { return true }}
+
+		states, err := src.AgentStates()
+		if err != nil {
+			t.Fatalf("AgentStates: %v", err)
+		}
+		if states["work"] != agent.Waiting {
+			t.Errorf("states = %v, want work waiting", states)
+		}
+	})
+
+	t.Run("reports no states while the daemon is down", func(t *testing.T) {
+		src := &agentStateSource{stateDir: dir, alive: func(string) bool { return false }}
+
+		states, err := src.AgentStates()
+		if err != nil {
+			t.Fatalf("AgentStates: %v", err)
+		}
+		if len(states) != 0 {
+			t.Errorf("states = %v, want none from a stale agent.json", states)
+		}
+	})
+
+	t.Run("treats a missing socket as a down daemon", func(t *testing.T) {
+		if daemonAnswers(dir) {
+			t.Error("daemonAnswers = true with no socket, want false")
+		}
+	})
+}
+
+func TestAgentInstallCommands(t *testing.T) {
+	runner := &recordingRunner{}
+	bootstrapDeps = &BootstrapDeps{Orchestrator: runner}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	t.Run("install with no agent found asks for one", func(t *testing.T) {
+		isolateAgentSettings(t)
+		_, err := runAgentCmd(t, "install")
+		if err == nil || !strings.Contains(err.Error(), "no supported coding agent found") {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	t.Run("install sets up each detected agent once", func(t *testing.T) {
+		claudeDir, codexDir := isolateAgentSettings(t)
+		for _, dir := range []string{claudeDir, codexDir} {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		settings := filepath.Join(claudeDir, "settings.json")
+
+		out, err := runAgentCmd(t, "install")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "Claude Code: installed hooks in "+settings+"\n" {
+			t.Errorf("output = %q", out)
+		}
+		out, _ = runAgentCmd(t, "install")
+		if out != "Claude Code: hooks already current in "+settings+"\n" {
+			t.Errorf("second output = %q", out)
+		}
+		data, _ := os.ReadFile(settings)
+		if !strings.Contains(string(data), "command -v portal >/dev/null && portal agent ingest --agent claude --hook-version") {
+			t.Errorf("settings = %s", data)
+		}
+		if _, err := os.Stat(filepath.Join(codexDir, "hooks.json")); !os.IsNotExist(err) {
+			t.Errorf("codex hooks.json written without being named: %v", err)
+		}
+	})
+
+	t.Run("status reports each agent and uninstall removes the hooks", func(t *testing.T) {
+		isolateAgentSettings(t)
+		if _, err := runAgentCmd(t, "install", "codex"); err != nil {
+			t.Fatalf("install codex: %v", err)
+		}
+
+		out, err := runAgentCmd(t, "status", "--json")
+		if err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		var records []agentHookRecord
+		if err := json.Unmarshal([]byte(out), &records); err != nil {
+			t.Fatalf("decode %q: %v", out, err)
+		}
+		got := map[string]string{}
+		for _, r := range records {
+			got[r.Agent] = r.Status
+		}
+		if got["claude"] != "not installed" || got["codex"] != "installed" {
+			t.Errorf("statuses = %v", got)
+		}
+
+		out, err = runAgentCmd(t, "uninstall")
+		if err != nil || !strings.Contains(out, "Codex: removed 5 hooks from") {
+			t.Errorf("uninstall = %q, %v", out, err)
+		}
+	})
+
+	if runner.calls != 0 {
+		t.Errorf("bootstrap ran %d times; agent must be exempt", runner.calls)
+	}
+}
@@ -42,16 +42,35 @@ var aliasRmCmd = &cobra.Command{
 	},
 }
 
+// aliasRecord is the --json / --format schema for one `alias list` entry.
+type aliasRecord struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
 var aliasListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all path aliases",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+
 		store, err := loadAliasStore()
 		if err != nil {
 			return err
 		}
 
+		if opts.structured() {
+			var records []aliasRecord
+			for _, a := range store.List() {
+				records = append(records, aliasRecord{Name: a.Name, Path: a.Path})
+			}
+			return writeRecords(cmd.OutOrStdout(), opts, records)
+		}
+
 		for _, a := range store.List() {
 			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", a.Name, a.Path); err != nil {
 				return err
@@ -109,6 +128,8 @@ func aliasFilePath() (string, error) {
 }
 
 func init() {
+	addOutputFlags(aliasListCmd, "name, path")
+
 	aliasCmd.AddCommand(aliasSetCmd)
 	aliasCmd.AddCommand(aliasRmCmd)
 	aliasCmd.AddCommand(aliasListCmd)
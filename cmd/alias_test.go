@@ -318,6 +318,60 @@ func TestAliasListCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("--json emits name and path records", func(t *testing.T) {
+		dir := t.TempDir()
+		aliasFile := filepath.Join(dir, "aliases")
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+
+		if err := os.WriteFile(aliasFile, []byte("zebra=/z/path\napple=/a/path\n"), 0o644); err != nil {
+			t.Fatalf("failed to write seed file: %v", err)
+		}
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"alias", "list", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := `[
+  {
+    "name": "apple",
+    "path": "/a/path"
+  },
+  {
+    "name": "zebra",
+    "path": "/z/path"
+  }
+]
+`
+		if got := buf.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("--format applies the template per alias", func(t *testing.T) {
+		dir := t.TempDir()
+		aliasFile := filepath.Join(dir, "aliases")
+		t.Setenv("PORTAL_ALIASES_FILE", aliasFile)
+
+		if err := os.WriteFile(aliasFile, []byte("apple=/a/path\n"), 0o644); err != nil {
+			t.Fatalf("failed to write seed file: %v", err)
+		}
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"alias", "list", "--format", `{{.name}}\t{{.path}}`})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "apple\t/a/path\n"; got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
 	t.Run("exits 0 on success", func(t *testing.T) {
 		dir := t.TempDir()
 		aliasFile := filepath.Join(dir, "aliases")
@@ -156,8 +156,10 @@ func TestCompositeBootstrap_ScrollbackDirPathSetStableAcross10Observations(t *te
 //     caller's separate len(baseline) > 0 assertion.
 //
 // Symlinks and subdirectories are excluded — the daemon's capture loop
-// writes only regular `.bin` files keyed by paneKey directly under
-// scrollback/.
+// writes only regular `.bin` manifests keyed by paneKey directly under
+// scrollback/. The chunk store under scrollback/chunks/ is skipped
+// wholesale: chunks come and go as live panes grow, which is not churn in
+// the per-pane path-set this test pins.
 //
 // Uses lstat semantics (the file mode reported by fs.DirEntry.Type() is
 // the lstat mode, not the stat mode) so a symlink pointing at a regular
@@ -180,6 +182,9 @@ func snapshotScrollbackPaths(t *testing.T, dir string) (map[string]struct{}, boo
 		if path == dir {
 			return nil
 		}
+		if d.IsDir() && path == state.ScrollbackChunksDir(filepath.Dir(dir)) {
+			return filepath.SkipDir
+		}
 		// Type() is the lstat mode (per filepath.WalkDir contract).
 		// Exclude directories and non-regular files (symlinks, sockets,
 		// FIFOs — none of which the daemon should be writing under
@@ -0,0 +1,59 @@
+//go:build integration
+
+// Drift guard for runDaemonTick's dormant and restore-policy steps: a
+// session killed between two ticks is carried as dormant, and a
+// never-policy session is dropped before the commit, as captureAndCommit
+// does in production.
+
+package bootstrap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmuxtest"
+)
+
+func TestRunDaemonTick_CarriesDormantAndDropsNeverSaved(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test; -short")
+	}
+	tmuxtest.SkipIfNoTmux(t)
+
+	stateDir := newIntegrationStateDir(t)
+	ts := tmuxtest.New(t, "ptl-dormant-")
+	client := ts.Client()
+
+	seedKeepAlivePane(t, ts)
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		ts.Run(t, "new-session", "-d", "-s", name, "sleep", "infinity")
+		ts.WaitForSession(t, name, 2*time.Second)
+	}
+
+	first := runDaemonTick(t, client, stateDir, withEmptyScrollback())
+	ts.Run(t, "kill-session", "-t", "beta")
+
+	never := func(s state.Session) prefs.RestorePolicy {
+		if s.Name == "gamma" {
+			return prefs.RestoreNever
+		}
+		return prefs.RestoreAlways
+	}
+	second := runDaemonTick(t, client, stateDir, withEmptyScrollback(), withPrevIndex(&first), withRestorePolicy(never))
+
+	got := map[string]bool{}
+	for _, s := range second.Sessions {
+		got[s.Name] = s.Dormant
+	}
+	if dormant, ok := got["alpha"]; !ok || dormant {
+		t.Errorf("alpha = present %v, dormant %v; want live", ok, dormant)
+	}
+	if dormant, ok := got["beta"]; !ok || !dormant {
+		t.Errorf("beta = present %v, dormant %v; want carried as dormant", ok, dormant)
+	}
+	if _, ok := got["gamma"]; ok {
+		t.Error("gamma (restore policy never) was committed")
+	}
+}
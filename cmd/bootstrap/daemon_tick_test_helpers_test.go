@@ -13,6 +13,9 @@
 // cmd/state_daemon.go captureAndCommit:
 //   - state.ListSkeletonMarkers   — read the skip-save set
 //   - state.CaptureStructure      — walk live sessions/windows/panes
+//   - state.CarryDormant          — keep sessions gone since prev as dormant
+//   - state.DropNeverSaved        — when withRestorePolicy() is set
+//   - dormant-session skip        — dormant sessions have no panes to capture
 //   - per-pane skip-save guard    — when WithSkipGuard() is set
 //   - state.CaptureAndHashPane    — production-shape scrollback bytes
 //     (or empty bytes when withEmptyScrollback() is set, used by the
@@ -21,15 +24,18 @@
 //   - state.WriteScrollbackIfChanged
 //   - state.Commit                — atomically persist sessions.json
 //
-// PrevIndex is intentionally nil — Fix Component A's merge filter only
-// kicks in when both prev and skipSet are non-empty, and the callers
+// PrevIndex defaults to nil — Fix Component A's merge filter only
+// kicks in when both prev and skipSet are non-empty, and most callers
 // of this helper focus on single-tick flows, not the merge filter
-// (covered separately by tasks 2-1 and 2-2).
+// (covered separately by tasks 2-1 and 2-2). withPrevIndex supplies one
+// for multi-tick flows; it feeds CaptureStructure and CarryDormant exactly
+// as deps.PrevIndex does in production.
 
 package bootstrap_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
@@ -48,6 +54,10 @@ type daemonTickOpts struct {
 	// round-trip which overwrites the one byte-compared scrollback
 	// file with a deterministic ANSI fixture after the commit.
 	emptyScrollback bool
+	// prev mirrors deps.PrevIndex: the index the previous tick committed.
+	prev *state.Index
+	// policy mirrors deps.RestorePolicy(); nil keeps every session.
+	policy state.RestorePolicyFunc
 }
 
 // daemonTickOption configures runDaemonTick. Functional options keep
@@ -73,6 +83,18 @@ func withEmptyScrollback() daemonTickOption {
 	return func(o *daemonTickOpts) { o.emptyScrollback = true }
 }
 
+// withPrevIndex supplies the previous tick's index, as deps.PrevIndex
+// does, so sessions gone since then are carried as dormant.
+func withPrevIndex(prev *state.Index) daemonTickOption {
+	return func(o *daemonTickOpts) { o.prev = prev }
+}
+
+// withRestorePolicy applies a restore policy, as deps.RestorePolicy does,
+// dropping never-policy sessions before the commit.
+func withRestorePolicy(policy state.RestorePolicyFunc) daemonTickOption {
+	return func(o *daemonTickOpts) { o.policy = policy }
+}
+
 // runDaemonTick drives a single daemon-equivalent capture-and-commit
 // against the live tmux server backing client. It returns the captured
 // state.Index so callers can sanity-check topology.
@@ -101,14 +123,21 @@ func runDaemonTick(
 		t.Fatalf("ListSkeletonMarkers: %v", err)
 	}
 
-	idx, err := state.CaptureStructure(client, skipSet, nil, nil)
+	idx, err := state.CaptureStructure(client, skipSet, opts.prev, nil)
 	if err != nil {
 		t.Fatalf("CaptureStructure: %v", err)
 	}
+	state.CarryDormant(&idx, opts.prev, time.Now(), opts.policy)
+	if opts.policy != nil {
+		state.DropNeverSaved(&idx, opts.policy)
+	}
 
 	hm := state.HashMap{}
 	anyChanged := false
 	for _, sess := range idx.Sessions {
+		if sess.Dormant {
+			continue
+		}
 		for _, win := range sess.Windows {
 			for _, pane := range win.Panes {
 				key := state.SanitizePaneKey(sess.Name, win.Index, pane.Index)
@@ -120,6 +120,7 @@ func buildProductionOrchestrator() (*bootstrap.Orchestrator, *tmux.Client) {
 		Client:   client,
 		StateDir: stateDir,
 		Logger:   restoreLogger,
+		Policy:   loadRestorePolicy(),
 	}
 
 	orch := &bootstrap.Orchestrator{
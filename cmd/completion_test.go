@@ -234,19 +234,16 @@ func TestCompletionWiring(t *testing.T) {
 		}
 	})
 
-	t.Run("kill offers nothing once one positional present", func(t *testing.T) {
-		withCompletionSessionNames(t, func() []string {
-			t.Error("seam must not be called once a positional is present")
-			return nil
-		})
+	t.Run("kill keeps completing, skipping names already given", func(t *testing.T) {
+		withCompletionSessionNames(t, func() []string { return []string{"api-1", "api-2", "web-2"} })
 
-		names, directive := killCmd.ValidArgsFunction(killCmd, []string{"x"}, "")
+		names, directive := killCmd.ValidArgsFunction(killCmd, []string{"api-1"}, "api")
 
 		if directive != cobra.ShellCompDirectiveNoFileComp {
 			t.Errorf("directive = %v, want ShellCompDirectiveNoFileComp", directive)
 		}
-		if names != nil {
-			t.Errorf("names = %v, want nil (ExactArgs(1) — only the first positional completes)", names)
+		if want := []string{"api-2"}; !slices.Equal(names, want) {
+			t.Errorf("names = %v, want %v", names, want)
 		}
 	})
 }
@@ -35,6 +35,17 @@ var configFileComponents = map[string]string{
 	// and its breadcrumb is suppressed via the empty component (mirrors the
 	// prefs.json precedent).
 	"terminals.json": "",
+	// resume.json holds the user-authored smart-resume rules. It is read-only
+	// to Portal and has no old-macOS-path predecessor either, so it follows
+	// terminals.json.
+	"resume.json": "",
+	// agent-rules.json holds the user-authored screen rules for coding agents
+	// without hooks; read-only to Portal, like resume.json.
+	"agent-rules.json": "",
+	// frecency.json is Portal's own visit history. It is written on every
+	// attach and mint, but as a ranking cache rather than user configuration it
+	// sits outside the audit-trail set, and it has no old-macOS-path predecessor.
+	"frecency.json": "",
 }
 
 // migrateConfigFile moves a config file from oldPath to newPath if oldPath
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// daemonCallTimeout bounds each request to the daemon. It outlasts the
+// daemon's own controlCallTimeout so a busy daemon answers "daemon busy"
+// before the client gives up on it.
+const daemonCallTimeout = controlCallTimeout + 5*time.Second
+
+// daemonSessionsSchema lists the fields `daemon sessions --format` templates
+// can address.
+const daemonSessionsSchema = "name, dormant, windows, panes, saved_at, agents"
+
+// dialDaemon connects to the running daemon's control socket. A daemon that
+// is not running, or predates the socket, is reported as such rather than as
+// the raw dial error.
+func dialDaemon(timeout time.Duration) (*control.Client, error) {
+	dir, err := state.Dir()
+	if err != nil {
+		return nil, err
+	}
+	path := state.DaemonSocket(dir)
+	client, err := control.Dial(path, timeout)
+	if errors.Is(err, control.ErrUnreachable) {
+		return nil, fmt.Errorf("daemon not reachable at %s (it runs alongside the tmux server)", path)
+	}
+	return client, err
+}
+
+// callDaemon makes one request to the daemon and decodes its result.
+func callDaemon(method string, params, result any) error {
+	client, err := dialDaemon(daemonCallTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	return client.Call(method, params, result)
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Query and drive the running state daemon",
+}
+
+var daemonPingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the daemon is answering",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var res control.PingResult
+		if err := callDaemon(control.MethodPing, nil, &res); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "pong (pid %d, version %s, protocol %d)\n",
+			res.PID, doctorDaemonVersion(res.Version), res.Protocol)
+		return err
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the daemon is tracking",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		var res control.StatusResult
+		if err := callDaemon(control.MethodStatus, nil, &res); err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		if asJSON {
+			return writeJSON(w, res)
+		}
+		lastSave := "never"
+		if !res.LastSaveAt.IsZero() {
+			lastSave = res.LastSaveAt.Local().Format("2006-01-02 15:04:05")
+		}
+		reaper := "off"
+		if res.Reaper {
+			reaper = "on"
+		}
+		lines := []string{
+			fmt.Sprintf("pid:       %d", res.PID),
+			fmt.Sprintf("version:   %s", doctorDaemonVersion(res.Version)),
+			fmt.Sprintf("started:   %s", res.StartedAt.Local().Format("2006-01-02 15:04:05")),
+			fmt.Sprintf("last save: %s", lastSave),
+			fmt.Sprintf("restoring: %t", res.Restoring),
+			fmt.Sprintf("sessions:  %d running, %d dormant, %s", res.Sessions, res.Dormant, pluralCount(res.Panes, "pane", "panes")),
+			fmt.Sprintf("agents:    %s", formatAgentCounts(res.Agents)),
+			fmt.Sprintf("reaper:    %s", reaper),
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// formatAgentCounts renders the per-state agent counts in a fixed state
+// order, or "none".
+func formatAgentCounts(counts map[string]int) string {
+	out := ""
+	for _, st := range []string{"working", "waiting", "idle", "unknown"} {
+		if n := counts[st]; n > 0 {
+			if out != "" {
+				out += ", "
+			}
+			out += fmt.Sprintf("%d %s", n, st)
+		}
+	}
+	if out == "" {
+		return "none"
+	}
+	return out
+}
+
+var daemonSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save every session now and wait for the commit",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var res control.SaveResult
+		if err := callDaemon(control.MethodSaveNow, nil, &res); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "Saved %s at %s\n",
+			pluralCount(res.Sessions, "session", "sessions"), res.SavedAt.Local().Format("15:04:05"))
+		return err
+	},
+}
+
+var daemonSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "List the sessions the daemon last saved",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+		var infos []control.SessionInfo
+		if err := callDaemon(control.MethodSessions, nil, &infos); err != nil {
+			return err
+		}
+		w := cmd.OutOrStdout()
+		if opts.json || opts.format != "" {
+			return writeRecords(w, opts, infos)
+		}
+		for _, s := range infos {
+			status := "running"
+			if s.Dormant {
+				status = "dormant"
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\tsaved %s\n",
+				s.Name, status,
+				pluralCount(s.Windows, "window", "windows"),
+				pluralCount(s.Panes, "pane", "panes"),
+				s.SavedAt.Local().Format("2006-01-02 15:04"),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var daemonEventsCmd = &cobra.Command{
+	Use:   "events [type...]",
+	Short: "Stream daemon events as JSON lines until interrupted",
+	Long: `Stream daemon events, one JSON object per line, until interrupted.
+
+Event types are saved (a commit of sessions.json), agent (a pane's agent
+state changed) and reaped (the idle reaper killed a session). Name one or
+more to receive only those.`,
+	ValidArgs: []string{control.EventSaved, control.EventAgent, control.EventReaped},
+	Args:      cobra.OnlyValidArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := dialDaemon(daemonCallTimeout)
+		if err != nil {
+			return err
+		}
+		interrupted := make(chan os.Signal, 1)
+		signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(interrupted)
+		go func() {
+			<-interrupted
+			_ = client.Close()
+		}()
+		defer func() { _ = client.Close() }()
+
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		return client.Subscribe(args, func(ev control.Event) error {
+			return enc.Encode(ev)
+		})
+	},
+}
+
+func init() {
+	daemonStatusCmd.Flags().Bool("json", false, "Output as JSON")
+	addOutputFlags(daemonSessionsCmd, daemonSessionsSchema)
+	daemonCmd.AddCommand(daemonPingCmd, daemonStatusCmd, daemonSaveCmd, daemonSessionsCmd, daemonEventsCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
@@ -0,0 +1,94 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/state"
+)
+
+func runDaemonCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(append([]string{"daemon"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestDaemonCommand(t *testing.T) {
+	dir := shortStateDir(t)
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	runner := &recordingRunner{}
+	bootstrapDeps = &BootstrapDeps{Orchestrator: runner}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	t.Run("reports a daemon that is not running", func(t *testing.T) {
+		_, err := runDaemonCmd(t, "ping")
+		if err == nil || !strings.Contains(err.Error(), "daemon not reachable") {
+			t.Errorf("got %v, want daemon not reachable", err)
+		}
+	})
+
+	savedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	ln, err := control.Listen(state.DaemonSocket(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := control.NewServer(ln, control.HandlerFunc(func(method string, _ json.RawMessage) (any, error) {
+		switch method {
+		case control.MethodPing:
+			return control.PingResult{Protocol: control.Version, Version: "1.2.3", PID: 4242}, nil
+		case control.MethodSessions:
+			return []control.SessionInfo{{Name: "api", Windows: 1, Panes: 2, SavedAt: savedAt}}, nil
+		case control.MethodSaveNow:
+			return nil, &control.Error{Code: control.ErrCodeRestoring, Message: "a restore is in progress"}
+		}
+		return nil, &control.Error{Code: control.ErrCodeMethod, Message: method}
+	}), discardDaemonLogger())
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	t.Run("ping prints the daemon's identity", func(t *testing.T) {
+		out, err := runDaemonCmd(t, "ping")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "pong (pid 4242, version 1.2.3, protocol 1)\n" {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("sessions --json prints the daemon's records", func(t *testing.T) {
+		t.Cleanup(func() { _ = daemonSessionsCmd.Flags().Set("json", "false") })
+		out, err := runDaemonCmd(t, "sessions", "--json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var infos []control.SessionInfo
+		if err := json.Unmarshal([]byte(out), &infos); err != nil {
+			t.Fatalf("decode %q: %v", out, err)
+		}
+		if len(infos) != 1 || infos[0].Name != "api" || infos[0].Panes != 2 {
+			t.Errorf("sessions = %+v", infos)
+		}
+	})
+
+	t.Run("a refused save surfaces the daemon's reason", func(t *testing.T) {
+		_, err := runDaemonCmd(t, "save")
+		if err == nil || !strings.Contains(err.Error(), "a restore is in progress") {
+			t.Errorf("got %v", err)
+		}
+	})
+
+	if runner.calls != 0 {
+		t.Errorf("bootstrap ran %d times; daemon must be exempt", runner.calls)
+	}
+}
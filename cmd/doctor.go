@@ -7,7 +7,11 @@ import (
 	"io/fs"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
 	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/log"
 	"github.com/leeovery/portal/internal/project"
@@ -66,6 +70,23 @@ const (
 	checkNotEvaluable
 )
 
+// String returns the status's stable name, as used in doctor's structured
+// output.
+func (s checkStatus) String() string {
+	switch s {
+	case checkPass:
+		return "pass"
+	case checkFail:
+		return "fail"
+	case checkInfo:
+		return "info"
+	case checkNotEvaluable:
+		return "not-evaluable"
+	default:
+		return "unknown"
+	}
+}
+
 // checkResult is one line of the doctor report: the check's name, its outcome,
 // and a short human-readable detail.
 type checkResult struct {
@@ -120,6 +141,17 @@ type DoctorDeps struct {
 	// does not exercise the line), the host-terminal line is omitted rather than
 	// invoking a real detector.
 	Detector TerminalDetector
+	// PingDaemon asks the daemon on the control socket under dir to identify
+	// itself. Production dials state.DaemonSocket with doctorPingTimeout; an
+	// error wrapping control.ErrUnreachable means nothing is listening, and
+	// the daemon check falls back to daemon.pid. When nil (a direct-call unit
+	// test), the check reads daemon.pid alone.
+	PingDaemon func(dir string) (control.PingResult, error)
+	// AgentIntegrations are the coding agents whose hook settings the
+	// agent-hooks check inspects. Production resolves them under the user's
+	// home (agentIntegrations). When nil (a direct-call unit test, or an
+	// unresolvable home), the check is omitted.
+	AgentIntegrations []agent.Integration
 	// Resolve maps a detected identity to its adapter + resolution class.
 	// Production wires it from the shared buildProductionSpawnSeams bundle (its
 	// config-aware buildResolver().Resolve), so doctor, the picker, and the open
@@ -166,6 +198,10 @@ func resolveDoctorDeps() *DoctorDeps {
 		HookLister: client,
 		Detector:   seams.Detector,
 		Resolve:    seams.Resolve,
+		PingDaemon: pingDaemon,
+	}
+	if integrations, err := agentIntegrations(); err == nil {
+		deps.AgentIntegrations = integrations
 	}
 	// The stale-entry stores are built best-effort: a load-path error (an
 	// unresolvable config dir) leaves the pointer nil, and the corresponding
@@ -207,6 +243,12 @@ func resolveDoctorDeps() *DoctorDeps {
 	if doctorDeps.Resolve != nil {
 		deps.Resolve = doctorDeps.Resolve
 	}
+	if doctorDeps.PingDaemon != nil {
+		deps.PingDaemon = doctorDeps.PingDaemon
+	}
+	if doctorDeps.AgentIntegrations != nil {
+		deps.AgentIntegrations = doctorDeps.AgentIntegrations
+	}
 	return deps
 }
 
@@ -224,15 +266,22 @@ var doctorCmd = &cobra.Command{
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+		fix, _ := cmd.Flags().GetBool("fix")
+
 		deps := resolveDoctorDeps()
 		results, err := runDoctorDiagnosis(deps)
 		if err != nil {
 			return err
 		}
-		renderDoctorReport(cmd.OutOrStdout(), results)
 
-		fix, _ := cmd.Flags().GetBool("fix")
 		if !fix {
+			if err := writeDoctorReport(cmd.OutOrStdout(), opts, results); err != nil {
+				return err
+			}
 			if doctorUnhealthy(results) {
 				return ErrDoctorUnhealthy
 			}
@@ -240,17 +289,29 @@ var doctorCmd = &cobra.Command{
 		}
 
 		// --fix: apply the reversible repairs (rendering the initial report
-		// above first), then re-diagnose against the same deps so the checks
+		// first), then re-diagnose against the same deps so the checks
 		// observe post-repair on-disk state. The exit is driven SOLELY by the
 		// post-repair results — the repairs never touch it directly.
-		if err := runDoctorFix(cmd, deps); err != nil {
+		//
+		// In a structured mode stdout must stay one parseable document, so
+		// only the post-repair report is written there and the per-repair
+		// "Pruned …" lines go to stderr.
+		repairOut := cmd.OutOrStdout()
+		if opts.structured() {
+			repairOut = cmd.ErrOrStderr()
+		} else {
+			renderDoctorReport(cmd.OutOrStdout(), results)
+		}
+		if err := runDoctorFix(repairOut, deps); err != nil {
 			return err
 		}
 		postResults, err := runDoctorDiagnosis(deps)
 		if err != nil {
 			return err
 		}
-		renderDoctorReport(cmd.OutOrStdout(), postResults)
+		if err := writeDoctorReport(cmd.OutOrStdout(), opts, postResults); err != nil {
+			return err
+		}
 		if doctorUnhealthy(postResults) {
 			return ErrDoctorUnhealthy
 		}
@@ -258,10 +319,44 @@ var doctorCmd = &cobra.Command{
 	},
 }
 
+// doctorReport is the --json schema for a doctor run: the overall verdict
+// that also drives the exit code, and every check in catalog order.
+type doctorReport struct {
+	Healthy bool          `json:"healthy"`
+	Checks  []checkRecord `json:"checks"`
+}
+
+// checkRecord is the --json / --format schema for one checkResult. Status is
+// one of pass, fail, info, not-evaluable or unknown.
+type checkRecord struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// writeDoctorReport renders results in the selected output mode: the human
+// report, a doctorReport JSON document, or one --format line per check.
+func writeDoctorReport(w io.Writer, opts outputOptions, results []checkResult) error {
+	if !opts.structured() {
+		renderDoctorReport(w, results)
+		return nil
+	}
+
+	checks := make([]checkRecord, 0, len(results))
+	for _, r := range results {
+		checks = append(checks, checkRecord{Name: r.name, Status: r.status.String(), Detail: r.detail})
+	}
+	if opts.json {
+		return writeJSON(w, doctorReport{Healthy: !doctorUnhealthy(results), Checks: checks})
+	}
+	return writeFormatted(w, opts.format, checks)
+}
+
 // runDoctorFix applies doctor's low-stakes, reversible-by-reconstruction repairs
 // in a fixed order — prune stale hooks, prune stale projects — then runs the
-// unconditional log-sweep maintenance side-action. It is invoked AFTER the
-// initial diagnosis render and BEFORE the re-diagnosis.
+// unconditional log-sweep maintenance side-action, writing one "Pruned …" line
+// per removal to w. It is invoked AFTER the initial diagnosis and BEFORE the
+// re-diagnosis.
 //
 // The exit code is driven exclusively by the post-repair re-diagnosis, never by
 // these repairs directly (per the spec's Exit-code contract), so every repair is
@@ -276,8 +371,7 @@ var doctorCmd = &cobra.Command{
 // down/rebooted-server state), so a user-authored — non-reconstructable —
 // on-resume command is never wiped. The stale-project prune is filesystem-only
 // and runs regardless of server state.
-func runDoctorFix(cmd *cobra.Command, deps *DoctorDeps) error {
-	w := cmd.OutOrStdout()
+func runDoctorFix(w io.Writer, deps *DoctorDeps) error {
 	pruneDoctorStaleHooks(w, deps)
 	pruneDoctorStaleProjects(w, deps)
 	sweepDoctorLogs(deps)
@@ -363,7 +457,7 @@ func runDoctorDiagnosis(deps *DoctorDeps) ([]checkResult, error) {
 	serverUp := deps.ServerRunning()
 
 	results := []checkResult{
-		checkDaemonAlive(serverUp, dir, dirErr),
+		checkDaemonAlive(serverUp, dir, dirErr, deps.PingDaemon),
 		checkSaverUp(serverUp, deps.SaverPresent),
 		checkHooksRegistered(serverUp, deps.HookCounts),
 		checkStateDirSane(dir, dirErr),
@@ -371,6 +465,9 @@ func runDoctorDiagnosis(deps *DoctorDeps) ([]checkResult, error) {
 		checkStaleHooks(deps.HookLister, deps.HookStore),
 		checkStaleProjects(deps.ProjectStore),
 	}
+	if deps.AgentIntegrations != nil {
+		results = append(results, checkAgentHooks(deps.AgentIntegrations))
+	}
 	// The host-terminal identity is INFORMATIONAL — it lives at the END of the
 	// report, after the pass/fail catalog, and never drives the exit code.
 	// Production always wires both seams (resolveDoctorDeps), so the line is
@@ -457,6 +554,46 @@ func checkStaleHooks(lister AllPaneLister, store *hooks.Store) checkResult {
 	return checkResult{name: name, status: checkPass, detail: "no stale hooks"}
 }
 
+// checkAgentHooks reports whether Portal's hooks are installed and current in
+// the settings of each coding agent found on this machine. Agent tracking is
+// opt-in, so an agent without Portal's hooks is informational, never a
+// failure, and an OptIn agent without them is not mentioned at all; hooks an older Portal wrote, or installed on only some events, fail
+// — tracking is silently degraded until `agent install` converges them. An
+// unreadable settings file is not evaluable.
+func checkAgentHooks(integrations []agent.Integration) checkResult {
+	const name = "agent hooks"
+	var current, notInstalled, broken []string
+	for _, in := range integrations {
+		if !in.Detected() {
+			continue
+		}
+		st, err := in.Status()
+		if err != nil {
+			return checkResult{name: name, status: checkNotEvaluable, detail: fmt.Sprintf("could not read %s", in.Path)}
+		}
+		switch st.Summary() {
+		case "installed":
+			current = append(current, in.Label)
+		case "not installed":
+			if !in.OptIn {
+				notInstalled = append(notInstalled, in.Label)
+			}
+		default:
+			broken = append(broken, fmt.Sprintf("%s %s", in.Label, st.Summary()))
+		}
+	}
+	switch {
+	case len(broken) > 0:
+		return checkResult{name: name, status: checkFail, detail: strings.Join(broken, ", ") + " — run xctl agent install"}
+	case len(current) > 0:
+		return checkResult{name: name, status: checkPass, detail: "current for " + strings.Join(current, ", ")}
+	case len(notInstalled) > 0:
+		return checkResult{name: name, status: checkInfo, detail: "not installed — run xctl agent install to track " + strings.Join(notInstalled, ", ")}
+	default:
+		return checkResult{name: name, status: checkInfo, detail: "no supported coding agent found"}
+	}
+}
+
 // checkStaleProjects reports whether projects.json holds records whose directory
 // no longer exists. It DERIVES the stale set from the store-owned
 // project.Store.StaleEntries predicate (the single owner of the os.Stat
@@ -482,14 +619,18 @@ func checkStaleProjects(store *project.Store) checkResult {
 
 // checkDaemonAlive reports whether the save daemon is running. With the server
 // down it reports the distinct not-running detail (doctor starts nothing, so a
-// down server is honestly unhealthy, not corrupt). With the server up it is a
-// narrow STATE-based probe reading only the three facts the detail needs: the
+// down server is honestly unhealthy, not corrupt). With the server up it first
+// pings the control socket: an answer passes with the pid and version the
+// daemon reports itself, and a socket that accepts but does not answer fails
+// with "not responding" — the process is alive but its loop is wedged, which
+// daemon.pid alone cannot tell. Nothing listening falls back to a narrow
+// STATE-based probe reading only the three facts the detail needs: the
 // recorded pid (state.ReadPIDFile), its liveness (state.IsProcessAlive), and the
 // recorded version (state.ReadVersionFile). A live daemon.pid passes with a
 // "running (pid N, version V)" detail; a missing, unparseable, or dead PID fails
 // with "not running". It deliberately does NOT walk the state-dir tree or scan
 // portal.log — a routine doctor run stays cheap.
-func checkDaemonAlive(serverUp bool, dir string, dirErr error) checkResult {
+func checkDaemonAlive(serverUp bool, dir string, dirErr error, ping func(dir string) (control.PingResult, error)) checkResult {
 	const name = "daemon"
 	if !serverUp {
 		return runtimeDownResult(name)
@@ -497,6 +638,21 @@ func checkDaemonAlive(serverUp bool, dir string, dirErr error) checkResult {
 	if dirErr != nil {
 		return checkResult{name: name, status: checkFail, detail: "not running"}
 	}
+	var res control.PingResult
+	err := control.ErrUnreachable
+	if ping != nil {
+		res, err = ping(dir)
+	}
+	switch {
+	case err == nil:
+		return checkResult{
+			name:   name,
+			status: checkPass,
+			detail: fmt.Sprintf("running (pid %d, version %s)", res.PID, doctorDaemonVersion(res.Version)),
+		}
+	case !errors.Is(err, control.ErrUnreachable):
+		return checkResult{name: name, status: checkFail, detail: fmt.Sprintf("not responding (%v)", err)}
+	}
 	pid, err := state.ReadPIDFile(dir)
 	if err != nil || !state.IsProcessAlive(pid) {
 		return checkResult{name: name, status: checkFail, detail: "not running"}
@@ -577,6 +733,22 @@ func checkHooksRegistered(serverUp bool, hookCounts func() (map[string]int, erro
 	return checkResult{name: name, status: checkPass, detail: "hooks registered (one per event)"}
 }
 
+// doctorPingTimeout bounds doctor's ping of the control socket. A healthy
+// daemon answers between ticks, well inside it.
+const doctorPingTimeout = 3 * time.Second
+
+// pingDaemon is the production PingDaemon seam.
+func pingDaemon(dir string) (control.PingResult, error) {
+	var res control.PingResult
+	client, err := control.Dial(state.DaemonSocket(dir), doctorPingTimeout)
+	if err != nil {
+		return res, err
+	}
+	defer func() { _ = client.Close() }()
+	err = client.Call(control.MethodPing, nil, &res)
+	return res, err
+}
+
 // doctorDaemonVersion substitutes "unknown" when the daemon never recorded a
 // version marker, so the detail never renders a bare "version )".
 func doctorDaemonVersion(v string) string {
@@ -692,5 +864,6 @@ func doctorUnhealthy(results []checkResult) bool {
 
 func init() {
 	doctorCmd.Flags().Bool("fix", false, "apply low-stakes reversible repairs, then re-diagnose")
+	addOutputFlags(doctorCmd, "name, status, detail")
 	rootCmd.AddCommand(doctorCmd)
 }
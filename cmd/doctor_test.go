@@ -13,6 +13,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
 	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/spawn"
@@ -124,6 +126,9 @@ func withHealthyRuntime(deps *DoctorDeps) *DoctorDeps {
 	if deps.Resolve == nil {
 		deps.Resolve = doctorUnsupportedResolve
 	}
+	if deps.AgentIntegrations == nil {
+		deps.AgentIntegrations = []agent.Integration{}
+	}
 	return deps
 }
 
@@ -149,6 +154,7 @@ func runDoctor(t *testing.T, dir string) (*bytes.Buffer, *bytes.Buffer, error) {
 	// buildProductionSpawnSeams bundle, which reads terminals.json eagerly — isolate
 	// it so the Execute path never touches the developer's real config file.
 	isolateTerminalsFile(t)
+	isolateAgentSettings(t)
 	doctorDeps = withHealthyRuntime(&DoctorDeps{StateDir: dir})
 	t.Cleanup(func() { doctorDeps = nil })
 
@@ -358,6 +364,54 @@ func TestDoctorDaemonCheckDetail(t *testing.T) {
 			t.Errorf("detail = %q; want %q", got.detail, "not running")
 		}
 	})
+
+	t.Run("an answering control socket passes with the daemon's own identity", func(t *testing.T) {
+		deps := withHealthyRuntime(&DoctorDeps{StateDir: t.TempDir()})
+		deps.PingDaemon = func(string) (control.PingResult, error) {
+			return control.PingResult{PID: 4242, Version: "v2.0.0"}, nil
+		}
+		results, err := runDoctorDiagnosis(deps)
+		if err != nil {
+			t.Fatalf("runDoctorDiagnosis: %v", err)
+		}
+		got := findCheck(t, results, "daemon")
+		if got.status != checkPass || got.detail != "running (pid 4242, version v2.0.0)" {
+			t.Errorf("got %v %q", got.status, got.detail)
+		}
+	})
+
+	t.Run("a socket that accepts but does not answer fails even with a live pid", func(t *testing.T) {
+		dir := t.TempDir()
+		seedLiveDaemonPID(t, dir)
+		deps := withHealthyRuntime(&DoctorDeps{StateDir: dir})
+		deps.PingDaemon = func(string) (control.PingResult, error) {
+			return control.PingResult{}, errors.New("read ping response: i/o timeout")
+		}
+		results, err := runDoctorDiagnosis(deps)
+		if err != nil {
+			t.Fatalf("runDoctorDiagnosis: %v", err)
+		}
+		got := findCheck(t, results, "daemon")
+		if got.status != checkFail || !strings.HasPrefix(got.detail, "not responding") {
+			t.Errorf("got %v %q", got.status, got.detail)
+		}
+	})
+
+	t.Run("no socket falls back to daemon.pid", func(t *testing.T) {
+		dir := t.TempDir()
+		seedLiveDaemonPID(t, dir)
+		deps := withHealthyRuntime(&DoctorDeps{StateDir: dir})
+		deps.PingDaemon = func(string) (control.PingResult, error) {
+			return control.PingResult{}, control.ErrUnreachable
+		}
+		results, err := runDoctorDiagnosis(deps)
+		if err != nil {
+			t.Fatalf("runDoctorDiagnosis: %v", err)
+		}
+		if got := findCheck(t, results, "daemon"); got.status != checkPass {
+			t.Errorf("status = %v; want checkPass from daemon.pid", got.status)
+		}
+	})
 }
 
 func TestDoctorStateDirSaneHealthyDirPasses(t *testing.T) {
@@ -845,8 +899,8 @@ func TestDoctorHostTerminalNeverDrivesExit(t *testing.T) {
 }
 
 // TestDoctorCheckOrder pins the stable report order: daemon, saver, hooks,
-// state dir, sessions.json, stale hooks, stale projects, host terminal (the
-// informational host line is appended last).
+// state dir, sessions.json, stale hooks, stale projects, agent hooks, host
+// terminal (the informational host line is appended last).
 func TestDoctorCheckOrder(t *testing.T) {
 	dir := t.TempDir()
 	seedHealthyStateDir(t, dir)
@@ -854,7 +908,7 @@ func TestDoctorCheckOrder(t *testing.T) {
 	if err != nil {
 		t.Fatalf("runDoctorDiagnosis: %v", err)
 	}
-	want := []string{"daemon", "saver", "hooks", "state dir", "sessions.json", "stale hooks", "stale projects", "host terminal"}
+	want := []string{"daemon", "saver", "hooks", "state dir", "sessions.json", "stale hooks", "stale projects", "agent hooks", "host terminal"}
 	if len(results) != len(want) {
 		t.Fatalf("check count = %d, want %d: %+v", len(results), len(want), results)
 	}
@@ -1194,6 +1248,7 @@ func runDoctorFixCmd(t *testing.T, deps *DoctorDeps) (*bytes.Buffer, *bytes.Buff
 	// resolveDoctorDeps eagerly builds the shared spawn seams (terminals.json read)
 	// — isolate the file so the Execute path stays hermetic.
 	isolateTerminalsFile(t)
+	isolateAgentSettings(t)
 	doctorDeps = deps
 	t.Cleanup(func() { doctorDeps = nil })
 
@@ -1217,6 +1272,7 @@ func runDoctorCmd(t *testing.T, deps *DoctorDeps) (*bytes.Buffer, *bytes.Buffer,
 	// resolveDoctorDeps eagerly builds the shared spawn seams (terminals.json read)
 	// — isolate the file so the Execute path stays hermetic.
 	isolateTerminalsFile(t)
+	isolateAgentSettings(t)
 	doctorDeps = deps
 	t.Cleanup(func() { doctorDeps = nil })
 
@@ -1577,3 +1633,172 @@ func TestDoctorStaleChecksAreReadOnly(t *testing.T) {
 		t.Errorf("projects.json mutated by diagnosis (read-only violated)\nbefore: %s\nafter:  %s", projectsBefore, projectsAfter)
 	}
 }
+
+// runDoctorArgs executes `portal doctor <args...>` against deps and returns
+// stdout, stderr and the Execute error.
+func runDoctorArgs(t *testing.T, deps *DoctorDeps, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+	isolateTerminalsFile(t)
+	isolateAgentSettings(t)
+	doctorDeps = deps
+	t.Cleanup(func() { doctorDeps = nil })
+
+	outBuf := new(bytes.Buffer)
+	errBuf := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(outBuf)
+	rootCmd.SetErr(errBuf)
+	rootCmd.SetArgs(append([]string{"doctor"}, args...))
+	err := rootCmd.Execute()
+	return outBuf, errBuf, err
+}
+
+func TestDoctorStructuredOutput(t *testing.T) {
+	t.Run("--json emits the verdict and every check", func(t *testing.T) {
+		dir := t.TempDir()
+		seedHealthyStateDir(t, dir)
+
+		outBuf, _, err := runDoctorArgs(t, withHealthyRuntime(&DoctorDeps{StateDir: dir}), "--json")
+		if err != nil {
+			t.Fatalf("Execute returned %v; want nil", err)
+		}
+
+		var report struct {
+			Healthy bool `json:"healthy"`
+			Checks  []struct {
+				Name   string `json:"name"`
+				Status string `json:"status"`
+				Detail string `json:"detail"`
+			} `json:"checks"`
+		}
+		if err := json.Unmarshal(outBuf.Bytes(), &report); err != nil {
+			t.Fatalf("stdout is not JSON: %v\n%s", err, outBuf)
+		}
+		if !report.Healthy {
+			t.Errorf("healthy = false, want true:\n%s", outBuf)
+		}
+		if len(report.Checks) == 0 {
+			t.Fatalf("no checks in report:\n%s", outBuf)
+		}
+		for _, c := range report.Checks {
+			if c.Name == "sessions.json" && (c.Status != "pass" || c.Detail == "") {
+				t.Errorf("sessions.json check = %+v, want pass with a detail", c)
+			}
+		}
+	})
+
+	t.Run("--json still drives the unhealthy exit", func(t *testing.T) {
+		dir := t.TempDir()
+		seedHealthyStateDir(t, dir)
+		seedDeadDaemonPID(t, dir)
+
+		outBuf, _, err := runDoctorArgs(t, withHealthyRuntime(&DoctorDeps{StateDir: dir}), "--json")
+		if !errors.Is(err, ErrDoctorUnhealthy) {
+			t.Fatalf("Execute err = %v; want ErrDoctorUnhealthy", err)
+		}
+		if !strings.Contains(outBuf.String(), `"healthy": false`) || !strings.Contains(outBuf.String(), `"status": "fail"`) {
+			t.Errorf("report does not record the failure:\n%s", outBuf)
+		}
+	})
+
+	t.Run("--format renders one line per check", func(t *testing.T) {
+		dir := t.TempDir()
+		seedHealthyStateDir(t, dir)
+
+		outBuf, _, err := runDoctorArgs(t, withHealthyRuntime(&DoctorDeps{StateDir: dir}), "--format", `{{.status}}\t{{.name}}`)
+		if err != nil {
+			t.Fatalf("Execute returned %v; want nil", err)
+		}
+		if !strings.Contains(outBuf.String(), "pass\tsessions.json\n") {
+			t.Errorf("formatted output missing sessions.json line:\n%s", outBuf)
+		}
+		if strings.Contains(outBuf.String(), "Portal doctor:") {
+			t.Errorf("formatted output includes the human header:\n%s", outBuf)
+		}
+	})
+
+	t.Run("--fix --json keeps stdout one document", func(t *testing.T) {
+		dir := t.TempDir()
+		seedHealthyStateDir(t, dir)
+		hookStore, _ := seedHooksJSON(t, "sessA:0.0")
+		projectStore, _ := seedProjectsJSON(t, t.TempDir())
+		lister := fakeHookLister{keys: []string{"sessB:0.0"}}
+
+		outBuf, errBuf, err := runDoctorArgs(t, staleDeps(dir, lister, hookStore, projectStore), "--fix", "--json")
+		if err != nil {
+			t.Fatalf("Execute err = %v; want nil", err)
+		}
+		var report map[string]any
+		if err := json.Unmarshal(outBuf.Bytes(), &report); err != nil {
+			t.Fatalf("stdout is not a single JSON document: %v\n%s", err, outBuf)
+		}
+		if !strings.Contains(errBuf.String(), "Pruned stale hook: sessA:0.0") {
+			t.Errorf("repair breadcrumb not on stderr:\n%s", errBuf)
+		}
+	})
+
+	t.Run("--json and --format are mutually exclusive", func(t *testing.T) {
+		_, _, err := runDoctorArgs(t, withHealthyRuntime(&DoctorDeps{StateDir: t.TempDir()}), "--json", "--format", "{{.name}}")
+		if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+			t.Fatalf("Execute err = %v; want mutually-exclusive error", err)
+		}
+	})
+}
+
+func TestCheckAgentHooks(t *testing.T) {
+	integration := func(t *testing.T, settings string) agent.Integration {
+		t.Helper()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "settings.json")
+		if settings != "" {
+			if err := os.WriteFile(path, []byte(settings), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return agent.Integration{Name: "claude", Label: "Claude Code", Path: path, Events: []string{"Stop"}}
+	}
+
+	t.Run("an agent that is not installed is informational", func(t *testing.T) {
+		missing := agent.Integration{Label: "Codex", Path: filepath.Join(t.TempDir(), "absent", "hooks.json")}
+		got := checkAgentHooks([]agent.Integration{missing})
+		if got.status != checkInfo || got.detail != "no supported coding agent found" {
+			t.Errorf("got %+v", got)
+		}
+		got = checkAgentHooks([]agent.Integration{integration(t, "")})
+		if got.status != checkInfo || !strings.Contains(got.detail, "not installed") {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("an opt-in agent without hooks is not suggested", func(t *testing.T) {
+		optIn := integration(t, "")
+		optIn.OptIn = true
+		if got := checkAgentHooks([]agent.Integration{optIn}); got.status != checkInfo || got.detail != "no supported coding agent found" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("current hooks pass", func(t *testing.T) {
+		in := integration(t, "")
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		if got := checkAgentHooks([]agent.Integration{in}); got.status != checkPass || got.detail != "current for Claude Code" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("hooks from an older Portal fail", func(t *testing.T) {
+		in := integration(t, `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"portal agent ingest"}]}]}}`)
+		got := checkAgentHooks([]agent.Integration{in})
+		if got.status != checkFail || got.detail != "Claude Code stale — run xctl agent install" {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("an unreadable settings file is not evaluable", func(t *testing.T) {
+		if got := checkAgentHooks([]agent.Integration{integration(t, "{")}); got.status != checkNotEvaluable {
+			t.Errorf("got %+v", got)
+		}
+	})
+}
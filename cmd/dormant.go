@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// readDormantSessions returns the sessions saved in dir's sessions.json that
+// are not among live. A missing sessions.json means nothing is dormant; an
+// unreadable or corrupt one is an error so callers never quietly
+// under-report.
+func readDormantSessions(dir string, live []tmux.Session) ([]state.Session, error) {
+	idx, skip, err := state.ReadIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	if skip {
+		return nil, nil
+	}
+	names := make([]string, 0, len(live))
+	for _, s := range live {
+		names = append(names, s.Name)
+	}
+	return state.DormantSessions(idx, names), nil
+}
+
+// dormantSource is the picker's tui.DormantSource: it lists dormant sessions
+// from sessions.json and resurrects one through the same marker-bracketed
+// IndexRestorer `snapshot restore` uses, handed a one-session index.
+type dormantSource struct {
+	stateDir string
+	restorer IndexRestorer
+}
+
+// ListDormant returns the dormant sessions relative to live.
+func (d *dormantSource) ListDormant(live []tmux.Session) ([]tui.DormantSession, error) {
+	saved, err := readDormantSessions(d.stateDir, live)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]tui.DormantSession, 0, len(saved))
+	for _, s := range saved {
+		out = append(out, tui.DormantSession{Name: s.Name, Windows: len(s.Windows), SavedAt: s.SavedAt})
+	}
+	return out, nil
+}
+
+// Resurrect restores the named session from sessions.json. A session the
+// restorer did not recreate — already live, or a per-session failure it
+// logged — is reported as an error.
+func (d *dormantSource) Resurrect(name string) error {
+	idx, skip, err := state.ReadIndex(d.stateDir)
+	if err != nil {
+		return err
+	}
+	if skip {
+		return fmt.Errorf("no saved session %q", name)
+	}
+	sess, ok := findSavedSession(idx, name)
+	if !ok {
+		return fmt.Errorf("no saved session %q", name)
+	}
+	return resurrectSession(d.restorer, idx, sess, "tui")
+}
+
+// findSavedSession returns the session named name in idx.
+func findSavedSession(idx state.Index, name string) (state.Session, bool) {
+	for _, sess := range idx.Sessions {
+		if sess.Name == name {
+			return sess, true
+		}
+	}
+	return state.Session{}, false
+}
+
+// resurrectSession recreates sess, a session saved in idx, through restorer.
+// The dormant flag is cleared on the copy handed over (restore skips dormant
+// sessions); the daemon's next save then sees the session live and drops the
+// dormant entry. via names the entry point for the breadcrumb.
+func resurrectSession(restorer IndexRestorer, idx state.Index, sess state.Session, via string) error {
+	sess.Dormant = false
+	restored, err := restorer.RestoreIndex(state.Index{
+		Version:  idx.Version,
+		SavedAt:  idx.SavedAt,
+		Sessions: []state.Session{sess},
+	})
+	if err != nil {
+		return err
+	}
+	if len(restored) == 0 {
+		return fmt.Errorf("session %q was not restored", sess.Name)
+	}
+	restoreLogger.Info("session resurrected", "session", sess.Name, "via", via)
+	return nil
+}
+
+// resurrectLazyTarget recreates name ahead of target resolution when it is a
+// lazy-policy session that is saved but not running, so `x <name>` (and
+// `x -s <name>`) attach it exactly as if the reboot restore had brought it
+// back. Anything else — no sessions.json, no such saved session, a policy
+// other than lazy, or a session already live — is left to the resolver
+// untouched; a killed always-policy session is deliberately not revived here,
+// so a bare name keeps falling through to its path/alias/zoxide match.
+func resurrectLazyTarget(cmd *cobra.Command, name string) error {
+	dir, err := state.Dir()
+	if err != nil {
+		return nil
+	}
+	idx, skip, err := state.ReadIndex(dir)
+	if err != nil || skip {
+		return nil
+	}
+	sess, ok := findSavedSession(idx, name)
+	if !ok || loadRestorePolicy().Of(sess) != prefs.RestoreLazy {
+		return nil
+	}
+	client := tmuxClient(cmd)
+	if client.HasSession(name) {
+		return nil
+	}
+	return resurrectSession(newOnDemandRestorer(client, dir), idx, sess, "cli")
+}
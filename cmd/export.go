@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/leeovery/portal/internal/export"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <session>[:window[.pane]]",
+	Short: "Export saved scrollback as text, ANSI, HTML or an asciinema cast",
+	Long: `Export the saved scrollback of a session, one window, or one pane.
+
+Formats:
+  txt   plain text with escape sequences stripped (default)
+  ansi  the raw terminal output, colours intact
+  html  a self-contained page with the colours rendered
+  cast  an asciinema v2 recording
+
+Scrollback is read from the state directory as of the daemon's last save, so
+dormant sessions export too. Output goes to stdout unless --output is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formatFlag, _ := cmd.Flags().GetString("format")
+		format, err := export.ParseFormat(formatFlag)
+		if err != nil {
+			return err
+		}
+		dir, err := state.Dir()
+		if err != nil {
+			return err
+		}
+		sel, err := export.Select(dir, args[0])
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := export.Write(&buf, format, sel); err != nil {
+			return err
+		}
+		// Scrollback can hold secrets, so a new file is readable by the user
+		// alone, like the state directory it came from.
+		if output, _ := cmd.Flags().GetString("output"); output != "" {
+			return os.WriteFile(output, buf.Bytes(), 0o600)
+		}
+		_, err = cmd.OutOrStdout().Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	exportCmd.Flags().String("format", string(export.Text), "Output format: txt, ansi, html or cast")
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+	exportCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeSessionNames(toComplete)
+	}
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,100 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/state"
+)
+
+func runExport(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(append([]string{"export"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestExportCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	data, err := state.EncodeIndex(state.Index{Version: state.SchemaVersion, Sessions: []state.Session{{
+		Name: "old", Dormant: true, Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, ScrollbackFile: "scrollback/" + state.SanitizePaneKey("old", 0, 0) + ".bin"}}}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.ScrollbackFile(dir, state.SanitizePaneKey("old", 0, 0)), []byte("\x1b[31mpanic\x1b[0m: boom\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runner := &recordingRunner{}
+	bootstrapDeps = &BootstrapDeps{Orchestrator: runner}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	t.Run("defaults to stripped text", func(t *testing.T) {
+		out, err := runExport(t, "old")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "panic: boom\n" {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("--format html renders the colours", func(t *testing.T) {
+		out, err := runExport(t, "--format", "html", "old:0.0")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(out, `<span style="color:#cd0000">panic</span>: boom`) {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("--output writes the file instead of stdout", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "old.ansi")
+		out, err := runExport(t, "--format", "ansi", "-o", path, "old")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "" || string(got) != "\x1b[31mpanic\x1b[0m: boom\n\x1b[0m" {
+			t.Errorf("stdout = %q, file = %q", out, got)
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+		}
+	})
+
+	t.Run("an unknown format is an error", func(t *testing.T) {
+		if _, err := runExport(t, "--format", "pdf", "old"); err == nil || !strings.Contains(err.Error(), `unknown format "pdf"`) {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("an unknown session is an error", func(t *testing.T) {
+		if _, err := runExport(t, "nope"); err == nil || !strings.Contains(err.Error(), `no saved session "nope"`) {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	if runner.calls != 0 {
+		t.Errorf("orchestrator ran %d times, want export bootstrap-exempt", runner.calls)
+	}
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/leeovery/portal/internal/frecency"
+)
+
+// recordVisitFunc credits one visit to Portal's frecency history. Tests
+// override it via t.Cleanup-restored assignment to observe what the open paths
+// record without touching frecency.json.
+var recordVisitFunc = recordVisit
+
+// recordVisit bumps the kind/key entry in frecency.json. It is best-effort: the
+// history only ranks the Recent mode and the bare-target fallback, so a path or
+// write failure is swallowed rather than blocking the attach or mint it rides
+// along with. frecency.json sits outside the audit-trail set, so there is no
+// component to log a failure under either.
+func recordVisit(kind frecency.Kind, key string) {
+	store, err := loadFrecencyStore()
+	if err != nil {
+		return
+	}
+	_ = store.Record(kind, key, time.Now())
+}
+
+// loadFrecencyStore creates a frecency store from the configured file path.
+func loadFrecencyStore() (*frecency.Store, error) {
+	path, err := frecencyFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return frecency.NewStore(path), nil
+}
+
+// frecencyFilePath returns the path to the frecency.json file.
+// Uses PORTAL_FRECENCY_FILE env var if set (for testing), otherwise
+// defaults to ~/.config/portal/frecency.json.
+func frecencyFilePath() (string, error) {
+	return configFilePath("PORTAL_FRECENCY_FILE", "frecency.json")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/frecency"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/spf13/cobra"
+)
+
+// recordedVisit is one call captured by captureVisits.
+type recordedVisit struct {
+	kind frecency.Kind
+	key  string
+}
+
+// captureVisits swaps recordVisitFunc for a recorder for the test's lifetime.
+func captureVisits(t *testing.T) *[]recordedVisit {
+	t.Helper()
+	var visits []recordedVisit
+	orig := recordVisitFunc
+	recordVisitFunc = func(kind frecency.Kind, key string) {
+		visits = append(visits, recordedVisit{kind, key})
+	}
+	t.Cleanup(func() { recordVisitFunc = orig })
+	return &visits
+}
+
+func TestOpenResolved_RecordsFrecency(t *testing.T) {
+	origSession, origPath := openSessionFunc, openPathFunc
+	openSessionFunc = func(*cobra.Command, string) error { return nil }
+	openPathFunc = func(*cobra.Command, string, []string) error { return nil }
+	t.Cleanup(func() { openSessionFunc, openPathFunc = origSession, origPath })
+
+	cases := []struct {
+		name   string
+		result resolver.QueryResult
+		want   recordedVisit
+	}{
+		{"an attach records the session", &resolver.SessionResult{Name: "portal"}, recordedVisit{frecency.KindSession, "portal"}},
+		{"a mint records the directory", &resolver.PathResult{Path: "/code/portal"}, recordedVisit{frecency.KindDir, "/code/portal"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			visits := captureVisits(t)
+
+			if err := openResolved(&cobra.Command{}, tc.result, nil); err != nil {
+				t.Fatalf("openResolved: %v", err)
+			}
+
+			if len(*visits) != 1 || (*visits)[0] != tc.want {
+				t.Errorf("visits = %+v, want [%+v]", *visits, tc.want)
+			}
+		})
+	}
+
+	t.Run("a command on an attach target records nothing", func(t *testing.T) {
+		visits := captureVisits(t)
+
+		if err := openResolved(&cobra.Command{}, &resolver.SessionResult{Name: "portal"}, []string{"vim"}); err == nil {
+			t.Fatal("openResolved: want the attach-only usage error")
+		}
+		if len(*visits) != 0 {
+			t.Errorf("visits = %+v, want none", *visits)
+		}
+	})
+}
+
+func TestRecordVisit_WritesFrecencyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frecency.json")
+	t.Setenv("PORTAL_FRECENCY_FILE", path)
+
+	recordVisit(frecency.KindDir, "/code/portal")
+	recordVisit(frecency.KindDir, "/code/portal")
+
+	entries, err := frecency.NewStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Visits != 2 {
+		t.Errorf("entries = %+v, want one directory with two visits", entries)
+	}
+}
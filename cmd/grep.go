@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/leeovery/portal/internal/grep"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+// grepDeps holds injectable dependencies for the grep command.
+// When nil, real implementations are used.
+var grepDeps *GrepDeps
+
+// GrepDeps allows injecting dependencies for testing.
+type GrepDeps struct {
+	Corpus grep.Corpus
+}
+
+// buildGrepCorpus returns the corpus the grep command searches: the saved
+// scrollback in the state directory, or with --live every running pane by
+// live capture plus the saved scrollback of sessions that are not running.
+// grep is bootstrap-exempt, so --live builds its own client rather than
+// reaching for the context-injected one.
+func buildGrepCorpus(cmd *cobra.Command) (grep.Corpus, error) {
+	if grepDeps != nil {
+		return grepDeps.Corpus, nil
+	}
+	dir, err := state.Dir()
+	if err != nil {
+		return nil, err
+	}
+	saved := grep.Saved{StateDir: dir}
+	if live, _ := cmd.Flags().GetBool("live"); live {
+		return grep.Live{Client: tmux.DefaultClient(), Saved: saved}, nil
+	}
+	return saved, nil
+}
+
+// writeGrepHits prints hits in grep -C style: "session:w.p:line:text" for a
+// match, "session:w.p-line-text" for a context line, and "--" between
+// non-adjacent groups when context was requested. Overlapping context from
+// neighbouring hits in the same pane is merged, so no line is printed twice.
+func writeGrepHits(w io.Writer, hits []grep.Hit, context int) error {
+	var prev *grep.Hit
+	last := 0 // last line printed in prev's pane
+	for i := range hits {
+		h := &hits[i]
+		first := h.Line - len(h.Before)
+		samePane := prev != nil && prev.Pane == h.Pane
+		if context > 0 && prev != nil && (!samePane || first > last+1) {
+			if _, err := fmt.Fprintln(w, "--"); err != nil {
+				return err
+			}
+		}
+		if !samePane {
+			last = 0
+		}
+		target := h.Target()
+		for j, text := range h.Before {
+			if n := first + j; n > last {
+				if _, err := fmt.Fprintf(w, "%s-%d-%s\n", target, n, text); err != nil {
+					return err
+				}
+			}
+		}
+		if h.Line > last {
+			if _, err := fmt.Fprintf(w, "%s:%d:%s\n", target, h.Line, h.Text); err != nil {
+				return err
+			}
+		}
+		last = h.Line
+		// A following hit inside this window prints the shared lines itself,
+		// as a match rather than as context.
+		for j, text := range h.After {
+			n := h.Line + 1 + j
+			if i+1 < len(hits) && hits[i+1].Pane == h.Pane && n >= hits[i+1].Line {
+				break
+			}
+			if _, err := fmt.Fprintf(w, "%s-%d-%s\n", target, n, text); err != nil {
+				return err
+			}
+			last = n
+		}
+		prev = h
+	}
+	return nil
+}
+
+// pickerGrepContext is the context the picker's find mode shows around each
+// hit; its results page reserves rows for exactly this much.
+const pickerGrepContext = 2
+
+// pickerSearcher adapts internal/grep to the picker's ScrollbackSearcher seam.
+// A pattern is a regexp matched smart-case, since the picker prompt has no
+// room for flags.
+type pickerSearcher struct {
+	corpus grep.Corpus
+}
+
+func (s pickerSearcher) Search(pattern string) ([]grep.Hit, error) {
+	re, err := grep.Compile(pattern, false, smartCase(pattern))
+	if err != nil {
+		return nil, err
+	}
+	return grep.Search(s.corpus, re, pickerGrepContext)
+}
+
+// smartCase reports whether a pattern should match case-insensitively: only
+// when it has no uppercase letter, as in vim's smartcase.
+func smartCase(pattern string) bool {
+	return !strings.ContainsFunc(pattern, unicode.IsUpper)
+}
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search the scrollback of every pane",
+	Long: `Search the saved scrollback of every pane Portal knows about, dormant
+sessions included, and print each matching line as session:window.pane:line.
+With --live, running panes are captured live instead, so output printed since
+the last save is searched too. Escape sequences are stripped before matching.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+		fixed, _ := cmd.Flags().GetBool("fixed-strings")
+		ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+		context, _ := cmd.Flags().GetInt("context")
+		if context < 0 {
+			return fmt.Errorf("--context must not be negative")
+		}
+
+		re, err := grep.Compile(args[0], fixed, ignoreCase)
+		if err != nil {
+			return err
+		}
+		corpus, err := buildGrepCorpus(cmd)
+		if err != nil {
+			return err
+		}
+		hits, err := grep.Search(corpus, re, context)
+		if err != nil {
+			return err
+		}
+
+		if opts.structured() {
+			return writeRecords(cmd.OutOrStdout(), opts, hits)
+		}
+		return writeGrepHits(cmd.OutOrStdout(), hits, context)
+	},
+}
+
+func init() {
+	grepCmd.Flags().BoolP("ignore-case", "i", false, "Match case-insensitively")
+	grepCmd.Flags().BoolP("fixed-strings", "F", false, "Treat the pattern as a literal string")
+	grepCmd.Flags().IntP("context", "C", 0, "Show this many lines of context around each match")
+	grepCmd.Flags().Bool("live", false, "Capture running panes live instead of reading their saved scrollback")
+	addOutputFlags(grepCmd, "session, window, window_name, pane, live, line, text, before, after")
+	rootCmd.AddCommand(grepCmd)
+}
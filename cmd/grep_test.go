@@ -0,0 +1,173 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/grep"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// stubGrepCorpus serves canned content keyed by pane target.
+type stubGrepCorpus struct {
+	panes   []grep.Pane
+	content map[string]string
+}
+
+func (s stubGrepCorpus) Panes() ([]grep.Pane, error) { return s.panes, nil }
+
+func (s stubGrepCorpus) Content(p grep.Pane) ([]byte, error) {
+	c, ok := s.content[p.Target()]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return []byte(c), nil
+}
+
+func runGrep(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs(append([]string{"grep"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestGrepCommand(t *testing.T) {
+	work := grep.Pane{Session: "work", Window: 1, Pane: 0}
+	api := grep.Pane{Session: "api", Window: 0, Pane: 2}
+	grepDeps = &GrepDeps{Corpus: stubGrepCorpus{
+		panes: []grep.Pane{work, api},
+		content: map[string]string{
+			work.Target(): "a\nb\n\x1b[31mpanic: one\x1b[0m\nc\npanic: two\nd\ne\nf\ng\npanic: three\n",
+			api.Target():  "Panic upper\n",
+		},
+	}}
+	t.Cleanup(func() { grepDeps = nil })
+
+	t.Run("prints session:window.pane:line:text per match", func(t *testing.T) {
+		out, err := runGrep(t, "panic")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "work:1.0:3:panic: one\nwork:1.0:5:panic: two\nwork:1.0:10:panic: three\n"
+		if out != want {
+			t.Errorf("output =\n%s\nwant\n%s", out, want)
+		}
+	})
+
+	t.Run("context lines merge across nearby matches and separate groups", func(t *testing.T) {
+		out, err := runGrep(t, "-C", "1", "panic")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := strings.Join([]string{
+			"work:1.0-2-b",
+			"work:1.0:3:panic: one",
+			"work:1.0-4-c",
+			"work:1.0:5:panic: two",
+			"work:1.0-6-d",
+			"--",
+			"work:1.0-9-g",
+			"work:1.0:10:panic: three",
+		}, "\n") + "\n"
+		if out != want {
+			t.Errorf("output =\n%s\nwant\n%s", out, want)
+		}
+	})
+
+	t.Run("-i matches across case", func(t *testing.T) {
+		out, err := runGrep(t, "-i", "PANIC UPPER")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "api:0.2:1:Panic upper\n" {
+			t.Errorf("output = %q", out)
+		}
+	})
+
+	t.Run("--json emits the hit records", func(t *testing.T) {
+		out, err := runGrep(t, "--json", "-F", "panic: t")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var hits []grep.Hit
+		if err := json.Unmarshal([]byte(out), &hits); err != nil {
+			t.Fatalf("decode %q: %v", out, err)
+		}
+		if len(hits) != 2 || hits[0].Line != 5 || hits[1].Line != 10 || hits[0].Session != "work" {
+			t.Errorf("hits = %+v, want lines 5 and 10 of work", hits)
+		}
+	})
+
+	t.Run("an invalid pattern is an error", func(t *testing.T) {
+		if _, err := runGrep(t, "("); err == nil || !strings.Contains(err.Error(), "invalid pattern") {
+			t.Errorf("err = %v, want invalid pattern", err)
+		}
+	})
+}
+
+func TestGrepCommand_SavedScrollbackSkipsBootstrap(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	data, err := state.EncodeIndex(state.Index{Version: state.SchemaVersion, Sessions: []state.Session{{
+		Name: "old", Dormant: true, Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, ScrollbackFile: "scrollback/" + state.SanitizePaneKey("old", 0, 0) + ".bin"}}}},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.ScrollbackFile(dir, state.SanitizePaneKey("old", 0, 0)), []byte("stack trace here\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runner := &recordingRunner{}
+	bootstrapDeps = &BootstrapDeps{Orchestrator: runner}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	out, err := runGrep(t, "stack trace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "old:0.0:1:stack trace here\n" {
+		t.Errorf("output = %q", out)
+	}
+	if runner.calls != 0 {
+		t.Errorf("orchestrator ran %d times, want grep bootstrap-exempt", runner.calls)
+	}
+}
+
+func TestPickerSearcher_SmartCase(t *testing.T) {
+	p := grep.Pane{Session: "work", Window: 0, Pane: 0, Live: true}
+	s := pickerSearcher{corpus: stubGrepCorpus{
+		panes:   []grep.Pane{p},
+		content: map[string]string{p.Target(): "Error: one\nerror: two\n"},
+	}}
+
+	cases := []struct {
+		pattern string
+		want    int
+	}{
+		{"error", 2},
+		{"Error", 1},
+	}
+	for _, c := range cases {
+		hits, err := s.Search(c.pattern)
+		if err != nil {
+			t.Fatalf("Search(%q): %v", c.pattern, err)
+		}
+		if len(hits) != c.want {
+			t.Errorf("Search(%q) = %d hits, want %d", c.pattern, len(hits), c.want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+// hookTarget is one session an event's hooks run for: the pane keys its
+// per-pane hooks are registered under and the directory its project hooks are
+// matched by.
+type hookTarget struct {
+	Session  string
+	Dir      string
+	PaneKeys []string
+}
+
+// savedHookTarget builds the target for a session as captured in an Index.
+// dir is the session's @portal-dir when known; otherwise the session is
+// matched to a project by its saved project directory, as restore policies
+// are.
+func savedHookTarget(sess state.Session, dir string) hookTarget {
+	if dir == "" {
+		dir = state.ProjectDir(sess)
+	}
+	t := hookTarget{Session: sess.Name, Dir: dir}
+	for _, w := range sess.Windows {
+		for _, p := range w.Panes {
+			t.PaneKeys = append(t.PaneKeys, tmux.HookKey(sess.PortalID, sess.Name, w.Index, p.Index))
+		}
+	}
+	return t
+}
+
+// liveSessionClient is the tmux surface needed to describe one running
+// session: the daemon's structural capture plus the session list carrying
+// each session's @portal-dir.
+type liveSessionClient interface {
+	state.CaptureClient
+	ListSessions() ([]tmux.Session, error)
+}
+
+// captureLiveSession captures the running session name, returning it with its
+// @portal-dir stamp ("" when unstamped).
+func captureLiveSession(client liveSessionClient, name string) (state.Session, string, error) {
+	idx, err := state.CaptureStructure(client, nil, nil, captureLogger)
+	if err != nil {
+		return state.Session{}, "", fmt.Errorf("failed to capture sessions: %w", err)
+	}
+	sess, ok := findSavedSession(idx, name)
+	if !ok {
+		return state.Session{}, "", fmt.Errorf("No session found: %s", name) //nolint:staticcheck // matches kill's user-facing message
+	}
+
+	dir := ""
+	if live, err := client.ListSessions(); err == nil {
+		for _, s := range live {
+			if s.Name == name {
+				dir = s.Dir
+			}
+		}
+	}
+	return sess, dir, nil
+}
+
+// liveHookTarget resolves the hook target for the running session name.
+func liveHookTarget(client liveSessionClient, name string) ([]hookTarget, error) {
+	sess, dir, err := captureLiveSession(client, name)
+	if err != nil {
+		return nil, err
+	}
+	return []hookTarget{savedHookTarget(sess, dir)}, nil
+}
+
+// startHookProcess runs command through sh in dir, in its own session so it
+// outlives the short-lived Portal process (and the tmux run-shell) that fired
+// it. Output is discarded: hooks fire from tmux hooks and the daemon, where
+// there is nobody to show it to. Tests replace it to record what would run.
+var startHookProcess = func(command, dir string, env []string) error {
+	c := exec.Command("sh", "-c", command)
+	c.Dir = dir
+	c.Env = append(os.Environ(), env...)
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Process.Release()
+}
+
+// fireHooks runs every command registered for event against the sessions
+// resolve returns: each pane-key hook of the session, then the hook of the
+// project containing its directory. resolve is only called once hooks.json is
+// known to hook event at all, so an unhooked event never touches tmux.
+//
+// Firing is best-effort throughout — a nil store, an unreadable hooks.json, a
+// failed resolve or a command that cannot start is logged under the hooks
+// component and skipped, because every caller is a tmux hook, the daemon or a
+// session being created, none of which a user hook may fail.
+//
+// A command runs in its project's directory for a project hook, else in the
+// session's directory, with PORTAL_EVENT, PORTAL_SESSION, PORTAL_HOOK_KEY and
+// PORTAL_DIR describing what fired it.
+func fireHooks(store *hooks.Store, event string, resolve func() ([]hookTarget, error)) {
+	if store == nil {
+		return
+	}
+	h, err := store.Load()
+	if err != nil {
+		hooksLogger.Warn("load hooks failed", "event", event, "error", err)
+		return
+	}
+	if !hooks.HasEvent(h, event) {
+		return
+	}
+
+	targets, err := resolve()
+	if err != nil {
+		hooksLogger.Warn("resolve hook target failed", "event", event, "error", err)
+		return
+	}
+
+	for _, t := range targets {
+		dirKey := ""
+		if t.Dir != "" {
+			dirKey = project.CanonicalDirKey(t.Dir)
+		}
+		for _, m := range hooks.EventMatches(h, event, t.PaneKeys, dirKey) {
+			dir := t.Dir
+			if hooks.IsProjectKey(m.Key) {
+				dir = m.Key
+			}
+			if _, err := os.Stat(dir); err != nil {
+				dir = ""
+			}
+			env := []string{
+				"PORTAL_EVENT=" + event,
+				"PORTAL_SESSION=" + t.Session,
+				"PORTAL_HOOK_KEY=" + m.Key,
+				"PORTAL_DIR=" + t.Dir,
+			}
+			if err := startHookProcess(m.Command, dir, env); err != nil {
+				hooksLogger.Warn("start hook failed", "event", event, "hook_key", m.Key, "session", t.Session, "error", err)
+				continue
+			}
+			hooksLogger.Debug("hook fired", "event", event, "hook_key", m.Key, "session", t.Session)
+		}
+	}
+}
+
+// fireKillHooks runs the on-kill hooks of the sessions a commit moved from
+// running to dormant (see state.CarryDormant). The daemon and commit-now both
+// call it right after the commit that records the transition, so whichever
+// writes it first reports the kill and the other sees it already dormant.
+func fireKillHooks(store *hooks.Store, stopped []state.Session) {
+	if len(stopped) == 0 {
+		return
+	}
+	fireHooks(store, hooks.EventKill, func() ([]hookTarget, error) {
+		targets := make([]hookTarget, 0, len(stopped))
+		for _, sess := range stopped {
+			targets = append(targets, savedHookTarget(sess, ""))
+		}
+		return targets, nil
+	})
+}
+
+// createHookCommand is the session.CreateHook Portal's session-creation paths
+// install: the fire-hooks invocation that runs the new session's on-create
+// hooks, or "" while no hook is registered for on-create, so a session in an
+// unhooked project chains nothing extra.
+//
+// on-create is fired from creation rather than from the session-created tmux
+// hook Portal already registers, because that hook also fires for every
+// session a restore or resurrect rebuilds, and fires before the @portal-dir
+// stamp that ties the session to its project exists.
+func createHookCommand(name string) string {
+	store, err := loadHookStore()
+	if err != nil {
+		return ""
+	}
+	h, err := store.Load()
+	if err != nil || !hooks.HasEvent(h, hooks.EventCreate) {
+		return ""
+	}
+	quoted := "'" + strings.ReplaceAll(name, "'", `'\''`) + "'"
+	return "command -v portal >/dev/null 2>&1 && portal state fire-hooks " + hooks.EventCreate + " -- " + quoted
+}
+
+// fireHooksRunFunc is the seam tests use to observe the state fire-hooks
+// body. Production points it at fireLiveSessionHooks.
+var fireHooksRunFunc = fireLiveSessionHooks
+
+// fireLiveSessionHooks fires event for the running session name.
+func fireLiveSessionHooks(event, name string) {
+	store, _ := loadHookStore()
+	client := tmux.DefaultClient()
+	fireHooks(store, event, func() ([]hookTarget, error) { return liveHookTarget(client, name) })
+}
+
+// stateFireHooksCmd runs a running session's hooks for one event. It is
+// invoked by the client-detached tmux hook (on-detach) and chained after
+// session creation (on-create); on-attach, on-kill and on-save fire in
+// process from signal-hydrate, commit-now and the daemon. Hidden from --help.
+var stateFireHooksCmd = &cobra.Command{
+	Use:    "fire-hooks <event> <session-name>",
+	Short:  "Run a session's hooks for an event (internal)",
+	Args:   cobra.ExactArgs(2),
+	Hidden: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		event := args[0]
+		if !hooks.IsEvent(event) || event == hooks.EventResume {
+			return fmt.Errorf("unknown hook event: %s", event)
+		}
+		fireHooksRunFunc(event, args[1])
+		return nil
+	},
+}
+
+func init() {
+	stateCmd.AddCommand(stateFireHooksCmd)
+}
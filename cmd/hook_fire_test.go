@@ -0,0 +1,172 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/project"
+)
+
+// firedHook is one command startHookProcess was asked to run.
+type firedHook struct {
+	Command string
+	Dir     string
+	Env     []string
+}
+
+// recordHookProcesses swaps startHookProcess for a recorder for the test.
+func recordHookProcesses(t *testing.T) *[]firedHook {
+	t.Helper()
+	var fired []firedHook
+	prev := startHookProcess
+	startHookProcess = func(command, dir string, env []string) error {
+		fired = append(fired, firedHook{Command: command, Dir: dir, Env: env})
+		return nil
+	}
+	t.Cleanup(func() { startHookProcess = prev })
+	return &fired
+}
+
+// writeHooksFile points PORTAL_HOOKS_FILE at a fresh hooks.json holding h and
+// returns a store over it.
+func writeHooksFile(t *testing.T, h map[string]map[string]string) *hooks.Store {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hooks.json")
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORTAL_HOOKS_FILE", path)
+	return hooks.NewStore(path)
+}
+
+func TestFireHooks(t *testing.T) {
+	projectDir := t.TempDir()
+	sessionDir := filepath.Join(projectDir, "web")
+	if err := os.Mkdir(sessionDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("runs pane hooks then the project hook with the event's environment", func(t *testing.T) {
+		fired := recordHookProcesses(t)
+		key := project.CanonicalDirKey(projectDir)
+		store := writeHooksFile(t, map[string]map[string]string{
+			"abc:0.1": {hooks.EventDetach: "echo pane"},
+			key:       {hooks.EventDetach: "echo project"},
+		})
+
+		fireHooks(store, hooks.EventDetach, func() ([]hookTarget, error) {
+			return []hookTarget{{Session: "app", Dir: sessionDir, PaneKeys: []string{"abc:0.0", "abc:0.1"}}}, nil
+		})
+
+		if len(*fired) != 2 {
+			t.Fatalf("fired %d hooks, want 2: %+v", len(*fired), *fired)
+		}
+		pane, proj := (*fired)[0], (*fired)[1]
+		if pane.Command != "echo pane" || pane.Dir != sessionDir {
+			t.Errorf("pane hook = %+v, want echo pane in the session dir", pane)
+		}
+		if proj.Command != "echo project" || proj.Dir != key {
+			t.Errorf("project hook = %+v, want echo project in the project dir", proj)
+		}
+		wantEnv := []string{"PORTAL_EVENT=on-detach", "PORTAL_SESSION=app", "PORTAL_HOOK_KEY=" + key, "PORTAL_DIR=" + sessionDir}
+		if !reflect.DeepEqual(proj.Env, wantEnv) {
+			t.Errorf("env = %v, want %v", proj.Env, wantEnv)
+		}
+	})
+
+	t.Run("an event nobody hooks never resolves its targets", func(t *testing.T) {
+		fired := recordHookProcesses(t)
+		store := writeHooksFile(t, map[string]map[string]string{
+			"abc:0.0": {hooks.EventResume: "npm start"},
+		})
+
+		resolved := false
+		fireHooks(store, hooks.EventSave, func() ([]hookTarget, error) {
+			resolved = true
+			return nil, nil
+		})
+
+		if resolved || len(*fired) != 0 {
+			t.Errorf("resolved = %v, fired = %v; want neither", resolved, *fired)
+		}
+	})
+
+	t.Run("a nil store fires nothing", func(t *testing.T) {
+		fired := recordHookProcesses(t)
+
+		fireHooks(nil, hooks.EventKill, func() ([]hookTarget, error) {
+			t.Error("resolve called with a nil store")
+			return nil, nil
+		})
+
+		if len(*fired) != 0 {
+			t.Errorf("fired = %v, want none", *fired)
+		}
+	})
+}
+
+func TestCreateHookCommand(t *testing.T) {
+	t.Run("is empty while no on-create hook exists", func(t *testing.T) {
+		writeHooksFile(t, map[string]map[string]string{"/code/app": {hooks.EventKill: "echo bye"}})
+
+		if got := createHookCommand("app-abc123"); got != "" {
+			t.Errorf("createHookCommand = %q, want empty", got)
+		}
+	})
+
+	t.Run("fires on-create for the quoted session name", func(t *testing.T) {
+		writeHooksFile(t, map[string]map[string]string{"/code/app": {hooks.EventCreate: "make deps"}})
+
+		got := createHookCommand("it's-abc123")
+
+		want := `command -v portal >/dev/null 2>&1 && portal state fire-hooks on-create -- 'it'\''s-abc123'`
+		if got != want {
+			t.Errorf("createHookCommand = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestStateFireHooksCommand(t *testing.T) {
+	var gotEvent, gotSession string
+	prev := fireHooksRunFunc
+	fireHooksRunFunc = func(event, name string) { gotEvent, gotSession = event, name }
+	t.Cleanup(func() { fireHooksRunFunc = prev })
+
+	run := func(args ...string) error {
+		resetRootCmd()
+		rootCmd.SetOut(new(bytes.Buffer))
+		rootCmd.SetErr(new(bytes.Buffer))
+		rootCmd.SetArgs(append([]string{"state", "fire-hooks"}, args...))
+		return rootCmd.Execute()
+	}
+
+	t.Run("dispatches the event for a session whose name starts with a dash", func(t *testing.T) {
+		if err := run("on-detach", "--", "-dotfiles-abc123"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotEvent != "on-detach" || gotSession != "-dotfiles-abc123" {
+			t.Errorf("fired (%q, %q), want (on-detach, -dotfiles-abc123)", gotEvent, gotSession)
+		}
+	})
+
+	t.Run("rejects an unknown event and on-resume", func(t *testing.T) {
+		for _, event := range []string{"on-boot", "on-resume"} {
+			err := run(event, "app")
+			if err == nil || !strings.Contains(err.Error(), "unknown hook event") {
+				t.Errorf("%s: err = %v, want an unknown-event error", event, err)
+			}
+		}
+	})
+}
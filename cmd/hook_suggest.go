@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+// loadResumeRules reads the smart-resume rules from resume.json. An
+// undeterminable config path degrades to smart resume off, as a missing file
+// does.
+func loadResumeRules() hooks.ResumeRules {
+	path, err := configFilePath("PORTAL_RESUME_FILE", "resume.json")
+	if err != nil {
+		return hooks.ResumeRules{}
+	}
+	return hooks.NewRulesStore(path).Load()
+}
+
+// resumeSuggestion is the --json / --format schema for one `hook suggest`
+// entry: a saved pane, the command it was running, and what it would resume
+// with. Argv is the command's full saved argv, when captured. Source is
+// "hook" for the pane's own on-resume hook, "project" for a project resume
+// hook and "rule" for a smart-resume rule.
+type resumeSuggestion struct {
+	Key     string   `json:"key"`
+	Session string   `json:"session"`
+	Command string   `json:"command"`
+	Argv    []string `json:"argv,omitempty"`
+	Resume  string   `json:"resume"`
+	Source  string   `json:"source"`
+}
+
+// suggestResumes returns what each pane saved in idx would run when restored,
+// resolved in the hydrate helper's order: its own hook, its project's hook,
+// then the first matching rule. Rules are consulted even while smart resume
+// is off, so they can be previewed before being enabled. Panes that would
+// come back to a bare shell are omitted.
+func suggestResumes(idx state.Index, store *hooks.Store, rules hooks.ResumeRules) ([]resumeSuggestion, error) {
+	var out []resumeSuggestion
+	for _, sess := range idx.Sessions {
+		sessionDir := state.SessionDir(sess)
+		for _, w := range sess.Windows {
+			for _, p := range w.Panes {
+				key := tmux.HookKey(sess.PortalID, sess.Name, w.Index, p.Index)
+				s := resumeSuggestion{Key: key, Session: sess.Name, Command: p.CurrentCommand, Argv: p.Argv}
+
+				match, found, err := hooks.LookupResume(store, resumePane(key, p.CWD, sessionDir, p.CurrentCommand))
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case found && match.Key == key:
+					s.Resume, s.Source = match.Command, "hook"
+				case found:
+					s.Resume, s.Source = match.Command, "project"
+				default:
+					rule, ok := rules.Match(p.CurrentCommand, p.Argv, p.CWD)
+					if !ok {
+						continue
+					}
+					s.Resume, s.Source = rule.Resume, "rule"
+				}
+				out = append(out, s)
+			}
+		}
+	}
+	return out, nil
+}
+
+var hookSuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "List what each saved pane would resume after a restore",
+	Long: `List the command each pane in sessions.json would run when Portal next
+recreates it: its own on-resume hook, its project's, or a smart-resume rule
+from resume.json. Panes that would come back to a plain shell are omitted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		dir, err := state.Dir()
+		if err != nil {
+			return err
+		}
+		idx, _, err := state.ReadIndex(dir)
+		if err != nil {
+			return err
+		}
+
+		store, err := loadHookStore()
+		if err != nil {
+			return err
+		}
+		rules := loadResumeRules()
+
+		suggestions, err := suggestResumes(idx, store, rules)
+		if err != nil {
+			return err
+		}
+
+		if opts.structured() {
+			return writeRecords(cmd.OutOrStdout(), opts, suggestions)
+		}
+		for _, s := range suggestions {
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\t%s\n", s.Key, s.Command, s.Source, s.Resume); err != nil {
+				return err
+			}
+		}
+		if !rules.Enabled && hasRuleSuggestion(suggestions) {
+			_, _ = fmt.Fprintln(cmd.ErrOrStderr(), `smart resume is off: set "enabled": true in resume.json to resume the rule entries`)
+		}
+		return nil
+	},
+}
+
+// hasRuleSuggestion reports whether any suggestion comes from a rule.
+func hasRuleSuggestion(suggestions []resumeSuggestion) bool {
+	for _, s := range suggestions {
+		if s.Source == "rule" {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	addOutputFlags(hookSuggestCmd, "key, session, command, argv, resume, source")
+	hookCmd.AddCommand(hookSuggestCmd)
+}
@@ -0,0 +1,97 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/state"
+)
+
+func TestSuggestResumes(t *testing.T) {
+	projectDir := t.TempDir()
+	store := writeHooksFile(t, map[string]map[string]string{
+		"id1:0.0":                           {hooks.EventResume: "claude --resume abc"},
+		project.CanonicalDirKey(projectDir): {hooks.ResumeEvent("npm"): "npm run dev"},
+	})
+	idx := state.Index{Sessions: []state.Session{{
+		Name: "api", PortalID: "id1",
+		Windows: []state.Window{{Index: 0, Active: true, Panes: []state.Pane{
+			{Index: 0, CWD: projectDir, Active: true, CurrentCommand: "claude"},
+			{Index: 1, CWD: projectDir, CurrentCommand: "npm", Argv: []string{"npm", "run", "dev"}},
+			{Index: 2, CWD: "/tmp", CurrentCommand: "claude"},
+			{Index: 3, CWD: "/tmp", CurrentCommand: "zsh"},
+		}}},
+	}}}
+
+	got, err := suggestResumes(idx, store, hooks.ResumeRules{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []resumeSuggestion{
+		{Key: "id1:0.0", Session: "api", Command: "claude", Resume: "claude --resume abc", Source: "hook"},
+		{Key: "id1:0.1", Session: "api", Command: "npm", Argv: []string{"npm", "run", "dev"}, Resume: "npm run dev", Source: "project"},
+		{Key: "id1:0.2", Session: "api", Command: "claude", Resume: "claude --continue", Source: "rule"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("suggestResumes =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestHookSuggestCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	writeHooksFile(t, map[string]map[string]string{})
+	idx := state.Index{Version: state.SchemaVersion, Sessions: []state.Session{{
+		Name: "work", Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/tmp", CurrentCommand: "claude"}}}},
+	}}}
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(t *testing.T, resumeJSON string) (string, string) {
+		t.Helper()
+		resumeFile := filepath.Join(t.TempDir(), "resume.json")
+		if err := os.WriteFile(resumeFile, []byte(resumeJSON), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PORTAL_RESUME_FILE", resumeFile)
+
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(stdout)
+		rootCmd.SetErr(stderr)
+		rootCmd.SetArgs([]string{"hook", "suggest"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return stdout.String(), stderr.String()
+	}
+
+	t.Run("lists rule suggestions with a note while smart resume is off", func(t *testing.T) {
+		stdout, stderr := run(t, `{}`)
+		if want := "work:0.0\tclaude\trule\tclaude --continue\n"; stdout != want {
+			t.Errorf("stdout = %q, want %q", stdout, want)
+		}
+		if stderr == "" {
+			t.Error("expected a smart-resume-off note on stderr")
+		}
+	})
+
+	t.Run("no note once enabled", func(t *testing.T) {
+		if _, stderr := run(t, `{"enabled": true}`); stderr != "" {
+			t.Errorf("stderr = %q, want empty", stderr)
+		}
+	})
+}
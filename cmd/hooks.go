@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
@@ -71,7 +73,7 @@ func resolveCurrentPaneKey() (string, error) {
 	return hookKey, nil
 }
 
-// hookCmd is the canonical resume-hook namespace. `hooks` is retained as a
+// hookCmd is the canonical hook namespace. `hooks` is retained as a
 // permanent, silent cobra alias (the one deliberate back-compat carve-out —
 // machine-written `portal hooks set …` from external SessionStart skills keeps
 // working). A plain Aliases entry is silent by design: cobra prints no
@@ -79,7 +81,31 @@ func resolveCurrentPaneKey() (string, error) {
 var hookCmd = &cobra.Command{
 	Use:     "hook",
 	Aliases: []string{"hooks"},
-	Short:   "Manage resume hooks",
+	Short:   "Manage resume and lifecycle hooks",
+	Long: `Hooks run a shell command when something happens to a session:
+
+  on-resume  a pane is recreated from saved state, running in that pane
+  on-create  Portal creates a new session in the project (per project only)
+  on-attach  a client attaches to, or switches into, the session
+  on-detach  a client detaches from the session
+  on-kill    the session is killed
+  on-save    the daemon saves the session
+
+A hook belongs to the current pane, or with --project to a project directory,
+where it applies to every session opened in that project. A project on-resume
+hook fires in each restored pane whose working directory is in the project,
+narrowed with --last-command to panes that were running that command; a pane's
+own on-resume hook takes precedence. The other events run in the background
+through sh with PORTAL_EVENT, PORTAL_SESSION, PORTAL_HOOK_KEY and PORTAL_DIR
+set.`,
+}
+
+// hookRecord is the --json / --format schema for one `hook list` entry: the
+// hook key it is registered under, its event, and the command it runs.
+type hookRecord struct {
+	Key     string `json:"key"`
+	Event   string `json:"event"`
+	Command string `json:"command"`
 }
 
 var hooksListCmd = &cobra.Command{
@@ -87,6 +113,11 @@ var hooksListCmd = &cobra.Command{
 	Short: "List all registered hooks",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+
 		store, err := loadHookStore()
 		if err != nil {
 			return err
@@ -97,6 +128,14 @@ var hooksListCmd = &cobra.Command{
 			return err
 		}
 
+		if opts.structured() {
+			records := make([]hookRecord, 0, len(list))
+			for _, h := range list {
+				records = append(records, hookRecord{Key: h.Key, Event: h.Event, Command: h.Command})
+			}
+			return writeRecords(cmd.OutOrStdout(), opts, records)
+		}
+
 		for _, h := range list {
 			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", h.Key, h.Event, h.Command); err != nil {
 				return err
@@ -109,15 +148,18 @@ var hooksListCmd = &cobra.Command{
 
 var hooksSetCmd = &cobra.Command{
 	Use:   "set",
-	Short: "Register a resume hook for the current pane",
+	Short: "Register hooks for the current pane or a project",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		hookKey, err := resolveCurrentPaneKey()
-		if err != nil {
+		forProject := cmd.Flags().Changed("project")
+		if cmd.Flags().Changed(hooks.EventCreate) && !forProject {
+			return NewUsageError("--on-create hooks belong to a project; add --project")
+		}
+		if err := checkLastCommandFlag(cmd); err != nil {
 			return err
 		}
 
-		command, err := cmd.Flags().GetString("on-resume")
+		hookKey, err := resolveHookKey(cmd)
 		if err != nil {
 			return err
 		}
@@ -127,10 +169,74 @@ var hooksSetCmd = &cobra.Command{
 			return err
 		}
 
-		return store.Set(hookKey, "on-resume", command, "cli")
+		for _, event := range hooks.Events {
+			if !cmd.Flags().Changed(event) {
+				continue
+			}
+			command, err := cmd.Flags().GetString(event)
+			if err != nil {
+				return err
+			}
+			if err := store.Set(hookKey, storedHookEvent(cmd, event), command, "cli"); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
+// checkLastCommandFlag rejects --last-command anywhere but on a project
+// resume hook, the only kind it qualifies.
+func checkLastCommandFlag(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("last-command") {
+		return nil
+	}
+	if !cmd.Flags().Changed("project") || !cmd.Flags().Changed(hooks.EventResume) {
+		return NewUsageError("--last-command applies to --project --on-resume hooks only")
+	}
+	return nil
+}
+
+// storedHookEvent returns the hooks.json event name an --on-<event> flag acts
+// on: the event itself, except for a project resume hook, which is stored per
+// --last-command so one project can resume different panes differently.
+func storedHookEvent(cmd *cobra.Command, event string) string {
+	if event != hooks.EventResume || !cmd.Flags().Changed("project") {
+		return event
+	}
+	lastCommand, _ := cmd.Flags().GetString("last-command")
+	return hooks.ResumeEvent(lastCommand)
+}
+
+// resolveHookKey returns the key hook set and hook rm act on: the project key
+// of --project's directory, else rm's explicit --pane-key, else the current
+// pane's key.
+func resolveHookKey(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Changed("project") {
+		dir, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return "", err
+		}
+		return projectHookKey(dir), nil
+	}
+	if f := cmd.Flags().Lookup("pane-key"); f != nil && f.Value.String() != "" {
+		return f.Value.String(), nil
+	}
+	return resolveCurrentPaneKey()
+}
+
+// projectHookKey returns the project key for dir: the canonical path of its
+// git root, the directory a session created there is stamped with. A
+// directory that is not in a repository — or no longer exists, for rm — keys
+// on itself.
+func projectHookKey(dir string) string {
+	path := resolver.NormalisePath(dir)
+	if root, err := resolver.ResolveGitRoot(path, &resolver.RealCommandRunner{}); err == nil {
+		path = root
+	}
+	return project.CanonicalDirKey(path)
+}
+
 // loadHookStore creates a hook store from the configured file path.
 func loadHookStore() (*hooks.Store, error) {
 	path, err := hooksFilePath()
@@ -150,22 +256,16 @@ func hooksFilePath() (string, error) {
 
 var hooksRmCmd = &cobra.Command{
 	Use:   "rm",
-	Short: "Remove a resume hook for the current pane",
+	Short: "Remove hooks for the current pane or a project",
 	Args:  cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		paneKey, err := cmd.Flags().GetString("pane-key")
-		if err != nil {
+		if err := checkLastCommandFlag(cmd); err != nil {
 			return err
 		}
 
-		var hookKey string
-		if paneKey != "" {
-			hookKey = paneKey
-		} else {
-			hookKey, err = resolveCurrentPaneKey()
-			if err != nil {
-				return err
-			}
+		hookKey, err := resolveHookKey(cmd)
+		if err != nil {
+			return err
 		}
 
 		store, err := loadHookStore()
@@ -173,17 +273,46 @@ var hooksRmCmd = &cobra.Command{
 			return err
 		}
 
-		return store.Remove(hookKey, "on-resume", "cli")
+		for _, event := range hooks.Events {
+			if remove, _ := cmd.Flags().GetBool(event); !remove {
+				continue
+			}
+			if err := store.Remove(hookKey, storedHookEvent(cmd, event), "cli"); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
+// hookEventUsage describes each event for its --on-<event> flag on set; rm's
+// bool flags reuse the event name.
+var hookEventUsage = map[string]string{
+	hooks.EventResume: "Command to run when the pane, or a pane in the project, is recreated from saved state",
+	hooks.EventCreate: "Command to run when Portal creates a session in the project (requires --project)",
+	hooks.EventAttach: "Command to run when a client attaches to the session",
+	hooks.EventDetach: "Command to run when a client detaches from the session",
+	hooks.EventKill:   "Command to run when the session is killed",
+	hooks.EventSave:   "Command to run each time the daemon saves the session",
+}
+
 func init() {
-	hooksSetCmd.Flags().String("on-resume", "", "Command to run when resuming the pane")
-	_ = hooksSetCmd.MarkFlagRequired("on-resume")
+	for _, event := range hooks.Events {
+		hooksSetCmd.Flags().String(event, "", hookEventUsage[event])
+		hooksRmCmd.Flags().Bool(event, false, "Remove the "+event+" hook")
+	}
+	hooksSetCmd.MarkFlagsOneRequired(hooks.Events...)
+	hooksRmCmd.MarkFlagsOneRequired(hooks.Events...)
 
-	hooksRmCmd.Flags().Bool("on-resume", false, "Remove the on-resume hook")
-	_ = hooksRmCmd.MarkFlagRequired("on-resume")
+	for _, c := range []*cobra.Command{hooksSetCmd, hooksRmCmd} {
+		c.Flags().String("project", "", "Act on the project containing this directory instead of the current pane (default: the working directory)")
+		c.Flags().Lookup("project").NoOptDefVal = "."
+		c.Flags().String("last-command", "", "With --project --on-resume, only panes whose saved foreground command was this")
+	}
 	hooksRmCmd.Flags().String("pane-key", "", "Structural key of the pane whose hook should be removed (defaults to the current pane)")
+	hooksRmCmd.MarkFlagsMutuallyExclusive("project", "pane-key")
+
+	addOutputFlags(hooksListCmd, "key, event, command")
 
 	hookCmd.AddCommand(hooksListCmd)
 	hookCmd.AddCommand(hooksSetCmd)
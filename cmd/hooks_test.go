@@ -3,11 +3,14 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/leeovery/portal/internal/project"
 )
 
 func TestHooksListCommand(t *testing.T) {
@@ -120,6 +123,72 @@ func TestHooksListCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("--json emits key event and command records", func(t *testing.T) {
+		dir := t.TempDir()
+		hooksFile := filepath.Join(dir, "hooks.json")
+		t.Setenv("PORTAL_HOOKS_FILE", hooksFile)
+
+		writeHooksJSON(t, hooksFile, map[string]map[string]string{
+			"proj-abc:0.0": {"on-resume": "claude --resume abc123"},
+		})
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"hook", "list", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := `[
+  {
+    "key": "proj-abc:0.0",
+    "event": "on-resume",
+    "command": "claude --resume abc123"
+  }
+]
+`
+		if got := buf.String(); got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("--json with no hooks emits an empty array", func(t *testing.T) {
+		t.Setenv("PORTAL_HOOKS_FILE", filepath.Join(t.TempDir(), "hooks.json"))
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"hook", "list", "--json"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := buf.String(); got != "[]\n" {
+			t.Errorf("output = %q, want %q", got, "[]\n")
+		}
+	})
+
+	t.Run("--format applies the template per hook", func(t *testing.T) {
+		dir := t.TempDir()
+		hooksFile := filepath.Join(dir, "hooks.json")
+		t.Setenv("PORTAL_HOOKS_FILE", hooksFile)
+
+		writeHooksJSON(t, hooksFile, map[string]map[string]string{
+			"proj-abc:0.0": {"on-resume": "claude --resume abc123"},
+		})
+
+		buf := new(bytes.Buffer)
+		resetRootCmd()
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"hook", "list", "--format", "{{.key}} => {{.command}}"})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "proj-abc:0.0 => claude --resume abc123\n"; got != want {
+			t.Errorf("output = %q, want %q", got, want)
+		}
+	})
+
 	t.Run("accepts no arguments", func(t *testing.T) {
 		dir := t.TempDir()
 		hooksFile := filepath.Join(dir, "hooks.json")
@@ -845,3 +914,99 @@ func TestHookCommandRename(t *testing.T) {
 		}
 	})
 }
+
+func TestHookEventFlags(t *testing.T) {
+	run := func(args ...string) error {
+		resetRootCmd()
+		rootCmd.SetOut(new(bytes.Buffer))
+		rootCmd.SetErr(new(bytes.Buffer))
+		rootCmd.SetArgs(append([]string{"hook"}, args...))
+		return rootCmd.Execute()
+	}
+
+	t.Run("--project keys on the project directory without a tmux pane", func(t *testing.T) {
+		hooksFile := filepath.Join(t.TempDir(), "hooks.json")
+		t.Setenv("PORTAL_HOOKS_FILE", hooksFile)
+		t.Setenv("TMUX_PANE", "")
+		projectDir := t.TempDir()
+
+		if err := run("set", "--project="+projectDir, "--on-create", "make deps", "--on-kill", "make clean"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		key := project.CanonicalDirKey(projectDir)
+		data := readHooksJSON(t, hooksFile)
+		if data[key]["on-create"] != "make deps" || data[key]["on-kill"] != "make clean" {
+			t.Errorf("hooks[%q] = %v, want on-create and on-kill set", key, data[key])
+		}
+
+		if err := run("rm", "--project="+projectDir, "--on-create"); err != nil {
+			t.Fatalf("unexpected rm error: %v", err)
+		}
+		data = readHooksJSON(t, hooksFile)
+		if _, ok := data[key]["on-create"]; ok || data[key]["on-kill"] != "make clean" {
+			t.Errorf("hooks[%q] = %v, want only on-kill left", key, data[key])
+		}
+	})
+
+	t.Run("sets several events for the current pane at once", func(t *testing.T) {
+		hooksFile := filepath.Join(t.TempDir(), "hooks.json")
+		t.Setenv("PORTAL_HOOKS_FILE", hooksFile)
+		t.Setenv("TMUX_PANE", "%3")
+		hooksDeps = &HooksDeps{KeyResolver: &mockKeyResolver{key: "my-session:0.0"}}
+		t.Cleanup(func() { hooksDeps = nil })
+
+		if err := run("set", "--on-attach", "echo hi", "--on-detach", "echo bye"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := map[string]string{"on-attach": "echo hi", "on-detach": "echo bye"}
+		data := readHooksJSON(t, hooksFile)
+		if fmt.Sprint(data["my-session:0.0"]) != fmt.Sprint(want) {
+			t.Errorf("hooks = %v, want %v", data["my-session:0.0"], want)
+		}
+	})
+
+	t.Run("--project --on-resume is stored per --last-command", func(t *testing.T) {
+		hooksFile := filepath.Join(t.TempDir(), "hooks.json")
+		t.Setenv("PORTAL_HOOKS_FILE", hooksFile)
+		projectDir := t.TempDir()
+
+		if err := run("set", "--project="+projectDir, "--on-resume", "npm run dev", "--last-command", "npm"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := run("set", "--project="+projectDir, "--on-resume", "ls"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		key := project.CanonicalDirKey(projectDir)
+		want := map[string]string{"on-resume:npm": "npm run dev", "on-resume": "ls"}
+		if data := readHooksJSON(t, hooksFile); fmt.Sprint(data[key]) != fmt.Sprint(want) {
+			t.Errorf("hooks[%q] = %v, want %v", key, data[key], want)
+		}
+
+		if err := run("rm", "--project="+projectDir, "--on-resume", "--last-command", "npm"); err != nil {
+			t.Fatalf("unexpected rm error: %v", err)
+		}
+		if data := readHooksJSON(t, hooksFile); fmt.Sprint(data[key]) != fmt.Sprint(map[string]string{"on-resume": "ls"}) {
+			t.Errorf("hooks[%q] = %v, want only the any-pane resume hook", key, data[key])
+		}
+	})
+
+	t.Run("--on-create needs --project and --last-command needs a project resume hook", func(t *testing.T) {
+		t.Setenv("PORTAL_HOOKS_FILE", filepath.Join(t.TempDir(), "hooks.json"))
+		t.Setenv("TMUX_PANE", "%3")
+
+		for _, args := range [][]string{
+			{"set", "--on-create", "make deps"},
+			{"set", "--on-resume", "npm start", "--last-command", "npm"},
+			{"set", "--project=/code/app", "--on-kill", "echo bye", "--last-command", "npm"},
+		} {
+			err := run(args...)
+			var usageErr *UsageError
+			if !errors.As(err, &usageErr) {
+				t.Errorf("%v: err = %v, want a usage error", args, err)
+			}
+		}
+	})
+}
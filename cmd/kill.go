@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
 
@@ -20,50 +29,308 @@ type SessionValidator interface {
 	HasSession(name string) bool
 }
 
-// KillDeps allows injecting dependencies for testing.
+// SessionTimesLister reports when each running session was created and last
+// active.
+type SessionTimesLister interface {
+	ListSessionTimes() (map[string]tmux.SessionTimes, error)
+}
+
+// CurrentSessionNamer reports the tmux session the calling client is in.
+type CurrentSessionNamer interface {
+	CurrentSessionName() (string, error)
+}
+
+// KillDeps allows injecting dependencies for testing. Lister and Times are
+// only consulted by globs and filters; a kill of exact names needs neither.
+// Current is only consulted inside tmux, and a nil Current leaves the kill
+// order as selected.
 type KillDeps struct {
 	Killer    SessionKiller
 	Validator SessionValidator
+	Lister    SessionLister
+	Times     SessionTimesLister
+	Current   CurrentSessionNamer
+	Now       func() time.Time
+}
+
+// killFilters are the flags that narrow the sessions a kill selects. With no
+// positional arguments they select from every running session.
+type killFilters struct {
+	detached  bool
+	olderThan time.Duration
+	tag       string
+	project   string
+}
+
+func (f killFilters) any() bool {
+	return f.detached || f.olderThan > 0 || f.tag != "" || f.project != ""
 }
 
 var killCmd = &cobra.Command{
-	Use:   "kill [name]",
-	Short: "Kill a tmux session",
-	Args:  cobra.ExactArgs(1),
+	Use:   "kill [name|glob...]",
+	Short: "Kill tmux sessions",
+	Long: `Kill one or more tmux sessions by exact name or glob ('api-*').
+
+Filters narrow the selection; with no names they select from every running
+session:
+  --detached          only sessions with no client attached
+  --older-than <dur>  only sessions created more than <dur> ago (e.g. 8h)
+  --tag <tag>         only sessions whose project carries <tag>
+  --project <path>    only sessions opened in the project at <path>
+
+Killing more than one session asks for confirmation first; --yes skips it and
+--dry-run lists the selection without killing anything. Run from inside tmux,
+the session you are in is killed last, so a selection that includes it is not
+cut short when the kill takes this command down with it. Each kill reaches
+sessions.json through the session-closed hook, which leaves the session
+dormant exactly as killing it from tmux would.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
+		filters, err := readKillFilters(cmd)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && !filters.any() {
+			return fmt.Errorf("kill needs a session name, a glob, or a filter")
+		}
+		deps := buildKillDeps(cmd)
 
-		killer, validator := buildKillDeps(cmd)
+		names, err := selectKillTargets(deps, args, filters)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("No sessions match") //nolint:staticcheck // user-facing message, matching "No session found"
+		}
+		names = killCurrentLast(deps, names)
 
-		if !validator.HasSession(name) {
-			return fmt.Errorf("No session found: %s", name) //nolint:staticcheck // user-facing message per spec
+		w := cmd.OutOrStdout()
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			for _, name := range names {
+				if _, err := fmt.Fprintf(w, "would kill %s\n", name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if yes, _ := cmd.Flags().GetBool("yes"); len(names) > 1 && !yes {
+			if !confirmKill(cmd.InOrStdin(), cmd.ErrOrStderr(), names) {
+				return fmt.Errorf("aborted: no sessions killed")
+			}
 		}
 
-		return killer.KillSession(name)
+		var errs []error
+		for _, name := range names {
+			if err := deps.Killer.KillSession(name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
 	},
 }
 
-// buildKillDeps returns the appropriate killer and validator for the kill command.
-// When killDeps is set (testing), uses injected dependencies.
-// Otherwise, builds real implementations.
-func buildKillDeps(cmd *cobra.Command) (SessionKiller, SessionValidator) {
+// readKillFilters reads and validates the filter flags.
+func readKillFilters(cmd *cobra.Command) (killFilters, error) {
+	var f killFilters
+	f.detached, _ = cmd.Flags().GetBool("detached")
+	f.olderThan, _ = cmd.Flags().GetDuration("older-than")
+	if f.olderThan < 0 {
+		return f, fmt.Errorf("--older-than must not be negative")
+	}
+	if raw, _ := cmd.Flags().GetString("tag"); raw != "" {
+		tag, ok := project.NormaliseTag(raw)
+		if !ok {
+			return f, fmt.Errorf("invalid tag %q", raw)
+		}
+		f.tag = tag
+	}
+	f.project, _ = cmd.Flags().GetString("project")
+	return f, nil
+}
+
+// selectKillTargets resolves args and filters to the session names to kill,
+// deduplicated, in argument order (or tmux's order when there are no args).
+// An exact name that does not exist, or a glob that matches nothing, is an
+// error naming it, so a typo never silently kills less than intended.
+func selectKillTargets(deps *KillDeps, args []string, filters killFilters) ([]string, error) {
+	needList := filters.any() || slices.ContainsFunc(args, resolver.HasGlobMeta)
+	if !needList {
+		for _, name := range args {
+			if !deps.Validator.HasSession(name) {
+				return nil, fmt.Errorf("No session found: %s", name) //nolint:staticcheck // user-facing message per spec
+			}
+		}
+		return dedupe(args), nil
+	}
+
+	sessions, err := deps.Lister.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]tmux.Session, len(sessions))
+	all := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		byName[s.Name] = s
+		all = append(all, s.Name)
+	}
+
+	candidates := all
+	if len(args) > 0 {
+		candidates = nil
+		for _, arg := range args {
+			if resolver.HasGlobMeta(arg) {
+				matches := resolver.MatchGlob(arg, all)
+				if len(matches) == 0 {
+					return nil, fmt.Errorf("No sessions match: %s", arg) //nolint:staticcheck // user-facing message, matching "No session found"
+				}
+				candidates = append(candidates, matches...)
+				continue
+			}
+			if _, ok := byName[arg]; !ok {
+				return nil, fmt.Errorf("No session found: %s", arg) //nolint:staticcheck // user-facing message per spec
+			}
+			candidates = append(candidates, arg)
+		}
+		candidates = dedupe(candidates)
+	}
+
+	keep, err := killFilterPredicate(deps, filters)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range candidates {
+		if keep(byName[name]) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// killFilterPredicate builds the filter test, loading creation times and the
+// project store only when a filter needs them. Tags are best-effort like
+// xctl list's: an unreadable projects.json tags nothing.
+func killFilterPredicate(deps *KillDeps, f killFilters) (func(tmux.Session) bool, error) {
+	var times map[string]tmux.SessionTimes
+	if f.olderThan > 0 {
+		var err error
+		if times, err = deps.Times.ListSessionTimes(); err != nil {
+			return nil, err
+		}
+	}
+	var idx project.Index
+	if f.tag != "" {
+		var projects []project.Project
+		if store, err := loadProjectStore(); err == nil {
+			projects, _ = store.Load()
+		}
+		idx = project.NewIndex(projects)
+	}
+	var projectKey string
+	if f.project != "" {
+		projectKey = project.CanonicalDirKey(f.project)
+	}
+	cutoff := deps.Now().Add(-f.olderThan)
+
+	return func(s tmux.Session) bool {
+		if f.detached && s.Attached {
+			return false
+		}
+		if f.olderThan > 0 {
+			t, ok := times[s.Name]
+			if !ok || !t.Created.Before(cutoff) {
+				return false
+			}
+		}
+		if f.tag != "" && !slices.Contains(idx.Tags(s.Dir), f.tag) {
+			return false
+		}
+		// An unstamped session (no @portal-dir) belongs to no project.
+		if f.project != "" && (s.Dir == "" || project.CanonicalDirKey(s.Dir) != projectKey) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// killCurrentLast moves the session the calling client is in to the end of
+// names. Killing it closes the terminal xctl runs in, which can end xctl
+// before it reaches the sessions after it; last, there are none left to miss.
+func killCurrentLast(deps *KillDeps, names []string) []string {
+	if deps.Current == nil || !tmux.InsideTmux() {
+		return names
+	}
+	current, err := deps.Current.CurrentSessionName()
+	if err != nil || current == "" {
+		return names
+	}
+	i := slices.Index(names, current)
+	if i < 0 {
+		return names
+	}
+	return append(slices.Delete(slices.Clone(names), i, i+1), current)
+}
+
+// dedupe drops repeated names, keeping the first occurrence.
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// confirmKill lists the sessions about to be killed and reads a y/N answer.
+// Anything but y or yes — including end of input, as from a script with no
+// terminal — declines.
+func confirmKill(in io.Reader, out io.Writer, names []string) bool {
+	_, _ = fmt.Fprintf(out, "Kill %d sessions?\n", len(names))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(out, "  %s\n", name)
+	}
+	_, _ = fmt.Fprint(out, "[y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// buildKillDeps returns the kill command's dependencies. When killDeps is set
+// (testing), its fields are used, with the clock defaulting to time.Now.
+// Otherwise every collaborator is the context-injected tmux client.
+func buildKillDeps(cmd *cobra.Command) *KillDeps {
 	if killDeps != nil {
-		return killDeps.Killer, killDeps.Validator
+		deps := *killDeps
+		if deps.Now == nil {
+			deps.Now = time.Now
+		}
+		return &deps
 	}
 
 	client := tmuxClient(cmd)
-	return client, client
+	return &KillDeps{Killer: client, Validator: client, Lister: client, Times: client, Current: client, Now: time.Now}
 }
 
 func init() {
-	// Tab completion (spec § Tab Completion): kill's single positional completes
-	// session names via the shared completer. kill is ExactArgs(1), so once the
-	// first positional is present there is nothing more to complete.
+	killCmd.Flags().Bool("detached", false, "Only kill sessions with no attached client")
+	killCmd.Flags().Duration("older-than", 0, "Only kill sessions created more than this long ago (e.g. 8h)")
+	killCmd.Flags().String("tag", "", "Only kill sessions whose project carries this tag")
+	killCmd.Flags().String("project", "", "Only kill sessions opened in the project at this path")
+	killCmd.Flags().Bool("dry-run", false, "List the sessions that would be killed without killing them")
+	killCmd.Flags().BoolP("yes", "y", false, "Kill several sessions without asking for confirmation")
+
+	// Tab completion (spec § Tab Completion): every positional completes
+	// session names via the shared completer, skipping names already given.
 	killCmd.ValidArgsFunction = func(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		if len(args) > 0 {
-			return nil, cobra.ShellCompDirectiveNoFileComp
-		}
-		return completeSessionNames(toComplete)
+		names, directive := completeSessionNames(toComplete)
+		names = slices.DeleteFunc(names, func(n string) bool { return slices.Contains(args, n) })
+		return names, directive
 	}
 
 	rootCmd.AddCommand(killCmd)
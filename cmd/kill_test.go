@@ -3,21 +3,41 @@ package cmd
 // Tests in this file mutate package-level state (bootstrapDeps, killDeps) and MUST NOT use t.Parallel.
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 // mockSessionKiller records KillSession calls for testing.
 type mockSessionKiller struct {
 	killedName string
+	killed     []string
 	err        error
 }
 
 func (m *mockSessionKiller) KillSession(name string) error {
 	m.killedName = name
+	m.killed = append(m.killed, name)
 	return m.err
 }
 
+// stubCurrentSession reports a fixed current session name.
+type stubCurrentSession string
+
+func (s stubCurrentSession) CurrentSessionName() (string, error) { return string(s), nil }
+
+// stubSessionTimes serves canned creation/activity times.
+type stubSessionTimes map[string]tmux.SessionTimes
+
+func (s stubSessionTimes) ListSessionTimes() (map[string]tmux.SessionTimes, error) { return s, nil }
+
 func TestKillCommand(t *testing.T) {
 	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
 	t.Cleanup(func() { bootstrapDeps = nil })
@@ -172,3 +192,181 @@ func TestKillCommand(t *testing.T) {
 		}
 	})
 }
+
+// bulkKillSetup wires killDeps over a fixed set of running sessions and
+// returns the recording killer.
+func bulkKillSetup(t *testing.T, projectDir string) *mockSessionKiller {
+	t.Helper()
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	killer := &mockSessionKiller{}
+	killDeps = &KillDeps{
+		Killer:    killer,
+		Validator: &mockSessionValidator{sessions: map[string]bool{"api-1": true, "api-2": true, "web": true}},
+		Lister: &mockSessionLister{sessions: []tmux.Session{
+			{Name: "api-1", Attached: true, Dir: projectDir},
+			{Name: "api-2", Dir: projectDir},
+			{Name: "web"},
+		}},
+		Times: stubSessionTimes{
+			"api-1": {Created: now.Add(-48 * time.Hour)},
+			"api-2": {Created: now.Add(-time.Hour)},
+			"web":   {Created: now.Add(-72 * time.Hour)},
+		},
+		Now: func() time.Time { return now },
+	}
+	t.Cleanup(func() {
+		killDeps = nil
+		bootstrapDeps = nil
+	})
+	return killer
+}
+
+func runKill(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+	resetRootCmd()
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+	rootCmd.SetIn(strings.NewReader(stdin))
+	rootCmd.SetArgs(append([]string{"kill"}, args...))
+	err := rootCmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestKillCommand_Bulk(t *testing.T) {
+	projectDir := t.TempDir()
+	projectsFile := filepath.Join(t.TempDir(), "projects.json")
+	t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+	seed := `{"projects":[{"path":"` + projectDir + `","name":"api","last_used":"2026-01-01T00:00:00Z","tags":["Work"]}]}`
+	if err := os.WriteFile(projectsFile, []byte(seed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"several exact names", []string{"web", "api-2", "web"}, []string{"web", "api-2"}},
+		{"a glob", []string{"api-*"}, []string{"api-1", "api-2"}},
+		{"--detached narrows a glob", []string{"api-*", "--detached"}, []string{"api-2"}},
+		{"--older-than alone selects from every session", []string{"--older-than", "24h"}, []string{"api-1", "web"}},
+		{"--tag matches the project's tags", []string{"--tag", "Work"}, []string{"api-1", "api-2"}},
+		{"--project matches the stamped dir", []string{"--project", projectDir, "--detached"}, []string{"api-2"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			killer := bulkKillSetup(t, projectDir)
+			if _, _, err := runKill(t, "", append(c.args, "--yes")...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(killer.killed, c.want) {
+				t.Errorf("killed %v, want %v", killer.killed, c.want)
+			}
+		})
+	}
+
+	t.Run("--dry-run lists without killing", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		out, _, err := runKill(t, "", "api-*", "--dry-run")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "would kill api-1\nwould kill api-2\n" || len(killer.killed) != 0 {
+			t.Errorf("out = %q, killed = %v", out, killer.killed)
+		}
+	})
+
+	t.Run("several matches ask for confirmation", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		_, prompt, err := runKill(t, "y\n", "api-*")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(prompt, "Kill 2 sessions?") || !strings.Contains(prompt, "api-2") {
+			t.Errorf("prompt = %q", prompt)
+		}
+		if !slices.Equal(killer.killed, []string{"api-1", "api-2"}) {
+			t.Errorf("killed %v", killer.killed)
+		}
+	})
+
+	t.Run("declining or closed stdin kills nothing", func(t *testing.T) {
+		for _, stdin := range []string{"n\n", ""} {
+			killer := bulkKillSetup(t, projectDir)
+			if _, _, err := runKill(t, stdin, "api-*"); err == nil || !strings.Contains(err.Error(), "aborted") {
+				t.Errorf("stdin %q: err = %v, want aborted", stdin, err)
+			}
+			if len(killer.killed) != 0 {
+				t.Errorf("stdin %q: killed %v", stdin, killer.killed)
+			}
+		}
+	})
+
+	t.Run("a glob matching nothing is an error", func(t *testing.T) {
+		bulkKillSetup(t, projectDir)
+		if _, _, err := runKill(t, "", "db-*"); err == nil || err.Error() != "No sessions match: db-*" {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("filters that exclude everything are an error", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		if _, _, err := runKill(t, "", "web", "--tag", "Work"); err == nil || err.Error() != "No sessions match" {
+			t.Errorf("err = %v", err)
+		}
+		if len(killer.killed) != 0 {
+			t.Errorf("killed %v", killer.killed)
+		}
+	})
+
+	t.Run("no names and no filters is an error", func(t *testing.T) {
+		bulkKillSetup(t, projectDir)
+		if _, _, err := runKill(t, ""); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("the caller's own session is killed last", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		killDeps.Current = stubCurrentSession("api-1")
+		t.Setenv("TMUX", "/tmp/tmux-0/default,1,0")
+		out, _, err := runKill(t, "", "api-*", "--dry-run")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "would kill api-2\nwould kill api-1\n" {
+			t.Errorf("dry-run out = %q, want api-1 last", out)
+		}
+		if _, _, err := runKill(t, "", "api-*", "web", "--yes"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"api-2", "web", "api-1"}; !slices.Equal(killer.killed, want) {
+			t.Errorf("killed %v, want %v", killer.killed, want)
+		}
+	})
+
+	t.Run("outside tmux the selection order is kept", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		killDeps.Current = stubCurrentSession("api-1")
+		t.Setenv("TMUX", "")
+		if _, _, err := runKill(t, "", "api-*", "--yes"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"api-1", "api-2"}; !slices.Equal(killer.killed, want) {
+			t.Errorf("killed %v, want %v", killer.killed, want)
+		}
+	})
+
+	t.Run("a failed kill does not stop the rest", func(t *testing.T) {
+		killer := bulkKillSetup(t, projectDir)
+		killer.err = fmt.Errorf("boom")
+		if _, _, err := runKill(t, "", "api-*", "--yes"); err == nil {
+			t.Error("expected the kill failures to be reported")
+		}
+		if len(killer.killed) != 2 {
+			t.Errorf("killed %v, want both attempted", killer.killed)
+		}
+	})
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 )
@@ -45,9 +48,77 @@ func formatSessionLong(s tmux.Session) string {
 	return fmt.Sprintf("%s    %s    %d %s", s.Name, status, s.Windows, windowWord)
 }
 
+// formatDormantLong formats a dormant session in long format. The window
+// count is the saved topology's; the time is when it was last saved running.
+func formatDormantLong(s state.Session) string {
+	windowWord := "windows"
+	if len(s.Windows) == 1 {
+		windowWord = "window"
+	}
+	return fmt.Sprintf("%s    dormant    %d %s    saved %s",
+		s.Name, len(s.Windows), windowWord, s.SavedAt.Local().Format("2006-01-02 15:04"))
+}
+
+// sessionRecord is the --json / --format schema for one `list` entry. Dir is
+// the session's stamped project directory (empty when unstamped) and Tags are
+// the tags of the project stored at Dir, always an array. Dormant entries
+// (list --all) carry SavedAt, the time they were last saved running, and an
+// empty Dir: the project stamp lives on the tmux session, not in the save.
+type sessionRecord struct {
+	Name     string    `json:"name"`
+	Windows  int       `json:"windows"`
+	Attached bool      `json:"attached"`
+	Dir      string    `json:"dir"`
+	Tags     []string  `json:"tags"`
+	Dormant  bool      `json:"dormant"`
+	SavedAt  time.Time `json:"saved_at,omitzero"`
+}
+
+// sessionRecords builds the structured records for sessions, resolving tags
+// through projects.json. Tags are best-effort: an unresolvable projects path
+// leaves every session untagged rather than failing the listing.
+func sessionRecords(sessions []tmux.Session) []sessionRecord {
+	var projects []project.Project
+	if store, err := loadProjectStore(); err == nil {
+		projects, _ = store.Load()
+	}
+	idx := project.NewIndex(projects)
+
+	records := make([]sessionRecord, 0, len(sessions))
+	for _, s := range sessions {
+		tags := idx.Tags(s.Dir)
+		if tags == nil {
+			tags = []string{}
+		}
+		records = append(records, sessionRecord{
+			Name:     s.Name,
+			Windows:  s.Windows,
+			Attached: s.Attached,
+			Dir:      s.Dir,
+			Tags:     tags,
+		})
+	}
+	return records
+}
+
+// dormantRecords builds the structured records for dormant sessions.
+func dormantRecords(sessions []state.Session) []sessionRecord {
+	records := make([]sessionRecord, 0, len(sessions))
+	for _, s := range sessions {
+		records = append(records, sessionRecord{
+			Name:    s.Name,
+			Windows: len(s.Windows),
+			Tags:    []string{},
+			Dormant: true,
+			SavedAt: s.SavedAt,
+		})
+	}
+	return records
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List running tmux sessions",
+	Short: "List running tmux sessions (--all adds dormant ones)",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		shortFlag, _ := cmd.Flags().GetBool("short")
 		longFlag, _ := cmd.Flags().GetBool("long")
@@ -55,6 +126,13 @@ var listCmd = &cobra.Command{
 		if shortFlag && longFlag {
 			return fmt.Errorf("--short and --long are mutually exclusive")
 		}
+		opts, err := readOutputFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if opts.structured() && (shortFlag || longFlag) {
+			return fmt.Errorf("--short and --long cannot be combined with --json or --format")
+		}
 
 		lister, ttyDetect := buildListDeps(cmd)
 
@@ -63,7 +141,23 @@ var listCmd = &cobra.Command{
 			return err
 		}
 
-		if len(sessions) == 0 {
+		var dormant []state.Session
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			dir, err := state.Dir()
+			if err != nil {
+				return err
+			}
+			if dormant, err = readDormantSessions(dir, sessions); err != nil {
+				return err
+			}
+		}
+
+		if opts.structured() {
+			records := append(sessionRecords(sessions), dormantRecords(dormant)...)
+			return writeRecords(cmd.OutOrStdout(), opts, records)
+		}
+
+		if len(sessions) == 0 && len(dormant) == 0 {
 			return nil
 		}
 
@@ -88,6 +182,17 @@ var listCmd = &cobra.Command{
 				return err
 			}
 		}
+		for _, s := range dormant {
+			var err error
+			if useLong {
+				_, err = fmt.Fprintln(w, formatDormantLong(s))
+			} else {
+				_, err = fmt.Fprintln(w, s.Name)
+			}
+			if err != nil {
+				return err
+			}
+		}
 
 		return nil
 	},
@@ -104,5 +209,7 @@ func buildListDeps(cmd *cobra.Command) (SessionLister, func() bool) {
 func init() {
 	listCmd.Flags().Bool("short", false, "Output session names only")
 	listCmd.Flags().Bool("long", false, "Output full session details")
+	listCmd.Flags().Bool("all", false, "Include dormant sessions: saved but not running")
+	addOutputFlags(listCmd, "name, windows, attached, dir, tags, dormant, saved_at")
 	rootCmd.AddCommand(listCmd)
 }
@@ -4,11 +4,40 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 )
 
+// seedSavedSessions writes a sessions.json into a fresh PORTAL_STATE_DIR
+// holding one single-pane session per name, saved at savedAt.
+func seedSavedSessions(t *testing.T, savedAt time.Time, names ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	idx := state.Index{SavedAt: savedAt}
+	for _, name := range names {
+		idx.Sessions = append(idx.Sessions, state.Session{
+			Name:    name,
+			Windows: []state.Window{{Panes: []state.Pane{{CWD: "/tmp"}}}},
+		})
+	}
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatalf("EncodeIndex: %v", err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatalf("write sessions.json: %v", err)
+	}
+}
+
 // mockSessionLister implements SessionLister for testing.
 type mockSessionLister struct {
 	sessions []tmux.Session
@@ -279,4 +308,187 @@ func TestListCommand(t *testing.T) {
 			t.Fatal("expected error for mutually exclusive flags, got nil")
 		}
 	})
+
+	t.Run("--json emits every session with dir and project tags", func(t *testing.T) {
+		projectDir := t.TempDir()
+		projectsFile := filepath.Join(t.TempDir(), "projects.json")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		seed := `{"projects":[{"path":"` + projectDir + `","name":"flowx","last_used":"2026-01-01T00:00:00Z","tags":["Work"," "]}]}`
+		if err := os.WriteFile(projectsFile, []byte(seed), 0o600); err != nil {
+			t.Fatalf("write projects.json: %v", err)
+		}
+		listDeps = &ListDeps{
+			Lister: &mockSessionLister{sessions: []tmux.Session{
+				{Name: "flowx-dev", Windows: 3, Attached: true, Dir: projectDir},
+				{Name: "scratch", Windows: 1},
+			}},
+			IsTTY: func() bool { return true },
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list", "--json"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []sessionRecord
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, buf)
+		}
+		want := []sessionRecord{
+			{Name: "flowx-dev", Windows: 3, Attached: true, Dir: projectDir, Tags: []string{"Work"}},
+			{Name: "scratch", Windows: 1, Tags: []string{}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("records = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("--json with no sessions emits an empty array", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+		listDeps = &ListDeps{Lister: &mockSessionLister{}, IsTTY: func() bool { return false }}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list", "--json"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != "[]\n" {
+			t.Errorf("output = %q, want %q", buf.String(), "[]\n")
+		}
+	})
+
+	t.Run("--format applies the template per session", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+		listDeps = &ListDeps{
+			Lister: &mockSessionLister{sessions: []tmux.Session{
+				{Name: "flowx-dev", Windows: 3, Attached: true},
+				{Name: "scratch", Windows: 1},
+			}},
+			IsTTY: func() bool { return false },
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list", "--format", `{{.name}}\t{{.windows}}\t{{.attached}}`})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "flowx-dev\t3\ttrue\nscratch\t1\tfalse\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("--format rejects an invalid template", func(t *testing.T) {
+		listDeps = &ListDeps{Lister: &mockSessionLister{}, IsTTY: func() bool { return false }}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"list", "--format", "{{.name"})
+
+		err := rootCmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "invalid --format template") {
+			t.Fatalf("err = %v, want invalid-template error", err)
+		}
+	})
+
+	t.Run("--all appends saved sessions that are not running", func(t *testing.T) {
+		savedAt := time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)
+		seedSavedSessions(t, savedAt, "flowx-dev", "old-work")
+		listDeps = &ListDeps{
+			Lister: &mockSessionLister{sessions: []tmux.Session{{Name: "flowx-dev", Windows: 3}}},
+			IsTTY:  func() bool { return true },
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list", "--all"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "flowx-dev    detached    3 windows\n" +
+			"old-work    dormant    1 window    saved " + savedAt.Local().Format("2006-01-02 15:04") + "\n"
+		if buf.String() != want {
+			t.Errorf("output = %q, want %q", buf.String(), want)
+		}
+	})
+
+	t.Run("dormant sessions are only listed with --all", func(t *testing.T) {
+		seedSavedSessions(t, time.Now(), "old-work")
+		listDeps = &ListDeps{Lister: &mockSessionLister{}, IsTTY: func() bool { return false }}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("output = %q, want empty", buf.String())
+		}
+	})
+
+	t.Run("--all --json marks dormant records with their saved time", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+		savedAt := time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)
+		seedSavedSessions(t, savedAt, "old-work")
+		listDeps = &ListDeps{
+			Lister: &mockSessionLister{sessions: []tmux.Session{{Name: "scratch", Windows: 1}}},
+			IsTTY:  func() bool { return false },
+		}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"list", "--all", "--json"})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []sessionRecord
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, buf)
+		}
+		want := []sessionRecord{
+			{Name: "scratch", Windows: 1, Tags: []string{}},
+			{Name: "old-work", Windows: 1, Tags: []string{}, Dormant: true, SavedAt: savedAt},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("records = %+v, want %+v", got, want)
+		}
+		if strings.Count(buf.String(), "saved_at") != 1 {
+			t.Errorf("saved_at should appear on the dormant record only:\n%s", buf)
+		}
+	})
+
+	t.Run("--short cannot be combined with --json", func(t *testing.T) {
+		listDeps = &ListDeps{Lister: &mockSessionLister{}, IsTTY: func() bool { return false }}
+		t.Cleanup(func() { listDeps = nil })
+
+		resetRootCmd()
+		rootCmd.SetArgs([]string{"list", "--short", "--json"})
+
+		if err := rootCmd.Execute(); err == nil {
+			t.Fatal("expected error combining --short with --json, got nil")
+		}
+	})
 }
@@ -9,6 +9,8 @@ import (
 	"syscall"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/frecency"
+	"github.com/leeovery/portal/internal/grep"
 	"github.com/leeovery/portal/internal/log"
 	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/project"
@@ -46,6 +48,11 @@ var openPathFunc = openPath
 // assignment to capture the target without building a real connector.
 var openSessionFunc = openSession
 
+// resurrectLazyFunc brings a lazy-policy session back before a bare target or
+// -s pin is resolved, so the resolver finds it live. Tests override it via
+// t.Cleanup-restored assignment to observe the call without a restore.
+var resurrectLazyFunc = resurrectLazyTarget
+
 // openDeps holds injectable dependencies for the open command.
 // When nil, real implementations are used.
 var openDeps *OpenDeps
@@ -56,6 +63,9 @@ type OpenDeps struct {
 	AliasLookup   resolver.AliasLookup
 	Zoxide        resolver.ZoxideQuerier
 	DirValidator  resolver.DirValidator
+	// Frecency is Portal's own history for the bare-target chain's fallback
+	// before zoxide. When nil the step is skipped.
+	Frecency resolver.FrecencyQuerier
 	// AckWriter writes the @portal-spawn-<batch>-<token> confirmation marker for
 	// a spawned window (the hidden --ack carrier). When nil, buildAckWriter falls
 	// back to a real @portal-spawn- server-option channel over the shared tmux
@@ -156,8 +166,9 @@ var openCmd = &cobra.Command{
 With no arguments, open launches the TUI picker so you can choose a destination.
 
 A bare target is resolved through the precedence chain — exact session name → path
-→ alias → zoxide query, first match wins. An exact session name attaches that
-existing session; a path, alias, or zoxide match mints a new session there.
+→ alias → Portal's own recent directories → zoxide query, first match wins. An
+exact session name attaches that existing session; any directory match mints a
+new session there.
 
 Domain pins skip the precedence chain and force a single domain:
   -s, --session   attach the named session or session glob (never mints)
@@ -173,7 +184,9 @@ A command to run in a freshly minted session is scoped with -e/--exec or after a
 
 Passing two or more targets (or one glob that expands to several sessions) opens a
 portal to each: this terminal becomes the first surface and the remaining N−1 open
-in host-terminal windows.`,
+in host-terminal windows. --as tabs or --as splits opens them as tabs of the host
+window or splits of the host pane instead, on terminals that support it; others
+fall back to windows.`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		command, destination, err := parseCommandArgs(cmd, args)
@@ -194,6 +207,12 @@ in host-terminal windows.`,
 			}
 		}
 
+		// --as picks where the burst's N−1 surfaces open. Validated here, with
+		// --ack, so a typo is a usage error before any tmux call or spawn.
+		if _, err := openPlacement(cmd); err != nil {
+			return NewUsageError("open: " + err.Error())
+		}
+
 		// -f/--filter is the sole non-composing flag (spec § -f/--filter is the sole
 		// non-composing flag; § Target-set composition): not a target, but a "skip
 		// resolution, open the picker pre-filtered" redirect. Handled BEFORE
@@ -254,6 +273,13 @@ in host-terminal windows.`,
 
 		query := destination
 
+		// A lazy session was left dormant by the reboot restore; naming it is
+		// the request to bring it back, so recreate it before the session
+		// domain is checked.
+		if err := resurrectLazyFunc(cmd, query); err != nil {
+			return err
+		}
+
 		qr, err := buildQueryResolver(cmd)
 		if err != nil {
 			return err
@@ -331,6 +357,11 @@ var pinResolvers = map[string]func(*resolver.QueryResolver, string) (resolver.Qu
 // handoff are identical for every pin.
 func resolvePinAndOpen(cmd *cobra.Command, flag string, resolve func(*resolver.QueryResolver, string) (resolver.QueryResult, error), command []string) error {
 	val, _ := cmd.Flags().GetString(flag)
+	if flag == "session" {
+		if err := resurrectLazyFunc(cmd, val); err != nil {
+			return err
+		}
+	}
 	qr, err := buildQueryResolver(cmd)
 	if err != nil {
 		return err
@@ -366,10 +397,12 @@ func openResolved(cmd *cobra.Command, result resolver.QueryResult, command []str
 		// The --ack marker write is the LAST act before the attach handoff, and
 		// strictly AFTER the command guard above — a command+attach usage error
 		// must fire without writing a marker (spec § Hidden --ack flag).
+		recordVisitFunc(frecency.KindSession, r.Name)
 		writeAckMarker(cmd)
 		return openSessionFunc(cmd, r.Name)
 	case *resolver.PathResult:
 		// The --ack marker write is the LAST act before the mint handoff.
+		recordVisitFunc(frecency.KindDir, r.Path)
 		writeAckMarker(cmd)
 		return openPathFunc(cmd, r.Path, command)
 	default:
@@ -611,9 +644,9 @@ func openPath(cmd *cobra.Command, resolvedPath string, command []string) error {
 
 	opener := &PathOpener{
 		insideTmux: insideTmux,
-		creator:    session.NewSessionCreator(gitResolver, store, client, gen),
+		creator:    session.NewSessionCreator(gitResolver, store, client, gen).WithTemplates(store).WithCreateHook(createHookCommand),
 		switcher:   client,
-		qs:         &quickStartAdapter{qs: session.NewQuickStart(gitResolver, store, client, gen)},
+		qs:         &quickStartAdapter{qs: session.NewQuickStart(gitResolver, store, client, gen).WithTemplates(store).WithCreateHook(createHookCommand)},
 		execer:     &realExecer{},
 	}
 
@@ -646,13 +679,22 @@ type tuiConfig struct {
 	aliasEditor     tui.AliasEditor
 	sessionCreator  tui.SessionCreator
 	enumerator      tui.TmuxEnumerator
+	capturer        tui.PaneCapturer
 	reader          tui.ScrollbackReader
 	previewAttacher tui.PreviewAttacher
+	searcher        tui.ScrollbackSearcher
 	dirReader       session.PaneCurrentPathReader
 	dirRunner       resolver.CommandRunner
 	initialMode     prefs.SessionListMode
 	appearance      prefs.Appearance
 	modePersister   tui.ModePersister
+	// frecency is the frecency.json snapshot the picker's Recent mode orders by.
+	frecency []frecency.Entry
+	// dormant backs the picker's Dormant section: sessions.json entries that
+	// are not running, resurrected on Enter via the on-demand restorer.
+	dormant tui.DormantSource
+	// agents backs the picker's agent-state glyphs and By Status mode.
+	agents tui.AgentSource
 	// detector + resolve are the §6 async host-terminal detection seams. Built
 	// once at TUI construction (detector over the shared *tmux.Client; resolve from
 	// the config-aware buildResolver, terminals.json loaded once) and threaded into
@@ -669,6 +711,8 @@ type tuiConfig struct {
 	ackChannel    spawn.AckChannelFull
 	spawnExe      spawn.ExecutableResolver
 	spawnGetenv   func(string) string
+	// burstPlacement seeds the picker burst's placement from `open --as`.
+	burstPlacement spawn.Placement
 	// spawnLogger is the §6-10 spawn-component logger the picker burst's completion
 	// chokepoint emits its batch summary + per-window detail through (log.For("spawn")
 	// in production; the parallel to cmd/spawn_seams.go's package-level spawnLogger).
@@ -715,11 +759,16 @@ func buildTUIModel(cfg tuiConfig, initialFilter string, command []string) tui.Mo
 		ProjectEditor:    cfg.projectEditor,
 		AliasEditor:      cfg.aliasEditor,
 		Enumerator:       cfg.enumerator,
+		Capturer:         cfg.capturer,
 		Reader:           cfg.reader,
 		PreviewAttacher:  cfg.previewAttacher,
+		Searcher:         cfg.searcher,
 		DirReader:        cfg.dirReader,
 		DirRunner:        cfg.dirRunner,
 		ModePersister:    cfg.modePersister,
+		Frecency:         cfg.frecency,
+		Dormant:          cfg.dormant,
+		Agents:           cfg.agents,
 		CWD:              cfg.cwd,
 		InitialMode:      cfg.initialMode,
 		Appearance:       cfg.appearance,
@@ -736,6 +785,7 @@ func buildTUIModel(cfg tuiConfig, initialFilter string, command []string) tui.Mo
 		AckChannel:       cfg.ackChannel,
 		SpawnExe:         cfg.spawnExe,
 		SpawnGetenv:      cfg.spawnGetenv,
+		BurstPlacement:   cfg.burstPlacement,
 		SpawnLogger:      cfg.spawnLogger,
 	})
 }
@@ -760,6 +810,12 @@ func processTUIResult(model tui.Model, connector SessionConnector) error {
 	if selected == "" {
 		return nil
 	}
+	// Credit the visit before the connector hands off: outside tmux Connect
+	// execs tmux and never returns. A picker mint also credits its directory.
+	if dir := model.MintedDir(); dir != "" {
+		recordVisitFunc(frecency.KindDir, dir)
+	}
+	recordVisitFunc(frecency.KindSession, selected)
 	return connector.Connect(selected)
 }
 
@@ -849,6 +905,12 @@ func openTUI(cmd *cobra.Command, initialFilter string, command []string, serverS
 	if prefsStore != nil {
 		appearance, _ = prefsStore.LoadAppearance()
 	}
+	// Snapshot Portal's frecency history for the Recent mode. Tolerant like the
+	// prefs reads: any failure leaves Recent mode in alphabetical order.
+	var frecencyEntries []frecency.Entry
+	if frecencyStore, err := loadFrecencyStore(); err == nil {
+		frecencyEntries, _ = frecencyStore.Load()
+	}
 
 	// Resolve the connector once. It is used post-TUI by processTUIResult
 	// for both Sessions-page Enter and Preview-page Enter. Both
@@ -883,18 +945,23 @@ func openTUI(cmd *cobra.Command, initialFilter string, command []string, serverS
 		projectStore:    store,
 		projectEditor:   store,
 		aliasEditor:     aliasStore,
-		sessionCreator:  session.NewSessionCreator(gitResolver, store, client, gen),
+		sessionCreator:  session.NewSessionCreator(gitResolver, store, client, gen).WithTemplates(store).WithCreateHook(createHookCommand),
 		enumerator:      client,
+		capturer:        client,
 		reader:          previewReader,
 		previewAttacher: previewAttacher,
+		searcher:        pickerSearcher{corpus: grep.Live{Client: client, Saved: grep.Saved{StateDir: stateDir}}},
 		// Render-layer lazy directory-resolution fallback: client
 		// (*tmux.Client) satisfies session.PaneCurrentPathReader via
 		// ActivePaneCurrentPath; RealCommandRunner resolves the active pane's
 		// cwd to a git-root. The result is cached in-memory only, never stamped.
 		dirReader:     client,
 		dirRunner:     &resolver.RealCommandRunner{},
+		dormant:       &dormantSource{stateDir: stateDir, restorer: newOnDemandRestorer(client, stateDir)},
+		agents:        &agentStateSource{stateDir: stateDir},
 		initialMode:   initialMode,
 		appearance:    appearance,
+		frecency:      frecencyEntries,
 		cwd:           cwd,
 		serverStarted: serverStarted,
 		// §6 async host-terminal detection seams, from the shared builder: the
@@ -914,6 +981,9 @@ func openTUI(cmd *cobra.Command, initialFilter string, command []string, serverS
 		ackChannel:    spawnSeams.Ack,
 		spawnExe:      spawnSeams.Exe,
 		spawnGetenv:   spawnSeams.Getenv,
+		// `open --as` (validated at the top of RunE) seeds the multi-select burst
+		// placement; the picker's t toggle cycles it from there.
+		burstPlacement: placementOrWindows(cmd),
 		// §6-10: the picker burst's spawn-component logger — the TUI parallel to
 		// cmd/spawn_seams.go's package-level spawnLogger = log.For("spawn").
 		spawnLogger: spawnSeams.Logger,
@@ -994,10 +1064,15 @@ func openTUI(cmd *cobra.Command, initialFilter string, command []string, serverS
 // buildQueryResolver creates a QueryResolver with appropriate dependencies.
 // The session lister is the user-visible (leading-underscore-filtered) session
 // set: openDeps.SessionLister when injected, otherwise the shared *tmux.Client
-// (which satisfies resolver.SessionLister via ListSessionNames).
+// (which satisfies resolver.SessionLister via ListSessionNames). Portal's own
+// frecency history is wired as the fallback before zoxide.
 func buildQueryResolver(cmd *cobra.Command) (*resolver.QueryResolver, error) {
 	if openDeps != nil {
-		return resolver.NewQueryResolver(openDeps.SessionLister, openDeps.AliasLookup, openDeps.Zoxide, openDeps.DirValidator), nil
+		qr := resolver.NewQueryResolver(openDeps.SessionLister, openDeps.AliasLookup, openDeps.Zoxide, openDeps.DirValidator)
+		if openDeps.Frecency != nil {
+			qr.WithFrecency(openDeps.Frecency)
+		}
+		return qr, nil
 	}
 
 	store, err := loadAliasStore()
@@ -1008,7 +1083,12 @@ func buildQueryResolver(cmd *cobra.Command) (*resolver.QueryResolver, error) {
 	zoxide := resolver.NewZoxideResolver(&resolver.RealCommandRunner{}, exec.LookPath)
 	dirValidator := &resolver.OSDirValidator{}
 
-	return resolver.NewQueryResolver(tmuxClient(cmd), store, zoxide, dirValidator), nil
+	qr := resolver.NewQueryResolver(tmuxClient(cmd), store, zoxide, dirValidator)
+	// A frecency path failure only costs the fallback step, never the resolve.
+	if frecencyStore, err := loadFrecencyStore(); err == nil {
+		qr.WithFrecency(frecencyStore)
+	}
+	return qr, nil
 }
 
 func init() {
@@ -1018,6 +1098,7 @@ func init() {
 	openCmd.Flags().StringP("path", "p", "", "mint a new session at the given directory (path-domain; dir must exist)")
 	openCmd.Flags().StringP("alias", "a", "", "mint a new session at the given alias key or key glob (alias-domain)")
 	openCmd.Flags().StringP("zoxide", "z", "", "mint a new session at zoxide's best match (zoxide-domain; explicit error if zoxide is not installed)")
+	openCmd.Flags().String("as", "", "open the other surfaces of a multi-target open as windows, tabs, or splits (default windows)")
 	openCmd.Flags().String("ack", "", "internal: <batch>:<token> — write the @portal-spawn-<batch>-<token> ack marker before the attach/mint handoff")
 	_ = openCmd.Flags().MarkHidden("ack")
 
@@ -174,7 +174,18 @@ func runOpenBurstWithDeps(cmd *cobra.Command, surfaces []spawn.Surface, command
 	// that could not even start. The per-window ~8s ack timeout is provided by the
 	// burster itself (spawnAckTimeout + awaitToken, each window timed from its OWN
 	// spawn); this path adds no timeout logic of its own.
-	batch, results, err := deps.NewBurster(adapter).Run(context.Background(), external, command, nil)
+	//
+	// --as picks the adapter capability each external surface opens through. A
+	// terminal whose driver cannot open the requested tabs/splits falls back to
+	// windows with a one-line note rather than refusing the burst.
+	requested := placementOrWindows(cmd)
+	placement, ok := spawn.EffectivePlacement(adapter, requested)
+	if !ok {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), spawn.PlacementFallbackMessage(id, requested))
+	}
+	burster := deps.NewBurster(adapter)
+	burster.Placement = placement
+	batch, results, err := burster.Run(context.Background(), external, command, nil)
 	if err != nil {
 		return err
 	}
@@ -241,6 +252,27 @@ func runOpenBurstWithDeps(cmd *cobra.Command, surfaces []spawn.Surface, command
 	return nil
 }
 
+// openPlacement reads open's --as flag as a spawn.Placement; an unset flag is
+// PlacementWindows. A command without the flag (tests driving the burst body
+// directly) also reads as windows.
+func openPlacement(cmd *cobra.Command) (spawn.Placement, error) {
+	val, _ := cmd.Flags().GetString("as")
+	if val == "" {
+		return spawn.PlacementWindows, nil
+	}
+	return spawn.ParsePlacement(val)
+}
+
+// placementOrWindows is openPlacement for callers past RunE's validation, where
+// the flag is known-good.
+func placementOrWindows(cmd *cobra.Command) spawn.Placement {
+	p, err := openPlacement(cmd)
+	if err != nil {
+		return spawn.PlacementWindows
+	}
+	return p
+}
+
 // hasMintSurface reports whether any surface in the set is a mint (a fresh session
 // at a directory) — the surface kind a mint-scoped command can run in. Used by the
 // multi-target zero-mint command guard.
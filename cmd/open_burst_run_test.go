@@ -34,6 +34,7 @@ type openBurstEvents struct {
 // then delegates to the inner FakeAdapter (which records argv + writes the ack
 // marker for a confirmed window).
 type recordingAdapter struct {
+	spawn.WindowsOnly
 	events *openBurstEvents
 	inner  *spawntest.FakeAdapter
 }
@@ -682,6 +683,7 @@ func (d *burstDelayingAck) Clean(batch string) error {
 // writingAdapter, used only where the burster's Ack is a burstDelayingAck (whose
 // concrete type the spawntest.FakeAdapter cannot target).
 type ackWritingAdapter struct {
+	spawn.WindowsOnly
 	ack     spawn.AckWriter
 	confirm []bool
 	calls   int
@@ -1104,3 +1106,81 @@ func TestRunOpenBurst_PerWindowAckTimeout_TimedFromOwnSpawn(t *testing.T) {
 		t.Errorf("self-connect targets = %#v, want exactly [trig]", conn.calls)
 	}
 }
+
+// placementCmd returns a bare command carrying open's --as flag set to value, so
+// runOpenBurstWithDeps reads it exactly as it would under a real `open --as`.
+func placementCmd(t *testing.T, value string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("as", "", "")
+	if err := cmd.Flags().Set("as", value); err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	return cmd, &stderr
+}
+
+func TestRunOpenBurst_AsTabsOpensExternalsAsTabs(t *testing.T) {
+	inner := &spawntest.FakeAdapter{}
+	conn := &recordingConnector{events: &openBurstEvents{}}
+	ack := &spawntest.FakeAckChannel{}
+	deps := openBurstDepsForTest(ghosttyIdentity(), spawn.ResolutionNative, inner, conn, nil)
+	withOpenBurster(deps, inner, ack, &manualClock{})
+	cmd, stderr := placementCmd(t, "tabs")
+
+	surfaces := spawn.AttachSurfaces([]string{"a", "b", "c"})
+	if err := runOpenBurstWithDeps(cmd, surfaces, nil, deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []spawn.Placement{spawn.PlacementTabs, spawn.PlacementTabs}
+	if !slices.Equal(inner.Placements, want) {
+		t.Errorf("external placements = %v, want %v", inner.Placements, want)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want no output on a fully confirmed tab burst", stderr.String())
+	}
+	if !slices.Equal(conn.calls, []string{"a"}) {
+		t.Errorf("self-connect targets = %#v, want [a]", conn.calls)
+	}
+}
+
+func TestRunOpenBurst_AsSplitsFallsBackToWindowsOnWindowsOnlyTerminal(t *testing.T) {
+	inner := &spawntest.FakeAdapter{WindowsOnly: true}
+	conn := &recordingConnector{events: &openBurstEvents{}}
+	ack := &spawntest.FakeAckChannel{}
+	deps := openBurstDepsForTest(ghosttyIdentity(), spawn.ResolutionNative, inner, conn, nil)
+	withOpenBurster(deps, inner, ack, &manualClock{})
+	cmd, stderr := placementCmd(t, "splits")
+
+	if err := runOpenBurstWithDeps(cmd, spawn.AttachSurfaces([]string{"a", "b"}), nil, deps); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(inner.Placements, []spawn.Placement{spawn.PlacementWindows}) {
+		t.Errorf("external placements = %v, want one window", inner.Placements)
+	}
+	want := spawn.PlacementFallbackMessage(ghosttyIdentity(), spawn.PlacementSplits)
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr = %q, want the fallback note %q", stderr.String(), want)
+	}
+}
+
+func TestOpenCommand_AsRejectsUnknownPlacement(t *testing.T) {
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	resetRootCmd()
+	rootCmd.SetArgs([]string{"open", "a", "b", "--as", "panes"})
+
+	err := rootCmd.Execute()
+
+	var usageErr *UsageError
+	if !errors.As(err, &usageErr) {
+		t.Fatalf("error = %v (%T), want a *UsageError", err, err)
+	}
+	if !strings.Contains(err.Error(), `invalid placement "panes"`) {
+		t.Errorf("error = %q, want it to name the bad placement", err.Error())
+	}
+}
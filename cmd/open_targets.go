@@ -17,7 +17,7 @@ type Target struct {
 
 // openTargetPins maps every known value-taking open flag (both long and short
 // forms) to the Target domain its value belongs to. An excluded flag
-// (-e/--exec, -f/--filter, --ack) maps to the empty domain: its value is still
+// (-e/--exec, -f/--filter, --as, --ack) maps to the empty domain: its value is still
 // consumed off the argv, but it is never emitted as a target. Any flag-like
 // token absent from this map is a flag cobra already validated (e.g. a boolean
 // or --help) and is skipped without consuming a following value.
@@ -45,6 +45,7 @@ var openTargetPins = map[string]resolver.Domain{
 	"-a": resolver.DomainAlias, "--alias": resolver.DomainAlias,
 	"-e": "", "--exec": "",
 	"-f": "", "--filter": "",
+	"--as":  "",
 	"--ack": "",
 }
 
@@ -56,7 +57,7 @@ var openTargetPins = map[string]resolver.Domain{
 //
 // It is a pure classifier, not a validator: it assumes cobra already accepted
 // the argv (RunE runs after the cobra parse), so it never rejects a token — it
-// only attributes each to its domain. -e/--exec, -f/--filter, and --ack values
+// only attributes each to its domain. -e/--exec, -f/--filter, --as, and --ack values
 // are consumed but never emitted; everything after a bare `--` (command
 // passthrough) is dropped. No dedup — repeats are honoured as intent.
 func orderedOpenTargets(args []string) []Target {
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// outputOptions is the machine-readable output mode selected by the shared
+// --json / --format flags. The zero value is the command's human output.
+type outputOptions struct {
+	json   bool
+	format string
+}
+
+// structured reports whether a machine-readable mode was selected.
+func (o outputOptions) structured() bool {
+	return o.json || o.format != ""
+}
+
+// addOutputFlags registers --json and --format on cmd. schema names the
+// fields each record carries, for the --format help text.
+func addOutputFlags(cmd *cobra.Command, schema string) {
+	cmd.Flags().Bool("json", false, "Output as JSON")
+	cmd.Flags().String("format", "", "Format each record with a Go template (fields: "+schema+")")
+}
+
+// readOutputFlags returns the output mode selected on cmd. --json and
+// --format are mutually exclusive; a --format template that does not parse
+// is rejected before any work is done.
+func readOutputFlags(cmd *cobra.Command) (outputOptions, error) {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	format, _ := cmd.Flags().GetString("format")
+	if asJSON && format != "" {
+		return outputOptions{}, fmt.Errorf("--json and --format are mutually exclusive")
+	}
+	if format != "" {
+		if _, err := parseFormatTemplate(format); err != nil {
+			return outputOptions{}, err
+		}
+	}
+	return outputOptions{json: asJSON, format: format}, nil
+}
+
+// parseFormatTemplate parses a --format template. The template gets a json
+// function for emitting a field (e.g. a tag list) as JSON, and `\t` / `\n`
+// escapes typed on the command line are honoured so tab-separated output
+// does not need shell quoting tricks.
+func parseFormatTemplate(format string) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeJSON writes v to w as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeFormatted executes the --format template once per record, each
+// followed by a newline. Records are round-tripped through their JSON form
+// first, so templates address the same field names as --json output
+// (`{{.name}}`, not the Go field names).
+func writeFormatted[T any](w io.Writer, format string, records []T) error {
+	tmpl, err := parseFormatTemplate(format)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		var fields any
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return err
+		}
+		if err := tmpl.Execute(w, fields); err != nil {
+			return fmt.Errorf("execute --format template: %w", err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRecords renders records in the selected structured mode: a JSON array
+// (never null, so an empty result is `[]`) or one template line per record.
+func writeRecords[T any](w io.Writer, opts outputOptions, records []T) error {
+	if opts.json {
+		if records == nil {
+			records = []T{}
+		}
+		return writeJSON(w, records)
+	}
+	return writeFormatted(w, opts.format, records)
+}
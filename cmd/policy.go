@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show or change which sessions are restored when tmux starts",
+	Long: `Show or change the session restore policy.
+
+  always  save the session and recreate it whenever the tmux server starts
+  lazy    save the session but leave it dormant at start; it is recreated when
+          picked in the TUI or opened by name (x <name>)
+  never   do not save the session at all
+
+The global policy lives in prefs.json. A project may override it in
+projects.json; the override applies to every session opened in that
+project's directory, wherever its panes have since moved.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefsStore, err := loadPrefsStore()
+		if err != nil {
+			return err
+		}
+		global, err := prefsStore.LoadRestorePolicy()
+		if err != nil {
+			return fmt.Errorf("failed to load prefs: %w", err)
+		}
+
+		projectStore, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+		projects, err := projectStore.List()
+		if err != nil {
+			return fmt.Errorf("failed to load projects: %w", err)
+		}
+
+		w := cmd.OutOrStdout()
+		if _, err := fmt.Fprintf(w, "global: %s\n", global); err != nil {
+			return err
+		}
+		for _, p := range projects {
+			if p.RestorePolicy == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s: %s\n", p.Path, p.RestorePolicy); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var policySetCmd = &cobra.Command{
+	Use:       "set <always|lazy|never> [project-dir]",
+	Short:     "Set the global policy, or a project's override",
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: prefs.RestorePolicyNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy, ok := prefs.ParseRestorePolicy(args[0])
+		if !ok {
+			return NewUsageError(fmt.Sprintf("unknown restore policy %q (want %s)", args[0], strings.Join(prefs.RestorePolicyNames, ", ")))
+		}
+
+		if len(args) == 1 {
+			store, err := loadPrefsStore()
+			if err != nil {
+				return err
+			}
+			if err := store.SaveRestorePolicy(policy); err != nil {
+				return fmt.Errorf("failed to save prefs: %w", err)
+			}
+			return nil
+		}
+
+		return setProjectRestorePolicy(args[1], policy.String())
+	},
+}
+
+var policyRmCmd = &cobra.Command{
+	Use:   "rm <project-dir>",
+	Short: "Remove a project's override so it follows the global policy",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setProjectRestorePolicy(args[0], "")
+	},
+}
+
+// setProjectRestorePolicy stores policy ("" clears it) on the remembered
+// project at dir.
+func setProjectRestorePolicy(dir, policy string) error {
+	return updateProjectAt(dir, func(store *project.Store, path string) error {
+		return store.SetRestorePolicy(path, policy)
+	})
+}
+
+// updateProjectAt applies update to the remembered project at dir, passing
+// its stored path. dir may be relative or use ~, and matches the project by
+// canonical path, so a symlinked spelling of the directory still finds it.
+func updateProjectAt(dir string, update func(store *project.Store, path string) error) error {
+	store, err := loadProjectStore()
+	if err != nil {
+		return err
+	}
+	projects, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	key := project.CanonicalDirKey(resolver.NormalisePath(dir))
+	for _, p := range projects {
+		if project.CanonicalDirKey(p.Path) != key {
+			continue
+		}
+		if err := update(store, p.Path); err != nil {
+			return fmt.Errorf("failed to save projects: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", project.ErrProjectNotFound, dir)
+}
+
+// loadRestorePolicy resolves the restore policy in force from prefs.json and
+// projects.json: a session follows the override of the project containing
+// its project directory (state.ProjectDir), else the global setting. It returns nil —
+// every session always restored, the behaviour before policies existed — when
+// neither file asks for anything else, which also skips building the
+// project index. Unreadable config falls back the same way: a policy must
+// never be the reason a session is lost.
+func loadRestorePolicy() state.RestorePolicyFunc {
+	global := prefs.RestoreAlways
+	if store, err := loadPrefsStore(); err == nil {
+		global, _ = store.LoadRestorePolicy()
+	}
+
+	var projects []project.Project
+	if store, err := loadProjectStore(); err == nil {
+		projects, _ = store.Load()
+	}
+
+	if global == prefs.RestoreAlways && !project.HasRestorePolicy(projects) {
+		return nil
+	}
+
+	idx := project.NewIndex(projects)
+	return func(sess state.Session) prefs.RestorePolicy {
+		if policy, ok := idx.RestorePolicy(state.ProjectDir(sess)); ok {
+			return policy
+		}
+		return global
+	}
+}
+
+// restorePolicyCache serves loadRestorePolicy to the daemon, which needs the
+// policy on every capture: it re-reads prefs.json and projects.json only when
+// either file's size or modification time changes, so a `policy set` still
+// takes effect on the next tick.
+type restorePolicyCache struct {
+	loaded bool
+	stamps [2]fileStamp
+	policy state.RestorePolicyFunc
+}
+
+// fileStamp is the size and modification time of a file; the zero value
+// stands for a missing or unreadable one.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+func statStamp(path string, err error) fileStamp {
+	if err != nil {
+		return fileStamp{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// Load returns the restore policy in force, rebuilding it only when a config
+// file changed since the last call.
+func (c *restorePolicyCache) Load() state.RestorePolicyFunc {
+	stamps := [2]fileStamp{statStamp(prefsFilePath()), statStamp(projectsFilePath())}
+	if !c.loaded || stamps != c.stamps {
+		c.loaded, c.stamps, c.policy = true, stamps, loadRestorePolicy()
+	}
+	return c.policy
+}
+
+func init() {
+	policyCmd.AddCommand(policySetCmd)
+	policyCmd.AddCommand(policyRmCmd)
+	rootCmd.AddCommand(policyCmd)
+}
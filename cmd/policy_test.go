@@ -0,0 +1,261 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// isolatePolicyConfig points prefs.json and projects.json at a temp dir and
+// remembers one project at projectDir.
+func isolatePolicyConfig(t *testing.T) (projectDir string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("PORTAL_PREFS_FILE", filepath.Join(dir, "prefs.json"))
+	projectsFile := filepath.Join(dir, "projects.json")
+	t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+
+	projectDir = filepath.Join(dir, "api")
+	if err := os.Mkdir(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.NewStore(projectsFile).Upsert(projectDir, "api", "internal"); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	return projectDir
+}
+
+// runPolicy executes `policy args...` and returns its stdout.
+func runPolicy(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(append([]string{"policy"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestPolicyCommand(t *testing.T) {
+	t.Run("set without a directory changes the global policy", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		if _, err := runPolicy(t, "set", "lazy"); err != nil {
+			t.Fatalf("policy set: %v", err)
+		}
+		out, err := runPolicy(t)
+		if err != nil {
+			t.Fatalf("policy: %v", err)
+		}
+
+		if out != "global: lazy\n" {
+			t.Errorf("output = %q, want %q", out, "global: lazy\n")
+		}
+	})
+
+	t.Run("set with a directory overrides that project and rm clears it", func(t *testing.T) {
+		projectDir := isolatePolicyConfig(t)
+
+		if _, err := runPolicy(t, "set", "never", projectDir); err != nil {
+			t.Fatalf("policy set: %v", err)
+		}
+		out, _ := runPolicy(t)
+		if want := "global: always\n" + projectDir + ": never\n"; out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+
+		if _, err := runPolicy(t, "rm", projectDir); err != nil {
+			t.Fatalf("policy rm: %v", err)
+		}
+		out, _ = runPolicy(t)
+		if out != "global: always\n" {
+			t.Errorf("output after rm = %q, want only the global line", out)
+		}
+	})
+
+	t.Run("an unknown policy is a usage error", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		_, err := runPolicy(t, "set", "sometimes")
+
+		var usage *UsageError
+		if !errors.As(err, &usage) {
+			t.Fatalf("err = %v, want a UsageError", err)
+		}
+		if !strings.Contains(err.Error(), "always, lazy, never") {
+			t.Errorf("err = %q, want it to list the valid policies", err)
+		}
+	})
+
+	t.Run("a directory that is not a project is rejected", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		_, err := runPolicy(t, "set", "lazy", t.TempDir())
+
+		if !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+	})
+}
+
+func TestLoadRestorePolicy(t *testing.T) {
+	sessionIn := func(dir string) state.Session {
+		return state.Session{Windows: []state.Window{{Active: true, Panes: []state.Pane{{CWD: dir, Active: true}}}}}
+	}
+
+	t.Run("nil when nothing overrides always", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		if loadRestorePolicy() != nil {
+			t.Error("loadRestorePolicy() != nil with no policy configured")
+		}
+	})
+
+	t.Run("a project override beats the global policy", func(t *testing.T) {
+		projectDir := isolatePolicyConfig(t)
+		if _, err := runPolicy(t, "set", "lazy"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := runPolicy(t, "set", "always", projectDir); err != nil {
+			t.Fatal(err)
+		}
+
+		policy := loadRestorePolicy()
+
+		if got := policy.Of(sessionIn(projectDir)); got != prefs.RestoreAlways {
+			t.Errorf("policy in project = %v, want always", got)
+		}
+		if got := policy.Of(sessionIn(t.TempDir())); got != prefs.RestoreLazy {
+			t.Errorf("policy outside project = %v, want the global lazy", got)
+		}
+	})
+}
+
+func TestLoadRestorePolicy_MatchesProjectDirNotActivePane(t *testing.T) {
+	projectDir := isolatePolicyConfig(t)
+	if _, err := runPolicy(t, "set", "lazy", projectDir); err != nil {
+		t.Fatal(err)
+	}
+	sess := state.Session{Dir: projectDir, Windows: []state.Window{{Active: true, Panes: []state.Pane{{CWD: t.TempDir(), Active: true}}}}}
+
+	if got := loadRestorePolicy().Of(sess); got != prefs.RestoreLazy {
+		t.Errorf("policy of a session stamped for the project = %v, want lazy though its pane moved out", got)
+	}
+}
+
+func TestRestorePolicyCache(t *testing.T) {
+	isolatePolicyConfig(t)
+	cache := &restorePolicyCache{}
+
+	if cache.Load() != nil {
+		t.Fatal("Load() != nil with no policy configured")
+	}
+	if _, err := runPolicy(t, "set", "never"); err != nil {
+		t.Fatal(err)
+	}
+	path, err := prefsFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := cache.Load()
+	if got := policy.Of(state.Session{}); got != prefs.RestoreNever {
+		t.Fatalf("policy after prefs.json changed = %v, want never", got)
+	}
+	if got := cache.Load(); got == nil || got.Of(state.Session{}) != prefs.RestoreNever {
+		t.Error("an unchanged config did not keep the cached policy")
+	}
+}
+
+func TestCaptureAndCommit_DropsNeverPolicySessions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+
+	sess, panes := oneSession()
+	fc := &daemonFakeCommander{sessionsOut: sess, panesOut: panes}
+	deps := makeCaptureDeps(t, dir, fc)
+	deps.RestorePolicy = func() state.RestorePolicyFunc {
+		return func(state.Session) prefs.RestorePolicy { return prefs.RestoreNever }
+	}
+
+	if err := captureAndCommit(context.Background(), deps); err != nil {
+		t.Fatalf("captureAndCommit: %v", err)
+	}
+
+	idx, _, err := state.ReadIndex(dir)
+	if err != nil {
+		t.Fatalf("ReadIndex: %v", err)
+	}
+	if len(idx.Sessions) != 0 {
+		t.Errorf("sessions.json holds %d sessions, want none saved under never", len(idx.Sessions))
+	}
+	if _, err := os.Stat(state.ScrollbackFile(dir, state.SanitizePaneKey("work", 0, 0))); !os.IsNotExist(err) {
+		t.Errorf("scrollback was captured for a never session: %v", err)
+	}
+}
+
+func TestOpenCommand_BareTargetResurrectsLazySessionFirst(t *testing.T) {
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	// The lister only reports the session once the resurrect seam has run,
+	// proving the seam fires before the session domain is checked.
+	lister := &testSessionLister{}
+	openDeps = &OpenDeps{
+		SessionLister: lister,
+		AliasLookup:   &testAliasLookup{aliases: map[string]string{}},
+		Zoxide:        &testZoxideQuerier{err: resolver.ErrNoMatch},
+		DirValidator:  &testDirValidator{existing: map[string]bool{}},
+	}
+	t.Cleanup(func() { openDeps = nil })
+
+	var resurrected []string
+	origResurrect := resurrectLazyFunc
+	resurrectLazyFunc = func(_ *cobra.Command, name string) error {
+		resurrected = append(resurrected, name)
+		lister.names = []string{name}
+		return nil
+	}
+	t.Cleanup(func() { resurrectLazyFunc = origResurrect })
+
+	var connectedTo string
+	origSession := openSessionFunc
+	openSessionFunc = func(_ *cobra.Command, name string) error {
+		connectedTo = name
+		return nil
+	}
+	t.Cleanup(func() { openSessionFunc = origSession })
+
+	for _, argv := range [][]string{{"open", "later"}, {"open", "-s", "later"}} {
+		resurrected, connectedTo, lister.names = nil, "", nil
+		resetRootCmd()
+		rootCmd.SetArgs(argv)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v: unexpected error: %v", argv, err)
+		}
+
+		if len(resurrected) != 1 || resurrected[0] != "later" {
+			t.Errorf("%v: resurrected = %v, want [later]", argv, resurrected)
+		}
+		if connectedTo != "later" {
+			t.Errorf("%v: attached to %q, want later", argv, connectedTo)
+		}
+	}
+}
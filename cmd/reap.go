@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var reapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Show or change when idle sessions are reaped",
+	Long: `Show or change the idle reaper's settings.
+
+The state daemon kills any detached session nobody has attached to, typed
+into, or heard output from for the idle duration, after saving an
+auto-reap-<date>-<time> snapshot. Reaping is off until a duration is set.
+
+The duration and the exempt tags live in prefs.json. A project may be
+exempted on its own in projects.json; the exemption covers every session
+opened in that project's directory or below it. The daemon picks up a
+change within a minute.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefsStore, err := loadPrefsStore()
+		if err != nil {
+			return err
+		}
+		settings, err := prefsStore.LoadReap()
+		if err != nil {
+			return fmt.Errorf("failed to load prefs: %w", err)
+		}
+
+		projectStore, err := loadProjectStore()
+		if err != nil {
+			return err
+		}
+		projects, err := projectStore.List()
+		if err != nil {
+			return fmt.Errorf("failed to load projects: %w", err)
+		}
+
+		idle := "off"
+		if settings.IdleAfter() > 0 {
+			idle = settings.Idle
+		}
+		w := cmd.OutOrStdout()
+		if _, err := fmt.Fprintf(w, "idle: %s\n", idle); err != nil {
+			return err
+		}
+		for _, tag := range settings.ExemptTags {
+			if _, err := fmt.Fprintf(w, "exempt tag: %s\n", tag); err != nil {
+				return err
+			}
+		}
+		for _, p := range projects {
+			if !p.ReapExempt {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "exempt: %s\n", p.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var reapSetCmd = &cobra.Command{
+	Use:   "set <duration|off>",
+	Short: `Set the idle duration ("36h", "7d"; at least 10m), or turn reaping off`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idle := args[0]
+		if idle == "off" {
+			idle = ""
+		} else if _, err := prefs.ParseReapIdle(idle); err != nil {
+			return NewUsageError(fmt.Sprintf("invalid idle duration %q: %v", idle, err))
+		}
+		return updateReapSettings(func(s *prefs.ReapSettings) { s.Idle = idle })
+	},
+}
+
+var reapExemptCmd = &cobra.Command{
+	Use:   "exempt <project-dir> | --tag <tag>",
+	Short: "Never reap a project's sessions, or those of every project with a tag",
+	Args:  reapExemptArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setReapExempt(cmd, args, true)
+	},
+}
+
+var reapUnexemptCmd = &cobra.Command{
+	Use:   "unexempt <project-dir> | --tag <tag>",
+	Short: "Remove a project's or a tag's exemption",
+	Args:  reapExemptArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setReapExempt(cmd, args, false)
+	},
+}
+
+// reapExemptArgs accepts exactly one of a project directory and --tag.
+func reapExemptArgs(cmd *cobra.Command, args []string) error {
+	tag, _ := cmd.Flags().GetString("tag")
+	switch {
+	case tag == "" && len(args) == 1, tag != "" && len(args) == 0:
+		return nil
+	}
+	return NewUsageError("pass either a project directory or --tag")
+}
+
+// setReapExempt adds or removes the exemption named by the command line: the
+// --tag flag's tag in prefs.json, else the project at args[0].
+func setReapExempt(cmd *cobra.Command, args []string, exempt bool) error {
+	raw, _ := cmd.Flags().GetString("tag")
+	if raw == "" {
+		return updateProjectAt(args[0], func(store *project.Store, path string) error {
+			return store.SetReapExempt(path, exempt)
+		})
+	}
+
+	tag, ok := project.NormaliseTag(raw)
+	if !ok {
+		return NewUsageError(fmt.Sprintf("invalid tag %q", raw))
+	}
+	return updateReapSettings(func(s *prefs.ReapSettings) {
+		s.ExemptTags = slices.DeleteFunc(s.ExemptTags, func(t string) bool { return t == tag })
+		if exempt {
+			s.ExemptTags = append(s.ExemptTags, tag)
+		}
+	})
+}
+
+// updateReapSettings read-modify-writes the reaper settings in prefs.json.
+func updateReapSettings(update func(*prefs.ReapSettings)) error {
+	store, err := loadPrefsStore()
+	if err != nil {
+		return err
+	}
+	settings, err := store.LoadReap()
+	if err != nil {
+		return fmt.Errorf("failed to load prefs: %w", err)
+	}
+	update(&settings)
+	if err := store.SaveReap(settings); err != nil {
+		return fmt.Errorf("failed to save prefs: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	reapExemptCmd.Flags().String("tag", "", "exempt every project carrying this tag")
+	reapUnexemptCmd.Flags().String("tag", "", "remove this tag's exemption")
+	reapCmd.AddCommand(reapSetCmd)
+	reapCmd.AddCommand(reapExemptCmd)
+	reapCmd.AddCommand(reapUnexemptCmd)
+	rootCmd.AddCommand(reapCmd)
+}
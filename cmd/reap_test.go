@@ -0,0 +1,99 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/leeovery/portal/internal/project"
+)
+
+// runReap executes `reap args...` and returns its stdout.
+func runReap(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(append([]string{"reap"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestReapCommand(t *testing.T) {
+	t.Run("reaping is off until a duration is set", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		out, err := runReap(t)
+		if err != nil || out != "idle: off\n" {
+			t.Fatalf("reap = %q, %v; want idle: off", out, err)
+		}
+		if _, err := runReap(t, "set", "7d"); err != nil {
+			t.Fatalf("reap set: %v", err)
+		}
+		if out, _ := runReap(t); out != "idle: 7d\n" {
+			t.Errorf("output = %q, want %q", out, "idle: 7d\n")
+		}
+		if _, err := runReap(t, "set", "off"); err != nil {
+			t.Fatalf("reap set off: %v", err)
+		}
+		if got := loadReapConfig(); got.IdleAfter != 0 {
+			t.Errorf("IdleAfter = %v after off, want 0", got.IdleAfter)
+		}
+	})
+
+	t.Run("a too-short duration is a usage error", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		_, err := runReap(t, "set", "5m")
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("err = %v, want a UsageError", err)
+		}
+	})
+
+	t.Run("exempt and unexempt a project and a tag", func(t *testing.T) {
+		projectDir := isolatePolicyConfig(t)
+
+		if _, err := runReap(t, "exempt", projectDir); err != nil {
+			t.Fatalf("reap exempt: %v", err)
+		}
+		if _, err := runReap(t, "exempt", "--tag", " work "); err != nil {
+			t.Fatalf("reap exempt --tag: %v", err)
+		}
+		want := "idle: off\nexempt tag: work\nexempt: " + projectDir + "\n"
+		if out, _ := runReap(t); out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+
+		if _, err := runReap(t, "unexempt", projectDir); err != nil {
+			t.Fatalf("reap unexempt: %v", err)
+		}
+		if _, err := runReap(t, "unexempt", "--tag", "work"); err != nil {
+			t.Fatalf("reap unexempt --tag: %v", err)
+		}
+		if out, _ := runReap(t); out != "idle: off\n" {
+			t.Errorf("output = %q, want everything cleared", out)
+		}
+	})
+
+	t.Run("exempt needs exactly one of a directory and --tag", func(t *testing.T) {
+		projectDir := isolatePolicyConfig(t)
+
+		for _, args := range [][]string{{"exempt"}, {"exempt", projectDir, "--tag", "work"}} {
+			var usageErr *UsageError
+			if _, err := runReap(t, args...); !errors.As(err, &usageErr) {
+				t.Errorf("reap %v: err = %v, want a UsageError", args, err)
+			}
+		}
+	})
+
+	t.Run("exempting an unknown project fails", func(t *testing.T) {
+		isolatePolicyConfig(t)
+
+		if _, err := runReap(t, "exempt", t.TempDir()); !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/cmd/bootstrap"
+	"github.com/leeovery/portal/internal/bootstrapadapter"
+	"github.com/leeovery/portal/internal/restore"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// IndexRestorer skeleton-restores the sessions in an arbitrary state.Index on
+// the live server and returns the names it actually recreated. Sessions that
+// are already live are skipped, exactly as on a reboot restore.
+type IndexRestorer interface {
+	RestoreIndex(idx state.Index) ([]string, error)
+}
+
+// onDemandRestorer replays bootstrap steps 3, 6, 7 and 8 against a
+// caller-supplied index rather than sessions.json: set @portal-restoring,
+// run the restore.Orchestrator loop, eagerly signal the hydrate FIFOs, then
+// clear the marker. Holding the marker across the signal keeps the daemon's
+// capture suppressed while scrollback replays, the same guarantee the
+// bootstrap sequence gives a reboot restore.
+//
+// Set and Clear failures are returned (bootstrap treats both as fatal); an
+// eager-signal failure is logged and swallowed because the client-attached
+// hook re-signals any pane still carrying a skeleton marker.
+type onDemandRestorer struct {
+	client   *tmux.Client
+	stateDir string
+}
+
+// newOnDemandRestorer builds the production IndexRestorer over client.
+func newOnDemandRestorer(client *tmux.Client, stateDir string) *onDemandRestorer {
+	return &onDemandRestorer{client: client, stateDir: stateDir}
+}
+
+// RestoreIndex runs the marker-bracketed restore sequence for idx.
+func (r *onDemandRestorer) RestoreIndex(idx state.Index) ([]string, error) {
+	marker := &bootstrapadapter.RestoringMarker{Client: r.client}
+	if err := marker.Set(); err != nil {
+		return nil, fmt.Errorf("set @portal-restoring marker: %w", err)
+	}
+
+	orch := &restore.Orchestrator{
+		Client:   r.client,
+		StateDir: r.stateDir,
+		Logger:   restoreLogger,
+	}
+	restored := orch.RestoreIndex(idx)
+
+	eager := &bootstrap.EagerSignalCore{
+		Markers:  r.client,
+		StateDir: r.stateDir,
+		Signaler: state.DefaultFIFOSignaler{},
+		Logger:   hydrateLogger,
+	}
+	if err := eager.EagerSignalHydrate(); err != nil {
+		restoreLogger.Warn("eager signal hydrate failed", "error", err)
+	}
+
+	if err := marker.Clear(); err != nil {
+		return restored, fmt.Errorf("clear @portal-restoring marker: %w", err)
+	}
+	return restored, nil
+}
@@ -32,6 +32,8 @@ import (
 //     prunes automatically and `doctor --fix` is the manual home. The
 //     permanent silent `hooks` alias is covered for free: skipTmuxCheck
 //     keys on cobra's canonical c.Name()=="hook" regardless of the alias.
+//   - policy: restore-policy show/set/rm edit prefs.json and projects.json
+//     only, like alias; the policy is read by later bootstraps and the daemon.
 //   - state: every `portal state ...` subcommand. Its children are all
 //     internal (daemon, notify, signal-hydrate, hydrate, migrate-rename,
 //     commit-now), invoked by tmux hooks or as the pane's initial process;
@@ -47,6 +49,22 @@ import (
 //     _portal-saver daemon and unregisters the global hooks; if bootstrap ran
 //     first it would EnsureServer / RegisterHooks / EnsureSaver / Restore and
 //     then immediately tear all of it back down — circular, wasteful, and racy.
+//   - grep: a read-only search of the saved scrollback in the state
+//     directory. Bootstrap would restore sessions and respawn the daemon just
+//     to read files; with --live the command builds its own
+//     tmux.DefaultClient() and a down server degrades to the saved files.
+//   - export: renders the saved scrollback files in the state directory and
+//     never talks to tmux, so bootstrap would only restore sessions and
+//     respawn the daemon for nothing.
+//   - agent: `agent ingest` runs from a coding agent's hooks on every tool
+//     call and only posts a file into the state directory for the daemon.
+//     Bootstrapping there would put a restore and a daemon respawn in the
+//     path of each tool call. `agent install|uninstall|status` only edit or
+//     read the agents' own settings files.
+//   - daemon: `daemon ping|status|save|sessions|events` talk to the running
+//     daemon over its control socket and nothing else. Bootstrap would
+//     respawn the very daemon a ping is meant to observe, so, as with doctor,
+//     a down daemon is reported rather than healed.
 //   - __complete: cobra's shell-completion request verb. Its execute() runs the
 //     ROOT PersistentPreRunE (passing __complete as cmd), so WITHOUT this entry
 //     every TAB press would fire Portal's full 10-step bootstrap (starting the
@@ -58,11 +76,17 @@ import (
 //     exempt path.
 var skipTmuxCheck = map[string]bool{
 	"__complete": true,
+	"agent":      true,
 	"alias":      true,
+	"daemon":     true,
 	"doctor":     true,
+	"export":     true,
+	"grep":       true,
 	"help":       true,
 	"hook":       true,
 	"init":       true,
+	"policy":     true,
+	"reap":       true,
 	"state":      true,
 	"uninstall":  true,
 	"version":    true,
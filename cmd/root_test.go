@@ -13,6 +13,8 @@ import (
 	"testing"
 
 	"github.com/leeovery/portal/cmd/bootstrap"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -37,6 +39,10 @@ func resetRootCmd() {
 	_ = initCmd.Flags().Set("cmd", "x")       // reset to default; value is always valid
 	_ = listCmd.Flags().Set("short", "false") // reset list flags
 	_ = listCmd.Flags().Set("long", "false")
+	_ = listCmd.Flags().Set("all", "false")
+	_ = templateCaptureCmd.Flags().Set("save", "false")
+	_ = reapExemptCmd.Flags().Set("tag", "")
+	_ = reapUnexemptCmd.Flags().Set("tag", "")
 	if f := openCmd.Flags().Lookup("exec"); f != nil { // reset exec flag
 		_ = f.Value.Set("")
 		f.Changed = false
@@ -61,10 +67,16 @@ func resetRootCmd() {
 		_ = f.Value.Set("")
 		f.Changed = false
 	}
+	if f := openCmd.Flags().Lookup("as"); f != nil { // reset --as placement flag
+		_ = f.Value.Set("")
+		f.Changed = false
+	}
 	if f := openCmd.Flags().Lookup("ack"); f != nil { // reset hidden --ack receiver flag
 		_ = f.Value.Set("")
 		f.Changed = false
 	}
+	_ = agentIngestCmd.Flags().Set("agent", agent.DefaultAdapter)
+	_ = agentStatusCmd.Flags().Set("json", "false")
 	// pflag does not reset argsLenAtDash between Parse calls, and it stays stale
 	// on an empty-args Parse (an early return). A prior `open <t> -- cmd` Execute
 	// therefore leaves openCmd's dash index at a positive value; a later no-`--`
@@ -73,22 +85,55 @@ func resetRootCmd() {
 	// (Init only resets name/errorHandling/argsLenAtDash). Production is immune —
 	// each portal invocation is a fresh process — so this is a harness-only reset.
 	openCmd.Flags().Init(openCmd.Name(), pflag.ContinueOnError)
-	if f := hooksSetCmd.Flags().Lookup("on-resume"); f != nil { // reset hooks set flags
-		_ = f.Value.Set("")
-		f.Changed = false
-	}
-	if f := hooksRmCmd.Flags().Lookup("on-resume"); f != nil { // reset hooks rm flags
-		_ = f.Value.Set("false")
-		f.Changed = false
+	for _, event := range hooks.Events { // reset hooks set / rm event flags
+		if f := hooksSetCmd.Flags().Lookup(event); f != nil {
+			_ = f.Value.Set("")
+			f.Changed = false
+		}
+		if f := hooksRmCmd.Flags().Lookup(event); f != nil {
+			_ = f.Value.Set("false")
+			f.Changed = false
+		}
 	}
-	if f := hooksRmCmd.Flags().Lookup("pane-key"); f != nil {
-		_ = f.Value.Set("")
-		f.Changed = false
+	for _, f := range []*pflag.Flag{
+		hooksSetCmd.Flags().Lookup("project"), hooksRmCmd.Flags().Lookup("project"), hooksRmCmd.Flags().Lookup("pane-key"),
+		hooksSetCmd.Flags().Lookup("last-command"), hooksRmCmd.Flags().Lookup("last-command"),
+		stateHydrateCmd.Flags().Lookup("dir"), stateHydrateCmd.Flags().Lookup("session-dir"), stateHydrateCmd.Flags().Lookup("command"),
+		stateHydrateCmd.Flags().Lookup("argv"),
+	} {
+		if f != nil {
+			_ = f.Value.Set("")
+			f.Changed = false
+		}
 	}
 	if f := doctorCmd.Flags().Lookup("fix"); f != nil { // reset doctor --fix flag
 		_ = f.Value.Set("false")
 		f.Changed = false
 	}
+	for _, name := range []string{"ignore-case", "fixed-strings", "live"} { // reset grep flags
+		_ = grepCmd.Flags().Set(name, "false")
+		grepCmd.Flags().Lookup(name).Changed = false
+	}
+	_ = grepCmd.Flags().Set("context", "0")
+	grepCmd.Flags().Lookup("context").Changed = false
+	for _, name := range []string{"detached", "dry-run", "yes"} { // reset kill flags
+		_ = killCmd.Flags().Set(name, "false")
+		killCmd.Flags().Lookup(name).Changed = false
+	}
+	for name, def := range map[string]string{"older-than": "0s", "tag": "", "project": ""} {
+		_ = killCmd.Flags().Set(name, def)
+		killCmd.Flags().Lookup(name).Changed = false
+	}
+	_ = exportCmd.Flags().Set("format", "txt") // reset export flags
+	exportCmd.Flags().Lookup("format").Changed = false
+	_ = exportCmd.Flags().Set("output", "")
+	exportCmd.Flags().Lookup("output").Changed = false
+	for _, c := range []*cobra.Command{listCmd, hooksListCmd, hookSuggestCmd, aliasListCmd, doctorCmd, grepCmd} { // reset --json / --format
+		_ = c.Flags().Set("json", "false")
+		_ = c.Flags().Set("format", "")
+		c.Flags().Lookup("json").Changed = false
+		c.Flags().Lookup("format").Changed = false
+	}
 }
 
 func TestTmuxDependentCommandsFailWithoutTmux(t *testing.T) {
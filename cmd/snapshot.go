@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leeovery/portal/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// snapshotDeps holds injectable dependencies for the snapshot commands.
+// When nil, real implementations are used.
+var snapshotDeps *SnapshotDeps
+
+// SnapshotDeps allows injecting dependencies for testing. Restorer replaces
+// the production marker-bracketed restore sequence (onDemandRestorer) so
+// `snapshot restore` can be exercised without a live tmux server.
+type SnapshotDeps struct {
+	Restorer IndexRestorer
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore named copies of the saved session state",
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Freeze the current saved state under a name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := state.ValidateSnapshotName(name, false); err != nil {
+			return err
+		}
+
+		dir, err := state.EnsureDir()
+		if err != nil {
+			return fmt.Errorf("ensure state dir: %w", err)
+		}
+
+		info, err := state.SaveSnapshot(dir, name)
+		if err != nil {
+			return err
+		}
+		snapshotLogger.Info("snapshot saved", "snapshot", name, "sessions", info.Sessions, "panes", info.Panes, "via", "cli")
+
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Saved snapshot %s (%s, %s)\n",
+			name, pluralCount(info.Sessions, "session", "sessions"), pluralCount(info.Panes, "pane", "panes"))
+		return err
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := state.Dir()
+		if err != nil {
+			return err
+		}
+
+		infos, err := state.ListSnapshots(dir, snapshotLogger)
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		for _, info := range infos {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				info.Name,
+				info.SavedAt.Local().Format("2006-01-02 15:04:05"),
+				pluralCount(info.Sessions, "session", "sessions"),
+				pluralCount(info.Panes, "pane", "panes"),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name> [session...]",
+	Short: "Recreate sessions from a snapshot",
+	Long: `Recreate sessions from a snapshot exactly as a reboot restore would:
+layout, zoom, working directories and environment are rebuilt, and
+scrollback loads as each session is attached.
+
+Sessions that are already running are skipped and left untouched. Kill or
+rename a live session first to restore the snapshot's copy of it. Name one
+or more sessions after the snapshot to restore only those.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, only := args[0], args[1:]
+
+		dir, err := state.Dir()
+		if err != nil {
+			return err
+		}
+
+		idx, err := state.ReadSnapshot(dir, name)
+		if err != nil {
+			return err
+		}
+
+		idx, err = filterSnapshotSessions(idx, only)
+		if err != nil {
+			return err
+		}
+
+		restorer := buildSnapshotRestorer(cmd, dir)
+		restored, err := restorer.RestoreIndex(idx)
+		snapshotLogger.Info("snapshot restored", "snapshot", name, "sessions", len(restored), "via", "cli")
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		for _, sess := range idx.Sessions {
+			status := "restored"
+			if !slices.Contains(restored, sess.Name) {
+				status = "skipped"
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\n", sess.Name, status); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var snapshotRmCmd = &cobra.Command{
+	Use:   "rm <name>...",
+	Short: "Delete one or more snapshots",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := state.Dir()
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, name := range args {
+			if err := state.RemoveSnapshot(dir, name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			snapshotLogger.Info("snapshot removed", "snapshot", name, "via", "cli")
+		}
+		return errors.Join(errs...)
+	},
+}
+
+// filterSnapshotSessions narrows idx to the named sessions, preserving the
+// snapshot's order. An empty names list returns idx unchanged; a name the
+// snapshot does not contain is an error so a typo never silently restores
+// nothing.
+func filterSnapshotSessions(idx state.Index, names []string) (state.Index, error) {
+	if len(names) == 0 {
+		return idx, nil
+	}
+
+	var kept []state.Session
+	for _, sess := range idx.Sessions {
+		if slices.Contains(names, sess.Name) {
+			kept = append(kept, sess)
+		}
+	}
+
+	var missing []string
+	for _, n := range names {
+		if !slices.ContainsFunc(kept, func(s state.Session) bool { return s.Name == n }) {
+			missing = append(missing, n)
+		}
+	}
+	if len(missing) > 0 {
+		return state.Index{}, fmt.Errorf("session not in snapshot: %s", strings.Join(missing, ", "))
+	}
+
+	idx.Sessions = kept
+	return idx, nil
+}
+
+// buildSnapshotRestorer returns the restorer for `snapshot restore`.
+// When snapshotDeps is set (testing), uses the injected restorer.
+// Otherwise, wraps the bootstrap-provided client in onDemandRestorer.
+func buildSnapshotRestorer(cmd *cobra.Command, dir string) IndexRestorer {
+	if snapshotDeps != nil && snapshotDeps.Restorer != nil {
+		return snapshotDeps.Restorer
+	}
+	return newOnDemandRestorer(tmuxClient(cmd), dir)
+}
+
+// completeSnapshotNames completes the first positional with the names of the
+// snapshots on disk.
+func completeSnapshotNames(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	dir, err := state.Dir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	infos, err := state.ListSnapshots(dir, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name, toComplete) {
+			out = append(out, info.Name)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	snapshotRestoreCmd.ValidArgsFunction = completeSnapshotNames
+	snapshotRmCmd.ValidArgsFunction = func(c *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeSnapshotNames(c, nil, toComplete)
+	}
+
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotRmCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,200 @@
+package cmd
+
+// Tests in this file mutate package-level state (bootstrapDeps, snapshotDeps) and MUST NOT use t.Parallel.
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/state"
+)
+
+// recordingIndexRestorer captures the index handed to RestoreIndex and
+// reports every session in it except those named in live as restored.
+type recordingIndexRestorer struct {
+	got  *state.Index
+	live map[string]bool
+	err  error
+}
+
+func (r *recordingIndexRestorer) RestoreIndex(idx state.Index) ([]string, error) {
+	r.got = &idx
+	var restored []string
+	for _, s := range idx.Sessions {
+		if !r.live[s.Name] {
+			restored = append(restored, s.Name)
+		}
+	}
+	return restored, r.err
+}
+
+// seedSnapshotStateDir points PORTAL_STATE_DIR at a temp dir holding a
+// committed sessions.json with the named single-pane sessions and their
+// scrollback, and returns the dir.
+func seedSnapshotStateDir(t *testing.T, sessions ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	idx := state.Index{Version: state.SchemaVersion, SavedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)}
+	for _, name := range sessions {
+		key := state.SanitizePaneKey(name, 0, 0)
+		rel := "scrollback/" + key + ".bin"
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(name), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		idx.Sessions = append(idx.Sessions, state.Session{
+			Name: name,
+			Windows: []state.Window{{Index: 0, Panes: []state.Pane{
+				{Index: 0, CWD: "/tmp", ScrollbackFile: rel},
+			}}},
+		})
+	}
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatalf("EncodeIndex: %v", err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return dir
+}
+
+// runSnapshotCmd executes `portal snapshot <args...>` and returns stdout and
+// the Execute error.
+func runSnapshotCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(append([]string{"snapshot"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestSnapshotCommands(t *testing.T) {
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	t.Run("save then list shows the snapshot", func(t *testing.T) {
+		seedSnapshotStateDir(t, "api", "web")
+
+		out, err := runSnapshotCmd(t, "save", "before-refactor")
+		if err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if want := "Saved snapshot before-refactor (2 sessions, 2 panes)\n"; out != want {
+			t.Errorf("save output = %q, want %q", out, want)
+		}
+
+		out, err = runSnapshotCmd(t, "list")
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if !strings.HasPrefix(out, "before-refactor\t") || !strings.Contains(out, "\t2 sessions\t2 panes\n") {
+			t.Errorf("list output = %q", out)
+		}
+	})
+
+	t.Run("save rejects the reserved auto- prefix", func(t *testing.T) {
+		seedSnapshotStateDir(t, "api")
+
+		_, err := runSnapshotCmd(t, "save", "auto-hourly-x")
+		if err == nil || !strings.Contains(err.Error(), "reserved") {
+			t.Fatalf("save error = %v, want reserved-prefix error", err)
+		}
+	})
+
+	t.Run("save fails cleanly with nothing committed", func(t *testing.T) {
+		t.Setenv("PORTAL_STATE_DIR", t.TempDir())
+
+		_, err := runSnapshotCmd(t, "save", "early")
+		if err == nil || !strings.Contains(err.Error(), "no saved state") {
+			t.Fatalf("save error = %v, want no-saved-state error", err)
+		}
+	})
+
+	t.Run("restore hands the snapshot index to the restorer", func(t *testing.T) {
+		dir := seedSnapshotStateDir(t, "api", "web")
+		if _, err := state.SaveSnapshot(dir, "s1"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+		restorer := &recordingIndexRestorer{live: map[string]bool{"web": true}}
+		snapshotDeps = &SnapshotDeps{Restorer: restorer}
+		t.Cleanup(func() { snapshotDeps = nil })
+
+		out, err := runSnapshotCmd(t, "restore", "s1")
+		if err != nil {
+			t.Fatalf("restore: %v", err)
+		}
+		if restorer.got == nil || len(restorer.got.Sessions) != 2 {
+			t.Fatalf("restorer got %+v, want both sessions", restorer.got)
+		}
+		gotFile := restorer.got.Sessions[0].Windows[0].Panes[0].ScrollbackFile
+		if !strings.HasPrefix(gotFile, "snapshots/s1/scrollback/") {
+			t.Errorf("ScrollbackFile = %q, want it rewritten into the snapshot", gotFile)
+		}
+		if want := "api\trestored\nweb\tskipped\n"; out != want {
+			t.Errorf("restore output = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("restore narrows to named sessions", func(t *testing.T) {
+		dir := seedSnapshotStateDir(t, "api", "web")
+		if _, err := state.SaveSnapshot(dir, "s1"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+		restorer := &recordingIndexRestorer{}
+		snapshotDeps = &SnapshotDeps{Restorer: restorer}
+		t.Cleanup(func() { snapshotDeps = nil })
+
+		if _, err := runSnapshotCmd(t, "restore", "s1", "web"); err != nil {
+			t.Fatalf("restore: %v", err)
+		}
+		if restorer.got == nil || len(restorer.got.Sessions) != 1 || restorer.got.Sessions[0].Name != "web" {
+			t.Fatalf("restorer got %+v, want only web", restorer.got)
+		}
+
+		restorer.got = nil
+		_, err := runSnapshotCmd(t, "restore", "s1", "nope")
+		if err == nil || !strings.Contains(err.Error(), "session not in snapshot: nope") {
+			t.Fatalf("restore error = %v, want session-not-in-snapshot", err)
+		}
+		if restorer.got != nil {
+			t.Error("restorer invoked despite unknown session name")
+		}
+	})
+
+	t.Run("restore of unknown snapshot errors", func(t *testing.T) {
+		seedSnapshotStateDir(t, "api")
+		snapshotDeps = &SnapshotDeps{Restorer: &recordingIndexRestorer{}}
+		t.Cleanup(func() { snapshotDeps = nil })
+
+		_, err := runSnapshotCmd(t, "restore", "missing")
+		if err == nil || !strings.Contains(err.Error(), "snapshot not found") {
+			t.Fatalf("restore error = %v, want snapshot not found", err)
+		}
+	})
+
+	t.Run("rm deletes snapshots and reports missing ones", func(t *testing.T) {
+		dir := seedSnapshotStateDir(t, "api")
+		if _, err := state.SaveSnapshot(dir, "a"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+
+		_, err := runSnapshotCmd(t, "rm", "a", "b")
+		if err == nil || !strings.Contains(err.Error(), "snapshot not found: b") {
+			t.Fatalf("rm error = %v, want not-found for b", err)
+		}
+		if _, err := os.Stat(state.SnapshotDir(dir, "a")); !os.IsNotExist(err) {
+			t.Errorf("snapshot a still present: %v", err)
+		}
+	})
+}
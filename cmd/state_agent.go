@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// agentReconcileInterval is how often the daemon re-resolves tracked agent
+// panes against the live server, dropping closed panes and following renamed
+// sessions. Updates already re-resolve the panes they name, so this only
+// bounds how long a closed pane's record lingers.
+const agentReconcileInterval = 10 * time.Second
+
+// agentScreenInterval is how often the daemon reads the screens of panes
+// running a screen-rule agent. Each read is a capture-pane per watched pane,
+// so it runs at a slower beat than the inbox drain.
+const agentScreenInterval = 2 * time.Second
+
+// paneAddrLister is the slice of *tmux.Client the agent table needs to turn
+// pane ids into addresses.
+type paneAddrLister interface {
+	ListPaneAddrs() (map[string]tmux.PaneAddr, error)
+}
+
+// paneScreenReader is the slice of *tmux.Client the screen scan needs.
+type paneScreenReader interface {
+	paneAddrLister
+	ListPaneCommands() (map[string]tmux.PaneCommand, error)
+	CapturePaneScreen(target string) (string, error)
+}
+
+// agentScreenRules returns the loader for the screen-rule agents: the user's
+// screen rules file (agent-rules.json) plus the shipped defaults it does not
+// override (agent.WithDefaultScreenAgents). The store re-reads the file only
+// when it changes, so an edit takes effect on the next scan without a daemon
+// restart; when the config path cannot be resolved only the defaults apply.
+func agentScreenRules() func() ([]agent.ScreenAgent, error) {
+	path, err := configFilePath("PORTAL_AGENT_RULES_FILE", "agent-rules.json")
+	if err != nil {
+		return func() ([]agent.ScreenAgent, error) { return agent.DefaultScreenAgents(), nil }
+	}
+	store := agent.NewScreenRulesStore(path)
+	return func() ([]agent.ScreenAgent, error) {
+		agents, err := store.Load()
+		return agent.WithDefaultScreenAgents(agents), err
+	}
+}
+
+// screenAgentFor returns the index in screens of the agent running in a pane,
+// or -1. A match on the pane command wins; failing that the pane's foreground
+// argv is read and matched by program or script name, which is how an agent
+// run through its interpreter ("python3") is found. The argv is read afresh
+// every scan: the command and pane pid stay the same from one python3 run to
+// the next, so nothing cheaper tells `python3 foo.py` from `python3 -m aider`.
+func screenAgentFor(screens []agent.ScreenAgent, pane tmux.PaneCommand, resolve func(int) []string) int {
+	if i := slices.IndexFunc(screens, func(s agent.ScreenAgent) bool { return s.Command == pane.Command }); i >= 0 || resolve == nil {
+		return i
+	}
+	argv := resolve(pane.PID)
+	return slices.IndexFunc(screens, func(s agent.ScreenAgent) bool { return s.MatchesArgv(argv) })
+}
+
+// loadAgentTable reads agent.json at daemon startup and keeps only the records
+// whose pane still sits where it was recorded. A missing or unreadable file
+// starts the table empty; the next hook event from each agent refills it.
+func loadAgentTable(lister paneAddrLister, dir string, logger *slog.Logger) agent.Table {
+	table, err := agent.Load(state.AgentJSON(dir))
+	if err != nil {
+		logger.Warn("load agent state failed; starting empty", "error", err)
+	}
+	if len(table) == 0 {
+		return table
+	}
+	addrs, err := lister.ListPaneAddrs()
+	if err != nil {
+		logger.Warn("list pane addresses failed; starting empty", "error", err)
+		return agent.Table{}
+	}
+	if table.DropMoved(addrs) {
+		saveAgentTable(table, dir, logger)
+	}
+	return table
+}
+
+// drainAgentInbox applies every update ingest has posted since the last tick.
+// It runs on every tick, ahead of the capture decision, so a permission
+// prompt shows within a second however busy the saver is. The pane addresses
+// are listed only when there is something to apply; when listing them fails
+// the updates are held, up to agent.InboxCap, and retried next tick.
+func drainAgentInbox(lister paneAddrLister, deps *daemonDeps, logger *slog.Logger) {
+	drained, err := agent.Drain(state.AgentInbox(deps.Dir))
+	if err != nil {
+		logger.Warn("drain agent inbox failed", "error", err)
+	}
+	updates := append(deps.agentBacklog, drained...)
+	deps.agentBacklog = nil
+	if len(updates) == 0 {
+		return
+	}
+	addrs, err := lister.ListPaneAddrs()
+	if err != nil {
+		deps.agentBacklog = updates[max(0, len(updates)-agent.InboxCap):]
+		logger.Warn("list pane addresses failed; agent updates held", "updates", len(deps.agentBacklog), "error", err)
+		return
+	}
+	changed := applyAgentUpdates(deps, updates, addrs, logger)
+	if deps.Agents.Reconcile(addrs) {
+		changed = true
+	}
+	deps.lastAgentReconcile = time.Now()
+	if changed {
+		saveAgentTable(deps.Agents, deps.Dir, logger)
+	}
+}
+
+// applyAgentUpdates folds updates into the table, publishing every pane whose
+// state they change, and reports whether the table changed.
+func applyAgentUpdates(deps *daemonDeps, updates []agent.Update, addrs map[string]tmux.PaneAddr, logger *slog.Logger) bool {
+	if deps.Agents == nil {
+		deps.Agents = agent.Table{}
+	}
+	changed := false
+	for _, u := range updates {
+		before := deps.Agents[u.PaneID].State
+		if deps.Agents.Apply(u, addrs) {
+			changed = true
+			// An untracked pane reads back as a zero record: empty State.
+			r := deps.Agents[u.PaneID]
+			r.PaneID = u.PaneID
+			if r.State != before {
+				if r.State != "" {
+					logger.Debug("agent state", "pane", u.PaneID, "agent", u.Agent, "target", r.Target(), "state", string(r.State), "event", u.Event)
+				}
+				publishAgent(deps, r)
+			}
+		}
+	}
+	return changed
+}
+
+// maybeScanAgentScreens drives the screen-rule adapters. Every
+// agentScreenInterval it captures the screen of each pane whose foreground
+// command a rule set watches, classifies it, and applies the result as if
+// ingest had posted it — but only when the state or its detail moved, so a
+// steady screen costs no write. A pane an agent's hooks report on is left to
+// them. A pane the scan tracks whose command has moved on — the agent has
+// exited — is dropped. A pane is matched by its command name or, failing
+// that, its foreground argv (screenAgentFor). With no rules and nothing
+// tracked by screen it costs no tmux call; a nil AgentScreens (unit tests)
+// disables it.
+func maybeScanAgentScreens(client paneScreenReader, deps *daemonDeps, logger *slog.Logger) {
+	if deps.AgentScreens == nil || time.Since(deps.lastAgentScreenScan) < agentScreenInterval {
+		return
+	}
+	deps.lastAgentScreenScan = time.Now()
+	screens, err := deps.AgentScreens()
+	if err != nil {
+		logger.Warn("screen rules unusable; only the built-in rules apply until the file changes", "error", err)
+	}
+	if len(screens) == 0 && !slices.ContainsFunc(deps.Agents.Records(), isScreenRecord) {
+		return
+	}
+	commands, err := client.ListPaneCommands()
+	if err != nil {
+		logger.Warn("list pane commands failed", "error", err)
+		return
+	}
+	var resolve func(int) []string
+	if deps.PaneArgv != nil {
+		resolve = deps.PaneArgv()
+	}
+	now := time.Now()
+	var updates []agent.Update
+	for id, pane := range commands {
+		r, tracked := deps.Agents[id]
+		if tracked && !isScreenRecord(r) {
+			continue
+		}
+		i := screenAgentFor(screens, pane, resolve)
+		if i < 0 {
+			if tracked {
+				updates = append(updates, agent.Update{PaneID: id, Agent: r.Agent, Event: agent.ScreenEvent, Ended: true, At: now})
+			}
+			continue
+		}
+		screen, err := client.CapturePaneScreen(id)
+		if err != nil {
+			logger.Warn("capture agent screen failed", "pane", id, "agent", screens[i].Agent, "error", err)
+			continue
+		}
+		event, err := screens[i].Parse(strings.NewReader(screen))
+		if err != nil {
+			logger.Warn("classify agent screen failed", "pane", id, "agent", screens[i].Agent, "error", err)
+			continue
+		}
+		if tracked && r.Agent == screens[i].Agent && r.State == event.State && r.Detail == event.Detail {
+			continue
+		}
+		updates = append(updates, agent.NewUpdate(id, screens[i].Agent, event, now))
+	}
+	if len(updates) == 0 {
+		return
+	}
+	addrs, err := client.ListPaneAddrs()
+	if err != nil {
+		logger.Warn("list pane addresses failed; agent screen updates dropped", "updates", len(updates), "error", err)
+		return
+	}
+	if applyAgentUpdates(deps, updates, addrs, logger) {
+		saveAgentTable(deps.Agents, deps.Dir, logger)
+	}
+}
+
+// isScreenRecord reports whether the screen scan, rather than an agent's
+// hooks, is tracking r.
+func isScreenRecord(r agent.Record) bool {
+	return r.Event == agent.ScreenEvent
+}
+
+// maybeReconcileAgents is the throttled gate that re-resolves the tracked
+// panes between updates, in the cleanup gates' shape. An empty table has
+// nothing to reconcile and costs no tmux call.
+func maybeReconcileAgents(lister paneAddrLister, deps *daemonDeps, logger *slog.Logger) {
+	if len(deps.Agents) == 0 {
+		return
+	}
+	if time.Since(deps.lastAgentReconcile) < agentReconcileInterval {
+		return
+	}
+	addrs, err := lister.ListPaneAddrs()
+	if err != nil {
+		logger.Warn("list pane addresses failed", "error", err)
+	} else if deps.Agents.Reconcile(addrs) {
+		saveAgentTable(deps.Agents, deps.Dir, logger)
+	}
+	deps.lastAgentReconcile = time.Now()
+}
+
+// saveAgentTable persists the table to agent.json, logging a failure: the
+// in-memory table stays authoritative and the next change retries the write.
+func saveAgentTable(table agent.Table, dir string, logger *slog.Logger) {
+	if err := table.Save(state.AgentJSON(dir)); err != nil {
+		logger.Warn("save agent state failed", "error", err)
+	}
+}
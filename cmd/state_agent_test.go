@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// fakePaneAddrs serves a canned address map and counts the calls.
+type fakePaneAddrs struct {
+	addrs map[string]tmux.PaneAddr
+	err   error
+	calls int
+}
+
+func (f *fakePaneAddrs) ListPaneAddrs() (map[string]tmux.PaneAddr, error) {
+	f.calls++
+	return f.addrs, f.err
+}
+
+func TestDrainAgentInbox(t *testing.T) {
+	t.Run("applies posted updates and persists agent.json", func(t *testing.T) {
+		dir := t.TempDir()
+		at := time.Now().UTC()
+		for _, u := range []agent.Update{
+			{PaneID: "%1", State: agent.Working, Event: "PreToolUse", At: at},
+			{PaneID: "%1", State: agent.Waiting, Event: "Notification", At: at.Add(time.Millisecond)},
+			{PaneID: "%9", State: agent.Working, Event: "PreToolUse", At: at},
+		} {
+			if err := agent.Post(state.AgentInbox(dir), u); err != nil {
+				t.Fatal(err)
+			}
+		}
+		lister := &fakePaneAddrs{addrs: map[string]tmux.PaneAddr{"%1": {Session: "api", Window: 0, Pane: 1}}}
+		deps := &daemonDeps{Dir: dir}
+
+		drainAgentInbox(lister, deps, discardDaemonLogger())
+
+		if r := deps.Agents["%1"]; r.State != agent.Waiting || r.Target() != "api:0.1" {
+			t.Errorf("record = %+v", r)
+		}
+		if _, ok := deps.Agents["%9"]; ok {
+			t.Error("update for a closed pane was tracked")
+		}
+		saved, err := agent.Load(state.AgentJSON(dir))
+		if err != nil || saved["%1"].State != agent.Waiting {
+			t.Errorf("agent.json = %v, %v", saved, err)
+		}
+	})
+
+	t.Run("updates are held across a failed pane listing", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := agent.Post(state.AgentInbox(dir), agent.Update{PaneID: "%1", State: agent.Waiting, Event: "Notification", At: time.Now().UTC()}); err != nil {
+			t.Fatal(err)
+		}
+		lister := &fakePaneAddrs{err: errors.New("server busy")}
+		deps := &daemonDeps{Dir: dir}
+
+		drainAgentInbox(lister, deps, discardDaemonLogger())
+		if len(deps.Agents) != 0 || len(deps.agentBacklog) != 1 {
+			t.Fatalf("after failure: agents %v, backlog %v", deps.Agents, deps.agentBacklog)
+		}
+
+		lister.err = nil
+		lister.addrs = map[string]tmux.PaneAddr{"%1": {Session: "api"}}
+		drainAgentInbox(lister, deps, discardDaemonLogger())
+		if deps.Agents["%1"].State != agent.Waiting || len(deps.agentBacklog) != 0 {
+			t.Errorf("after retry: record %+v, backlog %v", deps.Agents["%1"], deps.agentBacklog)
+		}
+	})
+
+	t.Run("an empty inbox costs no tmux call", func(t *testing.T) {
+		lister := &fakePaneAddrs{}
+		drainAgentInbox(lister, &daemonDeps{Dir: t.TempDir()}, discardDaemonLogger())
+		if lister.calls != 0 {
+			t.Errorf("ListPaneAddrs called %d times", lister.calls)
+		}
+	})
+}
+
+// fakePaneScreens serves canned pane commands and screens on top of
+// fakePaneAddrs, recording which panes were captured.
+type fakePaneScreens struct {
+	fakePaneAddrs
+	commands map[string]tmux.PaneCommand
+	screens  map[string]string
+	captured []string
+}
+
+func (f *fakePaneScreens) ListPaneCommands() (map[string]tmux.PaneCommand, error) {
+	return f.commands, nil
+}
+
+// paneCommands builds ListPaneCommands output from pane commands, giving each
+// pane a distinct pid.
+func paneCommands(commands map[string]string) map[string]tmux.PaneCommand {
+	out := make(map[string]tmux.PaneCommand, len(commands))
+	for id, command := range commands {
+		pid, _ := strconv.Atoi(strings.TrimPrefix(id, "%"))
+		out[id] = tmux.PaneCommand{Command: command, PID: 1000 + pid}
+	}
+	return out
+}
+
+func (f *fakePaneScreens) CapturePaneScreen(target string) (string, error) {
+	f.captured = append(f.captured, target)
+	return f.screens[target], nil
+}
+
+func TestMaybeScanAgentScreens(t *testing.T) {
+	rules, err := agent.ParseScreenRules([]byte(`{"agents":[{"agent":"aider","command":"aider","default":"working",
+		"rules":[{"match":"\\(Y\\)es/\\(N\\)o","state":"waiting"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs := map[string]tmux.PaneAddr{"%1": {Session: "api"}, "%2": {Session: "web"}, "%3": {Session: "db"}}
+	scanDeps := func(t *testing.T, table agent.Table) *daemonDeps {
+		return &daemonDeps{Dir: t.TempDir(), Agents: table, AgentScreens: func() ([]agent.ScreenAgent, error) { return rules, nil }}
+	}
+
+	t.Run("classifies watched panes and leaves hooked ones to their hooks", func(t *testing.T) {
+		client := &fakePaneScreens{
+			fakePaneAddrs: fakePaneAddrs{addrs: addrs},
+			commands:      paneCommands(map[string]string{"%1": "aider", "%2": "aider", "%3": "zsh"}),
+			screens:       map[string]string{"%1": "Run shell command? (Y)es/(N)o [Yes]:"},
+		}
+		deps := scanDeps(t, agent.Table{"%2": {PaneID: "%2", Agent: "claude", Session: "web", State: agent.Idle, Event: "Stop"}})
+
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+
+		if r := deps.Agents["%1"]; r.Agent != "aider" || r.State != agent.Waiting || r.Detail != "Run shell command? (Y)es/(N)o [Yes]:" {
+			t.Errorf("%%1 = %+v, want aider waiting", r)
+		}
+		if r := deps.Agents["%2"]; r.Agent != "claude" || r.State != agent.Idle {
+			t.Errorf("%%2 = %+v, want the hooked record untouched", r)
+		}
+		if len(client.captured) != 1 || client.captured[0] != "%1" {
+			t.Errorf("captured %v, want only %%1", client.captured)
+		}
+		if saved, _ := agent.Load(state.AgentJSON(deps.Dir)); saved["%1"].State != agent.Waiting {
+			t.Errorf("agent.json = %v", saved)
+		}
+	})
+
+	t.Run("a steady screen writes nothing and an exited agent is dropped", func(t *testing.T) {
+		client := &fakePaneScreens{
+			fakePaneAddrs: fakePaneAddrs{addrs: addrs},
+			commands:      paneCommands(map[string]string{"%1": "aider", "%3": "zsh"}),
+			screens:       map[string]string{"%1": "thinking"},
+		}
+		deps := scanDeps(t, agent.Table{
+			"%1": {PaneID: "%1", Agent: "aider", Session: "api", State: agent.Working, Event: agent.ScreenEvent},
+			"%3": {PaneID: "%3", Agent: "aider", Session: "db", State: agent.Idle, Event: agent.ScreenEvent},
+		})
+
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+
+		if _, ok := deps.Agents["%3"]; ok {
+			t.Error("pane whose agent exited is still tracked")
+		}
+		if r := deps.Agents["%1"]; r.State != agent.Working {
+			t.Errorf("%%1 = %+v, want still working", r)
+		}
+		if client.calls != 1 {
+			t.Errorf("ListPaneAddrs called %d times, want once for the exit", client.calls)
+		}
+
+		client.calls = 0
+		deps.lastAgentScreenScan = time.Time{}
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+		if client.calls != 0 {
+			t.Error("an unchanged screen was applied again")
+		}
+	})
+
+	t.Run("an agent run through its interpreter is found by its argv", func(t *testing.T) {
+		client := &fakePaneScreens{
+			fakePaneAddrs: fakePaneAddrs{addrs: addrs},
+			commands:      paneCommands(map[string]string{"%1": "python3", "%3": "zsh"}),
+			screens:       map[string]string{"%1": "Run shell command? (Y)es/(N)o [Yes]:"},
+		}
+		deps := scanDeps(t, nil)
+		python := []string{"python3", "/home/u/.local/bin/aider", "--model", "sonnet"}
+		deps.PaneArgv = func() func(int) []string {
+			return func(pid int) []string {
+				if pid == 1001 {
+					return python
+				}
+				return []string{"-zsh"}
+			}
+		}
+
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+
+		if r := deps.Agents["%1"]; r.Agent != "aider" || r.State != agent.Waiting {
+			t.Errorf("%%1 = %+v, want aider waiting", r)
+		}
+		if _, ok := deps.Agents["%3"]; ok {
+			t.Error("the shell pane is tracked")
+		}
+
+		python = []string{"python3", "foo.py"}
+		deps.lastAgentScreenScan = time.Time{}
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+		if _, ok := deps.Agents["%1"]; ok {
+			t.Error("%1 still tracked after aider gave way to another python3 run")
+		}
+
+		python = []string{"python3", "-m", "aider"}
+		deps.lastAgentScreenScan = time.Time{}
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+		if r := deps.Agents["%1"]; r.Agent != "aider" {
+			t.Errorf("%%1 = %+v, want aider found again under the same python3", r)
+		}
+	})
+
+	t.Run("no rules and nothing tracked by screen costs no tmux call", func(t *testing.T) {
+		client := &fakePaneScreens{commands: paneCommands(map[string]string{"%1": "aider"})}
+		deps := &daemonDeps{Dir: t.TempDir(), AgentScreens: func() ([]agent.ScreenAgent, error) { return nil, nil }}
+
+		maybeScanAgentScreens(client, deps, discardDaemonLogger())
+
+		if len(client.captured) != 0 || len(deps.Agents) != 0 {
+			t.Errorf("captured %v, table %v; want nothing", client.captured, deps.Agents)
+		}
+	})
+}
+
+func TestMaybeReconcileAgents(t *testing.T) {
+	dir := t.TempDir()
+	deps := &daemonDeps{Dir: dir, Agents: agent.Table{
+		"%1": {PaneID: "%1", Session: "api", State: agent.Idle},
+		"%2": {PaneID: "%2", Session: "web", State: agent.Working},
+	}}
+	lister := &fakePaneAddrs{addrs: map[string]tmux.PaneAddr{"%1": {Session: "api"}}}
+
+	deps.lastAgentReconcile = time.Now()
+	maybeReconcileAgents(lister, deps, discardDaemonLogger())
+	if lister.calls != 0 {
+		t.Fatal("reconcile ran inside its throttle interval")
+	}
+
+	deps.lastAgentReconcile = time.Now().Add(-agentReconcileInterval)
+	maybeReconcileAgents(lister, deps, discardDaemonLogger())
+	if _, ok := deps.Agents["%2"]; ok || len(deps.Agents) != 1 {
+		t.Errorf("table = %v, want the closed pane dropped", deps.Agents)
+	}
+	if _, err := os.Stat(state.AgentJSON(dir)); err != nil {
+		t.Errorf("agent.json not written: %v", err)
+	}
+}
+
+func TestLoadAgentTable(t *testing.T) {
+	dir := t.TempDir()
+	table := agent.Table{
+		"%1": {PaneID: "%1", Session: "api", State: agent.Waiting},
+		"%2": {PaneID: "%2", Session: "web", State: agent.Working},
+	}
+	if err := table.Save(state.AgentJSON(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("keeps only panes still at their recorded address", func(t *testing.T) {
+		// %2 now names a pane in another session: a reused id after a restart.
+		lister := &fakePaneAddrs{addrs: map[string]tmux.PaneAddr{"%1": {Session: "api"}, "%2": {Session: "db"}}}
+		got := loadAgentTable(lister, dir, discardDaemonLogger())
+		if len(got) != 1 || got["%1"].State != agent.Waiting {
+			t.Errorf("table = %v, want only %%1", got)
+		}
+	})
+
+	t.Run("an unlistable server starts empty", func(t *testing.T) {
+		if err := table.Save(state.AgentJSON(dir)); err != nil {
+			t.Fatal(err)
+		}
+		got := loadAgentTable(&fakePaneAddrs{err: errors.New("no server")}, dir, discardDaemonLogger())
+		if len(got) != 0 {
+			t.Errorf("table = %v, want empty", got)
+		}
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/leeovery/portal/internal/control"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
@@ -22,6 +24,12 @@ import (
 // errors.Unwrap on the wrapped failure returned from failCommitNow.
 var errCommitNowFailed = errors.New("commit-now failed")
 
+// commitNowSaveTimeout bounds the save-now request commit-now makes of the
+// daemon. commit-now runs inside a tmux hook, so it waits only briefly; a
+// request that outlasts this has reached the daemon, which carries the save
+// through regardless, and the save.requested fallback covers it too.
+const commitNowSaveTimeout = 2 * time.Second
+
 // IsSilentExitError reports whether err is one of the cmd-package sentinels
 // whose stderr emission must be suppressed at the top-level error handler.
 // errCommitNowFailed (state commit-now, hook subprocess context) and
@@ -77,14 +85,46 @@ type CommitNowDeps struct {
 	// calls state.IsRestoringSet against a fresh production tmux client.
 	IsRestoring func() (bool, error)
 
+	// RestorePolicy loads the restore policy in force so never-policy
+	// sessions are dropped from the commit, as on a daemon tick. Defaults to
+	// loadRestorePolicy.
+	RestorePolicy func() state.RestorePolicyFunc
+
+	// SaveNow asks the running daemon for an immediate save over its control
+	// socket (see internal/control). Used on the @portal-restoring
+	// short-circuit and the failure paths so the daemon commits without
+	// waiting for the 30s gap rule. Defaults to daemonSaveNow.
+	SaveNow func(dir string) error
+
 	// TouchSaveRequested creates-or-truncates save.requested under dir and
-	// bumps its mtime, mirroring the in-line touch state notify performs.
-	// Used on the @portal-restoring short-circuit so the daemon's first
-	// post-restoration tick commits without waiting for the 30s gap rule.
-	// Defaults to state.TouchSaveRequested.
+	// bumps its mtime, mirroring the in-line touch state notify performs. It
+	// is the fallback for a SaveNow the daemon could not take — not running,
+	// mid-restore, or busy — so its next tick commits. Defaults to
+	// state.TouchSaveRequested.
 	TouchSaveRequested func(dir string) error
 }
 
+// requestSave asks the daemon for a save through SaveNow and falls back to
+// touching save.requested when that fails. Only a failed fallback is an
+// error.
+func (d *CommitNowDeps) requestSave(dir string) error {
+	if err := d.SaveNow(dir); err == nil {
+		return nil
+	}
+	return d.TouchSaveRequested(dir)
+}
+
+// daemonSaveNow sends save-now to the daemon serving the control socket under
+// dir. A daemon that is down fails the dial at once.
+func daemonSaveNow(dir string) error {
+	client, err := control.Dial(state.DaemonSocket(dir), commitNowSaveTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+	return client.Call(control.MethodSaveNow, nil, nil)
+}
+
 // resolveCommitNowDeps returns a fully-populated *CommitNowDeps for one
 // commit-now invocation. Unset fields in the package-level commitNowDeps fall
 // through to the production implementation independently — same per-field
@@ -98,7 +138,9 @@ func resolveCommitNowDeps() *CommitNowDeps {
 		Commit:             state.Commit,
 		NewClient:          func() state.CaptureClient { return tmux.DefaultClient() },
 		IsRestoring:        func() (bool, error) { return state.IsRestoringSet(tmux.DefaultClient()) },
+		SaveNow:            daemonSaveNow,
 		TouchSaveRequested: state.TouchSaveRequested,
+		RestorePolicy:      loadRestorePolicy,
 	}
 
 	if commitNowDeps == nil {
@@ -119,9 +161,15 @@ func resolveCommitNowDeps() *CommitNowDeps {
 	if commitNowDeps.IsRestoring != nil {
 		deps.IsRestoring = commitNowDeps.IsRestoring
 	}
+	if commitNowDeps.SaveNow != nil {
+		deps.SaveNow = commitNowDeps.SaveNow
+	}
 	if commitNowDeps.TouchSaveRequested != nil {
 		deps.TouchSaveRequested = commitNowDeps.TouchSaveRequested
 	}
+	if commitNowDeps.RestorePolicy != nil {
+		deps.RestorePolicy = commitNowDeps.RestorePolicy
+	}
 	return deps
 }
 
@@ -181,9 +229,11 @@ var stateCommitNowCmd = &cobra.Command{
 		// guard: when bootstrap step 6 Restore (or a step-5 saver
 		// version-upgrade firing session-closed mid-restore) is in progress,
 		// any structural commit would write a partial skeleton view. Skip
-		// every primitive, touch save.requested so the daemon's first
+		// every primitive, request a save so the daemon's first
 		// post-restoration tick commits, and exit 0 — the skip is a
-		// deliberate completion, not an error.
+		// deliberate completion, not an error. A daemon that is up refuses
+		// save-now mid-restore, so in practice this lands on the
+		// save.requested fallback.
 		//
 		// A query failure on @portal-restoring is treated symmetrically to
 		// (true, nil): if we cannot prove the marker is clear, presume it
@@ -197,11 +247,11 @@ var stateCommitNowCmd = &cobra.Command{
 		switch {
 		case err != nil:
 			logger.Warn("isRestoring query failed; presuming @portal-restoring set to protect in-flight restore", "error", err)
-			touchAfterShortCircuit(logger, dir, deps.TouchSaveRequested)
+			touchAfterShortCircuit(logger, dir, deps.requestSave)
 			return nil
 		case restoring:
 			logger.Info("commit-now skipped: @portal-restoring set")
-			touchAfterShortCircuit(logger, dir, deps.TouchSaveRequested)
+			touchAfterShortCircuit(logger, dir, deps.requestSave)
 			return nil
 		}
 
@@ -210,20 +260,33 @@ var stateCommitNowCmd = &cobra.Command{
 		client := deps.NewClient()
 		idx, err := deps.CaptureStructure(client, nil, &prev, logger)
 		if err != nil {
-			return failCommitNow(logger, dir, deps.TouchSaveRequested, "capture structure", err)
+			return failCommitNow(logger, dir, deps.requestSave, "capture structure", err)
 		}
+		// A killed session leaves sessions.json as a dormant entry rather than
+		// vanishing, exactly as on a daemon tick. The sessions this carry moves
+		// to dormant are the ones this session-closed was fired for — unless a
+		// daemon tick already committed that transition, in which case prev
+		// has them dormant and the daemon fired their hooks.
+		policy := deps.RestorePolicy()
+		stopped := state.CarryDormant(&idx, &prev, time.Now(), policy)
+		state.DropNeverSaved(&idx, policy)
 
 		if err := deps.Commit(dir, idx, false, logger); err != nil {
-			return failCommitNow(logger, dir, deps.TouchSaveRequested, "commit sessions.json", err)
+			return failCommitNow(logger, dir, deps.requestSave, "commit sessions.json", err)
 		}
 
+		// on-kill fires only once the commit has landed: the next writer reads
+		// this one's index as prev, so each kill is reported exactly once.
+		store, _ := loadHookStore()
+		fireKillHooks(store, stopped)
+
 		return nil
 	},
 }
 
-// touchAfterShortCircuit performs the best-effort save.requested touch shared
-// by both @portal-restoring short-circuit branches — (true, nil) and the
-// query-error "presume set" branch. A touch failure is logged at WARN under
+// touchAfterShortCircuit performs the best-effort save request shared by both
+// @portal-restoring short-circuit branches — (true, nil) and the query-error
+// "presume set" branch — through CommitNowDeps.requestSave. A touch failure is logged at WARN under
 // the daemon component and swallowed; the short-circuit's exit-0 status
 // dominates per spec § save.requested Touch Failure Handling.
 func touchAfterShortCircuit(logger *slog.Logger, dir string, touch func(string) error) {
@@ -241,10 +304,11 @@ func touchAfterShortCircuit(logger *slog.Logger, dir string, touch func(string)
 //     stage argument is a fixed terse phrase at each call site ("capture
 //     structure" / "commit sessions.json"); it is used both as the log
 //     message and as the stage descriptor folded into the wrapped error.
-//  2. Best-effort touch save.requested so the daemon's next scheduled tick
-//     (within 1s when it is alive) commits — bounded fallback recovery for the
-//     resurrection window. Touch errors are logged at WARN and never
-//     propagated; the original failure dominates.
+//  2. Best-effort save request (CommitNowDeps.requestSave): save-now over the
+//     control socket, else touch save.requested so the daemon's next
+//     scheduled tick (within 1s when it is alive) commits — bounded fallback
+//     recovery for the resurrection window. Touch errors are logged at WARN
+//     and never propagated; the original failure dominates.
 //  3. Return an error that wraps errCommitNowFailed via fmt.Errorf("%w: %s:
 //     %v", ...). errors.Is(err, errCommitNowFailed) drives main.go's silent-
 //     exit suppression (see IsSilentExitError). The cause is preserved as
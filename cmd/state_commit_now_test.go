@@ -12,6 +12,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/log"
 	"github.com/leeovery/portal/internal/logtest"
 	"github.com/leeovery/portal/internal/state"
@@ -96,6 +98,11 @@ type commitNowFixture struct {
 	touchCalls     int
 	touchDirs      []string
 	touchErr       error
+
+	// daemonUp makes SaveNow succeed; otherwise it fails as a daemon that is
+	// not running would.
+	daemonUp     bool
+	saveNowCalls int
 }
 
 type commitInvocation struct {
@@ -131,6 +138,13 @@ func installCommitNowDeps(t *testing.T, f *commitNowFixture) {
 			f.restoringCalls++
 			return f.restoring, f.restoringErr
 		},
+		SaveNow: func(string) error {
+			f.saveNowCalls++
+			if f.daemonUp {
+				return nil
+			}
+			return control.ErrUnreachable
+		},
 		TouchSaveRequested: func(dir string) error {
 			f.touchCalls++
 			f.touchDirs = append(f.touchDirs, dir)
@@ -384,7 +398,7 @@ func TestStateCommitNow_OmitsUnderscorePrefixedSessions(t *testing.T) {
 	client := &fakeCaptureClient{
 		sessions: []string{"work", "_portal-saver"},
 		rows: strings.Join([]string{
-			"work|||0|||main|||tiled|||0|||1|||0|||/home/u|||1|||zsh|||",
+			"work|||0|||main|||tiled|||0|||1|||0|||/home/u|||1|||zsh|||||||||",
 		}, "\n"),
 		env: map[string]string{"work": "", "_portal-saver": ""},
 	}
@@ -638,6 +652,63 @@ func TestStateCommitNow_ShortCircuits_TouchesSaveRequested(t *testing.T) {
 	}
 }
 
+// A running daemon takes the save request over its control socket, so the
+// save.requested marker is left alone.
+func TestStateCommitNow_FailureAsksRunningDaemonToSave(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+
+	f := &commitNowFixture{
+		client:     &fakeCaptureClient{sessions: nil},
+		captureErr: errors.New("boom"),
+		daemonUp:   true,
+	}
+	installCommitNowDeps(t, f)
+
+	if _, _, err := runStateCommitNow(t); !errors.Is(err, errCommitNowFailed) {
+		t.Fatalf("err = %v, want errCommitNowFailed", err)
+	}
+
+	if f.saveNowCalls != 1 || f.touchCalls != 0 {
+		t.Errorf("SaveNow calls = %d, touch calls = %d; want 1 and 0", f.saveNowCalls, f.touchCalls)
+	}
+}
+
+func TestDaemonSaveNow(t *testing.T) {
+	t.Run("sends save-now on the state dir's control socket", func(t *testing.T) {
+		// A short directory keeps the socket path inside the sun_path limit.
+		dir, err := os.MkdirTemp("", "ctl")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		ln, err := control.Listen(state.DaemonSocket(dir))
+		if err != nil {
+			t.Fatalf("Listen: %v", err)
+		}
+		methods := make(chan string, 1)
+		srv := control.NewServer(ln, control.HandlerFunc(func(method string, _ json.RawMessage) (any, error) {
+			methods <- method
+			return control.SaveResult{}, nil
+		}), discardDaemonLogger())
+		go func() { _ = srv.Serve() }()
+		t.Cleanup(func() { _ = srv.Close() })
+
+		if err := daemonSaveNow(dir); err != nil {
+			t.Fatalf("daemonSaveNow: %v", err)
+		}
+		if got := <-methods; got != control.MethodSaveNow {
+			t.Errorf("method = %q, want %q", got, control.MethodSaveNow)
+		}
+	})
+
+	t.Run("no daemon is ErrUnreachable", func(t *testing.T) {
+		if err := daemonSaveNow(t.TempDir()); !errors.Is(err, control.ErrUnreachable) {
+			t.Errorf("err = %v, want ErrUnreachable", err)
+		}
+	})
+}
+
 // 13. short-circuit emits an INFO-level structured log entry.
 func TestStateCommitNow_ShortCircuits_LogsInfoSkipEvent(t *testing.T) {
 	dir := t.TempDir()
@@ -1269,3 +1340,36 @@ func TestStateCommitNow_IsRegisteredAsStateSubcommand(t *testing.T) {
 		t.Fatal("commit-now must be registered as a subcommand of state")
 	}
 }
+
+// on-kill fires for the session this session-closed removed, from its saved
+// pane keys, and not for the session still running.
+func TestStateCommitNow_FiresOnKillHooksForStoppedSessions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	fired := recordHookProcesses(t)
+	writeHooksFile(t, map[string]map[string]string{
+		"id-gone:0.0": {hooks.EventKill: "echo gone"},
+		"id-live:0.0": {hooks.EventKill: "echo live"},
+	})
+
+	session := func(name, id string) state.Session {
+		return state.Session{Name: name, PortalID: id, Environment: map[string]string{}, Windows: []state.Window{
+			{Index: 0, Active: true, Panes: []state.Pane{{Index: 0, CWD: "/tmp", Active: true}}},
+		}}
+	}
+	f := &commitNowFixture{
+		client:          &fakeCaptureClient{},
+		readIdxOverride: true,
+		readIdxReturn:   state.Index{Version: state.SchemaVersion, Sessions: []state.Session{session("gone", "id-gone"), session("live", "id-live")}},
+		captureReturn:   state.Index{Version: state.SchemaVersion, Sessions: []state.Session{session("live", "id-live")}},
+	}
+	installCommitNowDeps(t, f)
+
+	if _, _, err := runStateCommitNow(t); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*fired) != 1 || (*fired)[0].Command != "echo gone" {
+		t.Errorf("fired = %+v, want only the killed session's on-kill hook", *fired)
+	}
+}
@@ -41,4 +41,14 @@ var (
 	// apart from the daemon's lifecycle lines. The per-pane WARNs stay on
 	// daemonLogger (lowest-churn; preserves their existing component).
 	captureLogger = log.For("capture")
+	// snapshotLogger is the component-bound logger for named and rolling
+	// snapshots — the `snapshot` CLI verbs and the daemon's auto-snapshot gate
+	// both log here so `grep "snapshot:"` shows every save, restore and prune.
+	snapshotLogger = log.For("snapshot")
+	// reapLogger is the component-bound logger for the daemon's idle session
+	// reaper, so `grep "reap:"` lists every session it killed and why.
+	reapLogger = log.For("reap")
+	// agentLogger is the component-bound logger for coding-agent tracking:
+	// `portal agent ingest` and the daemon's drain of the agent inbox.
+	agentLogger = log.For("agent")
 )
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// controlCallTimeout bounds how long a control request waits for the tick
+// loop to take it and answer. A save-now runs a full capture, so it is
+// generous; a loop wedged past it answers "daemon busy" rather than hanging
+// the client.
+const controlCallTimeout = 30 * time.Second
+
+// controlCall is one control request handed to the tick loop, with the
+// channel its answer goes back on.
+type controlCall struct {
+	method string
+	params json.RawMessage
+	reply  chan controlReply
+}
+
+type controlReply struct {
+	result any
+	err    error
+}
+
+// daemonControl is the control.Handler the daemon serves. Connections are
+// served on their own goroutines, but daemonDeps belongs to the tick loop, so
+// every request is passed through calls to be answered between ticks —
+// which also makes a ping proof that the loop itself is turning.
+type daemonControl struct {
+	calls chan<- controlCall
+	done  <-chan struct{}
+}
+
+func (d daemonControl) Handle(method string, params json.RawMessage) (any, error) {
+	call := controlCall{method: method, params: params, reply: make(chan controlReply, 1)}
+	timeout := time.NewTimer(controlCallTimeout)
+	defer timeout.Stop()
+	select {
+	case d.calls <- call:
+	case <-d.done:
+		return nil, errors.New("daemon shutting down")
+	case <-timeout.C:
+		return nil, errors.New("daemon busy")
+	}
+	select {
+	case r := <-call.reply:
+		return r.result, r.err
+	case <-timeout.C:
+		return nil, errors.New("daemon busy")
+	}
+}
+
+// startControlServer opens the control socket and serves it for the daemon's
+// lifetime. The socket is an addition to the file markers, not a replacement,
+// so failing to open it is a WARN and the daemon runs on without it.
+func startControlServer(ctx context.Context, deps *daemonDeps) *control.Server {
+	path := state.DaemonSocket(deps.Dir)
+	ln, err := control.Listen(path)
+	if err != nil {
+		deps.Logger.Warn("control socket unavailable", "path", path, "error", err)
+		return nil
+	}
+	calls := make(chan controlCall)
+	srv := control.NewServer(ln, daemonControl{calls: calls, done: ctx.Done()}, deps.Logger)
+	deps.controlCalls = calls
+	deps.Control = srv
+	go func() {
+		if err := srv.Serve(); err != nil {
+			deps.Logger.Warn("control socket stopped", "error", err)
+		}
+	}()
+	deps.Logger.Info("control socket listening", "path", path)
+	return srv
+}
+
+// publish sends an event to control-socket subscribers. Without a socket it
+// does nothing.
+func (d *daemonDeps) publish(typ string, data any) {
+	if d.Control != nil {
+		d.Control.Publish(typ, data)
+	}
+}
+
+// handleControlCall answers one control request on the tick loop.
+func handleControlCall(ctx context.Context, deps *daemonDeps, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case control.MethodPing:
+		return control.PingResult{Protocol: control.Version, Version: deps.Version, PID: os.Getpid()}, nil
+	case control.MethodStatus:
+		return controlStatus(deps), nil
+	case control.MethodSaveNow:
+		return controlSaveNow(ctx, deps)
+	case control.MethodSessions:
+		return controlSessions(deps), nil
+	}
+	return nil, &control.Error{Code: control.ErrCodeMethod, Message: "unknown method " + method}
+}
+
+// controlStatus reports the daemon's view of itself from the index it last
+// committed. A failed @portal-restoring read reports not restoring: status
+// is informational, and the flag is the daemon's to act on, not the caller's.
+func controlStatus(deps *daemonDeps) control.StatusResult {
+	st := control.StatusResult{
+		PID:        os.Getpid(),
+		Version:    deps.Version,
+		StartedAt:  deps.StartedAt,
+		LastSaveAt: deps.LastSaveAt,
+		Agents:     map[string]int{},
+		Reaper:     deps.Reap != nil && deps.Reap().IdleAfter > 0,
+	}
+	if deps.Client != nil {
+		st.Restoring, _ = state.IsRestoringSet(deps.Client)
+	}
+	if deps.PrevIndex != nil {
+		for _, s := range deps.PrevIndex.Sessions {
+			if s.Dormant {
+				st.Dormant++
+				continue
+			}
+			st.Sessions++
+			for _, w := range s.Windows {
+				st.Panes += len(w.Panes)
+			}
+		}
+	}
+	for _, r := range deps.Agents {
+		st.Agents[string(r.State)]++
+	}
+	return st
+}
+
+// controlSaveNow runs a capture-and-commit immediately, as a tick with
+// save.requested set would: on-save hooks fire, subscribers hear of it, and
+// the dirty flag is cleared. Like a tick it refuses while a restore owns
+// sessions.json.
+func controlSaveNow(ctx context.Context, deps *daemonDeps) (any, error) {
+	restoring, err := state.IsRestoringSet(deps.Client)
+	if err != nil {
+		return nil, err
+	}
+	if restoring {
+		return nil, &control.Error{Code: control.ErrCodeRestoring, Message: "a restore is in progress; try again once it finishes"}
+	}
+	if err := captureAndCommit(ctx, deps); err != nil {
+		return nil, err
+	}
+	deps.LastSaveAt = time.Now()
+	fireSaveHooks(deps)
+	publishSaved(deps)
+	if err := os.Remove(state.SaveRequested(deps.Dir)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		deps.Logger.Warn("remove save.requested failed", "error", err)
+	}
+	return savedResult(deps), nil
+}
+
+// publishSaved announces a commit to subscribers.
+func publishSaved(deps *daemonDeps) {
+	deps.publish(control.EventSaved, savedResult(deps))
+}
+
+func savedResult(deps *daemonDeps) control.SaveResult {
+	res := control.SaveResult{SavedAt: deps.LastSaveAt.UTC()}
+	if deps.PrevIndex != nil {
+		for _, s := range deps.PrevIndex.Sessions {
+			if !s.Dormant {
+				res.Sessions++
+			}
+		}
+	}
+	return res
+}
+
+// controlSessions lists the sessions of the last committed index, dormant
+// ones included, with the agent state of each tracked pane.
+func controlSessions(deps *daemonDeps) []control.SessionInfo {
+	if deps.PrevIndex == nil {
+		return []control.SessionInfo{}
+	}
+	agents := map[string][]control.AgentPane{}
+	for _, r := range deps.Agents.Records() {
+		agents[r.Session] = append(agents[r.Session], control.AgentPane{Window: r.Window, Pane: r.Pane, State: string(r.State), Since: r.Since})
+	}
+	infos := make([]control.SessionInfo, 0, len(deps.PrevIndex.Sessions))
+	for _, s := range deps.PrevIndex.Sessions {
+		info := control.SessionInfo{Name: s.Name, Dormant: s.Dormant, Windows: len(s.Windows), SavedAt: deps.PrevIndex.SavedAt}
+		if s.Dormant {
+			info.SavedAt = s.SavedAt
+		} else {
+			info.Agents = agents[s.Name]
+		}
+		for _, w := range s.Windows {
+			info.Panes += len(w.Panes)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// publishAgent announces a change in a pane's agent state. A record with an
+// empty State means the pane is no longer tracked.
+func publishAgent(deps *daemonDeps, r agent.Record) {
+	deps.publish(control.EventAgent, r)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// shortStateDir returns a state dir whose daemon.sock fits in sun_path;
+// t.TempDir() nests the full test name and can overrun it.
+func shortStateDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "pst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
+
+// controlTestIndex is a committed index of one running session with two panes
+// and one dormant session.
+func controlTestIndex(savedAt time.Time) *state.Index {
+	return &state.Index{
+		SavedAt: savedAt,
+		Sessions: []state.Session{
+			{Name: "api", Windows: []state.Window{{Panes: []state.Pane{{}, {}}}}},
+			{Name: "old", Dormant: true, SavedAt: savedAt.Add(-time.Hour), Windows: []state.Window{{Panes: []state.Pane{{}}}}},
+		},
+	}
+}
+
+func TestHandleControlCall(t *testing.T) {
+	savedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	deps := &daemonDeps{
+		Version:    "1.2.3",
+		LastSaveAt: savedAt,
+		PrevIndex:  controlTestIndex(savedAt),
+		Agents: agent.Table{
+			"%1": {PaneID: "%1", Session: "api", Window: 0, Pane: 1, State: agent.Waiting},
+			"%2": {PaneID: "%2", Session: "api", Window: 0, Pane: 0, State: agent.Working},
+		},
+	}
+
+	t.Run("status counts sessions, panes and agent states", func(t *testing.T) {
+		res, err := handleControlCall(context.Background(), deps, control.MethodStatus, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := res.(control.StatusResult)
+		if st.Sessions != 1 || st.Dormant != 1 || st.Panes != 2 || st.Version != "1.2.3" || st.Reaper {
+			t.Errorf("status = %+v", st)
+		}
+		if st.Agents["waiting"] != 1 || st.Agents["working"] != 1 {
+			t.Errorf("agents = %v", st.Agents)
+		}
+	})
+
+	t.Run("sessions lists the committed index with agents", func(t *testing.T) {
+		res, err := handleControlCall(context.Background(), deps, control.MethodSessions, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		infos := res.([]control.SessionInfo)
+		if len(infos) != 2 {
+			t.Fatalf("got %d sessions", len(infos))
+		}
+		api, old := infos[0], infos[1]
+		if api.Name != "api" || api.Panes != 2 || !api.SavedAt.Equal(savedAt) || len(api.Agents) != 2 {
+			t.Errorf("api = %+v", api)
+		}
+		if !old.Dormant || !old.SavedAt.Equal(savedAt.Add(-time.Hour)) || old.Agents != nil {
+			t.Errorf("old = %+v", old)
+		}
+	})
+
+	t.Run("an unknown method is unknown-method", func(t *testing.T) {
+		var cerr *control.Error
+		_, err := handleControlCall(context.Background(), deps, "reboot", nil)
+		if !errors.As(err, &cerr) || cerr.Code != control.ErrCodeMethod {
+			t.Errorf("got %v", err)
+		}
+	})
+}
+
+func TestStartControlServer(t *testing.T) {
+	dir := shortStateDir(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deps := &daemonDeps{Dir: dir, Version: "1.2.3", Logger: discardDaemonLogger()}
+
+	srv := startControlServer(ctx, deps)
+	if srv == nil {
+		t.Fatal("no server started")
+	}
+	defer func() { _ = srv.Close() }()
+
+	// Stand in for the tick loop: answer calls until the test ends.
+	go func() {
+		for {
+			select {
+			case call := <-deps.controlCalls:
+				result, err := handleControlCall(ctx, deps, call.method, call.params)
+				call.reply <- controlReply{result: result, err: err}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	client, err := control.Dial(state.DaemonSocket(dir), 2*time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	var ping control.PingResult
+	if err := client.Call(control.MethodPing, nil, &ping); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if ping.PID != os.Getpid() || ping.Version != "1.2.3" || ping.Protocol != control.Version {
+		t.Errorf("ping = %+v", ping)
+	}
+
+	t.Run("a second daemon leaves the socket alone", func(t *testing.T) {
+		if second := startControlServer(ctx, &daemonDeps{Dir: dir, Logger: discardDaemonLogger()}); second != nil {
+			_ = second.Close()
+			t.Fatal("second server started on a live socket")
+		}
+	})
+}
+
+func TestDaemonControl_ShuttingDown(t *testing.T) {
+	done := make(chan struct{})
+	close(done)
+	_, err := daemonControl{calls: make(chan controlCall), done: done}.Handle(control.MethodPing, json.RawMessage(nil))
+	if err == nil || err.Error() != "daemon shutting down" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestStartControlServer_UnusableDirIsNotFatal(t *testing.T) {
+	deps := &daemonDeps{Dir: filepath.Join(shortStateDir(t), "missing"), Logger: discardDaemonLogger()}
+	if srv := startControlServer(context.Background(), deps); srv != nil {
+		_ = srv.Close()
+		t.Fatal("server started in a missing dir")
+	}
+	// Publishing without a socket is a no-op.
+	deps.publish(control.EventSaved, control.SaveResult{})
+}
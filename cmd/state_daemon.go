@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io/fs"
 	"log/slog"
 	"os"
@@ -13,6 +16,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/control"
 	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/log"
 	"github.com/leeovery/portal/internal/project"
@@ -28,8 +33,9 @@ import (
 // HashMap and PrevIndex are mutable across ticks and updated by the loop —
 // HashMap by WriteScrollbackIfChanged, PrevIndex by captureAndCommit.
 // LastSaveAt is updated by tick when a capture-and-commit succeeds; lastCleanup
-// is rewritten by maybeRunHookCleanup and lastProjectCleanup by
-// maybeRunProjectCleanup, each time its throttled prune fires.
+// is rewritten by maybeRunHookCleanup, lastProjectCleanup by
+// maybeRunProjectCleanup and lastSnapshot by maybeRunAutoSnapshot, each time
+// its throttled gate fires.
 type daemonDeps struct {
 	Dir     string
 	Version string
@@ -68,8 +74,74 @@ type daemonDeps struct {
 	// first idle tick.
 	lastProjectCleanup time.Time
 
-	HashMap      state.HashMap
-	PrevIndex    *state.Index
+	// SnapshotRetention is the rolling hourly/daily snapshot retention,
+	// resolved once at startup from PORTAL_SNAPSHOT_HOURLY /
+	// PORTAL_SNAPSHOT_DAILY. A single zero tier is pruned to nothing by
+	// RunAutoSnapshots; both counts zero skips the gate outright, so existing
+	// auto-snapshots are then left on disk untouched.
+	SnapshotRetention state.SnapshotRetention
+
+	// lastSnapshot is the throttle anchor for maybeRunAutoSnapshot; anchored
+	// to daemon-START time like the two cleanup anchors above.
+	lastSnapshot time.Time
+
+	// Reap loads the idle reaper's configuration (loadReapConfig in
+	// production) once per reap round; a zero IdleAfter, the default, leaves
+	// the round a no-op. nil disables the gate outright.
+	Reap func() reapConfig
+
+	// lastReap is the throttle anchor for maybeRunIdleReap, idle the
+	// reaper's per-session activity marks, and reapIdle the threshold last
+	// logged. All are owned by the gate; idle is created lazily on the first
+	// enabled round.
+	lastReap time.Time
+	idle     idleTracker
+	reapIdle time.Duration
+
+	// Agents is the coding-agent state table, loaded from agent.json at
+	// startup and kept current by drainAgentInbox on every tick;
+	// lastAgentReconcile is the throttle anchor for maybeReconcileAgents.
+	// agentBacklog holds drained updates a failed pane listing kept from being
+	// applied, for the next tick to retry.
+	Agents             agent.Table
+	lastAgentReconcile time.Time
+	agentBacklog       []agent.Update
+
+	// AgentScreens loads the screen-rule agents maybeScanAgentScreens watches
+	// for (agentScreenRules); lastAgentScreenScan is its throttle anchor. Nil
+	// (the unit-test default) disables the scan. PaneArgv builds the resolver
+	// one scan reads foreground argvs through (state.ForegroundArgvResolver);
+	// nil matches on the command name alone.
+	AgentScreens        func() ([]agent.ScreenAgent, error)
+	PaneArgv            func() func(panePID int) []string
+	lastAgentScreenScan time.Time
+
+	// Control is the control-socket server, nil when the socket could not be
+	// opened (or in unit tests); controlCalls carries its requests into the
+	// tick loop. Both are set by startControlServer.
+	Control      *control.Server
+	controlCalls chan controlCall
+
+	// RestorePolicy loads the restore policy in force; it is called once per
+	// capture — production serves it from a restorePolicyCache, so the config
+	// files are only re-read when they change — and a `policy set` takes
+	// effect on the next tick without a daemon restart. Sessions it resolves
+	// to never are dropped from the capture; lazy ones never age out of the
+	// dormant set. Nil (the unit-test default) keeps every session.
+	RestorePolicy func() state.RestorePolicyFunc
+
+	HashMap   state.HashMap
+	PrevIndex *state.Index
+	StartedAt time.Time
+
+	// prevStamp is sessions.json as this daemon last committed or read it;
+	// a different stamp means commit-now wrote since, and PrevIndex is
+	// reloaded (see syncPrevIndex). saveFingerprints holds each running
+	// session's fingerprint as of its last on-save firing; nil until the
+	// first commit seeds it.
+	prevStamp        fileStamp
+	saveFingerprints map[string]uint64
+
 	LastSaveAt   time.Time
 	TickerPeriod time.Duration
 	MaxGap       time.Duration
@@ -240,6 +312,11 @@ const hookCleanupInterval = 10 * time.Second
 // production; the throttle-gate mechanism mirrors the hook cleanup.
 const projectCleanupInterval = 1 * time.Hour
 
+// autoSnapshotInterval is how often the idle branch checks whether the current
+// hourly/daily snapshot bucket has been taken yet. The buckets themselves
+// decide cadence; this only bounds how late into a new hour the snapshot lands.
+const autoSnapshotInterval = 5 * time.Minute
+
 // defaultDaemonRun is the production daemon body: a 1-second ticker that fires
 // captures when the dirty flag is set or the 30-second max-gap has elapsed,
 // returning to delegate the final flush to daemonShutdownFunc on ctx-cancel.
@@ -311,6 +388,12 @@ func defaultDaemonRun(ctx context.Context, deps *daemonDeps) error {
 	}
 	daemonLockFile = lockFile
 
+	// The control socket opens once the lock is held, so only the singleton
+	// daemon ever serves it, and closes after the shutdown flush.
+	if srv := startControlServer(ctx, deps); srv != nil {
+		defer func() { _ = srv.Close() }()
+	}
+
 	// Additive subsystem milestone (spec § Saver and daemon lifecycle event
 	// taxonomy — daemon "lock acquired"). The OS-process-boundary marker is
 	// "process: start process_role=daemon" (Phase 2); this line carries the
@@ -365,6 +448,9 @@ func defaultDaemonTickLoop(ctx context.Context, deps *daemonDeps) error {
 				deps.Logger.Debug("saver-membership probe failed", "ticks", consecutiveAbsenceTicks, "threshold", selfSupervisionHysteresisTicks)
 			}
 			tick(ctx, deps)
+		case call := <-deps.controlCalls:
+			result, err := handleControlCall(ctx, deps, call.method, call.params)
+			call.reply <- controlReply{result: result, err: err}
 		case <-ctx.Done():
 			return daemonShutdownFunc(deps)
 		}
@@ -378,7 +464,10 @@ func defaultDaemonTickLoop(ctx context.Context, deps *daemonDeps) error {
 // The order of checks matters:
 //  1. @portal-restoring suppresses the entire tick (incl. clearing the dirty
 //     flag) so a save.requested touch during restore survives until restore
-//     completes.
+//     completes. Past it, the agent inbox is drained (drainAgentInbox) on
+//     every tick, capture-pending or not, so an agent's permission prompt
+//     reaches agent.json within a tick; the screen-rule scan
+//     (maybeScanAgentScreens) follows it, throttled, for the same reason.
 //  2. !dirty && !gap is the idle fast path — after the no-op stat, run the two
 //     throttled daemon-owned prunes: the hooks stale-cleanup gate
 //     (maybeRunHookCleanup; ~10s throttle) then the stale-project prune
@@ -388,7 +477,13 @@ func defaultDaemonTickLoop(ctx context.Context, deps *daemonDeps) error {
 //     them behind capture work and they would never run on an idle server. They
 //     are skipped entirely while @portal-restoring is set (whole tick skipped)
 //     and on capture-pending ticks (dirty||gap -> capture runs, prunes skipped;
-//     scrollback always wins).
+//     scrollback always wins). The rolling auto-snapshot gate
+//     (maybeRunAutoSnapshot) rides the same branch for the same reasons, and
+//     additionally because a restoring server must never be frozen into a
+//     snapshot. The idle reaper (maybeRunIdleReap) comes last: it reads the
+//     HashMap the capture branch keeps current, and a restoring server's
+//     sessions have not had the chance to show any activity. The agent-table
+//     reconcile (maybeReconcileAgents) follows it on the same branch.
 //  3. captureAndCommit failures leave LastSaveAt and save.requested untouched
 //     so the next tick retries.
 func tick(ctx context.Context, deps *daemonDeps) {
@@ -401,11 +496,17 @@ func tick(ctx context.Context, deps *daemonDeps) {
 		return
 	}
 
+	drainAgentInbox(deps.Client, deps, agentLogger)
+	maybeScanAgentScreens(deps.Client, deps, agentLogger)
+
 	dirty := fileExists(state.SaveRequested(deps.Dir))
 	gap := time.Since(deps.LastSaveAt) >= deps.MaxGap
 	if !dirty && !gap {
 		maybeRunHookCleanup(deps)
 		maybeRunProjectCleanup(deps)
+		maybeRunAutoSnapshot(deps)
+		maybeRunIdleReap(deps)
+		maybeReconcileAgents(deps.Client, deps, agentLogger)
 		return
 	}
 
@@ -415,12 +516,87 @@ func tick(ctx context.Context, deps *daemonDeps) {
 	}
 
 	deps.LastSaveAt = time.Now()
+	fireSaveHooks(deps)
+	publishSaved(deps)
 
 	if err := os.Remove(state.SaveRequested(deps.Dir)); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		deps.Logger.Warn("remove save.requested failed", "error", err)
 	}
 }
 
+// fireSaveHooks runs the on-save hooks of every running session whose saved
+// state changed in the commit the tick just made — its structure or any of
+// its panes' scrollback — so a quiet session does not spawn a hook on every
+// save. The first commit after the daemon starts only records where each
+// session stands. It sits in tick rather than captureAndCommit so the
+// shutdown flush, which is not a save a user asked to hear about, stays
+// silent, and so the integration drift-mirror of captureAndCommit is
+// unaffected. A nil HookStore disables it, like the hooks cleanup gate.
+func fireSaveHooks(deps *daemonDeps) {
+	if deps.PrevIndex == nil {
+		return
+	}
+	seeding := deps.saveFingerprints == nil
+	fingerprints := make(map[string]uint64, len(deps.PrevIndex.Sessions))
+	var changed []state.Session
+	for _, sess := range deps.PrevIndex.Sessions {
+		if sess.Dormant {
+			continue
+		}
+		fp := sessionFingerprint(sess, deps.HashMap)
+		fingerprints[sess.Name] = fp
+		if last, ok := deps.saveFingerprints[sess.Name]; !seeding && (!ok || last != fp) {
+			changed = append(changed, sess)
+		}
+	}
+	deps.saveFingerprints = fingerprints
+	if len(changed) == 0 {
+		return
+	}
+
+	fireHooks(deps.HookStore, hooks.EventSave, func() ([]hookTarget, error) {
+		targets := make([]hookTarget, 0, len(changed))
+		for _, sess := range changed {
+			targets = append(targets, savedHookTarget(sess, ""))
+		}
+		return targets, nil
+	})
+}
+
+// sessionFingerprint hashes what a save records for sess: its structure as
+// committed and the content hash of each of its panes' scrollback.
+func sessionFingerprint(sess state.Session, hm state.HashMap) uint64 {
+	h := fnv.New64a()
+	data, _ := json.Marshal(sess)
+	_, _ = h.Write(data)
+	var buf [8]byte
+	for _, w := range sess.Windows {
+		for _, p := range w.Panes {
+			binary.LittleEndian.PutUint64(buf[:], hm[state.SanitizePaneKey(sess.Name, w.Index, p.Index)])
+			_, _ = h.Write(buf[:])
+		}
+	}
+	return h.Sum64()
+}
+
+// syncPrevIndex reloads deps.PrevIndex from sessions.json when another writer
+// — commit-now, run from the session-closed hook — committed since this
+// daemon's own last commit. Without it the daemon would diff against its
+// stale in-memory index and report a kill commit-now already moved to
+// dormant as a second live-to-dormant transition.
+func syncPrevIndex(deps *daemonDeps) {
+	stamp := statStamp(state.SessionsJSON(deps.Dir), nil)
+	if stamp == deps.prevStamp {
+		return
+	}
+	if idx, skip, err := state.ReadIndex(deps.Dir); !skip {
+		deps.PrevIndex = &idx
+	} else if err != nil {
+		deps.Logger.Warn("ReadIndex failed", "error", err)
+	}
+	deps.prevStamp = stamp
+}
+
 // maybeRunHookCleanup is the throttled gate for the daemon-owned hooks
 // stale-cleanup (spec § Daemon-Owned Hooks Cleanup → Operational contract).
 // Below the throttle interval it is a pure no-op (no cleanup call, lastCleanup
@@ -500,6 +676,27 @@ func maybeRunProjectCleanup(deps *daemonDeps) {
 	deps.lastProjectCleanup = time.Now()
 }
 
+// maybeRunAutoSnapshot is the throttled gate for the rolling hourly/daily
+// snapshots. It follows the cleanup gates' shape — disabled-guard →
+// throttle-check → best-effort call → reset the anchor AFTER the body — and
+// delegates to state.RunAutoSnapshots, which takes any missing bucket snapshot
+// and prunes each tier to deps.SnapshotRetention, logging its own breadcrumbs
+// under the snapshot component.
+//
+// It snapshots the COMMITTED sessions.json, never a fresh capture: running on
+// the idle branch means the last commit is current, and reusing it keeps the
+// snapshot byte-identical to what a reboot restore would read.
+func maybeRunAutoSnapshot(deps *daemonDeps) {
+	if deps.SnapshotRetention == (state.SnapshotRetention{}) {
+		return
+	}
+	if time.Since(deps.lastSnapshot) < autoSnapshotInterval {
+		return
+	}
+	state.RunAutoSnapshots(deps.Dir, time.Now(), deps.SnapshotRetention, snapshotLogger)
+	deps.lastSnapshot = time.Now()
+}
+
 // captureAndCommit runs a full save cycle: list skeleton markers, capture the
 // structural index (merging skeleton-marked panes from the prior index),
 // capture and dedup-write per-pane scrollback, then atomically commit
@@ -533,6 +730,8 @@ func captureAndCommit(ctx context.Context, deps *daemonDeps) error {
 	// capture work returns nil without arming a summary.
 	start := time.Now()
 
+	syncPrevIndex(deps)
+
 	skipSet, err := state.ListSkeletonMarkers(deps.Client)
 	if err != nil {
 		return fmt.Errorf("list markers: %w", err)
@@ -542,16 +741,25 @@ func captureAndCommit(ctx context.Context, deps *daemonDeps) error {
 	if err != nil {
 		return fmt.Errorf("capture structure: %w", err)
 	}
+	// Sessions that stopped running since the last save stay in the index as
+	// dormant so they can be listed and resurrected; they have no live panes
+	// to capture and are skipped below. Those that stopped since the last
+	// save get their on-kill hooks once the commit lands.
+	var policy state.RestorePolicyFunc
+	if deps.RestorePolicy != nil {
+		policy = deps.RestorePolicy()
+	}
+	stopped := state.CarryDormant(&idx, deps.PrevIndex, time.Now(), policy)
+	state.DropNeverSaved(&idx, policy)
 
 	// Cycle-summary counters (spec § Cycle-level summary cadence and shape).
-	// sessions is the structural session count; panes counts every PROCESSED
+	// sessions is the live (non-dormant) session count; panes counts every PROCESSED
 	// pane (skipSet entries are excluded); naturalChurn counts panes that
 	// vanished mid-tick by normal action (a user closing a pane/session —
 	// distinguished from an anomalous failure via the tmux pane-vanished
 	// signal); anomalous counts genuine capture/write failures that did not
 	// terminate the cycle (each also emits a per-pane WARN).
-	sessions := len(idx.Sessions)
-	var panes, naturalChurn, anomalous int
+	var sessions, panes, naturalChurn, anomalous int
 
 	// observation point 2 of 3: post-enumeration, pre-first-iteration; covers
 	// cancellation during the CaptureStructure subprocess call. Returns before
@@ -564,6 +772,10 @@ func captureAndCommit(ctx context.Context, deps *daemonDeps) error {
 
 	anyScrollbackChanged := false
 	for _, sess := range idx.Sessions {
+		if sess.Dormant {
+			continue
+		}
+		sessions++
 		for _, win := range sess.Windows {
 			for _, pane := range win.Panes {
 				// observation point 3 of 3: between per-pane iterations; caps
@@ -625,6 +837,8 @@ func captureAndCommit(ctx context.Context, deps *daemonDeps) error {
 	}
 
 	deps.PrevIndex = &idx
+	deps.prevStamp = statStamp(state.SessionsJSON(deps.Dir), nil)
+	fireKillHooks(deps.HookStore, stopped)
 
 	// Tick-complete cycle summary (spec § Cycle-level summary cadence and
 	// shape, daemon-tick row). Emitted ONLY on the successful post-Commit
@@ -751,8 +965,10 @@ var stateDaemonCmd = &cobra.Command{
 		// the lock-held / lock-error paths short-circuit before reaching
 		// the ticker loop.
 
-		// Seed the per-pane content-hash map from any existing scrollback
-		// files so the first cycle dedupes against what is already on disk.
+		// Convert any raw scrollback left by an older version into the chunk
+		// store, then seed the per-pane content-hash map from the manifests so
+		// the first cycle dedupes against what is already on disk.
+		state.MigrateScrollback(dir, logger)
 		hm := state.SeedHashMap(dir, logger)
 
 		// Load the prior structural index so skeleton-marked panes can be
@@ -802,7 +1018,12 @@ var stateDaemonCmd = &cobra.Command{
 			projectStore = nil
 		}
 
+		// Rolling snapshot retention is resolved once; an invalid env value
+		// WARNs here (under the snapshot component) and falls back to defaults.
+		snapshotRetention := state.ResolveSnapshotRetention(snapshotLogger)
+
 		client := tmux.DefaultClient()
+		agents := loadAgentTable(client, dir, agentLogger)
 		startedAt := time.Now()
 		deps := &daemonDeps{
 			Dir:     dir,
@@ -816,8 +1037,18 @@ var stateDaemonCmd = &cobra.Command{
 			lastCleanup:        startedAt,
 			ProjectStore:       projectStore,
 			lastProjectCleanup: startedAt,
+			SnapshotRetention:  snapshotRetention,
+			lastSnapshot:       startedAt,
+			Reap:               loadReapConfig,
+			lastReap:           startedAt,
+			Agents:             agents,
+			lastAgentReconcile: startedAt,
+			AgentScreens:       agentScreenRules(),
+			PaneArgv:           state.ForegroundArgvResolver,
+			RestorePolicy:      (&restorePolicyCache{}).Load,
 			HashMap:            hm,
 			PrevIndex:          prevIdx,
+			StartedAt:          startedAt,
 			TickerPeriod:       1 * time.Second,
 			MaxGap:             30 * time.Second,
 		}
@@ -0,0 +1,70 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/state"
+)
+
+func TestMaybeRunAutoSnapshot_SnapshotsOnceIntervalElapsed(t *testing.T) {
+	dir := seedSnapshotStateDir(t, "api")
+	deps := &daemonDeps{
+		Dir:               dir,
+		Logger:            discardDaemonLogger(),
+		SnapshotRetention: state.SnapshotRetention{Hourly: 1, Daily: 1},
+		lastSnapshot:      time.Now().Add(-autoSnapshotInterval - time.Second),
+	}
+	beforeCall := time.Now()
+
+	maybeRunAutoSnapshot(deps)
+
+	hourly, daily := state.AutoSnapshotNames(time.Now())
+	for _, name := range []string{hourly, daily} {
+		if _, err := os.Stat(state.SnapshotDir(dir, name)); err != nil {
+			t.Errorf("auto snapshot %s not taken: %v", name, err)
+		}
+	}
+	if deps.lastSnapshot.Before(beforeCall) {
+		t.Errorf("lastSnapshot not advanced: got %v, want >= %v", deps.lastSnapshot, beforeCall)
+	}
+}
+
+func TestMaybeRunAutoSnapshot_NoOpBelowInterval(t *testing.T) {
+	dir := seedSnapshotStateDir(t, "api")
+	anchor := time.Now()
+	deps := &daemonDeps{
+		Dir:               dir,
+		Logger:            discardDaemonLogger(),
+		SnapshotRetention: state.SnapshotRetention{Hourly: 1, Daily: 1},
+		lastSnapshot:      anchor,
+	}
+
+	maybeRunAutoSnapshot(deps)
+
+	if _, err := os.Stat(state.SnapshotsDir(dir)); !os.IsNotExist(err) {
+		t.Errorf("snapshots dir created below the throttle interval: %v", err)
+	}
+	if !deps.lastSnapshot.Equal(anchor) {
+		t.Errorf("lastSnapshot moved below the throttle interval")
+	}
+}
+
+func TestMaybeRunAutoSnapshot_DisabledRetentionIsNoOp(t *testing.T) {
+	dir := seedSnapshotStateDir(t, "api")
+	deps := &daemonDeps{
+		Dir:    dir,
+		Logger: discardDaemonLogger(),
+	}
+
+	maybeRunAutoSnapshot(deps)
+
+	if _, err := os.Stat(state.SnapshotsDir(dir)); !os.IsNotExist(err) {
+		t.Errorf("snapshots dir created with auto-snapshots disabled: %v", err)
+	}
+	if !deps.lastSnapshot.IsZero() {
+		t.Errorf("lastSnapshot advanced with auto-snapshots disabled")
+	}
+}
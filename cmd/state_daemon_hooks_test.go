@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// hookedSession is a single-pane running session as an Index records it.
+func hookedSession(name string) state.Session {
+	return state.Session{Name: name, Environment: map[string]string{}, Windows: []state.Window{
+		{Index: 0, Active: true, Panes: []state.Pane{{Index: 0, CWD: "/tmp", Active: true}}},
+	}}
+}
+
+func TestCaptureAndCommit_FiresOnKillForStoppedSessions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	fired := recordHookProcesses(t)
+	store := writeHooksFile(t, map[string]map[string]string{
+		"gone:0.0": {hooks.EventKill: "echo gone"},
+		"work:0.0": {hooks.EventKill: "echo work"},
+	})
+
+	sess, panes := oneSession()
+	deps := makeCaptureDeps(t, dir, &daemonFakeCommander{sessionsOut: sess, panesOut: panes})
+	deps.HookStore = store
+	deps.PrevIndex = &state.Index{Version: state.SchemaVersion, SavedAt: time.Now(), Sessions: []state.Session{hookedSession("gone"), hookedSession("work")}}
+
+	if err := captureAndCommit(context.Background(), deps); err != nil {
+		t.Fatalf("captureAndCommit: %v", err)
+	}
+	if len(*fired) != 1 || (*fired)[0].Command != "echo gone" {
+		t.Fatalf("fired = %+v, want only the killed session's on-kill hook", *fired)
+	}
+
+	if err := captureAndCommit(context.Background(), deps); err != nil {
+		t.Fatalf("second captureAndCommit: %v", err)
+	}
+	if len(*fired) != 1 {
+		t.Errorf("fired = %+v, want the kill reported once", *fired)
+	}
+}
+
+func TestCaptureAndCommit_SkipsKillCommitNowAlreadyCommitted(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PORTAL_STATE_DIR", dir)
+	fired := recordHookProcesses(t)
+	store := writeHooksFile(t, map[string]map[string]string{
+		"gone:0.0": {hooks.EventKill: "echo gone"},
+	})
+
+	sess, panes := oneSession()
+	deps := makeCaptureDeps(t, dir, &daemonFakeCommander{sessionsOut: sess, panesOut: panes})
+	deps.HookStore = store
+	saved := time.Now()
+	deps.PrevIndex = &state.Index{Version: state.SchemaVersion, SavedAt: saved, Sessions: []state.Session{hookedSession("gone"), hookedSession("work")}}
+
+	// commit-now already recorded the kill on disk behind the daemon's back.
+	gone := hookedSession("gone")
+	gone.Dormant, gone.SavedAt = true, saved
+	onDisk := state.Index{Version: state.SchemaVersion, SavedAt: saved, Sessions: []state.Session{gone, hookedSession("work")}}
+	if err := state.Commit(dir, onDisk, false, nil); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if err := captureAndCommit(context.Background(), deps); err != nil {
+		t.Fatalf("captureAndCommit: %v", err)
+	}
+	if len(*fired) != 0 {
+		t.Errorf("fired = %+v, want no second on-kill for a kill commit-now reported", *fired)
+	}
+}
+
+func TestFireSaveHooks_OnlyForChangedSessions(t *testing.T) {
+	fired := recordHookProcesses(t)
+	store := writeHooksFile(t, map[string]map[string]string{
+		"quiet:0.0": {hooks.EventSave: "echo quiet"},
+		"busy:0.0":  {hooks.EventSave: "echo busy"},
+	})
+	deps := &daemonDeps{
+		HookStore: store,
+		HashMap:   state.HashMap{"quiet__0.0": 1, "busy__0.0": 1},
+		PrevIndex: &state.Index{Sessions: []state.Session{hookedSession("busy"), hookedSession("quiet")}},
+	}
+
+	fireSaveHooks(deps)
+	if len(*fired) != 0 {
+		t.Fatalf("fired = %+v, want the first save to only record fingerprints", *fired)
+	}
+
+	fireSaveHooks(deps)
+	if len(*fired) != 0 {
+		t.Fatalf("fired = %+v, want nothing for an unchanged save", *fired)
+	}
+
+	deps.HashMap["busy__0.0"] = 2
+	fireSaveHooks(deps)
+	if len(*fired) != 1 || (*fired)[0].Command != "echo busy" {
+		t.Errorf("fired = %+v, want only the session whose scrollback changed", *fired)
+	}
+}
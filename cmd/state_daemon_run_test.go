@@ -267,10 +267,12 @@ func touchSaveRequested(t *testing.T, dir string) {
 // path.
 func oneSession() (sessionsOut, panesOut string) {
 	sessionsOut = "work|1|0|"
-	// Format matches captureFormat in internal/state/capture.go. The trailing
-	// empty |||-separated field is the un-stamped @portal-id column (11th
-	// field) added by the fixed-arity bump; a legacy session resolves it to "".
-	panesOut = "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||"
+	// Format matches captureFormat in internal/state/capture.go. The three
+	// trailing empty |||-separated fields are the un-stamped @portal-id column
+	// (11th field; a legacy session resolves it to "") and the #{pane_pid}
+	// column (12th), left empty so no argv lookup runs against a real pid,
+	// and the un-stamped @portal-dir column (13th).
+	panesOut = "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||"
 	return
 }
 
@@ -471,8 +473,8 @@ func TestDaemonTick_SkipsSkeletonMarkedPanesInScrollback(t *testing.T) {
 	fc := &daemonFakeCommander{
 		markersOut:  markersOut,
 		sessionsOut: "work|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "captured-pane-0",
 			"work:0.1": "should-not-be-captured",
@@ -505,8 +507,8 @@ func TestDaemonTick_ContinuesOnPerPaneCaptureError(t *testing.T) {
 	t.Setenv("PORTAL_STATE_DIR", dir)
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||",
 		captureErrByTarget: map[string]error{
 			"work:0.0": errors.New("flaky pane"),
 		},
@@ -1093,9 +1095,9 @@ func TestCaptureAndCommit_UncancelledCtxMatchesPreThreadingBehaviour(t *testing.
 	// loop's pane-iteration across the (sess, win, pane) nesting.
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|\nside|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||\n" +
-			"side|||0|||main|||layout|||0|||1|||0|||/var|||1|||zsh|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||\n" +
+			"side|||0|||main|||layout|||0|||1|||0|||/var|||1|||zsh|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "work-pane-0-bytes",
 			"work:0.1": "work-pane-1-bytes",
@@ -1177,7 +1179,7 @@ func TestCaptureAndCommit_PreCancelledCtxReturnsImmediately(t *testing.T) {
 	// capture panes, commit) — the assertions below would catch the leak.
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|",
-		panesOut:    "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||",
+		panesOut:    "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "work-pane-0-bytes",
 		},
@@ -1247,9 +1249,9 @@ func TestCaptureAndCommit_CancelDuringCaptureStructureReturnsBeforePerPaneWork(t
 	// sessions.json).
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|\nside|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||\n" +
-			"side|||0|||main|||layout|||0|||1|||0|||/var|||1|||zsh|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||\n" +
+			"side|||0|||main|||layout|||0|||1|||0|||/var|||1|||zsh|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "work-pane-0-bytes",
 			"work:0.1": "work-pane-1-bytes",
@@ -1341,9 +1343,9 @@ func TestCaptureAndCommit_CancelMidLoopAfterKofNPanesProcessed(t *testing.T) {
 	// pane-iteration loop.
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||\n" +
-			"work|||0|||main|||layout|||0|||1|||2|||/tmp|||0|||fish|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||2|||/tmp|||0|||fish|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "work-pane-0-bytes",
 			"work:0.1": "work-pane-1-bytes",
@@ -1406,9 +1408,9 @@ func TestCaptureAndCommit_UncancelledMultiPaneFixtureProcessesAllPanesAndCommits
 
 	fc := &daemonFakeCommander{
 		sessionsOut: "work|1|0|",
-		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||\n" +
-			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||\n" +
-			"work|||0|||main|||layout|||0|||1|||2|||/tmp|||0|||fish|||",
+		panesOut: "work|||0|||main|||layout|||0|||1|||0|||/tmp|||1|||zsh|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||1|||/tmp|||0|||bash|||||||||\n" +
+			"work|||0|||main|||layout|||0|||1|||2|||/tmp|||0|||fish|||||||||",
 		captureByTarget: map[string]string{
 			"work:0.0": "work-pane-0-bytes",
 			"work:0.1": "work-pane-1-bytes",
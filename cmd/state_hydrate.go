@@ -13,6 +13,7 @@ import (
 
 	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/log"
+	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
@@ -40,6 +41,12 @@ var ErrHydrateTimeout = errors.New("fifo open timeout")
 // by tasks 3-9 and 3-10. HookStore + HookKey drive the Phase 4 hook lookup —
 // HookKey is the saved structural identifier (per spec "Helper hook lookup
 // under index drift") and is used verbatim, not the FIFO-derived live key.
+// Dir, SessionDir and Command are the pane's saved working directory, its
+// session's directory and its saved foreground command; they match the pane
+// to project resume hooks when no hook is registered on HookKey itself, and
+// to ResumeRules (resume.json) when no hook applies at all. CommandLine is the
+// saved foreground command's full argv as one shell command line, exported to
+// whichever resume command runs.
 //
 // ExecShell takes (prog, args) so the hook-firing path can hand off to
 // `sh -c '<HOOK>; exec $SHELL'` with the user-registered command living in
@@ -48,10 +55,15 @@ type hydrateConfig struct {
 	FIFO              string
 	File              string
 	HookKey           string
+	Dir               string
+	SessionDir        string
+	Command           string
+	CommandLine       string
 	Stdout            io.Writer
 	Client            *tmux.Client
 	Logger            *slog.Logger
 	HookStore         *hooks.Store
+	ResumeRules       hooks.ResumeRules
 	ExecShell         func(prog string, args []string)
 	OpenFIFO          func(path string, timeout time.Duration) (*os.File, error)
 	HandleFileMissing func(cfg hydrateConfig, ctx hydrateFileMissingContext) error
@@ -166,10 +178,11 @@ func runHydrate(cfg hydrateConfig) error {
 	// without the handler having to re-emit it.
 	_, _ = io.WriteString(cfg.Stdout, hydrateResetPreamble)
 
-	// 4. Open the saved scrollback file. Failure (ENOENT, permission denied,
-	// or any other I/O error) routes through HandleFileMissing — preamble is
+	// 4. Open the saved scrollback file (decompressing its chunks when it is
+	// a chunk-store manifest). Failure (ENOENT on the manifest or a chunk,
+	// permission denied, or any other I/O error) routes through HandleFileMissing — preamble is
 	// already on stdout, so the pane lands on a clean shell after exec.
-	sb, err := os.Open(cfg.File)
+	sb, err := state.OpenScrollback(cfg.File)
 	if err != nil {
 		if cfg.HandleFileMissing != nil {
 			if hErr := cfg.HandleFileMissing(cfg, hydrateFileMissingContext{Cause: err}); hErr != nil {
@@ -278,9 +291,11 @@ func resolveShell() string {
 // spec § Fix 2 → Specific Changes → 2 — every recovery path that falls
 // through to a shell fires the on-resume hook if one is registered.
 //
-// On any of: nil HookStore, lookup error, or no hook registered → exec bare
-// $SHELL via execShellAndExit. On a registered non-empty on-resume command,
-// exec `/bin/sh -c '<cmd>; exec $SHELL'`. The hook command sits in its own
+// On any of: nil HookStore, lookup error, or no hook registered → fall back
+// to smart resume, then bare $SHELL, via execSmartResumeOrShell. On a
+// non-empty on-resume command registered for the pane's key or, failing
+// that, for its project (see resumePane), exec `/bin/sh -c '<cmd>; exec
+// $SHELL'`. The hook command sits in its own
 // argv slot (`sh -c <cmd>`) so sh's parser handles any embedded quotes —
 // Portal does no string-interpolation of the user-registered command.
 //
@@ -293,23 +308,49 @@ func execShellOrHookAndExit(cfg hydrateConfig) {
 		// A nil store degrades to a bare shell — that is a "miss", NOT an
 		// "error" (the lookup never ran; nothing failed). No error attr.
 		cfg.Logger.Debug("hook lookup", "hook_key", cfg.HookKey, "result", "miss")
-		execShellAndExit(cfg)
+		execSmartResumeOrShell(cfg)
 		return
 	}
-	command, found, err := hooks.LookupOnResume(cfg.HookStore, cfg.HookKey)
+	match, found, err := hooks.LookupResume(cfg.HookStore, resumePane(cfg.HookKey, cfg.Dir, cfg.SessionDir, cfg.Command))
 	if err != nil {
 		cfg.Logger.Debug("hook lookup", "hook_key", cfg.HookKey, "result", "error", "error", err)
 		cfg.Logger.Warn("lookup on-resume hook failed", "hook_key", cfg.HookKey, "error", err)
-		execShellAndExit(cfg)
+		execSmartResumeOrShell(cfg)
 		return
 	}
 	if !found {
 		// No hook / missing-or-malformed hooks.json → ("", false, nil) → miss.
 		cfg.Logger.Debug("hook lookup", "hook_key", cfg.HookKey, "result", "miss")
-		execShellAndExit(cfg)
+		execSmartResumeOrShell(cfg)
 		return
 	}
-	cfg.Logger.Debug("hook lookup", "hook_key", cfg.HookKey, "result", "hit")
+	cfg.Logger.Debug("hook lookup", "hook_key", cfg.HookKey, "result", "hit", "matched_key", match.Key)
+	execHookChain(cfg, match.Command)
+}
+
+// execSmartResumeOrShell is the fallback for a pane with no resume hook: when
+// resume.json enables smart resume and one of its rules matches the pane's
+// saved command and directory, the rule's command is exec'd exactly as a hook
+// would be; otherwise the pane gets its bare $SHELL.
+func execSmartResumeOrShell(cfg hydrateConfig) {
+	if cfg.ResumeRules.Enabled {
+		if rule, ok := cfg.ResumeRules.Match(cfg.Command, hooks.SplitCommandLine(cfg.CommandLine), cfg.Dir); ok {
+			cfg.Logger.Debug("smart resume", "hook_key", cfg.HookKey, "command", cfg.Command, "result", "hit")
+			execHookChain(cfg, rule.Resume)
+			return
+		}
+	}
+	execShellAndExit(cfg)
+}
+
+// execHookChain execs `/bin/sh -c '<command>; exec $SHELL'`, so the pane runs
+// command and lands in the user's shell once it exits. The saved command line,
+// when known, is exported as PORTAL_COMMAND_LINE so a hook or rule can re-run
+// exactly what the pane was running (`eval "$PORTAL_COMMAND_LINE"`).
+func execHookChain(cfg hydrateConfig, command string) {
+	if cfg.CommandLine != "" {
+		_ = os.Setenv("PORTAL_COMMAND_LINE", cfg.CommandLine)
+	}
 	shell := resolveShell()
 	chained := command + "; exec " + shell
 	args := []string{"sh", "-c", chained}
@@ -324,6 +365,22 @@ func execShellOrHookAndExit(cfg hydrateConfig) {
 	cfg.ExecShell("/bin/sh", args)
 }
 
+// resumePane describes a pane to hooks.LookupResume from its saved hook
+// key, working directory, session directory and foreground command. The
+// directories are canonicalised the way project hook keys are, so a pane
+// whose saved CWD sits anywhere under a project picks up that project's
+// resume hooks. The session directory stands in for @portal-dir, which does
+// not survive the reboot a restore follows.
+func resumePane(hookKey, dir, sessionDir, command string) hooks.ResumePane {
+	pane := hooks.ResumePane{HookKey: hookKey, Command: command}
+	for _, d := range []string{dir, sessionDir} {
+		if d != "" {
+			pane.Dirs = append(pane.Dirs, project.CanonicalDirKey(d))
+		}
+	}
+	return pane
+}
+
 // handleHydrateTimeout is invoked when openFIFOWithTimeout returns
 // ErrHydrateTimeout. Per spec § Fix 2 → Specific Changes → 1, the handler
 // emits the reset preamble, unlinks the FIFO, logs a warning naming the
@@ -464,8 +521,9 @@ func defaultExecShell(prog string, args []string) {
 var hydrateRunFunc = runHydrate
 
 // stateHydrateCmd is the per-pane initial command at skeleton restore time.
-// Hidden from --help; bound flags (fifo, file, hook-key) are required and the
-// command takes no positional args. The command is wired by skeleton restore
+// Hidden from --help; bound flags (fifo, file, hook-key) are required, the
+// optional dir, session-dir and command flags feed project resume-hook
+// matching, and the command takes no positional args. The command is wired by skeleton restore
 // as the pane's `tmux new-window`/`split-window` shell-command argument.
 var stateHydrateCmd = &cobra.Command{
 	Use:    "hydrate",
@@ -476,6 +534,10 @@ var stateHydrateCmd = &cobra.Command{
 		fifo, _ := cmd.Flags().GetString("fifo")
 		file, _ := cmd.Flags().GetString("file")
 		hookKey, _ := cmd.Flags().GetString("hook-key")
+		dir, _ := cmd.Flags().GetString("dir")
+		sessionDir, _ := cmd.Flags().GetString("session-dir")
+		command, _ := cmd.Flags().GetString("command")
+		commandLine, _ := cmd.Flags().GetString("argv")
 
 		// loadHookStore() resolves the hooks.json path via configFilePath; a
 		// failure means the path itself could not be derived (e.g. no HOME).
@@ -483,6 +545,7 @@ var stateHydrateCmd = &cobra.Command{
 		// so swallowing the error here trades a missing hook for an exec'd
 		// shell — better than failing closed in the per-pane helper.
 		store, _ := loadHookStore()
+		rules := loadResumeRules()
 
 		// Diagnostics (timeouts, file-missing, marker-unset failures) land in
 		// the central log file via the handler configured once by main ->
@@ -493,10 +556,15 @@ var stateHydrateCmd = &cobra.Command{
 			FIFO:              fifo,
 			File:              file,
 			HookKey:           hookKey,
+			Dir:               dir,
+			SessionDir:        sessionDir,
+			Command:           command,
+			CommandLine:       commandLine,
 			Stdout:            cmd.OutOrStdout(),
 			Client:            tmux.DefaultClient(),
 			Logger:            hydrateLogger,
 			HookStore:         store,
+			ResumeRules:       rules,
 			ExecShell:         defaultExecShell,
 			OpenFIFO:          openFIFOWithTimeout,
 			HandleFileMissing: handleHydrateFileMissing,
@@ -510,6 +578,10 @@ func init() {
 	stateHydrateCmd.Flags().String("fifo", "", "Absolute path to the per-pane FIFO")
 	stateHydrateCmd.Flags().String("file", "", "Absolute path to the saved scrollback file")
 	stateHydrateCmd.Flags().String("hook-key", "", "Saved structural identifier (<session>:<window>.<pane>)")
+	stateHydrateCmd.Flags().String("dir", "", "Saved working directory of the pane")
+	stateHydrateCmd.Flags().String("session-dir", "", "Saved directory of the pane's session, when it differs from --dir")
+	stateHydrateCmd.Flags().String("command", "", "Saved foreground command of the pane")
+	stateHydrateCmd.Flags().String("argv", "", "Saved full command line of the pane's foreground process, shell-quoted")
 	_ = stateHydrateCmd.MarkFlagRequired("fifo")
 	_ = stateHydrateCmd.MarkFlagRequired("file")
 	_ = stateHydrateCmd.MarkFlagRequired("hook-key")
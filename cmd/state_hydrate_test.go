@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/leeovery/portal/internal/hooks"
+	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 )
@@ -1204,7 +1205,7 @@ func TestHydrate_TimeoutHandler_OrderingAndTimingInvariants(t *testing.T) {
 
 // seedHookStore writes a hooks.json containing the given map and returns a
 // *hooks.Store pointing at it. Used by hook-firing tests to drive
-// LookupOnResume against a real on-disk store.
+// LookupResume against a real on-disk store.
 func seedHookStore(t *testing.T, dir string, contents map[string]map[string]string) *hooks.Store {
 	t.Helper()
 	path := filepath.Join(dir, "hooks.json")
@@ -1255,6 +1256,121 @@ func TestHydrate_SignalArrived_ExecsHookChainWhenHookRegistered(t *testing.T) {
 	}
 }
 
+// A pane with no hook of its own resumes its project's hook for the command
+// it was running, matched through its saved --dir; a pane running something
+// else in the same project gets the bare shell.
+func TestHydrate_ExecsProjectResumeHookForMatchingCommand(t *testing.T) {
+	dir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("SHELL", "/bin/zsh")
+	store := seedHookStore(t, dir, map[string]map[string]string{
+		project.CanonicalDirKey(projectDir): {hooks.ResumeEvent("npm"): "npm run dev"},
+	})
+
+	run := func(command string) *stubExecShell {
+		exec := &stubExecShell{}
+		execShellOrHookAndExit(hydrateConfig{
+			HookKey:    "work:0.1",
+			Dir:        filepath.Join(projectDir, "src"),
+			SessionDir: projectDir,
+			Command:    command,
+			HookStore:  store,
+			ExecShell:  exec.fn(),
+		})
+		return exec
+	}
+
+	if got, want := run("npm").args, []string{"sh", "-c", "npm run dev; exec /bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("npm pane ExecShell args = %#v, want %#v", got, want)
+	}
+	if got, want := run("vim").args, []string{"/bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vim pane ExecShell args = %#v, want %#v", got, want)
+	}
+}
+
+// With smart resume enabled a pane with no hook resumes through the first
+// matching rule; a hook still wins, and a disabled rule set is ignored.
+func TestHydrate_SmartResumeFallsBackToRules(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	store := seedHookStore(t, t.TempDir(), map[string]map[string]string{
+		"work:0.0": {"on-resume": "claude --resume abc"},
+	})
+	enabled := hooks.ResumeRules{Enabled: true}
+
+	run := func(hookKey string, rules hooks.ResumeRules) []string {
+		exec := &stubExecShell{}
+		execShellOrHookAndExit(hydrateConfig{
+			HookKey:     hookKey,
+			Dir:         "/tmp",
+			Command:     "claude",
+			HookStore:   store,
+			ResumeRules: rules,
+			ExecShell:   exec.fn(),
+		})
+		return exec.args
+	}
+
+	if got, want := run("work:0.1", enabled), []string{"sh", "-c", "claude --continue; exec /bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rule pane ExecShell args = %#v, want %#v", got, want)
+	}
+	if got, want := run("work:0.0", enabled), []string{"sh", "-c", "claude --resume abc; exec /bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hooked pane ExecShell args = %#v, want %#v", got, want)
+	}
+	if got, want := run("work:0.1", hooks.ResumeRules{}), []string{"/bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("disabled ExecShell args = %#v, want %#v", got, want)
+	}
+}
+
+// A rule that needs flags is matched against the argv split back out of the
+// saved command line: a following tail resumes, a one-shot one does not.
+func TestHydrate_SmartResumeMatchesRuleArgsAgainstCommandLine(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	t.Setenv("PORTAL_COMMAND_LINE", "")
+
+	run := func(commandLine string) []string {
+		exec := &stubExecShell{}
+		execShellOrHookAndExit(hydrateConfig{
+			HookKey:     "work:0.0",
+			Command:     "tail",
+			CommandLine: commandLine,
+			ResumeRules: hooks.ResumeRules{Enabled: true},
+			ExecShell:   exec.fn(),
+		})
+		return exec.args
+	}
+
+	if got, want := run("tail -f 'app log.txt'"), []string{"sh", "-c", `eval "$PORTAL_COMMAND_LINE"; exec /bin/zsh`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tail -f ExecShell args = %#v, want %#v", got, want)
+	}
+	if got, want := run("tail app.log"), []string{"/bin/zsh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("one-shot tail ExecShell args = %#v, want %#v", got, want)
+	}
+}
+
+func TestHydrate_ExportsSavedCommandLineToResumeCommand(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	t.Setenv("PORTAL_COMMAND_LINE", "")
+	store := seedHookStore(t, t.TempDir(), map[string]map[string]string{
+		"work:0.0": {"on-resume": `eval "$PORTAL_COMMAND_LINE"`},
+	})
+
+	exec := &stubExecShell{}
+	execShellOrHookAndExit(hydrateConfig{
+		HookKey:     "work:0.0",
+		Command:     "node",
+		CommandLine: "node server.js --port 3000",
+		HookStore:   store,
+		ExecShell:   exec.fn(),
+	})
+
+	if want := []string{"sh", "-c", `eval "$PORTAL_COMMAND_LINE"; exec /bin/zsh`}; !reflect.DeepEqual(exec.args, want) {
+		t.Errorf("ExecShell args = %#v, want %#v", exec.args, want)
+	}
+	if got := os.Getenv("PORTAL_COMMAND_LINE"); got != "node server.js --port 3000" {
+		t.Errorf("PORTAL_COMMAND_LINE = %q, want the saved command line", got)
+	}
+}
+
 func TestHydrate_SignalArrived_ExecsBareShellWhenNoHookRegistered(t *testing.T) {
 	dir := t.TempDir()
 	fifo := makeFIFO(t, dir, "hydrate-nohook__0.0.fifo")
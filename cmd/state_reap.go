@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/control"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// The idle session reaper. An opt-in daemon maintenance gate (maybeRunIdleReap,
+// on the tick's idle branch beside the cleanup and snapshot gates) that kills
+// detached sessions nobody has looked at or heard from in a configured
+// duration. "Looked at" and typed into come from tmux itself — the session's
+// session_last_attached and session_activity times — plus an attached client
+// at any check, since a session that stays attached never re-stamps its
+// attach time. "Heard from" is the scrollback HashMap: a session is idle while
+// the content hash of every one of its panes stays put across the daemon's
+// captures. Before each round of kills the committed state is frozen into a
+// snapshot, and the kills themselves go through tmux, so session-closed's
+// commit-now leaves each reaped session dormant — recoverable from either.
+//
+// Configuration lives with the restore policy's: the idle threshold and the
+// exempt tags in prefs.json, a project's own exemption in projects.json (see
+// `portal reap`). Both are re-read every round, so a change takes effect
+// within reapInterval without a daemon restart.
+
+// reapInterval is how often the reaper samples the sessions. Idle durations are
+// hours or days, so a minute's sampling granularity is immaterial.
+const reapInterval = 1 * time.Minute
+
+// reapConfig is the reaper's configuration for one round. A zero IdleAfter
+// disables it. ExemptTags are canonical project tags.
+type reapConfig struct {
+	IdleAfter  time.Duration
+	ExemptTags []string
+}
+
+// loadReapConfig reads the reaper settings from prefs.json. Unreadable prefs
+// leave the reaper off for the round: failing closed is the only safe reading
+// of config the reaper cannot see.
+func loadReapConfig() reapConfig {
+	store, err := loadPrefsStore()
+	if err != nil {
+		return reapConfig{}
+	}
+	settings, err := store.LoadReap()
+	if err != nil {
+		return reapConfig{}
+	}
+	cfg := reapConfig{IdleAfter: settings.IdleAfter()}
+	for _, raw := range settings.ExemptTags {
+		if tag, ok := project.NormaliseTag(raw); ok {
+			cfg.ExemptTags = append(cfg.ExemptTags, tag)
+		}
+	}
+	return cfg
+}
+
+// idleMark is when the daemon last saw a session attached or its output
+// change, and the pane-hash signature it had then.
+type idleMark struct {
+	signature string
+	since     time.Time
+}
+
+// idleTracker follows what tmux's own times cannot tell the reaper: a client
+// that stays attached, and pane output. It is in-memory only: a daemon
+// restart starts every mark afresh, which can only ever delay a reap.
+type idleTracker map[string]idleMark
+
+// observe records one sample and returns the detached sessions idle for at
+// least idleAfter, with how long each has been idle. A session's idle clock
+// runs from the latest of its tracker mark, its tmux activity time and its
+// tmux last-attach time. The mark restarts when the session is new to the
+// tracker, has a client attached, or its panes' committed scrollback hashes
+// differ from the last sample. Sessions no longer running are forgotten.
+func (t idleTracker) observe(now time.Time, sessions []tmux.Session, times map[string]tmux.SessionTimes, idx *state.Index, hm state.HashMap, idleAfter time.Duration) map[string]time.Duration {
+	live := make(map[string]bool, len(sessions))
+	idle := map[string]time.Duration{}
+	for _, s := range sessions {
+		live[s.Name] = true
+		sig := paneHashSignature(s.Name, idx, hm)
+		mark, ok := t[s.Name]
+		if !ok || s.Attached || mark.signature != sig {
+			mark = idleMark{signature: sig, since: now}
+			t[s.Name] = mark
+		}
+		if s.Attached {
+			continue
+		}
+		since := mark.since
+		if st, ok := times[s.Name]; ok {
+			since = latest(since, st.Activity, st.LastAttached)
+		}
+		if now.Sub(since) >= idleAfter {
+			idle[s.Name] = now.Sub(since)
+		}
+	}
+	for name := range t {
+		if !live[name] {
+			delete(t, name)
+		}
+	}
+	return idle
+}
+
+// latest returns the latest of ts.
+func latest(ts ...time.Time) time.Time {
+	var l time.Time
+	for _, t := range ts {
+		if t.After(l) {
+			l = t
+		}
+	}
+	return l
+}
+
+// paneHashSignature joins the committed scrollback hashes of every pane the
+// index records for session, in index order. It changes whenever any pane's
+// output does, or when a pane comes or goes.
+func paneHashSignature(session string, idx *state.Index, hm state.HashMap) string {
+	if idx == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, s := range idx.Sessions {
+		if s.Name != session || s.Dormant {
+			continue
+		}
+		for _, w := range s.Windows {
+			for _, p := range w.Panes {
+				key := state.SanitizePaneKey(session, w.Index, p.Index)
+				fmt.Fprintf(&b, "%s=%x;", key, hm[key])
+			}
+		}
+	}
+	return b.String()
+}
+
+// reapClient is the slice of *tmux.Client the reaper drives.
+type reapClient interface {
+	ListSessions() ([]tmux.Session, error)
+	ListSessionTimes() (map[string]tmux.SessionTimes, error)
+	KillSession(name string) error
+}
+
+// maybeRunIdleReap is the throttled gate for the idle reaper, in the cleanup
+// gates' shape: disabled-guard → throttle-check → best-effort body → reset the
+// anchor AFTER the body. The configuration is loaded after the throttle, so
+// prefs.json is read once a round rather than once a tick; turning the reaper
+// on or off logs one INFO.
+func maybeRunIdleReap(deps *daemonDeps) {
+	if deps.Reap == nil {
+		return
+	}
+	if time.Since(deps.lastReap) < reapInterval {
+		return
+	}
+	cfg := deps.Reap()
+	if cfg.IdleAfter != deps.reapIdle {
+		if cfg.IdleAfter == 0 {
+			reapLogger.Info("idle reaper disabled")
+		} else {
+			reapLogger.Info("idle reaper enabled", "idle", cfg.IdleAfter.String(), "exempt_tags", strings.Join(cfg.ExemptTags, ","))
+		}
+		deps.reapIdle = cfg.IdleAfter
+	}
+	if cfg.IdleAfter == 0 {
+		deps.idle = nil
+		deps.lastReap = time.Now()
+		return
+	}
+	if deps.idle == nil {
+		deps.idle = idleTracker{}
+	}
+	runIdleReap(deps.Client, deps, cfg, time.Now(), reapLogger)
+	deps.lastReap = time.Now()
+}
+
+// runIdleReap samples the running sessions, and when any detached session
+// has been idle past the threshold and is not exempt, snapshots the committed
+// state and kills each one, logging one INFO per reap. A failed snapshot
+// reaps nothing: no session is killed without its recovery point. Every
+// failure is logged and swallowed, as in the other daemon gates.
+func runIdleReap(client reapClient, deps *daemonDeps, cfg reapConfig, now time.Time, logger *slog.Logger) {
+	sessions, err := client.ListSessions()
+	if err != nil {
+		logger.Warn("list sessions failed", "error", err)
+		return
+	}
+	times, err := client.ListSessionTimes()
+	if err != nil {
+		logger.Warn("list session times failed", "error", err)
+		return
+	}
+	idle := deps.idle.observe(now, sessions, times, deps.PrevIndex, deps.HashMap, cfg.IdleAfter)
+	if len(idle) == 0 {
+		return
+	}
+
+	exempt := reapExemption(cfg, deps.ProjectStore)
+	var victims []string
+	for _, s := range sessions {
+		if s.Dir == "" {
+			s.Dir = savedSessionDir(deps.PrevIndex, s.Name)
+		}
+		if _, ok := idle[s.Name]; ok && !exempt(s) {
+			victims = append(victims, s.Name)
+		}
+	}
+	if len(victims) == 0 {
+		return
+	}
+
+	snapshot := state.ReapSnapshotPrefix + now.Format("20060102-150405")
+	if _, err := state.SaveSnapshot(deps.Dir, snapshot); err != nil && !errors.Is(err, state.ErrSnapshotExists) {
+		logger.Warn("pre-reap snapshot failed; nothing reaped", "snapshot", snapshot, "error", err)
+		return
+	}
+	for _, name := range victims {
+		if err := client.KillSession(name); err != nil {
+			logger.Warn("reap kill failed", "session", name, "error", err)
+			continue
+		}
+		delete(deps.idle, name)
+		deps.publish(control.EventReaped, control.ReapedEvent{Session: name, Snapshot: snapshot})
+		logger.Info("session reaped", "session", name, "idle", idle[name].Truncate(time.Minute).String(), "snapshot", snapshot)
+	}
+}
+
+// savedSessionDir stands in for a missing @portal-dir stamp (a session Portal
+// did not create carries none) with the saved session's project directory,
+// so its project exemptions still apply.
+func savedSessionDir(idx *state.Index, session string) string {
+	if idx == nil {
+		return ""
+	}
+	for _, s := range idx.Sessions {
+		if s.Name == session {
+			return state.ProjectDir(s)
+		}
+	}
+	return ""
+}
+
+// reapExemption returns the exemption test for one reap round. A session is
+// spared when the project containing its directory is marked exempt in
+// projects.json, or carries one of cfg's exempt tags. projects.json is loaded
+// once per round; when it cannot be read, every session is treated as exempt
+// rather than risk reaping one the user protected.
+func reapExemption(cfg reapConfig, store *project.Store) func(tmux.Session) bool {
+	if store == nil {
+		return func(tmux.Session) bool { return true }
+	}
+	projects, err := store.Load()
+	if err != nil {
+		return func(tmux.Session) bool { return true }
+	}
+	idx := project.NewIndex(projects)
+	return func(s tmux.Session) bool {
+		if s.Dir == "" {
+			return false
+		}
+		if idx.ReapExempt(s.Dir) {
+			return true
+		}
+		for _, tag := range idx.Tags(s.Dir) {
+			if slices.Contains(cfg.ExemptTags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// fakeReapClient serves a canned session list and records kills.
+type fakeReapClient struct {
+	sessions []tmux.Session
+	times    map[string]tmux.SessionTimes
+	killed   []string
+	killErr  error
+}
+
+func (f *fakeReapClient) ListSessions() ([]tmux.Session, error) { return f.sessions, nil }
+
+func (f *fakeReapClient) ListSessionTimes() (map[string]tmux.SessionTimes, error) {
+	return f.times, nil
+}
+
+func (f *fakeReapClient) KillSession(name string) error {
+	f.killed = append(f.killed, name)
+	return f.killErr
+}
+
+func TestLoadReapConfig(t *testing.T) {
+	t.Run("no prefs is disabled", func(t *testing.T) {
+		t.Setenv("PORTAL_PREFS_FILE", filepath.Join(t.TempDir(), "prefs.json"))
+		if got := loadReapConfig(); got.IdleAfter != 0 {
+			t.Errorf("IdleAfter = %v, want disabled", got.IdleAfter)
+		}
+	})
+
+	t.Run("reads the idle duration and normalised exempt tags", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "prefs.json")
+		t.Setenv("PORTAL_PREFS_FILE", file)
+		if err := prefs.NewStore(file).SaveReap(prefs.ReapSettings{Idle: "3d", ExemptTags: []string{"Work", " ", "keep "}}); err != nil {
+			t.Fatal(err)
+		}
+		got := loadReapConfig()
+		if got.IdleAfter != 72*time.Hour || !slices.Equal(got.ExemptTags, []string{"Work", "keep"}) {
+			t.Errorf("config = %+v", got)
+		}
+	})
+}
+
+func TestIdleTracker_Observe(t *testing.T) {
+	idx := &state.Index{Sessions: []state.Session{{Name: "api", Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0}}}}}}}
+	key := state.SanitizePaneKey("api", 0, 0)
+	hm := state.HashMap{key: 1}
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	detached := []tmux.Session{{Name: "api"}}
+	tr := idleTracker{}
+
+	old := map[string]tmux.SessionTimes{"api": {Activity: start.Add(-48 * time.Hour), LastAttached: start.Add(-48 * time.Hour)}}
+
+	if idle := tr.observe(start, detached, old, idx, hm, time.Hour); len(idle) != 0 {
+		t.Fatalf("first sight reported idle: %v", idle)
+	}
+	if idle := tr.observe(start.Add(time.Hour), detached, old, idx, hm, time.Hour); idle["api"] != time.Hour {
+		t.Fatalf("unchanged for the threshold: idle = %v, want api for 1h", idle)
+	}
+
+	hm[key] = 2
+	if idle := tr.observe(start.Add(2*time.Hour), detached, old, idx, hm, time.Hour); len(idle) != 0 {
+		t.Errorf("new output still idle: %v", idle)
+	}
+	if idle := tr.observe(start.Add(4*time.Hour), []tmux.Session{{Name: "api", Attached: true}}, old, idx, hm, time.Hour); len(idle) != 0 {
+		t.Errorf("attached session reported idle: %v", idle)
+	}
+	if idle := tr.observe(start.Add(4*time.Hour+30*time.Minute), detached, old, idx, hm, time.Hour); len(idle) != 0 {
+		t.Errorf("attach did not restart the clock: %v", idle)
+	}
+
+	// An attach or keystroke between samples shows only in tmux's times.
+	recent := map[string]tmux.SessionTimes{"api": {Activity: start.Add(5 * time.Hour), LastAttached: start.Add(5*time.Hour + 20*time.Minute)}}
+	if idle := tr.observe(start.Add(6*time.Hour), detached, recent, idx, hm, time.Hour); len(idle) != 0 {
+		t.Errorf("a recent tmux attach did not restart the clock: %v", idle)
+	}
+	if idle := tr.observe(start.Add(6*time.Hour+20*time.Minute), detached, recent, idx, hm, time.Hour); idle["api"] != time.Hour {
+		t.Errorf("idle = %v, want api idle for 1h since its last attach", idle)
+	}
+
+	tr.observe(start.Add(7*time.Hour), nil, old, idx, hm, time.Hour)
+	if len(tr) != 0 {
+		t.Errorf("tracker kept a session that is gone: %v", tr)
+	}
+}
+
+// reapDeps returns daemon deps over a seeded state dir in which every named
+// session has been idle for two hours against a one-hour threshold.
+func reapDeps(t *testing.T, now time.Time, sessions ...string) *daemonDeps {
+	t.Helper()
+	dir := seedSnapshotStateDir(t, sessions...)
+	idx, _, err := state.ReadIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deps := &daemonDeps{Dir: dir, PrevIndex: &idx, HashMap: state.HashMap{}, idle: idleTracker{}}
+	deps.ProjectStore = project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+	for _, name := range sessions {
+		deps.idle[name] = idleMark{signature: paneHashSignature(name, &idx, deps.HashMap), since: now.Add(-2 * time.Hour)}
+	}
+	return deps
+}
+
+func TestRunIdleReap(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	t.Run("snapshots then kills idle detached sessions", func(t *testing.T) {
+		deps := reapDeps(t, now, "api", "web", "db")
+		client := &fakeReapClient{sessions: []tmux.Session{{Name: "api"}, {Name: "web", Attached: true}, {Name: "db"}}}
+		var buf bytes.Buffer
+
+		runIdleReap(client, deps, reapConfig{IdleAfter: time.Hour}, now, slog.New(slog.NewTextHandler(&buf, nil)))
+
+		if !slices.Equal(client.killed, []string{"api", "db"}) {
+			t.Errorf("killed %v, want api and db", client.killed)
+		}
+		snap := state.ReapSnapshotPrefix + "20261017-120000"
+		if _, err := os.Stat(state.SnapshotDir(deps.Dir, snap)); err != nil {
+			t.Errorf("pre-reap snapshot %s missing: %v", snap, err)
+		}
+		if n := strings.Count(buf.String(), "session reaped"); n != 2 || !strings.Contains(buf.String(), "idle=2h0m0s") {
+			t.Errorf("want one breadcrumb per reap with its idle time:\n%s", buf.String())
+		}
+	})
+
+	t.Run("exempt tags and projects are spared", func(t *testing.T) {
+		deps := reapDeps(t, now, "api", "web", "db", "ops")
+		tagged, exempt, plain := t.TempDir(), t.TempDir(), t.TempDir()
+		if err := os.Mkdir(filepath.Join(exempt, "sub"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		store, file := seedProjectsJSON(t, tagged)
+		body := `{"projects":[{"path":"` + tagged + `","name":"p","tags":["keep"]},{"path":"` + exempt + `","name":"e","reap_exempt":true}]}`
+		if err := os.WriteFile(file, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		deps.ProjectStore = store
+		cfg := reapConfig{IdleAfter: time.Hour, ExemptTags: []string{"keep"}}
+		client := &fakeReapClient{sessions: []tmux.Session{{Name: "api", Dir: tagged}, {Name: "web", Dir: exempt}, {Name: "db", Dir: filepath.Join(exempt, "sub")}, {Name: "ops", Dir: plain}}}
+
+		runIdleReap(client, deps, cfg, now, discardDaemonLogger())
+
+		if !slices.Equal(client.killed, []string{"ops"}) {
+			t.Errorf("killed %v, want only ops", client.killed)
+		}
+	})
+
+	t.Run("an unreadable project store spares everything", func(t *testing.T) {
+		deps := reapDeps(t, now, "api")
+		deps.ProjectStore = nil
+		client := &fakeReapClient{sessions: []tmux.Session{{Name: "api"}}}
+
+		runIdleReap(client, deps, reapConfig{IdleAfter: time.Hour}, now, discardDaemonLogger())
+
+		if len(client.killed) != 0 {
+			t.Errorf("killed %v with exemptions unresolvable", client.killed)
+		}
+	})
+
+	t.Run("a failed snapshot reaps nothing", func(t *testing.T) {
+		deps := reapDeps(t, now, "api")
+		if err := os.Remove(state.SessionsJSON(deps.Dir)); err != nil {
+			t.Fatal(err)
+		}
+		client := &fakeReapClient{sessions: []tmux.Session{{Name: "api"}}}
+		var buf bytes.Buffer
+
+		runIdleReap(client, deps, reapConfig{IdleAfter: time.Hour}, now, slog.New(slog.NewTextHandler(&buf, nil)))
+
+		if len(client.killed) != 0 || !strings.Contains(buf.String(), "nothing reaped") {
+			t.Errorf("killed %v, log = %q", client.killed, buf.String())
+		}
+	})
+
+	t.Run("a failed kill keeps the session tracked", func(t *testing.T) {
+		deps := reapDeps(t, now, "api")
+		client := &fakeReapClient{sessions: []tmux.Session{{Name: "api"}}, killErr: errors.New("gone")}
+
+		runIdleReap(client, deps, reapConfig{IdleAfter: time.Hour}, now, discardDaemonLogger())
+
+		if _, ok := deps.idle["api"]; !ok {
+			t.Error("a session whose kill failed was dropped from the tracker")
+		}
+	})
+}
+
+func TestMaybeRunIdleReap_DisabledIsNoOp(t *testing.T) {
+	deps := &daemonDeps{}
+	maybeRunIdleReap(deps)
+	if deps.idle != nil || !deps.lastReap.IsZero() {
+		t.Error("disabled reaper touched its state")
+	}
+
+	deps.Reap = func() reapConfig { return reapConfig{} }
+	maybeRunIdleReap(deps)
+	if deps.idle != nil {
+		t.Error("a round with the reaper off started tracking sessions")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/leeovery/portal/internal/hooks"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/spf13/cobra"
@@ -100,7 +101,8 @@ var signalHydrateRunFunc = runSignalHydrate
 
 // stateSignalHydrateCmd is invoked by client-attached / client-session-changed
 // hooks. It enumerates panes in the named session and signals any with a
-// pending skeleton marker via their FIFO. Hidden from --help.
+// pending skeleton marker via their FIFO, then runs the session's on-attach
+// hooks. Hidden from --help.
 var stateSignalHydrateCmd = &cobra.Command{
 	Use:    "signal-hydrate <session-name>",
 	Short:  "Signal hydrate helpers for the named session (internal)",
@@ -124,14 +126,25 @@ var stateSignalHydrateCmd = &cobra.Command{
 		// (Subsystem prefix taxonomy), matching EagerSignalHydrate. The
 		// command's process_role stays `hydrate` (argv-resolved binary) —
 		// orthogonal to the subsystem component.
+		client := tmux.DefaultClient()
 		cfg := signalHydrateConfig{
 			Session:  sessionName,
 			StateDir: dir,
-			Client:   tmux.DefaultClient(),
+			Client:   client,
 			Logger:   signalLogger,
 			Signaler: state.DefaultFIFOSignaler{},
 		}
-		return signalHydrateRunFunc(cfg)
+		if err := signalHydrateRunFunc(cfg); err != nil {
+			return err
+		}
+
+		// The same two tmux events mark a client arriving in the session, so
+		// on-attach hooks ride along once the panes have been signalled.
+		store, _ := loadHookStore()
+		fireHooks(store, hooks.EventAttach, func() ([]hookTarget, error) {
+			return liveHookTarget(client, sessionName)
+		})
+		return nil
 	},
 }
 
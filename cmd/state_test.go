@@ -164,6 +164,7 @@ func TestStateInternalSubcommandsAcceptValidArgv(t *testing.T) {
 		{name: "signal-hydrate with session name", args: []string{"state", "signal-hydrate", "foo"}},
 		{name: "hydrate with all required flags", args: []string{"state", "hydrate", "--fifo", "/tmp/f", "--file", "/tmp/s", "--hook-key", "k:0.0"}},
 		{name: "migrate-rename with old and new", args: []string{"state", "migrate-rename", "old", "new"}},
+		{name: "fire-hooks with event and session name", args: []string{"state", "fire-hooks", "on-detach", "foo"}},
 	}
 
 	for _, tt := range tests {
@@ -256,7 +257,7 @@ func TestStateHydrateRequiresAllFlags(t *testing.T) {
 	}
 }
 
-// stateChildCommands is the canonical list of the six hidden `state` children,
+// stateChildCommands is the canonical list of the seven hidden `state` children,
 // referenced by their package-level command vars so a rename or a dropped
 // registration is a compile error rather than a silent miss.
 var stateChildCommands = []*cobra.Command{
@@ -266,6 +267,7 @@ var stateChildCommands = []*cobra.Command{
 	stateNotifyCmd,
 	stateCommitNowCmd,
 	stateMigrateRenameCmd,
+	stateFireHooksCmd,
 }
 
 // TestStateParentIsHidden locks the parent stateCmd as Hidden so the entire
@@ -283,7 +285,7 @@ func TestStateParentIsHidden(t *testing.T) {
 }
 
 func TestStateHiddenSubcommandsAreHidden(t *testing.T) {
-	t.Run("each of the six child command vars is Hidden", func(t *testing.T) {
+	t.Run("each of the seven child command vars is Hidden", func(t *testing.T) {
 		for _, c := range stateChildCommands {
 			if !c.Hidden {
 				t.Errorf("state child %q must have Hidden=true", c.Name())
@@ -292,7 +294,7 @@ func TestStateHiddenSubcommandsAreHidden(t *testing.T) {
 	})
 
 	// Every registered child must be hidden plumbing. Iterating the live child
-	// set (which contains only the six real children — cobra adds no help /
+	// set (which contains only the seven real children — cobra adds no help /
 	// completion command under a subcommand) means a future child added without
 	// Hidden fails loudly here.
 	t.Run("every registered state child is Hidden", func(t *testing.T) {
@@ -313,7 +315,7 @@ func TestStateHiddenSubcommandsAreHidden(t *testing.T) {
 // The daemon, the hydrate helpers, and reboot hook-firing all invoke these by
 // argv, so this invariant is load-bearing.
 func TestStateChildrenRemainInvocableByArgv(t *testing.T) {
-	names := []string{"daemon", "hydrate", "signal-hydrate", "notify", "commit-now", "migrate-rename"}
+	names := []string{"daemon", "hydrate", "signal-hydrate", "notify", "commit-now", "migrate-rename", "fire-hooks"}
 	for _, name := range names {
 		t.Run(name+" resolves via Find", func(t *testing.T) {
 			resetRootCmd()
@@ -329,8 +331,8 @@ func TestStateChildrenRemainInvocableByArgv(t *testing.T) {
 }
 
 func TestStateHiddenSubcommandsAbsentFromShellCompletions(t *testing.T) {
-	// All six hidden children plus the parent must be gone from every shell.
-	hidden := []string{"daemon", "notify", "signal-hydrate", "hydrate", "migrate-rename", "commit-now"}
+	// All seven hidden children plus the parent must be gone from every shell.
+	hidden := []string{"daemon", "notify", "signal-hydrate", "hydrate", "migrate-rename", "commit-now", "fire-hooks"}
 	// Whole-word matcher for the parent `state` entry: the completion boilerplate
 	// contains the word "statement(s)", so a bare substring check for "state"
 	// false-positives. \bstate\b matches only a standalone `state` command entry.
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/resolver"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/spf13/cobra"
+)
+
+// templateDeps holds injectable dependencies for the template commands.
+// When nil, real implementations are used.
+var templateDeps *TemplateDeps
+
+// TemplateClient is the tmux surface `template capture` reads: the structural
+// capture the daemon also uses, plus the session list that carries each
+// session's @portal-dir.
+type TemplateClient interface {
+	state.CaptureClient
+	ListSessions() ([]tmux.Session, error)
+}
+
+// TemplateDeps allows injecting dependencies for testing.
+type TemplateDeps struct {
+	Client TemplateClient
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with per-project session layout templates",
+	Long: `A layout template describes the windows, panes, layouts, working
+directories and commands of the sessions Portal creates for a project. It is
+read from .portal.yml (or .portal.yaml) in the project root, or from the
+project's "template" entry in projects.json, which takes precedence.
+
+A .portal.yml comes with the repo, so its pane commands only run once you
+trust that file with "template trust"; until then, and again after every
+edit to it, its panes open as plain shells.`,
+}
+
+var templateTrustCmd = &cobra.Command{
+	Use:   "trust [project-dir]",
+	Short: "Allow a project's .portal.yml to run its pane commands",
+	Long: `Trust the .portal.yml in a remembered project's directory (default: the
+current directory), recording a hash of its contents in projects.json. Its
+pane commands run in new sessions until the file changes; an edited file must
+be trusted again.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		root, err := trustProjectTemplate(dir)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Trusted template for %s\n", root)
+		return err
+	},
+}
+
+var templateCaptureCmd = &cobra.Command{
+	Use:   "capture <session>",
+	Short: "Print a layout template derived from a running session",
+	Long: `Print a layout template derived from a running session, as YAML ready
+to save as .portal.yml. Pane directories are made relative to the session's
+project root, and panes running something other than a shell keep that
+command. With --save the template is stored on the project in projects.json
+instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := buildTemplateClient(cmd)
+		save, _ := cmd.Flags().GetBool("save")
+
+		t, root, err := captureTemplate(client, args[0])
+		if err != nil {
+			return err
+		}
+
+		w := cmd.OutOrStdout()
+		if save {
+			if err := saveProjectTemplate(root, &t); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "Saved template for %s\n", root)
+			return err
+		}
+
+		data, err := layout.Marshal(t)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	},
+}
+
+// buildTemplateClient returns the injected client, or the bootstrapped tmux
+// client from the command context.
+func buildTemplateClient(cmd *cobra.Command) TemplateClient {
+	if templateDeps != nil && templateDeps.Client != nil {
+		return templateDeps.Client
+	}
+	return tmuxClient(cmd)
+}
+
+// captureTemplate captures the live session name and derives its template,
+// returning it with the project root its directories are relative to: the
+// session's @portal-dir stamp, else the git root of its active pane.
+func captureTemplate(client TemplateClient, name string) (layout.Template, string, error) {
+	sess, root, err := captureLiveSession(client, name)
+	if err != nil {
+		return layout.Template{}, "", err
+	}
+	if root == "" {
+		root = state.SessionDir(sess)
+		if gitRoot, err := resolver.ResolveGitRoot(root, &resolver.RealCommandRunner{}); err == nil {
+			root = gitRoot
+		}
+	}
+
+	return layout.FromSession(sess, root), root, nil
+}
+
+// saveProjectTemplate stores t on the remembered project at root.
+func saveProjectTemplate(root string, t *layout.Template) error {
+	store, err := loadProjectStore()
+	if err != nil {
+		return err
+	}
+	projects, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	key := project.CanonicalDirKey(root)
+	for _, p := range projects {
+		if project.CanonicalDirKey(p.Path) == key {
+			if err := store.SetTemplate(p.Path, t); err != nil {
+				return fmt.Errorf("failed to save projects: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", project.ErrProjectNotFound, root)
+}
+
+// trustProjectTemplate trusts the .portal.yml of the remembered project at
+// dir, which may be relative or use ~ and is matched by canonical path. It
+// returns the project's stored path.
+func trustProjectTemplate(dir string) (string, error) {
+	store, err := loadProjectStore()
+	if err != nil {
+		return "", err
+	}
+	projects, err := store.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	key := project.CanonicalDirKey(resolver.NormalisePath(dir))
+	for _, p := range projects {
+		if project.CanonicalDirKey(p.Path) != key {
+			continue
+		}
+		if err := store.TrustTemplate(p.Path); err != nil {
+			return "", err
+		}
+		return p.Path, nil
+	}
+	return "", fmt.Errorf("%w: %s", project.ErrProjectNotFound, dir)
+}
+
+func init() {
+	templateCaptureCmd.Flags().Bool("save", false, "Store the template on the project in projects.json instead of printing it")
+	templateCmd.AddCommand(templateCaptureCmd)
+	templateCmd.AddCommand(templateTrustCmd)
+	rootCmd.AddCommand(templateCmd)
+}
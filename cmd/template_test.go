@@ -0,0 +1,158 @@
+// Tests in this file mutate package-level state and MUST NOT use t.Parallel.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// fakeTemplateClient serves one live session "app" rooted at root: an editor
+// pane beside a shell in web/, with a second window tailing logs.
+type fakeTemplateClient struct {
+	root string
+}
+
+func (f *fakeTemplateClient) ListSessionNames() ([]string, error) { return []string{"app"}, nil }
+
+func (f *fakeTemplateClient) ListAllPanesWithFormat(string) (string, error) {
+	return strings.Join([]string{
+		"app|||1|||code|||b25d,80x24,0,0{40x24,0,0,1,39x24,41,0,2}|||0|||1|||1|||" + f.root + "|||1|||nvim|||id1||||||",
+		"app|||1|||code|||b25d,80x24,0,0{40x24,0,0,1,39x24,41,0,2}|||0|||1|||2|||" + filepath.Join(f.root, "web") + "|||0|||zsh|||id1||||||",
+		"app|||2|||logs|||c1a0,80x24,0,0,3|||0|||0|||1|||" + f.root + "|||1|||tail|||id1||||||",
+	}, "\n"), nil
+}
+
+func (f *fakeTemplateClient) ShowEnvironment(string) (string, error) { return "", nil }
+
+func (f *fakeTemplateClient) ListSessions() ([]tmux.Session, error) {
+	return []tmux.Session{{Name: "app", Windows: 2, Dir: f.root}}, nil
+}
+
+func runTemplateCapture(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	resetRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs(append([]string{"template", "capture"}, args...))
+	err := rootCmd.Execute()
+	return buf.String(), err
+}
+
+func TestTemplateCaptureCommand(t *testing.T) {
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	root := t.TempDir()
+	templateDeps = &TemplateDeps{Client: &fakeTemplateClient{root: root}}
+	t.Cleanup(func() { templateDeps = nil })
+
+	want := layout.Template{Windows: []layout.Window{
+		{Name: "code", Layout: "b25d,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []layout.Pane{
+			{Command: "nvim"},
+			{Dir: "web"},
+		}},
+		{Name: "logs", Layout: "c1a0,80x24,0,0,3", Panes: []layout.Pane{
+			{Command: "tail"},
+		}},
+	}}
+
+	t.Run("prints YAML relative to the session's project root", func(t *testing.T) {
+		out, err := runTemplateCapture(t, "app")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := layout.Parse([]byte(out))
+		if err != nil {
+			t.Fatalf("output does not parse as a template: %v\n%s", err, out)
+		}
+		wantOut, _ := layout.Marshal(want)
+		if out != string(wantOut) {
+			t.Errorf("output =\n%s\nwant\n%s", out, wantOut)
+		}
+		if len(got.Windows) != 2 {
+			t.Errorf("parsed %d windows, want 2", len(got.Windows))
+		}
+	})
+
+	t.Run("--save stores it on the project in projects.json", func(t *testing.T) {
+		projectsFile := filepath.Join(t.TempDir(), "projects.json")
+		t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+		store := project.NewStore(projectsFile)
+		if err := store.Upsert(root, "app", "internal"); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := runTemplateCapture(t, "app", "--save")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if out != "Saved template for "+root+"\n" {
+			t.Errorf("output = %q", out)
+		}
+		got, err := store.Template(root)
+		if err != nil || got == nil || len(got.Windows) != 2 || got.Windows[0].Panes[0].Command != "nvim" {
+			t.Errorf("stored template = %+v, %v; want the captured one", got, err)
+		}
+	})
+
+	t.Run("--save for a directory that is not a project fails", func(t *testing.T) {
+		t.Setenv("PORTAL_PROJECTS_FILE", filepath.Join(t.TempDir(), "projects.json"))
+
+		_, err := runTemplateCapture(t, "app", "--save")
+
+		if !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+	})
+
+	t.Run("an unknown session is an error", func(t *testing.T) {
+		_, err := runTemplateCapture(t, "nope")
+
+		if err == nil || !strings.Contains(err.Error(), "No session found: nope") {
+			t.Errorf("err = %v, want a no-session error", err)
+		}
+	})
+}
+
+func TestTemplateTrustCommand(t *testing.T) {
+	bootstrapDeps = &BootstrapDeps{Orchestrator: &nopRunner{}}
+	t.Cleanup(func() { bootstrapDeps = nil })
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".portal.yml"), []byte("windows:\n  - panes:\n      - command: make dev\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	projectsFile := filepath.Join(t.TempDir(), "projects.json")
+	t.Setenv("PORTAL_PROJECTS_FILE", projectsFile)
+	store := project.NewStore(projectsFile)
+	if err := store.Upsert(root, "app", "internal"); err != nil {
+		t.Fatal(err)
+	}
+
+	resetRootCmd()
+	buf := new(bytes.Buffer)
+	rootCmd.SetOut(buf)
+	rootCmd.SetArgs([]string{"template", "trust", root})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if buf.String() != "Trusted template for "+root+"\n" {
+		t.Errorf("output = %q", buf.String())
+	}
+	got, err := store.Template(root)
+	if err != nil || got == nil || got.Windows[0].Panes[0].Command != "make dev" {
+		t.Errorf("template = %+v, %v; want the trusted file's command", got, err)
+	}
+}
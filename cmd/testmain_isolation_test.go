@@ -38,6 +38,9 @@ func TestMain(m *testing.M) {
 	os.Setenv("PORTAL_HOOKS_FILE", "/nonexistent/portal-test-must-isolate-hooks.json")
 	os.Setenv("PORTAL_PROJECTS_FILE", "/nonexistent/portal-test-must-isolate-projects.json")
 	os.Setenv("PORTAL_ALIASES_FILE", "/nonexistent/portal-test-must-isolate-aliases")
+	os.Setenv("PORTAL_RESUME_FILE", "/nonexistent/portal-test-must-isolate-resume.json")
+	os.Setenv("PORTAL_AGENT_RULES_FILE", "/nonexistent/portal-test-must-isolate-agent-rules.json")
+	os.Setenv("PORTAL_FRECENCY_FILE", "/nonexistent/portal-test-must-isolate-frecency.json")
 	// TMUX poison — the tmux-boundary counterpart of the path poisons above.
 	// Tests usually run inside the developer's real tmux, so any test that
 	// Executes a real command body whose production wiring builds
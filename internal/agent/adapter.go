@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"io"
+)
+
+// maxEventSize caps the hook payload an adapter reads. Claude Code's payloads
+// carry tool inputs, which can be large (a whole file for Write), but only a
+// few top-level fields ever matter here.
+const maxEventSize = 4 << 20
+
+// Event is one agent event normalised across agents: what the agent is doing
+// now, and the short context recorded with it.
+type Event struct {
+	// Name is the agent's own name for the event, kept for the log and
+	// agent.json.
+	Name string
+	// State is what the event says the agent is now doing.
+	State State
+	// Detail is the short human context: the tool about to run, or what a
+	// prompt asked.
+	Detail string
+	// AgentSession is the agent's own session id, when it reports one.
+	AgentSession string
+	// Ended marks the agent as exited: the pane is no longer tracked.
+	Ended bool
+}
+
+// Adapter turns one agent's raw payload into an Event. Each agent reports in
+// its own shape through its own mechanism; the adapter is the only code that
+// knows the shape, so ingest, the inbox and the table deal in Events alone.
+type Adapter interface {
+	// Name is the adapter's short name: the value `agent ingest --agent`
+	// takes and the agent recorded against each pane.
+	Name() string
+	// Parse decodes one payload. A payload the adapter cannot read, or one
+	// naming no event, is an error: there is nothing to classify.
+	Parse(r io.Reader) (Event, error)
+}
+
+// DefaultAdapter is the adapter ingest uses when its hook names none: every
+// hook installed before adapters existed was Claude Code's.
+const DefaultAdapter = "claude"
+
+// adapters is the ordered registry of hook adapters, one per agent that can
+// run `agent ingest`. Screen-rule agents are not registered here: they are
+// user-defined (see ScreenAgent) and driven by the daemon, not by ingest.
+var adapters = []Adapter{
+	claudeAdapter{},
+	codexAdapter{},
+	openCodeAdapter{},
+}
+
+// LookupAdapter returns the registered adapter called name.
+func LookupAdapter(name string) (Adapter, bool) {
+	for _, a := range adapters {
+		if a.Name() == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// AdapterNames lists the registered adapters in registry order.
+func AdapterNames() []string {
+	names := make([]string, 0, len(adapters))
+	for _, a := range adapters {
+		names = append(names, a.Name())
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package agent_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/agent"
+)
+
+func TestLookupAdapter(t *testing.T) {
+	if got := agent.AdapterNames(); !slices.Equal(got, []string{"claude", "codex", "opencode"}) {
+		t.Errorf("AdapterNames() = %v", got)
+	}
+	if _, ok := agent.LookupAdapter(agent.DefaultAdapter); !ok {
+		t.Error("the default adapter is not registered")
+	}
+	if _, ok := agent.LookupAdapter("aider"); ok {
+		t.Error("found an adapter for an agent with no hooks")
+	}
+}
+
+// parse runs the named adapter over payload.
+func parse(t *testing.T, name, payload string) (agent.Event, error) {
+	t.Helper()
+	a, ok := agent.LookupAdapter(name)
+	if !ok {
+		t.Fatalf("no %s adapter", name)
+	}
+	return a.Parse(strings.NewReader(payload))
+}
+
+func TestCodexAdapter(t *testing.T) {
+	cases := []struct {
+		name, payload string
+		want          agent.Event
+	}{
+		{"a hook event classifies like Claude Code's", `{"hook_event_name":"PreToolUse","session_id":"c1","tool_name":"shell"}`, agent.Event{Name: "PreToolUse", State: agent.Working, Detail: "shell", AgentSession: "c1"}},
+		{"a notify turn-complete is idle", `{"type":"agent-turn-complete","turn-id":"7","last-assistant-message":"Done."}`, agent.Event{Name: "agent-turn-complete", State: agent.Idle}},
+		{"another notify type is unclassified", `{"type":"approval-requested"}`, agent.Event{Name: "approval-requested", State: agent.Unknown}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parse(t, "codex", c.payload)
+			if err != nil || got != c.want {
+				t.Errorf("Parse = %+v, %v; want %+v", got, err, c.want)
+			}
+		})
+	}
+
+	if _, err := parse(t, "codex", `{"turn-id":"7"}`); err == nil {
+		t.Error("a payload naming no event parsed")
+	}
+}
+
+func TestOpenCodeAdapter(t *testing.T) {
+	cases := []struct {
+		payload string
+		want    agent.Event
+	}{
+		{`{"type":"permission.updated","properties":{"sessionID":"s","title":"Run make"}}`, agent.Event{Name: "permission.updated", State: agent.Waiting, Detail: "Run make", AgentSession: "s"}},
+		{`{"type":"permission.replied","properties":{"sessionID":"s"}}`, agent.Event{Name: "permission.replied", State: agent.Working, AgentSession: "s"}},
+		{`{"type":"tool.execute.before","properties":{"sessionID":"s","tool":"bash"}}`, agent.Event{Name: "tool.execute.before", State: agent.Working, Detail: "bash", AgentSession: "s"}},
+		{`{"type":"session.status","properties":{"sessionID":"s","status":{"type":"busy"}}}`, agent.Event{Name: "session.status", State: agent.Working, AgentSession: "s"}},
+		{`{"type":"session.idle","properties":{"sessionID":"s"}}`, agent.Event{Name: "session.idle", State: agent.Idle, AgentSession: "s"}},
+		{`{"type":"session.created","properties":{"info":{"id":"s"}}}`, agent.Event{Name: "session.created", State: agent.Idle, AgentSession: "s"}},
+		{`{"type":"session.deleted","properties":{"info":{"id":"s"}}}`, agent.Event{Name: "session.deleted", State: agent.Unknown, AgentSession: "s", Ended: true}},
+		{`{"type":"message.updated"}`, agent.Event{Name: "message.updated", State: agent.Unknown}},
+	}
+	for _, c := range cases {
+		got, err := parse(t, "opencode", c.payload)
+		if err != nil || got != c.want {
+			t.Errorf("Parse(%s) = %+v, %v; want %+v", c.payload, got, err, c.want)
+		}
+	}
+
+	if _, err := parse(t, "opencode", `{"properties":{}}`); err == nil {
+		t.Error("an event without a type parsed")
+	}
+}
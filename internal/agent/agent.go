@@ -0,0 +1,77 @@
+// Package agent tracks what the coding agents running in tmux panes are doing,
+// so the picker can show which sessions are busy and which are blocked waiting
+// on their user.
+//
+// An agent reports through its own hook mechanism: Claude Code, for one, runs
+// `portal agent ingest` on each configured hook event with the event JSON on
+// stdin and $TMUX_PANE in the environment. The agent's Adapter normalises the
+// payload into an Event, which ingest wraps in an Update — the pane's new
+// State — and posts to an inbox directory under the state directory. The
+// state daemon drains the inbox on every tick, resolves each pane id to its
+// session, and keeps the resulting Table persisted to agent.json so a daemon
+// restart picks up where the last one left off. Agents with no hooks at all
+// are read off their screens instead, by ScreenAgent rules the daemon runs.
+package agent
+
+import (
+	"time"
+)
+
+// State is what an agent in a pane is doing, normalised across agents.
+type State string
+
+const (
+	// Unknown is an agent that reported an event Portal does not classify.
+	Unknown State = "unknown"
+	// Working is an agent busy on a turn: running tools or thinking.
+	Working State = "working"
+	// Waiting is an agent blocked on its user, typically a permission prompt.
+	Waiting State = "waiting"
+	// Idle is an agent that has finished its turn and is at its prompt.
+	Idle State = "idle"
+)
+
+// Urgency ranks a state by how much it needs the user: Waiting highest, then
+// Working, Idle and Unknown. The zero State — no agent — ranks below them all,
+// so rolling several panes up to the most urgent never loses a tracked one.
+func (s State) Urgency() int {
+	switch s {
+	case Waiting:
+		return 4
+	case Working:
+		return 3
+	case Idle:
+		return 2
+	case Unknown:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Update is one normalised report about one pane, as ingest posts it and the
+// daemon applies it.
+type Update struct {
+	PaneID       string    `json:"pane_id"`
+	Agent        string    `json:"agent,omitempty"`
+	State        State     `json:"state"`
+	Event        string    `json:"event"`
+	Detail       string    `json:"detail,omitempty"`
+	AgentSession string    `json:"agent_session,omitempty"`
+	Ended        bool      `json:"ended,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// NewUpdate wraps an event the named agent reported from paneID at at.
+func NewUpdate(paneID, agentName string, e Event, at time.Time) Update {
+	return Update{
+		PaneID:       paneID,
+		Agent:        agentName,
+		State:        e.State,
+		Event:        e.Name,
+		Detail:       e.Detail,
+		AgentSession: e.AgentSession,
+		Ended:        e.Ended,
+		At:           at.UTC(),
+	}
+}
@@ -0,0 +1,74 @@
+package agent_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+)
+
+func TestParseHookEvent(t *testing.T) {
+	t.Run("reads the fields it classifies on", func(t *testing.T) {
+		e, err := agent.ParseHookEvent(strings.NewReader(`{"session_id":"s1","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e.Name != "PreToolUse" || e.SessionID != "s1" || e.ToolName != "Bash" {
+			t.Errorf("event = %+v", e)
+		}
+	})
+
+	for name, payload := range map[string]string{
+		"malformed JSON":     `{"hook_event_name":`,
+		"missing event name": `{"session_id":"s1"}`,
+		"empty input":        ``,
+	} {
+		t.Run(name+" is an error", func(t *testing.T) {
+			if _, err := agent.ParseHookEvent(strings.NewReader(payload)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestHookEvent_State(t *testing.T) {
+	cases := []struct {
+		event agent.HookEvent
+		want  agent.State
+	}{
+		{agent.HookEvent{Name: "PreToolUse"}, agent.Working},
+		{agent.HookEvent{Name: "PostToolUse"}, agent.Working},
+		{agent.HookEvent{Name: "UserPromptSubmit"}, agent.Working},
+		{agent.HookEvent{Name: "Notification", NotificationType: "permission_prompt"}, agent.Waiting},
+		{agent.HookEvent{Name: "Notification"}, agent.Waiting},
+		{agent.HookEvent{Name: "Notification", NotificationType: "idle_prompt"}, agent.Idle},
+		{agent.HookEvent{Name: "Stop"}, agent.Idle},
+		{agent.HookEvent{Name: "SessionStart"}, agent.Idle},
+		{agent.HookEvent{Name: "PreCompact"}, agent.Unknown},
+	}
+	for _, c := range cases {
+		if got := c.event.State(); got != c.want {
+			t.Errorf("%s/%s = %s, want %s", c.event.Name, c.event.NotificationType, got, c.want)
+		}
+	}
+}
+
+func TestNewUpdate(t *testing.T) {
+	at := time.Date(2026, 10, 17, 9, 0, 0, 0, time.FixedZone("X", 3600))
+
+	u := agent.NewUpdate("%4", "claude", agent.HookEvent{Name: "Notification", SessionID: "s1", Message: "Claude needs your permission to use Bash"}.Event(), at)
+	if u.PaneID != "%4" || u.Agent != "claude" || u.State != agent.Waiting || u.Detail != "Claude needs your permission to use Bash" || u.AgentSession != "s1" || u.Ended {
+		t.Errorf("update = %+v", u)
+	}
+	if !u.At.Equal(at) || u.At.Location() != time.UTC {
+		t.Errorf("At = %v, want %v in UTC", u.At, at)
+	}
+
+	if u := agent.NewUpdate("%4", "claude", agent.HookEvent{Name: "PreToolUse", ToolName: "Edit"}.Event(), at); u.Detail != "Edit" {
+		t.Errorf("PreToolUse detail = %q, want the tool name", u.Detail)
+	}
+	if u := agent.NewUpdate("%4", "claude", agent.HookEvent{Name: "SessionEnd"}.Event(), at); !u.Ended {
+		t.Error("SessionEnd did not end the pane's tracking")
+	}
+}
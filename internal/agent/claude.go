@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// HookEvent is the part of a Claude Code hook payload Portal reads. Codex's
+// hooks send the same shape.
+type HookEvent struct {
+	Name             string `json:"hook_event_name"`
+	SessionID        string `json:"session_id"`
+	ToolName         string `json:"tool_name"`
+	Message          string `json:"message"`
+	NotificationType string `json:"notification_type"`
+}
+
+// ParseHookEvent decodes a hook payload. A payload without a hook_event_name
+// is an error: there is nothing to classify.
+func ParseHookEvent(r io.Reader) (HookEvent, error) {
+	var e HookEvent
+	if err := json.NewDecoder(io.LimitReader(r, maxEventSize)).Decode(&e); err != nil {
+		return HookEvent{}, fmt.Errorf("decode hook event: %w", err)
+	}
+	if e.Name == "" {
+		return HookEvent{}, errors.New("decode hook event: missing hook_event_name")
+	}
+	return e, nil
+}
+
+// State classifies the event. A tool about to run, a tool just finished, and
+// a submitted prompt all mean the agent is mid-turn; Stop means it has handed
+// back to the user. A Notification is the agent asking for attention — a
+// permission prompt — except the idle reminder Claude Code sends after a
+// finished turn has sat unanswered, which changes nothing.
+func (e HookEvent) State() State {
+	switch e.Name {
+	case "PreToolUse", "PostToolUse", "UserPromptSubmit":
+		return Working
+	case "Notification":
+		if e.NotificationType == "idle_prompt" {
+			return Idle
+		}
+		return Waiting
+	case "Stop", "SessionStart":
+		return Idle
+	}
+	return Unknown
+}
+
+// Event normalises the hook event. SessionEnd marks it Ended: the agent has
+// exited.
+func (e HookEvent) Event() Event {
+	return Event{
+		Name:         e.Name,
+		State:        e.State(),
+		Detail:       e.detail(),
+		AgentSession: e.SessionID,
+		Ended:        e.Name == "SessionEnd",
+	}
+}
+
+// detail is the short human context recorded with the state: the tool about
+// to run, or what a notification said.
+func (e HookEvent) detail() string {
+	switch e.Name {
+	case "PreToolUse", "PostToolUse":
+		return e.ToolName
+	case "Notification":
+		return e.Message
+	}
+	return ""
+}
+
+// claudeAdapter reads Claude Code's hook payloads.
+type claudeAdapter struct{}
+
+func (claudeAdapter) Name() string { return "claude" }
+
+func (claudeAdapter) Parse(r io.Reader) (Event, error) {
+	e, err := ParseHookEvent(r)
+	if err != nil {
+		return Event{}, err
+	}
+	return e.Event(), nil
+}
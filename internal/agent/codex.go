@@ -0,0 +1,42 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// codexTurnComplete is the one event Codex's notify program is sent.
+const codexTurnComplete = "agent-turn-complete"
+
+// codexPayload is the union of the two shapes Codex reports in: its hooks
+// send Claude Code's hook payload, and its older `notify` setting sends a
+// {"type": ...} object once per finished turn.
+type codexPayload struct {
+	HookEvent
+	Type string `json:"type"`
+}
+
+// codexAdapter reads Codex's payloads. A hook event classifies exactly as
+// Claude Code's does. A notify payload can only say a turn has finished, so a
+// Codex wired through notify alone shows Idle and never Working or Waiting.
+type codexAdapter struct{}
+
+func (codexAdapter) Name() string { return "codex" }
+
+func (codexAdapter) Parse(r io.Reader) (Event, error) {
+	var p codexPayload
+	if err := json.NewDecoder(io.LimitReader(r, maxEventSize)).Decode(&p); err != nil {
+		return Event{}, fmt.Errorf("decode codex event: %w", err)
+	}
+	switch {
+	case p.Name != "":
+		return p.HookEvent.Event(), nil
+	case p.Type == codexTurnComplete:
+		return Event{Name: p.Type, State: Idle}, nil
+	case p.Type != "":
+		return Event{Name: p.Type, State: Unknown}, nil
+	}
+	return Event{}, errors.New("decode codex event: missing hook_event_name or type")
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/leeovery/portal/internal/fileutil"
+)
+
+// The inbox is a directory of one-update files. A file per update, written by
+// temp-file-and-rename, means concurrent ingests from different panes never
+// interleave and the daemon never reads a half-written update; draining is a
+// list, read and remove with no lock shared between the two sides.
+
+// InboxCap bounds the updates the inbox holds. With the daemon down nothing
+// drains it, so Post makes room by removing the oldest entries — the ones a
+// newer update for the same pane has most likely superseded anyway.
+const InboxCap = 512
+
+// Post writes u into the inbox directory, creating it if needed, first
+// pruning the inbox to below InboxCap.
+func Post(inbox string, u Update) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent update: %w", err)
+	}
+	pruneInbox(inbox, InboxCap-1)
+	name := strconv.FormatInt(u.At.UnixNano(), 10) + "-" + strconv.Itoa(os.Getpid()) + ".json"
+	return fileutil.AtomicWrite0600(filepath.Join(inbox, name), data)
+}
+
+// pruneInbox removes the oldest entries until at most keep remain. Entry names
+// lead with the update's Unix-nanosecond time, a fixed 19 digits, so name
+// order is age order. Failures are ignored: the cap is housekeeping, and a
+// prune that loses a race with Drain has nothing left to do.
+func pruneInbox(inbox string, keep int) {
+	entries, err := os.ReadDir(inbox)
+	if err != nil {
+		return
+	}
+	entries = slices.DeleteFunc(entries, func(e os.DirEntry) bool { return !isInboxEntry(e) })
+	for _, e := range entries[:max(0, len(entries)-keep)] {
+		_ = os.Remove(filepath.Join(inbox, e.Name()))
+	}
+}
+
+// isInboxEntry reports whether e is a posted update. Dot-files are
+// AtomicWrite temp files still being written.
+func isInboxEntry(e os.DirEntry) bool {
+	return !e.IsDir() && !strings.HasPrefix(e.Name(), ".") && filepath.Ext(e.Name()) == ".json"
+}
+
+// Drain removes every posted update from the inbox and returns them oldest
+// first. A missing inbox holds nothing. An entry that cannot be read or
+// decoded is removed all the same, so one bad file cannot wedge the inbox; its
+// error is joined into the returned error alongside the good updates.
+func Drain(inbox string) ([]Update, error) {
+	entries, err := os.ReadDir(inbox)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var updates []Update
+	var errs []error
+	for _, e := range entries {
+		if !isInboxEntry(e) {
+			continue
+		}
+		path := filepath.Join(inbox, e.Name())
+		data, err := os.ReadFile(path)
+		if rmErr := os.Remove(path); rmErr != nil && !errors.Is(rmErr, os.ErrNotExist) {
+			errs = append(errs, rmErr)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var u Update
+		if err := json.Unmarshal(data, &u); err != nil || u.PaneID == "" {
+			errs = append(errs, fmt.Errorf("malformed agent update %s", e.Name()))
+			continue
+		}
+		updates = append(updates, u)
+	}
+	slices.SortStableFunc(updates, func(a, b Update) int { return a.At.Compare(b.At) })
+	return updates, errors.Join(errs...)
+}
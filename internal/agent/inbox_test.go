@@ -0,0 +1,84 @@
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+)
+
+func TestInbox(t *testing.T) {
+	t.Run("drains posted updates oldest first and empties the inbox", func(t *testing.T) {
+		inbox := filepath.Join(t.TempDir(), "agent-inbox")
+		for _, u := range []agent.Update{
+			update("%1", agent.Idle, t0.Add(2*time.Second)),
+			update("%2", agent.Working, t0),
+			update("%1", agent.Working, t0.Add(time.Second)),
+		} {
+			if err := agent.Post(inbox, u); err != nil {
+				t.Fatalf("Post: %v", err)
+			}
+		}
+
+		got, err := agent.Drain(inbox)
+		if err != nil {
+			t.Fatalf("Drain: %v", err)
+		}
+		if len(got) != 3 || got[0].PaneID != "%2" || got[2].State != agent.Idle {
+			t.Errorf("drained %+v, want oldest first", got)
+		}
+		if again, _ := agent.Drain(inbox); len(again) != 0 {
+			t.Errorf("second drain returned %v", again)
+		}
+	})
+
+	t.Run("posting to a full inbox drops the oldest entries", func(t *testing.T) {
+		inbox := t.TempDir()
+		for i := range agent.InboxCap + 3 {
+			if err := agent.Post(inbox, update("%1", agent.Working, t0.Add(time.Duration(i)*time.Second))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := agent.Drain(inbox)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != agent.InboxCap || !got[0].At.Equal(t0.Add(3*time.Second)) {
+			t.Errorf("drained %d updates from %v, want %d from t0+3s", len(got), got[0].At, agent.InboxCap)
+		}
+	})
+
+	t.Run("a missing inbox drains nothing", func(t *testing.T) {
+		got, err := agent.Drain(filepath.Join(t.TempDir(), "absent"))
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, %v", got, err)
+		}
+	})
+
+	t.Run("a malformed entry is removed and reported, and in-flight temp files are left", func(t *testing.T) {
+		inbox := t.TempDir()
+		if err := agent.Post(inbox, update("%1", agent.Working, t0)); err != nil {
+			t.Fatal(err)
+		}
+		bad := filepath.Join(inbox, "1-1.json")
+		temp := filepath.Join(inbox, ".atomic-1.tmp")
+		for _, p := range []string{bad, temp} {
+			if err := os.WriteFile(p, []byte("{"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		got, err := agent.Drain(inbox)
+		if len(got) != 1 || err == nil {
+			t.Errorf("got %v, %v; want the good update and an error", got, err)
+		}
+		if _, err := os.Stat(bad); !os.IsNotExist(err) {
+			t.Error("malformed entry left in the inbox")
+		}
+		if _, err := os.Stat(temp); err != nil {
+			t.Errorf("temp file removed: %v", err)
+		}
+	})
+}
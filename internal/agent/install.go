@@ -0,0 +1,491 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/leeovery/portal/internal/fileutil"
+)
+
+// HookVersion versions the hook command Portal installs into an agent's
+// settings. It is stamped on the command itself (Integration.Command), so an
+// entry written by an older Portal reads back as stale and the next Install
+// replaces it. Bump it whenever the installed command or event set changes.
+//
+// Version 2 names the agent's adapter (--agent), which version 1 left to the
+// Claude Code default. Version 3 guards the command with `command -v portal`,
+// so an uninstalled or not-yet-on-PATH Portal costs the agent nothing.
+const HookVersion = 3
+
+// ingestFingerprint identifies a Portal-authored hook command of any version,
+// the way tmux's hook convergence recognises its run-shell entries: a command
+// containing it is Portal's to replace or remove, anything else is the user's
+// and is never touched.
+const ingestFingerprint = "portal agent ingest"
+
+// Integration is one agent whose hook configuration Portal manages: the JSON
+// settings file it reads hooks from and the hook events Portal listens on.
+// Both agents share Claude Code's schema — a "hooks" object mapping each event
+// to a list of matcher groups, each holding a list of command hooks.
+type Integration struct {
+	// Name is the short name used on the command line ("claude", "codex").
+	Name string
+	// Label is the agent's display name.
+	Label string
+	// Path is the settings file the hooks live in.
+	Path string
+	// Events are the hook events Portal installs an entry on.
+	Events []string
+	// OptIn marks an agent `agent install` sets up only when it is named, never
+	// because its config directory exists.
+	OptIn bool
+}
+
+// Integrations returns the agents Portal can install hooks for, with their
+// settings files resolved under home unless the agent's own config-dir
+// variable (read through getenv) overrides it.
+func Integrations(home string, getenv func(string) string) []Integration {
+	configDir := func(env, fallback string) string {
+		if dir := getenv(env); dir != "" {
+			return dir
+		}
+		return filepath.Join(home, fallback)
+	}
+	return []Integration{
+		{
+			Name:   "claude",
+			Label:  "Claude Code",
+			Path:   filepath.Join(configDir("CLAUDE_CONFIG_DIR", ".claude"), "settings.json"),
+			Events: []string{"SessionStart", "UserPromptSubmit", "PreToolUse", "PostToolUse", "Notification", "Stop", "SessionEnd"},
+		},
+		// Codex's hooks.json is written in Claude Code's schema, which has not
+		// been confirmed against a Codex release, so Codex is set up only on
+		// request; its notify program is the verified route.
+		{
+			Name:   "codex",
+			Label:  "Codex",
+			Path:   filepath.Join(configDir("CODEX_HOME", ".codex"), "hooks.json"),
+			Events: []string{"SessionStart", "UserPromptSubmit", "PreToolUse", "PostToolUse", "Stop"},
+			OptIn:  true,
+		},
+	}
+}
+
+// Command is the hook command Install writes for every event: ingest, told
+// which adapter reads the agent's payloads. Integration names match adapter
+// names. The command -v guard and the trailing true keep the hook a silent
+// success on a machine where portal is not on the agent's PATH.
+func (in Integration) Command() string {
+	return fmt.Sprintf("command -v portal >/dev/null && %s --agent %s --hook-version %d || true", ingestFingerprint, in.Name, HookVersion)
+}
+
+// Detected reports whether the agent's config directory exists — whether the
+// agent looks installed on this machine.
+func (in Integration) Detected() bool {
+	info, err := os.Stat(filepath.Dir(in.Path))
+	return err == nil && info.IsDir()
+}
+
+// InstallByDefault reports whether `agent install` with no agent named sets
+// this agent up: it is detected and not OptIn.
+func (in Integration) InstallByDefault() bool {
+	return !in.OptIn && in.Detected()
+}
+
+// HookStatus is how an agent's settings compare with the hooks Portal would
+// install. Each event lands in exactly one list.
+type HookStatus struct {
+	// Current holds the events carrying exactly one Portal entry, of this
+	// version.
+	Current []string `json:"current"`
+	// Stale holds the events whose Portal entries are of another version, or
+	// duplicated.
+	Stale []string `json:"stale"`
+	// Missing holds the events with no Portal entry.
+	Missing []string `json:"missing"`
+}
+
+// Installed reports whether every event carries the current entry.
+func (s HookStatus) Installed() bool {
+	return len(s.Stale) == 0 && len(s.Missing) == 0
+}
+
+// Summary is a one-word verdict: installed, stale, partial or not installed.
+// Stale wins over partial: an old entry anywhere means Install has work to do
+// beyond filling gaps.
+func (s HookStatus) Summary() string {
+	switch {
+	case s.Installed():
+		return "installed"
+	case len(s.Stale) > 0:
+		return "stale"
+	case len(s.Current) > 0:
+		return "partial"
+	default:
+		return "not installed"
+	}
+}
+
+// Status reads the settings file and classifies each event. A missing file is
+// every event missing.
+func (in Integration) Status() (HookStatus, error) {
+	f, err := readSettings(in.Path)
+	if err != nil {
+		return HookStatus{}, err
+	}
+	hooks := f.hooks
+	var st HookStatus
+	want := in.Command()
+	for _, event := range in.Events {
+		commands := portalCommands(hooks[event])
+		switch {
+		case len(commands) == 0:
+			st.Missing = append(st.Missing, event)
+		case len(commands) == 1 && commands[0] == want:
+			st.Current = append(st.Current, event)
+		default:
+			st.Stale = append(st.Stale, event)
+		}
+	}
+	return st, nil
+}
+
+// Install converges the settings file to exactly one current Portal entry per
+// event, leaving every other hook — and every other byte outside the "hooks"
+// member — as it was. An event already carrying exactly the current entry is
+// left alone, so a second Install changes nothing and rewrites nothing.
+// changed reports whether the file was written.
+func (in Integration) Install() (changed bool, err error) {
+	f, err := readSettings(in.Path)
+	if err != nil {
+		return false, err
+	}
+	hooks := f.hooks
+	want := in.Command()
+	for _, event := range in.Events {
+		commands := portalCommands(hooks[event])
+		if len(commands) == 1 && commands[0] == want {
+			continue
+		}
+		groups, _ := stripPortalHooks(hooks[event])
+		hooks[event] = append(groups, map[string]any{
+			"hooks": []any{map[string]any{"type": "command", "command": want}},
+		})
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, f.write(hooks)
+}
+
+// Uninstall removes every Portal entry from the settings file, on any event,
+// dropping matcher groups and events it leaves empty. removed counts the
+// entries taken out; a file without any is not rewritten.
+func (in Integration) Uninstall() (removed int, err error) {
+	f, err := readSettings(in.Path)
+	if err != nil {
+		return 0, err
+	}
+	hooks := f.hooks
+	for event, v := range hooks {
+		groups, n := stripPortalHooks(v)
+		if n == 0 {
+			continue
+		}
+		removed += n
+		if len(groups) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = groups
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, f.write(hooks)
+}
+
+// settingsFile is an agent's settings file as read: its bytes, kept so a write
+// can splice in a new "hooks" member and leave everything else byte for byte,
+// and its decoded "hooks" object.
+type settingsFile struct {
+	path  string
+	data  []byte
+	hooks map[string]any
+}
+
+// readSettings reads the settings file and decodes its "hooks" object. Numbers
+// stay json.Number so a rewrite cannot reformat them. A missing or blank file
+// has no hooks; anything but an object is an error, so Portal never overwrites
+// a file it does not understand.
+func readSettings(path string) (settingsFile, error) {
+	f := settingsFile{path: path, hooks: map[string]any{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return f, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var settings map[string]any
+	if err := dec.Decode(&settings); err != nil {
+		return f, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if settings == nil {
+		return f, nil
+	}
+	if f.hooks, err = hooksObject(settings, path); err != nil {
+		return f, err
+	}
+	f.data = data
+	return f, nil
+}
+
+// hooksObject returns the settings' "hooks" object, empty when absent.
+func hooksObject(settings map[string]any, path string) (map[string]any, error) {
+	v, ok := settings["hooks"]
+	if !ok || v == nil {
+		return map[string]any{}, nil
+	}
+	hooks, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("decode %s: \"hooks\" is not an object", path)
+	}
+	return hooks, nil
+}
+
+// portalCommands lists the Portal-authored commands among an event's matcher
+// groups, in order.
+func portalCommands(event any) []string {
+	var out []string
+	groups, _ := event.([]any)
+	for _, g := range groups {
+		group, _ := g.(map[string]any)
+		entries, _ := group["hooks"].([]any)
+		for _, e := range entries {
+			if cmd, ok := portalCommand(e); ok {
+				out = append(out, cmd)
+			}
+		}
+	}
+	return out
+}
+
+// portalCommand returns a hook entry's command when Portal wrote it.
+func portalCommand(entry any) (string, bool) {
+	hook, _ := entry.(map[string]any)
+	cmd, _ := hook["command"].(string)
+	return cmd, strings.Contains(cmd, ingestFingerprint)
+}
+
+// stripPortalHooks returns an event's matcher groups with every Portal entry
+// removed, and how many it removed. A group left with no hooks is dropped;
+// groups and entries Portal did not write pass through untouched.
+func stripPortalHooks(event any) ([]any, int) {
+	groups, _ := event.([]any)
+	out := make([]any, 0, len(groups))
+	removed := 0
+	for _, g := range groups {
+		group, ok := g.(map[string]any)
+		if !ok {
+			out = append(out, g)
+			continue
+		}
+		entries, _ := group["hooks"].([]any)
+		kept := slices.DeleteFunc(slices.Clone(entries), func(e any) bool {
+			_, ok := portalCommand(e)
+			return ok
+		})
+		if len(kept) == len(entries) {
+			out = append(out, g)
+			continue
+		}
+		removed += len(entries) - len(kept)
+		if len(kept) == 0 {
+			continue
+		}
+		group["hooks"] = kept
+		out = append(out, group)
+	}
+	return out, removed
+}
+
+// write replaces the file's "hooks" member with hooks — removing the member
+// when hooks is empty — and replaces the file atomically. A symlinked settings
+// file (a dotfiles checkout) is written through to its target rather than
+// replaced by a regular file, and an existing file keeps its permissions.
+func (f settingsFile) write(hooks map[string]any) error {
+	data, err := spliceHooks(f.data, hooks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", f.path, err)
+	}
+	path := f.path
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	info, statErr := os.Stat(path)
+	if err := fileutil.AtomicWrite(path, data); err != nil {
+		return err
+	}
+	if statErr == nil {
+		_ = os.Chmod(path, info.Mode().Perm())
+	}
+	return nil
+}
+
+// jsonMember locates one member of a JSON object in its source bytes: the
+// offset of its key's opening quote and the span of its value.
+type jsonMember struct {
+	name                           string
+	keyStart, valueStart, valueEnd int
+}
+
+// objectMembers locates the top-level members of the JSON object in data, and
+// the offsets of its braces. data has already decoded as an object.
+func objectMembers(data []byte) (members []jsonMember, open, end int, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, 0, 0, err
+	}
+	open = int(dec.InputOffset()) - 1
+	for dec.More() {
+		prev := int(dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		name, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, 0, 0, err
+		}
+		valueEnd := int(dec.InputOffset())
+		members = append(members, jsonMember{
+			name:       name,
+			keyStart:   prev + bytes.IndexByte(data[prev:], '"'),
+			valueStart: valueEnd - len(raw),
+			valueEnd:   valueEnd,
+		})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, 0, 0, err
+	}
+	return members, open, int(dec.InputOffset()) - 1, nil
+}
+
+// spliceHooks returns data with its top-level "hooks" member's value replaced
+// by hooks, the member added when data has none and removed when hooks is
+// empty. Every byte outside that member is kept; the new value is indented to
+// sit where it lands, or written compact into a one-line file. A file with no
+// content yet is written the way the agents write theirs: two-space indent,
+// no HTML escaping, since hook commands routinely carry & and >.
+func spliceHooks(data []byte, hooks map[string]any) ([]byte, error) {
+	if data == nil {
+		if len(hooks) == 0 {
+			return []byte("{}\n"), nil
+		}
+		v, err := encodeSettingsJSON(hooks, "  ", "  ", false)
+		if err != nil {
+			return nil, err
+		}
+		return fmt.Appendf(nil, "{\n  \"hooks\": %s\n}\n", v), nil
+	}
+	members, open, end, err := objectMembers(data)
+	if err != nil {
+		return nil, err
+	}
+	at := -1
+	for i, m := range members {
+		if m.name == "hooks" {
+			at = i
+		}
+	}
+
+	splice := func(from, to int, insert []byte) []byte {
+		out := slices.Clone(data[:from])
+		out = append(out, insert...)
+		return append(out, data[to:]...)
+	}
+
+	if len(hooks) == 0 {
+		switch {
+		case at < 0:
+			return data, nil
+		case at > 0:
+			return splice(members[at-1].valueEnd, members[at].valueEnd, nil), nil
+		case len(members) > 1:
+			return splice(members[0].keyStart, members[1].keyStart, nil), nil
+		default:
+			return splice(open+1, members[0].valueEnd, nil), nil
+		}
+	}
+
+	if at >= 0 {
+		m := members[at]
+		indent := lineIndent(data, m.keyStart)
+		v, err := encodeSettingsJSON(hooks, indent, indent, !bytes.Contains(data[:m.keyStart], []byte("\n")))
+		if err != nil {
+			return nil, err
+		}
+		return splice(m.valueStart, m.valueEnd, v), nil
+	}
+
+	if len(members) == 0 {
+		v, err := encodeSettingsJSON(hooks, "  ", "  ", false)
+		if err != nil {
+			return nil, err
+		}
+		return splice(open+1, end, fmt.Appendf(nil, "\n  \"hooks\": %s\n", v)), nil
+	}
+	first, last := members[0], members[len(members)-1]
+	lead := string(data[open+1 : first.keyStart])
+	compact := !strings.Contains(lead, "\n")
+	indent := lineIndent(data, first.keyStart)
+	v, err := encodeSettingsJSON(hooks, indent, indent, compact)
+	if err != nil {
+		return nil, err
+	}
+	sep := `": `
+	if lead == "" {
+		sep = `":`
+	}
+	return splice(last.valueEnd, last.valueEnd, fmt.Appendf(nil, ",%s\"hooks%s%s", lead, sep, v)), nil
+}
+
+// lineIndent returns the whitespace between the start of the line holding
+// offset and offset itself — the indent of a member that starts its own line —
+// defaulting to two spaces for a member sharing its line with other text.
+func lineIndent(data []byte, offset int) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	if indent := data[start:offset]; len(bytes.TrimLeft(indent, " \t")) == 0 && len(indent) > 0 {
+		return string(indent)
+	}
+	return "  "
+}
+
+// encodeSettingsJSON encodes v without HTML escaping, either compact or
+// indented by indent under prefix, without a trailing newline.
+func encodeSettingsJSON(v any, prefix, indent string, compact bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if !compact {
+		enc.SetIndent(prefix, indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,253 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/agent"
+)
+
+// userSettings is a Claude Code settings file with a user hook and an
+// unrelated setting, both of which every operation must preserve.
+const userSettings = `{
+  "model": "opus",
+  "cleanupPeriodDays": 30,
+  "hooks": {
+    "PreToolUse": [
+      {
+        "matcher": "Bash",
+        "hooks": [{ "type": "command", "command": "audit-bash && echo ok > /dev/null" }]
+      }
+    ]
+  }
+}
+`
+
+func testIntegration(t *testing.T, content string) agent.Integration {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return agent.Integration{Name: "claude", Path: path, Events: []string{"PreToolUse", "Stop"}}
+}
+
+func readJSON(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	return v
+}
+
+func TestIntegration_Install(t *testing.T) {
+	t.Run("adds one entry per event and keeps the user's hooks and settings", func(t *testing.T) {
+		in := testIntegration(t, userSettings)
+
+		changed, err := in.Install()
+		if err != nil || !changed {
+			t.Fatalf("Install = %v, %v; want a change", changed, err)
+		}
+
+		st, err := in.Status()
+		if err != nil || !st.Installed() {
+			t.Errorf("status = %+v, %v; want installed", st, err)
+		}
+		data, _ := os.ReadFile(in.Path)
+		for _, want := range []string{`"model": "opus"`, `"cleanupPeriodDays": 30`, `audit-bash && echo ok > /dev/null`, `"matcher": "Bash"`} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("settings lost %s:\n%s", want, data)
+			}
+		}
+		if info, _ := os.Stat(in.Path); info.Mode().Perm() != 0o644 {
+			t.Errorf("mode = %v, want the file's own 0644", info.Mode().Perm())
+		}
+	})
+
+	t.Run("a second install changes nothing", func(t *testing.T) {
+		in := testIntegration(t, userSettings)
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		before, _ := os.ReadFile(in.Path)
+
+		changed, err := in.Install()
+		if err != nil || changed {
+			t.Errorf("Install = %v, %v; want no change", changed, err)
+		}
+		if after, _ := os.ReadFile(in.Path); string(after) != string(before) {
+			t.Error("second install rewrote the file")
+		}
+	})
+
+	t.Run("replaces stale and duplicated Portal entries with the current one", func(t *testing.T) {
+		in := testIntegration(t, `{"hooks": {
+  "Stop": [
+    {"hooks": [{"type": "command", "command": "portal agent ingest"}]},
+    {"hooks": [{"type": "command", "command": "portal agent ingest"}, {"type": "command", "command": "notify-send done"}]}
+  ]
+}}`)
+		if st, _ := in.Status(); st.Summary() != "stale" {
+			t.Fatalf("status = %+v, want stale", st)
+		}
+
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+
+		stop := readJSON(t, in.Path)["hooks"].(map[string]any)["Stop"].([]any)
+		if len(stop) != 2 {
+			t.Fatalf("Stop = %v, want the user's group and one Portal group", stop)
+		}
+		user := stop[0].(map[string]any)["hooks"].([]any)
+		if len(user) != 1 || user[0].(map[string]any)["command"] != "notify-send done" {
+			t.Errorf("user group = %v, want only notify-send", user)
+		}
+		portal := stop[1].(map[string]any)["hooks"].([]any)
+		if portal[0].(map[string]any)["command"] != in.Command() {
+			t.Errorf("portal group = %v", portal)
+		}
+	})
+
+	t.Run("creates a missing file", func(t *testing.T) {
+		in := testIntegration(t, "")
+		if st, _ := in.Status(); st.Summary() != "not installed" {
+			t.Fatalf("status = %+v, want not installed", st)
+		}
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		if st, _ := in.Status(); !st.Installed() {
+			t.Errorf("status = %+v, want installed", st)
+		}
+	})
+
+	t.Run("refuses a file that is not a settings object", func(t *testing.T) {
+		in := testIntegration(t, `{"hooks": []}`)
+		if _, err := in.Install(); err == nil {
+			t.Error("Install succeeded on a non-object hooks value")
+		}
+	})
+
+	t.Run("writes through a symlinked settings file", func(t *testing.T) {
+		in := testIntegration(t, userSettings)
+		target := in.Path + ".dotfiles"
+		if err := os.Rename(in.Path, target); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, in.Path); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		if info, err := os.Lstat(in.Path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("settings link replaced: %v, %v", info, err)
+		}
+		if data, _ := os.ReadFile(target); !strings.Contains(string(data), in.Command()) {
+			t.Errorf("target not updated:\n%s", data)
+		}
+	})
+}
+
+func TestIntegration_InstallPreservesLayout(t *testing.T) {
+	t.Run("replaces only the hooks member's value", func(t *testing.T) {
+		in := testIntegration(t, "{\n    \"zed\": 1,\n    \"hooks\": {},\n    \"alpha\": [1,  2]\n}\n")
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(in.Path)
+		got := string(data)
+		if !strings.HasPrefix(got, "{\n    \"zed\": 1,\n    \"hooks\": {\n        \"PreToolUse\": [") {
+			t.Errorf("prefix or indent changed:\n%s", got)
+		}
+		if !strings.HasSuffix(got, "\n    },\n    \"alpha\": [1,  2]\n}\n") {
+			t.Errorf("suffix changed:\n%s", got)
+		}
+		readJSON(t, in.Path)
+	})
+
+	t.Run("appends a missing hooks member in the file's style", func(t *testing.T) {
+		in := testIntegration(t, `{"b":true,"a":1}`)
+		if _, err := in.Install(); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(in.Path)
+		if !strings.HasPrefix(string(data), `{"b":true,"a":1,"hooks":{"PreToolUse":[`) {
+			t.Errorf("settings = %s", data)
+		}
+		readJSON(t, in.Path)
+	})
+
+	t.Run("uninstall removes the member it added and nothing else", func(t *testing.T) {
+		for _, original := range []string{
+			"{\n  \"zed\": 1\n}\n",
+			"{\n  \"zed\": 1,\n  \"hooks\": {},\n  \"alpha\": 2\n}\n",
+		} {
+			in := testIntegration(t, original)
+			if _, err := in.Install(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := in.Uninstall(); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := os.ReadFile(in.Path)
+			want := strings.Replace(original, "\n  \"hooks\": {},", "", 1)
+			if string(data) != want {
+				t.Errorf("after install+uninstall of %q: got %q, want %q", original, data, want)
+			}
+		}
+	})
+}
+
+func TestIntegration_Uninstall(t *testing.T) {
+	in := testIntegration(t, userSettings)
+	if _, err := in.Install(); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := in.Uninstall()
+	if err != nil || removed != 2 {
+		t.Fatalf("Uninstall = %d, %v; want 2 removed", removed, err)
+	}
+
+	hooks := readJSON(t, in.Path)["hooks"].(map[string]any)
+	if _, ok := hooks["Stop"]; ok {
+		t.Errorf("empty Stop event left behind: %v", hooks)
+	}
+	if pre := hooks["PreToolUse"].([]any); len(pre) != 1 {
+		t.Errorf("PreToolUse = %v, want only the user's group", pre)
+	}
+	if removed, _ := in.Uninstall(); removed != 0 {
+		t.Errorf("second Uninstall removed %d", removed)
+	}
+}
+
+func TestIntegrations(t *testing.T) {
+	env := map[string]string{"CODEX_HOME": "/opt/codex"}
+	got := agent.Integrations("/home/u", func(k string) string { return env[k] })
+
+	paths := map[string]string{}
+	for _, in := range got {
+		paths[in.Name] = in.Path
+	}
+	if paths["claude"] != "/home/u/.claude/settings.json" || paths["codex"] != "/opt/codex/hooks.json" {
+		t.Errorf("paths = %v", paths)
+	}
+	for _, in := range got {
+		if in.OptIn != (in.Name == "codex") {
+			t.Errorf("%s OptIn = %v; only codex is opt-in", in.Name, in.OptIn)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// openCodeEvent is the part of an OpenCode bus event Portal reads. OpenCode
+// has no hook commands; a plugin forwards the events it cares about to
+// `agent ingest` as they are published, in the bus's own {type, properties}
+// shape.
+type openCodeEvent struct {
+	Type       string `json:"type"`
+	Properties struct {
+		SessionID string `json:"sessionID"`
+		Tool      string `json:"tool"`
+		Title     string `json:"title"`
+		Status    struct {
+			Type string `json:"type"`
+		} `json:"status"`
+		Info struct {
+			ID string `json:"id"`
+		} `json:"info"`
+	} `json:"properties"`
+}
+
+// state classifies the event. A permission request is the agent waiting on
+// its user until the reply arrives; a tool call or a busy session is a turn in
+// progress; an idle session, or one whose turn ended in an error, is back at
+// its prompt.
+func (e openCodeEvent) state() State {
+	switch e.Type {
+	case "permission.updated", "permission.asked":
+		return Waiting
+	case "permission.replied", "tool.execute.before", "tool.execute.after":
+		return Working
+	case "session.status":
+		switch e.Properties.Status.Type {
+		case "busy", "retry":
+			return Working
+		case "idle":
+			return Idle
+		}
+	case "session.created", "session.idle", "session.error":
+		return Idle
+	}
+	return Unknown
+}
+
+// openCodeAdapter reads OpenCode bus events forwarded by a plugin.
+type openCodeAdapter struct{}
+
+func (openCodeAdapter) Name() string { return "opencode" }
+
+func (openCodeAdapter) Parse(r io.Reader) (Event, error) {
+	var e openCodeEvent
+	if err := json.NewDecoder(io.LimitReader(r, maxEventSize)).Decode(&e); err != nil {
+		return Event{}, fmt.Errorf("decode opencode event: %w", err)
+	}
+	if e.Type == "" {
+		return Event{}, errors.New("decode opencode event: missing type")
+	}
+	ev := Event{
+		Name:         e.Type,
+		State:        e.state(),
+		AgentSession: e.Properties.SessionID,
+		Ended:        e.Type == "session.deleted",
+	}
+	if ev.AgentSession == "" {
+		ev.AgentSession = e.Properties.Info.ID
+	}
+	switch ev.State {
+	case Waiting:
+		ev.Detail = e.Properties.Title
+	case Working:
+		ev.Detail = e.Properties.Tool
+	}
+	return ev, nil
+}
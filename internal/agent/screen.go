@@ -0,0 +1,251 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ScreenEvent is the event name recorded for a state read off a pane's screen.
+// It is how the daemon tells the panes it tracks by screen from the ones an
+// agent's hooks report on.
+const ScreenEvent = "screen"
+
+// defaultScreenLines is how many of the screen's last non-blank lines the
+// rules see when an agent does not set Lines. A prompt that has scrolled
+// further up has been answered.
+const defaultScreenLines = 5
+
+// maxDetailLen caps the matched line recorded as an update's detail.
+const maxDetailLen = 120
+
+// ScreenRule maps a pattern on an agent's screen to the state it shows.
+type ScreenRule struct {
+	// Match is a regular expression in Go's syntax. It is matched in
+	// multi-line mode, so ^ and $ anchor to a line.
+	Match string `json:"match"`
+	// State is the state a match means.
+	State State `json:"state"`
+
+	re *regexp.Regexp
+}
+
+// ScreenAgent is the screen-rule adapter for one agent with no hook mechanism.
+// The daemon captures the visible screen of every pane whose foreground
+// command is Command and classifies it by the first rule matching the screen's
+// last Lines non-blank lines; a screen no rule matches shows Default, or
+// Unknown when Default is unset.
+type ScreenAgent struct {
+	// Agent is the name recorded against each pane it tracks.
+	Agent string `json:"agent"`
+	// Command is the pane foreground command to watch, matched exactly
+	// against tmux's pane_current_command (e.g. "aider") or, for an agent run
+	// through an interpreter, the base name of its argv[0] or argv[1] ("python3
+	// /usr/local/bin/aider") or the module it runs ("python3 -m aider"); see
+	// MatchesArgv.
+	Command string       `json:"command"`
+	Lines   int          `json:"lines,omitempty"`
+	Default State        `json:"default,omitempty"`
+	Rules   []ScreenRule `json:"rules"`
+}
+
+// Name is the agent name, satisfying Adapter.
+func (a ScreenAgent) Name() string { return a.Agent }
+
+// MatchesArgv reports whether the foreground process argv runs the agent: its
+// program, or the script its interpreter runs, has Command as its base name,
+// or its interpreter runs Command as a module (-m).
+func (a ScreenAgent) MatchesArgv(argv []string) bool {
+	if len(argv) > 2 && argv[1] == "-m" && argv[2] == a.Command {
+		return true
+	}
+	for _, arg := range argv[:min(len(argv), 2)] {
+		if filepath.Base(arg) == a.Command {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse classifies a captured screen. It fails only when the screen cannot be
+// read; a screen no rule matches is an event in its own right.
+func (a ScreenAgent) Parse(r io.Reader) (Event, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxEventSize))
+	if err != nil {
+		return Event{}, fmt.Errorf("read %s screen: %w", a.Agent, err)
+	}
+	tail := screenTail(string(data), a.Lines)
+	for _, rule := range a.Rules {
+		if loc := rule.re.FindStringIndex(tail); loc != nil {
+			return Event{Name: ScreenEvent, State: rule.State, Detail: lineAt(tail, loc[0])}, nil
+		}
+	}
+	state := a.Default
+	if state == "" {
+		state = Unknown
+	}
+	return Event{Name: ScreenEvent, State: state}, nil
+}
+
+// screenTail returns the last n non-blank lines of screen, each with its
+// trailing padding trimmed.
+func screenTail(screen string, n int) string {
+	if n <= 0 {
+		n = defaultScreenLines
+	}
+	var lines []string
+	all := strings.Split(screen, "\n")
+	for i := len(all) - 1; i >= 0 && len(lines) < n; i-- {
+		if line := strings.TrimRight(all[i], " \t\r"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineAt returns the line of s holding byte offset i, trimmed and capped to
+// maxDetailLen.
+func lineAt(s string, i int) string {
+	start := strings.LastIndexByte(s[:i], '\n') + 1
+	end := len(s)
+	if j := strings.IndexByte(s[i:], '\n'); j >= 0 {
+		end = i + j
+	}
+	line := strings.TrimSpace(s[start:end])
+	if len(line) > maxDetailLen {
+		line = line[:maxDetailLen]
+	}
+	return line
+}
+
+// defaultScreenRules are the screen rules Portal ships, in the rules file's
+// format. Aider asks its yes/no questions and waits at its mode prompt ("> ",
+// "ask> ", "architect> ", …) on the screen's last line; anything else is it
+// printing a reply.
+const defaultScreenRules = `{"agents": [{
+	"agent": "aider",
+	"command": "aider",
+	"default": "working",
+	"rules": [
+		{"match": "\\(Y\\)es/\\(N\\)o.*\\z", "state": "waiting"},
+		{"match": "^([a-z-]+ )*[a-z-]*> ?.*\\z", "state": "idle"}
+	]
+}]}`
+
+// DefaultScreenAgents returns the screen-rule agents Portal ships. Each call
+// returns a fresh slice, so callers may append to it.
+func DefaultScreenAgents() []ScreenAgent {
+	agents, err := ParseScreenRules([]byte(defaultScreenRules))
+	if err != nil {
+		panic("agent: invalid default screen rules: " + err.Error())
+	}
+	return agents
+}
+
+// WithDefaultScreenAgents returns agents followed by every default agent the
+// user has not defined: an entry in the rules file with a default's agent name
+// replaces the shipped rules for it.
+func WithDefaultScreenAgents(agents []ScreenAgent) []ScreenAgent {
+	out := slices.Clone(agents)
+	for _, d := range DefaultScreenAgents() {
+		if !slices.ContainsFunc(agents, func(a ScreenAgent) bool { return a.Agent == d.Agent }) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// screenRulesFile is the on-disk JSON structure for the screen rules file.
+type screenRulesFile struct {
+	Agents []ScreenAgent `json:"agents"`
+}
+
+// ParseScreenRules decodes and validates a screen rules file. Every agent
+// needs a name and a command, every rule a pattern that compiles and a state;
+// one bad entry fails the whole file, so a typo never silently drops a rule.
+func ParseScreenRules(data []byte) ([]ScreenAgent, error) {
+	var f screenRulesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	for i := range f.Agents {
+		a := &f.Agents[i]
+		if a.Agent == "" || a.Command == "" {
+			return nil, fmt.Errorf("agent %d: agent and command are required", i+1)
+		}
+		if a.Default != "" && !a.Default.valid() {
+			return nil, fmt.Errorf("agent %q: unknown default state %q", a.Agent, a.Default)
+		}
+		for j := range a.Rules {
+			rule := &a.Rules[j]
+			if !rule.State.valid() {
+				return nil, fmt.Errorf("agent %q rule %d: unknown state %q", a.Agent, j+1, rule.State)
+			}
+			re, err := regexp.Compile("(?m)" + rule.Match)
+			if err != nil || rule.Match == "" {
+				return nil, fmt.Errorf("agent %q rule %d: invalid match %q", a.Agent, j+1, rule.Match)
+			}
+			rule.re = re
+		}
+	}
+	return f.Agents, nil
+}
+
+// valid reports whether s is one of the four states.
+func (s State) valid() bool {
+	return s.Urgency() > 0
+}
+
+// ScreenRulesStore is a read-only store over the user-authored screen rules
+// file. The daemon loads it on every scan, so it caches the parsed rules and
+// re-reads the file only when its size or modification time changes.
+type ScreenRulesStore struct {
+	path    string
+	modTime time.Time
+	size    int64
+	agents  []ScreenAgent
+}
+
+// NewScreenRulesStore returns a store that reads the rules file at path.
+func NewScreenRulesStore(path string) *ScreenRulesStore {
+	return &ScreenRulesStore{path: path}
+}
+
+// Load returns the screen rules in force. A missing file is no rules. A file
+// that fails to parse is also no rules, with the error returned only by the
+// Load that read it — not again until the file next changes — so a bad edit
+// is reported once rather than on every scan.
+func (s *ScreenRulesStore) Load() ([]ScreenAgent, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		s.modTime, s.size, s.agents = time.Time{}, 0, nil
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.agents, nil
+	}
+	s.modTime, s.size, s.agents = info.ModTime(), info.Size(), nil
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := ParseScreenRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", s.path, err)
+	}
+	s.agents = agents
+	return agents, nil
+}
@@ -0,0 +1,182 @@
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+)
+
+const aiderRules = `{"agents": [{
+  "agent": "aider",
+  "command": "aider",
+  "default": "working",
+  "rules": [
+    {"match": "\\(Y\\)es/\\(N\\)o", "state": "waiting"},
+    {"match": "^> ?$", "state": "idle"}
+  ]
+}]}`
+
+func TestScreenAgent_Parse(t *testing.T) {
+	agents, err := agent.ParseScreenRules([]byte(aiderRules))
+	if err != nil || len(agents) != 1 {
+		t.Fatalf("ParseScreenRules = %v, %v", agents, err)
+	}
+	aider := agents[0]
+
+	cases := []struct {
+		name, screen string
+		want         agent.Event
+	}{
+		{
+			"the first matching rule wins and its line is the detail",
+			"Applied edit to main.go\n\nRun shell command? (Y)es/(N)o [Yes]:    \n\n\n",
+			agent.Event{Name: agent.ScreenEvent, State: agent.Waiting, Detail: "Run shell command? (Y)es/(N)o [Yes]:"},
+		},
+		{
+			"anchors match a single line",
+			"Tokens: 2.1k sent\n> \n",
+			agent.Event{Name: agent.ScreenEvent, State: agent.Idle, Detail: ">"},
+		},
+		{
+			"a prompt scrolled above the last lines no longer matches",
+			"Add file? (Y)es/(N)o [Yes]: y\n1\n2\n3\n4\n5\n",
+			agent.Event{Name: agent.ScreenEvent, State: agent.Working},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := aider.Parse(strings.NewReader(c.screen))
+			if err != nil || got != c.want {
+				t.Errorf("Parse = %+v, %v; want %+v", got, err, c.want)
+			}
+		})
+	}
+
+	t.Run("no default is unknown", func(t *testing.T) {
+		plain := agent.ScreenAgent{Agent: "x", Command: "x"}
+		if got, _ := plain.Parse(strings.NewReader("anything")); got.State != agent.Unknown {
+			t.Errorf("State = %s, want unknown", got.State)
+		}
+	})
+}
+
+func TestScreenAgent_MatchesArgv(t *testing.T) {
+	aider := agent.ScreenAgent{Agent: "aider", Command: "aider"}
+	tests := []struct {
+		argv []string
+		want bool
+	}{
+		{[]string{"/usr/local/bin/aider", "--model", "sonnet"}, true},
+		{[]string{"python3", "/home/u/.local/bin/aider"}, true},
+		{[]string{"python3", "-m", "aider"}, true},
+		{[]string{"python3", "-m", "aider.main"}, false},
+		{[]string{"python3", "-m"}, false},
+		{[]string{"vim", "aider"}, true},
+		{[]string{"-zsh"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := aider.MatchesArgv(tt.argv); got != tt.want {
+			t.Errorf("MatchesArgv(%q) = %v, want %v", tt.argv, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultScreenAgents(t *testing.T) {
+	t.Run("ships Aider rules", func(t *testing.T) {
+		defaults := agent.DefaultScreenAgents()
+		if len(defaults) != 1 || defaults[0].Agent != "aider" {
+			t.Fatalf("defaults = %+v, want Aider", defaults)
+		}
+		aider := defaults[0]
+		tests := []struct {
+			screen string
+			want   agent.State
+		}{
+			{"Edit the README\nRun shell command? (Y)es/(N)o [Yes]: ", agent.Waiting},
+			{"Tokens: 2.1k sent, 310 received.\n\n> ", agent.Idle},
+			{"Tokens: 2.1k sent, 310 received.\n\narchitect> ", agent.Idle},
+			{"> fix the tests\n\nI'll update the test to", agent.Working},
+			{"Run shell command? (Y)es/(N)o [Yes]: y\nRunning go test", agent.Working},
+		}
+		for _, tt := range tests {
+			event, err := aider.Parse(strings.NewReader(tt.screen))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if event.State != tt.want {
+				t.Errorf("screen %q = %q, want %q", tt.screen, event.State, tt.want)
+			}
+		}
+	})
+
+	t.Run("the rules file overrides a shipped agent by name", func(t *testing.T) {
+		custom := agent.ScreenAgent{Agent: "aider", Command: "my-aider"}
+		other := agent.ScreenAgent{Agent: "goose", Command: "goose"}
+
+		if got := agent.WithDefaultScreenAgents([]agent.ScreenAgent{custom}); len(got) != 1 || got[0].Command != "my-aider" {
+			t.Errorf("with an aider entry = %+v, want only the user's", got)
+		}
+		if got := agent.WithDefaultScreenAgents([]agent.ScreenAgent{other}); len(got) != 2 || got[1].Agent != "aider" {
+			t.Errorf("without one = %+v, want the user's then the shipped aider", got)
+		}
+	})
+}
+
+func TestParseScreenRules(t *testing.T) {
+	for name, data := range map[string]string{
+		"missing command": `{"agents":[{"agent":"a","rules":[]}]}`,
+		"bad pattern":     `{"agents":[{"agent":"a","command":"a","rules":[{"match":"(","state":"idle"}]}]}`,
+		"empty pattern":   `{"agents":[{"agent":"a","command":"a","rules":[{"match":"","state":"idle"}]}]}`,
+		"unknown state":   `{"agents":[{"agent":"a","command":"a","rules":[{"match":"x","state":"busy"}]}]}`,
+		"unknown default": `{"agents":[{"agent":"a","command":"a","default":"busy"}]}`,
+		"malformed JSON":  `{"agents":`,
+	} {
+		t.Run(name+" fails the file", func(t *testing.T) {
+			if _, err := agent.ParseScreenRules([]byte(data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestScreenRulesStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-rules.json")
+	store := agent.NewScreenRulesStore(path)
+	write := func(data string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := time.Now().Add(-time.Hour)
+
+	if got, err := store.Load(); got != nil || err != nil {
+		t.Errorf("missing file = %v, %v; want no rules", got, err)
+	}
+
+	write(aiderRules, base)
+	if got, err := store.Load(); err != nil || len(got) != 1 || got[0].Agent != "aider" {
+		t.Fatalf("Load = %v, %v", got, err)
+	}
+
+	write(`{"agents":[{"agent":"a"}]}`, base.Add(time.Minute))
+	if got, err := store.Load(); got != nil || err == nil {
+		t.Errorf("bad edit = %v, %v; want no rules and an error", got, err)
+	}
+	if got, err := store.Load(); got != nil || err != nil {
+		t.Errorf("unchanged bad file = %v, %v; want the error reported once", got, err)
+	}
+
+	write(aiderRules, base.Add(2*time.Minute))
+	if got, err := store.Load(); err != nil || len(got) != 1 {
+		t.Errorf("fixed file = %v, %v; want the rules back", got, err)
+	}
+}
@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/leeovery/portal/internal/fileutil"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// Record is the tracked state of the agent in one pane. Session, Window and
+// Pane are where the pane was last seen; PaneID is its identity.
+type Record struct {
+	PaneID       string    `json:"pane_id"`
+	Agent        string    `json:"agent,omitempty"`
+	Session      string    `json:"session"`
+	Window       int       `json:"window"`
+	Pane         int       `json:"pane"`
+	State        State     `json:"state"`
+	Event        string    `json:"event"`
+	Detail       string    `json:"detail,omitempty"`
+	AgentSession string    `json:"agent_session,omitempty"`
+	Since        time.Time `json:"since"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Target returns the pane's "session:window.pane" address.
+func (r Record) Target() string {
+	return tmux.PaneTarget(r.Session, r.Window, r.Pane)
+}
+
+// Table is the tracked agents, keyed by pane id.
+type Table map[string]Record
+
+// Apply folds u into the table given the server's current pane addresses,
+// reporting whether anything changed. An update for a pane that no longer
+// exists is dropped, as is one older than what the table already holds —
+// inbox entries written in the same instant may drain out of order. Since
+// moves only when the state does, so it reads as "waiting for 4m". An Unknown
+// update for a tracked pane says the agent is alive but not what it is doing,
+// so it refreshes UpdatedAt and the address and leaves the state alone.
+func (t Table) Apply(u Update, addrs map[string]tmux.PaneAddr) bool {
+	prev, tracked := t[u.PaneID]
+	if tracked && u.At.Before(prev.UpdatedAt) {
+		return false
+	}
+	addr, live := addrs[u.PaneID]
+	if !live || u.Ended {
+		if tracked {
+			delete(t, u.PaneID)
+		}
+		return tracked
+	}
+	if tracked && u.State == Unknown {
+		prev.Session, prev.Window, prev.Pane = addr.Session, addr.Window, addr.Pane
+		prev.UpdatedAt = u.At
+		t[u.PaneID] = prev
+		return true
+	}
+	r := Record{
+		PaneID:       u.PaneID,
+		Agent:        u.Agent,
+		Session:      addr.Session,
+		Window:       addr.Window,
+		Pane:         addr.Pane,
+		State:        u.State,
+		Event:        u.Event,
+		Detail:       u.Detail,
+		AgentSession: u.AgentSession,
+		Since:        u.At,
+		UpdatedAt:    u.At,
+	}
+	if tracked && prev.State == u.State {
+		r.Since = prev.Since
+	}
+	t[u.PaneID] = r
+	return true
+}
+
+// Reconcile drops the records of panes that have closed and moves the rest to
+// their panes' current addresses, following session renames and window moves.
+// It reports whether anything changed.
+func (t Table) Reconcile(addrs map[string]tmux.PaneAddr) bool {
+	changed := false
+	for id, r := range t {
+		addr, ok := addrs[id]
+		if !ok {
+			delete(t, id)
+			changed = true
+			continue
+		}
+		if r.Session != addr.Session || r.Window != addr.Window || r.Pane != addr.Pane {
+			r.Session, r.Window, r.Pane = addr.Session, addr.Window, addr.Pane
+			t[id] = r
+			changed = true
+		}
+	}
+	return changed
+}
+
+// DropMoved drops the records of panes that have closed or no longer sit at
+// their recorded address. It is the check for a table loaded from disk: tmux
+// reuses pane ids after a server restart, so an id alone cannot prove a
+// record still describes the same pane.
+func (t Table) DropMoved(addrs map[string]tmux.PaneAddr) bool {
+	changed := false
+	for id, r := range t {
+		if addr, ok := addrs[id]; !ok || addr != (tmux.PaneAddr{Session: r.Session, Window: r.Window, Pane: r.Pane}) {
+			delete(t, id)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Records returns the table's records ordered by target.
+func (t Table) Records() []Record {
+	records := slices.Collect(maps.Values(t))
+	slices.SortFunc(records, func(a, b Record) int {
+		return cmp.Or(
+			cmp.Compare(a.Session, b.Session),
+			cmp.Compare(a.Window, b.Window),
+			cmp.Compare(a.Pane, b.Pane),
+		)
+	})
+	return records
+}
+
+// BySession rolls the table up to one state per session: the most urgent
+// state among the session's panes.
+func (t Table) BySession() map[string]State {
+	states := make(map[string]State)
+	for _, r := range t {
+		if r.State.Urgency() > states[r.Session].Urgency() {
+			states[r.Session] = r.State
+		}
+	}
+	return states
+}
+
+// agentFile is the on-disk JSON structure for agent.json.
+type agentFile struct {
+	Panes []Record `json:"panes"`
+}
+
+// Load reads the table persisted at path. A missing file is an empty table. A
+// corrupt one is also an empty table, with the decode error returned so the
+// caller can log it: agent state is a hint that the next hook event rebuilds,
+// never worth failing over.
+func Load(path string) (Table, error) {
+	t := Table{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return t, nil
+		}
+		return t, err
+	}
+	var f agentFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return t, fmt.Errorf("decode %s: %w", path, err)
+	}
+	for _, r := range f.Panes {
+		if r.PaneID != "" {
+			t[r.PaneID] = r
+		}
+	}
+	return t, nil
+}
+
+// Save persists the table to path atomically.
+func (t Table) Save(path string) error {
+	data, err := json.MarshalIndent(agentFile{Panes: t.Records()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent state: %w", err)
+	}
+	return fileutil.AtomicWrite0600(path, data)
+}
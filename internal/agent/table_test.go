@@ -0,0 +1,178 @@
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+var t0 = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+func update(pane string, s agent.State, at time.Time) agent.Update {
+	return agent.Update{PaneID: pane, State: s, Event: "e", At: at}
+}
+
+func TestTable_Apply(t *testing.T) {
+	addrs := map[string]tmux.PaneAddr{"%1": {Session: "api", Window: 1, Pane: 2}}
+
+	t.Run("records the state at the pane's address", func(t *testing.T) {
+		table := agent.Table{}
+		if !table.Apply(update("%1", agent.Working, t0), addrs) {
+			t.Fatal("Apply reported no change")
+		}
+		r := table["%1"]
+		if r.Target() != "api:1.2" || r.State != agent.Working || !r.Since.Equal(t0) {
+			t.Errorf("record = %+v", r)
+		}
+	})
+
+	t.Run("Since holds while the state repeats and moves when it changes", func(t *testing.T) {
+		table := agent.Table{}
+		table.Apply(update("%1", agent.Working, t0), addrs)
+		table.Apply(update("%1", agent.Working, t0.Add(time.Minute)), addrs)
+		if r := table["%1"]; !r.Since.Equal(t0) || !r.UpdatedAt.Equal(t0.Add(time.Minute)) {
+			t.Errorf("repeat: record = %+v", r)
+		}
+		table.Apply(update("%1", agent.Waiting, t0.Add(2*time.Minute)), addrs)
+		if r := table["%1"]; !r.Since.Equal(t0.Add(2 * time.Minute)) {
+			t.Errorf("change: Since = %v", r.Since)
+		}
+	})
+
+	t.Run("an update older than the record is ignored", func(t *testing.T) {
+		table := agent.Table{}
+		table.Apply(update("%1", agent.Idle, t0.Add(time.Second)), addrs)
+		if table.Apply(update("%1", agent.Working, t0), addrs) {
+			t.Error("stale update reported a change")
+		}
+		if table["%1"].State != agent.Idle {
+			t.Errorf("state = %s, want idle", table["%1"].State)
+		}
+	})
+
+	t.Run("an unclassified event refreshes the timestamp and keeps the state", func(t *testing.T) {
+		table := agent.Table{}
+		table.Apply(update("%1", agent.Waiting, t0), addrs)
+		if !table.Apply(update("%1", agent.Unknown, t0.Add(time.Minute)), addrs) {
+			t.Error("unknown update reported no change")
+		}
+		if r := table["%1"]; r.State != agent.Waiting || !r.Since.Equal(t0) || !r.UpdatedAt.Equal(t0.Add(time.Minute)) {
+			t.Errorf("record = %+v, want waiting since t0, updated a minute later", r)
+		}
+
+		fresh := agent.Table{}
+		fresh.Apply(update("%1", agent.Unknown, t0), addrs)
+		if fresh["%1"].State != agent.Unknown {
+			t.Errorf("untracked pane state = %s, want unknown", fresh["%1"].State)
+		}
+	})
+
+	t.Run("a closed pane or an ended agent is untracked", func(t *testing.T) {
+		table := agent.Table{}
+		if table.Apply(update("%9", agent.Working, t0), addrs) || len(table) != 0 {
+			t.Errorf("update for a closed pane was recorded: %v", table)
+		}
+		table.Apply(update("%1", agent.Working, t0), addrs)
+		end := update("%1", agent.Unknown, t0.Add(time.Second))
+		end.Ended = true
+		if !table.Apply(end, addrs) || len(table) != 0 {
+			t.Errorf("ended agent still tracked: %v", table)
+		}
+	})
+}
+
+func TestTable_Reconcile(t *testing.T) {
+	table := agent.Table{
+		"%1": {PaneID: "%1", Session: "api", State: agent.Working},
+		"%2": {PaneID: "%2", Session: "web", State: agent.Idle},
+	}
+	addrs := map[string]tmux.PaneAddr{"%1": {Session: "api-renamed", Window: 3}}
+
+	if !table.Reconcile(addrs) {
+		t.Fatal("Reconcile reported no change")
+	}
+	if _, ok := table["%2"]; ok {
+		t.Error("closed pane kept")
+	}
+	if r := table["%1"]; r.Target() != "api-renamed:3.0" || r.State != agent.Working {
+		t.Errorf("record = %+v, want it moved with its state", r)
+	}
+	if table.Reconcile(addrs) {
+		t.Error("a second Reconcile reported a change")
+	}
+}
+
+func TestTable_DropMoved(t *testing.T) {
+	table := agent.Table{
+		"%1": {PaneID: "%1", Session: "api"},
+		"%2": {PaneID: "%2", Session: "web"},
+		"%3": {PaneID: "%3", Session: "db"},
+	}
+	addrs := map[string]tmux.PaneAddr{"%1": {Session: "api"}, "%2": {Session: "other"}}
+
+	if !table.DropMoved(addrs) {
+		t.Fatal("DropMoved reported no change")
+	}
+	if len(table) != 1 || table["%1"].Session != "api" {
+		t.Errorf("table = %v, want only the pane still at its address", table)
+	}
+}
+
+func TestTable_BySession(t *testing.T) {
+	table := agent.Table{
+		"%1": {PaneID: "%1", Session: "api", State: agent.Idle},
+		"%2": {PaneID: "%2", Session: "api", State: agent.Waiting},
+		"%3": {PaneID: "%3", Session: "api", State: agent.Working},
+		"%4": {PaneID: "%4", Session: "web", State: agent.Unknown},
+	}
+	got := table.BySession()
+	if len(got) != 2 || got["api"] != agent.Waiting || got["web"] != agent.Unknown {
+		t.Errorf("BySession = %v, want api waiting (its most urgent pane) and web unknown", got)
+	}
+}
+
+func TestTable_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.json")
+
+	t.Run("round-trips in target order", func(t *testing.T) {
+		table := agent.Table{
+			"%2": {PaneID: "%2", Session: "web", State: agent.Waiting, Since: t0, UpdatedAt: t0},
+			"%1": {PaneID: "%1", Session: "api", State: agent.Idle, Since: t0, UpdatedAt: t0},
+		}
+		if err := table.Save(path); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		got, err := agent.Load(path)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		records := got.Records()
+		if len(records) != 2 || records[0].Session != "api" || records[1].State != agent.Waiting || !records[1].Since.Equal(t0) {
+			t.Errorf("records = %+v", records)
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("agent.json mode = %v, %v; want 0600", info.Mode().Perm(), err)
+		}
+	})
+
+	t.Run("a missing file is an empty table", func(t *testing.T) {
+		got, err := agent.Load(filepath.Join(t.TempDir(), "agent.json"))
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, %v", got, err)
+		}
+	})
+
+	t.Run("a corrupt file is an empty table and an error", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := agent.Load(path)
+		if err == nil || got == nil || len(got) != 0 {
+			t.Errorf("got %v, %v; want an empty table and an error", got, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// ErrUnreachable wraps a failed Dial: nothing is listening on the socket,
+// which means no daemon is running or one is running without a socket.
+var ErrUnreachable = errors.New("daemon not reachable")
+
+// Client is one connection to the daemon's control socket. It is not safe for
+// concurrent use.
+type Client struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	next    int64
+}
+
+// Dial connects to the control socket at path. timeout bounds the connect and
+// each later Call; a daemon that is down fails the dial at once.
+func Dial(path string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	return &Client{conn: conn, r: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Call sends one request and decodes its result into result, which may be nil
+// to discard it. A daemon-side failure is returned as a *Error.
+func (c *Client) Call(method string, params, result any) error {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	resp, err := c.roundTrip(method, params)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// Subscribe streams events to fn until fn returns an error, the daemon hangs
+// up, or the connection fails. The stream has no deadline; close the client
+// from another goroutine to end it early. An orderly hang-up by the daemon
+// returns nil.
+func (c *Client) Subscribe(types []string, fn func(Event) error) error {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	resp, err := c.roundTrip(MethodSubscribe, SubscribeParams{Types: types})
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if err := c.conn.SetDeadline(time.Time{}); err != nil {
+		return err
+	}
+	for {
+		line, err := c.r.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+		if err := fn(ev); err != nil {
+			return err
+		}
+	}
+}
+
+// roundTrip writes one request and reads its response.
+func (c *Client) roundTrip(method string, params any) (Response, error) {
+	c.next++
+	req := Request{V: Version, ID: c.next, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return Response{}, fmt.Errorf("encode %s params: %w", method, err)
+		}
+		req.Params = raw
+	}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, err
+	}
+	if _, err := c.conn.Write(append(line, '\n')); err != nil {
+		return Response{}, fmt.Errorf("send %s: %w", method, err)
+	}
+	raw, err := c.r.ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("read %s response: %w", method, err)
+	}
+	var resp Response
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return Response{}, fmt.Errorf("decode %s response: %w", method, err)
+	}
+	if resp.ID != req.ID {
+		return Response{}, fmt.Errorf("%s: response id %d does not match request %d", method, resp.ID, req.ID)
+	}
+	return resp, nil
+}
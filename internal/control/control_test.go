@@ -0,0 +1,208 @@
+package control_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/control"
+)
+
+// socketPath returns a socket path short enough for sun_path: t.TempDir()
+// nests the full test name and can overrun the 104-byte limit on macOS.
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "daemon.sock")
+}
+
+// serve starts a server on a fresh socket and returns it with its path.
+func serve(t *testing.T, h control.Handler) (*control.Server, string) {
+	t.Helper()
+	path := socketPath(t)
+	ln, err := control.Listen(path)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	srv := control.NewServer(ln, h, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv, path
+}
+
+func dial(t *testing.T, path string) *control.Client {
+	t.Helper()
+	c, err := control.Dial(path, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+var pingHandler = control.HandlerFunc(func(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case control.MethodPing:
+		return control.PingResult{Protocol: control.Version, Version: "1.2.3", PID: 42}, nil
+	case control.MethodSaveNow:
+		return nil, errors.New("capture failed")
+	case control.MethodStatus:
+		return nil, &control.Error{Code: control.ErrCodeRestoring, Message: "busy restoring"}
+	}
+	return nil, &control.Error{Code: control.ErrCodeMethod, Message: "unknown method " + method}
+})
+
+func TestClientCall(t *testing.T) {
+	_, path := serve(t, pingHandler)
+	c := dial(t, path)
+
+	t.Run("decodes the result", func(t *testing.T) {
+		var res control.PingResult
+		if err := c.Call(control.MethodPing, nil, &res); err != nil {
+			t.Fatalf("Call: %v", err)
+		}
+		if res.PID != 42 || res.Version != "1.2.3" || res.Protocol != control.Version {
+			t.Errorf("got %+v", res)
+		}
+	})
+
+	t.Run("a protocol error comes back as-is", func(t *testing.T) {
+		var cerr *control.Error
+		err := c.Call(control.MethodStatus, nil, nil)
+		if !errors.As(err, &cerr) || cerr.Code != control.ErrCodeRestoring {
+			t.Errorf("got %v, want a %s error", err, control.ErrCodeRestoring)
+		}
+	})
+
+	t.Run("any other handler error is failed", func(t *testing.T) {
+		var cerr *control.Error
+		err := c.Call(control.MethodSaveNow, nil, nil)
+		if !errors.As(err, &cerr) || cerr.Code != control.ErrCodeFailed || cerr.Message != "capture failed" {
+			t.Errorf("got %v, want failed: capture failed", err)
+		}
+	})
+
+	t.Run("the connection stays usable after an error", func(t *testing.T) {
+		var cerr *control.Error
+		if err := c.Call("bogus", nil, nil); !errors.As(err, &cerr) || cerr.Code != control.ErrCodeMethod {
+			t.Errorf("got %v, want %s", err, control.ErrCodeMethod)
+		}
+		if err := c.Call(control.MethodPing, nil, nil); err != nil {
+			t.Errorf("ping after error: %v", err)
+		}
+	})
+}
+
+func TestServer_VersionMismatch(t *testing.T) {
+	_, path := serve(t, pingHandler)
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := io.WriteString(conn, `{"v":99,"id":7,"method":"ping"}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	var resp control.Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.ID != 7 || resp.Error == nil || resp.Error.Code != control.ErrCodeVersion {
+		t.Errorf("got %+v, want an %s error for id 7", resp, control.ErrCodeVersion)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	srv, path := serve(t, pingHandler)
+	c := dial(t, path)
+
+	events := make(chan control.Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Subscribe([]string{control.EventSaved}, func(ev control.Event) error {
+			events <- ev
+			return nil
+		})
+	}()
+
+	// The subscription is registered before its ack, but the ack is read on
+	// the goroutine above; publish until the first event lands.
+	deadline := time.After(2 * time.Second)
+	for len(events) == 0 {
+		srv.Publish(control.EventReaped, control.ReapedEvent{Session: "old"})
+		srv.Publish(control.EventSaved, control.SaveResult{Sessions: 3})
+		select {
+		case <-deadline:
+			t.Fatal("no event received")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	ev := <-events
+	if ev.Type != control.EventSaved {
+		t.Fatalf("got %s event, want only %s", ev.Type, control.EventSaved)
+	}
+	var res control.SaveResult
+	if err := json.Unmarshal(ev.Data, &res); err != nil || res.Sessions != 3 {
+		t.Errorf("data %s: %+v, %v", ev.Data, res, err)
+	}
+
+	_ = srv.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Subscribe after server close: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return after server close")
+	}
+}
+
+func TestListen(t *testing.T) {
+	t.Run("refuses a socket another server answers on", func(t *testing.T) {
+		_, path := serve(t, pingHandler)
+		if _, err := control.Listen(path); !errors.Is(err, control.ErrInUse) {
+			t.Errorf("got %v, want ErrInUse", err)
+		}
+	})
+
+	t.Run("replaces a stale socket file", func(t *testing.T) {
+		path := socketPath(t)
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		ln, err := control.Listen(path)
+		if err != nil {
+			t.Fatalf("Listen: %v", err)
+		}
+		defer func() { _ = ln.Close() }()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0o600 {
+			t.Errorf("mode %v, want a 0600 socket", info.Mode())
+		}
+	})
+}
+
+func TestDial_NothingListening(t *testing.T) {
+	_, err := control.Dial(socketPath(t), time.Second)
+	if !errors.Is(err, control.ErrUnreachable) {
+		t.Errorf("got %v, want ErrUnreachable", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "daemon not reachable") {
+		t.Errorf("message %q", err)
+	}
+}
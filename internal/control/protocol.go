@@ -0,0 +1,158 @@
+// Package control is the state daemon's local control API: a unix socket in
+// the state directory speaking newline-delimited JSON. Tools, doctor and a
+// status line use it to query and drive the daemon directly, rather than
+// inferring its health from daemon.pid or nudging it through save.requested.
+//
+// Each request is one JSON object on one line, carrying the protocol version,
+// a caller-chosen id and a method. The daemon answers each with one response
+// line echoing the id, holding either a result or an error. A subscribe
+// request turns the connection into an event stream: after its response, the
+// daemon writes one event line per daemon event until the client hangs up.
+//
+// The version is checked on every request. Additive changes — a new method, a
+// new result field — keep Version; anything an older client would misread
+// bumps it, and the daemon refuses requests of a version it does not speak
+// with ErrCodeVersion, so a mismatched client fails loudly rather than
+// misreading a reply.
+package control
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Version is the protocol version this package speaks.
+const Version = 1
+
+// Methods the daemon serves.
+const (
+	// MethodPing answers with a PingResult; it proves the daemon's loop is
+	// turning, not just that its socket is open.
+	MethodPing = "ping"
+	// MethodStatus answers with a StatusResult.
+	MethodStatus = "status"
+	// MethodSaveNow captures and commits immediately and answers with a
+	// SaveResult once the commit has landed.
+	MethodSaveNow = "save-now"
+	// MethodSessions answers with the saved sessions as []SessionInfo.
+	MethodSessions = "sessions"
+	// MethodSubscribe takes SubscribeParams, answers with an empty result, and
+	// then streams events on the connection.
+	MethodSubscribe = "subscribe"
+)
+
+// Event types the daemon publishes.
+const (
+	// EventSaved follows every commit of sessions.json; its data is a
+	// SaveResult.
+	EventSaved = "saved"
+	// EventAgent follows every change in a pane's agent state; its data is the
+	// pane's agent.Record, with an empty state once the pane stops being
+	// tracked.
+	EventAgent = "agent"
+	// EventReaped follows each session the idle reaper kills; its data is a
+	// ReapedEvent.
+	EventReaped = "reaped"
+)
+
+// Error codes carried in Error.Code.
+const (
+	ErrCodeVersion   = "unsupported-version"
+	ErrCodeMethod    = "unknown-method"
+	ErrCodeParams    = "invalid-params"
+	ErrCodeRestoring = "restoring"
+	ErrCodeFailed    = "failed"
+)
+
+// Request is one request line.
+type Request struct {
+	V      int             `json:"v"`
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is one response line: exactly one of Result and Error is set.
+type Response struct {
+	V      int             `json:"v"`
+	ID     int64           `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Error is a request's failure. It implements error so a client can return it
+// as-is.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// Event is one line of a subscription stream.
+type Event struct {
+	V    int             `json:"v"`
+	Type string          `json:"event"`
+	At   time.Time       `json:"at"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// PingResult identifies the daemon answering.
+type PingResult struct {
+	Protocol int    `json:"protocol"`
+	Version  string `json:"version"`
+	PID      int    `json:"pid"`
+}
+
+// StatusResult is the daemon's view of itself.
+type StatusResult struct {
+	PID        int            `json:"pid"`
+	Version    string         `json:"version"`
+	StartedAt  time.Time      `json:"started_at"`
+	LastSaveAt time.Time      `json:"last_save_at"`
+	Restoring  bool           `json:"restoring"`
+	Sessions   int            `json:"sessions"`
+	Dormant    int            `json:"dormant"`
+	Panes      int            `json:"panes"`
+	Agents     map[string]int `json:"agents"`
+	Reaper     bool           `json:"reaper"`
+}
+
+// SaveResult reports a commit of sessions.json.
+type SaveResult struct {
+	SavedAt  time.Time `json:"saved_at"`
+	Sessions int       `json:"sessions"`
+}
+
+// SessionInfo is one session in sessions.json, as the daemon last committed
+// it, with the agent states of its panes.
+type SessionInfo struct {
+	Name    string      `json:"name"`
+	Dormant bool        `json:"dormant"`
+	Windows int         `json:"windows"`
+	Panes   int         `json:"panes"`
+	SavedAt time.Time   `json:"saved_at"`
+	Agents  []AgentPane `json:"agents,omitempty"`
+}
+
+// AgentPane is the agent state of one pane of a session.
+type AgentPane struct {
+	Window int       `json:"window"`
+	Pane   int       `json:"pane"`
+	State  string    `json:"state"`
+	Since  time.Time `json:"since"`
+}
+
+// ReapedEvent is the data of an EventReaped.
+type ReapedEvent struct {
+	Session  string `json:"session"`
+	Snapshot string `json:"snapshot"`
+}
+
+// SubscribeParams narrows a subscription to the listed event types; empty
+// means every type.
+type SubscribeParams struct {
+	Types []string `json:"types,omitempty"`
+}
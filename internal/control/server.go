@@ -0,0 +1,246 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxLine caps one request line. Requests are a method and a few params.
+const maxLine = 64 << 10
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// the server drops it. A subscriber that cannot keep up with the daemon's
+// event rate is hung up on rather than allowed to stall the daemon.
+const subscriberBuffer = 64
+
+// ErrInUse is returned by Listen when another process is already serving on
+// the socket path.
+var ErrInUse = errors.New("control socket in use")
+
+// Handler answers the daemon's requests. Handle returns the method's result,
+// or an error: a *Error is sent as-is, anything else as ErrCodeFailed.
+type Handler interface {
+	Handle(method string, params json.RawMessage) (any, error)
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(method string, params json.RawMessage) (any, error)
+
+// Handle calls f.
+func (f HandlerFunc) Handle(method string, params json.RawMessage) (any, error) {
+	return f(method, params)
+}
+
+// Listen opens the control socket at path, readable and writable by the user
+// only. The socket is bound under a 077 umask, so it is never reachable by
+// anyone else, not even in the moment before the chmod; the umask is
+// process-wide, but the daemon creates nothing that wants group or other
+// access. A socket file left by a daemon that died is replaced; one a live
+// process still answers on is ErrInUse, so a second daemon never steals the
+// first one's socket.
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrInUse, path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale control socket: %w", err)
+	}
+	old := syscall.Umask(0o077)
+	ln, err := net.Listen("unix", path)
+	syscall.Umask(old)
+	if err != nil {
+		return nil, fmt.Errorf("listen on control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("chmod control socket: %w", err)
+	}
+	return ln, nil
+}
+
+// Server serves the protocol on a listener and fans published events out to
+// subscribers.
+type Server struct {
+	ln      net.Listener
+	handler Handler
+	logger  *slog.Logger
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+// subscriber is one subscribed connection's event queue.
+type subscriber struct {
+	types []string
+	ch    chan Event
+}
+
+// NewServer returns a server on ln dispatching requests to handler.
+// Connection failures are logged to logger at DEBUG: a client hanging up
+// mid-request is routine.
+func NewServer(ln net.Listener, handler Handler, logger *slog.Logger) *Server {
+	return &Server{ln: ln, handler: handler, logger: logger, subs: map[*subscriber]struct{}{}}
+}
+
+// Serve accepts connections until Close, serving each on its own goroutine.
+// It returns nil after Close and the accept error otherwise.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting, ends every subscription and removes the socket
+// file.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for sub := range s.subs {
+		close(sub.ch)
+		delete(s.subs, sub)
+	}
+	s.mu.Unlock()
+	// Closing a unix listener unlinks its socket file.
+	return s.ln.Close()
+}
+
+// Publish sends an event to every subscriber interested in its type. It never
+// blocks: a subscriber whose queue is full is dropped.
+func (s *Server) Publish(typ string, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Warn("marshal control event failed", "event", typ, "error", err)
+		return
+	}
+	ev := Event{V: Version, Type: typ, At: time.Now().UTC(), Data: raw}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, typ) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			s.logger.Debug("control subscriber too slow; dropped", "event", typ)
+			close(sub.ch)
+			delete(s.subs, sub)
+		}
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxLine)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			_ = enc.Encode(errorResponse(0, ErrCodeParams, "malformed request: "+err.Error()))
+			return
+		}
+		if req.V != Version {
+			_ = enc.Encode(errorResponse(req.ID, ErrCodeVersion, fmt.Sprintf("daemon speaks protocol %d, request is %d", Version, req.V)))
+			continue
+		}
+		if req.Method == MethodSubscribe {
+			s.stream(scanner, enc, req)
+			return
+		}
+		if err := enc.Encode(s.dispatch(req)); err != nil {
+			s.logger.Debug("control write failed", "method", req.Method, "error", err)
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		s.logger.Debug("control read failed", "error", err)
+	}
+}
+
+// dispatch runs one non-streaming request through the handler.
+func (s *Server) dispatch(req Request) Response {
+	result, err := s.handler.Handle(req.Method, req.Params)
+	if err != nil {
+		var cerr *Error
+		if errors.As(err, &cerr) {
+			return Response{V: Version, ID: req.ID, Error: cerr}
+		}
+		return errorResponse(req.ID, ErrCodeFailed, err.Error())
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, ErrCodeFailed, "marshal result: "+err.Error())
+	}
+	return Response{V: Version, ID: req.ID, Result: raw}
+}
+
+// stream turns the connection into an event stream. It ends when the client
+// hangs up (the read side hits EOF), when the subscriber is dropped, or when
+// the server closes.
+func (s *Server) stream(scanner *bufio.Scanner, enc *json.Encoder, req Request) {
+	var params SubscribeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			_ = enc.Encode(errorResponse(req.ID, ErrCodeParams, err.Error()))
+			return
+		}
+	}
+	sub := &subscriber{types: params.Types, ch: make(chan Event, subscriberBuffer)}
+	s.mu.Lock()
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+	defer s.unsubscribe(sub)
+
+	if err := enc.Encode(Response{V: Version, ID: req.ID, Result: json.RawMessage("{}")}); err != nil {
+		return
+	}
+	hangup := make(chan struct{})
+	go func() {
+		for scanner.Scan() {
+		}
+		close(hangup)
+	}()
+	for {
+		select {
+		case ev, ok := <-sub.ch:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(ev); err != nil {
+				return
+			}
+		case <-hangup:
+			return
+		}
+	}
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		close(sub.ch)
+		delete(s.subs, sub)
+	}
+}
+
+func errorResponse(id int64, code, message string) Response {
+	return Response{V: Version, ID: id, Error: &Error{Code: code, Message: message}}
+}
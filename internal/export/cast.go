@@ -0,0 +1,78 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/tmuxout"
+)
+
+// The saved index records no pane dimensions, so a cast is sized to its
+// content: wide enough for the longest line and tall enough that the whole
+// scrollback is on screen when playback ends, within these bounds.
+const (
+	castMinWidth  = 80
+	castMinHeight = 24
+	castMaxHeight = 1000
+)
+
+// castFrameInterval is the playback time between panes of a multi-pane cast.
+const castFrameInterval = 1.0
+
+// castHeader is the first line of an asciinema v2 file.
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// writeCast writes sel as an asciinema v2 recording: a header line, then one
+// output event per pane carrying its scrollback with the newlines a terminal
+// needs (CRLF). Each pane after the first clears the screen and is labelled,
+// so playback steps through the panes in order.
+func writeCast(w io.Writer, sel Selection) error {
+	width, height := castMinWidth, castMinHeight
+	for _, p := range sel.Panes {
+		ls := lines(string(p.Content))
+		for _, line := range ls {
+			width = max(width, ansi.StringWidth(tmuxout.StripANSI(line)))
+		}
+		if len(sel.Panes) > 1 {
+			ls = append(ls, "") // the heading row
+		}
+		height = max(height, min(len(ls), castMaxHeight))
+	}
+	hdr := castHeader{Version: 2, Width: width, Height: height, Title: sel.Title}
+	if !sel.SavedAt.IsZero() {
+		hdr.Timestamp = sel.SavedAt.Unix()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(hdr); err != nil {
+		return err
+	}
+	for i, p := range sel.Panes {
+		var b strings.Builder
+		if len(sel.Panes) > 1 {
+			if i > 0 {
+				b.WriteString("\x1b[2J\x1b[3J\x1b[H")
+			}
+			b.WriteString("\x1b[1m==> " + paneHeading(p) + " <==" + sgrReset + "\r\n")
+		}
+		for _, line := range lines(string(p.Content)) {
+			b.WriteString(line)
+			b.WriteString("\r\n")
+		}
+		b.WriteString(sgrReset)
+		event := []any{float64(i) * castFrameInterval, "o", b.String()}
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
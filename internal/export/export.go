@@ -0,0 +1,225 @@
+// Package export renders saved pane scrollback into a shareable form: plain
+// text, the raw ANSI stream, a self-contained HTML page that keeps the
+// colours, or an asciinema v2 cast. The input is the state directory the
+// daemon writes — sessions.json plus one opaque scrollback file per pane — so
+// dormant sessions export as readily as running ones.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tmuxout"
+)
+
+// Format names an output format.
+type Format string
+
+const (
+	// Text is the scrollback with every escape sequence stripped.
+	Text Format = "txt"
+	// ANSI is the scrollback with its escape sequences intact, colours and all.
+	ANSI Format = "ansi"
+	// HTML is a standalone page with the colours rendered as inline styles.
+	HTML Format = "html"
+	// Cast is an asciinema v2 recording that replays the scrollback.
+	Cast Format = "cast"
+)
+
+// Formats lists every format in the order help text presents them.
+var Formats = []Format{Text, ANSI, HTML, Cast}
+
+// ParseFormat resolves a --format value.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (want txt, ansi, html or cast)", s)
+}
+
+// Pane is one exported pane: its raw tmux indices and its raw scrollback.
+type Pane struct {
+	Session    string
+	Window     int
+	WindowName string
+	Pane       int
+	Content    []byte
+}
+
+// Target returns the plain "session:window.pane" form of the pane.
+func (p Pane) Target() string {
+	return tmux.PaneTarget(p.Session, p.Window, p.Pane)
+}
+
+// Selection is what one export covers: the panes a selector chose, in index
+// order, titled by the selector and stamped with the session's last save.
+type Selection struct {
+	Title   string
+	SavedAt time.Time
+	Panes   []Pane
+}
+
+// Select reads the panes named by selector from the state directory. The
+// selector is "session" for every pane of the session, "session:window" for
+// every pane of one window, or "session:window.pane" for one pane. A pane the
+// daemon has not saved yet exports empty rather than failing the export.
+func Select(stateDir, selector string) (Selection, error) {
+	name, window, pane, err := parseSelector(selector)
+	if err != nil {
+		return Selection{}, err
+	}
+	idx, _, err := state.ReadIndex(stateDir)
+	if err != nil {
+		return Selection{}, err
+	}
+
+	sel := Selection{Title: selector}
+	for _, sess := range idx.Sessions {
+		if sess.Name != name {
+			continue
+		}
+		sel.SavedAt = idx.SavedAt
+		if sess.Dormant {
+			sel.SavedAt = sess.SavedAt
+		}
+		for _, w := range sess.Windows {
+			if window >= 0 && w.Index != window {
+				continue
+			}
+			for _, p := range w.Panes {
+				if pane >= 0 && p.Index != pane {
+					continue
+				}
+				content, err := readPaneScrollback(stateDir, p)
+				if err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return Selection{}, err
+				}
+				sel.Panes = append(sel.Panes, Pane{Session: name, Window: w.Index, WindowName: w.Name, Pane: p.Index, Content: content})
+			}
+		}
+		if len(sel.Panes) == 0 {
+			return Selection{}, fmt.Errorf("no saved pane matches %q", selector)
+		}
+		return sel, nil
+	}
+	return Selection{}, fmt.Errorf("no saved session %q", name)
+}
+
+// readPaneScrollback reads the scrollback file sessions.json records for p,
+// resolved against stateDir as restore resolves it. A pane with no recorded
+// file has no scrollback.
+func readPaneScrollback(stateDir string, p state.Pane) ([]byte, error) {
+	if p.ScrollbackFile == "" {
+		return nil, nil
+	}
+	return state.ReadScrollback(filepath.Join(stateDir, p.ScrollbackFile))
+}
+
+// parseSelector splits "session[:window[.pane]]", returning -1 for an index
+// the selector leaves open. tmux does not allow a colon in a session name, so
+// the first one always starts the window.
+func parseSelector(selector string) (name string, window, pane int, err error) {
+	name, rest, found := strings.Cut(selector, ":")
+	if name == "" {
+		return "", 0, 0, fmt.Errorf("invalid selector %q: missing session name", selector)
+	}
+	if !found {
+		return name, -1, -1, nil
+	}
+	winStr, paneStr, hasPane := strings.Cut(rest, ".")
+	if window, err = strconv.Atoi(winStr); err != nil || window < 0 {
+		return "", 0, 0, fmt.Errorf("invalid selector %q: want session[:window[.pane]]", selector)
+	}
+	pane = -1
+	if hasPane {
+		if pane, err = strconv.Atoi(paneStr); err != nil || pane < 0 {
+			return "", 0, 0, fmt.Errorf("invalid selector %q: want session[:window[.pane]]", selector)
+		}
+	}
+	return name, window, pane, nil
+}
+
+// Write renders sel to w in format f. Exports of more than one pane label
+// each pane with its target; a single pane is written bare.
+func Write(w io.Writer, f Format, sel Selection) error {
+	switch f {
+	case Text:
+		return writeText(w, sel)
+	case ANSI:
+		return writeANSI(w, sel)
+	case HTML:
+		return writeHTML(w, sel)
+	case Cast:
+		return writeCast(w, sel)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+// paneHeading is the label written above each pane of a multi-pane export.
+func paneHeading(p Pane) string {
+	if p.WindowName == "" {
+		return p.Target()
+	}
+	return p.Target() + " (" + p.WindowName + ")"
+}
+
+// lines returns the pane's content as lines, without the trailing blank rows
+// a capture of a part-filled screen ends with.
+func lines(content string) []string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+func writeText(w io.Writer, sel Selection) error {
+	var b strings.Builder
+	for i, p := range sel.Panes {
+		if len(sel.Panes) > 1 {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "==> %s <==\n", paneHeading(p))
+		}
+		for _, line := range lines(tmuxout.StripANSI(string(p.Content))) {
+			b.WriteString(strings.TrimRight(line, " "))
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sgrReset ends each pane of an ANSI export so colour left set at the end of
+// one pane does not bleed into the next heading or the reader's prompt.
+const sgrReset = "\x1b[0m"
+
+func writeANSI(w io.Writer, sel Selection) error {
+	var b strings.Builder
+	for i, p := range sel.Panes {
+		if len(sel.Panes) > 1 {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "\x1b[1m==> %s <==%s\n", paneHeading(p), sgrReset)
+		}
+		for _, line := range lines(string(p.Content)) {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString(sgrReset)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
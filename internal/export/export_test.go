@@ -0,0 +1,246 @@
+package export_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/export"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// writeSavedState writes a sessions.json for sessions plus a raw scrollback
+// file for each entry in content, returning the state dir.
+func writeSavedState(t *testing.T, sessions []state.Session, content map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	recordScrollbackFiles(sessions)
+	data, err := state.EncodeIndex(state.Index{Version: state.SchemaVersion, SavedAt: time.Unix(1700000000, 0), Sessions: sessions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for key, c := range content {
+		if err := os.WriteFile(state.ScrollbackFile(dir, key), []byte(c), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// recordScrollbackFiles gives every pane without one the scrollback file a
+// capture would record for it.
+func recordScrollbackFiles(sessions []state.Session) {
+	for _, s := range sessions {
+		for _, w := range s.Windows {
+			for i := range w.Panes {
+				if w.Panes[i].ScrollbackFile == "" {
+					w.Panes[i].ScrollbackFile = "scrollback/" + state.SanitizePaneKey(s.Name, w.Index, w.Panes[i].Index) + ".bin"
+				}
+			}
+		}
+	}
+}
+
+func savedWork(t *testing.T) string {
+	t.Helper()
+	return writeSavedState(t,
+		[]state.Session{{Name: "work", Windows: []state.Window{
+			{Index: 0, Name: "editor", Panes: []state.Pane{{Index: 0}, {Index: 1}}},
+			{Index: 1, Name: "build", Panes: []state.Pane{{Index: 0}}},
+		}}},
+		map[string]string{
+			state.SanitizePaneKey("work", 0, 0): "vim\n",
+			state.SanitizePaneKey("work", 1, 0): "\x1b[31mFAIL\x1b[0m pkg\n\n\n",
+		},
+	)
+}
+
+func TestSelect(t *testing.T) {
+	dir := savedWork(t)
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"work", []string{"work:0.0", "work:0.1", "work:1.0"}},
+		{"work:0", []string{"work:0.0", "work:0.1"}},
+		{"work:1.0", []string{"work:1.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			sel, err := export.Select(dir, tt.selector)
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			var got []string
+			for _, p := range sel.Panes {
+				got = append(got, p.Target())
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("panes = %v, want %v", got, tt.want)
+			}
+			if sel.Title != tt.selector || sel.SavedAt.Unix() != 1700000000 {
+				t.Errorf("title, savedAt = %q, %v", sel.Title, sel.SavedAt)
+			}
+		})
+	}
+
+	t.Run("reads the scrollback file sessions.json records", func(t *testing.T) {
+		dir := writeSavedState(t,
+			[]state.Session{{Name: "work", Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, ScrollbackFile: "scrollback/elsewhere.bin"}}}}}},
+			map[string]string{"elsewhere": "moved\n"},
+		)
+		sel, err := export.Select(dir, "work")
+		if err != nil || len(sel.Panes) != 1 || string(sel.Panes[0].Content) != "moved\n" {
+			t.Errorf("sel, err = %+v, %v; want the recorded file's content", sel, err)
+		}
+	})
+
+	t.Run("a pane with no saved file exports empty", func(t *testing.T) {
+		sel, err := export.Select(dir, "work:0.1")
+		if err != nil || len(sel.Panes) != 1 || len(sel.Panes[0].Content) != 0 {
+			t.Errorf("sel, err = %+v, %v; want one empty pane", sel, err)
+		}
+	})
+
+	errs := []struct {
+		selector string
+		want     string
+	}{
+		{"api", `no saved session "api"`},
+		{"work:2", `no saved pane matches "work:2"`},
+		{"work:0.9", `no saved pane matches "work:0.9"`},
+		{"work:x", "invalid selector"},
+		{"work:0.", "invalid selector"},
+		{":0.0", "missing session name"},
+	}
+	for _, tt := range errs {
+		t.Run(tt.selector, func(t *testing.T) {
+			if _, err := export.Select(dir, tt.selector); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range export.Formats {
+		if got, err := export.ParseFormat(string(f)); err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %q, %v", f, got, err)
+		}
+	}
+	if _, err := export.ParseFormat("pdf"); err == nil {
+		t.Error("ParseFormat(pdf) succeeded")
+	}
+}
+
+func render(t *testing.T, f export.Format, sel export.Selection) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := export.Write(&buf, f, sel); err != nil {
+		t.Fatalf("Write(%s): %v", f, err)
+	}
+	return buf.String()
+}
+
+var (
+	onePane  = export.Selection{Title: "work:1.0", Panes: []export.Pane{{Session: "work", Window: 1, WindowName: "build", Content: []byte("\x1b[1;31mFAIL\x1b[0m <pkg>  \n\n")}}}
+	twoPanes = export.Selection{Title: "work", Panes: []export.Pane{
+		{Session: "work", Window: 0, WindowName: "editor", Content: []byte("vim\n")},
+		{Session: "work", Window: 1, Content: []byte("\x1b[32mok\n")},
+	}}
+)
+
+func TestWrite_Text(t *testing.T) {
+	if got := render(t, export.Text, onePane); got != "FAIL <pkg>\n" {
+		t.Errorf("single pane = %q", got)
+	}
+	want := "==> work:0.0 (editor) <==\nvim\n\n==> work:1.0 <==\nok\n"
+	if got := render(t, export.Text, twoPanes); got != want {
+		t.Errorf("two panes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestWrite_ANSI(t *testing.T) {
+	got := render(t, export.ANSI, twoPanes)
+	want := "\x1b[1m==> work:0.0 (editor) <==\x1b[0m\nvim\n\x1b[0m\n\x1b[1m==> work:1.0 <==\x1b[0m\n\x1b[32mok\n\x1b[0m"
+	if got != want {
+		t.Errorf("two panes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestWrite_HTML(t *testing.T) {
+	got := render(t, export.HTML, export.Selection{Title: "a<b>", Panes: []export.Pane{{Session: "s", Content: []byte(
+		"\x1b[1;31mFAIL\x1b[0m <pkg>\n" +
+			"\x1b[38;5;208mamber\x1b[39m \x1b[38;2;1;2;3;48:2::4:5:6mrgb\x1b[m\n" +
+			"\x1b[7mrev\x1b[27m \x1b[3;4mit\x1b[K\x1b[0m\n")}}})
+
+	for _, want := range []string{
+		"<title>a&lt;b&gt;</title>",
+		`<span style="color:#cd0000;font-weight:bold">FAIL</span> &lt;pkg&gt;`,
+		`<span style="color:#ff8700">amber</span>`,
+		`<span style="color:#010203;background:#040506">rgb</span>`,
+		`<span style="color:#1c1c1c;background:#d0d0d0">rev</span>`,
+		`<span style="font-style:italic;text-decoration:underline">it</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("html missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b") || strings.Contains(got, "<h2>") {
+		t.Errorf("single-pane html has escapes or a heading:\n%s", got)
+	}
+	if !strings.Contains(render(t, export.HTML, twoPanes), "<h2>work:0.0 (editor)</h2>") {
+		t.Error("multi-pane html has no pane heading")
+	}
+}
+
+func TestWrite_Cast(t *testing.T) {
+	wide := strings.Repeat("x", 120)
+	sel := twoPanes
+	sel.SavedAt = time.Unix(1700000000, 0)
+	sel.Panes = append([]export.Pane{}, sel.Panes...)
+	sel.Panes[1].Content = []byte("\x1b[32m" + wide + "\x1b[0m\n")
+
+	sc := bufio.NewScanner(strings.NewReader(render(t, export.Cast, sel)))
+	sc.Buffer(nil, 1<<20)
+	var lines []string
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) != 3 {
+		t.Fatalf("cast has %d lines, want header + 2 events:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+
+	var hdr map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &hdr); err != nil {
+		t.Fatal(err)
+	}
+	if hdr["version"] != 2.0 || hdr["width"] != 120.0 || hdr["height"] != 24.0 || hdr["timestamp"] != 1700000000.0 || hdr["title"] != "work" {
+		t.Errorf("header = %v", hdr)
+	}
+
+	var first, second []any
+	if err := json.Unmarshal([]byte(lines[1]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &second); err != nil {
+		t.Fatal(err)
+	}
+	if first[0] != 0.0 || first[1] != "o" || first[2] != "\x1b[1m==> work:0.0 (editor) <==\x1b[0m\r\nvim\r\n\x1b[0m" {
+		t.Errorf("first event = %q", first)
+	}
+	if second[0] != 1.0 || !strings.HasPrefix(second[2].(string), "\x1b[2J") {
+		t.Errorf("second event = %q, want a screen clear at t=1", second)
+	}
+}
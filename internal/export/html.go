@@ -0,0 +1,260 @@
+package export
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/leeovery/portal/internal/tmuxout"
+)
+
+// The HTML page's own colours: the default foreground and background a pane
+// renders with when no SGR colour is set, which reverse video swaps.
+const (
+	htmlForeground = "#d0d0d0"
+	htmlBackground = "#1c1c1c"
+)
+
+// basePalette is the xterm default for the 16 standard and bright colours.
+var basePalette = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// paletteColour resolves an index into the xterm 256-colour palette: the 16
+// base colours, the 6×6×6 cube, then the 24-step grey ramp.
+func paletteColour(n int) string {
+	switch {
+	case n < 16:
+		return basePalette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return rgbColour(level(n/36), level(n/6%6), level(n%6))
+	default:
+		g := 8 + (n-232)*10
+		return rgbColour(g, g, g)
+	}
+}
+
+func rgbColour(r, g, b int) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// sgrState is the graphic rendition in effect at a point in the stream. An
+// empty colour is the default.
+type sgrState struct {
+	fg, bg                                   string
+	bold, dim, italic, underline, strike, rv bool
+}
+
+// apply folds one SGR parameter string into the state. Unknown parameters are
+// ignored, as a terminal ignores them.
+func (s *sgrState) apply(params string) {
+	if params == "" {
+		*s = sgrState{}
+		return
+	}
+	ps := strings.Split(params, ";")
+	for i := 0; i < len(ps); i++ {
+		// Colon sub-parameters (38:2::r:g:b, 4:3) carry a whole colour or
+		// underline style in one parameter.
+		if sub := strings.Split(ps[i], ":"); len(sub) > 1 {
+			s.applySub(sub)
+			continue
+		}
+		n, err := strconv.Atoi(ps[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 0:
+			*s = sgrState{}
+		case n == 1:
+			s.bold = true
+		case n == 2:
+			s.dim = true
+		case n == 3:
+			s.italic = true
+		case n == 4:
+			s.underline = true
+		case n == 7:
+			s.rv = true
+		case n == 9:
+			s.strike = true
+		case n == 22:
+			s.bold, s.dim = false, false
+		case n == 23:
+			s.italic = false
+		case n == 24:
+			s.underline = false
+		case n == 27:
+			s.rv = false
+		case n == 29:
+			s.strike = false
+		case n >= 30 && n <= 37:
+			s.fg = basePalette[n-30]
+		case n == 39:
+			s.fg = ""
+		case n >= 40 && n <= 47:
+			s.bg = basePalette[n-40]
+		case n == 49:
+			s.bg = ""
+		case n >= 90 && n <= 97:
+			s.fg = basePalette[n-90+8]
+		case n >= 100 && n <= 107:
+			s.bg = basePalette[n-100+8]
+		case n == 38 || n == 48:
+			colour, used := extendedColour(ps[i+1:])
+			i += used
+			if colour == "" {
+				continue
+			}
+			if n == 38 {
+				s.fg = colour
+			} else {
+				s.bg = colour
+			}
+		}
+	}
+}
+
+// applySub handles one colon-form parameter.
+func (s *sgrState) applySub(sub []string) {
+	switch sub[0] {
+	case "4":
+		s.underline = sub[1] != "0"
+	case "38", "48":
+		args := sub[1:]
+		// The truecolor form may carry a colour-space id: 38:2:<id>:r:g:b.
+		if len(args) == 5 && args[0] == "2" {
+			args = append([]string{"2"}, args[2:]...)
+		}
+		if colour, _ := extendedColour(args); colour != "" {
+			if sub[0] == "38" {
+				s.fg = colour
+			} else {
+				s.bg = colour
+			}
+		}
+	}
+}
+
+// extendedColour parses the arguments after a 38 or 48: "5;n" for a palette
+// index or "2;r;g;b" for truecolor. It returns the colour ("" when malformed)
+// and how many arguments it consumed.
+func extendedColour(args []string) (string, int) {
+	num := func(i int) (int, bool) {
+		if i >= len(args) {
+			return 0, false
+		}
+		n, err := strconv.Atoi(args[i])
+		return n, err == nil && n >= 0 && n <= 255
+	}
+	if len(args) == 0 {
+		return "", 0
+	}
+	switch args[0] {
+	case "5":
+		if n, ok := num(1); ok {
+			return paletteColour(n), 2
+		}
+		return "", min(len(args), 2)
+	case "2":
+		r, okR := num(1)
+		g, okG := num(2)
+		b, okB := num(3)
+		if okR && okG && okB {
+			return rgbColour(r, g, b), 4
+		}
+		return "", min(len(args), 4)
+	}
+	return "", 1
+}
+
+// style renders the state as an inline CSS declaration list, "" for the
+// default rendition.
+func (s sgrState) style() string {
+	fg, bg := s.fg, s.bg
+	if s.rv {
+		fg, bg = cmp.Or(bg, htmlBackground), cmp.Or(fg, htmlForeground)
+	}
+	var decls []string
+	if fg != "" {
+		decls = append(decls, "color:"+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background:"+bg)
+	}
+	if s.bold {
+		decls = append(decls, "font-weight:bold")
+	}
+	if s.dim {
+		decls = append(decls, "opacity:.6")
+	}
+	if s.italic {
+		decls = append(decls, "font-style:italic")
+	}
+	switch {
+	case s.underline && s.strike:
+		decls = append(decls, "text-decoration:underline line-through")
+	case s.underline:
+		decls = append(decls, "text-decoration:underline")
+	case s.strike:
+		decls = append(decls, "text-decoration:line-through")
+	}
+	return strings.Join(decls, ";")
+}
+
+// htmlPane renders one pane's content as the inside of a <pre>: text
+// HTML-escaped, each run under a non-default rendition wrapped in a styled
+// span. Styles carry across lines, as they do in the terminal.
+func htmlPane(content string) string {
+	var b strings.Builder
+	var st sgrState
+	tmuxout.WalkSGR(strings.Join(lines(content), "\n"), func(text string) {
+		escaped := html.EscapeString(text)
+		if css := st.style(); css != "" {
+			fmt.Fprintf(&b, `<span style="%s">%s</span>`, css, escaped)
+			return
+		}
+		b.WriteString(escaped)
+	}, st.apply)
+	return b.String()
+}
+
+func writeHTML(w io.Writer, sel Selection) error {
+	var b strings.Builder
+	title := html.EscapeString(sel.Title)
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { margin: 0; padding: 1em; background: %s; color: %s; }
+h2 { font: bold 13px ui-monospace, SFMono-Regular, Menlo, monospace; color: #8a8a8a; margin: 1.5em 0 .5em; }
+h2:first-child { margin-top: 0; }
+pre { margin: 0; font: 13px/1.35 ui-monospace, SFMono-Regular, Menlo, monospace; white-space: pre; }
+</style>
+</head>
+<body>
+`, title, htmlBackground, htmlForeground)
+	for _, p := range sel.Panes {
+		if len(sel.Panes) > 1 {
+			fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(paneHeading(p)))
+		}
+		fmt.Fprintf(&b, "<pre>%s</pre>\n", htmlPane(string(p.Content)))
+	}
+	b.WriteString("</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,235 @@
+// Package frecency keeps Portal's own frecency scores — visit count weighted by
+// recency — for the sessions it attaches and the directories it mints sessions
+// in, persisted to frecency.json.
+//
+// Each visit adds one to an entry's rank after decaying the existing rank by the
+// time since the last visit, so an entry's score halves every HalfLife without a
+// visit. A directory visited daily this week outranks one visited fifty times
+// last year, while a steady favourite still outranks a one-off.
+//
+// Like internal/prefs, the package is a pure leaf — it imports only the standard
+// library and internal/fileutil — so internal/tui and internal/resolver can read
+// it without an import cycle. frecency.json is not part of the closed
+// state-mutation audit-trail set, so the store emits no breadcrumbs.
+package frecency
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/leeovery/portal/internal/fileutil"
+)
+
+// HalfLife is how long an entry's score takes to halve without a visit.
+const HalfLife = 7 * 24 * time.Hour
+
+// maxEntries caps frecency.json. Past the cap, Record drops the lowest-scoring
+// entries so the file stays small however many throwaway sessions come and go.
+const maxEntries = 500
+
+// Kind discriminates what an entry's Key names.
+type Kind string
+
+const (
+	// KindSession keys an entry by tmux session name (an attach).
+	KindSession Kind = "session"
+	// KindDir keys an entry by directory path (a project mint).
+	KindDir Kind = "dir"
+)
+
+// ErrNoMatch indicates Query found no recorded directory matching the terms.
+var ErrNoMatch = errors.New("no frecency match found")
+
+// Entry is one scored session or directory.
+type Entry struct {
+	Kind      Kind      `json:"kind"`
+	Key       string    `json:"key"`
+	Visits    int       `json:"visits"`
+	Rank      float64   `json:"rank"`
+	LastVisit time.Time `json:"last_visit"`
+}
+
+// Score returns the entry's rank decayed to now.
+func (e Entry) Score(now time.Time) float64 {
+	return e.Rank * decay(now.Sub(e.LastVisit))
+}
+
+// decay is the multiplier HalfLife-based decay applies over elapsed. A
+// negative elapsed (a clock step backwards) decays nothing.
+func decay(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(elapsed) / float64(HalfLife))
+}
+
+// frecencyFile is the on-disk JSON structure for frecency.json.
+type frecencyFile struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Store manages persistence of frecency entries to a JSON file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store that reads and writes to the given file path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the entries from frecency.json. A missing file (the first-run
+// state) and an empty or corrupt file both yield no entries and no error; only
+// a non-ErrNotExist read error is propagated.
+func (s *Store) Load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var f frecencyFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, nil
+	}
+	return f.Entries, nil
+}
+
+// lock takes an exclusive flock on frecency.json's sidecar lock file, blocking
+// until it is free, and returns the func that releases it. The lock lives
+// beside the data file rather than on it because AtomicWrite replaces the data
+// file's inode on every write.
+func (s *Store) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create frecency directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open frecency lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock frecency: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
+
+// Record registers one visit to the kind/key entry at now, creating it on the
+// first visit, and persists the result via AtomicWrite. Past maxEntries the
+// lowest-scoring entries are dropped.
+//
+// The read-modify-write runs under lock, so concurrent Records — the attaches
+// of a multi-select burst, each its own process — all land instead of the last
+// write clobbering the others.
+func (s *Store) Record(kind Kind, key string, now time.Time) error {
+	if key == "" {
+		return nil
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+	i := slices.IndexFunc(entries, func(e Entry) bool { return e.Kind == kind && e.Key == key })
+	if i < 0 {
+		entries = append(entries, Entry{Kind: kind, Key: key})
+		i = len(entries) - 1
+	}
+	e := &entries[i]
+	e.Rank = e.Score(now) + 1
+	e.Visits++
+	e.LastVisit = now
+
+	if len(entries) > maxEntries {
+		slices.SortStableFunc(entries, func(a, b Entry) int {
+			return cmp.Compare(b.Score(now), a.Score(now))
+		})
+		entries = entries[:maxEntries]
+	}
+
+	data, err := json.MarshalIndent(frecencyFile{Entries: entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal frecency: %w", err)
+	}
+	return fileutil.AtomicWrite(s.path, data)
+}
+
+// Query returns the highest-scoring recorded directory matching terms, the
+// zoxide matching rule applied to Portal's own history: every
+// whitespace-separated term must appear in the path, case-insensitively and in
+// order, and the last term must fall within the final path component. It
+// returns ErrNoMatch when nothing matches (including an empty store).
+func (s *Store) Query(terms string) (string, error) {
+	entries, err := s.Load()
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(strings.ToLower(terms))
+	if len(fields) == 0 {
+		return "", ErrNoMatch
+	}
+
+	now := time.Now()
+	best, bestScore := "", 0.0
+	for _, e := range entries {
+		if e.Kind != KindDir || !matchesTerms(e.Key, fields) {
+			continue
+		}
+		if score := e.Score(now); best == "" || score > bestScore {
+			best, bestScore = e.Key, score
+		}
+	}
+	if best == "" {
+		return "", ErrNoMatch
+	}
+	return best, nil
+}
+
+// matchesTerms applies Query's matching rule to one path.
+func matchesTerms(path string, terms []string) bool {
+	lower := strings.ToLower(path)
+	pos := 0
+	for _, t := range terms {
+		i := strings.Index(lower[pos:], t)
+		if i < 0 {
+			return false
+		}
+		pos += i + len(t)
+	}
+	last := terms[len(terms)-1]
+	return strings.Contains(strings.ToLower(filepath.Base(path)), last)
+}
+
+// Scores returns the decayed score at now of every entry of kind, keyed by
+// entry key. An entry that was never recorded has no key in the map, so a
+// lookup yields zero.
+func Scores(entries []Entry, kind Kind, now time.Time) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, e := range entries {
+		if e.Kind == kind {
+			scores[e.Key] = e.Score(now)
+		}
+	}
+	return scores
+}
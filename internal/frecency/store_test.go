@@ -0,0 +1,220 @@
+package frecency_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/frecency"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("returns no entries for a missing file", func(t *testing.T) {
+		store := frecency.NewStore(filepath.Join(t.TempDir(), "nonexistent", "frecency.json"))
+
+		entries, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("entries = %v, want none", entries)
+		}
+	})
+
+	t.Run("returns no entries for corrupt JSON", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "frecency.json")
+		if err := os.WriteFile(filePath, []byte("{invalid json!!!"), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+
+		entries, err := frecency.NewStore(filePath).Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("entries = %v, want none", entries)
+		}
+	})
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("creates the file and entry on the first visit", func(t *testing.T) {
+		store := frecency.NewStore(filepath.Join(t.TempDir(), "sub", "frecency.json"))
+
+		if err := store.Record(frecency.KindDir, "/code/portal", now); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+
+		entries, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("len(entries) = %d, want 1", len(entries))
+		}
+		e := entries[0]
+		if e.Kind != frecency.KindDir || e.Key != "/code/portal" || e.Visits != 1 || e.Rank != 1 || !e.LastVisit.Equal(now) {
+			t.Errorf("entry = %+v, want one dir visit at now", e)
+		}
+	})
+
+	t.Run("decays the existing rank before adding a visit", func(t *testing.T) {
+		store := frecency.NewStore(filepath.Join(t.TempDir(), "frecency.json"))
+
+		if err := store.Record(frecency.KindSession, "portal", now); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if err := store.Record(frecency.KindSession, "portal", now.Add(frecency.HalfLife)); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+
+		entries, _ := store.Load()
+		if len(entries) != 1 {
+			t.Fatalf("len(entries) = %d, want 1", len(entries))
+		}
+		if e := entries[0]; e.Visits != 2 || math.Abs(e.Rank-1.5) > 1e-9 {
+			t.Errorf("entry = %+v, want 2 visits at rank 1.5", e)
+		}
+	})
+
+	t.Run("keys sessions and directories separately", func(t *testing.T) {
+		store := frecency.NewStore(filepath.Join(t.TempDir(), "frecency.json"))
+
+		_ = store.Record(frecency.KindSession, "portal", now)
+		_ = store.Record(frecency.KindDir, "portal", now)
+
+		entries, _ := store.Load()
+		if len(entries) != 2 {
+			t.Errorf("len(entries) = %d, want 2", len(entries))
+		}
+	})
+
+	t.Run("ignores an empty key", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "frecency.json")
+
+		if err := frecency.NewStore(filePath).Record(frecency.KindDir, "", now); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+		if _, err := os.Stat(filePath); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Stat err = %v, want the file left unwritten", err)
+		}
+	})
+}
+
+func TestRecord_Concurrent(t *testing.T) {
+	store := frecency.NewStore(filepath.Join(t.TempDir(), "frecency.json"))
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.Record(frecency.KindSession, fmt.Sprintf("s%d", i), now); err != nil {
+				t.Errorf("Record: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(entries) != n {
+		t.Errorf("len(entries) = %d, want every concurrent visit recorded (%d)", len(entries), n)
+	}
+}
+
+func TestEntryScore(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	e := frecency.Entry{Rank: 8, LastVisit: now}
+
+	cases := []struct {
+		at   time.Time
+		want float64
+	}{
+		{now, 8},
+		{now.Add(frecency.HalfLife), 4},
+		{now.Add(3 * frecency.HalfLife), 1},
+		{now.Add(-time.Hour), 8},
+	}
+	for _, c := range cases {
+		if got := e.Score(c.at); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("Score(%v) = %v, want %v", c.at, got, c.want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	store := frecency.NewStore(filepath.Join(t.TempDir(), "frecency.json"))
+	now := time.Now()
+	for range 3 {
+		_ = store.Record(frecency.KindDir, "/code/portal", now)
+	}
+	_ = store.Record(frecency.KindDir, "/code/portal-web", now)
+	_ = store.Record(frecency.KindDir, "/work/Api", now)
+	_ = store.Record(frecency.KindSession, "notes", now)
+
+	cases := []struct {
+		name  string
+		terms string
+		want  string
+	}{
+		{"highest score wins among matches", "portal", "/code/portal"},
+		{"a narrower term picks the only match", "web", "/code/portal-web"},
+		{"matching is case-insensitive", "api", "/work/Api"},
+		{"terms match in order", "code web", "/code/portal-web"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := store.Query(c.terms)
+			if err != nil {
+				t.Fatalf("Query(%q): %v", c.terms, err)
+			}
+			if got != c.want {
+				t.Errorf("Query(%q) = %q, want %q", c.terms, got, c.want)
+			}
+		})
+	}
+
+	noMatch := []struct {
+		name  string
+		terms string
+	}{
+		{"nothing recorded matches", "zzz"},
+		{"the last term must be in the basename", "code"},
+		{"terms out of order", "web code"},
+		{"session entries are not directories", "notes"},
+		{"empty query", "  "},
+	}
+	for _, c := range noMatch {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := store.Query(c.terms); !errors.Is(err, frecency.ErrNoMatch) {
+				t.Errorf("Query(%q) err = %v, want ErrNoMatch", c.terms, err)
+			}
+		})
+	}
+}
+
+func TestScores(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	entries := []frecency.Entry{
+		{Kind: frecency.KindSession, Key: "portal", Rank: 2, LastVisit: now},
+		{Kind: frecency.KindDir, Key: "/code/portal", Rank: 5, LastVisit: now},
+	}
+
+	scores := frecency.Scores(entries, frecency.KindSession, now)
+
+	if len(scores) != 1 || scores["portal"] != 2 {
+		t.Errorf("Scores = %v, want only portal at 2", scores)
+	}
+}
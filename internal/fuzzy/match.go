@@ -1,34 +1,241 @@
-// Package fuzzy provides subsequence-based fuzzy matching.
+// Package fuzzy provides scored, fzf-style subsequence matching.
+//
+// A pattern matches a text when every rune of the pattern appears in the text
+// in order. Among all the ways a pattern can be laid over a text, Score picks
+// the alignment with the highest score: each matched rune earns a base score,
+// runs of consecutive matches, matches at the start of a word (after a
+// separator or at a camelCase / digit transition) and a match on the text's
+// first rune earn bonuses, and gaps between matched runes are penalised. A
+// tight, word-aligned match such as "api" in "api-xyz" therefore ranks above
+// a scattered one such as "api" in "a-long-project-impl".
 package fuzzy
 
-import "strings"
+import (
+	"cmp"
+	"slices"
+	"unicode"
+)
+
+// Scoring weights. The values follow fzf's defaults closely enough that
+// rankings feel familiar: a single word-boundary bonus outweighs a short gap,
+// and a consecutive run is worth more than the same runes scattered.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	// bonusPrefix rewards a match on the very first rune of the text.
+	bonusPrefix = 10
+	// bonusBoundary rewards a match on the first rune of a word: a letter or
+	// digit following a separator such as '-', '_', '/', '.' or a space.
+	bonusBoundary = 8
+	// bonusCamel rewards a lower→upper or letter→digit transition.
+	bonusCamel = 7
+	// bonusConsecutive is the floor bonus for every rune after the first in a
+	// run of consecutive matches.
+	bonusConsecutive = 4
+	// bonusFirstCharMultiplier weights the bonus earned by the pattern's first
+	// rune, so where the match starts matters most.
+	bonusFirstCharMultiplier = 2
+)
+
+// unmatched marks an impossible alignment cell in the scoring matrix.
+const unmatched = -1 << 30
+
+// Result is a successful scored match.
+type Result struct {
+	// Score ranks the match; higher is better. It is only comparable with
+	// other scores for the same pattern.
+	Score int
+	// Positions holds the rune indexes into the text of each matched pattern
+	// rune, in ascending order. It is nil for an empty pattern.
+	Positions []int
+}
+
+// Hit is one target matched by Find.
+type Hit struct {
+	// Index is the target's position in the slice passed to Find.
+	Index int
+	Result
+}
 
 // Match returns true if pattern is a subsequence of text.
-// Each character in pattern must appear in text in order,
-// but not necessarily consecutively.
+// Each rune in pattern must appear in text in order,
+// but not necessarily consecutively. Matching is case-sensitive.
 func Match(text, pattern string) bool {
-	pi := 0
-	for i := 0; i < len(text) && pi < len(pattern); i++ {
-		if text[i] == pattern[pi] {
-			pi++
+	_, ok := score([]rune(text), []rune(pattern), false)
+	return ok
+}
+
+// Score reports whether pattern fuzzy-matches text and, if so, the best
+// alignment's score and matched rune positions. Matching is case-insensitive
+// for any script with Unicode case mappings.
+func Score(text, pattern string) (Result, bool) {
+	return score([]rune(text), []rune(pattern), true)
+}
+
+// Find scores pattern against every target and returns the matches sorted by
+// descending score. Ties keep their input order. An empty pattern matches
+// every target with a zero score, so the input order is preserved.
+func Find(pattern string, targets []string) []Hit {
+	p := []rune(pattern)
+	var hits []Hit
+	for i, t := range targets {
+		if r, ok := score([]rune(t), p, true); ok {
+			hits = append(hits, Hit{Index: i, Result: r})
 		}
 	}
-	return pi == len(pattern)
+	slices.SortStableFunc(hits, func(a, b Hit) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return hits
 }
 
-// Filter returns items whose names fuzzy-match the given filter string.
-// Matching is case-insensitive. The nameOf function extracts the name from each item.
-// If filter is empty, all items are returned.
+// Filter returns items whose names fuzzy-match the given filter string,
+// best match first. Matching is case-insensitive. The nameOf function extracts
+// the name from each item. If filter is empty, all items are returned in their
+// original order.
 func Filter[T any](items []T, filter string, nameOf func(T) string) []T {
 	if filter == "" {
 		return items
 	}
-	lowerFilter := strings.ToLower(filter)
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = nameOf(item)
+	}
 	var result []T
-	for _, item := range items {
-		if Match(strings.ToLower(nameOf(item)), lowerFilter) {
-			result = append(result, item)
-		}
+	for _, h := range Find(filter, names) {
+		result = append(result, items[h.Index])
 	}
 	return result
 }
+
+// score computes the best alignment of pattern over text.
+//
+// h[j][i] holds the best score of an alignment that places pattern[j] on
+// text[i], run[j][i] the length of the consecutive match run ending there, and
+// from[j][i] the text index pattern[j-1] sits on in that alignment, for
+// walking the positions back out. Gap penalties are affine, so the best
+// gapped predecessor for each row is carried forward in a single pass.
+func score(text, pattern []rune, fold bool) (Result, bool) {
+	if len(pattern) == 0 {
+		return Result{}, true
+	}
+	if !isSubsequence(text, pattern, fold) {
+		return Result{}, false
+	}
+
+	n, m := len(text), len(pattern)
+	bonus := make([]int, n)
+	for i := range text {
+		bonus[i] = bonusAt(text, i)
+	}
+
+	h := make([][]int, m)
+	run := make([][]int, m)
+	from := make([][]int, m)
+	for j := range m {
+		h[j] = make([]int, n)
+		run[j] = make([]int, n)
+		from[j] = make([]int, n)
+		for i := range n {
+			h[j][i] = unmatched
+		}
+	}
+
+	for j := range m {
+		gapScore, gapFrom := unmatched, -1
+		for i := j; i < n; i++ {
+			// Extend the open gap by one rune, or open a new one right after
+			// a match two runes back.
+			if j > 0 {
+				if gapScore != unmatched {
+					gapScore += scoreGapExtension
+				}
+				if i >= 2 && h[j-1][i-2] != unmatched && h[j-1][i-2]+scoreGapStart > gapScore {
+					gapScore, gapFrom = h[j-1][i-2]+scoreGapStart, i-2
+				}
+			}
+			if !runeEqual(text[i], pattern[j], fold) {
+				continue
+			}
+
+			if j == 0 {
+				h[j][i] = scoreMatch + bonus[i]*bonusFirstCharMultiplier
+				run[j][i] = 1
+				continue
+			}
+
+			if prev := h[j-1][i-1]; prev != unmatched {
+				// A consecutive run keeps the bonus of the rune that started
+				// it, so "api" in "api-xyz" scores the prefix bonus three times.
+				length := run[j-1][i-1] + 1
+				b := max(bonus[i], bonus[i-length+1], bonusConsecutive)
+				h[j][i] = prev + scoreMatch + b
+				run[j][i] = length
+				from[j][i] = i - 1
+			}
+			if gapScore != unmatched && gapScore+scoreMatch+bonus[i] > h[j][i] {
+				h[j][i] = gapScore + scoreMatch + bonus[i]
+				run[j][i] = 1
+				from[j][i] = gapFrom
+			}
+		}
+	}
+
+	end := -1
+	for i := range n {
+		if h[m-1][i] != unmatched && (end < 0 || h[m-1][i] > h[m-1][end]) {
+			end = i
+		}
+	}
+
+	positions := make([]int, m)
+	for j, i := m-1, end; j >= 0; j-- {
+		positions[j] = i
+		i = from[j][i]
+	}
+	return Result{Score: h[m-1][end], Positions: positions}, true
+}
+
+// isSubsequence is the cheap pre-check that rejects a non-match before the
+// scoring matrix is allocated.
+func isSubsequence(text, pattern []rune, fold bool) bool {
+	pi := 0
+	for i := 0; i < len(text) && pi < len(pattern); i++ {
+		if runeEqual(text[i], pattern[pi], fold) {
+			pi++
+		}
+	}
+	return pi == len(pattern)
+}
+
+// runeEqual compares two runes, optionally ignoring Unicode case.
+func runeEqual(a, b rune, fold bool) bool {
+	if a == b {
+		return true
+	}
+	return fold && unicode.ToLower(a) == unicode.ToLower(b)
+}
+
+// bonusAt returns the positional bonus a match on text[i] earns.
+func bonusAt(text []rune, i int) int {
+	if i == 0 {
+		return bonusPrefix
+	}
+	prev, cur := text[i-1], text[i]
+	switch {
+	case isWord(cur) && !isWord(prev):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+// isWord reports whether r is part of a word rather than a separator.
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -1,6 +1,7 @@
 package fuzzy_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/leeovery/portal/internal/fuzzy"
@@ -155,6 +156,12 @@ func TestFilter(t *testing.T) {
 			filter: "echo",
 			want:   []string{"ECHO"},
 		},
+		{
+			name:   "best match ranks first",
+			items:  []string{"a-long-project-impl", "api-xyz"},
+			filter: "api",
+			want:   []string{"api-xyz", "a-long-project-impl"},
+		},
 		{
 			name:   "nil items returns nil for non-empty filter",
 			items:  nil,
@@ -195,3 +202,87 @@ func TestFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestScore(t *testing.T) {
+	t.Run("positions are the best-aligned rune indexes", func(t *testing.T) {
+		tests := []struct {
+			text, pattern string
+			want          []int
+		}{
+			{"api-xyz", "api", []int{0, 1, 2}},
+			{"my-project", "mpr", []int{0, 3, 4}},
+			{"aabbcc", "abc", []int{0, 3, 4}},
+			{"fooBar", "fb", []int{0, 3}},
+			{"café-crème", "cc", []int{0, 5}},
+			{"日本語-テスト", "テス", []int{4, 5}},
+		}
+		for _, tt := range tests {
+			r, ok := fuzzy.Score(tt.text, tt.pattern)
+			if !ok {
+				t.Errorf("Score(%q, %q) did not match", tt.text, tt.pattern)
+				continue
+			}
+			if !slices.Equal(r.Positions, tt.want) {
+				t.Errorf("Score(%q, %q).Positions = %v, want %v", tt.text, tt.pattern, r.Positions, tt.want)
+			}
+		}
+	})
+
+	t.Run("matching is case-insensitive", func(t *testing.T) {
+		if _, ok := fuzzy.Score("ÉCOLE", "éc"); !ok {
+			t.Error("Score(ÉCOLE, éc) did not match, want a case-insensitive match")
+		}
+	})
+
+	t.Run("a non-subsequence does not match", func(t *testing.T) {
+		if _, ok := fuzzy.Score("abc", "cb"); ok {
+			t.Error("Score(abc, cb) matched, want no match")
+		}
+	})
+
+	t.Run("an empty pattern matches with no positions", func(t *testing.T) {
+		r, ok := fuzzy.Score("abc", "")
+		if !ok || r.Score != 0 || r.Positions != nil {
+			t.Errorf("Score(abc, \"\") = (%+v, %v), want a zero match", r, ok)
+		}
+	})
+
+	ranked := []struct {
+		name          string
+		better, worse string
+		pattern       string
+	}{
+		{"a consecutive prefix beats a scattered match", "api-xyz", "a-long-project-impl", "api"},
+		{"a word boundary beats a mid-word match", "my-app", "snapper", "app"},
+		{"a camelCase boundary beats a mid-word match", "fooBar", "foobar", "b"},
+		{"a prefix beats a later boundary", "portal-web", "web-portal", "p"},
+		{"a short gap beats a long gap", "ab-c", "ab----c", "abc"},
+	}
+	for _, tt := range ranked {
+		t.Run(tt.name, func(t *testing.T) {
+			better, _ := fuzzy.Score(tt.better, tt.pattern)
+			worse, _ := fuzzy.Score(tt.worse, tt.pattern)
+			if better.Score <= worse.Score {
+				t.Errorf("Score(%q) = %d, Score(%q) = %d; want the first higher", tt.better, better.Score, tt.worse, worse.Score)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	targets := []string{"a-long-project-impl", "nothing", "api-xyz"}
+
+	hits := fuzzy.Find("api", targets)
+
+	var order []int
+	for _, h := range hits {
+		order = append(order, h.Index)
+	}
+	if want := []int{2, 0}; !slices.Equal(order, want) {
+		t.Errorf("Find(api) order = %v, want %v", order, want)
+	}
+
+	if all := fuzzy.Find("", targets); len(all) != len(targets) || all[0].Index != 0 {
+		t.Errorf("Find(\"\") = %+v, want every target in input order", all)
+	}
+}
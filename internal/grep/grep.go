@@ -0,0 +1,228 @@
+// Package grep searches the scrollback of every tmux pane Portal knows about:
+// the saved scrollback files the daemon writes and, optionally, a live capture
+// of each running pane. Content is ANSI-stripped via internal/tmuxout before
+// matching, so a pattern matches the text a reader sees rather than the
+// escape sequences around it.
+//
+// The search is read-only. Callers pick a Corpus — Saved for the state
+// directory alone, Live to prefer running panes — and feed it to Search with a
+// pattern from Compile.
+package grep
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tmuxout"
+)
+
+// Pane identifies one searched pane by its raw tmux indices. Live reports
+// whether the pane was running when listed, and so was captured live rather
+// than read from its saved file (barring a failed capture).
+type Pane struct {
+	Session    string `json:"session"`
+	Window     int    `json:"window"`
+	WindowName string `json:"window_name"`
+	Pane       int    `json:"pane"`
+	Live       bool   `json:"live"`
+
+	// scrollbackFile is the saved pane's scrollback file as sessions.json
+	// records it, relative to the state directory; Saved sets it.
+	scrollbackFile string
+}
+
+// Target returns the plain "session:window.pane" form of the pane.
+func (p Pane) Target() string {
+	return tmux.PaneTarget(p.Session, p.Window, p.Pane)
+}
+
+// Hit is one matching line. Line is 1-based within the pane's searched
+// content; Before and After hold up to the requested number of context lines
+// either side, ANSI-stripped like Text.
+type Hit struct {
+	Pane
+	Line   int      `json:"line"`
+	Text   string   `json:"text"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+// Corpus supplies the panes to search and their raw (escape-laden) content.
+type Corpus interface {
+	Panes() ([]Pane, error)
+	Content(p Pane) ([]byte, error)
+}
+
+// Compile builds the search pattern: a regular expression, or a literal
+// string when fixed is set, matched case-insensitively when ignoreCase is set.
+func Compile(pattern string, fixed, ignoreCase bool) (*regexp.Regexp, error) {
+	if fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// Search returns every line of every pane in c that re matches, in pane order
+// then line order, each with up to context lines either side. A pane whose
+// content cannot be read (a pane the daemon has not saved yet, or one gone
+// since it was listed) is skipped; only a failure to list the panes is an
+// error.
+func Search(c Corpus, re *regexp.Regexp, context int) ([]Hit, error) {
+	panes, err := c.Panes()
+	if err != nil {
+		return nil, err
+	}
+	var hits []Hit
+	for _, p := range panes {
+		content, err := c.Content(p)
+		if err != nil {
+			continue
+		}
+		hits = append(hits, searchContent(p, string(content), re, context)...)
+	}
+	return hits, nil
+}
+
+// searchContent matches re against each ANSI-stripped line of content.
+func searchContent(p Pane, content string, re *regexp.Regexp, context int) []Hit {
+	lines := strings.Split(strings.TrimSuffix(tmuxout.StripANSI(content), "\n"), "\n")
+	var hits []Hit
+	for i, line := range lines {
+		if !re.MatchString(line) {
+			continue
+		}
+		hits = append(hits, Hit{
+			Pane:   p,
+			Line:   i + 1,
+			Text:   line,
+			Before: clone(lines[max(0, i-context):i]),
+			After:  clone(lines[i+1 : min(len(lines), i+1+context)]),
+		})
+	}
+	return hits
+}
+
+// clone copies a context window out of the pane's line slice so a Hit does
+// not pin the whole pane's content in memory, and so an empty window encodes
+// as [] rather than null.
+func clone(lines []string) []string {
+	return append([]string{}, lines...)
+}
+
+// Saved searches the scrollback files in a state directory, one per pane in
+// sessions.json — dormant sessions included, since their saved scrollback is
+// all that is left of them.
+type Saved struct {
+	StateDir string
+}
+
+// Panes lists every pane recorded in sessions.json. A missing index yields no
+// panes; a corrupt one is an error.
+func (s Saved) Panes() ([]Pane, error) {
+	idx, _, err := state.ReadIndex(s.StateDir)
+	if err != nil {
+		return nil, err
+	}
+	var panes []Pane
+	for _, sess := range idx.Sessions {
+		for _, w := range sess.Windows {
+			for _, p := range w.Panes {
+				panes = append(panes, Pane{Session: sess.Name, Window: w.Index, WindowName: w.Name, Pane: p.Index, scrollbackFile: p.ScrollbackFile})
+			}
+		}
+	}
+	return panes, nil
+}
+
+// Content reads the pane's saved scrollback from the file sessions.json
+// records for it. A pane with no recorded file has none.
+func (s Saved) Content(p Pane) ([]byte, error) {
+	if p.scrollbackFile == "" {
+		return nil, nil
+	}
+	return state.ReadScrollback(filepath.Join(s.StateDir, p.scrollbackFile))
+}
+
+// LiveClient is the tmux surface Live needs; *tmux.Client satisfies it.
+type LiveClient interface {
+	ListSessionNames() ([]string, error)
+	ListWindowsAndPanesInSession(session string) ([]tmux.WindowGroup, error)
+	CapturePane(target string) (string, error)
+}
+
+var _ LiveClient = (*tmux.Client)(nil)
+
+// Live searches every running pane by live capture, plus the saved scrollback
+// of any session in Saved that is not running. A pane whose live capture
+// fails falls back to its saved file.
+type Live struct {
+	Client LiveClient
+	Saved  Saved
+}
+
+// Panes lists the running panes, marked Live, followed by the saved panes of
+// sessions with no running counterpart. A running pane that was also saved
+// keeps its saved file for Content's fallback. Without a tmux server every
+// pane comes from Saved. An unreadable sessions.json only fails the listing
+// when there are no running panes to search instead.
+func (l Live) Panes() ([]Pane, error) {
+	saved, savedErr := l.Saved.Panes()
+	files := make(map[string]string, len(saved))
+	for _, p := range saved {
+		files[p.Target()] = p.scrollbackFile
+	}
+
+	// No server is not an error here: the search degrades to Saved alone.
+	names, _ := l.Client.ListSessionNames()
+	running := make(map[string]bool, len(names))
+	var panes []Pane
+	for _, name := range names {
+		groups, err := l.Client.ListWindowsAndPanesInSession(name)
+		if err != nil {
+			continue
+		}
+		running[name] = true
+		for _, g := range groups {
+			for _, idx := range g.PaneIndices {
+				p := Pane{Session: name, Window: g.WindowIndex, WindowName: g.WindowName, Pane: idx, Live: true}
+				p.scrollbackFile = files[p.Target()]
+				panes = append(panes, p)
+			}
+		}
+	}
+
+	if savedErr != nil {
+		if len(panes) == 0 {
+			return nil, savedErr
+		}
+		return panes, nil
+	}
+	for _, p := range saved {
+		if !running[p.Session] {
+			panes = append(panes, p)
+		}
+	}
+	return panes, nil
+}
+
+// Content captures a live pane's full history, falling back to the saved
+// file when the capture fails; a saved pane reads its file directly.
+func (l Live) Content(p Pane) ([]byte, error) {
+	if p.Live {
+		if out, err := l.Client.CapturePane(tmux.PaneTargetExact(p.Session, p.Window, p.Pane)); err == nil {
+			return []byte(out), nil
+		}
+	}
+	return l.Saved.Content(p)
+}
@@ -0,0 +1,256 @@
+package grep_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/leeovery/portal/internal/grep"
+	"github.com/leeovery/portal/internal/state"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// fakeCorpus serves canned content keyed by pane target.
+type fakeCorpus struct {
+	panes   []grep.Pane
+	content map[string]string
+	err     error
+}
+
+func (f fakeCorpus) Panes() ([]grep.Pane, error) { return f.panes, f.err }
+
+func (f fakeCorpus) Content(p grep.Pane) ([]byte, error) {
+	c, ok := f.content[p.Target()]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return []byte(c), nil
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name              string
+		pattern           string
+		fixed, ignoreCase bool
+		line              string
+		want              bool
+	}{
+		{"regexp", `pan(ic|ed)`, false, false, "panicked", true},
+		{"case-sensitive by default", "Error", false, false, "error", false},
+		{"ignore case", "Error", false, true, "ERROR: x", true},
+		{"fixed treats metacharacters literally", "a.b", true, false, "axb", false},
+		{"fixed still matches the literal", "a.b(", true, false, "see a.b(", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := grep.Compile(tt.pattern, tt.fixed, tt.ignoreCase)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if got := re.MatchString(tt.line); got != tt.want {
+				t.Errorf("match %q = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("rejects an invalid regexp", func(t *testing.T) {
+		if _, err := grep.Compile("(", false, false); err == nil {
+			t.Error("Compile(\"(\") = nil error, want invalid pattern")
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	work := grep.Pane{Session: "work", Window: 1, WindowName: "build", Pane: 0}
+	api := grep.Pane{Session: "api", Window: 0, Pane: 2}
+	corpus := fakeCorpus{
+		panes: []grep.Pane{work, api, {Session: "gone", Window: 0, Pane: 0}},
+		content: map[string]string{
+			work.Target(): "one\ntwo\n\x1b[31mpanic: boom\x1b[0m\nthree\n",
+			api.Target():  "panic first\nlast\n",
+		},
+	}
+	re, err := grep.Compile("panic", false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hits, err := grep.Search(corpus, re, 1)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	want := []grep.Hit{
+		{Pane: work, Line: 3, Text: "panic: boom", Before: []string{"two"}, After: []string{"three"}},
+		{Pane: api, Line: 1, Text: "panic first", Before: []string{}, After: []string{"last"}},
+	}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("hits =\n%+v\nwant\n%+v", hits, want)
+	}
+
+	t.Run("ANSI escapes never match", func(t *testing.T) {
+		re, _ := grep.Compile(`\[31m`, false, false)
+		if hits, _ := grep.Search(corpus, re, 0); len(hits) != 0 {
+			t.Errorf("hits = %+v, want none", hits)
+		}
+	})
+
+	t.Run("a listing failure is an error", func(t *testing.T) {
+		if _, err := grep.Search(fakeCorpus{err: errors.New("boom")}, re, 0); err == nil {
+			t.Error("Search err = nil, want the listing error")
+		}
+	})
+}
+
+// writeSavedState writes a sessions.json naming the given panes plus a raw
+// scrollback file for each entry in content, returning the state dir.
+func writeSavedState(t *testing.T, sessions []state.Session, content map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	recordScrollbackFiles(sessions)
+	data, err := state.EncodeIndex(state.Index{Version: state.SchemaVersion, Sessions: sessions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(dir), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for key, c := range content {
+		if err := os.WriteFile(state.ScrollbackFile(dir, key), []byte(c), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// recordScrollbackFiles gives every pane without one the scrollback file a
+// capture would record for it.
+func recordScrollbackFiles(sessions []state.Session) {
+	for _, s := range sessions {
+		for _, w := range s.Windows {
+			for i := range w.Panes {
+				if w.Panes[i].ScrollbackFile == "" {
+					w.Panes[i].ScrollbackFile = "scrollback/" + state.SanitizePaneKey(s.Name, w.Index, w.Panes[i].Index) + ".bin"
+				}
+			}
+		}
+	}
+}
+
+func TestSaved(t *testing.T) {
+	dir := writeSavedState(t,
+		[]state.Session{{Name: "work", Dormant: true, Windows: []state.Window{{Index: 1, Name: "build", Panes: []state.Pane{{Index: 0}}}}}},
+		map[string]string{state.SanitizePaneKey("work", 1, 0): "go test\nFAIL pkg\n"},
+	)
+	re, _ := grep.Compile("FAIL", false, false)
+
+	hits, err := grep.Search(grep.Saved{StateDir: dir}, re, 0)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Target() != "work:1.0" || hits[0].WindowName != "build" || hits[0].Line != 2 || hits[0].Live {
+		t.Errorf("hits = %+v, want one saved hit at work:1.0 line 2", hits)
+	}
+
+	t.Run("reads the scrollback file sessions.json records", func(t *testing.T) {
+		dir := writeSavedState(t,
+			[]state.Session{{Name: "work", Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, ScrollbackFile: "scrollback/elsewhere.bin"}}}}}},
+			map[string]string{"elsewhere": "FAIL moved\n"},
+		)
+		hits, err := grep.Search(grep.Saved{StateDir: dir}, re, 0)
+		if err != nil || len(hits) != 1 || hits[0].Text != "FAIL moved" {
+			t.Errorf("hits, err = %+v, %v; want the recorded file's line", hits, err)
+		}
+	})
+
+	t.Run("a missing index searches nothing", func(t *testing.T) {
+		hits, err := grep.Search(grep.Saved{StateDir: filepath.Join(t.TempDir(), "none")}, re, 0)
+		if err != nil || len(hits) != 0 {
+			t.Errorf("hits, err = %+v, %v; want none", hits, err)
+		}
+	})
+}
+
+// fakeLiveClient serves a canned live server.
+type fakeLiveClient struct {
+	sessions   map[string][]tmux.WindowGroup
+	names      []string
+	captures   map[string]string
+	listErr    error
+	captureErr error
+}
+
+func (f fakeLiveClient) ListSessionNames() ([]string, error) { return f.names, f.listErr }
+
+func (f fakeLiveClient) ListWindowsAndPanesInSession(session string) ([]tmux.WindowGroup, error) {
+	return f.sessions[session], nil
+}
+
+func (f fakeLiveClient) CapturePane(target string) (string, error) {
+	if f.captureErr != nil {
+		return "", f.captureErr
+	}
+	return f.captures[target], nil
+}
+
+func TestLive(t *testing.T) {
+	dir := writeSavedState(t,
+		[]state.Session{
+			{Name: "work", Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0}}}}},
+			{Name: "old", Dormant: true, Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0}}}}},
+		},
+		map[string]string{
+			state.SanitizePaneKey("work", 0, 0): "saved needle\n",
+			state.SanitizePaneKey("old", 0, 0):  "dormant needle\n",
+		},
+	)
+	client := fakeLiveClient{
+		names:    []string{"work"},
+		sessions: map[string][]tmux.WindowGroup{"work": {{WindowIndex: 0, WindowName: "main", PaneIndices: []int{0}}}},
+		captures: map[string]string{"=work:0.0": "live needle\n"},
+	}
+	re, _ := grep.Compile("needle", false, false)
+
+	t.Run("running panes capture live, others read saved", func(t *testing.T) {
+		hits, err := grep.Search(grep.Live{Client: client, Saved: grep.Saved{StateDir: dir}}, re, 0)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		var got []string
+		for _, h := range hits {
+			got = append(got, h.Text)
+		}
+		if want := []string{"live needle", "dormant needle"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("hit texts = %v, want %v", got, want)
+		}
+		if !hits[0].Live || hits[1].Live {
+			t.Errorf("Live flags = %v, %v; want true, false", hits[0].Live, hits[1].Live)
+		}
+	})
+
+	t.Run("a failed capture falls back to the saved file", func(t *testing.T) {
+		failing := client
+		failing.captureErr = errors.New("can't find pane")
+
+		hits, _ := grep.Search(grep.Live{Client: failing, Saved: grep.Saved{StateDir: dir}}, re, 0)
+
+		if len(hits) == 0 || hits[0].Text != "saved needle" {
+			t.Errorf("hits = %+v, want the saved content first", hits)
+		}
+	})
+
+	t.Run("no server searches saved alone", func(t *testing.T) {
+		down := fakeLiveClient{listErr: errors.New("no server running")}
+
+		hits, err := grep.Search(grep.Live{Client: down, Saved: grep.Saved{StateDir: dir}}, re, 0)
+
+		if err != nil || len(hits) != 2 {
+			t.Errorf("hits, err = %+v, %v; want both saved hits", hits, err)
+		}
+	})
+}
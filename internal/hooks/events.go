@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// The lifecycle events a hook can be registered for. on-resume is the
+// original event and fires from the hydrate helper when a pane is recreated
+// from saved state; the rest fire from the tmux hooks Portal registers and
+// from its own session-creation and save paths.
+const (
+	EventResume = "on-resume"
+	EventCreate = "on-create"
+	EventAttach = "on-attach"
+	EventDetach = "on-detach"
+	EventKill   = "on-kill"
+	EventSave   = "on-save"
+)
+
+// Events lists every event in the order the CLI documents them.
+var Events = []string{EventResume, EventCreate, EventAttach, EventDetach, EventKill, EventSave}
+
+// IsEvent reports whether event is one of Events.
+func IsEvent(event string) bool {
+	return slices.Contains(Events, event)
+}
+
+// IsProjectKey reports whether key is a project key — the canonical absolute
+// directory of a project (project.CanonicalDirKey) — rather than a pane key
+// ("<@portal-id or session_name>:window.pane"). Pane keys are never absolute
+// paths, so the two share hooks.json without colliding.
+func IsProjectKey(key string) bool {
+	return filepath.IsAbs(key)
+}
+
+// Match is one command to run for an event, with the key it was registered
+// under.
+type Match struct {
+	Key     string
+	Command string
+}
+
+// HasEvent reports whether any key in h registers a non-empty command for
+// event. Firing paths check it before resolving the session a hook would run
+// for, so an event nobody hooks costs one hooks.json read.
+func HasEvent(h map[string]map[string]string, event string) bool {
+	for _, events := range h {
+		if events[event] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// EventMatches returns the commands h registers for event against a session:
+// one per pane key in paneKeys, in order, then the one registered against the
+// nearest project key containing dirKey. dirKey must already be canonical
+// (project.CanonicalDirKey); walking its ancestors means a session whose
+// directory is a subdirectory of a project still picks up the project's
+// hooks. An empty dirKey matches no project. Empty commands are skipped, as
+// in LookupResume.
+func EventMatches(h map[string]map[string]string, event string, paneKeys []string, dirKey string) []Match {
+	var matches []Match
+	for _, key := range paneKeys {
+		if cmd := h[key][event]; cmd != "" {
+			matches = append(matches, Match{Key: key, Command: cmd})
+		}
+	}
+	if key, cmd, ok := projectMatch(h, event, dirKey); ok {
+		matches = append(matches, Match{Key: key, Command: cmd})
+	}
+	return matches
+}
+
+// projectMatch finds the event command registered against dirKey or its
+// nearest ancestor that has one.
+func projectMatch(h map[string]map[string]string, event, dirKey string) (string, string, bool) {
+	if !IsProjectKey(dirKey) {
+		return "", "", false
+	}
+	for dir := dirKey; ; dir = filepath.Dir(dir) {
+		if cmd := h[dir][event]; cmd != "" {
+			return dir, cmd, true
+		}
+		if filepath.Dir(dir) == dir {
+			return "", "", false
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package hooks_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leeovery/portal/internal/hooks"
+)
+
+func TestIsProjectKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"/code/app", true},
+		{"abc123:0.1", false},
+		{"my-session:1.0", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := hooks.IsProjectKey(tt.key); got != tt.want {
+			t.Errorf("IsProjectKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestEventMatches(t *testing.T) {
+	h := map[string]map[string]string{
+		"abc:0.0":   {"on-attach": "echo pane0", "on-resume": "npm start"},
+		"abc:0.1":   {"on-attach": ""},
+		"abc:1.0":   {"on-detach": "echo bye"},
+		"/code/app": {"on-attach": "echo project", "on-create": "make deps"},
+		"/code":     {"on-attach": "echo parent", "on-kill": "echo parent-kill"},
+	}
+
+	t.Run("pane keys in order, then the nearest project", func(t *testing.T) {
+		got := hooks.EventMatches(h, hooks.EventAttach, []string{"abc:0.0", "abc:0.1", "abc:1.0"}, "/code/app/web")
+		want := []hooks.Match{
+			{Key: "abc:0.0", Command: "echo pane0"},
+			{Key: "/code/app", Command: "echo project"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("EventMatches = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("an ancestor project is used when the nearest has no command for the event", func(t *testing.T) {
+		got := hooks.EventMatches(h, hooks.EventKill, nil, "/code/app")
+		want := []hooks.Match{{Key: "/code", Command: "echo parent-kill"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("EventMatches = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("no directory matches no project", func(t *testing.T) {
+		if got := hooks.EventMatches(h, hooks.EventCreate, nil, ""); len(got) != 0 {
+			t.Errorf("EventMatches = %v, want none", got)
+		}
+	})
+}
+
+func TestHasEvent(t *testing.T) {
+	h := map[string]map[string]string{
+		"abc:0.0":   {"on-resume": "npm start", "on-detach": ""},
+		"/code/app": {"on-create": "make deps"},
+	}
+	for event, want := range map[string]bool{
+		hooks.EventResume: true,
+		hooks.EventCreate: true,
+		hooks.EventDetach: false,
+		hooks.EventSave:   false,
+	} {
+		if got := hooks.HasEvent(h, event); got != want {
+			t.Errorf("HasEvent(%q) = %v, want %v", event, got, want)
+		}
+	}
+}
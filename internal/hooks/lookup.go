@@ -1,34 +1,86 @@
 package hooks
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+)
 
-// LookupOnResume returns the on-resume command registered for hookKey.
+// ResumeEvent returns the event a project resume hook is stored under:
+// "on-resume" for one that fires in every pane of the project, or
+// "on-resume:<command>" for one that fires only in panes whose saved
+// foreground command was command.
+func ResumeEvent(command string) string {
+	if command == "" {
+		return EventResume
+	}
+	return EventResume + ":" + command
+}
+
+// ResumePane describes a pane being recreated from saved state, as the
+// hydrate helper knows it.
 //
-// Return contract:
-//   - ("", false, nil) — no on-resume hook registered for hookKey, OR
-//     hooks.json is missing, OR malformed JSON. Matches Store.Load
-//     contract: file-level corruption degrades silently to "no hook".
-//   - ("", false, err) — genuine I/O error (e.g. EISDIR, permission denied).
-//     Wrapped with "load hooks" prefix.
-//   - (cmd, true, nil) — non-empty on-resume command registered for hookKey.
+// HookKey is the raw saved structural identifier (session:window.pane);
+// un-sanitized so colons in session names round-trip verbatim. Dirs are the
+// canonical directories (project.CanonicalDirKey) the pane is matched to a
+// project by, most specific first — its own working directory, then its
+// session's. Command is the pane's saved foreground command.
+type ResumePane struct {
+	HookKey string
+	Dirs    []string
+	Command string
+}
+
+// LookupResume returns the on-resume command that applies to pane. A hook
+// registered on the pane's own key wins outright; otherwise each of
+// pane.Dirs is walked up to the filesystem root and the first project key
+// with a match supplies the command, preferring the project's hook for
+// pane.Command over its any-pane hook.
 //
-// hookKey is the raw saved structural identifier (session:window.pane);
-// un-sanitized so colons in session names round-trip verbatim.
+// Return contract:
+//   - (Match{}, false, nil) — no hook applies, OR hooks.json is missing, OR
+//     malformed JSON. Matches Store.Load contract: file-level corruption
+//     degrades silently to "no hook".
+//   - (Match{}, false, err) — genuine I/O error (e.g. EISDIR, permission
+//     denied). Wrapped with "load hooks" prefix.
+//   - (m, true, nil) — m.Command is non-empty; m.Key is the pane or project
+//     key it was registered under.
 //
 // Empty-string commands are treated as "no hook" — avoids spawning an
 // empty `sh -c ”` from the helper's exec chain.
-func LookupOnResume(store *Store, hookKey string) (string, bool, error) {
+func LookupResume(store *Store, pane ResumePane) (Match, bool, error) {
 	h, err := store.Load()
 	if err != nil {
-		return "", false, fmt.Errorf("load hooks: %w", err)
+		return Match{}, false, fmt.Errorf("load hooks: %w", err)
+	}
+	if cmd := h[pane.HookKey][EventResume]; cmd != "" {
+		return Match{Key: pane.HookKey, Command: cmd}, true, nil
 	}
-	events, ok := h[hookKey]
-	if !ok {
-		return "", false, nil
+
+	events := []string{EventResume}
+	if pane.Command != "" {
+		events = []string{ResumeEvent(pane.Command), EventResume}
 	}
-	cmd, ok := events["on-resume"]
-	if !ok || cmd == "" {
-		return "", false, nil
+	for _, dirKey := range pane.Dirs {
+		if !IsProjectKey(dirKey) {
+			continue
+		}
+		for dir := dirKey; ; dir = filepath.Dir(dir) {
+			for _, event := range events {
+				if cmd := h[dir][event]; cmd != "" {
+					return Match{Key: dir, Command: cmd}, true, nil
+				}
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
 	}
-	return cmd, true, nil
+	return Match{}, false, nil
+}
+
+// LookupOnResume returns the on-resume command registered for hookKey
+// itself, ignoring project hooks. It shares LookupResume's return contract.
+func LookupOnResume(store *Store, hookKey string) (string, bool, error) {
+	m, ok, err := LookupResume(store, ResumePane{HookKey: hookKey})
+	return m.Command, ok, err
 }
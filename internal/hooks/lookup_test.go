@@ -179,3 +179,69 @@ func TestLookupOnResume(t *testing.T) {
 		}
 	})
 }
+
+func TestLookupResume(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "hooks.json")
+	content := `{
+		"work:0.0": {"on-resume": "claude --resume abc"},
+		"/code/api": {"on-resume:npm": "npm run dev", "on-resume": "git status"},
+		"/code": {"on-resume:vim": "vim -S"}
+	}`
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+	store := hooks.NewStore(filePath)
+
+	tests := []struct {
+		name    string
+		pane    hooks.ResumePane
+		want    hooks.Match
+		wantHit bool
+	}{
+		{
+			name:    "a pane-key hook takes precedence over its project",
+			pane:    hooks.ResumePane{HookKey: "work:0.0", Dirs: []string{"/code/api"}, Command: "npm"},
+			want:    hooks.Match{Key: "work:0.0", Command: "claude --resume abc"},
+			wantHit: true,
+		},
+		{
+			name:    "the project's hook for the pane's last command",
+			pane:    hooks.ResumePane{HookKey: "work:0.1", Dirs: []string{"/code/api/src"}, Command: "npm"},
+			want:    hooks.Match{Key: "/code/api", Command: "npm run dev"},
+			wantHit: true,
+		},
+		{
+			name:    "the project's any-pane hook when no command hook matches",
+			pane:    hooks.ResumePane{HookKey: "work:0.1", Dirs: []string{"/code/api"}, Command: "zsh"},
+			want:    hooks.Match{Key: "/code/api", Command: "git status"},
+			wantHit: true,
+		},
+		{
+			name:    "the nearest project wins over an ancestor's command hook",
+			pane:    hooks.ResumePane{HookKey: "work:0.1", Dirs: []string{"/code/api"}, Command: "vim"},
+			want:    hooks.Match{Key: "/code/api", Command: "git status"},
+			wantHit: true,
+		},
+		{
+			name:    "the session directory is tried after the pane's own",
+			pane:    hooks.ResumePane{HookKey: "work:0.1", Dirs: []string{"/tmp", "/code/web"}, Command: "vim"},
+			want:    hooks.Match{Key: "/code", Command: "vim -S"},
+			wantHit: true,
+		},
+		{
+			name: "no directory matches no project",
+			pane: hooks.ResumePane{HookKey: "work:0.1", Command: "npm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := hooks.LookupResume(store, tt.pane)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantHit || got != tt.want {
+				t.Errorf("LookupResume = (%+v, %v), want (%+v, %v)", got, ok, tt.want, tt.wantHit)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ResumeRule maps a pane's saved foreground command to the command that
+// resumes it. Command is matched exactly against the pane's saved
+// current_command (the process name tmux reports, e.g. "nvim"). Args, when
+// set, are arguments that must each appear in the pane's saved argv after the
+// program name — "-f" tells a following tail from a one-shot one — so a rule
+// with Args never matches a pane whose argv is unknown. IfExists, when set, is
+// a path relative to the pane's working directory that must exist for the
+// rule to apply — a vim session file, say.
+type ResumeRule struct {
+	Command  string   `json:"command"`
+	Args     []string `json:"args,omitempty"`
+	IfExists string   `json:"if_exists,omitempty"`
+	Resume   string   `json:"resume"`
+}
+
+// ResumeRules is the decoded resume.json: smart resume's on/off switch and its
+// rules. Rules is nil when the file has no rules key, which selects
+// DefaultResumeRules; an explicit empty list disables every rule.
+type ResumeRules struct {
+	Enabled bool         `json:"enabled"`
+	Rules   []ResumeRule `json:"rules"`
+}
+
+// rerunCommandLine is the resume command of a rule that re-runs the pane's
+// saved command line verbatim; hydrate exports it as PORTAL_COMMAND_LINE.
+const rerunCommandLine = `eval "$PORTAL_COMMAND_LINE"`
+
+// DefaultResumeRules are the rules smart resume applies when resume.json
+// enables it without listing its own.
+var DefaultResumeRules = []ResumeRule{
+	{Command: "nvim", IfExists: "Session.vim", Resume: "nvim -S Session.vim"},
+	{Command: "vim", IfExists: "Session.vim", Resume: "vim -S Session.vim"},
+	{Command: "less", Resume: rerunCommandLine},
+	{Command: "tail", Args: []string{"-f"}, Resume: rerunCommandLine},
+	{Command: "tail", Args: []string{"-F"}, Resume: rerunCommandLine},
+	{Command: "claude", Resume: "claude --continue"},
+	{Command: "npm", Args: []string{"run", "dev"}, IfExists: "package.json", Resume: "npm run dev"},
+}
+
+// Match returns the resume command of the first rule for a pane that was
+// running command, with the saved argv, in dir. A rule with Args never
+// matches a pane whose argv is unknown, nor one with IfExists a pane whose dir
+// is.
+func (r ResumeRules) Match(command string, argv []string, dir string) (ResumeRule, bool) {
+	if command == "" {
+		return ResumeRule{}, false
+	}
+	rules := r.Rules
+	if rules == nil {
+		rules = DefaultResumeRules
+	}
+	for _, rule := range rules {
+		if rule.Command != command || rule.Resume == "" {
+			continue
+		}
+		if len(rule.Args) > 0 && (len(argv) < 2 || !containsAll(argv[1:], rule.Args)) {
+			continue
+		}
+		if rule.IfExists != "" {
+			if dir == "" {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, rule.IfExists)); err != nil {
+				continue
+			}
+		}
+		return rule, true
+	}
+	return ResumeRule{}, false
+}
+
+// containsAll reports whether every one of want appears in args.
+func containsAll(args, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(args, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitCommandLine splits a command line rendered by restore's shellJoin —
+// bare words and single-quoted ones, with '\” standing for an embedded quote —
+// back into its argv. Outside quotes a backslash escapes the next byte; it is
+// not a general shell parser, so double quotes are kept literally.
+func SplitCommandLine(line string) []string {
+	var argv []string
+	var word strings.Builder
+	inWord, quoted := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\'':
+			quoted = false
+		case quoted:
+			word.WriteByte(c)
+		case c == '\'':
+			quoted, inWord = true, true
+		case c == '\\' && i+1 < len(line):
+			i++
+			word.WriteByte(line[i])
+			inWord = true
+		case c == ' ':
+			if inWord {
+				argv = append(argv, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		argv = append(argv, word.String())
+	}
+	return argv
+}
+
+// RulesStore is a read-only store over the user-authored resume.json. Like
+// spawn's TerminalsStore it just holds a path and never writes.
+type RulesStore struct {
+	path string
+}
+
+// NewRulesStore returns a store that reads resume.json from path.
+func NewRulesStore(path string) *RulesStore {
+	return &RulesStore{path: path}
+}
+
+// Load reads resume.json. It never fails: a missing file is smart resume left
+// off, and an unreadable or malformed one is logged and treated the same, so
+// a bad edit costs the rules rather than the pane's shell.
+func (s *RulesStore) Load() ResumeRules {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("resume.json unreadable", "detail", err.Error())
+		}
+		return ResumeRules{}
+	}
+	var r ResumeRules
+	if err := json.Unmarshal(data, &r); err != nil {
+		logger.Warn("resume.json malformed", "detail", err.Error())
+		return ResumeRules{}
+	}
+	return r
+}
@@ -0,0 +1,126 @@
+package hooks_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/leeovery/portal/internal/hooks"
+)
+
+func TestResumeRulesMatch(t *testing.T) {
+	withSession := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withSession, "Session.vim"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bare := t.TempDir()
+	npmProject := t.TempDir()
+	if err := os.WriteFile(filepath.Join(npmProject, "package.json"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		rules   hooks.ResumeRules
+		command string
+		argv    []string
+		dir     string
+		want    string
+	}{
+		{"default rule without a condition", hooks.ResumeRules{}, "claude", nil, bare, "claude --continue"},
+		{"default rule whose file exists", hooks.ResumeRules{}, "nvim", nil, withSession, "nvim -S Session.vim"},
+		{"default rule whose file is missing", hooks.ResumeRules{}, "nvim", nil, bare, ""},
+		{"a conditional rule needs a directory", hooks.ResumeRules{}, "nvim", nil, "", ""},
+		{"an unknown command", hooks.ResumeRules{}, "zsh", nil, bare, ""},
+		{
+			"user rules replace the defaults",
+			hooks.ResumeRules{Rules: []hooks.ResumeRule{{Command: "htop", Resume: "htop"}}},
+			"claude", nil, bare, "",
+		},
+		{
+			"the first matching user rule wins",
+			hooks.ResumeRules{Rules: []hooks.ResumeRule{
+				{Command: "nvim", IfExists: "Session.vim", Resume: "nvim -S"},
+				{Command: "nvim", Resume: "nvim ."},
+			}},
+			"nvim", nil, bare, "nvim .",
+		},
+		{"an explicit empty list disables every rule", hooks.ResumeRules{Rules: []hooks.ResumeRule{}}, "claude", nil, bare, ""},
+		{"less re-runs its command line", hooks.ResumeRules{}, "less", []string{"less", "app.log"}, bare, `eval "$PORTAL_COMMAND_LINE"`},
+		{"tail -f re-runs its command line", hooks.ResumeRules{}, "tail", []string{"tail", "-n", "50", "-f", "app.log"}, bare, `eval "$PORTAL_COMMAND_LINE"`},
+		{"tail -F re-runs its command line", hooks.ResumeRules{}, "tail", []string{"tail", "-F", "app.log"}, bare, `eval "$PORTAL_COMMAND_LINE"`},
+		{"a one-shot tail does not resume", hooks.ResumeRules{}, "tail", []string{"tail", "app.log"}, bare, ""},
+		{"an args rule needs the argv", hooks.ResumeRules{}, "tail", nil, bare, ""},
+		{"npm run dev resumes the dev server", hooks.ResumeRules{}, "npm", []string{"npm", "run", "dev"}, npmProject, "npm run dev"},
+		{"another npm script does not resume as the dev server", hooks.ResumeRules{}, "npm", []string{"npm", "test", "--watch"}, npmProject, ""},
+		{"npm run build does not resume as the dev server", hooks.ResumeRules{}, "npm", []string{"npm", "run", "build"}, npmProject, ""},
+		{"args are not matched against the program name", hooks.ResumeRules{Rules: []hooks.ResumeRule{{Command: "tail", Args: []string{"tail"}, Resume: "x"}}}, "tail", []string{"tail"}, bare, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := tt.rules.Match(tt.command, tt.argv, tt.dir)
+			if ok != (tt.want != "") || rule.Resume != tt.want {
+				t.Errorf("Match(%q) = (%q, %v), want %q", tt.command, rule.Resume, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRulesStoreLoad(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "resume.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("a missing file leaves smart resume off", func(t *testing.T) {
+		got := hooks.NewRulesStore(filepath.Join(t.TempDir(), "resume.json")).Load()
+		if got.Enabled || got.Rules != nil {
+			t.Errorf("Load = %+v, want zero", got)
+		}
+	})
+
+	t.Run("a malformed file leaves smart resume off", func(t *testing.T) {
+		got := hooks.NewRulesStore(write(t, `{"enabled": true,`)).Load()
+		if got.Enabled {
+			t.Errorf("Load = %+v, want disabled", got)
+		}
+	})
+
+	t.Run("enabled without rules keeps the defaults", func(t *testing.T) {
+		got := hooks.NewRulesStore(write(t, `{"enabled": true}`)).Load()
+		if !got.Enabled || got.Rules != nil {
+			t.Errorf("Load = %+v, want enabled with nil rules", got)
+		}
+	})
+
+	t.Run("decodes user rules", func(t *testing.T) {
+		got := hooks.NewRulesStore(write(t, `{"enabled": true, "rules": [{"command": "tail", "args": ["-f"], "resume": "tail -f log"}]}`)).Load()
+		want := []hooks.ResumeRule{{Command: "tail", Args: []string{"-f"}, Resume: "tail -f log"}}
+		if !reflect.DeepEqual(got.Rules, want) {
+			t.Errorf("Rules = %+v, want %+v", got.Rules, want)
+		}
+	})
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"tail -f app.log", []string{"tail", "-f", "app.log"}},
+		{"less 'my notes.txt'", []string{"less", "my notes.txt"}},
+		{`echo 'it'\''s' ''`, []string{"echo", "it's", ""}},
+	}
+	for _, tt := range tests {
+		if got := hooks.SplitCommandLine(tt.line); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -241,6 +241,10 @@ func (s *Store) List() ([]Hook, error) {
 // mass-deletion hazard guard — an empty live set makes every persisted key
 // stale here; the guard that defers on an empty/errored live set is a
 // cmd-layer repair-safety policy, not part of this classification.
+//
+// Project keys (IsProjectKey) are never stale: they name a directory, not a
+// pane, so they cannot appear in the live pane-key set and outlive every
+// session opened in the project by design.
 func StaleKeys(persisted map[string]map[string]string, live []string) []string {
 	liveSet := make(map[string]struct{}, len(live))
 	for _, k := range live {
@@ -248,6 +252,9 @@ func StaleKeys(persisted map[string]map[string]string, live []string) []string {
 	}
 	var stale []string
 	for key := range persisted {
+		if IsProjectKey(key) {
+			continue
+		}
 		if _, ok := liveSet[key]; !ok {
 			stale = append(stale, key)
 		}
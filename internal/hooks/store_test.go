@@ -774,6 +774,17 @@ func TestStaleKeys(t *testing.T) {
 			t.Errorf("StaleKeys = %v, want empty", got)
 		}
 	})
+
+	t.Run("never reports a project key", func(t *testing.T) {
+		persisted := map[string]map[string]string{
+			"/code/app":   {"on-create": "make deps"},
+			"stale-b:0.0": {"on-resume": "y"},
+		}
+		got := hooks.StaleKeys(persisted, []string{})
+		if len(got) != 1 || got[0] != "stale-b:0.0" {
+			t.Errorf("StaleKeys = %v, want only the pane key", got)
+		}
+	})
 }
 
 // TestCleanStaleRemovesExactlyStaleKeys proves CleanStale removes precisely the
@@ -0,0 +1,55 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leeovery/portal/internal/state"
+)
+
+// shells are the foreground commands FromSession treats as an idle prompt
+// rather than something worth re-running.
+var shells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "dash": true,
+	"ksh": true, "tcsh": true, "csh": true, "nu": true,
+}
+
+// FromSession derives a template from a captured session, making every pane
+// directory relative to root where it lies inside it. A pane's command is its
+// foreground command at capture time; panes sitting at a shell prompt get
+// none. Window layouts are kept verbatim so the template reproduces the exact
+// geometry.
+func FromSession(sess state.Session, root string) Template {
+	userShell := filepath.Base(os.Getenv("SHELL"))
+
+	t := Template{Windows: make([]Window, 0, len(sess.Windows))}
+	for _, w := range sess.Windows {
+		win := Window{Name: w.Name, Layout: w.Layout}
+		for _, p := range w.Panes {
+			pane := Pane{Dir: relativeDir(root, p.CWD)}
+			if cmd := p.CurrentCommand; cmd != "" && !shells[cmd] && cmd != userShell {
+				pane.Command = cmd
+			}
+			win.Panes = append(win.Panes, pane)
+		}
+		t.Windows = append(t.Windows, win)
+	}
+	return t
+}
+
+// relativeDir expresses dir relative to root: "" for root itself, a relative
+// path beneath it, and dir unchanged when it lies outside root.
+func relativeDir(root, dir string) string {
+	if root == "" || dir == "" {
+		return dir
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return dir
+	}
+	if rel == "." {
+		return ""
+	}
+	return rel
+}
@@ -0,0 +1,172 @@
+// Package layout describes declarative session templates: the windows, panes,
+// tmux layouts, working directories and per-pane commands Portal builds when
+// it mints a new session for a project. A template lives either in a
+// .portal.yml at the project root or on the project's entry in projects.json.
+package layout
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// FileNames are the repo-local template files Load looks for, in order.
+var FileNames = []string{".portal.yml", ".portal.yaml"}
+
+// Template is a session layout. Windows are created in order; the first pane
+// of the first window is the pane the session starts with.
+type Template struct {
+	Windows []Window `json:"windows" yaml:"windows"`
+}
+
+// Window is one tmux window. Dir is relative to the project root (absolute
+// and ~ paths are kept as-is) and is the default for the window's panes.
+// Layout is anything select-layout accepts — a preset such as main-vertical
+// or tiled, or a layout string copied from #{window_layout}. A window with no
+// panes gets a single shell pane.
+type Window struct {
+	Name   string `json:"name,omitempty" yaml:"name,omitempty"`
+	Dir    string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	Layout string `json:"layout,omitempty" yaml:"layout,omitempty"`
+	Panes  []Pane `json:"panes,omitempty" yaml:"panes,omitempty"`
+}
+
+// Pane is one pane of a window. Dir is relative to the window's directory;
+// Command is a shell command line, run interactively before dropping back to
+// the shell. An empty Command leaves a plain shell.
+type Pane struct {
+	Dir     string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+// Parse decodes a YAML (or JSON — YAML is a superset) template and validates
+// it. Unknown keys are rejected so a typo such as "comand" is reported
+// instead of silently producing a plain shell.
+func Parse(data []byte) (*Template, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var t Template
+	if err := dec.Decode(&t); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("template is empty")
+		}
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Validate reports whether t can be built: it needs at least one window.
+func (t *Template) Validate() error {
+	if len(t.Windows) == 0 {
+		return errors.New("template has no windows")
+	}
+	return nil
+}
+
+// HasCommands reports whether any pane of t runs a command.
+func (t *Template) HasCommands() bool {
+	for _, w := range t.Windows {
+		for _, p := range w.Panes {
+			if p.Command != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WithoutCommands returns a copy of t with every pane command removed: the
+// same windows, directories and layouts, each pane a plain shell.
+func (t *Template) WithoutCommands() *Template {
+	out := &Template{Windows: make([]Window, len(t.Windows))}
+	for i, w := range t.Windows {
+		panes := make([]Pane, len(w.Panes))
+		for j, p := range w.Panes {
+			panes[j] = Pane{Dir: p.Dir}
+		}
+		w.Panes = panes
+		out.Windows[i] = w
+	}
+	return out
+}
+
+// Load reads the template file in dir, trying each of FileNames. It returns
+// (nil, nil) when dir has none, and an error naming the file when one exists
+// but cannot be read or parsed.
+func Load(dir string) (*Template, error) {
+	t, _, err := LoadFile(dir)
+	return t, err
+}
+
+// LoadFile is Load that also returns the hex SHA-256 of the file's contents,
+// which a caller records to trust exactly the file it was shown.
+func LoadFile(dir string) (*Template, string, error) {
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		t, err := Parse(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid %s: %w", path, err)
+		}
+		sum := sha256.Sum256(data)
+		return t, hex.EncodeToString(sum[:]), nil
+	}
+	return nil, "", nil
+}
+
+// Marshal encodes t as YAML in the shape Load reads back.
+func Marshal(t Template) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(t); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PaneDir resolves the working directory of pane p in window w for a session
+// rooted at root.
+func PaneDir(root string, w Window, p Pane) string {
+	return resolveDir(resolveDir(root, w.Dir), p.Dir)
+}
+
+// resolveDir resolves dir against base: empty is base itself, ~ expands to
+// the home directory, and an absolute path is kept.
+func resolveDir(base, dir string) string {
+	switch {
+	case dir == "":
+		return base
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return base
+		}
+		return filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	case filepath.IsAbs(dir):
+		return filepath.Clean(dir)
+	default:
+		return filepath.Join(base, dir)
+	}
+}
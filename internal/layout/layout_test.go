@@ -0,0 +1,203 @@
+package layout_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/state"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("it decodes windows, panes, layouts and commands", func(t *testing.T) {
+		got, err := layout.Parse([]byte(`
+windows:
+  - name: code
+    layout: main-vertical
+    panes:
+      - command: nvim
+      - dir: web
+        command: npm run dev
+  - name: logs
+    dir: log
+`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := &layout.Template{Windows: []layout.Window{
+			{Name: "code", Layout: "main-vertical", Panes: []layout.Pane{
+				{Command: "nvim"},
+				{Dir: "web", Command: "npm run dev"},
+			}},
+			{Name: "logs", Dir: "log"},
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse = %+v, want %+v", got, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "empty document", data: "", wantErr: "empty"},
+		{name: "no windows", data: "windows: []\n", wantErr: "no windows"},
+		{name: "unknown key", data: "windows:\n  - panes:\n      - comand: nvim\n", wantErr: "comand"},
+	}
+	for _, tt := range tests {
+		t.Run("it rejects "+tt.name, func(t *testing.T) {
+			_, err := layout.Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("it returns nil when the directory has no template", func(t *testing.T) {
+		got, err := layout.Load(t.TempDir())
+		if err != nil || got != nil {
+			t.Errorf("Load = %v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("it reads .portal.yaml as well as .portal.yml", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ".portal.yaml"), []byte("windows:\n  - name: one\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := layout.Load(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == nil || got.Windows[0].Name != "one" {
+			t.Errorf("Load = %+v, want the one-window template", got)
+		}
+	})
+
+	t.Run("it names the file when the template is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ".portal.yml"), []byte("windows: nope\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := layout.Load(dir)
+		if err == nil || !strings.Contains(err.Error(), ".portal.yml") {
+			t.Errorf("err = %v, want one naming .portal.yml", err)
+		}
+	})
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".portal.yml")
+	if err := os.WriteFile(path, []byte("windows:\n  - name: one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, first, err := layout.LoadFile(dir)
+	if err != nil || first == "" {
+		t.Fatalf("LoadFile = %q, %v; want a content hash", first, err)
+	}
+	if err := os.WriteFile(path, []byte("windows:\n  - name: two\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, second, _ := layout.LoadFile(dir); second == first {
+		t.Error("hash unchanged after the file was edited")
+	}
+}
+
+func TestWithoutCommands(t *testing.T) {
+	tmpl := &layout.Template{Windows: []layout.Window{
+		{Name: "code", Layout: "tiled", Panes: []layout.Pane{{Command: "nvim"}, {Dir: "web", Command: "npm run dev"}}},
+		{Name: "logs"},
+	}}
+
+	if !tmpl.HasCommands() {
+		t.Fatal("HasCommands = false for a template with pane commands")
+	}
+	got := tmpl.WithoutCommands()
+	if got.HasCommands() {
+		t.Errorf("WithoutCommands left a command: %+v", got)
+	}
+	if got.Windows[0].Layout != "tiled" || len(got.Windows[0].Panes) != 2 || got.Windows[0].Panes[1].Dir != "web" {
+		t.Errorf("WithoutCommands = %+v, want the layout and directories kept", got)
+	}
+	if tmpl.Windows[0].Panes[0].Command != "nvim" {
+		t.Error("WithoutCommands modified the original template")
+	}
+}
+
+func TestPaneDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		name string
+		win  layout.Window
+		pane layout.Pane
+		want string
+	}{
+		{name: "defaults to the root", want: "/code/app"},
+		{name: "window dir is relative to the root", win: layout.Window{Dir: "web"}, want: "/code/app/web"},
+		{name: "pane dir is relative to the window dir", win: layout.Window{Dir: "web"}, pane: layout.Pane{Dir: "src"}, want: "/code/app/web/src"},
+		{name: "absolute pane dir is kept", win: layout.Window{Dir: "web"}, pane: layout.Pane{Dir: "/var/log"}, want: "/var/log"},
+		{name: "tilde expands to home", pane: layout.Pane{Dir: "~/notes"}, want: filepath.Join(home, "notes")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := layout.PaneDir("/code/app", tt.win, tt.pane); got != tt.want {
+				t.Errorf("PaneDir = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSession(t *testing.T) {
+	t.Setenv("SHELL", "/opt/bin/xonsh")
+	sess := state.Session{Name: "app", Windows: []state.Window{
+		{Index: 1, Name: "code", Layout: "b25d,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []state.Pane{
+			{Index: 0, CWD: "/code/app", CurrentCommand: "nvim"},
+			{Index: 1, CWD: "/code/app/web", CurrentCommand: "zsh"},
+		}},
+		{Index: 2, Name: "misc", Layout: "c1a0,80x24,0,0,3", Panes: []state.Pane{
+			{Index: 0, CWD: "/tmp", CurrentCommand: "xonsh"},
+		}},
+	}}
+
+	got := layout.FromSession(sess, "/code/app")
+
+	want := layout.Template{Windows: []layout.Window{
+		{Name: "code", Layout: "b25d,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []layout.Pane{
+			{Command: "nvim"},
+			{Dir: "web"},
+		}},
+		{Name: "misc", Layout: "c1a0,80x24,0,0,3", Panes: []layout.Pane{
+			{Dir: "/tmp"},
+		}},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FromSession = %+v, want %+v", got, want)
+	}
+
+	data, err := layout.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	back, err := layout.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse of marshalled template: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(*back, want) {
+		t.Errorf("round trip = %+v, want %+v", *back, want)
+	}
+}
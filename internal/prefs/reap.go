@@ -0,0 +1,83 @@
+package prefs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReapMinIdle is the shortest accepted reap idle duration. Anything shorter is
+// far more likely a unit slip ("7m" for "7d") than a wish to kill sessions
+// that have been quiet over a coffee.
+const ReapMinIdle = 10 * time.Minute
+
+// ReapSettings is the idle reaper's global configuration. Idle is the
+// threshold as the user wrote it ("36h", "7d"); empty disables the reaper.
+// ExemptTags are project tags whose sessions are never reaped. A project may
+// also be exempted on its own in projects.json.
+type ReapSettings struct {
+	Idle       string
+	ExemptTags []string
+}
+
+// IdleAfter returns the parsed idle threshold, or zero — reaper off — when
+// Idle is empty or does not parse. Callers validate with ParseReapIdle before
+// saving, so an unparseable value only comes from a hand edit, and failing
+// closed is the only safe reading of one.
+func (r ReapSettings) IdleAfter() time.Duration {
+	if strings.TrimSpace(r.Idle) == "" {
+		return 0
+	}
+	d, err := ParseReapIdle(r.Idle)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// ParseReapIdle parses a Go duration, or a whole number of days with a "d"
+// suffix, and enforces ReapMinIdle.
+func ParseReapIdle(raw string) (time.Duration, error) {
+	s := strings.TrimSpace(raw)
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid day count %q", days)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+	if d < ReapMinIdle {
+		return 0, fmt.Errorf("shorter than the %s minimum", ReapMinIdle)
+	}
+	return d, nil
+}
+
+// LoadReap reads the reaper settings from prefs.json with the same tolerant
+// policy as Load: a missing, empty or corrupt file yields the zero
+// ReapSettings (reaper off). Only a non-ErrNotExist read error is propagated.
+func (s *Store) LoadReap() (ReapSettings, error) {
+	f, _, err := s.readFile()
+	if err != nil {
+		return ReapSettings{}, err
+	}
+	return ReapSettings{Idle: f.ReapIdle, ExemptTags: f.ReapExemptTags}, nil
+}
+
+// SaveReap persists the reaper settings to prefs.json, read-modify-writing so
+// every other preference is preserved.
+func (s *Store) SaveReap(settings ReapSettings) error {
+	f, _, err := s.readFile()
+	if err != nil {
+		return err
+	}
+	f.ReapIdle = strings.TrimSpace(settings.Idle)
+	f.ReapExemptTags = settings.ExemptTags
+	return s.write(f)
+}
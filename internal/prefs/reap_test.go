@@ -0,0 +1,69 @@
+package prefs_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+)
+
+func TestParseReapIdle(t *testing.T) {
+	good := map[string]time.Duration{"36h": 36 * time.Hour, " 7d ": 7 * 24 * time.Hour, "10m": 10 * time.Minute}
+	for raw, want := range good {
+		if got, err := prefs.ParseReapIdle(raw); err != nil || got != want {
+			t.Errorf("ParseReapIdle(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"7m", "0d", "soon", "1.5d"} {
+		if _, err := prefs.ParseReapIdle(raw); err == nil {
+			t.Errorf("ParseReapIdle(%q) succeeded, want an error", raw)
+		}
+	}
+}
+
+func TestReapSettings(t *testing.T) {
+	t.Run("missing file is reaper off", func(t *testing.T) {
+		got, err := prefs.NewStore(filepath.Join(t.TempDir(), "prefs.json")).LoadReap()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.IdleAfter() != 0 || len(got.ExemptTags) != 0 {
+			t.Errorf("settings = %+v, want reaper off", got)
+		}
+	})
+
+	t.Run("an unparseable idle value is reaper off", func(t *testing.T) {
+		if d := (prefs.ReapSettings{Idle: "5m"}).IdleAfter(); d != 0 {
+			t.Errorf("IdleAfter = %v, want 0", d)
+		}
+	})
+
+	t.Run("save round-trips and preserves other preferences", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "prefs.json")
+		if err := os.WriteFile(filePath, []byte(`{"session_list_mode":"by-tag","restore_policy":"lazy"}`), 0o644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+		store := prefs.NewStore(filePath)
+
+		if err := store.SaveReap(prefs.ReapSettings{Idle: " 7d ", ExemptTags: []string{"work"}}); err != nil {
+			t.Fatalf("SaveReap: %v", err)
+		}
+
+		got, err := store.LoadReap()
+		if err != nil {
+			t.Fatalf("LoadReap: %v", err)
+		}
+		if got.Idle != "7d" || got.IdleAfter() != 7*24*time.Hour || !slices.Equal(got.ExemptTags, []string{"work"}) {
+			t.Errorf("settings = %+v", got)
+		}
+		if policy, _ := store.LoadRestorePolicy(); policy != prefs.RestoreLazy {
+			t.Errorf("restore policy = %v, want lazy kept", policy)
+		}
+		if mode, _ := store.Load(); mode != prefs.ModeByTag {
+			t.Errorf("mode = %v, want by-tag kept", mode)
+		}
+	})
+}
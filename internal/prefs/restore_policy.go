@@ -0,0 +1,83 @@
+package prefs
+
+// RestorePolicy decides what happens to a session across a server restart. It
+// is set globally in prefs.json and may be overridden per project in
+// projects.json; the project override wins.
+type RestorePolicy int
+
+const (
+	// RestoreAlways saves the session and recreates it whenever the server
+	// starts. It is the first-run / tolerant-decode default.
+	RestoreAlways RestorePolicy = iota
+	// RestoreLazy saves the session but leaves it dormant at server start; it is
+	// recreated only when picked in the TUI or named by `x <name>`.
+	RestoreLazy
+	// RestoreNever does not save the session at all: it is gone once the server
+	// stops.
+	RestoreNever
+)
+
+// Canonical on-disk strings for each policy, shared by prefs.json and the
+// per-project override in projects.json.
+const (
+	restoreAlwaysString = "always"
+	restoreLazyString   = "lazy"
+	restoreNeverString  = "never"
+)
+
+// RestorePolicyNames lists the canonical policy strings in declaration order,
+// for help text and completion.
+var RestorePolicyNames = []string{restoreAlwaysString, restoreLazyString, restoreNeverString}
+
+// String returns the canonical on-disk string for the policy. An out-of-range
+// value maps to the always default.
+func (p RestorePolicy) String() string {
+	switch p {
+	case RestoreLazy:
+		return restoreLazyString
+	case RestoreNever:
+		return restoreNeverString
+	default:
+		return restoreAlwaysString
+	}
+}
+
+// ParseRestorePolicy maps a canonical string to its policy. Unlike the
+// tolerant file decode, it reports ok == false for an unrecognised value so
+// the CLI can reject a typo instead of silently storing "always".
+func ParseRestorePolicy(s string) (RestorePolicy, bool) {
+	switch s {
+	case restoreAlwaysString:
+		return RestoreAlways, true
+	case restoreLazyString:
+		return RestoreLazy, true
+	case restoreNeverString:
+		return RestoreNever, true
+	default:
+		return RestoreAlways, false
+	}
+}
+
+// LoadRestorePolicy reads the global restore policy from prefs.json with the
+// same tolerant policy as Load: a missing file, an empty or corrupt file, a
+// missing restore_policy field, and an unrecognised value all return
+// (RestoreAlways, nil). Only a non-ErrNotExist read error is propagated.
+func (s *Store) LoadRestorePolicy() (RestorePolicy, error) {
+	f, _, err := s.readFile()
+	if err != nil {
+		return RestoreAlways, err
+	}
+	policy, _ := ParseRestorePolicy(f.RestorePolicy)
+	return policy, nil
+}
+
+// SaveRestorePolicy persists the global restore policy to prefs.json,
+// read-modify-writing so the session list mode and appearance are preserved.
+func (s *Store) SaveRestorePolicy(policy RestorePolicy) error {
+	f, _, err := s.readFile()
+	if err != nil {
+		return err
+	}
+	f.RestorePolicy = policy.String()
+	return s.write(f)
+}
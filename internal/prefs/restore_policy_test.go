@@ -0,0 +1,105 @@
+package prefs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/prefs"
+)
+
+func TestLoadRestorePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    prefs.RestorePolicy
+	}{
+		{name: "missing field defaults to always", content: `{"session_list_mode":"by-tag"}`, want: prefs.RestoreAlways},
+		{name: "corrupt file defaults to always", content: `{not json`, want: prefs.RestoreAlways},
+		{name: "unknown value defaults to always", content: `{"restore_policy":"sometimes"}`, want: prefs.RestoreAlways},
+		{name: "lazy", content: `{"restore_policy":"lazy"}`, want: prefs.RestoreLazy},
+		{name: "never", content: `{"restore_policy":"never"}`, want: prefs.RestoreNever},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "prefs.json")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("failed to write test file: %v", err)
+			}
+
+			got, err := prefs.NewStore(filePath).LoadRestorePolicy()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("policy = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing file defaults to always", func(t *testing.T) {
+		store := prefs.NewStore(filepath.Join(t.TempDir(), "prefs.json"))
+
+		got, err := store.LoadRestorePolicy()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != prefs.RestoreAlways {
+			t.Errorf("policy = %v, want always", got)
+		}
+	})
+}
+
+func TestSaveRestorePolicy(t *testing.T) {
+	t.Run("round-trips and preserves the other preferences", func(t *testing.T) {
+		store := prefs.NewStore(filepath.Join(t.TempDir(), "prefs.json"))
+		if err := store.Save(prefs.ModeByTag); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if err := store.SaveAppearance(prefs.AppearanceDark); err != nil {
+			t.Fatalf("SaveAppearance: %v", err)
+		}
+
+		if err := store.SaveRestorePolicy(prefs.RestoreLazy); err != nil {
+			t.Fatalf("SaveRestorePolicy: %v", err)
+		}
+
+		if got, _ := store.LoadRestorePolicy(); got != prefs.RestoreLazy {
+			t.Errorf("policy = %v, want lazy", got)
+		}
+		if got, _ := store.Load(); got != prefs.ModeByTag {
+			t.Errorf("session_list_mode = %v, want by-tag (blanked by SaveRestorePolicy)", got)
+		}
+		if got, _ := store.LoadAppearance(); got != prefs.AppearanceDark {
+			t.Errorf("appearance = %v, want dark (blanked by SaveRestorePolicy)", got)
+		}
+	})
+
+	t.Run("Save preserves a previously-saved policy", func(t *testing.T) {
+		store := prefs.NewStore(filepath.Join(t.TempDir(), "prefs.json"))
+		if err := store.SaveRestorePolicy(prefs.RestoreNever); err != nil {
+			t.Fatalf("SaveRestorePolicy: %v", err)
+		}
+
+		if err := store.Save(prefs.ModeByProject); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		if got, _ := store.LoadRestorePolicy(); got != prefs.RestoreNever {
+			t.Errorf("policy = %v, want never", got)
+		}
+	})
+}
+
+func TestParseRestorePolicy(t *testing.T) {
+	for _, name := range prefs.RestorePolicyNames {
+		policy, ok := prefs.ParseRestorePolicy(name)
+		if !ok || policy.String() != name {
+			t.Errorf("ParseRestorePolicy(%q) = %v, %v; want a round-trip", name, policy, ok)
+		}
+	}
+	if _, ok := prefs.ParseRestorePolicy("Lazy"); ok {
+		t.Error("ParseRestorePolicy accepted a non-canonical value")
+	}
+}
@@ -1,6 +1,7 @@
 // Package prefs provides persistence for UI preferences that do not belong in a
 // domain store like projects.json. It owns the last-used session list grouping
-// mode, persisted to prefs.json.
+// mode, the appearance, the global session restore policy, and the idle
+// reaper's settings, persisted to prefs.json.
 //
 // The package is a pure leaf — it imports only the standard library and
 // internal/fileutil — so it is safe to import from internal/tui without an
@@ -20,7 +21,7 @@ import (
 )
 
 // SessionListMode is the grouping mode for the TUI session list. It is the
-// single source of truth for the three modes; the TUI reuses this type.
+// single source of truth for the five modes; the TUI reuses this type.
 type SessionListMode int
 
 const (
@@ -31,6 +32,11 @@ const (
 	ModeByProject
 	// ModeByTag groups sessions by their project tags.
 	ModeByTag
+	// ModeRecent lists sessions ungrouped, ordered by Portal's frecency score.
+	ModeRecent
+	// ModeByStatus groups sessions by the state of their coding agents, the
+	// sessions waiting on their user first.
+	ModeByStatus
 )
 
 // Canonical on-disk strings for each mode. String enum (not int) so prefs.json
@@ -39,17 +45,23 @@ const (
 	modeFlatString      = "flat"
 	modeByProjectString = "by-project"
 	modeByTagString     = "by-tag"
+	modeRecentString    = "recent"
+	modeByStatusString  = "by-status"
 )
 
 // String returns the canonical on-disk string for the mode. An out-of-range
 // value maps to the flat default so the marshalled form is always one of the
-// three canonical tokens.
+// five canonical tokens.
 func (m SessionListMode) String() string {
 	switch m {
 	case ModeByProject:
 		return modeByProjectString
 	case ModeByTag:
 		return modeByTagString
+	case ModeRecent:
+		return modeRecentString
+	case ModeByStatus:
+		return modeByStatusString
 	default:
 		return modeFlatString
 	}
@@ -63,6 +75,10 @@ func parseMode(s string) SessionListMode {
 		return ModeByProject
 	case modeByTagString:
 		return ModeByTag
+	case modeRecentString:
+		return ModeRecent
+	case modeByStatusString:
+		return ModeByStatus
 	default:
 		return ModeFlat
 	}
@@ -123,8 +139,11 @@ func parseAppearance(s string) Appearance {
 // independent field; a missing field decodes to the empty string, which the
 // per-field parsers collapse to their default (tolerant decode).
 type prefsFile struct {
-	SessionListMode string `json:"session_list_mode"`
-	Appearance      string `json:"appearance"`
+	SessionListMode string   `json:"session_list_mode"`
+	Appearance      string   `json:"appearance"`
+	RestorePolicy   string   `json:"restore_policy,omitempty"`
+	ReapIdle        string   `json:"reap_idle,omitempty"`
+	ReapExemptTags  []string `json:"reap_exempt_tags,omitempty"`
 }
 
 // Store manages persistence of UI preferences to a JSON file.
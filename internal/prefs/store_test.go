@@ -93,6 +93,40 @@ func TestRoundTrip(t *testing.T) {
 		}
 	})
 
+	t.Run("round-trips recent through Save and Load", func(t *testing.T) {
+		dir := t.TempDir()
+		store := prefs.NewStore(filepath.Join(dir, "prefs.json"))
+
+		if err := store.Save(prefs.ModeRecent); err != nil {
+			t.Fatalf("unexpected save error: %v", err)
+		}
+
+		mode, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		if mode != prefs.ModeRecent {
+			t.Errorf("mode = %v, want ModeRecent", mode)
+		}
+	})
+
+	t.Run("round-trips by-status through Save and Load", func(t *testing.T) {
+		dir := t.TempDir()
+		store := prefs.NewStore(filepath.Join(dir, "prefs.json"))
+
+		if err := store.Save(prefs.ModeByStatus); err != nil {
+			t.Fatalf("unexpected save error: %v", err)
+		}
+
+		mode, err := store.Load()
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		if mode != prefs.ModeByStatus {
+			t.Errorf("mode = %v, want ModeByStatus", mode)
+		}
+	})
+
 	t.Run("round-trips by-project through Save and Load", func(t *testing.T) {
 		dir := t.TempDir()
 		store := prefs.NewStore(filepath.Join(dir, "prefs.json"))
@@ -155,6 +189,8 @@ func TestModeString(t *testing.T) {
 		{prefs.ModeFlat, "flat"},
 		{prefs.ModeByProject, "by-project"},
 		{prefs.ModeByTag, "by-tag"},
+		{prefs.ModeRecent, "recent"},
+		{prefs.ModeByStatus, "by-status"},
 	}
 	for _, c := range cases {
 		if got := c.mode.String(); got != c.want {
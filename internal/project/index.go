@@ -51,3 +51,26 @@ func (idx Index) Match(dirPath string) (Project, string, bool) {
 	p, ok := idx.byKey[key]
 	return p, key, ok
 }
+
+// Tags returns the canonical, usable tags of the project stored at dirPath.
+// Each stored tag is defensively re-normalised through NormaliseTag and junk
+// (empty/whitespace) entries are dropped. An empty dirPath or a directory with
+// no matching project yields nil.
+func (idx Index) Tags(dirPath string) []string {
+	if dirPath == "" {
+		return nil
+	}
+
+	matched, _, ok := idx.Match(dirPath)
+	if !ok {
+		return nil
+	}
+
+	var tags []string
+	for _, raw := range matched.Tags {
+		if tag, ok := NormaliseTag(raw); ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
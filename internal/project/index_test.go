@@ -149,3 +149,24 @@ func TestNewIndexEmpty(t *testing.T) {
 		}
 	})
 }
+
+func TestIndexTags(t *testing.T) {
+	projDir := t.TempDir()
+	idx := NewIndex([]Project{{Path: projDir, Name: "Proj", Tags: []string{"work", "  ", " Client "}}})
+
+	t.Run("it returns the matched project's usable tags", func(t *testing.T) {
+		got := idx.Tags(projDir)
+		if len(got) != 2 || got[0] != "work" || got[1] != "Client" {
+			t.Errorf("Index.Tags(%q) = %q, want [work Client]", projDir, got)
+		}
+	})
+
+	t.Run("it returns nil for an empty or unknown dir", func(t *testing.T) {
+		if got := idx.Tags(""); got != nil {
+			t.Errorf("Index.Tags(\"\") = %q, want nil", got)
+		}
+		if got := idx.Tags(t.TempDir()); got != nil {
+			t.Errorf("Index.Tags(unknown) = %q, want nil", got)
+		}
+	})
+}
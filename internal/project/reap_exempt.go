@@ -0,0 +1,46 @@
+package project
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// SetReapExempt marks or unmarks the project matched by exact path as exempt
+// from the idle reaper. It is a no-op (no Save, no breadcrumb) when the flag
+// is unchanged and returns ErrProjectNotFound when no project matches path. A
+// real change emits an op=set-reap-exempt breadcrumb carrying the new flag as
+// the value.
+func (s *Store) SetReapExempt(path string, exempt bool) error {
+	projects, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	idx, ok := findByPath(projects, path)
+	if !ok {
+		return ErrProjectNotFound
+	}
+	if projects[idx].ReapExempt == exempt {
+		return nil
+	}
+
+	projects[idx].ReapExempt = exempt
+	return s.saveMutation(projects, "set-reap-exempt", projects[idx].Name, path, strconv.FormatBool(exempt))
+}
+
+// ReapExempt reports whether the project containing dirPath — the project
+// stored at dirPath itself or, failing that, at its nearest ancestor, as for
+// RestorePolicy — is exempt from the idle reaper.
+func (idx Index) ReapExempt(dirPath string) bool {
+	if dirPath == "" {
+		return false
+	}
+	for dir := CanonicalDirKey(dirPath); ; dir = filepath.Dir(dir) {
+		if p, ok := idx.byKey[dir]; ok {
+			return p.ReapExempt
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
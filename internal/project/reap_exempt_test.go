@@ -0,0 +1,83 @@
+package project_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/project"
+)
+
+func TestSetReapExempt(t *testing.T) {
+	t.Run("it sets and clears the flag with one breadcrumb per change", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert("/code/portal", "portal", "internal"); err != nil {
+			t.Fatalf("unexpected error on upsert: %v", err)
+		}
+
+		sink := installCapture(t)
+		if err := store.SetReapExempt("/code/portal", true); err != nil {
+			t.Fatalf("unexpected error on SetReapExempt: %v", err)
+		}
+		if err := store.SetReapExempt("/code/portal", true); err != nil {
+			t.Fatalf("unexpected error on the no-op SetReapExempt: %v", err)
+		}
+		rec := sink.OnlyRecord(t)
+		if got := rec.AttrString(t, "op"); got != "set-reap-exempt" {
+			t.Errorf("op = %q, want %q", got, "set-reap-exempt")
+		}
+		if got := rec.AttrString(t, "value"); got != "true" {
+			t.Errorf("value = %q, want %q", got, "true")
+		}
+		projects, _ := store.Load()
+		if !projects[0].ReapExempt {
+			t.Fatal("ReapExempt not set")
+		}
+
+		if err := store.SetReapExempt("/code/portal", false); err != nil {
+			t.Fatalf("unexpected error clearing: %v", err)
+		}
+		projects, _ = store.Load()
+		if projects[0].ReapExempt {
+			t.Error("ReapExempt not cleared")
+		}
+	})
+
+	t.Run("it returns ErrProjectNotFound for an unknown path without writing", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "projects.json")
+		store := project.NewStore(filePath)
+
+		err := store.SetReapExempt("/code/missing", true)
+
+		if !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+		if _, statErr := os.Stat(filePath); !errors.Is(statErr, os.ErrNotExist) {
+			t.Error("projects.json was written for an unknown project")
+		}
+	})
+}
+
+func TestIndexReapExempt(t *testing.T) {
+	root := t.TempDir()
+	exemptDir := filepath.Join(root, "keep")
+	nestedDir := filepath.Join(exemptDir, "pkg")
+	plainDir := filepath.Join(root, "plain")
+	for _, d := range []string{nestedDir, plainDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := project.NewIndex([]project.Project{
+		{Path: exemptDir, Name: "keep", ReapExempt: true},
+		{Path: plainDir, Name: "plain"},
+	})
+
+	tests := map[string]bool{exemptDir: true, nestedDir: true, plainDir: false, root: false, "": false}
+	for dir, want := range tests {
+		if got := idx.ReapExempt(dir); got != want {
+			t.Errorf("ReapExempt(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package project
+
+import (
+	"path/filepath"
+
+	"github.com/leeovery/portal/internal/prefs"
+)
+
+// SetRestorePolicy sets the restore-policy override of the project matched by
+// exact path. An empty policy clears the override so the project inherits the
+// global setting; any other value must be a canonical policy string (callers
+// validate with prefs.ParseRestorePolicy). It is a no-op (no Save, no
+// breadcrumb) when the override is unchanged and returns ErrProjectNotFound
+// when no project matches path. A real change emits an op=set-policy
+// breadcrumb carrying the new policy ("" for a clear) as the value, so policy
+// edits can be told apart from tag edits in the log.
+func (s *Store) SetRestorePolicy(path, policy string) error {
+	projects, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	idx, ok := findByPath(projects, path)
+	if !ok {
+		return ErrProjectNotFound
+	}
+	if projects[idx].RestorePolicy == policy {
+		return nil
+	}
+
+	projects[idx].RestorePolicy = policy
+	return s.saveMutation(projects, "set-policy", projects[idx].Name, path, policy)
+}
+
+// RestorePolicy returns the restore-policy override of the project that
+// contains dirPath: the project stored at dirPath itself or, failing that, at
+// its nearest ancestor, so a pane that has cd'd into a subdirectory still
+// belongs to its project. ok is false when no containing project exists or
+// the nearest one carries no (or an unrecognised) override — the caller then
+// applies the global policy.
+func (idx Index) RestorePolicy(dirPath string) (prefs.RestorePolicy, bool) {
+	if dirPath == "" {
+		return prefs.RestoreAlways, false
+	}
+	for dir := CanonicalDirKey(dirPath); ; dir = filepath.Dir(dir) {
+		if p, ok := idx.byKey[dir]; ok {
+			return prefs.ParseRestorePolicy(p.RestorePolicy)
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return prefs.RestoreAlways, false
+		}
+	}
+}
+
+// HasRestorePolicy reports whether any project in projects overrides the
+// global restore policy. Callers use it to skip per-session project matching
+// entirely in the common no-override case.
+func HasRestorePolicy(projects []Project) bool {
+	for _, p := range projects {
+		if p.RestorePolicy != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package project_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+)
+
+func TestSetRestorePolicy(t *testing.T) {
+	t.Run("it sets and clears a project's override", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert("/code/portal", "portal", "internal"); err != nil {
+			t.Fatalf("unexpected error on upsert: %v", err)
+		}
+		if err := store.AddTag("/code/portal", "work"); err != nil {
+			t.Fatalf("unexpected error on AddTag: %v", err)
+		}
+
+		if err := store.SetRestorePolicy("/code/portal", "lazy"); err != nil {
+			t.Fatalf("unexpected error on SetRestorePolicy: %v", err)
+		}
+		projects, _ := store.Load()
+		if projects[0].RestorePolicy != "lazy" {
+			t.Fatalf("RestorePolicy = %q, want lazy", projects[0].RestorePolicy)
+		}
+		if len(projects[0].Tags) != 1 {
+			t.Errorf("Tags = %v, want the tag kept", projects[0].Tags)
+		}
+
+		if err := store.SetRestorePolicy("/code/portal", ""); err != nil {
+			t.Fatalf("unexpected error clearing: %v", err)
+		}
+		projects, _ = store.Load()
+		if projects[0].RestorePolicy != "" {
+			t.Errorf("RestorePolicy = %q, want cleared", projects[0].RestorePolicy)
+		}
+	})
+
+	t.Run("it emits INFO op=set-policy carrying the new policy", func(t *testing.T) {
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert("/code/portal", "portal", "internal"); err != nil {
+			t.Fatalf("unexpected error on upsert: %v", err)
+		}
+
+		sink := installCapture(t)
+		if err := store.SetRestorePolicy("/code/portal", "never"); err != nil {
+			t.Fatalf("unexpected error on SetRestorePolicy: %v", err)
+		}
+
+		rec := sink.OnlyRecord(t)
+		if rec.Msg != "set-policy" {
+			t.Errorf("msg = %q, want %q", rec.Msg, "set-policy")
+		}
+		if got := rec.AttrString(t, "op"); got != "set-policy" {
+			t.Errorf("op = %q, want %q", got, "set-policy")
+		}
+		if got := rec.AttrString(t, "value"); got != "never" {
+			t.Errorf("value = %q, want %q", got, "never")
+		}
+	})
+
+	t.Run("it returns ErrProjectNotFound for an unknown path without writing", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "projects.json")
+		store := project.NewStore(filePath)
+
+		err := store.SetRestorePolicy("/code/missing", "never")
+
+		if !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+		if _, statErr := os.Stat(filePath); !errors.Is(statErr, os.ErrNotExist) {
+			t.Error("projects.json was written for an unknown project")
+		}
+	})
+}
+
+func TestIndexRestorePolicy(t *testing.T) {
+	root := t.TempDir()
+	lazyDir := filepath.Join(root, "lazy")
+	nestedDir := filepath.Join(lazyDir, "pkg", "deep")
+	plainDir := filepath.Join(root, "plain")
+	for _, d := range []string{nestedDir, plainDir} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := project.NewIndex([]project.Project{
+		{Path: lazyDir, Name: "lazy", RestorePolicy: "lazy"},
+		{Path: plainDir, Name: "plain"},
+	})
+
+	tests := []struct {
+		name   string
+		dir    string
+		want   prefs.RestorePolicy
+		wantOK bool
+	}{
+		{name: "project directory", dir: lazyDir, want: prefs.RestoreLazy, wantOK: true},
+		{name: "subdirectory inherits its project's override", dir: nestedDir, want: prefs.RestoreLazy, wantOK: true},
+		{name: "project without an override", dir: plainDir, wantOK: false},
+		{name: "directory outside every project", dir: root, wantOK: false},
+		{name: "empty directory", dir: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := idx.RestorePolicy(tt.dir)
+			if ok != tt.wantOK || (ok && got != tt.want) {
+				t.Errorf("RestorePolicy(%q) = %v, %v; want %v, %v", tt.dir, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
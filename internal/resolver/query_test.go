@@ -202,6 +202,77 @@ func TestQueryResolver_Resolve(t *testing.T) {
 	}
 }
 
+// mockFrecencyQuerier implements resolver.FrecencyQuerier for testing.
+type mockFrecencyQuerier struct {
+	result string
+	err    error
+}
+
+func (m *mockFrecencyQuerier) Query(terms string) (string, error) {
+	return m.result, m.err
+}
+
+func TestQueryResolver_Resolve_Frecency(t *testing.T) {
+	t.Run("frecency hit resolves before zoxide", func(t *testing.T) {
+		zoxide := &mockZoxideQuerier{result: "/zoxide/path"}
+		dirs := &mockDirValidator{existing: map[string]bool{"/recent/path": true, "/zoxide/path": true}}
+		qr := resolver.NewQueryResolver(&mockSessionLister{}, &mockAliasLookup{}, zoxide, dirs).
+			WithFrecency(&mockFrecencyQuerier{result: "/recent/path"})
+
+		result, err := qr.Resolve("proj")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pr, ok := result.(*resolver.PathResult)
+		if !ok {
+			t.Fatalf("expected PathResult, got %T", result)
+		}
+		if pr.Path != "/recent/path" || pr.Domain != resolver.DomainFrecency {
+			t.Errorf("PathResult = %+v, want /recent/path in the frecency domain", pr)
+		}
+	})
+
+	t.Run("alias still resolves before frecency", func(t *testing.T) {
+		dirs := &mockDirValidator{existing: map[string]bool{"/alias/path": true, "/recent/path": true}}
+		qr := resolver.NewQueryResolver(&mockSessionLister{}, &mockAliasLookup{aliases: map[string]string{"proj": "/alias/path"}}, &mockZoxideQuerier{err: resolver.ErrNoMatch}, dirs).
+			WithFrecency(&mockFrecencyQuerier{result: "/recent/path"})
+
+		result, err := qr.Resolve("proj")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pr, ok := result.(*resolver.PathResult); !ok || pr.Path != "/alias/path" {
+			t.Errorf("result = %+v, want the alias path", result)
+		}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		frecency *mockFrecencyQuerier
+	}{
+		{"frecency miss falls through to zoxide", &mockFrecencyQuerier{err: errors.New("no frecency match found")}},
+		{"stale frecency directory falls through to zoxide", &mockFrecencyQuerier{result: "/gone/path"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dirs := &mockDirValidator{existing: map[string]bool{"/zoxide/path": true}}
+			qr := resolver.NewQueryResolver(&mockSessionLister{}, &mockAliasLookup{}, &mockZoxideQuerier{result: "/zoxide/path"}, dirs).
+				WithFrecency(tc.frecency)
+
+			result, err := qr.Resolve("proj")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pr, ok := result.(*resolver.PathResult)
+			if !ok {
+				t.Fatalf("expected PathResult, got %T", result)
+			}
+			if pr.Path != "/zoxide/path" || pr.Domain != "zoxide" {
+				t.Errorf("PathResult = %+v, want /zoxide/path in the zoxide domain", pr)
+			}
+		})
+	}
+}
+
 func TestQueryResolver_Resolve_PathLikeArguments(t *testing.T) {
 	t.Run("path containing / resolved directly", func(t *testing.T) {
 		dir := t.TempDir()
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/leeovery/portal/internal/log"
+	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
 )
@@ -49,6 +50,13 @@ type Orchestrator struct {
 	// the cold/TUI route (cmd/bootstrap_production.go) wires a non-nil closure
 	// forwarding (n, m) onto the §10.2 progress channel.
 	Progress func(n, m int)
+
+	// Policy resolves each saved session's restore policy. Restore recreates
+	// only prefs.RestoreAlways sessions; lazy ones stay in sessions.json to be
+	// resurrected on demand, and never ones are not expected there at all.
+	// RestoreIndex ignores it: an on-demand restore is an explicit request.
+	// Nil restores everything.
+	Policy state.RestorePolicyFunc
 }
 
 // Restore is the bootstrap entry point. Returns (false, nil) on the happy
@@ -64,13 +72,52 @@ func (o *Orchestrator) Restore() (bool, error) {
 		return o.handleReadIndexSkip(err)
 	}
 
+	o.RestoreIndex(o.applyPolicy(idx))
+	return false, nil
+}
+
+// applyPolicy returns idx without the sessions whose policy is not
+// prefs.RestoreAlways. Dropping them before the loop (rather than skipping
+// inside it) keeps the N/M progress total to the sessions actually in play.
+func (o *Orchestrator) applyPolicy(idx state.Index) state.Index {
+	if o.Policy == nil {
+		return idx
+	}
+	kept := make([]state.Session, 0, len(idx.Sessions))
+	deferred := 0
+	for _, sess := range idx.Sessions {
+		if o.Policy(sess) != prefs.RestoreAlways {
+			deferred++
+			continue
+		}
+		kept = append(kept, sess)
+	}
+	if deferred > 0 {
+		o.logger().Info("restore deferred by policy", "sessions", deferred)
+	}
+	idx.Sessions = kept
+	return idx
+}
+
+// RestoreIndex runs the same skeleton-restore loop Restore does, but against a
+// caller-supplied index rather than the live sessions.json. It is the entry
+// point for on-demand restores (a named snapshot) that must behave exactly
+// like a reboot restore: same live-skip, same topology validation, same
+// geometry and skeleton markers, same Progress callback cadence. Per-session
+// failures are logged and isolated as in Restore.
+//
+// Returns the names of the sessions actually skeleton-restored, in index
+// order; live-skipped and failed sessions are absent. Callers that need the
+// panes hydrated promptly follow up with the eager FIFO signal, exactly as
+// bootstrap step 7 does after Restore.
+func (o *Orchestrator) RestoreIndex(idx state.Index) []string {
 	if len(idx.Sessions) == 0 {
-		return false, nil // nothing to restore
+		return nil // nothing to restore
 	}
 
 	liveSet, ok := o.snapshotLiveSessions()
 	if !ok {
-		return false, nil
+		return nil
 	}
 
 	sr := &SessionRestorer{
@@ -85,7 +132,8 @@ func (o *Orchestrator) Restore() (bool, error) {
 	// cycle-level summary cadence.
 	start := time.Now()
 	m := len(idx.Sessions)
-	var restoredSessions, restoredWindows, restoredPanes int
+	var restored []string
+	var restoredWindows, restoredPanes int
 	for i, sess := range idx.Sessions {
 		// §10.4 N/M progress: fire BEFORE restoreOne so N advances regardless of
 		// the per-session outcome — a live-skip, underscore-prefix, invalid
@@ -98,19 +146,19 @@ func (o *Orchestrator) Restore() (bool, error) {
 		if !o.restoreOne(sr, sess, liveSet) {
 			continue
 		}
-		restoredSessions++
+		restored = append(restored, sess.Name)
 		restoredWindows += len(sess.Windows)
 		for _, w := range sess.Windows {
 			restoredPanes += len(w.Panes)
 		}
 	}
 	o.logger().Info("skeleton complete",
-		"sessions", restoredSessions,
+		"sessions", len(restored),
 		"windows", restoredWindows,
 		"panes", restoredPanes,
 		log.Took(start),
 	)
-	return false, nil
+	return restored
 }
 
 // handleReadIndexSkip classifies ReadIndex's skip-with-error path. A clean
@@ -149,8 +197,8 @@ func (o *Orchestrator) snapshotLiveSessions() (map[string]struct{}, bool) {
 	return set, true
 }
 
-// restoreOne handles the per-session decision tree: skip live sessions
-// silently, skip Portal-internal underscore-prefixed names with a log entry,
+// restoreOne handles the per-session decision tree: skip live and dormant
+// sessions silently, skip Portal-internal underscore-prefixed names with a log entry,
 // reject malformed topologies (zero windows / zero panes), then dispatch to
 // the SessionRestorer's create / geometry / markers sequence with all three
 // operations sharing the same live []tmux.PaneCoord that the arm phase
@@ -172,6 +220,12 @@ func (o *Orchestrator) restoreOne(sr *SessionRestorer, sess state.Session, liveS
 		return false
 	}
 
+	if sess.Dormant {
+		// A dormant session was not running when last saved; only an explicit
+		// resurrect (which clears the flag) brings it back.
+		return false
+	}
+
 	if !o.validateTopology(sess) {
 		return false
 	}
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/restore"
 	"github.com/leeovery/portal/internal/state"
 	"github.com/leeovery/portal/internal/tmux"
@@ -257,6 +259,87 @@ func TestOrchestrator_SilentlySkipsLiveSession(t *testing.T) {
 	}
 }
 
+func TestOrchestrator_SilentlySkipsDormantSession(t *testing.T) {
+	dir := t.TempDir()
+	sess := state.Session{
+		Name:    "old",
+		Dormant: true,
+		SavedAt: time.Now().UTC().Add(-time.Hour),
+		Windows: []state.Window{
+			{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/old", ScrollbackFile: "scrollback/old__0.0.bin"}}},
+		},
+	}
+	writeValidIndex(t, dir, []state.Session{sess})
+
+	rf := &orchestratorRunFunc{listSessionsOut: ""}
+	mock := &mockCommander{RunFunc: rf.run}
+	logger, sink := openTestLogger(t, dir)
+	o := newOrchestrator(t, mock, dir, logger)
+	if _, err := o.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if got := len(findAllCalls(mock.Calls, "new-session")); got != 0 {
+		t.Errorf("new-session calls = %d, want 0 (dormant session must be skipped)", got)
+	}
+	if strings.Contains(sink.Body(), "WARN") {
+		t.Errorf("expected no WARN on a dormant skip; got %q", sink.Body())
+	}
+}
+
+func TestOrchestrator_RestoresOnlyAlwaysPolicySessions(t *testing.T) {
+	dir := t.TempDir()
+	session := func(name string) state.Session {
+		return state.Session{
+			Name: name,
+			Windows: []state.Window{
+				{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/" + name, ScrollbackFile: "scrollback/" + name + "__0.0.bin", Active: true}}},
+			},
+		}
+	}
+	writeValidIndex(t, dir, []state.Session{session("eager"), session("later"), session("gone")})
+
+	rf := &orchestratorRunFunc{listSessionsOut: "", listPanesOut: "0:0"}
+	mock := &mockCommander{RunFunc: rf.run}
+	logger, sink := openTestLogger(t, dir)
+	o := newOrchestrator(t, mock, dir, logger)
+	var progressTotal int
+	o.Progress = func(_, m int) { progressTotal = m }
+	o.Policy = func(s state.Session) prefs.RestorePolicy {
+		switch s.Name {
+		case "later":
+			return prefs.RestoreLazy
+		case "gone":
+			return prefs.RestoreNever
+		}
+		return prefs.RestoreAlways
+	}
+	if _, err := o.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	calls := findAllCalls(mock.Calls, "new-session")
+	if len(calls) != 1 || !slices.Contains(mock.Calls[calls[0]], "eager") {
+		t.Errorf("new-session calls = %d, want one, for eager", len(calls))
+	}
+	if progressTotal != 1 {
+		t.Errorf("progress total = %d, want 1 (deferred sessions are not counted)", progressTotal)
+	}
+	if !strings.Contains(sink.Body(), "restore deferred by policy") {
+		t.Errorf("expected a deferred-by-policy log entry; got %q", sink.Body())
+	}
+
+	// An on-demand restore is explicit and ignores the policy.
+	idx, _, err := state.ReadIndex(dir)
+	if err != nil {
+		t.Fatalf("ReadIndex: %v", err)
+	}
+	idx.Sessions = idx.Sessions[1:2] // later
+	if restored := o.RestoreIndex(idx); len(restored) != 1 || restored[0] != "later" {
+		t.Errorf("RestoreIndex restored %v, want [later]", restored)
+	}
+}
+
 func TestOrchestrator_SkipsUnderscorePrefixedSessions(t *testing.T) {
 	dir := t.TempDir()
 	sess := state.Session{
@@ -859,3 +942,45 @@ func TestOrchestrator_AlwaysRunsApplySkeletonMarkersAfterApplyWindowGeometry(t *
 			newSessionAt, armListPanesAt, layoutAt, setOptAt)
 	}
 }
+
+func TestOrchestrator_RestoreIndexRestoresSuppliedIndexAndReportsNames(t *testing.T) {
+	dir := t.TempDir()
+	// No sessions.json on disk: RestoreIndex must work purely from its argument.
+	idx := state.Index{
+		Version: state.SchemaVersion,
+		Sessions: []state.Session{
+			{Name: "live", Windows: []state.Window{
+				{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/live", ScrollbackFile: "snapshots/s1/scrollback/live__0.0.bin"}}},
+			}},
+			{Name: "gone", Windows: []state.Window{
+				{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/gone", ScrollbackFile: "snapshots/s1/scrollback/gone__0.0.bin", Active: true}}},
+			}},
+		},
+	}
+
+	rf := &orchestratorRunFunc{
+		listSessionsOut: "live|1|0|",
+		listPanesOut:    "0:0",
+	}
+	mock := &mockCommander{RunFunc: rf.run}
+	logger, _ := openTestLogger(t, dir)
+	o := newOrchestrator(t, mock, dir, logger)
+
+	restored := o.RestoreIndex(idx)
+
+	if len(restored) != 1 || restored[0] != "gone" {
+		t.Fatalf("RestoreIndex = %v, want [gone]", restored)
+	}
+	if got := len(findAllCalls(mock.Calls, "new-session")); got != 1 {
+		t.Fatalf("new-session calls = %d, want 1", got)
+	}
+	// The hydrate helper is pointed at the snapshot's frozen scrollback.
+	wantFile := dir + "/snapshots/s1/scrollback/gone__0.0.bin"
+	respawns := findAllCalls(mock.Calls, "respawn-pane")
+	if len(respawns) != 1 {
+		t.Fatalf("respawn-pane calls = %d, want 1", len(respawns))
+	}
+	if args := strings.Join(mock.Calls[respawns[0]], " "); !strings.Contains(args, wantFile) {
+		t.Errorf("respawn-pane args %q do not reference %q", args, wantFile)
+	}
+}
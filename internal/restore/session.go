@@ -68,9 +68,23 @@ type SessionRestorer struct {
 // number of renames. It is computed here from saved state only; the firing
 // path (the helper) resolves hooks.json by this baked key and never reads the
 // live @portal-id.
+//
+// `resume` carries the pane's saved working directory, session directory and
+// foreground command, which the helper matches against project resume hooks
+// when no hook is registered on hookKey, plus the command's full argv.
 type savedPaneArmInfo struct {
 	scrollAbs string
 	hookKey   string
+	resume    resumeContext
+}
+
+// resumeContext is the saved pane context handed to the hydrate helper for
+// project resume-hook matching and for the resume command's environment.
+type resumeContext struct {
+	dir        string
+	sessionDir string
+	command    string
+	argv       []string
 }
 
 // Restore creates the session with all of its windows and panes in their
@@ -110,11 +124,13 @@ func (r *SessionRestorer) Restore(sess state.Session) ([]tmux.PaneCoord, error)
 // linearly against the live re-query result.
 func (r *SessionRestorer) collectArmInfos(sess state.Session) []savedPaneArmInfo {
 	var infos []savedPaneArmInfo
+	sessionDir := state.SessionDir(sess)
 	for _, w := range sess.Windows {
 		for _, p := range w.Panes {
 			infos = append(infos, savedPaneArmInfo{
 				scrollAbs: filepath.Join(r.StateDir, p.ScrollbackFile),
 				hookKey:   tmux.HookKey(sess.PortalID, sess.Name, w.Index, p.Index),
+				resume:    resumeContext{dir: p.CWD, sessionDir: sessionDir, command: p.CurrentCommand, argv: p.Argv},
 			})
 		}
 	}
@@ -151,6 +167,11 @@ func (r *SessionRestorer) createSkeleton(sess state.Session) error {
 	if sess.PortalID != "" {
 		_ = r.Client.SetSessionOption(sess.Name, session.PortalIDOption, sess.PortalID)
 	}
+	// @portal-dir is re-stamped for the same reason, so the restored session
+	// keeps matching its project's restore policy and reap exemptions.
+	if sess.Dir != "" {
+		_ = r.Client.SetSessionOption(sess.Name, session.PortalDirOption, sess.Dir)
+	}
 
 	r.applyEnvironment(sess)
 
@@ -239,7 +260,7 @@ func (r *SessionRestorer) armPanes(sess state.Session, armInfos []savedPaneArmIn
 			return nil, fmt.Errorf("session %q: %w", sess.Name, err)
 		}
 
-		hydrateCmd := buildHydrateCommand(fifo, info.scrollAbs, info.hookKey)
+		hydrateCmd := buildHydrateCommand(fifo, info.scrollAbs, info.hookKey) + resumeContextFlags(info.resume)
 		liveTarget := tmux.PaneTarget(sess.Name, live.Window, live.Pane)
 		if err := r.Client.RespawnPane(liveTarget, hydrateCmd); err != nil {
 			return nil, fmt.Errorf("session %q: arm pane %s: %w", sess.Name, liveTarget, err)
@@ -502,6 +523,45 @@ func buildHydrateCommand(fifo, file, hookKey string) string {
 	)
 }
 
+// resumeContextFlags returns the optional hydrate flags carrying rc, each
+// single-quoted like buildHydrateCommand's values and led by a space, or ""
+// when rc is empty. Empty values are omitted, as is a --session-dir equal to
+// --dir — the common case, since the session directory is its active pane's.
+func resumeContextFlags(rc resumeContext) string {
+	var b strings.Builder
+	if rc.dir != "" {
+		b.WriteString(" --dir " + shellQuoteSingle(rc.dir))
+	}
+	if rc.sessionDir != "" && rc.sessionDir != rc.dir {
+		b.WriteString(" --session-dir " + shellQuoteSingle(rc.sessionDir))
+	}
+	if rc.command != "" {
+		b.WriteString(" --command " + shellQuoteSingle(rc.command))
+	}
+	if len(rc.argv) > 0 {
+		b.WriteString(" --argv " + shellQuoteSingle(shellJoin(rc.argv)))
+	}
+	return b.String()
+}
+
+// shellJoin renders argv as one shell command line that re-runs it exactly:
+// arguments made only of characters the shell passes through untouched are
+// left bare, and everything else is single-quoted.
+func shellJoin(argv []string) string {
+	words := make([]string, len(argv))
+	for i, a := range argv {
+		if a != "" && strings.Trim(a, shellSafeChars) == "" {
+			words[i] = a
+		} else {
+			words[i] = shellQuoteSingle(a)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// shellSafeChars are the characters shellJoin leaves unquoted.
+const shellSafeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@%+,"
+
 // shellQuoteSingle wraps s in single quotes for safe interpolation into a
 // shell command string. Embedded single quotes are escaped via the standard
 // close-escape-reopen idiom: each literal single quote in s is replaced by
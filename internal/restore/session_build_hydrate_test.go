@@ -88,3 +88,52 @@ func TestBuildHydrateCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestResumeContextFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		rc   resumeContext
+		want string
+	}{
+		{"empty context adds nothing", resumeContext{}, ""},
+		{
+			"session dir equal to the pane dir is omitted",
+			resumeContext{dir: "/code/api", sessionDir: "/code/api", command: "npm"},
+			" --dir '/code/api' --command 'npm'",
+		},
+		{
+			"distinct session dir and quoted values",
+			resumeContext{dir: "/tmp/it's", sessionDir: "/code/api", command: "vim"},
+			` --dir '/tmp/it'\''s' --session-dir '/code/api' --command 'vim'`,
+		},
+		{
+			"argv is passed as one quoted command line",
+			resumeContext{command: "node", argv: []string{"node", "server.js", "--title", "my app"}},
+			` --command 'node' --argv 'node server.js --title '\''my app'\'''`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resumeContextFlags(tt.rc); got != tt.want {
+				t.Errorf("resumeContextFlags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"npm", "run", "dev", "--port=3000"}, "npm run dev --port=3000"},
+		{[]string{"vim", "my notes.md"}, `vim 'my notes.md'`},
+		{[]string{"echo", "it's", ""}, `echo 'it'\''s' ''`},
+		{[]string{"grep", "-e", "a|b"}, `grep -e 'a|b'`},
+	}
+	for _, tt := range tests {
+		if got := shellJoin(tt.argv); got != tt.want {
+			t.Errorf("shellJoin(%q) = %q, want %q", tt.argv, got, tt.want)
+		}
+	}
+}
@@ -712,8 +712,8 @@ func TestSessionRestorer_HydrateCommandFormat(t *testing.T) {
 	wantFIFO := state.FIFOPath(dir, liveKey)
 	wantFile := filepath.Join(dir, "scrollback/work__0.0.bin")
 	wantCmd := fmt.Sprintf(
-		"portal state hydrate --fifo '%s' --file '%s' --hook-key '%s'",
-		wantFIFO, wantFile, "work:0.0",
+		"portal state hydrate --fifo '%s' --file '%s' --hook-key '%s' --dir '%s'",
+		wantFIFO, wantFile, "work:0.0", "/work",
 	)
 	if hydrate != wantCmd {
 		t.Errorf("hydrate cmd:\n got %q\nwant %q", hydrate, wantCmd)
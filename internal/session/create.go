@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 // PortalDirOption is the tmux session user-option that stamps a session with
@@ -60,20 +62,36 @@ type ProjectStore interface {
 	Upsert(path, name, via string) error
 }
 
-// TmuxClient provides tmux session operations.
+// TmuxClient provides tmux session operations. The window, pane and layout
+// methods build a project's layout template into a new session.
 type TmuxClient interface {
 	HasSession(name string) bool
 	NewSession(name, dir, shellCommand string) error
 	SetSessionOption(session, name, value string) error
+	NewWindow(target, name, cwd, shellCommand string) error
+	SplitWindow(target, cwd, shellCommand string) error
+	RenameWindow(target, name string) error
+	ListPanesInSession(session string) ([]tmux.PaneCoord, error)
+	SelectLayout(session string, window int, layout string) error
+	SelectWindow(session string, window int) error
+	KillSession(name string) error
+	RunShell(command string) error
 }
 
+// CreateHook returns the shell command to run in the background once the
+// session name has been created, or "" for none. Portal uses it to fire a
+// project's on-create hooks.
+type CreateHook func(name string) string
+
 // SessionCreator orchestrates the creation of a new tmux session from a directory.
 type SessionCreator struct {
-	git   GitResolver
-	store ProjectStore
-	tmux  TmuxClient
-	gen   IDGenerator
-	shell string
+	git       GitResolver
+	store     ProjectStore
+	tmux      TmuxClient
+	gen       IDGenerator
+	shell     string
+	templates TemplateLoader
+	onCreate  CreateHook
 }
 
 // NewSessionCreator creates a SessionCreator with the given dependencies.
@@ -88,9 +106,25 @@ func NewSessionCreator(git GitResolver, store ProjectStore, tmux TmuxClient, gen
 	}
 }
 
+// WithTemplates makes CreateFromDir build new sessions from the project's
+// layout template when it has one. It returns sc for chaining.
+func (sc *SessionCreator) WithTemplates(loader TemplateLoader) *SessionCreator {
+	sc.templates = loader
+	return sc
+}
+
+// WithCreateHook makes CreateFromDir run hook's command through tmux
+// run-shell once the session is fully built. It returns sc for chaining.
+func (sc *SessionCreator) WithCreateHook(hook CreateHook) *SessionCreator {
+	sc.onCreate = hook
+	return sc
+}
+
 // CreateFromDir resolves the directory to a git root, generates a session name,
 // upserts the project in the store, and creates a tmux session.
 // When command is non-nil and non-empty, constructs a shell-command for tmux.
+// When the project has a layout template (see WithTemplates) the session is
+// built from it, with command replacing the first pane's command.
 // Returns the generated session name.
 func (sc *SessionCreator) CreateFromDir(dir string, command []string) (string, error) {
 	prepared, err := PrepareSession(dir, command, sc.git, sc.store, sc.tmux, sc.gen, sc.shell)
@@ -98,7 +132,16 @@ func (sc *SessionCreator) CreateFromDir(dir string, command []string) (string, e
 		return "", err
 	}
 
-	if err := sc.tmux.NewSession(prepared.SessionName, prepared.ResolvedDir, prepared.ShellCmd); err != nil {
+	plan, err := loadTemplatePlan(sc.templates, prepared.ResolvedDir, command, sc.shell)
+	if err != nil {
+		return "", err
+	}
+
+	firstDir, firstCmd := prepared.ResolvedDir, prepared.ShellCmd
+	if plan != nil {
+		firstDir, firstCmd = plan[0].panes[0].dir, plan[0].panes[0].shellCmd
+	}
+	if err := sc.tmux.NewSession(prepared.SessionName, firstDir, firstCmd); err != nil {
 		return "", fmt.Errorf("failed to create tmux session: %w", err)
 	}
 
@@ -123,5 +166,22 @@ func (sc *SessionCreator) CreateFromDir(dir string, command []string) (string, e
 		_ = sc.tmux.SetSessionOption(prepared.SessionName, PortalIDOption, token)
 	}
 
+	// A half-built layout is killed rather than handed over: the caller gets
+	// the error, and no session with missing panes is left behind.
+	if plan != nil {
+		if err := applyTemplate(sc.tmux, prepared.SessionName, plan); err != nil {
+			_ = sc.tmux.KillSession(prepared.SessionName)
+			return "", fmt.Errorf("failed to apply template to %q: %w", prepared.SessionName, err)
+		}
+	}
+
+	// Best-effort like the stamps: a hook that cannot be started must not
+	// fail the session it was meant to decorate.
+	if sc.onCreate != nil {
+		if cmd := sc.onCreate(prepared.SessionName); cmd != "" {
+			_ = sc.tmux.RunShell(cmd)
+		}
+	}
+
 	return prepared.SessionName, nil
 }
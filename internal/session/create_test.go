@@ -3,10 +3,12 @@ package session_test
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"testing"
 
 	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/tmux"
 )
 
 func TestBuildShellCommand(t *testing.T) {
@@ -140,6 +142,15 @@ type mockTmuxClient struct {
 
 	setOptionCalls []setOptionCall
 	setOptionErr   error
+
+	// calls records the template-building calls (windows, splits, layouts)
+	// as argv-like slices; livePanes is what ListPanesInSession returns.
+	calls     [][]string
+	splitErr  error
+	livePanes []tmux.PaneCoord
+	listErr   error
+
+	killed []string
 }
 
 // setOptionCallFor returns the recorded SetSessionOption call for the given
@@ -173,6 +184,45 @@ func (m *mockTmuxClient) SetSessionOption(session, name, value string) error {
 	return m.setOptionErr
 }
 
+func (m *mockTmuxClient) NewWindow(target, name, cwd, shellCommand string) error {
+	m.calls = append(m.calls, []string{"new-window", target, name, cwd, shellCommand})
+	return nil
+}
+
+func (m *mockTmuxClient) SplitWindow(target, cwd, shellCommand string) error {
+	m.calls = append(m.calls, []string{"split-window", target, cwd, shellCommand})
+	return m.splitErr
+}
+
+func (m *mockTmuxClient) RenameWindow(target, name string) error {
+	m.calls = append(m.calls, []string{"rename-window", target, name})
+	return nil
+}
+
+func (m *mockTmuxClient) ListPanesInSession(string) ([]tmux.PaneCoord, error) {
+	return m.livePanes, m.listErr
+}
+
+func (m *mockTmuxClient) KillSession(name string) error {
+	m.killed = append(m.killed, name)
+	return nil
+}
+
+func (m *mockTmuxClient) SelectLayout(session string, window int, layout string) error {
+	m.calls = append(m.calls, []string{"select-layout", fmt.Sprintf("%s:%d", session, window), layout})
+	return nil
+}
+
+func (m *mockTmuxClient) SelectWindow(session string, window int) error {
+	m.calls = append(m.calls, []string{"select-window", fmt.Sprintf("%s:%d", session, window)})
+	return nil
+}
+
+func (m *mockTmuxClient) RunShell(command string) error {
+	m.calls = append(m.calls, []string{"run-shell", command})
+	return nil
+}
+
 func TestCreateFromDir(t *testing.T) {
 	namePattern := regexp.MustCompile(`^[a-zA-Z0-9_-]+-[a-zA-Z0-9]{6}$`)
 
@@ -691,4 +741,42 @@ func TestCreateFromDir(t *testing.T) {
 			t.Errorf("session name = %q, want %q", sessionName, wantName)
 		}
 	})
+	t.Run("runs the create hook's command once the session exists", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		var hookedName string
+		creator := session.NewSessionCreator(&mockGitResolver{}, &mockProjectStore{}, tmuxClient, gen).
+			WithCreateHook(func(name string) string {
+				hookedName = name
+				return "fire " + name
+			})
+
+		name, err := creator.CreateFromDir(t.TempDir(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if hookedName != name {
+			t.Errorf("hook called for %q, want %q", hookedName, name)
+		}
+		want := [][]string{{"run-shell", "fire " + name}}
+		if !reflect.DeepEqual(tmuxClient.calls, want) {
+			t.Errorf("calls = %v, want %v", tmuxClient.calls, want)
+		}
+	})
+
+	t.Run("an empty create hook command runs nothing", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		gen := func() (string, error) { return "abc123", nil }
+		creator := session.NewSessionCreator(&mockGitResolver{}, &mockProjectStore{}, tmuxClient, gen).
+			WithCreateHook(func(string) string { return "" })
+
+		if _, err := creator.CreateFromDir(t.TempDir(), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(tmuxClient.calls) != 0 {
+			t.Errorf("calls = %v, want none", tmuxClient.calls)
+		}
+	})
 }
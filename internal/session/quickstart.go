@@ -20,11 +20,13 @@ type QuickStartResult struct {
 // git root resolution, project registration, session name generation,
 // and returns exec args for atomic tmux create-or-attach via process handoff.
 type QuickStart struct {
-	git     GitResolver
-	store   ProjectStore
-	checker SessionChecker
-	gen     IDGenerator
-	shell   string
+	git       GitResolver
+	store     ProjectStore
+	checker   SessionChecker
+	gen       IDGenerator
+	shell     string
+	templates TemplateLoader
+	onCreate  CreateHook
 }
 
 // NewQuickStart creates a QuickStart with the given dependencies.
@@ -39,6 +41,20 @@ func NewQuickStart(git GitResolver, store ProjectStore, checker SessionChecker,
 	}
 }
 
+// WithTemplates makes Run build new sessions from the project's layout
+// template when it has one. It returns qs for chaining.
+func (qs *QuickStart) WithTemplates(loader TemplateLoader) *QuickStart {
+	qs.templates = loader
+	return qs
+}
+
+// WithCreateHook makes Run chain hook's command in as a background run-shell
+// once the session is fully built. It returns qs for chaining.
+func (qs *QuickStart) WithCreateHook(hook CreateHook) *QuickStart {
+	qs.onCreate = hook
+	return qs
+}
+
 // Run executes the quick-start pipeline for the given path.
 // It resolves the git root, registers the project, generates a session name,
 // and returns the result with exec args for the tmux create-stamp-attach
@@ -74,21 +90,38 @@ func NewQuickStart(git GitResolver, store ProjectStore, checker SessionChecker,
 // belt-and-suspenders attach-to-existing that the uniqueness guarantee makes
 // unreachable, and -A -d on an existing session would attach immediately and
 // break the stamp-before-attach ordering.
+//
+// When the project has a layout template (see WithTemplates), new-session
+// starts the template's first pane and the remaining windows, splits and
+// layouts are chained in after the stamps, before attach-session, so the
+// client attaches to the finished layout. A create hook (see WithCreateHook)
+// is chained last as run-shell -b, so it starts against the finished session
+// without holding up the attach.
 func (qs *QuickStart) Run(path string, command []string) (*QuickStartResult, error) {
 	prepared, err := PrepareSession(path, command, qs.git, qs.store, qs.checker, qs.gen, qs.shell)
 	if err != nil {
 		return nil, err
 	}
 
+	plan, err := loadTemplatePlan(qs.templates, prepared.ResolvedDir, command, qs.shell)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate the @portal-id stamp token in Go before assembling the chain —
 	// there is no error-return point inside the argv chain. Independent of the
 	// name suffix (PrepareSession already consumed one qs.gen call for that);
 	// the id is name-independent. A generation failure omits the stamp step.
 	idToken, idGenErr := qs.gen()
 
-	execArgs := []string{"tmux", "new-session", "-d", "-s", prepared.SessionName, "-c", prepared.ResolvedDir}
-	if prepared.ShellCmd != "" {
-		execArgs = append(execArgs, prepared.ShellCmd)
+	firstDir, firstCmd := prepared.ResolvedDir, prepared.ShellCmd
+	if plan != nil {
+		firstDir, firstCmd = plan[0].panes[0].dir, plan[0].panes[0].shellCmd
+	}
+
+	execArgs := []string{"tmux", "new-session", "-d", "-s", prepared.SessionName, "-c", firstDir}
+	if firstCmd != "" {
+		execArgs = append(execArgs, firstCmd)
 	}
 	execArgs = append(execArgs,
 		";", "set-option", "-t", prepared.SessionName, PortalDirOption, prepared.ResolvedDir,
@@ -98,6 +131,12 @@ func (qs *QuickStart) Run(path string, command []string) (*QuickStartResult, err
 			";", "set-option", "-t", prepared.SessionName, PortalIDOption, idToken,
 		)
 	}
+	execArgs = append(execArgs, templateExecArgs(prepared.SessionName, plan)...)
+	if qs.onCreate != nil {
+		if cmd := qs.onCreate(prepared.SessionName); cmd != "" {
+			execArgs = append(execArgs, ";", "run-shell", "-b", cmd)
+		}
+	}
 	execArgs = append(execArgs,
 		";", "attach-session", "-t", prepared.SessionName,
 	)
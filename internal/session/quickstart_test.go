@@ -446,6 +446,27 @@ func TestQuickStart(t *testing.T) {
 	})
 }
 
+func TestQuickStartCreateHook(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	dir := t.TempDir()
+	gen := func() (string, error) { return "abc123", nil }
+	qs := session.NewQuickStart(&mockGitResolver{}, &mockProjectStore{}, &mockSessionChecker{}, gen).
+		WithCreateHook(func(name string) string { return "fire " + name })
+
+	result, err := qs.Run(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := result.SessionName
+	want := wantExecArgs(name, dir, "", "abc123")
+	attach := len(want) - 4
+	want = append(want[:attach:attach], ";", "run-shell", "-b", "fire "+name, ";", "attach-session", "-t", name)
+	if !reflect.DeepEqual(result.ExecArgs, want) {
+		t.Errorf("ExecArgs = %v\nwant       %v", result.ExecArgs, want)
+	}
+}
+
 // indexOf returns the first index of v in s, or -1.
 func indexOf(s []string, v string) int {
 	for i, x := range s {
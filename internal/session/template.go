@@ -0,0 +1,153 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/leeovery/portal/internal/layout"
+)
+
+// TemplateLoader finds the layout template for a resolved project directory,
+// returning nil when the project has none.
+type TemplateLoader interface {
+	Template(dir string) (*layout.Template, error)
+}
+
+// windowPlan and panePlan are a template resolved against a project root:
+// absolute working directories and ready-to-run tmux shell-commands.
+type windowPlan struct {
+	name   string
+	layout string
+	panes  []panePlan
+}
+
+type panePlan struct {
+	dir      string
+	shellCmd string
+}
+
+// loadTemplatePlan resolves the template for root into a plan, or returns nil
+// when loader is nil or the project has no template. A command given on the
+// command line replaces the first pane's command, so `x . -- claude` keeps the
+// project's layout and only swaps what the first pane runs.
+func loadTemplatePlan(loader TemplateLoader, root string, command []string, shell string) ([]windowPlan, error) {
+	if loader == nil {
+		return nil, nil
+	}
+	t, err := loader.Template(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	if t == nil {
+		return nil, nil
+	}
+
+	plan := make([]windowPlan, 0, len(t.Windows))
+	for _, w := range t.Windows {
+		panes := w.Panes
+		if len(panes) == 0 {
+			panes = []layout.Pane{{}}
+		}
+		wp := windowPlan{name: w.Name, layout: w.Layout}
+		for _, p := range panes {
+			shellCmd := ""
+			if p.Command != "" {
+				shellCmd = BuildShellCommand([]string{p.Command}, shell)
+			}
+			wp.panes = append(wp.panes, panePlan{dir: layout.PaneDir(root, w, p), shellCmd: shellCmd})
+		}
+		plan = append(plan, wp)
+	}
+
+	if len(command) > 0 {
+		plan[0].panes[0].shellCmd = BuildShellCommand(command, shell)
+	}
+	return plan, nil
+}
+
+// applyTemplate builds the rest of plan into the session name, whose first
+// pane new-session already created from plan[0].panes[0]. Splits and
+// new-windows target "<name>:" — the active window — which is always the
+// window just created, so no window index is predicted. Windows and panes
+// that fail to create, and a failure to list the panes the layouts are
+// applied to, abort with an error; layouts and the final return to the first
+// window are best-effort, as in restore's geometry pass.
+func applyTemplate(client TmuxClient, name string, plan []windowPlan) error {
+	target := name + ":"
+
+	for wi, w := range plan {
+		if wi == 0 {
+			if w.name != "" {
+				if err := client.RenameWindow(target, w.name); err != nil {
+					return err
+				}
+			}
+		} else if err := client.NewWindow(target, w.name, w.panes[0].dir, w.panes[0].shellCmd); err != nil {
+			return err
+		}
+		for _, p := range w.panes[1:] {
+			if err := client.SplitWindow(target, p.dir, p.shellCmd); err != nil {
+				return err
+			}
+		}
+	}
+
+	coords, err := client.ListPanesInSession(name)
+	if err != nil {
+		return fmt.Errorf("failed to list panes: %w", err)
+	}
+	var windows []int
+	for _, c := range coords {
+		if len(windows) == 0 || windows[len(windows)-1] != c.Window {
+			windows = append(windows, c.Window)
+		}
+	}
+	for wi, w := range plan {
+		if w.layout != "" && wi < len(windows) {
+			_ = client.SelectLayout(name, windows[wi], w.layout)
+		}
+	}
+	if len(plan) > 1 && len(windows) > 0 {
+		_ = client.SelectWindow(name, windows[0])
+	}
+	return nil
+}
+
+// templateExecArgs renders the steps of applyTemplate as ";"-chained tmux
+// arguments for QuickStart's exec handoff. Inside the chain the layout is
+// applied right after each window's splits, while that window is still the
+// active one, and select-window "<name>:^" returns to the lowest-numbered
+// window whatever base-index is set to.
+func templateExecArgs(name string, plan []windowPlan) []string {
+	target := name + ":"
+
+	var args []string
+	for wi, w := range plan {
+		if wi == 0 {
+			if w.name != "" {
+				args = append(args, ";", "rename-window", "-t", target, w.name)
+			}
+		} else {
+			args = append(args, ";", "new-window", "-t", target)
+			if w.name != "" {
+				args = append(args, "-n", w.name)
+			}
+			args = append(args, "-c", w.panes[0].dir)
+			if w.panes[0].shellCmd != "" {
+				args = append(args, w.panes[0].shellCmd)
+			}
+		}
+		for _, p := range w.panes[1:] {
+			args = append(args, ";", "split-window", "-t", target, "-c", p.dir)
+			if p.shellCmd != "" {
+				args = append(args, p.shellCmd)
+			}
+		}
+		if w.layout != "" {
+			args = append(args, ";", "select-layout", "-t", target, w.layout)
+		}
+	}
+	if len(plan) > 1 {
+		args = append(args, ";", "select-window", "-t", target+"^")
+	}
+	return args
+}
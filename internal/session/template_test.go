@@ -0,0 +1,178 @@
+package session_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/layout"
+	"github.com/leeovery/portal/internal/session"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// fakeTemplates implements session.TemplateLoader, recording the directory
+// it was asked about.
+type fakeTemplates struct {
+	tmpl  *layout.Template
+	err   error
+	asked string
+}
+
+func (f *fakeTemplates) Template(dir string) (*layout.Template, error) {
+	f.asked = dir
+	return f.tmpl, f.err
+}
+
+// devTemplate is a two-window template: an editor beside a dev server, then a
+// log window rooted in a subdirectory.
+func devTemplate() *layout.Template {
+	return &layout.Template{Windows: []layout.Window{
+		{Name: "code", Layout: "main-vertical", Panes: []layout.Pane{
+			{Command: "nvim"},
+			{Dir: "web", Command: "npm run dev"},
+		}},
+		{Name: "logs", Dir: "log"},
+	}}
+}
+
+func TestCreateFromDirWithTemplate(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	gen := func() (string, error) { return "abc123", nil }
+
+	t.Run("it builds the template's windows, panes and layouts", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{
+			existingSessions: map[string]bool{},
+			livePanes:        []tmux.PaneCoord{{Window: 1, Pane: 1}, {Window: 1, Pane: 2}, {Window: 2, Pane: 1}},
+		}
+		templates := &fakeTemplates{tmpl: devTemplate()}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(templates)
+
+		name, err := creator.CreateFromDir("/code/app/sub", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if templates.asked != "/code/app" {
+			t.Errorf("template looked up for %q, want the resolved root", templates.asked)
+		}
+		if tmuxClient.newSessionDir != "/code/app" || tmuxClient.newSessionShellCmd != "/bin/zsh -ic 'nvim; exec /bin/zsh'" {
+			t.Errorf("new-session = (%q, %q), want the first pane's dir and command", tmuxClient.newSessionDir, tmuxClient.newSessionShellCmd)
+		}
+		want := [][]string{
+			{"rename-window", name + ":", "code"},
+			{"split-window", name + ":", "/code/app/web", "/bin/zsh -ic 'npm run dev; exec /bin/zsh'"},
+			{"new-window", name + ":", "logs", "/code/app/log", ""},
+			{"select-layout", name + ":1", "main-vertical"},
+			{"select-window", name + ":1"},
+		}
+		if !reflect.DeepEqual(tmuxClient.calls, want) {
+			t.Errorf("calls = %v\nwant    %v", tmuxClient.calls, want)
+		}
+		if _, ok := tmuxClient.setOptionCallFor(session.PortalDirOption); !ok {
+			t.Error("@portal-dir was not stamped")
+		}
+	})
+
+	t.Run("a command-line command replaces the first pane's command", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(&fakeTemplates{tmpl: devTemplate()})
+
+		if _, err := creator.CreateFromDir("/code/app", []string{"claude"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := tmuxClient.newSessionShellCmd; got != "/bin/zsh -ic 'claude; exec /bin/zsh'" {
+			t.Errorf("first pane command = %q, want the command-line command", got)
+		}
+	})
+
+	t.Run("a broken template fails before any session is created", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(&fakeTemplates{err: errors.New("invalid .portal.yml")})
+
+		_, err := creator.CreateFromDir("/code/app", nil)
+
+		if err == nil || !strings.Contains(err.Error(), "invalid .portal.yml") {
+			t.Errorf("err = %v, want the template error", err)
+		}
+		if tmuxClient.newSessionName != "" {
+			t.Errorf("new-session ran for %q despite the broken template", tmuxClient.newSessionName)
+		}
+	})
+
+	t.Run("a failed split is reported", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}, splitErr: errors.New("no space for new pane")}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(&fakeTemplates{tmpl: devTemplate()})
+
+		_, err := creator.CreateFromDir("/code/app", nil)
+
+		if err == nil || !strings.Contains(err.Error(), "no space for new pane") {
+			t.Errorf("err = %v, want the split failure", err)
+		}
+		if len(tmuxClient.killed) != 1 || tmuxClient.killed[0] != tmuxClient.newSessionName {
+			t.Errorf("killed = %v, want the half-built session %q", tmuxClient.killed, tmuxClient.newSessionName)
+		}
+	})
+
+	t.Run("a failed pane listing is reported and the session killed", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}, listErr: errors.New("server exited")}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(&fakeTemplates{tmpl: devTemplate()})
+
+		_, err := creator.CreateFromDir("/code/app", nil)
+
+		if err == nil || !strings.Contains(err.Error(), "server exited") {
+			t.Errorf("err = %v, want the list-panes failure", err)
+		}
+		if len(tmuxClient.killed) != 1 {
+			t.Errorf("killed = %v, want the half-built session", tmuxClient.killed)
+		}
+	})
+
+	t.Run("no template keeps the single-pane session", func(t *testing.T) {
+		tmuxClient := &mockTmuxClient{existingSessions: map[string]bool{}}
+		creator := session.NewSessionCreator(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, tmuxClient, gen).
+			WithTemplates(&fakeTemplates{})
+
+		if _, err := creator.CreateFromDir("/code/app", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(tmuxClient.calls) != 0 {
+			t.Errorf("calls = %v, want none beyond new-session", tmuxClient.calls)
+		}
+	})
+}
+
+func TestQuickStartWithTemplate(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+	gen := func() (string, error) { return "abc123", nil }
+	qs := session.NewQuickStart(&mockGitResolver{resolvedDir: "/code/app"}, &mockProjectStore{}, &mockSessionChecker{}, gen).
+		WithTemplates(&fakeTemplates{tmpl: devTemplate()})
+
+	result, err := qs.Run("/code/app", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	name := result.SessionName
+	want := []string{
+		"tmux", "new-session", "-d", "-s", name, "-c", "/code/app", "/bin/zsh -ic 'nvim; exec /bin/zsh'",
+		";", "set-option", "-t", name, session.PortalDirOption, "/code/app",
+		";", "set-option", "-t", name, session.PortalIDOption, "abc123",
+		";", "rename-window", "-t", name + ":", "code",
+		";", "split-window", "-t", name + ":", "-c", "/code/app/web", "/bin/zsh -ic 'npm run dev; exec /bin/zsh'",
+		";", "select-layout", "-t", name + ":", "main-vertical",
+		";", "new-window", "-t", name + ":", "-n", "logs", "-c", "/code/app/log",
+		";", "select-window", "-t", name + ":^",
+		";", "attach-session", "-t", name,
+	}
+	if !reflect.DeepEqual(result.ExecArgs, want) {
+		t.Errorf("ExecArgs = %v\nwant       %v", result.ExecArgs, want)
+	}
+}
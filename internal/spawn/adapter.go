@@ -16,6 +16,21 @@ type Adapter interface {
 	// and never parses the target out of the argv (the spec rejects a
 	// session-aware OpenAttached(session)).
 	OpenWindow(command []string) Result
+
+	// OpenTab opens command as a new tab in the host terminal's front window.
+	// It takes the same composed argv as OpenWindow and reports through the
+	// same Result taxonomy, so the burster's ack collection is unchanged.
+	OpenTab(command []string) Result
+
+	// OpenSplit opens command in a new split of the host terminal's focused
+	// pane, with the same argv and Result contract as OpenWindow.
+	OpenSplit(command []string) Result
+
+	// Supports reports whether the driver can open surfaces with placement p.
+	// PlacementWindows is always supported; a driver that cannot open tabs or
+	// splits embeds WindowsOnly, and callers downgrade through
+	// EffectivePlacement rather than calling an unsupported capability.
+	Supports(p Placement) bool
 }
 
 // Outcome is the generic, terminal-agnostic classification of an OpenWindow
@@ -64,24 +64,31 @@ type WindowResult struct {
 
 // Burster is the N−1 external half of the spawn burst: it generates a batch id +
 // one opaque token per external window, opens each window sequentially through
-// Adapter, and confirms each by watching Ack for that window's token within a
-// per-window Timeout. The Nth self-attach is the caller's concern.
+// Adapter — as a window, tab, or split per Placement — and confirms each by
+// watching Ack for that window's token within a per-window Timeout. The Nth
+// self-attach is the caller's concern.
 //
 // Every seam is injectable so the whole flow is unit-testable under a fake clock
 // with no real time, tmux, or osascript: Ack (the read-side marker channel),
 // Exe/Getenv (attach-argv composition), NewID (raw id generator wrapped by
 // NewSpawnID per id), Timeout/Poll (the per-window ack budget + poll cadence),
 // and Now/Sleep (the clock). NewBurster applies production defaults.
+//
+// Placement selects the adapter capability each surface opens through; the zero
+// value and any placement the adapter does not support both open windows (see
+// EffectivePlacement). Every placement shares the same per-surface token ack,
+// so "window" in the names below means any opened surface.
 type Burster struct {
-	Adapter Adapter
-	Ack     AckCollector
-	Exe     ExecutableResolver
-	Getenv  func(string) string
-	NewID   func() (string, error)
-	Timeout time.Duration
-	Poll    time.Duration
-	Now     func() time.Time
-	Sleep   func(time.Duration)
+	Adapter   Adapter
+	Placement Placement
+	Ack       AckCollector
+	Exe       ExecutableResolver
+	Getenv    func(string) string
+	NewID     func() (string, error)
+	Timeout   time.Duration
+	Poll      time.Duration
+	Now       func() time.Time
+	Sleep     func(time.Duration)
 }
 
 // NewBurster wires a Burster to its adapter + ack channel + composition seams and
@@ -169,7 +176,7 @@ func (b *Burster) Run(ctx context.Context, external []Surface, command []string,
 		}
 		token := tokens[i]
 		argv := composeOpenArgv(exePath, path, surface, batch, token, command)
-		result := b.Adapter.OpenWindow(argv)
+		result := openPlaced(b.Adapter, b.Placement, argv)
 
 		ack := AckFailed
 		if result.OK() {
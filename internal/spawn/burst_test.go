@@ -76,6 +76,7 @@ func (e *erroringAck) Collect(batch string) (map[string]struct{}, error) {
 // non-success Result (or a false confirm flag) it writes nothing, so the burster
 // times that window out.
 type writingAdapter struct {
+	WindowsOnly
 	calls   [][]string
 	results []Result
 	confirm []bool
@@ -76,12 +76,52 @@ func recipeFailureDetail(out string, exitCode int, err error) string {
 	return execFailureDetail(out, exitCode, err, "recipe exit %d")
 }
 
+// recipePlacements is the tab/split half of a config recipe adapter: the
+// entry's optional `open_tab` and `open_split` recipes, each held as a
+// single-recipe adapter whose OpenWindow runs that recipe with the same
+// {command} / $1 delivery as `open`. A nil member means the entry declared no
+// valid recipe for that placement, and Supports reports false for it.
+type recipePlacements struct {
+	tab   Adapter
+	split Adapter
+}
+
+// OpenTab runs the entry's `open_tab` recipe.
+func (p recipePlacements) OpenTab(command []string) Result {
+	if p.tab == nil {
+		return SpawnFailed("terminals.json entry has no open_tab recipe")
+	}
+	return p.tab.OpenWindow(command)
+}
+
+// OpenSplit runs the entry's `open_split` recipe.
+func (p recipePlacements) OpenSplit(command []string) Result {
+	if p.split == nil {
+		return SpawnFailed("terminals.json entry has no open_split recipe")
+	}
+	return p.split.OpenWindow(command)
+}
+
+// Supports reports windows always, and tabs/splits only when the entry
+// declared a valid recipe for them.
+func (p recipePlacements) Supports(placement Placement) bool {
+	switch placement.orWindows() {
+	case PlacementTabs:
+		return p.tab != nil
+	case PlacementSplits:
+		return p.split != nil
+	default:
+		return true
+	}
+}
+
 // argvRecipeAdapter is the config-escape-hatch Adapter for a validated argv
 // recipe: it substitutes the composed attach command into the recipe's argv
 // template and runs the result through the recipeRunner seam. The constructor
 // wiring (matchConfig winner + RecipeArgv → &argvRecipeAdapter{recipe.Argv,
 // r.runner}) lives in the resolver.
 type argvRecipeAdapter struct {
+	recipePlacements
 	template []string
 	runner   recipeRunner
 }
@@ -113,7 +153,7 @@ var _ Adapter = (*argvRecipeAdapter)(nil)
 // carries its OWN exec bit + shebang and Portal execs it DIRECTLY (never via
 // `sh <path>`), so a file with no exec bit could never run and is rejected here;
 // the check is a Perm() mode-bit test, not an access probe, so it is root-safe.
-func newScriptRecipeAdapter(key, rawPath string, runner recipeRunner) (Adapter, bool) {
+func newScriptRecipeAdapter(key, rawPath string, runner recipeRunner) (*scriptRecipeAdapter, bool) {
 	p := resolver.ExpandTilde(rawPath)
 	info, err := os.Stat(p)
 	if err != nil {
@@ -134,6 +174,7 @@ func newScriptRecipeAdapter(key, rawPath string, runner recipeRunner) (Adapter,
 // {command} token. The constructor wiring (matchConfig winner + RecipeScript →
 // newScriptRecipeAdapter) lives in the resolver (Task 4.6).
 type scriptRecipeAdapter struct {
+	recipePlacements
 	scriptPath string
 	runner     recipeRunner
 }
@@ -9,8 +9,15 @@ import "strings"
 // spelling of the family, so config matching stays on bundle-id families
 // (identical to native detection). Extend this table as more terminals ship.
 var friendlyAliases = map[string]string{
-	"ghostty": "com.mitchellh.ghostty*",
-	"warp":    "dev.warp.Warp-*",
+	"ghostty":        "com.mitchellh.ghostty*",
+	"warp":           "dev.warp.Warp-*",
+	"kitty":          "net.kovidgoyal.kitty",
+	"wezterm":        "com.github.wez.wezterm",
+	"alacritty":      "org.alacritty",
+	"foot":           "org.codeberg.dnkl.foot",
+	"gnome-terminal": "org.gnome.Terminal",
+	"konsole":        "org.kde.konsole",
+	"xterm":          "net.invisible-island.xterm",
 }
 
 // Specificity tiers, highest-first. A user's exact override always beats their
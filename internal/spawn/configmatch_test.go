@@ -87,6 +87,16 @@ func TestMatchConfig(t *testing.T) {
 			wantKey: "warp",
 			wantOK:  true,
 		},
+		{
+			name: "it matches a Linux-detected terminal by its friendly alias",
+			cfg: TerminalsConfig{
+				"gnome-terminal": {},
+				"org.*":          {},
+			},
+			id:      NewIdentity("org.gnome.Terminal", "GNOME Terminal"),
+			wantKey: "gnome-terminal",
+			wantOK:  true,
+		},
 	}
 
 	for _, tt := range tests {
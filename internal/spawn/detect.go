@@ -59,14 +59,15 @@ type Detector struct {
 
 // NewDetector builds the production Detector, wiring the real seams: tmux.
 // InsideTmux for the branch, os.Getenv / os.Getpid for the outside path, the
-// real `ps`/`defaults`-backed walker and reader, the tmux client-list adapter
-// and current-session read for the inside path, and the spawn-component logger.
+// real walker (/proc where it exists, else `ps`) and `defaults`-backed reader,
+// the tmux client-list adapter and current-session read for the inside path,
+// and the spawn-component logger.
 func NewDetector(client *tmux.Client) *Detector {
 	return &Detector{
 		insideTmux:     tmux.InsideTmux,
 		getenv:         os.Getenv,
 		selfPID:        os.Getpid(),
-		walker:         realProcessWalker{},
+		walker:         newProcessWalker("/proc"),
 		reader:         realBundleReader{},
 		lister:         tmuxClientLister{c: client},
 		currentSession: client.CurrentSessionName,
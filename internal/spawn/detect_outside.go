@@ -30,7 +30,11 @@ var ghosttyEnvKeys = []string{
 //  2. GHOSTTY_* — Ghostty stamps its own env vars; their presence (absent a
 //     usable __CFBundleIdentifier) resolves to Ghostty's known bundle id, with
 //     no walk.
-//  3. Otherwise walkToBundle from selfPID (the picker's own pid), propagating
+//  3. Linux terminal env markers (KITTY_PID, WEZTERM_PANE, ALACRITTY_SOCKET, …;
+//     see linuxTerminals) resolve to that terminal's identity when exactly one
+//     terminal is marked (and kitty's KITTY_PID is an ancestor); see
+//     linuxTerminalFromEnv.
+//  4. Otherwise walkToBundle from selfPID (the picker's own pid), propagating
 //     its resolved / clean-NULL / transient-error outcome verbatim.
 //
 // getenv is an injectable seam (production passes os.Getenv) so the resolution
@@ -44,6 +48,10 @@ func detectOutsideTmux(getenv func(string) string, selfPID int, walker ProcessWa
 		return NewIdentity(ghosttyBundleID, "Ghostty"), nil
 	}
 
+	if id, ok := linuxTerminalFromEnv(getenv, selfPID, walker); ok {
+		return id, nil
+	}
+
 	return walkToBundle(selfPID, walker, reader)
 }
 
@@ -72,6 +72,83 @@ func TestDetectOutsideTmux(t *testing.T) {
 		}
 	})
 
+	t.Run("it resolves a Linux terminal from its env marker without walking", func(t *testing.T) {
+		tests := []struct {
+			key      string
+			bundleID string
+			name     string
+		}{
+			{"KITTY_WINDOW_ID", "net.kovidgoyal.kitty", "kitty"},
+			{"WEZTERM_PANE", "com.github.wez.wezterm", "WezTerm"},
+			{"ALACRITTY_SOCKET", "org.alacritty", "Alacritty"},
+			{"GNOME_TERMINAL_SCREEN", "org.gnome.Terminal", "GNOME Terminal"},
+			{"KONSOLE_VERSION", "org.kde.konsole", "Konsole"},
+			{"XTERM_VERSION", "net.invisible-island.xterm", "xterm"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.key, func(t *testing.T) {
+				getenv := mapGetenv(map[string]string{tt.key: "1"})
+
+				got, err := detectOutsideTmux(getenv, 100, failWalker{t}, failReader{t})
+				if err != nil {
+					t.Fatalf("detectOutsideTmux returned error: %v, want nil", err)
+				}
+				if got.BundleID != tt.bundleID || got.Name != tt.name {
+					t.Errorf("identity = %+v, want {%s %s}", got, tt.bundleID, tt.name)
+				}
+			})
+		}
+	})
+
+	t.Run("it resolves kitty from KITTY_PID when that pid is an ancestor", func(t *testing.T) {
+		getenv := mapGetenv(map[string]string{"KITTY_PID": "50"})
+		walker := &fakeWalker{procs: map[int]fakeProc{
+			100: {ppid: 60, command: "/usr/bin/zsh"},
+			60:  {ppid: 50, command: "/usr/bin/zsh"},
+		}}
+
+		got, err := detectOutsideTmux(getenv, 100, walker, failReader{t})
+		if err != nil {
+			t.Fatalf("detectOutsideTmux returned error: %v, want nil", err)
+		}
+		if got.BundleID != "net.kovidgoyal.kitty" {
+			t.Errorf("identity = %+v, want kitty", got)
+		}
+	})
+
+	t.Run("it walks when KITTY_PID is not an ancestor", func(t *testing.T) {
+		getenv := mapGetenv(map[string]string{"KITTY_PID": "50", "KITTY_WINDOW_ID": "3"})
+		walker := &fakeWalker{procs: map[int]fakeProc{
+			100: {ppid: 70, command: "/usr/bin/zsh"},
+			70:  {ppid: 1, command: "/usr/bin/foot"},
+		}}
+
+		got, err := detectOutsideTmux(getenv, 100, walker, failReader{t})
+		if err != nil {
+			t.Fatalf("detectOutsideTmux returned error: %v, want nil", err)
+		}
+		if got.BundleID != "org.codeberg.dnkl.foot" {
+			t.Errorf("identity = %+v, want the walk's foot, not the inherited kitty marker", got)
+		}
+	})
+
+	t.Run("it walks when markers from nested terminals are both set", func(t *testing.T) {
+		getenv := mapGetenv(map[string]string{"WEZTERM_PANE": "0", "XTERM_VERSION": "XTerm(390)"})
+		walker := &fakeWalker{procs: map[int]fakeProc{
+			100: {ppid: 70, command: "/usr/bin/bash"},
+			70:  {ppid: 40, command: "/usr/bin/xterm"},
+			40:  {ppid: 1, command: "/usr/bin/wezterm-gui"},
+		}}
+
+		got, err := detectOutsideTmux(getenv, 100, walker, failReader{t})
+		if err != nil {
+			t.Fatalf("detectOutsideTmux returned error: %v, want nil", err)
+		}
+		if got.BundleID != "net.invisible-island.xterm" {
+			t.Errorf("identity = %+v, want the innermost terminal (xterm)", got)
+		}
+	})
+
 	t.Run("it falls back to the walk when both env vars are absent", func(t *testing.T) {
 		getenv := mapGetenv(nil)
 		walker := &fakeWalker{procs: map[int]fakeProc{
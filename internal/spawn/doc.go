@@ -10,9 +10,10 @@
 // behaviour cannot drift. (The host-terminal detector alone is also reused by the
 // `portal doctor` host-terminal line.)
 //
-// Detection produces an Identity: the host terminal's macOS bundle id plus a
-// friendly display name, or a NULL identity when there is no host-local
-// terminal (a remote/mosh client, or an unsupported/transient outcome).
+// Detection produces an Identity: the host terminal's macOS bundle id (or, on
+// Linux, the equivalent id of a recognised terminal) plus a friendly display
+// name, or a NULL identity when there is no host-local terminal (a remote/mosh
+// client, or an unsupported/transient outcome).
 // Adapter resolution matches an Identity's bundle id against a bundle-id family
 // glob. Phase 1 of the feature provides only the Identity value type and the
 // standalone family-matching primitive; the process-tree walk, the env
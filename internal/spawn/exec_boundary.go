@@ -1,6 +1,7 @@
 package spawn
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
@@ -9,17 +10,24 @@ import (
 	"github.com/leeovery/portal/internal/log"
 )
 
-// runArgvCombined is the shared exec boundary behind both deliberately-separate
-// production runner seams (execOsascriptRunner and execRecipeRunner): it execs
-// argv through log.CombinedOutputWithContext (the stderr-preserving boundary
-// helper) and derives exitCode from an *exec.ExitError. A clean run returns
+// runArgvCombined is the shared exec boundary behind the deliberately-separate
+// production runner seams (execOsascriptRunner, execRecipeRunner and
+// execRemoteControlRunner): it execs argv through
+// log.CombinedOutputWithContext (the stderr-preserving boundary helper) and
+// derives exitCode from an *exec.ExitError. A clean run returns
 // (stdout, 0, nil); a non-zero (or signal) exit returns the combined output
 // plus the exit code with a nil err (it ran but failed); a non-exit failure
 // (binary missing on PATH — no exit status) surfaces as err so the caller's
 // mapping folds it to spawn-failed. Only this identical plumbing is shared —
-// the two runner interfaces and their Adapters stay fully separate.
+// the runner interfaces and their Adapters stay fully separate.
 func runArgvCombined(argv []string) (out string, exitCode int, err error) {
-	cmd := exec.Command(argv[0], argv[1:]...)
+	return runArgvCombinedContext(context.Background(), argv)
+}
+
+// runArgvCombinedContext is runArgvCombined bound to ctx: the child is killed
+// when ctx is done, for runners that must not hang on an unresponsive CLI.
+func runArgvCombinedContext(ctx context.Context, argv []string) (out string, exitCode int, err error) {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
 	combined, runErr := log.CombinedOutputWithContext(cmd)
 	if runErr == nil {
 		return string(combined), 0, nil
@@ -49,9 +57,9 @@ func combineOutput(stdout []byte, wrapErr error) string {
 }
 
 // execFailureDetail is the shared opaque-Detail formatter for a non-clean exit
-// across both spawn adapters: the combined output and/or execution-error text,
+// across the spawn adapters: the combined output and/or execution-error text,
 // falling back to fallbackLabel (a "%d" format string for the exit code) so
-// Detail is never empty. The two failure-detail wrappers differ only in that
+// Detail is never empty. The adapters' failure details differ only in that
 // label, so a fix or behaviour tweak lands here once.
 func execFailureDetail(out string, exitCode int, err error, fallbackLabel string) string {
 	detail := strings.TrimSpace(out)
@@ -126,7 +126,10 @@ func (execOsascriptRunner) Run(argv []string) (string, int, error) {
 // ghosttyAdapter is the native window-spawning driver for the Ghostty terminal.
 // It owns the thin osascript exec boundary; every Ghostty/AppleScript/osascript
 // specific concern stays quarantined here behind the generic Result taxonomy.
+// The osascript recipe only knows how to open a new window, so the driver is
+// windows-only: tab and split bursts downgrade to windows.
 type ghosttyAdapter struct {
+	WindowsOnly
 	runner osascriptRunner
 }
 
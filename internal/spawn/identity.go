@@ -12,6 +12,10 @@ import (
 // "Ghostty", "Apple Terminal") shown by the picker banner and the `portal doctor`
 // host-terminal line.
 //
+// A terminal recognised on Linux has no bundle to read; BundleID then holds the
+// id Portal assigns it in linuxTerminals (its macOS bundle id where one exists,
+// else its desktop application id, e.g. "org.gnome.Terminal").
+//
 // The zero value is the NULL identity — no host-local terminal — which a
 // remote/mosh client or an unsupported/transient detection outcome resolves to.
 type Identity struct {
@@ -0,0 +1,136 @@
+package spawn
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// kittyRemoteTimeout bounds one `kitty @` call. A socket kitty stopped
+// answering (or a confirmation prompt nobody sees) would otherwise hang the
+// burst that is waiting on it.
+const kittyRemoteTimeout = 5 * time.Second
+
+// kittyRefusals are the lowercased fragments of kitty's own remote-control
+// refusals: remote control switched off in kitty.conf, and a request turned
+// away by remote_control_password or the interactive confirmation. Anything
+// else — a generic "permission denied" on the socket file included — is a
+// spawn failure, not a kitty.conf problem.
+var kittyRefusals = []string{
+	"remote control is disabled",
+	"the user rejected this request",
+	"not allowed by the remote control password",
+}
+
+// kittyAdapter is the native window-spawning driver for kitty. It opens an OS
+// window through kitty's remote control (`kitty @ launch --type=os-window`);
+// every kitty-specific concern stays quarantined here behind the generic Result
+// taxonomy.
+//
+// listenOn is the remote-control socket (kitty's KITTY_LISTEN_ON, e.g.
+// "unix:/tmp/kitty-1234"). Without it `kitty @` would talk to kitty over the
+// controlling terminal, which inside tmux is tmux's — so the resolver only
+// picks this adapter when kitty advertised a socket (kittySocketAdvertised).
+type kittyAdapter struct {
+	runner   remoteControlRunner
+	listenOn string
+}
+
+// newKittyAdapter builds the native kitty adapter wired with the real runner
+// and the socket kitty advertised in the environment. It runs nothing itself.
+func newKittyAdapter() *kittyAdapter {
+	return &kittyAdapter{
+		runner:   execRemoteControlRunner{timeout: kittyRemoteTimeout},
+		listenOn: os.Getenv("KITTY_LISTEN_ON"),
+	}
+}
+
+// kittySocketAdvertised reports whether kitty advertised a remote-control
+// socket (KITTY_LISTEN_ON, set when kitty.conf has `listen_on`). It is the
+// native kitty entry's availability check: without a socket the adapter cannot
+// reach kitty from inside tmux, so kitty resolves as unsupported and a
+// terminals.json recipe remains the way to drive it.
+func kittySocketAdvertised() bool {
+	return os.Getenv("KITTY_LISTEN_ON") != ""
+}
+
+// kittyOpenArgv builds the `kitty @ launch` argv that opens command in a new
+// surface of the given launch type: "os-window" for a window, "tab" for a tab,
+// or "window" (kitty's name for a pane inside a tab) placed as a vertical split.
+// The composed argv is wrapped in the same shell fallback Ghostty uses
+// (wrapWithShellFallback), so the surface lands at a login shell once the
+// session's exec chain ends instead of closing. kitty takes the program as real
+// argv after `--`, so no string rendering is needed beyond the wrapper's own
+// payload.
+func kittyOpenArgv(listenOn, launchType string, command []string) []string {
+	argv := []string{"kitty", "@"}
+	if listenOn != "" {
+		argv = append(argv, "--to", listenOn)
+	}
+	argv = append(argv, "launch", "--type="+launchType)
+	if launchType == "window" {
+		argv = append(argv, "--location=vsplit")
+	}
+	argv = append(argv, "--")
+	return append(argv, wrapWithShellFallback(command)...)
+}
+
+// launch runs one launch argv through the runner seam and maps the outcome to a
+// generic typed Result (mapKittyResult).
+func (k *kittyAdapter) launch(launchType string, command []string) Result {
+	out, code, err := k.runner.Run(kittyOpenArgv(k.listenOn, launchType, command))
+	return mapKittyResult(out, code, err)
+}
+
+// OpenWindow launches command in a new OS window.
+func (k *kittyAdapter) OpenWindow(command []string) Result {
+	return k.launch("os-window", command)
+}
+
+// OpenTab launches command in a new tab of the focused OS window.
+func (k *kittyAdapter) OpenTab(command []string) Result {
+	return k.launch("tab", command)
+}
+
+// OpenSplit launches command in a new kitty window beside the focused one. The
+// split is only visible side by side under a layout that tiles windows (e.g.
+// `enabled_layouts splits`); under the stack layout it stacks instead.
+func (k *kittyAdapter) OpenSplit(command []string) Result {
+	return k.launch("window", command)
+}
+
+// Supports reports true for every placement: kitty's launch types cover all
+// three.
+func (k *kittyAdapter) Supports(Placement) bool { return true }
+
+// mapKittyResult is the pure outcome mapping for a `kitty @ launch` run. A
+// clean run is Success (kitty prints the new window's id, kept as Detail). One
+// of kitty's own remote-control refusals (kittyRefusals) is PermissionRequired
+// with the driver-composed Guidance; every other non-clean outcome, including a
+// socket kitty is not listening on or a call that timed out, is SpawnFailed.
+func mapKittyResult(out string, exitCode int, err error) Result {
+	if err == nil && exitCode == 0 {
+		if trimmed := strings.TrimSpace(out); trimmed != "" {
+			return Success(trimmed)
+		}
+		return Success("kitty @ launch exit 0")
+	}
+	lower := strings.ToLower(out)
+	for _, refusal := range kittyRefusals {
+		if strings.Contains(lower, refusal) {
+			return PermissionRequired(out, kittyPermissionGuidance())
+		}
+	}
+	return SpawnFailed(execFailureDetail(out, exitCode, err, "kitty @ launch exit %d"))
+}
+
+// kittyPermissionGuidance is the user-readable guidance for kitty refusing
+// remote control. It is opaque above the driver boundary.
+func kittyPermissionGuidance() string {
+	return "kitty needs remote control to open new windows. Add " +
+		"`allow_remote_control yes` and `listen_on unix:/tmp/kitty` to kitty.conf, " +
+		"restart kitty, then try again."
+}
+
+// Compile-time assertion that *kittyAdapter satisfies the Adapter contract.
+var _ Adapter = (*kittyAdapter)(nil)
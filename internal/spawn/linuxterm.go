@@ -0,0 +1,180 @@
+package spawn
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// linuxTerminal describes one terminal emulator Portal recognises without a
+// macOS bundle: the executable names its window process runs as, the identity
+// it resolves to, and the env vars it stamps into every shell it spawns.
+//
+// The id is the terminal's macOS bundle id where it ships one (kitty, WezTerm,
+// Alacritty, Ghostty), so a terminals.json key written on one platform matches
+// on the other; Linux-only terminals use their desktop application id.
+//
+// pidKey, when set, is the env var carrying the terminal's own pid (kitty's
+// KITTY_PID), which lets the env fast-path check the marked terminal really is
+// an ancestor rather than one a shell merely inherited the marker from.
+type linuxTerminal struct {
+	executables []string
+	bundleID    string
+	name        string
+	envKeys     []string
+	pidKey      string
+}
+
+// linuxTerminals is the recognised set, in env fast-path precedence order.
+// Ghostty carries no env keys here because detectOutsideTmux checks its
+// GHOSTTY_* vars first; it is listed so the /proc walk recognises it inside
+// tmux. foot stamps no env var of its own, so only the walk finds it.
+var linuxTerminals = []linuxTerminal{
+	{executables: []string{"ghostty"}, bundleID: ghosttyBundleID, name: "Ghostty"},
+	{executables: []string{"kitty"}, bundleID: "net.kovidgoyal.kitty", name: "kitty", envKeys: []string{"KITTY_PID", "KITTY_WINDOW_ID"}, pidKey: "KITTY_PID"},
+	{executables: []string{"wezterm-gui"}, bundleID: "com.github.wez.wezterm", name: "WezTerm", envKeys: []string{"WEZTERM_PANE", "WEZTERM_EXECUTABLE"}},
+	{executables: []string{"alacritty"}, bundleID: "org.alacritty", name: "Alacritty", envKeys: []string{"ALACRITTY_SOCKET", "ALACRITTY_WINDOW_ID"}},
+	{executables: []string{"foot", "footclient"}, bundleID: "org.codeberg.dnkl.foot", name: "foot"},
+	{executables: []string{"gnome-terminal-server", "gnome-terminal"}, bundleID: "org.gnome.Terminal", name: "GNOME Terminal", envKeys: []string{"GNOME_TERMINAL_SCREEN", "GNOME_TERMINAL_SERVICE"}},
+	{executables: []string{"konsole"}, bundleID: "org.kde.konsole", name: "Konsole", envKeys: []string{"KONSOLE_VERSION", "KONSOLE_DBUS_SESSION"}},
+	{executables: []string{"xterm"}, bundleID: "net.invisible-island.xterm", name: "xterm", envKeys: []string{"XTERM_VERSION"}},
+}
+
+// linuxTerminalForExecutable resolves an executable path to the Identity of the
+// recognised terminal it runs, matching on its basename. An executable inside
+// a macOS bundle never matches here — the bundle's own Info.plist is the better
+// identity there — but a Linux install that merely lives in a ".app" directory
+// (kitty's ~/.local/kitty.app/bin/kitty) does.
+func linuxTerminalForExecutable(command string) (Identity, bool) {
+	if strings.Contains(command, appBundleSuffix+"/Contents/") {
+		return Identity{}, false
+	}
+	base := path.Base(command)
+	for _, term := range linuxTerminals {
+		for _, exe := range term.executables {
+			if base == exe {
+				return NewIdentity(term.bundleID, term.name), true
+			}
+		}
+	}
+	return Identity{}, false
+}
+
+// linuxTerminalFromEnv resolves the terminal whose env markers are set. Like
+// the GHOSTTY_* fast-path it is only trustworthy outside tmux, where the
+// environment is still the launching terminal's.
+//
+// Markers are inherited, so a terminal started from another one's shell
+// carries both sets. The fast-path therefore answers only when exactly one
+// terminal is marked and, for a terminal that stamps its pid (pidKey), that pid
+// is an ancestor of selfPID — a terminal launched from kitty's shell but
+// reparented away from it fails the check. Anything else returns ok=false so
+// the caller's walk finds the innermost terminal instead.
+func linuxTerminalFromEnv(getenv func(string) string, selfPID int, walker ProcessWalker) (Identity, bool) {
+	var marked []linuxTerminal
+	for _, term := range linuxTerminals {
+		if slices.ContainsFunc(term.envKeys, func(key string) bool { return strings.TrimSpace(getenv(key)) != "" }) {
+			marked = append(marked, term)
+		}
+	}
+	if len(marked) != 1 {
+		return Identity{}, false
+	}
+	term := marked[0]
+	if term.pidKey != "" {
+		pid, err := strconv.Atoi(strings.TrimSpace(getenv(term.pidKey)))
+		if err == nil && !isAncestor(pid, selfPID, walker) {
+			return Identity{}, false
+		}
+	}
+	return NewIdentity(term.bundleID, term.name), true
+}
+
+// isAncestor reports whether pid appears in selfPID's ancestry (selfPID itself
+// excluded), bounded by maxWalkHops like walkToBundle. A read error ends the
+// walk as not-an-ancestor.
+func isAncestor(pid, selfPID int, walker ProcessWalker) bool {
+	cur := selfPID
+	for range maxWalkHops {
+		ppid, _, err := walker.ProcessInfo(cur)
+		if err != nil || ppid <= 0 || ppid == cur {
+			return false
+		}
+		if ppid == pid {
+			return true
+		}
+		cur = ppid
+	}
+	return false
+}
+
+// procProcessWalker is the ProcessWalker for systems with a /proc filesystem.
+// `ps -o comm=` is truncated to 15 characters on Linux ("gnome-terminal-"), so
+// the walk reads /proc directly: the parent pid from <root>/<pid>/stat and the
+// executable from the <root>/<pid>/exe link. root is "/proc" in production and
+// a fabricated tree in tests.
+type procProcessWalker struct {
+	root string
+}
+
+var _ ProcessWalker = procProcessWalker{}
+
+// ProcessInfo reads pid's parent and executable. When the exe link cannot be
+// read (another user's process) it falls back to argv[0] from cmdline.
+func (w procProcessWalker) ProcessInfo(pid int) (int, string, error) {
+	dir := filepath.Join(w.root, strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return 0, "", err
+	}
+	ppid, err := parseProcStatPPID(string(stat))
+	if err != nil {
+		return 0, "", err
+	}
+
+	if exe, err := os.Readlink(filepath.Join(dir, "exe")); err == nil {
+		return ppid, strings.TrimSuffix(exe, " (deleted)"), nil
+	}
+	cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return 0, "", err
+	}
+	argv0, _, _ := strings.Cut(string(cmdline), "\x00")
+	if argv0 == "" {
+		return 0, "", fmt.Errorf("no executable for pid %d", pid)
+	}
+	return ppid, argv0, nil
+}
+
+// parseProcStatPPID extracts the parent pid from a /proc/<pid>/stat line. The
+// command name in field 2 is parenthesised and may contain spaces or ")", so
+// fields are counted from the last ')': state, then ppid.
+func parseProcStatPPID(stat string) (int, error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, errors.New("malformed stat: no command name")
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed stat %q", stat)
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("parse ppid from %q: %w", stat, err)
+	}
+	return ppid, nil
+}
+
+// newProcessWalker returns the production ProcessWalker: the /proc walker when
+// procRoot is readable, else the `ps`-backed walker macOS uses.
+func newProcessWalker(procRoot string) ProcessWalker {
+	if _, err := os.Stat(filepath.Join(procRoot, "self", "stat")); err == nil {
+		return procProcessWalker{root: procRoot}
+	}
+	return realProcessWalker{}
+}
@@ -0,0 +1,110 @@
+package spawn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLinuxTerminalForExecutable(t *testing.T) {
+	tests := []struct {
+		command  string
+		wantOK   bool
+		bundleID string
+	}{
+		{"/usr/bin/kitty", true, "net.kovidgoyal.kitty"},
+		{"/usr/bin/wezterm-gui", true, "com.github.wez.wezterm"},
+		{"/usr/bin/alacritty", true, "org.alacritty"},
+		{"/usr/bin/footclient", true, "org.codeberg.dnkl.foot"},
+		{"/usr/libexec/gnome-terminal-server", true, "org.gnome.Terminal"},
+		{"/usr/bin/konsole", true, "org.kde.konsole"},
+		{"/usr/bin/xterm", true, "net.invisible-island.xterm"},
+		{"/opt/ghostty/bin/ghostty", true, ghosttyBundleID},
+		{"/usr/bin/zsh", false, ""},
+		{"/Applications/kitty.app/Contents/MacOS/kitty", false, ""},
+	}
+	for _, tt := range tests {
+		got, ok := linuxTerminalForExecutable(tt.command)
+		if ok != tt.wantOK || got.BundleID != tt.bundleID {
+			t.Errorf("linuxTerminalForExecutable(%q) = (%+v, %v), want (%q, %v)", tt.command, got, ok, tt.bundleID, tt.wantOK)
+		}
+	}
+}
+
+func TestParseProcStatPPID(t *testing.T) {
+	ppid, err := parseProcStatPPID("4242 (tmux: client) S 4100 4242 4100 34817 4242 4194304")
+	if err != nil || ppid != 4100 {
+		t.Errorf("parseProcStatPPID = (%d, %v), want (4100, nil)", ppid, err)
+	}
+
+	for _, bad := range []string{"", "4242 (zsh)", "4242 (zsh) S x"} {
+		if _, err := parseProcStatPPID(bad); err == nil {
+			t.Errorf("parseProcStatPPID(%q) returned nil error, want a parse failure", bad)
+		}
+	}
+}
+
+// writeProc fabricates <root>/<pid> with a stat naming ppid, plus an exe link
+// to exe when it is non-empty and a cmdline holding argv.
+func writeProc(t *testing.T, root, pid, ppid, exe string, argv ...string) {
+	t.Helper()
+	dir := filepath.Join(root, pid)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stat := pid + " (proc) S " + ppid + " 1 1 0 -1 0"
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if exe != "" {
+		if err := os.Symlink(exe, filepath.Join(dir, "exe")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var cmdline []byte
+	for _, a := range argv {
+		cmdline = append(append(cmdline, a...), 0)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cmdline"), cmdline, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcProcessWalker(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, "100", "200", "/usr/bin/tmux", "tmux", "attach")
+	writeProc(t, root, "200", "300", "", "/usr/libexec/gnome-terminal-server")
+	walker := procProcessWalker{root: root}
+
+	t.Run("reads the parent and the exe link", func(t *testing.T) {
+		ppid, command, err := walker.ProcessInfo(100)
+		if err != nil || ppid != 200 || command != "/usr/bin/tmux" {
+			t.Errorf("ProcessInfo(100) = (%d, %q, %v), want (200, /usr/bin/tmux, nil)", ppid, command, err)
+		}
+	})
+
+	t.Run("falls back to argv[0] when the exe link is unreadable", func(t *testing.T) {
+		ppid, command, err := walker.ProcessInfo(200)
+		if err != nil || ppid != 300 || command != "/usr/libexec/gnome-terminal-server" {
+			t.Errorf("ProcessInfo(200) = (%d, %q, %v), want (300, gnome-terminal-server, nil)", ppid, command, err)
+		}
+	})
+
+	t.Run("fails for a vanished process", func(t *testing.T) {
+		if _, _, err := walker.ProcessInfo(999); err == nil {
+			t.Error("ProcessInfo(999) returned nil error, want a read failure")
+		}
+	})
+}
+
+func TestNewProcessWalker(t *testing.T) {
+	if _, ok := newProcessWalker(t.TempDir()).(realProcessWalker); !ok {
+		t.Error("newProcessWalker without a proc tree should fall back to ps")
+	}
+
+	root := t.TempDir()
+	writeProc(t, root, "self", "1", "/usr/bin/portal")
+	if w, ok := newProcessWalker(root).(procProcessWalker); !ok || w.root != root {
+		t.Errorf("newProcessWalker with a proc tree = %#v, want procProcessWalker{%q}", newProcessWalker(root), root)
+	}
+}
@@ -82,3 +82,12 @@ func UnsupportedNoopMessage(id Identity) string {
 	}
 	return fmt.Sprintf("can't open new windows in %s · %s — nothing opened", id.Name, id.BundleID)
 }
+
+// PlacementFallbackMessage is the single renderer for a tab/split burst
+// downgraded to windows because the host terminal's driver cannot open the
+// requested placement (EffectivePlacement returned false). Both the open
+// burst's stderr note and the picker's notice render through it; like the
+// messages above it carries no prefix or glyph.
+func PlacementFallbackMessage(id Identity, requested Placement) string {
+	return fmt.Sprintf("%s can't open %s — opening windows instead", id.Name, requested)
+}
@@ -0,0 +1,101 @@
+package spawn
+
+import "fmt"
+
+// Placement is where a burst opens each external surface: a new host window, a
+// new tab in the host's front window, or a split of the host's focused pane.
+// The zero value is treated as PlacementWindows, so a Burster built without one
+// keeps the original one-window-per-surface behaviour.
+type Placement string
+
+const (
+	// PlacementWindows opens every external surface in its own host window.
+	PlacementWindows Placement = "windows"
+	// PlacementTabs opens every external surface as a tab of the host window.
+	PlacementTabs Placement = "tabs"
+	// PlacementSplits opens every external surface as a split of the host pane.
+	PlacementSplits Placement = "splits"
+)
+
+// Placements is the cycle order the picker's placement toggle walks, and the
+// accepted values of `x --as`.
+var Placements = []Placement{PlacementWindows, PlacementTabs, PlacementSplits}
+
+// ParsePlacement maps a user-supplied `--as` value onto a Placement. It
+// rejects anything outside Placements with an error naming the valid values.
+func ParsePlacement(s string) (Placement, error) {
+	for _, p := range Placements {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid placement %q: must be one of windows, tabs, splits", s)
+}
+
+// Next returns the placement after p in the Placements cycle, wrapping from
+// splits back to windows.
+func (p Placement) Next() Placement {
+	for i, candidate := range Placements {
+		if candidate == p.orWindows() {
+			return Placements[(i+1)%len(Placements)]
+		}
+	}
+	return PlacementWindows
+}
+
+// orWindows folds the zero value onto PlacementWindows.
+func (p Placement) orWindows() Placement {
+	if p == "" {
+		return PlacementWindows
+	}
+	return p
+}
+
+// EffectivePlacement reports the placement a burst on adapter will actually
+// use for the requested p: p itself when the adapter supports it, otherwise
+// PlacementWindows. The boolean is false when the request was downgraded, so a
+// caller can tell the user the terminal only opens windows.
+func EffectivePlacement(adapter Adapter, p Placement) (Placement, bool) {
+	p = p.orWindows()
+	if p == PlacementWindows || adapter.Supports(p) {
+		return p, true
+	}
+	return PlacementWindows, false
+}
+
+// openPlaced dispatches one composed open argv to the adapter capability for p,
+// downgrading to OpenWindow when the adapter does not support p. The Result
+// taxonomy is identical across all three capabilities, so the burster's ack
+// handling never needs to know which one ran.
+func openPlaced(adapter Adapter, p Placement, command []string) Result {
+	p, _ = EffectivePlacement(adapter, p)
+	switch p {
+	case PlacementTabs:
+		return adapter.OpenTab(command)
+	case PlacementSplits:
+		return adapter.OpenSplit(command)
+	default:
+		return adapter.OpenWindow(command)
+	}
+}
+
+// WindowsOnly is an embeddable base for an Adapter whose host terminal can only
+// open new windows. Supports reports false for every placement but windows, so
+// EffectivePlacement downgrades tab and split requests before OpenTab or
+// OpenSplit could be reached; both still return SpawnFailed defensively.
+type WindowsOnly struct{}
+
+// OpenTab reports that the terminal has no tab capability.
+func (WindowsOnly) OpenTab([]string) Result {
+	return SpawnFailed("terminal cannot open tabs")
+}
+
+// OpenSplit reports that the terminal has no split capability.
+func (WindowsOnly) OpenSplit([]string) Result {
+	return SpawnFailed("terminal cannot open splits")
+}
+
+// Supports reports true for PlacementWindows only.
+func (WindowsOnly) Supports(p Placement) bool {
+	return p.orWindows() == PlacementWindows
+}
@@ -0,0 +1,150 @@
+package spawn
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// placingAdapter wraps writingAdapter with working tab and split capabilities,
+// recording which capability each surface went through. Every capability
+// writes the ack exactly like OpenWindow, so the burster's token collection can
+// be asserted identical across placements.
+type placingAdapter struct {
+	*writingAdapter
+	supported []Placement
+	used      []Placement
+}
+
+func (a *placingAdapter) OpenWindow(command []string) Result {
+	a.used = append(a.used, PlacementWindows)
+	return a.writingAdapter.OpenWindow(command)
+}
+
+func (a *placingAdapter) OpenTab(command []string) Result {
+	a.used = append(a.used, PlacementTabs)
+	return a.writingAdapter.OpenWindow(command)
+}
+
+func (a *placingAdapter) OpenSplit(command []string) Result {
+	a.used = append(a.used, PlacementSplits)
+	return a.writingAdapter.OpenWindow(command)
+}
+
+func (a *placingAdapter) Supports(p Placement) bool {
+	return p.orWindows() == PlacementWindows || slices.Contains(a.supported, p)
+}
+
+func TestParsePlacement(t *testing.T) {
+	for _, p := range Placements {
+		if got, err := ParsePlacement(string(p)); err != nil || got != p {
+			t.Errorf("ParsePlacement(%q) = (%q, %v), want (%q, nil)", p, got, err, p)
+		}
+	}
+	for _, bad := range []string{"", "tab", "Windows", "panes"} {
+		if _, err := ParsePlacement(bad); err == nil {
+			t.Errorf("ParsePlacement(%q) returned nil error, want a rejection", bad)
+		}
+	}
+}
+
+func TestPlacementNext(t *testing.T) {
+	tests := []struct {
+		from, want Placement
+	}{
+		{"", PlacementTabs},
+		{PlacementWindows, PlacementTabs},
+		{PlacementTabs, PlacementSplits},
+		{PlacementSplits, PlacementWindows},
+	}
+	for _, tt := range tests {
+		if got := tt.from.Next(); got != tt.want {
+			t.Errorf("%q.Next() = %q, want %q", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestEffectivePlacement(t *testing.T) {
+	windowsOnly := &writingAdapter{}
+	tabsOnly := &placingAdapter{writingAdapter: &writingAdapter{}, supported: []Placement{PlacementTabs}}
+
+	tests := []struct {
+		name      string
+		adapter   Adapter
+		requested Placement
+		want      Placement
+		wantOK    bool
+	}{
+		{"zero value is windows", windowsOnly, "", PlacementWindows, true},
+		{"windows is always supported", windowsOnly, PlacementWindows, PlacementWindows, true},
+		{"an unsupported tab downgrades", windowsOnly, PlacementTabs, PlacementWindows, false},
+		{"a supported tab is kept", tabsOnly, PlacementTabs, PlacementTabs, true},
+		{"an unsupported split downgrades", tabsOnly, PlacementSplits, PlacementWindows, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := EffectivePlacement(tt.adapter, tt.requested)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("EffectivePlacement = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBurster_RunPlacement(t *testing.T) {
+	newBurster := func(adapter Adapter, ack *delayingAck, clock *manualClock, p Placement) *Burster {
+		return &Burster{
+			Adapter: adapter, Placement: p, Ack: ack, Exe: fixedExe(testBurstExe),
+			Getenv:  mapGetenv(map[string]string{"PATH": testBurstPath}),
+			NewID:   seqIDGen(),
+			Timeout: 8 * time.Second, Poll: 75 * time.Millisecond,
+			Now: clock.now, Sleep: clock.sleep,
+		}
+	}
+
+	for _, p := range []Placement{PlacementTabs, PlacementSplits} {
+		t.Run("it opens every surface as "+string(p)+" and confirms each token", func(t *testing.T) {
+			clock := &manualClock{}
+			ack := newDelayingAck(clock.now, 0)
+			adapter := &placingAdapter{writingAdapter: &writingAdapter{ack: ack}, supported: Placements}
+
+			_, results, err := newBurster(adapter, ack, clock, p).Run(context.Background(), AttachSurfaces([]string{"s1", "s2"}), nil, nil)
+			if err != nil {
+				t.Fatalf("Run error = %v, want nil", err)
+			}
+			if !slices.Equal(adapter.used, []Placement{p, p}) {
+				t.Errorf("capabilities used = %v, want %v for both surfaces", adapter.used, p)
+			}
+			for i, r := range results {
+				if r.Ack != AckConfirmed {
+					t.Errorf("results[%d].Ack = %q, want %q", i, r.Ack, AckConfirmed)
+				}
+			}
+		})
+	}
+
+	t.Run("it falls back to windows when the adapter cannot open the placement", func(t *testing.T) {
+		clock := &manualClock{}
+		ack := newDelayingAck(clock.now, 0)
+		adapter := &placingAdapter{writingAdapter: &writingAdapter{ack: ack}}
+
+		_, results, err := newBurster(adapter, ack, clock, PlacementSplits).Run(context.Background(), AttachSurfaces([]string{"s1"}), nil, nil)
+		if err != nil {
+			t.Fatalf("Run error = %v, want nil", err)
+		}
+		if !slices.Equal(adapter.used, []Placement{PlacementWindows}) || results[0].Ack != AckConfirmed {
+			t.Errorf("used = %v, ack = %q, want one confirmed window", adapter.used, results[0].Ack)
+		}
+	})
+}
+
+func TestWindowsOnly(t *testing.T) {
+	var w WindowsOnly
+	if !w.Supports("") || !w.Supports(PlacementWindows) || w.Supports(PlacementTabs) || w.Supports(PlacementSplits) {
+		t.Error("WindowsOnly must support windows and nothing else")
+	}
+	if w.OpenTab(nil).Outcome != OutcomeSpawnFailed || w.OpenSplit(nil).Outcome != OutcomeSpawnFailed {
+		t.Error("WindowsOnly OpenTab/OpenSplit must report spawn-failed")
+	}
+}
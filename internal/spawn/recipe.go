@@ -86,6 +86,23 @@ func validRecipeForEntry(key string, e TerminalEntry) (Recipe, RecipeKind, bool)
 	return *e.Commands.Open, kind, true
 }
 
+// validPlacementRecipe validates an entry's optional `open_tab` / `open_split`
+// recipe (capability names which). An absent recipe is ok=false with no WARN —
+// the entry simply cannot open that placement. A configured-but-invalid one
+// WARNs once naming the entry key and capability, then ok=false; the entry's
+// `open` recipe is unaffected, so the terminal still opens windows.
+func validPlacementRecipe(key, capability string, r *Recipe) (Recipe, RecipeKind, bool) {
+	if r == nil {
+		return Recipe{}, 0, false
+	}
+	kind, err := validateRecipe(*r)
+	if err != nil {
+		spawnLogger.Warn("terminals.json entry rejected", "detail", fmt.Sprintf("%q %s: %v", key, capability, err))
+		return Recipe{}, 0, false
+	}
+	return *r, kind, true
+}
+
 // shellQuote wraps s in POSIX single quotes so it survives as a single word when
 // the rendered {command} string is later word-split by a shell — Ghostty's
 // `bash -c` on the native path, or a shell-based terminal recipe / the delivered
@@ -0,0 +1,47 @@
+package spawn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// remoteControlRunner is the 1-method DI seam over a terminal's own
+// remote-control CLI (`kitty @`, `wezterm cli`), so those drivers' exec
+// boundary and outcome mapping are unit-testable with a fabricated outcome and
+// no real terminal. Its (out, exitCode, err) contract is osascriptRunner's: out
+// is the combined stdout+stderr, exitCode the process exit status, and err a
+// non-exit execution error such as the CLI missing from PATH.
+//
+// It is kept apart from osascriptRunner for the same reason recipeRunner is:
+// the seams name different boundaries even though the exec plumbing behind
+// them (runArgvCombined) is shared.
+type remoteControlRunner interface {
+	Run(argv []string) (out string, exitCode int, err error)
+}
+
+// execRemoteControlRunner is the production remoteControlRunner. The real CLI
+// boundary is manual only — no automated test drives a live kitty or WezTerm.
+// A non-zero timeout kills the CLI once it elapses; zero waits indefinitely.
+type execRemoteControlRunner struct {
+	timeout time.Duration
+}
+
+var _ remoteControlRunner = execRemoteControlRunner{}
+
+// Run execs argv through the shared exec boundary (runArgvCombinedContext),
+// bounded by the runner's timeout. A run the timeout killed surfaces as an
+// execution error naming the timeout rather than as a signal exit.
+func (r execRemoteControlRunner) Run(argv []string) (string, int, error) {
+	if r.timeout <= 0 {
+		return runArgvCombined(argv)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	out, code, err := runArgvCombinedContext(ctx, argv)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return out, 0, fmt.Errorf("%s timed out after %s", argv[0], r.timeout)
+	}
+	return out, code, err
+}
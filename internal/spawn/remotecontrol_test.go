@@ -0,0 +1,240 @@
+package spawn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRemoteControlRunner is a test double for remoteControlRunner: it records
+// the argv it is handed and returns a fabricated outcome, standing in for a
+// live kitty or WezTerm.
+type fakeRemoteControlRunner struct {
+	gotArgv  []string
+	out      string
+	exitCode int
+	err      error
+}
+
+func (f *fakeRemoteControlRunner) Run(argv []string) (string, int, error) {
+	f.gotArgv = append([]string(nil), argv...)
+	return f.out, f.exitCode, f.err
+}
+
+func TestKittyOpenWindow(t *testing.T) {
+	t.Run("it launches an OS window over the advertised socket", func(t *testing.T) {
+		cmd := realAttachArgv()
+		fake := &fakeRemoteControlRunner{out: "42\n"}
+		adapter := &kittyAdapter{runner: fake, listenOn: "unix:/tmp/kitty-1"}
+
+		result := adapter.OpenWindow(cmd)
+
+		want := append([]string{"kitty", "@", "--to", "unix:/tmp/kitty-1", "launch", "--type=os-window", "--"}, wrapWithShellFallback(cmd)...)
+		if !slices.Equal(fake.gotArgv, want) {
+			t.Errorf("runner received argv %#v, want %#v", fake.gotArgv, want)
+		}
+		if result.Outcome != OutcomeSuccess || result.Detail != "42" {
+			t.Errorf("result = %+v, want success carrying the window id", result)
+		}
+	})
+
+	t.Run("it omits --to when kitty advertised no socket", func(t *testing.T) {
+		fake := &fakeRemoteControlRunner{}
+		(&kittyAdapter{runner: fake}).OpenWindow(realAttachArgv())
+
+		if slices.Contains(fake.gotArgv, "--to") {
+			t.Errorf("argv %#v carries --to without a socket", fake.gotArgv)
+		}
+	})
+}
+
+func TestKittyOpenTabAndSplit(t *testing.T) {
+	cmd := realAttachArgv()
+	tests := []struct {
+		name   string
+		open   func(*kittyAdapter, []string) Result
+		launch []string
+	}{
+		{"tab", (*kittyAdapter).OpenTab, []string{"--type=tab"}},
+		{"split", (*kittyAdapter).OpenSplit, []string{"--type=window", "--location=vsplit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRemoteControlRunner{out: "9\n"}
+			adapter := &kittyAdapter{runner: fake}
+
+			result := tt.open(adapter, cmd)
+
+			want := append(append([]string{"kitty", "@", "launch"}, tt.launch...), "--")
+			want = append(want, wrapWithShellFallback(cmd)...)
+			if !slices.Equal(fake.gotArgv, want) {
+				t.Errorf("runner received argv %#v, want %#v", fake.gotArgv, want)
+			}
+			if !result.OK() {
+				t.Errorf("result = %+v, want success", result)
+			}
+		})
+	}
+
+	for _, p := range Placements {
+		if !(&kittyAdapter{}).Supports(p) {
+			t.Errorf("kitty Supports(%q) = false, want true", p)
+		}
+	}
+}
+
+func TestMapKittyResult(t *testing.T) {
+	t.Run("it maps disabled remote control to permission-required", func(t *testing.T) {
+		const body = "Error: Remote control is disabled. Add allow_remote_control to your kitty.conf"
+		result := mapKittyResult(body, 1, nil)
+
+		if result.Outcome != OutcomePermissionRequired {
+			t.Fatalf("Outcome = %v, want OutcomePermissionRequired", result.Outcome)
+		}
+		if result.Detail != body || !strings.Contains(result.Guidance, "allow_remote_control") {
+			t.Errorf("result = %+v, want the opaque body plus kitty.conf guidance", result)
+		}
+	})
+
+	t.Run("it maps a password refusal to permission-required", func(t *testing.T) {
+		result := mapKittyResult("Error: This request is not allowed by the remote control password", 1, nil)
+
+		if result.Outcome != OutcomePermissionRequired {
+			t.Errorf("Outcome = %v, want OutcomePermissionRequired", result.Outcome)
+		}
+	})
+
+	t.Run("it maps an unrelated permission error to spawn-failed", func(t *testing.T) {
+		result := mapKittyResult("Error: [Errno 13] Permission denied: '/tmp/kitty-1'", 1, nil)
+
+		if result.Outcome != OutcomeSpawnFailed {
+			t.Errorf("Outcome = %v, want OutcomeSpawnFailed", result.Outcome)
+		}
+	})
+
+	t.Run("it maps an unreachable socket to spawn-failed", func(t *testing.T) {
+		result := mapKittyResult("Error: Failed to connect to unix:/tmp/kitty-1", 1, nil)
+
+		if result.Outcome != OutcomeSpawnFailed || !strings.Contains(result.Detail, "Failed to connect") {
+			t.Errorf("result = %+v, want spawn-failed with the output as detail", result)
+		}
+	})
+
+	t.Run("it maps a missing kitty binary to spawn-failed", func(t *testing.T) {
+		result := mapKittyResult("", 0, errors.New(`exec: "kitty": executable file not found in $PATH`))
+
+		if result.Outcome != OutcomeSpawnFailed || !strings.Contains(result.Detail, "not found") {
+			t.Errorf("result = %+v, want spawn-failed naming the exec error", result)
+		}
+	})
+
+	t.Run("it never leaves a success detail empty", func(t *testing.T) {
+		if result := mapKittyResult("  ", 0, nil); !result.OK() || result.Detail == "" {
+			t.Errorf("result = %+v, want success with a non-empty detail", result)
+		}
+	})
+}
+
+func TestExecRemoteControlRunner_Timeout(t *testing.T) {
+	runner := execRemoteControlRunner{timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, _, err := runner.Run([]string{"sleep", "5"})
+
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want a timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %v, want it killed at the timeout", elapsed)
+	}
+}
+
+func TestWeztermAdapter_Timeout(t *testing.T) {
+	if got := newWeztermAdapter().runner; got != (execRemoteControlRunner{timeout: weztermRemoteTimeout}) {
+		t.Errorf("newWeztermAdapter runner = %#v, want one bounded by weztermRemoteTimeout", got)
+	}
+
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "wezterm"), []byte("#!/bin/sh\nexec sleep 5\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	start := time.Now()
+	result := (&weztermAdapter{runner: execRemoteControlRunner{timeout: 50 * time.Millisecond}}).OpenWindow(realAttachArgv())
+
+	if result.Outcome != OutcomeSpawnFailed || !strings.Contains(result.Detail, "timed out") {
+		t.Errorf("result = %+v, want a spawn failure naming the timeout", result)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("OpenWindow took %v, want the hung CLI killed at the timeout", elapsed)
+	}
+}
+
+func TestWeztermOpenWindow(t *testing.T) {
+	cmd := realAttachArgv()
+	fake := &fakeRemoteControlRunner{out: "7\n"}
+
+	result := (&weztermAdapter{runner: fake}).OpenWindow(cmd)
+
+	want := append([]string{"wezterm", "cli", "spawn", "--new-window", "--"}, wrapWithShellFallback(cmd)...)
+	if !slices.Equal(fake.gotArgv, want) {
+		t.Errorf("runner received argv %#v, want %#v", fake.gotArgv, want)
+	}
+	if result.Outcome != OutcomeSuccess || result.Detail != "7" {
+		t.Errorf("result = %+v, want success carrying the pane id", result)
+	}
+}
+
+func TestWeztermOpenTabAndSplit(t *testing.T) {
+	cmd := realAttachArgv()
+	tests := []struct {
+		name string
+		open func(*weztermAdapter, []string) Result
+		head []string
+	}{
+		{"tab", (*weztermAdapter).OpenTab, []string{"wezterm", "cli", "spawn", "--"}},
+		{"split", (*weztermAdapter).OpenSplit, []string{"wezterm", "cli", "split-pane", "--right", "--"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRemoteControlRunner{out: "3\n"}
+
+			tt.open(&weztermAdapter{runner: fake}, cmd)
+
+			want := append(tt.head, wrapWithShellFallback(cmd)...)
+			if !slices.Equal(fake.gotArgv, want) {
+				t.Errorf("runner received argv %#v, want %#v", fake.gotArgv, want)
+			}
+		})
+	}
+}
+
+func TestMapWeztermResult(t *testing.T) {
+	t.Run("it maps a refused mux socket to permission-required", func(t *testing.T) {
+		const body = "failed to connect to Socket(\"/run/user/1000/wezterm/gui-sock-1\"): Permission denied (os error 13)"
+		result := mapWeztermResult(body, 1, nil)
+
+		if result.Outcome != OutcomePermissionRequired || result.Guidance == "" {
+			t.Errorf("result = %+v, want permission-required with guidance", result)
+		}
+	})
+
+	t.Run("it maps no running GUI to spawn-failed", func(t *testing.T) {
+		result := mapWeztermResult("Error: failed to connect: No such file or directory (os error 2)", 1, nil)
+
+		if result.Outcome != OutcomeSpawnFailed {
+			t.Errorf("Outcome = %v, want OutcomeSpawnFailed", result.Outcome)
+		}
+	})
+
+	t.Run("it labels a silent failure with the exit code", func(t *testing.T) {
+		if result := mapWeztermResult("", 3, nil); result.Detail != "wezterm cli spawn exit 3" {
+			t.Errorf("Detail = %q, want the exit-code label", result.Detail)
+		}
+	})
+}
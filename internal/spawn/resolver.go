@@ -30,20 +30,33 @@ type AdapterResolver func(Identity) (Adapter, Resolution)
 
 // nativeAdapter is one entry in the ordered native-adapter registry: a bundle-id
 // family glob and the constructor that builds its driver on a family hit.
+// available, when set, gates the entry on the host actually being drivable; an
+// entry whose check fails is skipped as if its family never matched.
 type nativeAdapter struct {
-	family string
-	build  func() Adapter
+	family    string
+	build     func() Adapter
+	available func() bool
 }
 
 // nativeAdapters is the ordered native-adapter registry, consulted in order
-// after any config override. Phase 2 ships exactly one entry: Ghostty, keyed by
-// the family glob so both com.mitchellh.ghostty and any channel-suffixed variant
-// resolve.
+// after any config override. Ghostty is keyed by the family glob so both
+// com.mitchellh.ghostty and any channel-suffixed variant resolve; kitty and
+// WezTerm by their one id, which is the same whether it came from a macOS
+// bundle or Linux detection (see linuxTerminals).
 var nativeAdapters = []nativeAdapter{
 	{
 		family: "com.mitchellh.ghostty*",
 		build:  func() Adapter { return newGhosttyAdapter() },
 	},
+	{
+		family:    "net.kovidgoyal.kitty",
+		build:     func() Adapter { return newKittyAdapter() },
+		available: kittySocketAdvertised,
+	},
+	{
+		family: "com.github.wez.wezterm",
+		build:  func() Adapter { return newWeztermAdapter() },
+	},
 }
 
 // Resolver maps a host-terminal Identity to the Adapter that opens windows for
@@ -79,7 +92,8 @@ func NewResolver(cfg TerminalsConfig) *Resolver {
 //     config entry must not hijack a NULL identity into a config adapter.
 //  2. The config tier (terminals.json) is tried first: its single most-specific
 //     matching entry, when it holds a valid recipe, builds a config adapter.
-//  3. The native registry is tried next, matched by bundle-id family.
+//  3. The native registry is tried next, matched by bundle-id family (and the
+//     entry's availability check, e.g. kitty's remote-control socket).
 //  4. Everything else — a known-but-undriven terminal, any passthrough/unknown
 //     identity — resolves to (nil, ResolutionUnsupported).
 //
@@ -98,7 +112,7 @@ func (r *Resolver) Resolve(id Identity) (Adapter, Resolution) {
 	}
 
 	for _, entry := range nativeAdapters {
-		if MatchesFamily(id.BundleID, entry.family) {
+		if MatchesFamily(id.BundleID, entry.family) && (entry.available == nil || entry.available()) {
 			return entry.build(), ResolutionNative
 		}
 	}
@@ -108,10 +122,12 @@ func (r *Resolver) Resolve(id Identity) (Adapter, Resolution) {
 
 // resolveConfig tries the terminals.json config tier for id: it matches the
 // single most-specific entry, validates its recipe, and builds the matching
-// argv/script adapter. It returns ok=false — so Resolve falls through to native —
+// argv/script adapter, carrying whichever `open_tab` / `open_split` recipes the
+// entry also declares. It returns ok=false — so Resolve falls through to native —
 // on no match, an entry with no `open` capability, a structurally-invalid recipe
 // (Task 4.2 already emitted the WARN), or a missing/non-exec script (Task 4.5's
-// constructor emits the WARN and returns false).
+// constructor emits the WARN and returns false). A broken placement recipe never
+// fails the entry: it is dropped and that placement downgrades to windows.
 func (r *Resolver) resolveConfig(id Identity) (Adapter, bool) {
 	key, entry, ok := matchConfig(r.Config, id)
 	if !ok {
@@ -125,14 +141,51 @@ func (r *Resolver) resolveConfig(id Identity) (Adapter, bool) {
 
 	switch kind {
 	case RecipeArgv:
-		return &argvRecipeAdapter{template: recipe.Argv, runner: r.runner}, true
+		return &argvRecipeAdapter{
+			recipePlacements: r.placementsForEntry(key, entry),
+			template:         recipe.Argv,
+			runner:           r.runner,
+		}, true
 	case RecipeScript:
-		return newScriptRecipeAdapter(key, recipe.Script, r.runner)
+		adapter, ok := newScriptRecipeAdapter(key, recipe.Script, r.runner)
+		if !ok {
+			return nil, false
+		}
+		adapter.recipePlacements = r.placementsForEntry(key, entry)
+		return adapter, true
 	default:
 		return nil, false
 	}
 }
 
+// placementsForEntry builds the tab and split recipe adapters for a matched
+// entry whose `open` recipe is already valid.
+func (r *Resolver) placementsForEntry(key string, entry TerminalEntry) recipePlacements {
+	return recipePlacements{
+		tab:   r.placementAdapter(key, "open_tab", entry.Commands.OpenTab),
+		split: r.placementAdapter(key, "open_split", entry.Commands.OpenSplit),
+	}
+}
+
+// placementAdapter builds the single-recipe adapter whose OpenWindow runs the
+// given placement recipe, or nil when the recipe is absent or invalid (both
+// validPlacementRecipe and the script gate emit their own WARN).
+func (r *Resolver) placementAdapter(key, capability string, raw *Recipe) Adapter {
+	recipe, kind, ok := validPlacementRecipe(key, capability, raw)
+	if !ok {
+		return nil
+	}
+	switch kind {
+	case RecipeArgv:
+		return &argvRecipeAdapter{template: recipe.Argv, runner: r.runner}
+	case RecipeScript:
+		if adapter, ok := newScriptRecipeAdapter(key, recipe.Script, r.runner); ok {
+			return adapter
+		}
+	}
+	return nil
+}
+
 // ResolveAdapter is a thin zero-config wrapper over Resolver.Resolve, preserving
 // the Phase 1/2 free-function entry point: an empty config means resolveConfig
 // never matches, so behaviour reduces to native → unsupported.
@@ -165,3 +165,68 @@ func TestResolverResolve(t *testing.T) {
 		}
 	})
 }
+
+func TestResolverResolve_PlacementRecipes(t *testing.T) {
+	id := NewIdentity("org.example.term", "Term")
+
+	t.Run("it routes open_tab and open_split through their own recipes", func(t *testing.T) {
+		entry := argvOpenEntry("term", "--window", "{command}")
+		entry.Commands.OpenTab = &Recipe{Argv: []string{"term", "--tab", "{command}"}}
+		entry.Commands.OpenSplit = &Recipe{Argv: []string{"term", "--split", "{command}"}}
+		r, fake := newTestResolver(TerminalsConfig{"org.example.term": entry})
+
+		adapter, _ := r.Resolve(id)
+
+		for _, p := range Placements {
+			if !adapter.Supports(p) {
+				t.Errorf("Supports(%q) = false, want true", p)
+			}
+		}
+		adapter.OpenTab([]string{"portal"})
+		if !slices.Equal(fake.gotArgv, []string{"term", "--tab", "'portal'"}) {
+			t.Errorf("OpenTab ran %#v, want the open_tab recipe", fake.gotArgv)
+		}
+		adapter.OpenSplit([]string{"portal"})
+		if !slices.Equal(fake.gotArgv, []string{"term", "--split", "'portal'"}) {
+			t.Errorf("OpenSplit ran %#v, want the open_split recipe", fake.gotArgv)
+		}
+	})
+
+	t.Run("it keeps the entry but drops an invalid placement recipe with a WARN", func(t *testing.T) {
+		sink := installSpawnCapture(t)
+		entry := argvOpenEntry("term", "{command}")
+		entry.Commands.OpenTab = &Recipe{Argv: []string{"term", "--tab"}} // no {command}
+		r, _ := newTestResolver(TerminalsConfig{"org.example.term": entry})
+
+		adapter, resolution := r.Resolve(id)
+
+		if resolution != ResolutionConfig {
+			t.Fatalf("resolution = %q, want %q", resolution, ResolutionConfig)
+		}
+		if adapter.Supports(PlacementTabs) || adapter.Supports(PlacementSplits) {
+			t.Error("an entry without valid placement recipes must support windows only")
+		}
+		if got := len(warnRecords(sink)); got != 1 {
+			t.Errorf("emitted %d WARN records, want exactly 1 for the bad open_tab", got)
+		}
+	})
+
+	t.Run("it attaches placement recipes to a script entry", func(t *testing.T) {
+		script := filepath.Join(t.TempDir(), "open.sh")
+		writeExecutableScript(t, script)
+		entry := TerminalEntry{Commands: Capabilities{
+			Open:      &Recipe{Script: script},
+			OpenSplit: &Recipe{Script: script},
+		}}
+		r, _ := newTestResolver(TerminalsConfig{"org.example.term": entry})
+
+		adapter, _ := r.Resolve(id)
+
+		if _, ok := adapter.(*scriptRecipeAdapter); !ok {
+			t.Fatalf("adapter = %T, want *scriptRecipeAdapter", adapter)
+		}
+		if !adapter.Supports(PlacementSplits) || adapter.Supports(PlacementTabs) {
+			t.Error("script entry should support splits (declared) but not tabs")
+		}
+	})
+}
@@ -74,3 +74,28 @@ func TestResolveAdapter(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveAdapter_RemoteControlTerminals(t *testing.T) {
+	t.Run("kitty resolves to the native kitty adapter", func(t *testing.T) {
+		t.Setenv("KITTY_LISTEN_ON", "unix:/tmp/kitty-1")
+		adapter, resolution := ResolveAdapter(NewIdentity("net.kovidgoyal.kitty", "kitty"))
+		if _, ok := adapter.(*kittyAdapter); !ok || resolution != ResolutionNative {
+			t.Errorf("ResolveAdapter(kitty) = (%T, %q), want (*kittyAdapter, native)", adapter, resolution)
+		}
+	})
+
+	t.Run("kitty without an advertised socket is unsupported", func(t *testing.T) {
+		t.Setenv("KITTY_LISTEN_ON", "")
+		adapter, resolution := ResolveAdapter(NewIdentity("net.kovidgoyal.kitty", "kitty"))
+		if adapter != nil || resolution != ResolutionUnsupported {
+			t.Errorf("ResolveAdapter(kitty) = (%T, %q), want (nil, unsupported)", adapter, resolution)
+		}
+	})
+
+	t.Run("WezTerm resolves to the native WezTerm adapter", func(t *testing.T) {
+		adapter, resolution := ResolveAdapter(NewIdentity("com.github.wez.wezterm", "WezTerm"))
+		if _, ok := adapter.(*weztermAdapter); !ok || resolution != ResolutionNative {
+			t.Errorf("ResolveAdapter(WezTerm) = (%T, %q), want (*weztermAdapter, native)", adapter, resolution)
+		}
+	})
+}
@@ -16,11 +16,15 @@ type Recipe struct {
 
 // Capabilities is the set of command capabilities a terminal entry declares.
 // Open is a pointer so an absent `open` sub-key decodes to nil — distinguishable
-// from a present-but-empty recipe. Future capabilities (introspect / place) are
+// from a present-but-empty recipe. OpenTab and OpenSplit are the optional
+// placement recipes (`open_tab` / `open_split`) a tab or split burst uses; an
+// entry without them still opens windows. Future capabilities (introspect) are
 // deliberately NOT fields: encoding/json drops unmodeled keys, giving
 // forward-compat for free without DisallowUnknownFields.
 type Capabilities struct {
-	Open *Recipe `json:"open"`
+	Open      *Recipe `json:"open"`
+	OpenTab   *Recipe `json:"open_tab"`
+	OpenSplit *Recipe `json:"open_split"`
 }
 
 // TerminalEntry is one terminal's config record: its command capabilities.
@@ -47,11 +47,13 @@ const maxWalkHops = 32
 const appBundleSuffix = ".app"
 
 // walkToBundle walks the process tree upward from startPID until it reaches a
-// macOS `.app` bundle, then resolves that bundle's identity. It has a three-shape
-// return contract:
+// macOS `.app` bundle, then resolves that bundle's identity. An ancestor running
+// a recognised Linux terminal executable (see linuxTerminals) ends the walk too,
+// resolving to that terminal without a bundle read. It has a three-shape return
+// contract:
 //
 //   - resolved Identity, nil error: an ancestor's command lives inside a `.app`
-//     bundle whose Info.plist read succeeded.
+//     bundle whose Info.plist read succeeded, or is a recognised terminal.
 //   - NULL Identity (Identity{}), nil error: the ancestry exhausted at ppid <= 1
 //     (or a repeated pid, or the hop bound) without ever reaching a `.app` — the
 //     honest "no host-local terminal" outcome (a remote/mosh client walks here).
@@ -73,6 +75,10 @@ func walkToBundle(startPID int, walker ProcessWalker, reader BundleReader) (Iden
 			return Identity{}, transient(fmt.Sprintf("read process info for pid %d", pid), err)
 		}
 
+		if id, ok := linuxTerminalForExecutable(command); ok {
+			return id, nil
+		}
+
 		if appPath, ok := appBundlePath(command); ok {
 			bundleID, name, rerr := reader.Read(appPath)
 			if rerr != nil {
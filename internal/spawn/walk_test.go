@@ -93,6 +93,24 @@ func TestWalkToBundle(t *testing.T) {
 		}
 	})
 
+	t.Run("it resolves a Linux terminal executable without reading a bundle", func(t *testing.T) {
+		for _, exe := range []string{"/usr/bin/gnome-terminal-server", "/home/me/.local/kitty.app/bin/kitty"} {
+			walker := &fakeWalker{procs: map[int]fakeProc{
+				100: {ppid: 200, command: "/usr/bin/tmux"},
+				200: {ppid: 300, command: "/usr/bin/bash"},
+				300: {ppid: 1, command: exe},
+			}}
+
+			got, err := walkToBundle(100, walker, failReader{t})
+			if err != nil {
+				t.Fatalf("walkToBundle(%s) returned error: %v, want nil", exe, err)
+			}
+			if got.IsNull() {
+				t.Errorf("walkToBundle(%s) returned NULL identity, want the terminal", exe)
+			}
+		}
+	})
+
 	t.Run("it returns clean NULL when ancestry reaches ppid 1 with no app bundle", func(t *testing.T) {
 		walker := &fakeWalker{procs: map[int]fakeProc{
 			100: {ppid: 200, command: "portal"},
@@ -0,0 +1,98 @@
+package spawn
+
+import (
+	"strings"
+	"time"
+)
+
+// weztermRemoteTimeout bounds one `wezterm cli` call. A mux server that stops
+// answering would otherwise hang the burst that is waiting on it.
+const weztermRemoteTimeout = 5 * time.Second
+
+// weztermAdapter is the native window-spawning driver for WezTerm. It opens a
+// window through WezTerm's mux CLI (`wezterm cli spawn --new-window`), which
+// finds the running GUI through WEZTERM_UNIX_SOCKET or WezTerm's own socket
+// discovery — so unlike kitty it needs no configuration, inside tmux or out.
+type weztermAdapter struct {
+	runner remoteControlRunner
+}
+
+// newWeztermAdapter builds the native WezTerm adapter wired with the real
+// runner, bounded by weztermRemoteTimeout. It runs nothing itself.
+func newWeztermAdapter() *weztermAdapter {
+	return &weztermAdapter{runner: execRemoteControlRunner{timeout: weztermRemoteTimeout}}
+}
+
+// weztermOpenArgv builds the `wezterm cli` argv that opens command in a new
+// surface for placement p — `spawn --new-window` for a window, plain `spawn`
+// (a tab in the current window) for a tab, or `split-pane --right` for a split —
+// wrapped in the shared shell fallback so the surface survives the session's
+// exec chain ending.
+func weztermOpenArgv(p Placement, command []string) []string {
+	var argv []string
+	switch p {
+	case PlacementTabs:
+		argv = []string{"wezterm", "cli", "spawn", "--"}
+	case PlacementSplits:
+		argv = []string{"wezterm", "cli", "split-pane", "--right", "--"}
+	default:
+		argv = []string{"wezterm", "cli", "spawn", "--new-window", "--"}
+	}
+	return append(argv, wrapWithShellFallback(command)...)
+}
+
+// open runs the placement's argv through the runner seam and maps the outcome
+// to a generic typed Result (mapWeztermResult).
+func (w *weztermAdapter) open(p Placement, command []string) Result {
+	out, code, err := w.runner.Run(weztermOpenArgv(p, command))
+	return mapWeztermResult(out, code, err)
+}
+
+// OpenWindow opens command in a new WezTerm window.
+func (w *weztermAdapter) OpenWindow(command []string) Result {
+	return w.open(PlacementWindows, command)
+}
+
+// OpenTab opens command in a new tab of the current WezTerm window.
+func (w *weztermAdapter) OpenTab(command []string) Result {
+	return w.open(PlacementTabs, command)
+}
+
+// OpenSplit splits the current WezTerm pane to the right and runs command in
+// the new half.
+func (w *weztermAdapter) OpenSplit(command []string) Result {
+	return w.open(PlacementSplits, command)
+}
+
+// Supports reports true for every placement: the mux CLI covers all three.
+func (w *weztermAdapter) Supports(Placement) bool { return true }
+
+// mapWeztermResult is the pure outcome mapping for a `wezterm cli spawn` or
+// `split-pane` run. A clean run is Success (WezTerm prints the new pane's id, kept as Detail).
+// WezTerm has no remote-control switch of its own, so the only permission wall
+// is the OS refusing the mux socket ("Permission denied"), which maps to
+// PermissionRequired; every other non-clean outcome — no running GUI, the CLI
+// missing from PATH — is SpawnFailed.
+func mapWeztermResult(out string, exitCode int, err error) Result {
+	if err == nil && exitCode == 0 {
+		if trimmed := strings.TrimSpace(out); trimmed != "" {
+			return Success(trimmed)
+		}
+		return Success("wezterm cli spawn exit 0")
+	}
+	if strings.Contains(strings.ToLower(out), "permission denied") {
+		return PermissionRequired(out, weztermPermissionGuidance())
+	}
+	return SpawnFailed(execFailureDetail(out, exitCode, err, "wezterm cli spawn exit %d"))
+}
+
+// weztermPermissionGuidance is the user-readable guidance for WezTerm's mux
+// socket refusing the connection. It is opaque above the driver boundary.
+func weztermPermissionGuidance() string {
+	return "WezTerm refused the connection to its mux socket. Check that " +
+		"WEZTERM_UNIX_SOCKET (or ~/.local/share/wezterm) belongs to your user, " +
+		"then try again."
+}
+
+// Compile-time assertion that *weztermAdapter satisfies the Adapter contract.
+var _ Adapter = (*weztermAdapter)(nil)
@@ -19,8 +19,10 @@ import (
 	"github.com/leeovery/portal/internal/spawn"
 )
 
-// FakeAdapter is a test double for spawn.Adapter. It records every OpenWindow
-// argv in Calls (in call order) and replays scripted Results.
+// FakeAdapter is a test double for spawn.Adapter. It records every OpenWindow,
+// OpenTab, and OpenSplit argv in Calls (in call order, with the capability each
+// went through in Placements) and replays scripted Results. Every placement is
+// supported unless WindowsOnly is set.
 //
 // Set Results to script per-call outcomes: call i returns Results[i], and once
 // Results is exhausted (or empty) every further call defaults to
@@ -49,6 +51,13 @@ type FakeAdapter struct {
 	// Confirm gates the marker write per window: Confirm[i] false suppresses
 	// window i's write (→ ack timeout). A nil slice confirms every window.
 	Confirm []bool
+	// Placements records which capability each call went through, parallel to
+	// Calls: PlacementWindows for OpenWindow, PlacementTabs for OpenTab, and
+	// PlacementSplits for OpenSplit.
+	Placements []spawn.Placement
+	// WindowsOnly makes Supports report false for tabs and splits, standing in
+	// for a driver such as Ghostty that can only open windows.
+	WindowsOnly bool
 
 	mu sync.Mutex
 }
@@ -58,10 +67,32 @@ type FakeAdapter struct {
 // exhausted or empty), and — on a confirmed success — writes the argv's parsed
 // token to Ack. It satisfies spawn.Adapter.
 func (f *FakeAdapter) OpenWindow(command []string) spawn.Result {
+	return f.open(spawn.PlacementWindows, command)
+}
+
+// OpenTab behaves exactly like OpenWindow, recording PlacementTabs.
+func (f *FakeAdapter) OpenTab(command []string) spawn.Result {
+	return f.open(spawn.PlacementTabs, command)
+}
+
+// OpenSplit behaves exactly like OpenWindow, recording PlacementSplits.
+func (f *FakeAdapter) OpenSplit(command []string) spawn.Result {
+	return f.open(spawn.PlacementSplits, command)
+}
+
+// Supports reports every placement unless WindowsOnly is set.
+func (f *FakeAdapter) Supports(p spawn.Placement) bool {
+	return !f.WindowsOnly || p == spawn.PlacementWindows || p == ""
+}
+
+// open is the shared recording + scripted-result body behind all three
+// capabilities.
+func (f *FakeAdapter) open(p spawn.Placement, command []string) spawn.Result {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	f.Calls = append(f.Calls, slices.Clone(command))
+	f.Placements = append(f.Placements, p)
 
 	i := len(f.Calls) - 1
 	result := spawn.Success("")
@@ -0,0 +1,168 @@
+package state
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestParseStatTPGID(t *testing.T) {
+	tests := []struct {
+		name string
+		stat string
+		want int
+		ok   bool
+	}{
+		{"plain command name", "4242 (zsh) S 4200 4242 4242 34817 5150 4194304 1 2", 5150, true},
+		{"command name containing ') '", "4242 (a) b (c) S 4200 4242 4242 34817 77 4194304", 77, true},
+		{"no controlling terminal", "4242 (daemon) S 1 4242 4242 0 -1 4194304", 0, false},
+		{"truncated line", "4242 (zsh) S 4200", 0, false},
+		{"no command name", "garbage", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseStatTPGID([]byte(tt.stat))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseStatTPGID = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSplitCmdline(t *testing.T) {
+	got := splitCmdline([]byte("node\x00server.js\x00--title\x00my app\x00"))
+	want := []string{"node", "server.js", "--title", "my app"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitCmdline = %q, want %q", got, want)
+	}
+
+	if got := splitCmdline(nil); got != nil {
+		t.Errorf("splitCmdline(empty) = %q, want nil", got)
+	}
+}
+
+func TestParsePSTable(t *testing.T) {
+	out := "  101   205 -zsh\n" +
+		"  205   205 npm run dev --port 3000\n" +
+		"    1    -1 /sbin/launchd\n" +
+		"malformed line\n"
+
+	table := parsePSTable(out)
+
+	if len(table) != 3 {
+		t.Fatalf("parsed %d entries, want 3: %+v", len(table), table)
+	}
+	pane := table[101]
+	if pane.tpgid != 205 {
+		t.Errorf("pane tpgid = %d, want 205", pane.tpgid)
+	}
+	want := []string{"npm", "run", "dev", "--port", "3000"}
+	if got := table[pane.tpgid].argv; !reflect.DeepEqual(got, want) {
+		t.Errorf("foreground argv = %q, want %q", got, want)
+	}
+}
+
+func TestBuildPanesResolvesArgvFromPanePID(t *testing.T) {
+	row, err := parsePaneRow("work|||0|||main|||L|||0|||1|||0|||/app|||1|||node|||aB3xY9kZ|||4242|||")
+	if err != nil {
+		t.Fatalf("parsePaneRow: %v", err)
+	}
+	unknown, err := parsePaneRow("work|||0|||main|||L|||0|||1|||1|||/app|||0|||zsh|||aB3xY9kZ||||||")
+	if err != nil {
+		t.Fatalf("parsePaneRow: %v", err)
+	}
+
+	var asked []int
+	resolve := func(pid int) []string {
+		asked = append(asked, pid)
+		return []string{"node", "server.js", "--token", "s3cret"}
+	}
+
+	panes := buildPanes("work", 0, []paneRow{row, unknown}, resolve)
+
+	if !reflect.DeepEqual(asked, []int{4242}) {
+		t.Errorf("resolver asked for %v, want only the known pid 4242", asked)
+	}
+	if got, want := panes[0].Argv, []string{"node", "server.js", "--token", "<redacted>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pane 0 Argv = %q, want the resolved argv redacted, %q", got, want)
+	}
+	if panes[1].Argv != nil {
+		t.Errorf("pane 1 Argv = %q, want nil for a pane without a pid", panes[1].Argv)
+	}
+}
+
+func TestForegroundArgvResolverRereadsArgsUnderTheSameCommand(t *testing.T) {
+	// Pids above the kernel's pid_max ceiling, so /proc never answers and every
+	// lookup goes through the ps fallback.
+	const pane = 4194400
+	listing := "4194401 4194401 node a.js"
+	orig := psCommand
+	psCommand = func() *exec.Cmd {
+		return exec.Command("printf", "%s\n", "4194400 4194401 -zsh", listing)
+	}
+	t.Cleanup(func() { psCommand = orig })
+
+	if got, want := ForegroundArgvResolver()(pane), []string{"node", "a.js"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("first capture argv = %q, want %q", got, want)
+	}
+	listing = "4194401 4194401 node b.js"
+	if got, want := ForegroundArgvResolver()(pane), []string{"node", "b.js"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("next capture argv = %q, want the new run's %q", got, want)
+	}
+}
+
+func TestForegroundArgvResolverListsPSOnce(t *testing.T) {
+	spawns := 0
+	orig := psCommand
+	psCommand = func() *exec.Cmd {
+		spawns++
+		return exec.Command("printf", "%s\n", "4194400 4194402 -zsh", "4194401 4194402 -zsh", "4194402 4194402 python3 /usr/bin/aider")
+	}
+	t.Cleanup(func() { psCommand = orig })
+
+	resolve := ForegroundArgvResolver()
+	for _, pane := range []int{4194400, 4194401} {
+		if got, want := resolve(pane), []string{"python3", "/usr/bin/aider"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("argv(%d) = %q, want %q", pane, got, want)
+		}
+	}
+	if got := resolve(4194499); got != nil {
+		t.Errorf("argv of an unknown pane = %q, want nil", got)
+	}
+	if spawns != 1 {
+		t.Errorf("ps spawned %d times, want once per resolver", spawns)
+	}
+}
+
+func TestRedactArgv(t *testing.T) {
+	tests := []struct {
+		name string
+		argv []string
+		want []string
+	}{
+		{"nothing secret", []string{"node", "server.js", "--port", "3000"}, []string{"node", "server.js", "--port", "3000"}},
+		{"flag=value", []string{"app", "--api-key=abc", "--password=hunter2"}, []string{"app", "--api-key=<redacted>", "--password=<redacted>"}},
+		{"flag value", []string{"app", "--token", "abc", "run"}, []string{"app", "--token", "<redacted>", "run"}},
+		{"single-dash long flag", []string{"app", "-secret", "abc"}, []string{"app", "-secret", "<redacted>"}},
+		{"secret flag without a value", []string{"app", "--auth", "--verbose"}, []string{"app", "--auth", "--verbose"}},
+		{"bare key is not secret", []string{"vim", "--key-bindings", "emacs"}, []string{"vim", "--key-bindings", "emacs"}},
+		{"env assignment", []string{"env", "GITHUB_TOKEN=abc", "AWS_SECRET_ACCESS_KEY=def", "EDITOR=vim", "gh"}, []string{"env", "GITHUB_TOKEN=<redacted>", "AWS_SECRET_ACCESS_KEY=<redacted>", "EDITOR=vim", "gh"}},
+		{"url password", []string{"psql", "postgres://bob:pw@db/app", "--url=https://u:p@h/x"}, []string{"psql", "postgres://bob:<redacted>@db/app", "--url=https://u:<redacted>@h/x"}},
+		{"url without password", []string{"git", "clone", "https://git@host/repo"}, []string{"git", "clone", "https://git@host/repo"}},
+		{"program name untouched", []string{"--token"}, []string{"--token"}},
+		{"run-together env name", []string{"env", "PGPASSWORD=pw", "MYAPPTOKEN=t", "psql"}, []string{"env", "PGPASSWORD=<redacted>", "MYAPPTOKEN=<redacted>", "psql"}},
+		{"short words stay whole segments", []string{"git", "commit", "--author", "Bob", "--bypass-cache"}, []string{"git", "commit", "--author", "Bob", "--bypass-cache"}},
+		{"attached -p", []string{"mysql", "-uroot", "-psecret", "app"}, []string{"mysql", "-uroot", "-p<redacted>", "app"}},
+		{"separate -p", []string{"sshpass", "-p", "secret", "ssh", "host"}, []string{"sshpass", "-p", "<redacted>", "ssh", "host"}},
+		{"single-dash password flag", []string{"app", "-password", "pw"}, []string{"app", "-password", "<redacted>"}},
+		{"secret header", []string{"curl", "-H", "Authorization: Bearer abc", "-HX-Api-Key: k", "--header=Cookie: s=1", "https://h"}, []string{"curl", "-H", "Authorization: <redacted>", "-HX-Api-Key: <redacted>", "--header=Cookie: <redacted>", "https://h"}},
+		{"plain header", []string{"curl", "--header", "Accept: text/html", "https://h"}, []string{"curl", "--header", "Accept: text/html", "https://h"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactArgv(tt.argv); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactArgv(%q) = %q, want %q", tt.argv, got, tt.want)
+			}
+		})
+	}
+}
@@ -39,9 +39,15 @@ type CaptureClient interface {
 // from the first row when assembling Session.PortalID. Its alphanumeric token
 // cannot contain "|||", so appending it as the last column keeps every existing
 // field index unchanged. An un-stamped (legacy) session resolves it to "".
-const captureFormat = "#{session_name}|||#{window_index}|||#{window_name}|||#{window_layout}|||#{window_zoomed_flag}|||#{window_active}|||#{pane_index}|||#{pane_current_path}|||#{pane_active}|||#{pane_current_command}|||#{@portal-id}"
+//
+// #{pane_pid} follows it: the pid of the pane's own process, from which the
+// foreground process's full argv is resolved (see argv.go) for Pane.Argv.
+//
+// #{@portal-dir} closes the row: the session-scoped project directory, lifted
+// from the first row like @portal-id into Session.Dir.
+const captureFormat = "#{session_name}|||#{window_index}|||#{window_name}|||#{window_layout}|||#{window_zoomed_flag}|||#{window_active}|||#{pane_index}|||#{pane_current_path}|||#{pane_active}|||#{pane_current_command}|||#{@portal-id}|||#{pane_pid}|||#{@portal-dir}"
 
-const captureFieldCount = 11
+const captureFieldCount = 13
 
 // internalSessionPrefix marks tmux sessions that Portal owns and which must
 // not appear in the captured structural index. See specification → Session
@@ -114,6 +120,7 @@ func CaptureStructure(c CaptureClient, skipSet map[string]struct{}, prev *Index,
 		}
 	}
 
+	argv := newArgvResolver()
 	sessions := make([]Session, 0, len(keep))
 	var anomalousErrs []error
 	naturalChurnCount := 0
@@ -129,19 +136,21 @@ func CaptureStructure(c CaptureClient, skipSet map[string]struct{}, prev *Index,
 			logger.Warn("capture anomalous session error", "session", name, "error", err)
 			continue
 		}
-		// @portal-id is session-scoped — identical on every pane row — so lift
-		// it once from the first row of the group. A zero-row session (no pane
-		// rows) yields "" here and empty Windows below; Restore rejects such an
-		// entry downstream, so no guard/skip is added at capture time.
-		portalID := ""
+		// @portal-id and @portal-dir are session-scoped — identical on every
+		// pane row — so lift them once from the first row of the group. A
+		// zero-row session (no pane rows) yields "" here and empty Windows
+		// below; Restore rejects such an entry downstream, so no guard/skip is
+		// added at capture time.
+		portalID, dir := "", ""
 		if rows := grouped[name]; len(rows) > 0 {
-			portalID = rows[0].portalID
+			portalID, dir = rows[0].portalID, rows[0].dir
 		}
 		sessions = append(sessions, Session{
 			Name:        name,
 			PortalID:    portalID,
+			Dir:         dir,
 			Environment: parseShowEnvironment(envRaw),
-			Windows:     buildWindows(name, grouped[name]),
+			Windows:     buildWindows(name, grouped[name], argv),
 		})
 	}
 
@@ -260,6 +269,7 @@ func findOrAppendSession(fresh *Index, ps Session) int {
 	fresh.Sessions = append(fresh.Sessions, Session{
 		Name:        ps.Name,
 		PortalID:    ps.PortalID,
+		Dir:         ps.Dir,
 		Environment: ps.Environment,
 		Windows:     []Window{},
 	})
@@ -347,6 +357,10 @@ type paneRow struct {
 	// portalID is the session-scoped @portal-id, repeated on every pane row of
 	// the same session; the consumer takes it from the first row of the group.
 	portalID string
+	// pid is the pane's own process id; 0 when tmux reported none.
+	pid int
+	// dir is the session-scoped @portal-dir, carried like portalID.
+	dir string
 }
 
 // parsePaneRows splits raw list-panes -a output into rows grouped by session
@@ -389,6 +403,8 @@ func parsePaneRow(line string) (paneRow, error) {
 	if err != nil {
 		return paneRow{}, fmt.Errorf("invalid pane index %q: %w", parts[6], err)
 	}
+	// An unparseable pid only costs the pane its argv, so it is not an error.
+	pid, _ := strconv.Atoi(parts[11])
 	return paneRow{
 		session:        parts[0],
 		windowIdx:      windowIdx,
@@ -401,6 +417,8 @@ func parsePaneRow(line string) (paneRow, error) {
 		paneActive:     parseTmuxBool(parts[8]),
 		currentCommand: parts[9],
 		portalID:       parts[10],
+		pid:            pid,
+		dir:            parts[12],
 	}, nil
 }
 
@@ -412,8 +430,9 @@ func parseTmuxBool(s string) bool {
 
 // buildWindows groups pane rows by window and produces a sorted []Window for
 // the named session. Windows are sorted by index ascending; panes within each
-// window are likewise sorted by index ascending.
-func buildWindows(session string, rows []paneRow) []Window {
+// window are likewise sorted by index ascending. argv resolves each pane's
+// foreground command line from its pid.
+func buildWindows(session string, rows []paneRow, argv argvResolver) []Window {
 	byWindow := make(map[int][]paneRow)
 	for _, r := range rows {
 		byWindow[r.windowIdx] = append(byWindow[r.windowIdx], r)
@@ -436,15 +455,17 @@ func buildWindows(session string, rows []paneRow) []Window {
 			Layout: head.layout,
 			Zoomed: head.zoomed,
 			Active: head.windowActive,
-			Panes:  buildPanes(session, head.windowIdx, group),
+			Panes:  buildPanes(session, head.windowIdx, group, argv),
 		})
 	}
 	return windows
 }
 
 // buildPanes converts the rows belonging to a single window into a sorted
-// []Pane with each pane's ScrollbackFile set to the canonical relative path.
-func buildPanes(session string, windowIdx int, rows []paneRow) []Pane {
+// []Pane with each pane's ScrollbackFile set to the canonical relative path
+// and its Argv resolved from the row's pid (left nil when the pid is unknown)
+// and redacted.
+func buildPanes(session string, windowIdx int, rows []paneRow, argv argvResolver) []Pane {
 	sort.Slice(rows, func(i, j int) bool {
 		return rows[i].paneIdx < rows[j].paneIdx
 	})
@@ -455,11 +476,16 @@ func buildPanes(session string, windowIdx int, rows []paneRow) []Pane {
 		// is identical across platforms (the daemon may run on Windows in
 		// future; the spec stores forward-slash relative paths).
 		path := filepath.ToSlash(filepath.Join("scrollback", key+".bin"))
+		var args []string
+		if r.pid > 0 {
+			args = redactArgv(argv(r.pid))
+		}
 		panes = append(panes, Pane{
 			Index:          r.paneIdx,
 			CWD:            r.cwd,
 			Active:         r.paneActive,
 			CurrentCommand: r.currentCommand,
+			Argv:           args,
 			ScrollbackFile: path,
 		})
 	}
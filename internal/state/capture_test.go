@@ -91,10 +91,12 @@ func paneLine(session string, windowIdx int, windowName, layout string, zoomed,
 	return paneLineWithID(session, windowIdx, windowName, layout, zoomed, windowActive, paneIdx, cwd, paneActive, currentCommand, "")
 }
 
-// paneLineWithID renders one pane row with an explicit trailing @portal-id
-// column — the 11th and final |||-separated field of captureFormat. The id is
-// session-scoped in tmux (repeated on every pane row of the same session); the
-// parser consumes it from the first row when assembling Session.PortalID.
+// paneLineWithID renders one pane row with an explicit @portal-id column — the
+// 11th |||-separated field of captureFormat. The id is session-scoped in tmux
+// (repeated on every pane row of the same session); the parser consumes it
+// from the first row when assembling Session.PortalID. The 12th #{pane_pid}
+// column is left empty so no argv lookup runs against a real process, and the
+// 13th and final @portal-dir column is left un-stamped.
 func paneLineWithID(session string, windowIdx int, windowName, layout string, zoomed, windowActive bool, paneIdx int, cwd string, paneActive bool, currentCommand, portalID string) string {
 	bool01 := func(b bool) string {
 		if b {
@@ -103,7 +105,7 @@ func paneLineWithID(session string, windowIdx int, windowName, layout string, zo
 		return "0"
 	}
 	return fmt.Sprintf(
-		"%s|||%d|||%s|||%s|||%s|||%s|||%d|||%s|||%s|||%s|||%s",
+		"%s|||%d|||%s|||%s|||%s|||%s|||%d|||%s|||%s|||%s|||%s||||||",
 		session, windowIdx, windowName, layout, bool01(zoomed), bool01(windowActive), paneIdx, cwd, bool01(paneActive), currentCommand, portalID,
 	)
 }
@@ -524,7 +526,7 @@ func TestCaptureStructure(t *testing.T) {
 }
 
 // TestCaptureStructurePortalID pins the Cross-Reboot Persistence capture leg:
-// captureFormat appends #{@portal-id} as its 11th and final column, the parser
+// captureFormat carries #{@portal-id} as its 11th column, the parser
 // reads it into paneRow.portalID (parts[10]), and CaptureStructure lifts it
 // from the FIRST row of a session's grouped pane rows into Session.PortalID.
 // sessions.json is the only durable record of a session's immutable id across
@@ -604,21 +606,22 @@ func TestCaptureStructurePortalID(t *testing.T) {
 	})
 
 	t.Run("it rejects a wrong-arity pane row after the field-count bump", func(t *testing.T) {
-		// A 10-field row (the pre-bump arity) is now short by one column. The
-		// len(parts) != captureFieldCount guard — now enforcing arity 11 — must
-		// reject it with the canonical field-count error, and CaptureStructure
-		// must propagate it as a pre-loop fail-fatal (no partial index).
-		tenFieldRow := "work|||0|||main|||L|||0|||1|||0|||/tmp|||1|||zsh"
+		// An 11-field row (the arity before #{pane_pid}) is now short by one
+		// column. The len(parts) != captureFieldCount guard — now enforcing
+		// arity 12 — must reject it with the canonical field-count error, and
+		// CaptureStructure must propagate it as a pre-loop fail-fatal (no
+		// partial index).
+		elevenFieldRow := "work|||0|||main|||L|||0|||1|||0|||/tmp|||1|||zsh|||aB3xY9kZ"
 		mock := &captureMock{
 			listSessions: listSessionsFor("work"),
-			listPanes:    tenFieldRow,
+			listPanes:    elevenFieldRow,
 			t:            t,
 		}
 		client := tmux.NewClient(mock)
 
 		idx, err := state.CaptureStructure(client, nil, nil, nil)
 		if err == nil {
-			t.Fatal("expected error for a 10-field row under 11-arity, got nil")
+			t.Fatal("expected error for an 11-field row under 12-arity, got nil")
 		}
 		if !strings.Contains(err.Error(), "unexpected pane row field count") {
 			t.Errorf("error = %q, want it to contain %q", err.Error(), "unexpected pane row field count")
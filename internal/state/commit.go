@@ -30,7 +30,8 @@ import (
 // On a real change, sessions.json is written via fileutil.AtomicWrite0600
 // (atomic write + post-rename chmod 0600 against a permissive umask). After a
 // successful write, gcOrphanScrollback removes any .bin files no longer
-// referenced by idx. GC failure is logged but never fails the commit —
+// referenced by idx and then any stored chunk no remaining manifest
+// references. GC failure is logged but never fails the commit —
 // sessions.json is the source of truth.
 func Commit(dir string, idx Index, anyScrollbackChanged bool, logger *slog.Logger) error {
 	logger = loggerOrDiscard(logger)
@@ -98,7 +99,8 @@ func ComputeReferencedSet(idx Index) map[string]struct{} {
 // referenced by idx. A missing scrollback directory is a no-op. Per-file
 // remove failures are logged at WARN and do not abort the sweep — the next
 // successful commit will retry. ENOENT during remove (e.g. concurrent
-// cleanup) is treated as success.
+// cleanup) is treated as success. The sweep finishes with gcOrphanChunks so
+// chunks only the removed manifests used go too.
 func gcOrphanScrollback(dir string, idx Index, logger *slog.Logger) error {
 	sbDir := ScrollbackDir(dir)
 	entries, err := os.ReadDir(sbDir)
@@ -134,5 +136,5 @@ func gcOrphanScrollback(dir string, idx Index, logger *slog.Logger) error {
 			// Continue: subsequent files may still be removable.
 		}
 	}
-	return nil
+	return gcOrphanChunks(dir, logger)
 }
@@ -0,0 +1,98 @@
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+)
+
+// DormantRetention is how long a dormant session is kept after it was last
+// saved running. A week matches the daily snapshot tier: long enough to
+// resurrect last Monday's session, short enough that killed sessions and
+// their scrollback do not accumulate forever. Lazy-policy sessions are exempt:
+// dormant is their resting state, not a sign they were abandoned.
+const DormantRetention = 7 * 24 * time.Hour
+
+// CarryDormant keeps sessions that prev held but fresh no longer captures,
+// marking them dormant instead of letting the commit drop them. A session
+// that goes dormant is stamped with prev.SavedAt — the last save that saw it
+// running — and keeps that stamp on every later carry, so it ages out
+// DormantRetention after it stopped. A live session always wins: once a
+// session of the same name is captured again its dormant entry is gone. A
+// session renamed since prev is matched by PortalID and not carried at all.
+//
+// It returns the sessions this carry moved from running to dormant — the
+// ones killed since prev was saved. That transition is committed exactly once,
+// by whichever of the daemon and commit-now writes it first, so its result is
+// the single source for on-kill hooks.
+//
+// Dormant sessions stay in the index so their scrollback stays referenced
+// (commit GC keeps it) and so an on-demand resurrect has the full topology to
+// rebuild from. Restore skips them; only an explicit resurrect recreates one.
+// A session policy resolves to prefs.RestoreLazy never ages out — boot leaves
+// it dormant on purpose, so expiring it would delete sessions the user asked
+// to keep. A nil prev is a no-op; a nil policy treats every session as always.
+func CarryDormant(fresh *Index, prev *Index, now time.Time, policy RestorePolicyFunc) []Session {
+	if prev == nil {
+		return nil
+	}
+	live := make(map[string]struct{}, len(fresh.Sessions))
+	ids := make(map[string]struct{}, len(fresh.Sessions))
+	for _, s := range fresh.Sessions {
+		live[s.Name] = struct{}{}
+		if s.PortalID != "" {
+			ids[s.PortalID] = struct{}{}
+		}
+	}
+
+	var stopped []Session
+	carried := false
+	for _, ps := range prev.Sessions {
+		if _, ok := live[ps.Name]; ok || strings.HasPrefix(ps.Name, internalSessionPrefix) {
+			continue
+		}
+		if _, ok := ids[ps.PortalID]; ok && ps.PortalID != "" {
+			continue
+		}
+		if !ps.Dormant {
+			ps.Dormant = true
+			ps.SavedAt = prev.SavedAt
+			stopped = append(stopped, ps)
+		}
+		if now.Sub(ps.SavedAt) > DormantRetention && policy.Of(ps) != prefs.RestoreLazy {
+			continue
+		}
+		fresh.Sessions = append(fresh.Sessions, ps)
+		carried = true
+	}
+	if carried {
+		resortIndex(fresh)
+	}
+	return stopped
+}
+
+// DormantSessions returns the sessions in idx that are not running, in index
+// order: every session whose name is absent from live. Each result is marked
+// Dormant and carries the time it was last saved running — its own SavedAt
+// when the daemon already carried it, else the index's SavedAt (a session
+// that failed to restore, or one killed since the last save).
+func DormantSessions(idx Index, live []string) []Session {
+	running := make(map[string]struct{}, len(live))
+	for _, name := range live {
+		running[name] = struct{}{}
+	}
+
+	var out []Session
+	for _, s := range idx.Sessions {
+		if _, ok := running[s.Name]; ok || strings.HasPrefix(s.Name, internalSessionPrefix) {
+			continue
+		}
+		if !s.Dormant {
+			s.Dormant = true
+			s.SavedAt = idx.SavedAt
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,226 @@
+package state_test
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// dormantIndex returns an index saved at savedAt holding one single-pane
+// session per name.
+func dormantIndex(savedAt time.Time, names ...string) state.Index {
+	idx := state.Index{Version: state.SchemaVersion, SavedAt: savedAt}
+	for _, name := range names {
+		idx.Sessions = append(idx.Sessions, state.Session{
+			Name: name,
+			Windows: []state.Window{{Panes: []state.Pane{{
+				CWD:            "/tmp",
+				ScrollbackFile: "scrollback/" + state.SanitizePaneKey(name, 0, 0) + ".bin",
+			}}}},
+		})
+	}
+	return idx
+}
+
+// sessionNames returns the session names of idx in order.
+func sessionNames(idx state.Index) []string {
+	var out []string
+	for _, s := range idx.Sessions {
+		out = append(out, s.Name)
+	}
+	return out
+}
+
+func TestCarryDormant(t *testing.T) {
+	prevSaved := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	now := prevSaved.Add(time.Minute)
+
+	t.Run("a session missing from the capture is carried as dormant", func(t *testing.T) {
+		prev := dormantIndex(prevSaved, "alpha", "beta")
+		fresh := dormantIndex(now, "beta")
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+
+		if got := sessionNames(fresh); len(got) != 2 || got[0] != "alpha" || got[1] != "beta" {
+			t.Fatalf("sessions = %v, want [alpha beta]", got)
+		}
+		alpha := fresh.Sessions[0]
+		if !alpha.Dormant || !alpha.SavedAt.Equal(prevSaved) {
+			t.Errorf("alpha = dormant %v saved %v, want dormant saved %v", alpha.Dormant, alpha.SavedAt, prevSaved)
+		}
+		if fresh.Sessions[1].Dormant {
+			t.Error("live session beta marked dormant")
+		}
+	})
+
+	t.Run("a dormant session keeps the time it stopped", func(t *testing.T) {
+		stopped := prevSaved.Add(-48 * time.Hour)
+		prev := dormantIndex(prevSaved, "alpha")
+		prev.Sessions[0].Dormant = true
+		prev.Sessions[0].SavedAt = stopped
+		fresh := dormantIndex(now)
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+
+		if len(fresh.Sessions) != 1 || !fresh.Sessions[0].SavedAt.Equal(stopped) {
+			t.Fatalf("sessions = %+v, want alpha still stamped %v", fresh.Sessions, stopped)
+		}
+	})
+
+	t.Run("a dormant session past retention is dropped", func(t *testing.T) {
+		prev := dormantIndex(prevSaved, "alpha")
+		prev.Sessions[0].Dormant = true
+		prev.Sessions[0].SavedAt = now.Add(-state.DormantRetention - time.Second)
+		fresh := dormantIndex(now)
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+
+		if len(fresh.Sessions) != 0 {
+			t.Errorf("sessions = %v, want the expired session dropped", sessionNames(fresh))
+		}
+	})
+
+	t.Run("a lazy-policy dormant session never ages out", func(t *testing.T) {
+		prev := dormantIndex(prevSaved, "alpha", "beta")
+		for i := range prev.Sessions {
+			prev.Sessions[i].Dormant = true
+			prev.Sessions[i].SavedAt = now.Add(-state.DormantRetention - time.Second)
+		}
+		fresh := dormantIndex(now)
+		lazyAlpha := func(s state.Session) prefs.RestorePolicy {
+			if s.Name == "alpha" {
+				return prefs.RestoreLazy
+			}
+			return prefs.RestoreAlways
+		}
+
+		state.CarryDormant(&fresh, &prev, now, lazyAlpha)
+
+		if got := sessionNames(fresh); len(got) != 1 || got[0] != "alpha" {
+			t.Errorf("sessions = %v, want only the lazy alpha kept", got)
+		}
+	})
+
+	t.Run("a live session of the same name replaces the dormant entry", func(t *testing.T) {
+		prev := dormantIndex(prevSaved, "alpha")
+		prev.Sessions[0].Dormant = true
+		prev.Sessions[0].SavedAt = prevSaved
+		fresh := dormantIndex(now, "alpha")
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+
+		if len(fresh.Sessions) != 1 || fresh.Sessions[0].Dormant {
+			t.Errorf("sessions = %+v, want only the live alpha", fresh.Sessions)
+		}
+	})
+
+	t.Run("internal sessions and a nil prev are ignored", func(t *testing.T) {
+		prev := dormantIndex(prevSaved, "_portal-saver")
+		fresh := dormantIndex(now)
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+		state.CarryDormant(&fresh, nil, now, nil)
+
+		if len(fresh.Sessions) != 0 {
+			t.Errorf("sessions = %v, want none", sessionNames(fresh))
+		}
+	})
+
+	t.Run("carried sessions keep their scrollback through commit GC", func(t *testing.T) {
+		dir := t.TempDir()
+		writeScrollback(t, dir, "alpha__0.0", "alpha history\n", state.HashMap{})
+		prev := dormantIndex(prevSaved, "alpha")
+		fresh := dormantIndex(now)
+
+		state.CarryDormant(&fresh, &prev, now, nil)
+		if err := state.Commit(dir, fresh, true, nil); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		if _, err := os.Stat(state.ScrollbackFile(dir, "alpha__0.0")); err != nil {
+			t.Errorf("dormant session's scrollback was collected: %v", err)
+		}
+	})
+
+	t.Run("re-carrying an unchanged dormant set is not a structural change", func(t *testing.T) {
+		dir := t.TempDir()
+		prev := dormantIndex(prevSaved, "alpha")
+		first := dormantIndex(now)
+		state.CarryDormant(&first, &prev, now, nil)
+		if err := state.Commit(dir, first, false, nil); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+		onDisk, _, err := state.ReadIndex(dir)
+		if err != nil {
+			t.Fatalf("ReadIndex: %v", err)
+		}
+		info, err := os.Stat(state.SessionsJSON(dir))
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if err := os.Chtimes(state.SessionsJSON(dir), prevSaved, prevSaved); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+
+		later := now.Add(time.Minute)
+		second := dormantIndex(later)
+		state.CarryDormant(&second, &onDisk, later, nil)
+		if err := state.Commit(dir, second, false, nil); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		after, err := os.Stat(state.SessionsJSON(dir))
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if !after.ModTime().Equal(prevSaved) || after.Size() != info.Size() {
+			t.Error("sessions.json was rewritten for an unchanged dormant set")
+		}
+	})
+}
+
+func TestDormantSessions(t *testing.T) {
+	savedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	stopped := savedAt.Add(-time.Hour)
+	idx := dormantIndex(savedAt, "_portal-saver", "alpha", "beta", "gamma")
+	idx.Sessions[3].Dormant = true
+	idx.Sessions[3].SavedAt = stopped
+
+	got := state.DormantSessions(idx, []string{"beta"})
+
+	if names := sessionNames(state.Index{Sessions: got}); len(names) != 2 || names[0] != "alpha" || names[1] != "gamma" {
+		t.Fatalf("dormant = %v, want [alpha gamma]", names)
+	}
+	if !got[0].Dormant || !got[0].SavedAt.Equal(savedAt) {
+		t.Errorf("alpha = dormant %v saved %v, want the index save time %v", got[0].Dormant, got[0].SavedAt, savedAt)
+	}
+	if !got[1].SavedAt.Equal(stopped) {
+		t.Errorf("gamma saved %v, want its own stamp %v", got[1].SavedAt, stopped)
+	}
+}
+
+func TestCarryDormant_ReturnsStoppedSessions(t *testing.T) {
+	savedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	prev := dormantIndex(savedAt, "_portal-saver", "alpha", "beta", "gamma", "delta")
+	prev.Sessions[2].PortalID = "id-beta"
+	prev.Sessions[4].Dormant = true
+	prev.Sessions[4].SavedAt = savedAt
+
+	// beta was renamed (same PortalID), gamma and the saver are still running,
+	// delta was already dormant: only alpha was killed.
+	fresh := dormantIndex(savedAt, "_portal-saver", "beta-renamed", "gamma")
+	fresh.Sessions[1].PortalID = "id-beta"
+
+	got := sessionNames(state.Index{Sessions: state.CarryDormant(&fresh, &prev, savedAt, nil)})
+
+	if len(got) != 1 || got[0] != "alpha" {
+		t.Errorf("stopped = %v, want [alpha]", got)
+	}
+	if names := sessionNames(fresh); len(names) != 5 || slices.Contains(names, "beta") {
+		t.Errorf("sessions = %v, want the renamed beta carried only under its new name", names)
+	}
+}
@@ -123,7 +123,7 @@ func TestReadIndex_ReturnsSkipWithParseErrorForNonJSONContent(t *testing.T) {
 func TestReadIndex_ReturnsSkipWithVersionErrorWhenSchemaVersionExceedsCurrent(t *testing.T) {
 	dir := t.TempDir()
 	writeSessionsJSON(t, dir, []byte(`{
-  "version": 2,
+  "version": 3,
   "saved_at": "2026-04-17T10:30:00Z",
   "sessions": []
 }`))
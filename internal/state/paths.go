@@ -18,9 +18,16 @@ const (
 	daemonPIDName     = "daemon.pid"
 	daemonVersionName = "daemon.version"
 	daemonLockName    = "daemon.lock"
+	daemonSocketName  = "daemon.sock"
 	portalLogName     = "portal.log"
 	portalLogOldName  = "portal.log.old"
 	scrollbackSubdir  = "scrollback"
+	snapshotsSubdir   = "snapshots"
+	agentJSONName     = "agent.json"
+	agentInboxSubdir  = "agent-inbox"
+
+	// scrollbackChunksSubdir is nested inside scrollbackSubdir.
+	scrollbackChunksSubdir = "chunks"
 )
 
 // Dir resolves the absolute path to Portal's state directory.
@@ -109,6 +116,10 @@ func DaemonVersion(dir string) string { return filepath.Join(dir, daemonVersionN
 // composes the path so the layout stays in one place.
 func DaemonLock(dir string) string { return filepath.Join(dir, daemonLockName) }
 
+// DaemonSocket returns the path to the daemon's control socket (see
+// internal/control).
+func DaemonSocket(dir string) string { return filepath.Join(dir, daemonSocketName) }
+
 // PortalLog returns the path to the current portal log file.
 func PortalLog(dir string) string { return filepath.Join(dir, portalLogName) }
 
@@ -119,12 +130,37 @@ func PortalLogOld(dir string) string { return filepath.Join(dir, portalLogOldNam
 // scrollback `.bin` files.
 func ScrollbackDir(dir string) string { return filepath.Join(dir, scrollbackSubdir) }
 
+// ScrollbackChunksDir returns the path to the content-addressed chunk store
+// the per-pane scrollback manifests reference.
+func ScrollbackChunksDir(dir string) string {
+	return filepath.Join(dir, scrollbackSubdir, scrollbackChunksSubdir)
+}
+
 // ScrollbackFile returns the path to the scrollback `.bin` file for the
-// given canonical paneKey.
+// given canonical paneKey. The file is a chunk-store manifest (or, before
+// migration, raw captured bytes); read it through OpenScrollback,
+// ReadScrollback or TailScrollback rather than directly.
 func ScrollbackFile(dir, paneKey string) string {
 	return filepath.Join(dir, scrollbackSubdir, paneKey+".bin")
 }
 
+// SnapshotsDir returns the path to the directory holding named snapshots. Each
+// snapshot is a subdirectory mirroring the state directory's own
+// sessions.json + scrollback/ layout.
+func SnapshotsDir(dir string) string { return filepath.Join(dir, snapshotsSubdir) }
+
+// SnapshotDir returns the path to the named snapshot's directory. The name is
+// not validated here; callers go through ValidateSnapshotName first.
+func SnapshotDir(dir, name string) string { return filepath.Join(dir, snapshotsSubdir, name) }
+
+// AgentJSON returns the path to the state daemon's persisted agent-state
+// table.
+func AgentJSON(dir string) string { return filepath.Join(dir, agentJSONName) }
+
+// AgentInbox returns the path to the directory `portal agent ingest` posts
+// agent updates into for the daemon to drain.
+func AgentInbox(dir string) string { return filepath.Join(dir, agentInboxSubdir) }
+
 // FIFOPath returns the hydration FIFO path for the given canonical paneKey.
 func FIFOPath(dir, paneKey string) string {
 	return filepath.Join(dir, "hydrate-"+paneKey+".fifo")
@@ -0,0 +1,73 @@
+package state
+
+import "github.com/leeovery/portal/internal/prefs"
+
+// RestorePolicyFunc resolves the restore policy that applies to a saved
+// session — the global prefs.json setting, or its project's override in
+// projects.json. A nil RestorePolicyFunc means every session is
+// prefs.RestoreAlways.
+type RestorePolicyFunc func(Session) prefs.RestorePolicy
+
+// Of returns the policy for sess, treating a nil func as always.
+func (f RestorePolicyFunc) Of(sess Session) prefs.RestorePolicy {
+	if f == nil {
+		return prefs.RestoreAlways
+	}
+	return f(sess)
+}
+
+// SessionDir returns the directory a saved session is matched to a project
+// by: the working directory of the active pane in the active window — the
+// same pane the picker's directory fallback reads for a live session. A
+// session with no active markers falls back to its first pane; one with no
+// panes yields "".
+func SessionDir(sess Session) string {
+	first := ""
+	for _, w := range sess.Windows {
+		for _, p := range w.Panes {
+			if first == "" {
+				first = p.CWD
+			}
+			if w.Active && p.Active {
+				return p.CWD
+			}
+		}
+	}
+	return first
+}
+
+// ProjectDir returns the directory a saved session's restore policy is matched
+// by: its @portal-dir (Session.Dir), the project it was opened for, which
+// stays put however far its panes cd. A session Portal never stamped falls
+// back to its root pane — the first pane of the first window, where the
+// session started — rather than the active pane, so the policy does not flip
+// with whichever pane had focus at the last save. A session with no panes
+// yields "".
+func ProjectDir(sess Session) string {
+	if sess.Dir != "" {
+		return sess.Dir
+	}
+	if len(sess.Windows) == 0 || len(sess.Windows[0].Panes) == 0 {
+		return ""
+	}
+	return sess.Windows[0].Panes[0].CWD
+}
+
+// DropNeverSaved removes every session whose policy is prefs.RestoreNever
+// from idx, live or dormant, so it is never written to sessions.json and its
+// scrollback is neither captured nor kept: the next commit's GC collects any
+// files left from before the policy changed. It runs on the capture result,
+// after CarryDormant, and is a no-op for a nil policy.
+func DropNeverSaved(idx *Index, policy RestorePolicyFunc) {
+	if policy == nil {
+		return
+	}
+	kept := idx.Sessions[:0]
+	for _, s := range idx.Sessions {
+		if policy(s) == prefs.RestoreNever {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	idx.Sessions = kept
+}
@@ -0,0 +1,98 @@
+package state_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/state"
+)
+
+func TestSessionDir(t *testing.T) {
+	sess := state.Session{Windows: []state.Window{
+		{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/first"}}},
+		{Index: 1, Active: true, Panes: []state.Pane{
+			{Index: 0, CWD: "/other"},
+			{Index: 1, CWD: "/active", Active: true},
+		}},
+	}}
+
+	if got := state.SessionDir(sess); got != "/active" {
+		t.Errorf("SessionDir = %q, want the active pane's /active", got)
+	}
+
+	sess.Windows[1].Active = false
+	if got := state.SessionDir(sess); got != "/first" {
+		t.Errorf("SessionDir without an active window = %q, want the first pane's /first", got)
+	}
+
+	if got := state.SessionDir(state.Session{}); got != "" {
+		t.Errorf("SessionDir of a pane-less session = %q, want empty", got)
+	}
+}
+
+func TestProjectDir(t *testing.T) {
+	sess := state.Session{Windows: []state.Window{
+		{Index: 0, Panes: []state.Pane{{Index: 0, CWD: "/root"}}},
+		{Index: 1, Active: true, Panes: []state.Pane{{Index: 0, CWD: "/active", Active: true}}},
+	}}
+
+	if got := state.ProjectDir(sess); got != "/root" {
+		t.Errorf("ProjectDir of an unstamped session = %q, want the root pane's /root", got)
+	}
+
+	sess.Dir = "/project"
+	if got := state.ProjectDir(sess); got != "/project" {
+		t.Errorf("ProjectDir = %q, want the @portal-dir stamp /project", got)
+	}
+
+	if got := state.ProjectDir(state.Session{}); got != "" {
+		t.Errorf("ProjectDir of a pane-less session = %q, want empty", got)
+	}
+}
+
+func TestDropNeverSaved(t *testing.T) {
+	savedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	neverSaved := func(s state.Session) prefs.RestorePolicy {
+		if s.Name == "scratch" {
+			return prefs.RestoreNever
+		}
+		return prefs.RestoreLazy
+	}
+
+	t.Run("never sessions are dropped, lazy ones kept", func(t *testing.T) {
+		idx := dormantIndex(savedAt, "alpha", "scratch", "zeta")
+
+		state.DropNeverSaved(&idx, neverSaved)
+
+		if got := sessionNames(idx); len(got) != 2 || got[0] != "alpha" || got[1] != "zeta" {
+			t.Errorf("sessions = %v, want [alpha zeta]", got)
+		}
+	})
+
+	t.Run("a nil policy keeps everything", func(t *testing.T) {
+		idx := dormantIndex(savedAt, "alpha", "scratch")
+
+		state.DropNeverSaved(&idx, nil)
+
+		if len(idx.Sessions) != 2 {
+			t.Errorf("sessions = %v, want both kept", sessionNames(idx))
+		}
+	})
+
+	t.Run("a session switched to never loses its scrollback at the next commit", func(t *testing.T) {
+		dir := t.TempDir()
+		writeScrollback(t, dir, "scratch__0.0", "secret\n", state.HashMap{})
+		idx := dormantIndex(savedAt, "scratch")
+
+		state.DropNeverSaved(&idx, neverSaved)
+		if err := state.Commit(dir, idx, true, nil); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		if _, err := os.Stat(state.ScrollbackFile(dir, "scratch__0.0")); !os.IsNotExist(err) {
+			t.Errorf("scrollback of a never session survived the commit: %v", err)
+		}
+	})
+}
@@ -10,7 +10,14 @@ import (
 // SchemaVersion is the current sessions.json schema version. It is bumped on
 // any schema-breaking change; the loader compares this against the version
 // field in a decoded payload to apply known-version logic.
-const SchemaVersion = 1
+//
+// Version 2 added Pane.Argv. DecodeIndex migrates a version-1 document, whose
+// panes carry no argv until the next capture rewrites them.
+const SchemaVersion = 2
+
+// minSchemaVersion is the oldest sessions.json version DecodeIndex still
+// reads.
+const minSchemaVersion = 1
 
 // Index is the root document persisted to sessions.json. It captures the
 // complete structural topology of all saved tmux sessions plus references to
@@ -28,11 +35,24 @@ type Index struct {
 // in-memory server state and do not outlive the reboot gap. An absent field
 // decodes to "" (a legacy/un-stamped session), which restore treats as the
 // name-fallback path.
+//
+// Dir is the session's @portal-dir — the project directory it was created
+// for — persisted for the same reason, so restore can re-stamp it and a
+// restore policy keeps matching the project however far the panes have
+// wandered. It is omitted for a session Portal did not create.
+//
+// Dormant marks a session that was saved running but is no longer live in
+// tmux — killed, or not recreated by a restore. Restore skips it; SavedAt is
+// the save time of the last index that captured it running. Both fields are
+// omitted for live sessions. See CarryDormant.
 type Session struct {
 	Name        string            `json:"name"`
 	PortalID    string            `json:"portal_id"`
+	Dir         string            `json:"dir,omitempty"`
 	Environment map[string]string `json:"environment"`
 	Windows     []Window          `json:"windows"`
+	Dormant     bool              `json:"dormant,omitempty"`
+	SavedAt     time.Time         `json:"saved_at,omitzero"`
 }
 
 // Window captures a single tmux window: layout, zoom and active state, and
@@ -49,12 +69,16 @@ type Window struct {
 // Pane captures a single tmux pane: its index, working directory, active
 // flag, current foreground command, and the relative path to its scrollback
 // file under the state directory.
+//
+// Argv is the full command line of the pane's foreground process, where
+// CurrentCommand is only its name; it is omitted when it could not be read.
 type Pane struct {
-	Index          int    `json:"index"`
-	CWD            string `json:"cwd"`
-	Active         bool   `json:"active"`
-	CurrentCommand string `json:"current_command"`
-	ScrollbackFile string `json:"scrollback_file"`
+	Index          int      `json:"index"`
+	CWD            string   `json:"cwd"`
+	Active         bool     `json:"active"`
+	CurrentCommand string   `json:"current_command"`
+	Argv           []string `json:"argv,omitempty"`
+	ScrollbackFile string   `json:"scrollback_file"`
 }
 
 // Canonicalize normalises the index for stable on-disk encoding:
@@ -100,7 +124,10 @@ func EncodeIndex(idx Index) ([]byte, error) {
 
 // DecodeIndex parses a sessions.json byte payload into an Index. It returns
 // an error if the JSON is malformed, the version field is missing (zero
-// after unmarshal), or the version does not match SchemaVersion.
+// after unmarshal), or the version is outside minSchemaVersion..SchemaVersion.
+// An older document is migrated on the way in: every field added since is
+// optional and decodes to its zero value, so migration is just raising
+// Version to SchemaVersion.
 //
 // Unknown fields are silently ignored — that is the default json.Unmarshal
 // behaviour and is desirable for forward compatibility with future writers.
@@ -112,8 +139,9 @@ func DecodeIndex(data []byte) (Index, error) {
 	if idx.Version == 0 {
 		return idx, errors.New("sessions.json missing version field")
 	}
-	if idx.Version != SchemaVersion {
+	if idx.Version < minSchemaVersion || idx.Version > SchemaVersion {
 		return idx, fmt.Errorf("unsupported sessions.json version: %d (current: %d)", idx.Version, SchemaVersion)
 	}
+	idx.Version = SchemaVersion
 	return idx, nil
 }
@@ -157,9 +157,62 @@ func TestDecodeIndex_DecodesSessionsWithoutPortalIDToEmptyString(t *testing.T) {
 	}
 }
 
-func TestSchemaVersion_NotBumpedForAdditivePortalIDField(t *testing.T) {
-	if state.SchemaVersion != 1 {
-		t.Errorf("SchemaVersion must stay 1 for the additive portal_id field; got %d", state.SchemaVersion)
+func TestSchemaVersion_BumpedForPaneArgv(t *testing.T) {
+	if state.SchemaVersion != 2 {
+		t.Errorf("SchemaVersion must be 2 once panes carry argv; got %d", state.SchemaVersion)
+	}
+}
+
+func TestDecodeIndex_MigratesVersionOneDocuments(t *testing.T) {
+	raw := []byte(`{
+  "version": 1,
+  "saved_at": "2026-04-17T10:30:00Z",
+  "sessions": [
+    {
+      "name": "work",
+      "portal_id": "aB3xY9kZ",
+      "windows": [{"index": 0, "panes": [{"index": 0, "current_command": "node"}]}]
+    }
+  ]
+}`)
+
+	idx, err := state.DecodeIndex(raw)
+	if err != nil {
+		t.Fatalf("DecodeIndex: unexpected error: %v", err)
+	}
+	if idx.Version != state.SchemaVersion {
+		t.Errorf("Version = %d, want it migrated to %d", idx.Version, state.SchemaVersion)
+	}
+	pane := idx.Sessions[0].Windows[0].Panes[0]
+	if pane.CurrentCommand != "node" || pane.Argv != nil {
+		t.Errorf("pane = %+v, want current_command kept and no argv", pane)
+	}
+}
+
+func TestPaneArgv_RoundTripsAndIsOmittedWhenEmpty(t *testing.T) {
+	idx := state.Index{Sessions: []state.Session{{
+		Name: "work",
+		Windows: []state.Window{{Index: 0, Panes: []state.Pane{
+			{Index: 0, CurrentCommand: "node", Argv: []string{"node", "server.js", "--port", "3000"}},
+			{Index: 1, CurrentCommand: "zsh"},
+		}}},
+	}}}
+
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatalf("EncodeIndex: %v", err)
+	}
+	if n := bytes.Count(data, []byte(`"argv"`)); n != 1 {
+		t.Errorf("encoded %d argv fields, want 1 (omitted for the pane without one):\n%s", n, data)
+	}
+
+	got, err := state.DecodeIndex(data)
+	if err != nil {
+		t.Fatalf("DecodeIndex: %v", err)
+	}
+	want := []string{"node", "server.js", "--port", "3000"}
+	if argv := got.Sessions[0].Windows[0].Panes[0].Argv; !reflect.DeepEqual(argv, want) {
+		t.Errorf("Argv = %v, want %v", argv, want)
 	}
 }
 
@@ -238,7 +291,7 @@ func TestEncodeIndex_SerialisesEmptyPanesAsArrayNotNull(t *testing.T) {
 	}
 }
 
-func TestEncodeIndex_AlwaysSetsVersionToOne(t *testing.T) {
+func TestEncodeIndex_AlwaysSetsCurrentVersion(t *testing.T) {
 	idx := state.Index{
 		// Version intentionally zero
 		SavedAt: time.Date(2026, 4, 17, 10, 30, 0, 0, time.UTC),
@@ -262,8 +315,8 @@ func TestEncodeIndex_AlwaysSetsVersionToOne(t *testing.T) {
 	if !ok {
 		t.Fatalf("version is not a number; got %T (%v)", v, v)
 	}
-	if int(num) != 1 {
-		t.Errorf("expected version 1; got %v", num)
+	if int(num) != state.SchemaVersion {
+		t.Errorf("expected version %d; got %v", state.SchemaVersion, num)
 	}
 }
 
@@ -315,8 +368,8 @@ func TestDecodeIndex_SilentlyIgnoresUnknownFields(t *testing.T) {
 	if err != nil {
 		t.Fatalf("DecodeIndex: unexpected error: %v", err)
 	}
-	if idx.Version != 1 {
-		t.Errorf("expected version 1; got %d", idx.Version)
+	if idx.Version != state.SchemaVersion {
+		t.Errorf("expected version %d; got %d", state.SchemaVersion, idx.Version)
 	}
 }
 
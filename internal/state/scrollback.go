@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/cespare/xxhash/v2"
-	"github.com/leeovery/portal/internal/fileutil"
 )
 
 // HashMap holds the daemon's content-hash dedup state keyed by canonical
@@ -32,7 +31,8 @@ type PaneCapturer interface {
 }
 
 // SeedHashMap rebuilds the dedup map from the on-disk scrollback directory at
-// daemon startup. Reading and hashing every existing `.bin` file means the
+// daemon startup. Recovering the content hash of every existing `.bin` file
+// (from its manifest, or by hashing a legacy raw capture) means the
 // first capture cycle after a daemon restart skips every pane whose live
 // scrollback still matches what is on disk — avoiding a full rewrite of every
 // scrollback file each time the daemon restarts (which happens on every
@@ -64,12 +64,12 @@ func SeedHashMap(dir string, logger *slog.Logger) HashMap {
 		}
 		paneKey := strings.TrimSuffix(name, ".bin")
 		path := filepath.Join(sbDir, name)
-		data, err := os.ReadFile(path)
+		hash, err := scrollbackContentHash(path)
 		if err != nil {
 			logger.Warn("seed read scrollback file failed", "path", path, "error", err)
 			continue
 		}
-		hm[paneKey] = xxhash.Sum64(data)
+		hm[paneKey] = hash
 	}
 	return hm
 }
@@ -87,25 +87,27 @@ func CaptureAndHashPane(c PaneCapturer, target string) ([]byte, uint64, error) {
 }
 
 // WriteScrollbackIfChanged is the dedup-aware writer for per-pane scrollback.
-// It commits data to `scrollback/<paneKey>.bin` via fileutil.AtomicWrite0600
-// only when the supplied newHash differs from the entry already stored in hm;
-// on hit (identical hash, paneKey present) it returns (false, nil) without
-// touching disk.
+// It commits data to the chunk store, with its manifest at
+// `scrollback/<paneKey>.bin`, only when the supplied newHash differs from the
+// entry already stored in hm; on hit (identical hash, paneKey present) it
+// returns (false, nil) without touching disk. Within a write, chunks already
+// in the store (typically the unchanged prefix of a growing history) are not
+// rewritten.
 //
 // The returned bool is "did we write?" — letting callers track whether the
 // surrounding save cycle has anything to commit at the index level. On a
 // successful write, hm[paneKey] is updated to newHash so subsequent calls in
 // the same cycle (and across ticks) keep the dedup map honest.
 //
-// AtomicWrite0600 atomically writes and chmods to 0600 so the scrollback
-// file's mode does not depend on the user's umask. Errors are wrapped with
-// the paneKey for traceable failure logs.
+// Chunks and manifest go through fileutil.AtomicWrite0600, which atomically
+// writes and chmods to 0600 so their mode does not depend on the user's
+// umask. Errors are wrapped with the paneKey for traceable failure logs.
 func WriteScrollbackIfChanged(dir, paneKey string, data []byte, newHash uint64, hm HashMap) (bool, error) {
 	if existing, ok := hm[paneKey]; ok && existing == newHash {
 		return false, nil
 	}
 	path := ScrollbackFile(dir, paneKey)
-	if err := fileutil.AtomicWrite0600(path, data); err != nil {
+	if err := writeScrollbackStore(ScrollbackDir(dir), path, data, newHash); err != nil {
 		return false, fmt.Errorf("write scrollback %s: %w", paneKey, err)
 	}
 	hm[paneKey] = newHash
@@ -0,0 +1,441 @@
+package state
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/leeovery/portal/internal/fileutil"
+)
+
+// Scrollback is stored as a per-pane manifest at `scrollback/<paneKey>.bin`
+// listing gzip-compressed, content-addressed chunks under
+// `scrollback/chunks/<ab>/<sha256>.gz`. Chunk boundaries are content-defined
+// and line-aligned, so when a pane's history grows only the chunks covering
+// the new tail are written — the unchanged prefix is stored once and shared
+// across captures, panes and snapshots.
+//
+// A `.bin` that does not start with scrollbackManifestMagic is a legacy raw
+// capture. Every reader accepts both shapes; MigrateScrollback converts the
+// legacy ones at daemon startup.
+const scrollbackManifestMagic = "portal-scrollback/1\n"
+
+// Chunking parameters. A cut is taken after a line once the chunk holds at
+// least chunkMinSize bytes and the line's hash has its low bits clear (about
+// one line in chunkBoundaryMask+1), or unconditionally once it reaches
+// chunkMaxSize. Cutting only on line ends keeps boundaries stable when tmux
+// trims history from the top: the first boundary after the trim point
+// re-synchronises and every later chunk dedupes.
+const (
+	chunkMinSize      = 16 * 1024
+	chunkMaxSize      = 256 * 1024
+	chunkBoundaryMask = 0x1ff
+)
+
+// scrollbackManifest is the JSON body that follows scrollbackManifestMagic.
+// Hash is the xxhash of the full uncompressed content, carried so
+// SeedHashMap can rebuild the dedup map without decompressing anything.
+type scrollbackManifest struct {
+	Size   int64             `json:"size"`
+	Hash   uint64            `json:"hash"`
+	Chunks []scrollbackChunk `json:"chunks"`
+}
+
+// scrollbackChunk names one chunk by the sha256 of its uncompressed bytes.
+type scrollbackChunk struct {
+	Sum  string `json:"sha256"`
+	Size int    `json:"size"`
+}
+
+// splitScrollback cuts data into line-aligned, content-defined chunks. The
+// chunks alias data; a final unterminated line joins the last chunk.
+func splitScrollback(data []byte) [][]byte {
+	var chunks [][]byte
+	for start := 0; start < len(data); {
+		end := start
+		for end < len(data) {
+			nl := bytes.IndexByte(data[end:], '\n')
+			if nl < 0 {
+				end = len(data)
+				break
+			}
+			line := data[end : end+nl+1]
+			end += nl + 1
+			if end-start >= chunkMaxSize {
+				break
+			}
+			if end-start >= chunkMinSize && xxhash.Sum64(line)&chunkBoundaryMask == 0 {
+				break
+			}
+		}
+		chunks = append(chunks, data[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// chunkPath returns the store path of the chunk with the given sum, relative
+// to the scrollback directory sbDir holding the manifest that references it.
+func chunkPath(sbDir, sum string) string {
+	return filepath.Join(sbDir, scrollbackChunksSubdir, sum[:2], sum+".gz")
+}
+
+// validChunkSum reports whether sum is a lowercase hex sha256 — the only
+// shape writeScrollbackStore produces. Manifest entries are checked against
+// it before being joined into a path.
+func validChunkSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	for _, c := range sum {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// writeScrollbackStore chunks data into sbDir's store and atomically writes
+// the manifest to path. Chunks already present are left alone; new ones are
+// compressed and written via fileutil.AtomicWrite0600 before the manifest is,
+// so a manifest on disk never references a chunk that is not.
+func writeScrollbackStore(sbDir, path string, data []byte, hash uint64) error {
+	m := scrollbackManifest{Size: int64(len(data)), Hash: hash, Chunks: []scrollbackChunk{}}
+	for _, chunk := range splitScrollback(data) {
+		sumBytes := sha256.Sum256(chunk)
+		sum := hex.EncodeToString(sumBytes[:])
+		m.Chunks = append(m.Chunks, scrollbackChunk{Sum: sum, Size: len(chunk)})
+		if err := writeChunk(chunkPath(sbDir, sum), chunk); err != nil {
+			return err
+		}
+	}
+
+	body, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encode scrollback manifest: %w", err)
+	}
+	return fileutil.AtomicWrite0600(path, append([]byte(scrollbackManifestMagic), body...))
+}
+
+// writeChunk compresses chunk to path unless a chunk with the same address
+// already exists.
+func writeChunk(path string, chunk []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create chunk directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(chunk); err != nil {
+		return fmt.Errorf("compress chunk: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress chunk: %w", err)
+	}
+	if err := fileutil.AtomicWrite0600(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("write chunk: %w", err)
+	}
+	return nil
+}
+
+// decodeScrollbackManifest parses a manifest file's full contents. ok is
+// false when data is a legacy raw capture rather than a manifest.
+func decodeScrollbackManifest(data []byte) (m scrollbackManifest, ok bool, err error) {
+	body, found := bytes.CutPrefix(data, []byte(scrollbackManifestMagic))
+	if !found {
+		return scrollbackManifest{}, false, nil
+	}
+	if err := json.Unmarshal(body, &m); err != nil {
+		return scrollbackManifest{}, true, fmt.Errorf("decode scrollback manifest: %w", err)
+	}
+	for _, c := range m.Chunks {
+		if !validChunkSum(c.Sum) {
+			return scrollbackManifest{}, true, fmt.Errorf("decode scrollback manifest: invalid chunk %q", c.Sum)
+		}
+	}
+	return m, true, nil
+}
+
+// hasManifestMagic reports whether the open file f starts with
+// scrollbackManifestMagic. size is f's length, already known to the caller.
+func hasManifestMagic(f *os.File, size int64) (bool, error) {
+	if size < int64(len(scrollbackManifestMagic)) {
+		return false, nil
+	}
+	head := make([]byte, len(scrollbackManifestMagic))
+	if _, err := f.ReadAt(head, 0); err != nil {
+		return false, err
+	}
+	return string(head) == scrollbackManifestMagic, nil
+}
+
+// OpenScrollback opens the scrollback at path — a manifest or a legacy raw
+// capture — and returns a reader over its uncompressed bytes. Errors wrap
+// the underlying cause, so a missing manifest or chunk satisfies
+// errors.Is(err, fs.ErrNotExist).
+//
+// Every chunk is opened before OpenScrollback returns. A daemon commit that
+// replaces the manifest and garbage-collects its old chunks mid-read
+// therefore cannot pull bytes out from under a reader that already holds
+// them open.
+func OpenScrollback(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	isManifest, err := hasManifestMagic(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if !isManifest {
+		return f, nil
+	}
+
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := decodeScrollbackManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	files, err := openChunks(filepath.Dir(path), m)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkReader{files: files}, nil
+}
+
+// ReadScrollback returns the full uncompressed contents of the scrollback at
+// path. See OpenScrollback for the accepted shapes and error semantics.
+func ReadScrollback(path string) ([]byte, error) {
+	r, err := OpenScrollback(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
+// openChunks opens every chunk m references, in order. On failure the
+// already-opened files are closed.
+func openChunks(sbDir string, m scrollbackManifest) ([]*os.File, error) {
+	files := make([]*os.File, 0, len(m.Chunks))
+	for _, c := range m.Chunks {
+		f, err := os.Open(chunkPath(sbDir, c.Sum))
+		if err != nil {
+			for _, open := range files {
+				_ = open.Close()
+			}
+			return nil, fmt.Errorf("open scrollback chunk: %w", err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// readChunk decompresses one chunk file in full.
+func readChunk(f *os.File) ([]byte, error) {
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read scrollback chunk %s: %w", f.Name(), err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("read scrollback chunk %s: %w", f.Name(), err)
+	}
+	return data, nil
+}
+
+// chunkReader streams the decompressed chunks of a manifest back to back.
+type chunkReader struct {
+	files []*os.File
+	next  int
+	cur   *gzip.Reader
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.cur == nil {
+			if r.next >= len(r.files) {
+				return 0, io.EOF
+			}
+			f := r.files[r.next]
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				return 0, fmt.Errorf("read scrollback chunk %s: %w", f.Name(), err)
+			}
+			r.cur = zr
+		}
+		n, err := r.cur.Read(p)
+		if errors.Is(err, io.EOF) {
+			r.cur = nil
+			r.next++
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *chunkReader) Close() error {
+	var errs []error
+	for _, f := range r.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// scrollbackContentHash returns the xxhash of the uncompressed scrollback at
+// path: read from the manifest when there is one, computed from the raw
+// bytes of a legacy capture otherwise.
+func scrollbackContentHash(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	m, ok, err := decodeScrollbackManifest(data)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return xxhash.Sum64(data), nil
+	}
+	return m.Hash, nil
+}
+
+// MigrateScrollback rewrites every legacy raw `.bin` capture under dir's
+// scrollback directory into the chunk store, leaving a manifest at the same
+// path. The daemon runs it once at startup, before SeedHashMap; the manifest
+// carries the same content hash, so migration never looks like a change to
+// the dedup map.
+//
+// Best-effort like SeedHashMap: a missing scrollback directory is a silent
+// no-op, and a file that cannot be read or converted is logged at WARN and
+// left as it was — readers still accept the legacy shape.
+func MigrateScrollback(dir string, logger *slog.Logger) {
+	logger = loggerOrDiscard(logger)
+	sbDir := ScrollbackDir(dir)
+	entries, err := os.ReadDir(sbDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("migrate read scrollback dir failed", "path", sbDir, "error", err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".bin") {
+			continue
+		}
+		path := filepath.Join(sbDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("migrate read scrollback file failed", "path", path, "error", err)
+			continue
+		}
+		if bytes.HasPrefix(data, []byte(scrollbackManifestMagic)) {
+			continue
+		}
+		if err := writeScrollbackStore(sbDir, path, data, xxhash.Sum64(data)); err != nil {
+			logger.Warn("migrate scrollback file failed", "path", path, "error", err)
+			continue
+		}
+		logger.Info("scrollback migrated", "pane_key", strings.TrimSuffix(name, ".bin"), "bytes", len(data))
+	}
+}
+
+// manifestChunkSums returns the chunk sums the manifest at path references.
+// A legacy raw capture references none.
+func manifestChunkSums(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := decodeScrollbackManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	sums := make([]string, 0, len(m.Chunks))
+	for _, c := range m.Chunks {
+		sums = append(sums, c.Sum)
+	}
+	return sums, nil
+}
+
+// gcOrphanChunks removes every chunk under dir's store that no manifest in
+// dir's scrollback directory references. It runs after gcOrphanScrollback
+// has dropped the manifests idx no longer needs, so their chunks go with
+// them. A manifest that cannot be read aborts the sweep — deleting chunks
+// without knowing what it references could corrupt it.
+func gcOrphanChunks(dir string, logger *slog.Logger) error {
+	sbDir := ScrollbackDir(dir)
+	entries, err := os.ReadDir(sbDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	live := make(map[string]struct{})
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".bin") {
+			continue
+		}
+		sums, err := manifestChunkSums(filepath.Join(sbDir, name))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("read manifest %s: %w", name, err)
+		}
+		for _, s := range sums {
+			live[s] = struct{}{}
+		}
+	}
+
+	chunksDir := ScrollbackChunksDir(dir)
+	return filepath.WalkDir(chunksDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if _, ok := live[strings.TrimSuffix(d.Name(), ".gz")]; ok {
+			return nil
+		}
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("gc remove chunk failed", "path", p, "error", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,253 @@
+package state_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// scrollbackLines returns n distinct newline-terminated lines of roughly 80
+// bytes each, numbered from start, so generated histories are large enough
+// to span several chunks.
+func scrollbackLines(start, n int) string {
+	var b strings.Builder
+	for i := start; i < start+n; i++ {
+		fmt.Fprintf(&b, "\x1b[32m%06d\x1b[0m %s\n", i, strings.Repeat("x", 64))
+	}
+	return b.String()
+}
+
+// chunkFiles returns the paths of every stored chunk under dir.
+func chunkFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var out []string
+	err := filepath.WalkDir(state.ScrollbackChunksDir(dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			out = append(out, p)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	return out
+}
+
+// writeScrollback commits data for paneKey through WriteScrollbackIfChanged.
+func writeScrollback(t *testing.T, dir, paneKey, data string, hm state.HashMap) {
+	t.Helper()
+	if _, err := state.WriteScrollbackIfChanged(dir, paneKey, []byte(data), xxhash.Sum64String(data), hm); err != nil {
+		t.Fatalf("WriteScrollbackIfChanged: %v", err)
+	}
+}
+
+func TestScrollbackStore(t *testing.T) {
+	t.Run("round-trips through ReadScrollback and is not stored raw", func(t *testing.T) {
+		dir := t.TempDir()
+		data := scrollbackLines(0, 3000) + "partial prompt"
+		writeScrollback(t, dir, "work__0.0", data, state.HashMap{})
+
+		got, err := state.ReadScrollback(state.ScrollbackFile(dir, "work__0.0"))
+		if err != nil {
+			t.Fatalf("ReadScrollback: %v", err)
+		}
+		if string(got) != data {
+			t.Errorf("ReadScrollback returned %d bytes, want %d identical bytes", len(got), len(data))
+		}
+
+		raw, err := os.ReadFile(state.ScrollbackFile(dir, "work__0.0"))
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if len(raw) >= len(data) {
+			t.Errorf("manifest is %d bytes for %d bytes of scrollback; expected chunks to hold the content", len(raw), len(data))
+		}
+		if len(chunkFiles(t, dir)) < 2 {
+			t.Errorf("got %d chunks, want the history split across several", len(chunkFiles(t, dir)))
+		}
+	})
+
+	t.Run("a growing history stores its unchanged prefix once", func(t *testing.T) {
+		dir := t.TempDir()
+		hm := state.HashMap{}
+		writeScrollback(t, dir, "work__0.0", scrollbackLines(0, 3000), hm)
+		before := chunkFiles(t, dir)
+
+		writeScrollback(t, dir, "work__0.0", scrollbackLines(0, 3010), hm)
+		after := chunkFiles(t, dir)
+
+		// Only the final chunk changes; the rest are reused as-is.
+		if added := len(after) - len(before); added != 1 {
+			t.Errorf("growth added %d chunks, want 1 (before=%d after=%d)", added, len(before), len(after))
+		}
+	})
+
+	t.Run("identical histories in different panes share chunks", func(t *testing.T) {
+		dir := t.TempDir()
+		hm := state.HashMap{}
+		data := scrollbackLines(0, 2000)
+		writeScrollback(t, dir, "work__0.0", data, hm)
+		n := len(chunkFiles(t, dir))
+
+		writeScrollback(t, dir, "work__0.1", data, hm)
+
+		if got := len(chunkFiles(t, dir)); got != n {
+			t.Errorf("second pane added chunks: %d -> %d", n, got)
+		}
+	})
+
+	t.Run("an empty history round-trips as empty", func(t *testing.T) {
+		dir := t.TempDir()
+		writeScrollback(t, dir, "empty__0.0", "", state.HashMap{})
+
+		got, err := state.ReadScrollback(state.ScrollbackFile(dir, "empty__0.0"))
+		if err != nil {
+			t.Fatalf("ReadScrollback: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("ReadScrollback = %q, want empty", got)
+		}
+	})
+
+	t.Run("a missing chunk surfaces as not-exist", func(t *testing.T) {
+		dir := t.TempDir()
+		writeScrollback(t, dir, "work__0.0", scrollbackLines(0, 10), state.HashMap{})
+		for _, p := range chunkFiles(t, dir) {
+			if err := os.Remove(p); err != nil {
+				t.Fatalf("Remove: %v", err)
+			}
+		}
+
+		_, err := state.OpenScrollback(state.ScrollbackFile(dir, "work__0.0"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("OpenScrollback error = %v, want a not-exist error", err)
+		}
+	})
+
+	t.Run("TailScrollback reads the last lines from a manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		writeScrollback(t, dir, "work__0.0", scrollbackLines(0, 3000)+"partial", state.HashMap{})
+
+		got, err := state.TailScrollback(state.ScrollbackFile(dir, "work__0.0"), 5)
+		if err != nil {
+			t.Fatalf("TailScrollback: %v", err)
+		}
+		if want := scrollbackLines(2995, 5); string(got) != want {
+			t.Errorf("TailScrollback = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("SeedHashMap reads the content hash from the manifest", func(t *testing.T) {
+		dir := t.TempDir()
+		data := scrollbackLines(0, 100)
+		writeScrollback(t, dir, "work__0.0", data, state.HashMap{})
+
+		hm := state.SeedHashMap(dir, nil)
+
+		if got, want := hm["work__0.0"], xxhash.Sum64String(data); got != want {
+			t.Errorf("hm[work__0.0] = %d, want %d", got, want)
+		}
+	})
+}
+
+func TestMigrateScrollback(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	legacy := scrollbackLines(0, 500)
+	if err := os.WriteFile(state.ScrollbackFile(dir, "old__0.0"), []byte(legacy), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	writeScrollback(t, dir, "new__0.0", scrollbackLines(0, 10), state.HashMap{})
+	before := state.SeedHashMap(dir, nil)
+	logger, sink := openTempLogger(t)
+
+	state.MigrateScrollback(dir, logger)
+
+	got, err := state.ReadScrollback(state.ScrollbackFile(dir, "old__0.0"))
+	if err != nil {
+		t.Fatalf("ReadScrollback: %v", err)
+	}
+	if string(got) != legacy {
+		t.Errorf("migrated scrollback differs from the legacy bytes")
+	}
+	raw, err := os.ReadFile(state.ScrollbackFile(dir, "old__0.0"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(raw) == legacy {
+		t.Error("legacy .bin was not converted")
+	}
+	if after := state.SeedHashMap(dir, nil); after["old__0.0"] != before["old__0.0"] || after["new__0.0"] != before["new__0.0"] {
+		t.Errorf("migration changed seeded hashes: before=%v after=%v", before, after)
+	}
+	if n := strings.Count(sink.Body(), "scrollback migrated"); n != 1 {
+		t.Errorf("got %d migration breadcrumbs, want 1:\n%s", n, sink.Body())
+	}
+}
+
+func TestCommit_RemovesOrphanChunks(t *testing.T) {
+	dir := t.TempDir()
+	hm := state.HashMap{}
+	writeScrollback(t, dir, "work__0.0", scrollbackLines(0, 2000), hm)
+	writeScrollback(t, dir, "work__0.1", scrollbackLines(5000, 2000), hm)
+	kept := chunkFiles(t, dir)
+
+	// work__0.1 closes: its manifest is no longer referenced.
+	idx := makeIndex(t, "scrollback/work__0.0.bin")
+	if err := state.Commit(dir, idx, true, nil); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	remaining := chunkFiles(t, dir)
+	if len(remaining) == 0 || len(remaining) >= len(kept) {
+		t.Fatalf("chunks %d -> %d, want the closed pane's chunks removed", len(kept), len(remaining))
+	}
+	got, err := state.ReadScrollback(state.ScrollbackFile(dir, "work__0.0"))
+	if err != nil {
+		t.Fatalf("surviving pane unreadable after GC: %v", err)
+	}
+	if string(got) != scrollbackLines(0, 2000) {
+		t.Error("surviving pane content changed after GC")
+	}
+}
+
+func TestSaveSnapshot_CarriesStoredChunks(t *testing.T) {
+	dir := t.TempDir()
+	data := scrollbackLines(0, 2000)
+	seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+	writeScrollback(t, dir, "work__0.0", data, state.HashMap{})
+
+	if _, err := state.SaveSnapshot(dir, "frozen"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+	// The live pane moves on and GC drops every chunk the snapshot used.
+	writeScrollback(t, dir, "work__0.0", "cleared\n", state.HashMap{})
+	if err := state.Commit(dir, makeIndex(t, "scrollback/work__0.0.bin"), true, nil); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	idx, err := state.ReadSnapshot(dir, "frozen")
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	rel := idx.Sessions[0].Windows[0].Panes[0].ScrollbackFile
+	got, err := state.ReadScrollback(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("ReadScrollback: %v", err)
+	}
+	if string(got) != data {
+		t.Error("snapshot scrollback does not match the content at save time")
+	}
+}
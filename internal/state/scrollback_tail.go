@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"slices"
 )
 
@@ -32,13 +33,17 @@ func SetOpenFileForTest(open func(name string) (*os.File, error)) (restore func(
 }
 
 // TailScrollback returns the bytes of the last n newline-terminated lines
-// from the .bin scrollback file at path. The returned slice always ends on
+// from the .bin scrollback file at path — a chunk-store manifest or a legacy
+// raw capture. The returned slice always ends on
 // a '\n' byte and contains complete records only — any trailing bytes after
 // the final '\n' (a partial/in-progress record) are excluded.
 //
-// The implementation opens the file once, seeks to end, and reads backwards
-// in fixed-size chunks against a single held file descriptor — never closing
-// and reopening between reads. Cost is decoupled from total file size.
+// For a legacy capture the implementation opens the file once, seeks to end,
+// and reads backwards in fixed-size chunks against a single held file
+// descriptor — never closing and reopening between reads. For a manifest it
+// decompresses stored chunks from the last one backwards, stopping as soon
+// as enough lines are in hand. Either way cost is decoupled from total
+// scrollback size.
 //
 // If the file holds at least one but fewer than n terminated lines, the
 // function returns every available terminated line (no padding, no error).
@@ -70,6 +75,17 @@ func TailScrollback(path string, n int) ([]byte, error) {
 	if size == 0 {
 		return nil, nil
 	}
+	isManifest, err := hasManifestMagic(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("tail scrollback %s: %w", path, err)
+	}
+	if isManifest {
+		out, err := tailManifest(f, path, n)
+		if err != nil {
+			return nil, fmt.Errorf("tail scrollback %s: %w", path, err)
+		}
+		return out, nil
+	}
 
 	// Reverse-scan invariant: `cursor` is the absolute file offset of the
 	// next byte we have NOT yet read; `tail` holds bytes already read,
@@ -124,6 +140,53 @@ func TailScrollback(path string, n int) ([]byte, error) {
 	return tail[:last+1], nil
 }
 
+// tailManifest is TailScrollback's manifest branch: f is the open manifest
+// at path. Chunks are line-aligned, so each one prepended in turn keeps
+// tail in file order with the same cut rules as the raw reverse scan.
+func tailManifest(f *os.File, path string, n int) ([]byte, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	m, _, err := decodeScrollbackManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	files, err := openChunks(filepath.Dir(path), m)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, cf := range files {
+			_ = cf.Close()
+		}
+	}()
+
+	target := n + 1
+	var tail []byte
+	for _, cf := range slices.Backward(files) {
+		chunk, err := readChunk(cf)
+		if err != nil {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+		if bytes.Count(tail, []byte{'\n'}) >= target {
+			cut := indexOfNthNewlineFromEnd(tail, target)
+			last := bytes.LastIndexByte(tail, '\n')
+			return tail[cut+1 : last+1], nil
+		}
+	}
+
+	last := bytes.LastIndexByte(tail, '\n')
+	if last < 0 {
+		return nil, nil
+	}
+	return tail[:last+1], nil
+}
+
 // indexOfNthNewlineFromEnd returns the byte index of the n-th '\n' counting
 // backwards from the end of buf (1 = last newline, 2 = second-to-last, …).
 // Caller must guarantee bytes.Count(buf, '\n') >= n.
@@ -272,9 +272,9 @@ func TestWriteScrollbackIfChanged(t *testing.T) {
 		if hm[paneKey] != newHash {
 			t.Errorf("hm[%s] = %d, want %d", paneKey, hm[paneKey], newHash)
 		}
-		got, err := os.ReadFile(state.ScrollbackFile(dir, paneKey))
+		got, err := state.ReadScrollback(state.ScrollbackFile(dir, paneKey))
 		if err != nil {
-			t.Fatalf("ReadFile: %v", err)
+			t.Fatalf("ReadScrollback: %v", err)
 		}
 		if string(got) != string(newData) {
 			t.Errorf("file contents = %q, want %q", got, newData)
@@ -344,9 +344,9 @@ func TestWriteScrollbackIfChanged(t *testing.T) {
 		if !wrote {
 			t.Error("wrote = false, want true (first capture even when empty)")
 		}
-		got, err := os.ReadFile(state.ScrollbackFile(dir, paneKey))
+		got, err := state.ReadScrollback(state.ScrollbackFile(dir, paneKey))
 		if err != nil {
-			t.Fatalf("ReadFile: %v", err)
+			t.Fatalf("ReadScrollback: %v", err)
 		}
 		if len(got) != 0 {
 			t.Errorf("file contents = %q, want zero bytes", got)
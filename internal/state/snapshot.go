@@ -0,0 +1,351 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leeovery/portal/internal/fileutil"
+)
+
+// AutoSnapshotPrefix is the reserved name prefix for snapshots the daemon
+// takes on its own (see RunAutoSnapshots). User-named snapshots may not use
+// it, so retention pruning can never delete something the user saved by hand.
+const AutoSnapshotPrefix = "auto-"
+
+// maxSnapshotNameLen bounds a snapshot name so it always fits comfortably in a
+// single path component.
+const maxSnapshotNameLen = 64
+
+var (
+	// ErrInvalidSnapshotName is returned when a snapshot name is empty, too
+	// long, starts with a dot, or contains anything outside [A-Za-z0-9._-].
+	ErrInvalidSnapshotName = errors.New("invalid snapshot name")
+
+	// ErrSnapshotExists is returned by SaveSnapshot when a snapshot of the
+	// same name is already on disk. Snapshots are immutable once written.
+	ErrSnapshotExists = errors.New("snapshot already exists")
+
+	// ErrSnapshotNotFound is returned when the named snapshot does not exist.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrNothingToSnapshot is returned by SaveSnapshot when the state
+	// directory holds no sessions.json yet — the daemon has never committed.
+	ErrNothingToSnapshot = errors.New("no saved state to snapshot")
+)
+
+// SnapshotInfo summarises one snapshot for listing. SavedAt is the frozen
+// index's own SavedAt — the moment the daemon committed the state the
+// snapshot captured — not the time the snapshot directory was written.
+type SnapshotInfo struct {
+	Name     string
+	SavedAt  time.Time
+	Sessions int
+	Panes    int
+	Auto     bool
+}
+
+// ValidateSnapshotName reports whether name is usable as a snapshot name. The
+// name becomes a directory under snapshots/, so the character set is kept
+// deliberately narrow. allowAuto permits the reserved AutoSnapshotPrefix;
+// only the daemon's rolling snapshots pass true.
+func ValidateSnapshotName(name string, allowAuto bool) error {
+	if name == "" || len(name) > maxSnapshotNameLen || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidSnapshotName, name)
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == '-':
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidSnapshotName, name)
+		}
+	}
+	if !allowAuto && strings.HasPrefix(name, AutoSnapshotPrefix) {
+		return fmt.Errorf("%w: %q (the %q prefix is reserved for automatic snapshots)", ErrInvalidSnapshotName, name, AutoSnapshotPrefix)
+	}
+	return nil
+}
+
+// SaveSnapshot freezes the committed sessions.json and every scrollback file
+// it references into snapshots/<name>/. The snapshot is assembled in a
+// dot-prefixed temp directory and renamed into place, so a crash mid-copy
+// never leaves a half-written snapshot that ListSnapshots would surface.
+//
+// Scrollback files are hard-linked where the filesystem allows it and copied
+// otherwise. Linking is safe because the daemon only ever replaces .bin files
+// via temp+rename (fileutil.AtomicWrite0600): the live path moves to a new
+// inode while the snapshot keeps the old one. A referenced file that is
+// missing on disk (a pane the daemon has not captured yet) is skipped — the
+// hydrate helper already treats a missing file as "no scrollback".
+//
+// The name is validated with allowAuto=true; user-facing callers enforce the
+// reserved prefix themselves before calling in.
+func SaveSnapshot(dir, name string) (SnapshotInfo, error) {
+	if err := ValidateSnapshotName(name, true); err != nil {
+		return SnapshotInfo{}, err
+	}
+
+	idx, skip, err := ReadIndex(dir)
+	if skip {
+		if err != nil {
+			return SnapshotInfo{}, err
+		}
+		return SnapshotInfo{}, ErrNothingToSnapshot
+	}
+
+	final := SnapshotDir(dir, name)
+	if _, err := os.Stat(final); err == nil {
+		return SnapshotInfo{}, fmt.Errorf("%w: %s", ErrSnapshotExists, name)
+	}
+
+	if err := os.MkdirAll(SnapshotsDir(dir), 0o700); err != nil {
+		return SnapshotInfo{}, fmt.Errorf("create snapshots directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(SnapshotsDir(dir), "."+name+"-")
+	if err != nil {
+		return SnapshotInfo{}, fmt.Errorf("create snapshot temp directory: %w", err)
+	}
+
+	if err := populateSnapshot(dir, tmp, idx); err != nil {
+		_ = os.RemoveAll(tmp)
+		return SnapshotInfo{}, err
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		_ = os.RemoveAll(tmp)
+		// A concurrent save of the same name won the race to the rename.
+		if _, statErr := os.Stat(final); statErr == nil {
+			return SnapshotInfo{}, fmt.Errorf("%w: %s", ErrSnapshotExists, name)
+		}
+		return SnapshotInfo{}, fmt.Errorf("publish snapshot %s: %w", name, err)
+	}
+
+	return snapshotInfo(name, idx), nil
+}
+
+// populateSnapshot writes idx and its referenced scrollback into the
+// in-progress snapshot directory.
+func populateSnapshot(dir, tmp string, idx Index) error {
+	if err := os.MkdirAll(ScrollbackDir(tmp), 0o700); err != nil {
+		return fmt.Errorf("create snapshot scrollback directory: %w", err)
+	}
+
+	for rel := range ComputeReferencedSet(idx) {
+		if !isScrollbackRelPath(rel) {
+			continue
+		}
+		if err := linkScrollback(dir, tmp, rel); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("snapshot scrollback %s: %w", rel, err)
+		}
+	}
+
+	data, err := EncodeIndex(idx)
+	if err != nil {
+		return fmt.Errorf("encode sessions.json: %w", err)
+	}
+	if err := fileutil.AtomicWrite0600(SessionsJSON(tmp), data); err != nil {
+		return fmt.Errorf("write snapshot sessions.json: %w", err)
+	}
+	return nil
+}
+
+// linkScrollback links the scrollback at rel, and every stored chunk its
+// manifest references, from dir into the snapshot directory tmp. The
+// manifest is read back from the linked copy so the chunks match it even if
+// the daemon commits a newer manifest meanwhile. When a chunk has already
+// been collected the linked manifest is dropped again and the fs.ErrNotExist
+// surfaces, so the pane is skipped exactly like one whose scrollback is gone.
+func linkScrollback(dir, tmp, rel string) error {
+	dst := filepath.Join(tmp, filepath.FromSlash(rel))
+	if err := linkOrCopy(filepath.Join(dir, filepath.FromSlash(rel)), dst); err != nil {
+		return err
+	}
+
+	sums, err := manifestChunkSums(dst)
+	if err != nil {
+		return err
+	}
+	srcDir, dstDir := ScrollbackDir(dir), ScrollbackDir(tmp)
+	for _, sum := range sums {
+		chunkDst := chunkPath(dstDir, sum)
+		if _, err := os.Stat(chunkDst); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(chunkDst), 0o700); err != nil {
+			return err
+		}
+		if err := linkOrCopy(chunkPath(srcDir, sum), chunkDst); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				_ = os.Remove(dst)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// isScrollbackRelPath reports whether rel is a plain "scrollback/<file>"
+// reference. Anything else (absolute paths, traversal, nested directories) is
+// not something CaptureStructure produces and is never followed.
+func isScrollbackRelPath(rel string) bool {
+	if rel == "" || path.Clean(rel) != rel {
+		return false
+	}
+	d, f := path.Split(rel)
+	return d == scrollbackSubdir+"/" && f != "" && f != "." && f != ".."
+}
+
+// linkOrCopy hard-links src to dst, falling back to a byte copy when linking
+// is not possible (cross-device state dirs, filesystems without link
+// support). The copy lands with mode 0600, matching the live scrollback.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	} else if errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ReadSnapshot loads the named snapshot's index with every pane's
+// ScrollbackFile rewritten to point into the snapshot
+// ("snapshots/<name>/scrollback/<paneKey>.bin"). The rewritten paths stay
+// relative to the state directory, so the index can be handed straight to
+// the restore path, whose hydrate command joins ScrollbackFile onto the state
+// dir exactly as it does for the live sessions.json.
+//
+// A missing snapshot returns ErrSnapshotNotFound; an unreadable or
+// undecodable one returns ReadIndex's ErrCorruptIndex-wrapped error.
+func ReadSnapshot(dir, name string) (Index, error) {
+	if err := ValidateSnapshotName(name, true); err != nil {
+		return Index{}, err
+	}
+
+	idx, skip, err := ReadIndex(SnapshotDir(dir, name))
+	if skip {
+		if err != nil {
+			return Index{}, fmt.Errorf("snapshot %s: %w", name, err)
+		}
+		return Index{}, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	prefix := path.Join(snapshotsSubdir, name)
+	for si := range idx.Sessions {
+		for wi := range idx.Sessions[si].Windows {
+			panes := idx.Sessions[si].Windows[wi].Panes
+			for pi := range panes {
+				if panes[pi].ScrollbackFile == "" {
+					continue
+				}
+				panes[pi].ScrollbackFile = path.Join(prefix, panes[pi].ScrollbackFile)
+			}
+		}
+	}
+	return idx, nil
+}
+
+// ListSnapshots returns every snapshot under snapshots/, newest SavedAt first
+// (ties broken by name). In-progress temp directories are ignored. A snapshot
+// whose sessions.json cannot be read is logged at WARN and left out of the
+// listing rather than failing it — `rm` still works on it by name.
+func ListSnapshots(dir string, logger *slog.Logger) ([]SnapshotInfo, error) {
+	logger = loggerOrDiscard(logger)
+
+	entries, err := os.ReadDir(SnapshotsDir(dir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read snapshots directory: %w", err)
+	}
+
+	var out []SnapshotInfo
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		idx, skip, err := ReadIndex(SnapshotDir(dir, name))
+		if skip {
+			if err != nil {
+				logger.Warn("snapshot unreadable", "snapshot", name, "error", err)
+			}
+			continue
+		}
+		out = append(out, snapshotInfo(name, idx))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].SavedAt.Equal(out[j].SavedAt) {
+			return out[i].SavedAt.After(out[j].SavedAt)
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out, nil
+}
+
+// RemoveSnapshot deletes the named snapshot directory. A missing snapshot
+// returns ErrSnapshotNotFound.
+func RemoveSnapshot(dir, name string) error {
+	if err := ValidateSnapshotName(name, true); err != nil {
+		return err
+	}
+	target := SnapshotDir(dir, name)
+	if _, err := os.Stat(target); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+		}
+		return fmt.Errorf("stat snapshot %s: %w", name, err)
+	}
+	if err := removeAllFunc(target); err != nil {
+		return fmt.Errorf("remove snapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+// removeAllFunc is the test seam over os.RemoveAll so retention's
+// WARN-and-continue path can be driven without a real unlink failure.
+// Production always uses os.RemoveAll.
+var removeAllFunc = os.RemoveAll
+
+// snapshotInfo builds the listing summary for a decoded snapshot index.
+func snapshotInfo(name string, idx Index) SnapshotInfo {
+	info := SnapshotInfo{
+		Name:     name,
+		SavedAt:  idx.SavedAt,
+		Sessions: len(idx.Sessions),
+		Auto:     strings.HasPrefix(name, AutoSnapshotPrefix),
+	}
+	for _, s := range idx.Sessions {
+		for _, w := range s.Windows {
+			info.Panes += len(w.Panes)
+		}
+	}
+	return info
+}
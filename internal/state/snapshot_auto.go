@@ -0,0 +1,158 @@
+package state
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rolling snapshot tiers. The bucket layout is part of the name so the newest
+// snapshot of a tier sorts last lexically and "does this bucket exist yet" is
+// a single stat.
+const (
+	hourlySnapshotPrefix = AutoSnapshotPrefix + "hourly-"
+	dailySnapshotPrefix  = AutoSnapshotPrefix + "daily-"
+	hourlyBucketLayout   = "2006-01-02T15"
+	dailyBucketLayout    = "2006-01-02"
+)
+
+// ReapSnapshotPrefix names the snapshots the idle reaper takes before each
+// round of kills. It sits inside the reserved auto- prefix, so no user
+// snapshot can collide with one, and RunAutoSnapshots prunes them to
+// SnapshotRetention.Reap like the rolling tiers; the reaper saves them itself.
+const ReapSnapshotPrefix = AutoSnapshotPrefix + "reap-"
+
+// Retention defaults and caps for the rolling snapshot tiers. A day of hourly
+// snapshots plus a week of dailies covers the "I broke it this morning" and
+// "it was fine on Monday" cases without unbounded disk growth; the last ten
+// reap rounds reach back well past a typical idle threshold.
+const (
+	defaultHourlySnapshots = 24
+	defaultDailySnapshots  = 7
+	defaultReapSnapshots   = 10
+	maxHourlySnapshots     = 24 * 14
+	maxDailySnapshots      = 366
+	maxReapSnapshots       = 366
+)
+
+// SnapshotRetention is how many automatic snapshots of each tier to keep. A
+// zero Hourly or Daily count disables that tier: RunAutoSnapshots takes no new
+// snapshots in it and prunes the existing ones. Reap bounds the idle reaper's
+// pre-kill snapshots, which RunAutoSnapshots only ever prunes. The daemon
+// skips RunAutoSnapshots entirely when every count is zero, so that case
+// leaves existing snapshots on disk.
+type SnapshotRetention struct {
+	Hourly int
+	Daily  int
+	Reap   int
+}
+
+// ResolveSnapshotRetention reads PORTAL_SNAPSHOT_HOURLY, PORTAL_SNAPSHOT_DAILY
+// and PORTAL_SNAPSHOT_REAP. Unset values take the defaults (24 hourly, 7
+// daily, 10 reap); a non-integer, negative, or over-cap value falls back to
+// the default with one WARN carrying the verbatim raw value.
+func ResolveSnapshotRetention(logger *slog.Logger) SnapshotRetention {
+	logger = loggerOrDiscard(logger)
+	return SnapshotRetention{
+		Hourly: resolveSnapshotCount(logger, "PORTAL_SNAPSHOT_HOURLY", defaultHourlySnapshots, maxHourlySnapshots),
+		Daily:  resolveSnapshotCount(logger, "PORTAL_SNAPSHOT_DAILY", defaultDailySnapshots, maxDailySnapshots),
+		Reap:   resolveSnapshotCount(logger, "PORTAL_SNAPSHOT_REAP", defaultReapSnapshots, maxReapSnapshots),
+	}
+}
+
+// resolveSnapshotCount resolves one tier's retention count from env.
+func resolveSnapshotCount(logger *slog.Logger, env string, def, limit int) int {
+	raw := os.Getenv(env)
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return def
+	}
+	n, err := strconv.Atoi(trimmed)
+	if err != nil || n < 0 || n > limit {
+		logger.Warn("invalid snapshot retention", "env", env, "raw", raw, "retention", def)
+		return def
+	}
+	return n
+}
+
+// AutoSnapshotNames returns the hourly and daily bucket names for now.
+func AutoSnapshotNames(now time.Time) (hourly, daily string) {
+	return hourlySnapshotPrefix + now.Format(hourlyBucketLayout),
+		dailySnapshotPrefix + now.Format(dailyBucketLayout)
+}
+
+// RunAutoSnapshots takes the current hourly and daily rolling snapshots if
+// their buckets do not exist yet, then prunes each tier — and the reaper's
+// snapshots — down to its retention count. It is the daemon's maintenance entry point and is best-effort
+// throughout: a failed save or prune is logged WARN and the rest of the run
+// continues. Each save emits one INFO "snapshot saved"; each prune emits one
+// INFO "snapshot pruned" BEFORE the removal, so the audit line survives even
+// if the process dies mid-delete.
+//
+// A state directory with no sessions.json yet is a silent no-op for the save
+// step — there is nothing to freeze until the daemon's first commit.
+func RunAutoSnapshots(dir string, now time.Time, keep SnapshotRetention, logger *slog.Logger) {
+	logger = loggerOrDiscard(logger)
+	hourly, daily := AutoSnapshotNames(now)
+
+	tiers := []struct {
+		name   string
+		prefix string
+		keep   int
+	}{
+		{hourly, hourlySnapshotPrefix, keep.Hourly},
+		{daily, dailySnapshotPrefix, keep.Daily},
+	}
+	for _, t := range tiers {
+		if t.keep > 0 {
+			saveAutoSnapshot(dir, t.name, logger)
+		}
+		pruneAutoSnapshots(dir, t.prefix, t.keep, logger)
+	}
+	pruneAutoSnapshots(dir, ReapSnapshotPrefix, keep.Reap, logger)
+}
+
+// saveAutoSnapshot saves name unless it already exists.
+func saveAutoSnapshot(dir, name string, logger *slog.Logger) {
+	if _, err := os.Stat(SnapshotDir(dir, name)); err == nil {
+		return
+	}
+	info, err := SaveSnapshot(dir, name)
+	switch {
+	case err == nil:
+		logger.Info("snapshot saved", "snapshot", name, "sessions", info.Sessions, "panes", info.Panes)
+	case errors.Is(err, ErrNothingToSnapshot), errors.Is(err, ErrSnapshotExists):
+	default:
+		logger.Warn("snapshot save failed", "snapshot", name, "error", err)
+	}
+}
+
+// pruneAutoSnapshots removes the oldest snapshots carrying prefix until at
+// most keep remain. Bucket names sort chronologically, so "oldest" is simply
+// "lexically smallest".
+func pruneAutoSnapshots(dir, prefix string, keep int, logger *slog.Logger) {
+	entries, err := os.ReadDir(SnapshotsDir(dir))
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), prefix) {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		logger.Info("snapshot pruned", "snapshot", name, "retention", keep)
+		if err := removeAllFunc(SnapshotDir(dir, name)); err != nil {
+			logger.Warn("snapshot prune failed", "snapshot", name, "error", err)
+		}
+	}
+}
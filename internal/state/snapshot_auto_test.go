@@ -0,0 +1,165 @@
+package state_test
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/state"
+)
+
+// snapshotNames returns the sorted names of every directory under snapshots/.
+func snapshotNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(state.SnapshotsDir(dir))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestAutoSnapshotNames(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 41, 0, 0, time.UTC)
+	hourly, daily := state.AutoSnapshotNames(now)
+	if hourly != "auto-hourly-2026-10-16T09" {
+		t.Errorf("hourly = %q", hourly)
+	}
+	if daily != "auto-daily-2026-10-16" {
+		t.Errorf("daily = %q", daily)
+	}
+	for _, n := range []string{hourly, daily} {
+		if err := state.ValidateSnapshotName(n, true); err != nil {
+			t.Errorf("auto name %q fails validation: %v", n, err)
+		}
+	}
+}
+
+func TestRunAutoSnapshots(t *testing.T) {
+	t.Run("takes each bucket once", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		logger, sink := openTempLogger(t)
+		now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+		keep := state.SnapshotRetention{Hourly: 24, Daily: 7}
+
+		state.RunAutoSnapshots(dir, now, keep, logger)
+		state.RunAutoSnapshots(dir, now.Add(10*time.Minute), keep, logger)
+
+		want := []string{"auto-daily-2026-10-16", "auto-hourly-2026-10-16T09"}
+		if got := snapshotNames(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("snapshots = %v, want %v", got, want)
+		}
+		if n := strings.Count(sink.Body(), "snapshot saved"); n != 2 {
+			t.Errorf("got %d save breadcrumbs, want 2:\n%s", n, sink.Body())
+		}
+	})
+
+	t.Run("prunes each tier to its retention", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		logger, sink := openTempLogger(t)
+		keep := state.SnapshotRetention{Hourly: 2, Daily: 1}
+
+		start := time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)
+		for i := range 4 {
+			state.RunAutoSnapshots(dir, start.Add(time.Duration(i)*time.Hour), keep, logger)
+		}
+
+		want := []string{
+			"auto-daily-2026-10-17",
+			"auto-hourly-2026-10-17T00",
+			"auto-hourly-2026-10-17T01",
+		}
+		if got := snapshotNames(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("snapshots = %v, want %v", got, want)
+		}
+		if n := strings.Count(sink.Body(), "snapshot pruned"); n != 3 {
+			t.Errorf("got %d prune breadcrumbs, want 3:\n%s", n, sink.Body())
+		}
+	})
+
+	t.Run("prunes reap snapshots to their retention without taking any", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		for _, name := range []string{"20261015-090000", "20261016-090000", "20261017-090000"} {
+			if _, err := state.SaveSnapshot(dir, state.ReapSnapshotPrefix+name); err != nil {
+				t.Fatalf("SaveSnapshot: %v", err)
+			}
+		}
+
+		state.RunAutoSnapshots(dir, time.Now(), state.SnapshotRetention{Reap: 2}, nil)
+
+		want := []string{"auto-reap-20261016-090000", "auto-reap-20261017-090000"}
+		if got := snapshotNames(t, dir); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("snapshots = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("user snapshots are never pruned", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		if _, err := state.SaveSnapshot(dir, "mine"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+
+		state.RunAutoSnapshots(dir, time.Now(), state.SnapshotRetention{}, nil)
+
+		if got := snapshotNames(t, dir); len(got) != 1 || got[0] != "mine" {
+			t.Errorf("snapshots = %v, want [mine]", got)
+		}
+	})
+
+	t.Run("no committed state is a silent no-op", func(t *testing.T) {
+		dir := t.TempDir()
+		logger, sink := openTempLogger(t)
+
+		state.RunAutoSnapshots(dir, time.Now(), state.SnapshotRetention{Hourly: 1, Daily: 1}, logger)
+
+		if body := sink.Body(); body != "" {
+			t.Errorf("expected no log output, got:\n%s", body)
+		}
+	})
+}
+
+func TestResolveSnapshotRetention(t *testing.T) {
+	t.Run("defaults when unset", func(t *testing.T) {
+		t.Setenv("PORTAL_SNAPSHOT_HOURLY", "")
+		t.Setenv("PORTAL_SNAPSHOT_DAILY", "")
+		t.Setenv("PORTAL_SNAPSHOT_REAP", "")
+		got := state.ResolveSnapshotRetention(nil)
+		if got != (state.SnapshotRetention{Hourly: 24, Daily: 7, Reap: 10}) {
+			t.Errorf("retention = %+v, want {24 7 10}", got)
+		}
+	})
+
+	t.Run("env overrides, zero allowed", func(t *testing.T) {
+		t.Setenv("PORTAL_SNAPSHOT_HOURLY", "0")
+		t.Setenv("PORTAL_SNAPSHOT_DAILY", " 30 ")
+		t.Setenv("PORTAL_SNAPSHOT_REAP", "3")
+		got := state.ResolveSnapshotRetention(nil)
+		if got != (state.SnapshotRetention{Hourly: 0, Daily: 30, Reap: 3}) {
+			t.Errorf("retention = %+v, want {0 30 3}", got)
+		}
+	})
+
+	t.Run("invalid value falls back with a WARN", func(t *testing.T) {
+		t.Setenv("PORTAL_SNAPSHOT_HOURLY", "-3")
+		t.Setenv("PORTAL_SNAPSHOT_DAILY", "lots")
+		t.Setenv("PORTAL_SNAPSHOT_REAP", "")
+		logger, sink := openTempLogger(t)
+		got := state.ResolveSnapshotRetention(logger)
+		if got != (state.SnapshotRetention{Hourly: 24, Daily: 7, Reap: 10}) {
+			t.Errorf("retention = %+v, want defaults", got)
+		}
+		if n := strings.Count(sink.Body(), "invalid snapshot retention"); n != 2 {
+			t.Errorf("got %d WARNs, want 2:\n%s", n, sink.Body())
+		}
+	})
+}
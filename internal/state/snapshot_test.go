@@ -0,0 +1,290 @@
+package state_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leeovery/portal/internal/fileutil"
+	"github.com/leeovery/portal/internal/state"
+)
+
+// seedCommittedState writes idx as sessions.json under dir plus one scrollback
+// file per referenced path, each containing the path itself as its bytes.
+func seedCommittedState(t *testing.T, dir string, idx state.Index) {
+	t.Helper()
+	if err := os.MkdirAll(state.ScrollbackDir(dir), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatalf("EncodeIndex: %v", err)
+	}
+	if err := fileutil.AtomicWrite0600(state.SessionsJSON(dir), data); err != nil {
+		t.Fatalf("write sessions.json: %v", err)
+	}
+	for rel := range state.ComputeReferencedSet(idx) {
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(rel), 0o600); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+}
+
+func TestValidateSnapshotName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		allowAuto bool
+		wantErr   bool
+	}{
+		{"plain name", "before-refactor", false, false},
+		{"dots and underscores", "v1.2_rc", false, false},
+		{"empty", "", false, true},
+		{"leading dot", ".hidden", false, true},
+		{"slash", "a/b", false, true},
+		{"traversal", "..", false, true},
+		{"space", "my snap", false, true},
+		{"too long", strings.Repeat("a", 65), false, true},
+		{"reserved prefix rejected for users", "auto-mine", false, true},
+		{"reserved prefix allowed for daemon", "auto-hourly-2026-10-16T09", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := state.ValidateSnapshotName(tt.input, tt.allowAuto)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSnapshotName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, state.ErrInvalidSnapshotName) {
+				t.Errorf("error %v does not wrap ErrInvalidSnapshotName", err)
+			}
+		})
+	}
+}
+
+func TestSaveSnapshot(t *testing.T) {
+	t.Run("freezes sessions.json and referenced scrollback", func(t *testing.T) {
+		dir := t.TempDir()
+		idx := makeIndex(t, "scrollback/work__0.0.bin", "scrollback/work__0.1.bin")
+		seedCommittedState(t, dir, idx)
+
+		info, err := state.SaveSnapshot(dir, "before")
+		if err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+		if info.Sessions != 1 || info.Panes != 2 || info.Auto {
+			t.Errorf("info = %+v, want 1 session, 2 panes, not auto", info)
+		}
+
+		snap := state.SnapshotDir(dir, "before")
+		for _, rel := range []string{"scrollback/work__0.0.bin", "scrollback/work__0.1.bin"} {
+			got, err := os.ReadFile(filepath.Join(snap, rel))
+			if err != nil {
+				t.Fatalf("read snapshot %s: %v", rel, err)
+			}
+			if string(got) != rel {
+				t.Errorf("snapshot %s = %q, want %q", rel, got, rel)
+			}
+		}
+		if _, err := os.Stat(state.SessionsJSON(snap)); err != nil {
+			t.Errorf("snapshot sessions.json missing: %v", err)
+		}
+	})
+
+	t.Run("snapshot survives the live scrollback being replaced", func(t *testing.T) {
+		dir := t.TempDir()
+		idx := makeIndex(t, "scrollback/work__0.0.bin")
+		seedCommittedState(t, dir, idx)
+
+		if _, err := state.SaveSnapshot(dir, "frozen"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+		// The daemon replaces scrollback via temp+rename.
+		live := filepath.Join(dir, "scrollback/work__0.0.bin")
+		if err := fileutil.AtomicWrite0600(live, []byte("newer")); err != nil {
+			t.Fatalf("rewrite live scrollback: %v", err)
+		}
+
+		got, err := os.ReadFile(filepath.Join(state.SnapshotDir(dir, "frozen"), "scrollback/work__0.0.bin"))
+		if err != nil {
+			t.Fatalf("read snapshot scrollback: %v", err)
+		}
+		if string(got) != "scrollback/work__0.0.bin" {
+			t.Errorf("snapshot scrollback = %q, want the pre-replacement bytes", got)
+		}
+	})
+
+	t.Run("missing referenced scrollback is skipped", func(t *testing.T) {
+		dir := t.TempDir()
+		idx := makeIndex(t, "scrollback/work__0.0.bin")
+		seedCommittedState(t, dir, idx)
+		if err := os.Remove(filepath.Join(dir, "scrollback/work__0.0.bin")); err != nil {
+			t.Fatalf("Remove: %v", err)
+		}
+
+		if _, err := state.SaveSnapshot(dir, "partial"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+	})
+
+	t.Run("existing name is rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+
+		if _, err := state.SaveSnapshot(dir, "dup"); err != nil {
+			t.Fatalf("first SaveSnapshot: %v", err)
+		}
+		_, err := state.SaveSnapshot(dir, "dup")
+		if !errors.Is(err, state.ErrSnapshotExists) {
+			t.Fatalf("second SaveSnapshot error = %v, want ErrSnapshotExists", err)
+		}
+	})
+
+	t.Run("no sessions.json returns ErrNothingToSnapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := state.SaveSnapshot(dir, "empty")
+		if !errors.Is(err, state.ErrNothingToSnapshot) {
+			t.Fatalf("SaveSnapshot error = %v, want ErrNothingToSnapshot", err)
+		}
+		if _, err := os.Stat(state.SnapshotDir(dir, "empty")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("snapshot dir created despite failure: %v", err)
+		}
+	})
+
+	t.Run("leaves no temp directory behind", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		if _, err := state.SaveSnapshot(dir, "clean"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+
+		entries, err := os.ReadDir(state.SnapshotsDir(dir))
+		if err != nil {
+			t.Fatalf("ReadDir: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "clean" {
+			t.Errorf("snapshots dir entries = %v, want only [clean]", entries)
+		}
+	})
+}
+
+func TestReadSnapshot(t *testing.T) {
+	t.Run("rewrites scrollback paths into the snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+		if _, err := state.SaveSnapshot(dir, "s1"); err != nil {
+			t.Fatalf("SaveSnapshot: %v", err)
+		}
+
+		idx, err := state.ReadSnapshot(dir, "s1")
+		if err != nil {
+			t.Fatalf("ReadSnapshot: %v", err)
+		}
+		got := idx.Sessions[0].Windows[0].Panes[0].ScrollbackFile
+		want := "snapshots/s1/scrollback/work__0.0.bin"
+		if got != want {
+			t.Fatalf("ScrollbackFile = %q, want %q", got, want)
+		}
+		// The rewritten path resolves against the state dir, as the hydrate
+		// command does.
+		if _, err := os.Stat(filepath.Join(dir, got)); err != nil {
+			t.Errorf("rewritten path does not resolve: %v", err)
+		}
+	})
+
+	t.Run("missing snapshot returns ErrSnapshotNotFound", func(t *testing.T) {
+		_, err := state.ReadSnapshot(t.TempDir(), "nope")
+		if !errors.Is(err, state.ErrSnapshotNotFound) {
+			t.Fatalf("ReadSnapshot error = %v, want ErrSnapshotNotFound", err)
+		}
+	})
+
+	t.Run("corrupt snapshot returns ErrCorruptIndex", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(state.SnapshotDir(dir, "bad"), 0o700); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(state.SessionsJSON(state.SnapshotDir(dir, "bad")), []byte("{"), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		_, err := state.ReadSnapshot(dir, "bad")
+		if !errors.Is(err, state.ErrCorruptIndex) {
+			t.Fatalf("ReadSnapshot error = %v, want ErrCorruptIndex", err)
+		}
+	})
+}
+
+func TestListSnapshots(t *testing.T) {
+	dir := t.TempDir()
+
+	older := makeIndex(t, "scrollback/work__0.0.bin")
+	seedCommittedState(t, dir, older)
+	if _, err := state.SaveSnapshot(dir, "older"); err != nil {
+		t.Fatalf("SaveSnapshot older: %v", err)
+	}
+
+	newer := makeIndex(t, "scrollback/work__0.0.bin", "scrollback/work__0.1.bin")
+	newer.SavedAt = older.SavedAt.Add(time.Hour)
+	seedCommittedState(t, dir, newer)
+	if _, err := state.SaveSnapshot(dir, "auto-hourly-2026-04-27T13"); err != nil {
+		t.Fatalf("SaveSnapshot newer: %v", err)
+	}
+
+	// An unreadable snapshot is logged and left out.
+	if err := os.MkdirAll(state.SnapshotDir(dir, "broken"), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(state.SnapshotDir(dir, "broken")), []byte("nope"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	logger, sink := openTempLogger(t)
+	infos, err := state.ListSnapshots(dir, logger)
+	if err != nil {
+		t.Fatalf("ListSnapshots: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("ListSnapshots returned %d entries, want 2: %+v", len(infos), infos)
+	}
+	if infos[0].Name != "auto-hourly-2026-04-27T13" || !infos[0].Auto || infos[0].Panes != 2 {
+		t.Errorf("infos[0] = %+v, want newest auto snapshot with 2 panes", infos[0])
+	}
+	if infos[1].Name != "older" || infos[1].Auto {
+		t.Errorf("infos[1] = %+v, want older user snapshot", infos[1])
+	}
+	if !strings.Contains(sink.Body(), "snapshot unreadable") {
+		t.Errorf("expected unreadable-snapshot WARN, got:\n%s", sink.Body())
+	}
+}
+
+func TestListSnapshots_MissingDirIsEmpty(t *testing.T) {
+	infos, err := state.ListSnapshots(t.TempDir(), nil)
+	if err != nil || len(infos) != 0 {
+		t.Fatalf("ListSnapshots = (%v, %v), want (empty, nil)", infos, err)
+	}
+}
+
+func TestRemoveSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	seedCommittedState(t, dir, makeIndex(t, "scrollback/work__0.0.bin"))
+	if _, err := state.SaveSnapshot(dir, "gone"); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	if err := state.RemoveSnapshot(dir, "gone"); err != nil {
+		t.Fatalf("RemoveSnapshot: %v", err)
+	}
+	if _, err := os.Stat(state.SnapshotDir(dir, "gone")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("snapshot still present after remove: %v", err)
+	}
+	// The live scrollback the snapshot linked to is untouched.
+	if _, err := os.Stat(filepath.Join(dir, "scrollback/work__0.0.bin")); err != nil {
+		t.Errorf("live scrollback removed with snapshot: %v", err)
+	}
+
+	if err := state.RemoveSnapshot(dir, "gone"); !errors.Is(err, state.ErrSnapshotNotFound) {
+		t.Errorf("second RemoveSnapshot error = %v, want ErrSnapshotNotFound", err)
+	}
+}
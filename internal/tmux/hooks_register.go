@@ -53,6 +53,7 @@ var managedEvents = []managedEvent{
 	{event: "pane-focus-out", fingerprints: []string{notifySubstring}, desiredBody: notifyCommand},
 	{event: "client-attached", fingerprints: []string{signalHydrateMarker}, desiredBody: signalHydrateCommand},
 	{event: "client-session-changed", fingerprints: []string{signalHydrateMarker}, desiredBody: signalHydrateCommand},
+	{event: "client-detached", fingerprints: []string{fireDetachMarker}, desiredBody: fireDetachCommand},
 }
 
 // managedEventNames projects the event field out of every managedEvents
@@ -176,6 +177,15 @@ const commitNowCommand = `run-shell "command -v portal >/dev/null 2>&1 && portal
 // as a positional argument regardless of leading dashes.
 const signalHydrateCommand = `run-shell "command -v portal >/dev/null 2>&1 && portal state signal-hydrate -- #{session_name}"`
 
+// fireDetachCommand is the exact command Portal appends to `client-detached`:
+// it runs the detached session's on-detach hooks. Same defensive guard and
+// load-bearing ` -- ` separator as signalHydrateCommand.
+const fireDetachCommand = `run-shell "command -v portal >/dev/null 2>&1 && portal state fire-hooks on-detach -- #{session_name}"`
+
+// fireDetachMarker is the content fingerprint identifying a Portal-authored
+// `client-detached` hook.
+const fireDetachMarker = "portal state fire-hooks on-detach"
+
 // notifySubstring is the per-event content fingerprint used to detect a
 // previously-registered Portal save-trigger hook. Distinct from
 // signalHydrateMarker so the two categories cannot cross-contaminate.
@@ -46,8 +46,9 @@ type managedEventFingerprint struct {
 
 // managedEventFingerprints enumerates every Portal-managed event with the
 // fingerprint that identifies a Portal-authored entry on it. This mirrors the
-// nine-entry managedEvents table: six notify save-trigger events,
-// session-closed (commit-now), and the two hydration events.
+// ten-entry managedEvents table: six notify save-trigger events,
+// session-closed (commit-now), the two hydration events, and client-detached
+// (on-detach hooks).
 var managedEventFingerprints = []managedEventFingerprint{
 	{event: "session-created", fingerprint: notifyFingerprint},
 	{event: "session-closed", fingerprint: commitNowFingerprint},
@@ -58,6 +59,7 @@ var managedEventFingerprints = []managedEventFingerprint{
 	{event: "pane-focus-out", fingerprint: notifyFingerprint},
 	{event: "client-attached", fingerprint: signalHydrateFingerprint},
 	{event: "client-session-changed", fingerprint: signalHydrateFingerprint},
+	{event: "client-detached", fingerprint: fireDetachFingerprint},
 }
 
 // portalEntryCommandsForEvent reads event's hook array PER-EVENT (via the
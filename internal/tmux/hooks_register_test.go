@@ -41,6 +41,11 @@ const commitNowFingerprint = "portal state commit-now"
 // and is the single source from which expectedSignalHydrateCommand is composed.
 const signalHydrateFingerprint = "portal state signal-hydrate"
 
+// fireDetachFingerprint is the content fingerprint for the client-detached
+// on-detach hook. Mirrors fireDetachMarker in hooks_register.go and is the
+// single source from which expectedFireDetachCommand is composed.
+const fireDetachFingerprint = "portal state fire-hooks on-detach"
+
 // expectedNotifyCommand is the exact full command Portal registers on each of
 // the six non-session-closed save-trigger events. Mirrors notifyCommand in
 // hooks_register.go, composed from notifyFingerprint so the fingerprint
@@ -65,10 +70,14 @@ const expectedCommitNowCommand = `run-shell "command -v portal >/dev/null 2>&1 &
 // short-flag clusters.
 const expectedSignalHydrateCommand = `run-shell "command -v portal >/dev/null 2>&1 && ` + signalHydrateFingerprint + ` -- #{session_name}"`
 
+// expectedFireDetachCommand is the exact full command Portal registers on
+// client-detached. Mirrors fireDetachCommand in hooks_register.go.
+const expectedFireDetachCommand = `run-shell "command -v portal >/dev/null 2>&1 && ` + fireDetachFingerprint + ` -- #{session_name}"`
+
 // expectedManagedEventCount is the total number of Portal-managed events the
 // convergence engine registers on a fresh table: seven save-trigger events +
-// two hydration-trigger events.
-var expectedManagedEventCount = len(expectedSaveTriggerEvents) + len(tmux.HydrationTriggerEvents)
+// two hydration-trigger events + client-detached.
+var expectedManagedEventCount = len(expectedSaveTriggerEvents) + len(tmux.HydrationTriggerEvents) + 1
 
 // nonSessionClosedSaveTriggerEvents is the canonical save-trigger event list
 // minus session-closed — the six events that converge to notifyCommand.
@@ -274,6 +283,7 @@ func convergedTable() string {
 	for _, e := range tmux.HydrationTriggerEvents {
 		fmt.Fprintf(&b, "%s[0] => '%s'\n", e, expectedSignalHydrateCommand)
 	}
+	fmt.Fprintf(&b, "client-detached[0] => '%s'\n", expectedFireDetachCommand)
 	return b.String()
 }
 
@@ -302,6 +312,7 @@ func TestRegisterPortalHooks_FreshTable(t *testing.T) {
 	for _, ev := range tmux.HydrationTriggerEvents {
 		wantBody[ev] = expectedSignalHydrateCommand
 	}
+	wantBody["client-detached"] = expectedFireDetachCommand
 
 	seen := map[string]int{}
 	for _, c := range got {
@@ -897,8 +908,8 @@ func TestRegisterPortalHooks_NoMigrateRename(t *testing.T) {
 		if strings.Contains(c[1], "portal state migrate-rename") {
 			t.Errorf("unexpected migrate-rename registration on event %q: %q", c[0], c[1])
 		}
-		// Every body must be one of the three Portal command literals.
-		if c[1] != expectedNotifyCommand && c[1] != expectedCommitNowCommand && c[1] != expectedSignalHydrateCommand {
+		// Every body must be one of the four Portal command literals.
+		if c[1] != expectedNotifyCommand && c[1] != expectedCommitNowCommand && c[1] != expectedSignalHydrateCommand && c[1] != expectedFireDetachCommand {
 			t.Errorf("unexpected command body on event %q: %q", c[0], c[1])
 		}
 	}
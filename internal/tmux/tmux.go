@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ErrOptionNotFound is returned when a tmux server option does not exist.
@@ -26,6 +27,30 @@ var optionAbsentStderrPatterns = []string{
 	"ambiguous option:",
 }
 
+// noServerStderrPatterns lists the stderr substrings tmux uses when there is
+// no server to talk to: "no server running on <socket>" when the socket is
+// stale, "error connecting to <socket>" when it does not exist. Any other
+// failure is a real error, not an empty server.
+var noServerStderrPatterns = []string{
+	"no server running",
+	"error connecting to",
+}
+
+// isNoServer reports whether err is tmux saying there is no server, as
+// opposed to a failure talking to one.
+func isNoServer(err error) bool {
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		return false
+	}
+	for _, pat := range noServerStderrPatterns {
+		if strings.Contains(cmdErr.Stderr, pat) {
+			return true
+		}
+	}
+	return false
+}
+
 // Session represents a running tmux session.
 type Session struct {
 	Name     string
@@ -274,6 +299,62 @@ func (c *Client) ListSessionNames() ([]string, error) {
 	return names, nil
 }
 
+// SessionTimes is when a session was created, when it last saw activity
+// (tmux's session_activity), and when a client last attached to it, all at
+// tmux's one-second resolution. LastAttached is zero for a session no client
+// has ever attached to.
+type SessionTimes struct {
+	Created      time.Time
+	Activity     time.Time
+	LastAttached time.Time
+}
+
+// ListSessionTimes returns the creation, last-activity and last-attach times
+// of every running session, keyed by name. It is separate from ListSessions so the
+// common listing's format string stays untouched; only the callers that
+// filter on age pay for it. As with ListSessions, no server yields an empty
+// map rather than an error, and internal "_"-prefixed sessions are omitted.
+func (c *Client) ListSessionTimes() (map[string]SessionTimes, error) {
+	// The name is last so a name containing '|' survives the split.
+	output, err := c.cmd.Run("list-sessions", "-F", "#{session_created}|#{session_activity}|#{session_last_attached}|#{session_name}")
+	times := map[string]SessionTimes{}
+	if err != nil || output == "" {
+		return times, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 4)
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("unexpected session times format: %q", line)
+		}
+		created, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session_created %q: %w", parts[0], err)
+		}
+		activity, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session_activity %q: %w", parts[1], err)
+		}
+		// A never-attached session has no last-attach time; tmux prints it
+		// empty.
+		var lastAttached time.Time
+		if parts[2] != "" && parts[2] != "0" {
+			n, err := strconv.ParseInt(parts[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid session_last_attached %q: %w", parts[2], err)
+			}
+			lastAttached = time.Unix(n, 0)
+		}
+		if strings.HasPrefix(parts[3], "_") {
+			continue
+		}
+		times[parts[3]] = SessionTimes{Created: time.Unix(created, 0), Activity: time.Unix(activity, 0), LastAttached: lastAttached}
+	}
+	return times, nil
+}
+
 // StartServer starts the tmux server by creating a detached bootstrap session
 // with the reserved name PortalBootstrapName. Using
 // "new-session -d" instead of "start-server" guarantees the server has at
@@ -874,6 +955,97 @@ func (c *Client) ListAllPanes() ([]string, error) {
 	return parsePaneOutput(raw), nil
 }
 
+// PaneAddr is where a pane currently sits: its session name and its window and
+// pane indices.
+type PaneAddr struct {
+	Session string
+	Window  int
+	Pane    int
+}
+
+// ListPaneAddrs returns the current address of every pane on the server, keyed
+// by tmux pane id (e.g. "%12"). A pane id is fixed for the pane's lifetime
+// while its session name and indices are not, so callers that follow a pane
+// across renames and window moves key by id and re-resolve the address here.
+// No server yields an empty map — every pane is gone — but any other failure
+// is an error, so a caller that drops the panes missing from the map never
+// mistakes a transient fault for every pane closing.
+func (c *Client) ListPaneAddrs() (map[string]PaneAddr, error) {
+	// The session name is last so a name containing '|' survives the split.
+	output, err := c.cmd.Run("list-panes", "-a", "-F", "#{pane_id}|#{window_index}|#{pane_index}|#{session_name}")
+	addrs := map[string]PaneAddr{}
+	if err != nil {
+		if isNoServer(err) {
+			return addrs, nil
+		}
+		return nil, fmt.Errorf("failed to list pane addresses: %w", err)
+	}
+	if output == "" {
+		return addrs, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 4)
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("unexpected pane address format: %q", line)
+		}
+		window, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid window_index %q: %w", parts[1], err)
+		}
+		pane, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pane_index %q: %w", parts[2], err)
+		}
+		addrs[parts[0]] = PaneAddr{Session: parts[3], Window: window, Pane: pane}
+	}
+	return addrs, nil
+}
+
+// PaneCommand is one pane's foreground command as ListPaneCommands reports it:
+// the process name tmux shows (#{pane_current_command}, e.g. "aider" — or
+// "python3" for a script run through its interpreter) and the pid of the
+// pane's own process (#{pane_pid}), from which the foreground process's full
+// argv can be read.
+type PaneCommand struct {
+	Command string
+	PID     int
+}
+
+// ListPaneCommands returns the foreground command of every pane on the server,
+// keyed by pane id. As with ListPaneAddrs, no server yields an empty map and
+// any other failure an error.
+func (c *Client) ListPaneCommands() (map[string]PaneCommand, error) {
+	output, err := c.cmd.Run("list-panes", "-a", "-F", "#{pane_id}|#{pane_pid}|#{pane_current_command}")
+	commands := map[string]PaneCommand{}
+	if err != nil {
+		if isNoServer(err) {
+			return commands, nil
+		}
+		return nil, fmt.Errorf("failed to list pane commands: %w", err)
+	}
+	if output == "" {
+		return commands, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected pane command format: %q", line)
+		}
+		pid, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pane_pid %q: %w", parts[1], err)
+		}
+		commands[parts[0]] = PaneCommand{Command: parts[2], PID: pid}
+	}
+	return commands, nil
+}
+
 // ListAllPaneHookKeys is the canonical live hook-key enumeration for stale
 // cleanup: it enumerates every live pane across every tmux session and returns
 // the hook key for each one, resolved per-session by HookKeyFormat's tmux
@@ -983,6 +1155,30 @@ func (c *Client) CapturePane(target string) (string, error) {
 	return out, nil
 }
 
+// CapturePaneTail returns the visible screen of the given pane target plus up
+// to lines rows of history above it, via "tmux capture-pane -e -p -S -<lines>
+// -t <target>". Like CapturePane the output is verbatim with ANSI escapes
+// preserved; the bounded -S start keeps a repeated live capture cheap on panes
+// with deep history.
+func (c *Client) CapturePaneTail(target string, lines int) (string, error) {
+	out, err := c.cmd.RunRaw("capture-pane", "-e", "-p", "-S", fmt.Sprintf("-%d", lines), "-t", target)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane %q: %w", target, err)
+	}
+	return out, nil
+}
+
+// CapturePaneScreen returns what the given pane shows right now as plain text,
+// via "tmux capture-pane -p -t <target>": the visible screen only, with no
+// history and no escape sequences, for matching against rather than saving.
+func (c *Client) CapturePaneScreen(target string) (string, error) {
+	out, err := c.cmd.RunRaw("capture-pane", "-p", "-t", target)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture pane %q: %w", target, err)
+	}
+	return out, nil
+}
+
 // NewSessionWithCommand creates a new detached tmux session with the given
 // name. When cwd is non-empty it is passed as -c; when shellCommand is
 // non-empty it is appended as the trailing argument and becomes the pane's
@@ -1024,6 +1220,27 @@ func (c *Client) NewWindow(target, name, cwd, shellCommand string) error {
 	return nil
 }
 
+// RenameWindow renames the tmux window identified by target via
+// "tmux rename-window -t <target> <name>". tmux switches automatic-rename off
+// for a renamed window, so the name sticks while commands run in it.
+func (c *Client) RenameWindow(target, name string) error {
+	_, err := c.cmd.Run("rename-window", "-t", target, name)
+	if err != nil {
+		return fmt.Errorf("failed to rename window %q: %w", target, err)
+	}
+	return nil
+}
+
+// RunShell runs command in the background on the tmux server via
+// "tmux run-shell -b <command>", returning as soon as tmux has started it.
+func (c *Client) RunShell(command string) error {
+	_, err := c.cmd.Run("run-shell", "-b", command)
+	if err != nil {
+		return fmt.Errorf("failed to run shell command: %w", err)
+	}
+	return nil
+}
+
 // SplitWindow splits the tmux window identified by target into a new pane.
 // Optional cwd (-c) and shellCommand are appended only when non-empty.
 func (c *Client) SplitWindow(target, cwd, shellCommand string) error {
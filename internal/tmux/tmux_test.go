@@ -3,7 +3,9 @@ package tmux_test
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os/exec"
+	"slices"
 	"strings"
 	"testing"
 
@@ -1918,6 +1920,131 @@ func TestListSessionNames(t *testing.T) {
 	})
 }
 
+func TestListSessionTimes(t *testing.T) {
+	t.Run("parses created, activity and last attach per session", func(t *testing.T) {
+		mock := &MockCommander{Output: "1700000000|1700000600|1700000300|dev\n1700001000|1700002000||a|b\n1700000000|1700000000||_portal-saver"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.ListSessionTimes()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("got %v, want dev and a|b only", got)
+		}
+		if dev := got["dev"]; dev.Created.Unix() != 1700000000 || dev.Activity.Unix() != 1700000600 || dev.LastAttached.Unix() != 1700000300 {
+			t.Errorf("dev = %+v", dev)
+		}
+		if ab, ok := got["a|b"]; !ok || !ab.LastAttached.IsZero() {
+			t.Errorf("a|b = %+v, %v; want present, never attached", ab, ok)
+		}
+		if want := "list-sessions -F #{session_created}|#{session_activity}|#{session_last_attached}|#{session_name}"; strings.Join(mock.Calls[0], " ") != want {
+			t.Errorf("called with %q, want %q", strings.Join(mock.Calls[0], " "), want)
+		}
+	})
+
+	t.Run("no server yields an empty map", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Err: errors.New("no server running")})
+		got, err := client.ListSessionTimes()
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, %v; want empty, nil", got, err)
+		}
+	})
+
+	t.Run("a malformed timestamp is an error", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Output: "x|1||dev"})
+		if _, err := client.ListSessionTimes(); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestListPaneAddrs(t *testing.T) {
+	t.Run("keys each pane's address by pane id", func(t *testing.T) {
+		mock := &MockCommander{Output: "%1|0|0|dev\n%7|2|1|a|b"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.ListPaneAddrs()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]tmux.PaneAddr{"%1": {Session: "dev"}, "%7": {Session: "a|b", Window: 2, Pane: 1}}
+		if len(got) != len(want) || got["%1"] != want["%1"] || got["%7"] != want["%7"] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if want := "list-panes -a -F #{pane_id}|#{window_index}|#{pane_index}|#{session_name}"; strings.Join(mock.Calls[0], " ") != want {
+			t.Errorf("called with %q, want %q", strings.Join(mock.Calls[0], " "), want)
+		}
+	})
+
+	t.Run("no server yields an empty map", func(t *testing.T) {
+		for _, stderr := range []string{"no server running on /tmp/tmux-501/default", "error connecting to /tmp/tmux-501/default (No such file or directory)"} {
+			client := tmux.NewClient(&MockCommander{Err: &tmux.CommandError{Stderr: stderr, Err: errors.New("exit status 1")}})
+			got, err := client.ListPaneAddrs()
+			if err != nil || got == nil || len(got) != 0 {
+				t.Errorf("stderr %q: got %v, %v; want empty, nil", stderr, got, err)
+			}
+		}
+	})
+
+	t.Run("any other failure is an error", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Err: &tmux.CommandError{Stderr: "server exited unexpectedly", Err: errors.New("exit status 1")}})
+		if got, err := client.ListPaneAddrs(); err == nil || got != nil {
+			t.Errorf("got %v, %v; want nil and an error", got, err)
+		}
+	})
+
+	t.Run("a malformed index is an error", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Output: "%1|x|0|dev"})
+		if _, err := client.ListPaneAddrs(); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestListPaneCommands(t *testing.T) {
+	t.Run("keys each pane's foreground command by pane id", func(t *testing.T) {
+		mock := &MockCommander{Output: "%1|100|zsh\n%7|200|python3"}
+		client := tmux.NewClient(mock)
+
+		got, err := client.ListPaneCommands()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]tmux.PaneCommand{"%1": {Command: "zsh", PID: 100}, "%7": {Command: "python3", PID: 200}}
+		if !maps.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if want := "list-panes -a -F #{pane_id}|#{pane_pid}|#{pane_current_command}"; strings.Join(mock.Calls[0], " ") != want {
+			t.Errorf("called with %q, want %q", strings.Join(mock.Calls[0], " "), want)
+		}
+	})
+
+	t.Run("no server yields an empty map", func(t *testing.T) {
+		for _, stderr := range []string{"no server running on /tmp/tmux-501/default", "error connecting to /tmp/tmux-501/default (No such file or directory)"} {
+			client := tmux.NewClient(&MockCommander{Err: &tmux.CommandError{Stderr: stderr, Err: errors.New("exit status 1")}})
+			got, err := client.ListPaneCommands()
+			if err != nil || got == nil || len(got) != 0 {
+				t.Errorf("stderr %q: got %v, %v; want empty, nil", stderr, got, err)
+			}
+		}
+	})
+
+	t.Run("a malformed pid is an error", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Output: "%1|x|zsh"})
+		if got, err := client.ListPaneCommands(); err == nil || got != nil {
+			t.Errorf("got %v, %v; want nil and an error", got, err)
+		}
+	})
+
+	t.Run("any other failure is an error", func(t *testing.T) {
+		client := tmux.NewClient(&MockCommander{Err: &tmux.CommandError{Stderr: "server exited unexpectedly", Err: errors.New("exit status 1")}})
+		if got, err := client.ListPaneCommands(); err == nil || got != nil {
+			t.Errorf("got %v, %v; want nil and an error", got, err)
+		}
+	})
+}
+
 func TestShowEnvironment(t *testing.T) {
 	t.Run("returns raw output from show-environment for the named session", func(t *testing.T) {
 		mock := &MockCommander{Output: "LANG=en_US.UTF-8\nTERM=xterm-256color"}
@@ -2085,6 +2212,61 @@ func TestCapturePane(t *testing.T) {
 	})
 }
 
+func TestCapturePaneTail(t *testing.T) {
+	t.Run("bounds the history start with -S -<lines>", func(t *testing.T) {
+		mock := &MockCommander{
+			RunRawFunc: func(args ...string) (string, error) {
+				return "out\n", nil
+			},
+		}
+		client := tmux.NewClient(mock)
+
+		got, err := client.CapturePaneTail("=work:0.1", 1000)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != "out\n" {
+			t.Errorf("CapturePaneTail() = %q, want the raw output", got)
+		}
+		wantArgs := []string{"capture-pane", "-e", "-p", "-S", "-1000", "-t", "=work:0.1"}
+		if !slices.Equal(mock.Calls[0], wantArgs) {
+			t.Errorf("args = %v, want %v", mock.Calls[0], wantArgs)
+		}
+	})
+
+	t.Run("propagates errors with target in message", func(t *testing.T) {
+		mock := &MockCommander{
+			RunRawFunc: func(args ...string) (string, error) {
+				return "", fmt.Errorf("no server running")
+			},
+		}
+		client := tmux.NewClient(mock)
+
+		_, err := client.CapturePaneTail("=missing:0.0", 10)
+		if err == nil || !strings.Contains(err.Error(), "=missing:0.0") {
+			t.Errorf("err = %v, want a capture failure naming the target", err)
+		}
+	})
+}
+
+func TestCapturePaneScreen(t *testing.T) {
+	mock := &MockCommander{
+		RunRawFunc: func(args ...string) (string, error) {
+			return "> \n", nil
+		},
+	}
+	client := tmux.NewClient(mock)
+
+	got, err := client.CapturePaneScreen("%7")
+	if err != nil || got != "> \n" {
+		t.Errorf("CapturePaneScreen() = %q, %v; want the raw output", got, err)
+	}
+	wantArgs := []string{"capture-pane", "-p", "-t", "%7"}
+	if !slices.Equal(mock.Calls[0], wantArgs) {
+		t.Errorf("args = %v, want %v", mock.Calls[0], wantArgs)
+	}
+}
+
 func TestShowAllServerOptions(t *testing.T) {
 	t.Run("invokes show-options -s and returns output", func(t *testing.T) {
 		mock := &MockCommander{Output: "@portal-skeleton-foo__0.0 \"1\"\n@portal-restoring \"1\""}
@@ -2342,6 +2524,58 @@ func TestNewWindow(t *testing.T) {
 	})
 }
 
+func TestRenameWindow(t *testing.T) {
+	t.Run("renames the target window", func(t *testing.T) {
+		mock := &MockCommander{}
+		client := tmux.NewClient(mock)
+
+		if err := client.RenameWindow("work:", "code"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"rename-window", "-t", "work:", "code"}
+		if got := mock.Calls[0]; !slices.Equal(got, want) {
+			t.Errorf("args = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("returns wrapped error when tmux command fails", func(t *testing.T) {
+		mock := &MockCommander{Err: fmt.Errorf("tmux failed")}
+		client := tmux.NewClient(mock)
+
+		err := client.RenameWindow("work:", "code")
+		if err == nil || !strings.Contains(err.Error(), "failed to rename window") {
+			t.Errorf("err = %v, want a wrapped rename failure", err)
+		}
+	})
+}
+
+func TestRunShell(t *testing.T) {
+	t.Run("runs the command in the background", func(t *testing.T) {
+		mock := &MockCommander{}
+		client := tmux.NewClient(mock)
+
+		if err := client.RunShell("echo hi"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"run-shell", "-b", "echo hi"}
+		if got := mock.Calls[0]; !slices.Equal(got, want) {
+			t.Errorf("args = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("returns wrapped error when tmux command fails", func(t *testing.T) {
+		mock := &MockCommander{Err: fmt.Errorf("tmux failed")}
+		client := tmux.NewClient(mock)
+
+		err := client.RunShell("echo hi")
+		if err == nil || !strings.Contains(err.Error(), "failed to run shell command") {
+			t.Errorf("err = %v, want a wrapped run-shell failure", err)
+		}
+	})
+}
+
 func TestSplitWindow(t *testing.T) {
 	t.Run("splits window with cwd and shell-command", func(t *testing.T) {
 		mock := &MockCommander{}
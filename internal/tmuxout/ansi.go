@@ -0,0 +1,105 @@
+package tmuxout
+
+import "strings"
+
+// esc is the byte that opens every escape sequence capture-pane -e emits.
+const esc = 0x1b
+
+// StripANSI returns s with its terminal escape sequences removed, leaving the
+// text a reader would see. It recognises the shapes `tmux capture-pane -e`
+// produces and the ones a pane's own output can leave in saved scrollback:
+//
+//   - CSI sequences (ESC [ … final byte), covering SGR colour and attributes;
+//   - OSC sequences (ESC ] …), such as hyperlinks and titles, terminated by
+//     BEL or ST (ESC \);
+//   - DCS, SOS, PM and APC strings (ESC P / X / ^ / _ …), terminated by ST;
+//   - any other ESC-introduced sequence: optional intermediate bytes
+//     (0x20–0x2F) then one final byte, e.g. the charset designation ESC ( B.
+//
+// A sequence truncated by the end of s is dropped. Text without an ESC byte
+// is returned unchanged without allocating.
+func StripANSI(s string) string {
+	if strings.IndexByte(s, esc) < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] != esc {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		i = skipEscape(s, i)
+	}
+	return b.String()
+}
+
+// WalkSGR splits s into its visible text and its SGR (Select Graphic
+// Rendition) sequences, calling text for each run of text and sgr with the
+// parameter bytes of each ESC [ … m between them, in order. Every other escape
+// sequence is dropped exactly as StripANSI drops it, so the text runs
+// concatenate to StripANSI(s). It is the basis for renderers that carry
+// colour and attributes into another format.
+func WalkSGR(s string, text func(string), sgr func(params string)) {
+	start := 0
+	for i := 0; i < len(s); {
+		if s[i] != esc {
+			i++
+			continue
+		}
+		if start < i {
+			text(s[start:i])
+		}
+		end := skipEscape(s, i)
+		if i+2 < end && s[i+1] == '[' && s[end-1] == 'm' {
+			sgr(s[i+2 : end-1])
+		}
+		i, start = end, end
+	}
+	if start < len(s) {
+		text(s[start:])
+	}
+}
+
+// skipEscape returns the index just past the escape sequence starting at
+// s[i] (which is ESC).
+func skipEscape(s string, i int) int {
+	i++
+	if i >= len(s) {
+		return i
+	}
+	switch s[i] {
+	case '[':
+		// Parameter and intermediate bytes run until a final byte in
+		// 0x40–0x7E.
+		for i++; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return i
+	case ']':
+		return skipString(s, i+1, true)
+	case 'P', 'X', '^', '_':
+		return skipString(s, i+1, false)
+	}
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	return min(i+1, len(s))
+}
+
+// skipString returns the index just past the ST (ESC \) — or, when bel is
+// set, the BEL — terminating a control string whose body starts at s[i].
+func skipString(s string, i int, bel bool) int {
+	for ; i < len(s); i++ {
+		switch {
+		case bel && s[i] == 0x07:
+			return i + 1
+		case s[i] == esc && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		}
+	}
+	return i
+}
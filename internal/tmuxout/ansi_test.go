@@ -0,0 +1,49 @@
+package tmuxout_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/leeovery/portal/internal/tmuxout"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text is unchanged", in: "hello world", want: "hello world"},
+		{name: "removes SGR colour", in: "\x1b[31;1merror\x1b[0m: boom", want: "error: boom"},
+		{name: "removes truecolor SGR", in: "\x1b[38;2;255;158;100mhot\x1b[m", want: "hot"},
+		{name: "removes cursor movement CSI", in: "a\x1b[2Kb\x1b[10;4Hc", want: "abc"},
+		{name: "removes OSC 8 hyperlinks terminated by ST", in: "\x1b]8;;https://x.dev\x1b\\link\x1b]8;;\x1b\\", want: "link"},
+		{name: "removes OSC titles terminated by BEL", in: "\x1b]0;title\x07text", want: "text"},
+		{name: "removes DCS strings", in: "\x1bPq#0;2;0;0;0\x1b\\after", want: "after"},
+		{name: "removes charset designation", in: "\x1b(Bline", want: "line"},
+		{name: "removes two-byte escapes", in: "\x1b=keypad\x1b>", want: "keypad"},
+		{name: "preserves newlines and multibyte text", in: "\x1b[32m✓ ok\x1b[0m\n日本", want: "✓ ok\n日本"},
+		{name: "drops a sequence truncated at the end", in: "text\x1b[38;5", want: "text"},
+		{name: "drops a lone trailing ESC", in: "text\x1b", want: "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tmuxout.StripANSI(tt.in); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkSGR(t *testing.T) {
+	in := "a\x1b[1;31mred\x1b[2Kbold\x1b]0;t\x07\x1b[mz\x1b[?25h"
+	var got []string
+	tmuxout.WalkSGR(in,
+		func(s string) { got = append(got, "text:"+s) },
+		func(p string) { got = append(got, "sgr:"+p) })
+
+	want := []string{"text:a", "sgr:1;31", "text:red", "text:bold", "sgr:", "text:z"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("WalkSGR events = %q, want %q", got, want)
+	}
+}
@@ -1,5 +1,5 @@
 // Package tmuxout provides shared, dependency-free helpers for parsing
-// `tmux show-*` command output. It is a leaf package — it must not import
+// `tmux show-*` and `tmux capture-pane` output. It is a leaf package — it must not import
 // any other internal/* package — so both internal/tmux and internal/state
 // can depend on it without risking an import cycle.
 package tmuxout
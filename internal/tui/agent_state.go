@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"maps"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// agentRefreshInterval is how often the open picker re-reads agent state, so a
+// session flipping to Waiting shows without leaving and re-opening the picker.
+const agentRefreshInterval = time.Second
+
+// agentSlotWidth is the fixed width of the agent-state trailing slot: one
+// glyph plus a separating cell before the attached slot.
+const agentSlotWidth = 2
+
+// Agent-state glyphs. Each is a distinct shape as well as a distinct colour,
+// so the state survives NO_COLOR (§2.2).
+const (
+	agentWaitingGlyph = "◆"
+	agentWorkingGlyph = "◐"
+	agentIdleGlyph    = "○"
+	agentUnknownGlyph = "?"
+)
+
+// By Status group headings, most urgent first. noAgentHeading is the pinned
+// catch-all for sessions with no tracked agent.
+const (
+	needsAttentionHeading = "Needs attention"
+	workingHeading        = "Working"
+	idleHeading           = "Idle"
+	unclassifiedHeading   = "Unclassified"
+	noAgentHeading        = "No agent"
+)
+
+// AgentSource reports the coding-agent state of each live session: the most
+// urgent state among its panes. The production implementation (cmd/agent.go)
+// reads the daemon's agent.json; the seam is nil in the capture harness, which
+// leaves the glyphs and the By Status grouping empty.
+type AgentSource interface {
+	AgentStates() (map[string]agent.State, error)
+}
+
+// agentsLoadedMsg carries the result of an AgentSource.AgentStates call.
+type agentsLoadedMsg struct {
+	States map[string]agent.State
+	Err    error
+}
+
+// agentRefreshMsg is the tick that re-reads agent state while the picker is
+// open.
+type agentRefreshMsg struct{}
+
+// agentGlyph returns the glyph and colour token for a state; ok is false for
+// the zero State (no agent), which renders an empty slot.
+func agentGlyph(s agent.State) (glyph string, tok theme.Token, ok bool) {
+	switch s {
+	case agent.Waiting:
+		return agentWaitingGlyph, theme.MV.AccentOrange, true
+	case agent.Working:
+		return agentWorkingGlyph, theme.MV.AccentCyan, true
+	case agent.Idle:
+		return agentIdleGlyph, theme.MV.TextDetail, true
+	case agent.Unknown:
+		return agentUnknownGlyph, theme.MV.TextDim, true
+	}
+	return "", theme.Token{}, false
+}
+
+// agentHeading returns the By Status heading a state groups under.
+func agentHeading(s agent.State) string {
+	switch s {
+	case agent.Waiting:
+		return needsAttentionHeading
+	case agent.Working:
+		return workingHeading
+	case agent.Idle:
+		return idleHeading
+	}
+	return unclassifiedHeading
+}
+
+// fetchAgentsCmd reads the current agent states. Nil when no AgentSource is
+// wired, which also leaves the refresh tick unscheduled.
+func (m Model) fetchAgentsCmd() tea.Cmd {
+	if m.agentSource == nil {
+		return nil
+	}
+	src := m.agentSource
+	return func() tea.Msg {
+		states, err := src.AgentStates()
+		return agentsLoadedMsg{States: states, Err: err}
+	}
+}
+
+// agentRefreshTickCmd schedules the next agent-state read.
+func agentRefreshTickCmd() tea.Cmd {
+	return tea.Tick(agentRefreshInterval, func(time.Time) tea.Msg { return agentRefreshMsg{} })
+}
+
+// handleAgentsLoaded ingests an agent-state read and schedules the next. A
+// failed read keeps the previous states; an unchanged one costs no re-render.
+// By Status orders rows by state, so a change there rebuilds the list; other
+// modes only re-point the delegates at the new states.
+func (m Model) handleAgentsLoaded(msg agentsLoadedMsg) (tea.Model, tea.Cmd) {
+	next := agentRefreshTickCmd()
+	if msg.Err != nil || maps.Equal(msg.States, m.agentStates) {
+		return m, next
+	}
+	m.agentStates = msg.States
+	m.refreshProjectAgents()
+	if m.sessionListMode == prefs.ModeByStatus {
+		return m, tea.Batch(m.rebuildSessionList(), next)
+	}
+	m.refreshSessionDelegate()
+	return m, next
+}
+
+// refreshProjectAgents recomputes the per-project rollup and re-points the
+// project delegate at it. It runs whenever an input changes: the agent
+// states, the live sessions, or the project records.
+func (m *Model) refreshProjectAgents() {
+	m.projectAgents = m.rollUpProjectAgents()
+	m.projectList.SetDelegate(m.projectDelegate())
+}
+
+// rollUpProjectAgents maps each project path to the most urgent agent state
+// among the live sessions running in it, for the Projects page glyphs.
+// Sessions whose directory does not resolve to a project are left out.
+func (m *Model) rollUpProjectAgents() map[string]agent.State {
+	if len(m.agentStates) == 0 {
+		return nil
+	}
+	var tracked []tmux.Session
+	for _, s := range m.sessions {
+		if _, ok := m.agentStates[s.Name]; ok {
+			tracked = append(tracked, s)
+		}
+	}
+	out := make(map[string]agent.State)
+	for _, s := range m.resolveSessionDirs(tracked) {
+		p, _, ok := m.projectIndex.Match(s.Dir)
+		if !ok {
+			continue
+		}
+		if st := m.agentStates[s.Name]; st.Urgency() > out[p.Path].Urgency() {
+			out[p.Path] = st
+		}
+	}
+	return out
+}
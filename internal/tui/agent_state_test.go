@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// sessionLine returns the rendered session-list line holding name, stripped of
+// ANSI.
+func sessionLine(t *testing.T, m Model, name string) string {
+	t.Helper()
+	for _, line := range strings.Split(ansi.Strip(m.sessionList.View()), "\n") {
+		if strings.Contains(line, name) {
+			return line
+		}
+	}
+	t.Fatalf("no line for %q in:\n%s", name, ansi.Strip(m.sessionList.View()))
+	return ""
+}
+
+func TestHandleAgentsLoaded(t *testing.T) {
+	sessions := []tmux.Session{{Name: "alpha", Windows: 1}, {Name: "bravo", Windows: 2, Attached: true}}
+
+	t.Run("renders each session's glyph in a column-aligned slot", func(t *testing.T) {
+		m := newSwitchViewTestModel(prefs.ModeFlat, nil, sessions, nil)
+
+		updated, cmd := m.handleAgentsLoaded(agentsLoadedMsg{States: map[string]agent.State{"bravo": agent.Waiting}})
+		m = updated.(Model)
+
+		if cmd == nil {
+			t.Error("no refresh tick scheduled")
+		}
+		alpha, bravo := sessionLine(t, m, "alpha"), sessionLine(t, m, "bravo")
+		if !strings.Contains(bravo, "2 windows  "+agentWaitingGlyph+" ● attached") {
+			t.Errorf("bravo row %q lacks the waiting glyph before the attached badge", bravo)
+		}
+		if strings.ContainsAny(alpha, agentWaitingGlyph+agentWorkingGlyph+agentIdleGlyph) {
+			t.Errorf("alpha row %q has a glyph but no agent", alpha)
+		}
+		if lipgloss.Width(alpha) != lipgloss.Width(bravo) {
+			t.Errorf("row widths differ: %d vs %d", lipgloss.Width(alpha), lipgloss.Width(bravo))
+		}
+	})
+
+	t.Run("a change rebuilds By Status so a waiting session floats up", func(t *testing.T) {
+		m := newSwitchViewTestModel(prefs.ModeByStatus, nil, sessions, nil)
+
+		updated, _ := m.handleAgentsLoaded(agentsLoadedMsg{States: map[string]agent.State{"alpha": agent.Idle, "bravo": agent.Waiting}})
+		m = updated.(Model)
+
+		rows := sessionRows(m.sessionList.Items())
+		if len(rows) != 2 || rows[0].Session.Name != "bravo" || rows[0].GroupHeading != needsAttentionHeading {
+			t.Errorf("rows = %+v, want bravo first under %s", rows, needsAttentionHeading)
+		}
+	})
+
+	t.Run("a failed read keeps the previous states", func(t *testing.T) {
+		m := newSwitchViewTestModel(prefs.ModeFlat, nil, sessions, nil)
+		m.agentStates = map[string]agent.State{"bravo": agent.Working}
+
+		updated, cmd := m.handleAgentsLoaded(agentsLoadedMsg{Err: errors.New("unreadable")})
+
+		if got := updated.(Model).agentStates["bravo"]; got != agent.Working {
+			t.Errorf("bravo = %q, want the previous working state", got)
+		}
+		if cmd == nil {
+			t.Error("a failed read must still schedule the next refresh")
+		}
+	})
+}
+
+func TestRollUpProjectAgents(t *testing.T) {
+	dir := t.TempDir()
+	projects := []project.Project{{Path: dir, Name: "Portal"}}
+	sessions := []tmux.Session{
+		{Name: "portal-a", Dir: dir},
+		{Name: "portal-b", Dir: dir},
+		{Name: "stray", Dir: t.TempDir()},
+	}
+	m := newSwitchViewTestModel(prefs.ModeFlat, nil, sessions, projects)
+	m.agentStates = map[string]agent.State{"portal-a": agent.Working, "portal-b": agent.Waiting, "stray": agent.Waiting}
+
+	got := m.rollUpProjectAgents()
+
+	if len(got) != 1 || got[dir] != agent.Waiting {
+		t.Errorf("rollup = %v, want only %s waiting (its most urgent session)", got, dir)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/frecency"
 	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/resolver"
 	"github.com/leeovery/portal/internal/session"
@@ -40,11 +41,22 @@ type Deps struct {
 	ProjectEditor   ProjectEditor
 	AliasEditor     AliasEditor
 	Enumerator      TmuxEnumerator
+	Capturer        PaneCapturer
 	Reader          ScrollbackReader
 	PreviewAttacher PreviewAttacher
+	Searcher        ScrollbackSearcher
 	DirReader       session.PaneCurrentPathReader
 	DirRunner       resolver.CommandRunner
 	ModePersister   ModePersister
+	// Frecency is the frecency.json snapshot Recent mode orders sessions by.
+	// Nil (the capture harness) leaves Recent mode alphabetical.
+	Frecency []frecency.Entry
+	// Dormant lists saved-but-not-running sessions for the picker's Dormant
+	// section and resurrects one on Enter. Nil in the capture harness.
+	Dormant DormantSource
+	// Agents reports each live session's coding-agent state for the row
+	// glyphs and By Status mode. Nil in the capture harness.
+	Agents AgentSource
 	// Detector + Resolve are the async host-terminal detection seams (§6). Both are
 	// injected together by cmd/open.go (Detector = spawn.NewDetector(client), Resolve
 	// = the config-aware resolver's Resolve, loaded once from terminals.json) and
@@ -62,6 +74,9 @@ type Deps struct {
 	AckChannel    spawn.AckChannelFull
 	SpawnExe      spawn.ExecutableResolver
 	SpawnGetenv   func(string) string
+	// BurstPlacement is where the picker burst opens its external surfaces when
+	// the user has not toggled it (`open --as`). Zero means windows.
+	BurstPlacement spawn.Placement
 	// SpawnLogger is the §6-10 spawn-component logger the burst completion chokepoint
 	// emits its batch summary + per-window detail through. Injected by cmd/open.go
 	// (log.For("spawn")) and nil in the offline capture harness. Nil-tolerant: the
@@ -176,12 +191,18 @@ func Build(deps Deps) Model {
 	if deps.Enumerator != nil {
 		opts = append(opts, WithEnumerator(deps.Enumerator))
 	}
+	if deps.Capturer != nil {
+		opts = append(opts, WithPaneCapturer(deps.Capturer))
+	}
 	if deps.Reader != nil {
 		opts = append(opts, WithScrollbackReader(deps.Reader))
 	}
 	if deps.PreviewAttacher != nil {
 		opts = append(opts, WithPreviewAttachPipeline(deps.PreviewAttacher))
 	}
+	if deps.Searcher != nil {
+		opts = append(opts, WithScrollbackSearcher(deps.Searcher))
+	}
 	if deps.DirReader != nil && deps.DirRunner != nil {
 		opts = append(opts, WithDirResolver(deps.DirReader, deps.DirRunner))
 	}
@@ -201,6 +222,13 @@ func Build(deps Deps) Model {
 	if deps.ModePersister != nil {
 		opts = append(opts, WithModePersister(deps.ModePersister))
 	}
+	opts = append(opts, WithFrecency(deps.Frecency))
+	if deps.Dormant != nil {
+		opts = append(opts, WithDormantSource(deps.Dormant))
+	}
+	if deps.Agents != nil {
+		opts = append(opts, WithAgentSource(deps.Agents))
+	}
 	// Async host-terminal detection seams (§6). Always injected via nil-tolerant
 	// options — a nil Detector/Resolve leaves detection unwired, mirroring the
 	// capture harness (which passes neither).
@@ -214,6 +242,7 @@ func Build(deps Deps) Model {
 	opts = append(opts, WithAckChannel(deps.AckChannel))
 	opts = append(opts, WithSpawnExe(deps.SpawnExe))
 	opts = append(opts, WithSpawnGetenv(deps.SpawnGetenv))
+	opts = append(opts, WithBurstPlacement(deps.BurstPlacement))
 	// §6-10 spawn batch-summary logger. Always injected via the nil-tolerant option —
 	// a nil logger (the capture harness) leaves emission discarded.
 	opts = append(opts, WithSpawnLogger(deps.SpawnLogger))
@@ -234,6 +234,13 @@ func WithSpawnGetenv(fn func(string) string) Option {
 	return func(m *Model) { m.spawnGetenv = fn }
 }
 
+// WithBurstPlacement seeds the placement the multi-select burst opens its
+// external surfaces with (the `t` toggle cycles it from there). The zero value
+// is windows.
+func WithBurstPlacement(p spawn.Placement) Option {
+	return func(m *Model) { m.burstPlacement = p }
+}
+
 // burstAllConfirmed reports whether a terminal spawnCompleteMsg represents a
 // FULL-success burst (§6-4): the pre-flight passed (msg.Err == nil), every
 // external window produced a result (len == the external set), and every result
@@ -497,6 +504,9 @@ func (m Model) dispatchBurst(ordered []string) (Model, tea.Cmd) {
 		return m, nil
 	}
 	burster := spawn.NewBurster(adapter, m.ackChannel, m.spawnExe, m.spawnGetenv)
+	// An unsupported placement already flashed when `t` selected it; the burster
+	// downgrades it to windows itself (spawn.EffectivePlacement).
+	burster.Placement = m.burstPlacement
 
 	ctx, cancel := context.WithCancel(context.Background())
 	pipe := newBurstProgressPipe()
@@ -79,7 +79,7 @@ func TestByTagZeroTagsSignpost(t *testing.T) {
 		}
 	})
 
-	t.Run("advances By Tag to Flat with one s press from the signposted state", func(t *testing.T) {
+	t.Run("advances By Tag to Recent with one s press from the signposted state", func(t *testing.T) {
 		dir := t.TempDir()
 		projects := []project.Project{{Path: dir, Name: "Portal"}}
 		sessions := []tmux.Session{{Name: "portal-abc", Dir: dir}}
@@ -92,14 +92,14 @@ func TestByTagZeroTagsSignpost(t *testing.T) {
 
 		updated, _ := m.Update(keyS)
 		mm := updated.(Model)
-		if mm.sessionListMode != prefs.ModeFlat {
-			t.Errorf("sessionListMode = %v, want ModeFlat (one s advances signposted By Tag to Flat)", mm.sessionListMode)
+		if mm.sessionListMode != prefs.ModeRecent {
+			t.Errorf("sessionListMode = %v, want ModeRecent (one s advances signposted By Tag to Recent)", mm.sessionListMode)
 		}
 		if mm.byTagSignpost {
-			t.Errorf("byTagSignpost = true after advancing to Flat, want false (cleared on rebuild)")
+			t.Errorf("byTagSignpost = true after advancing to Recent, want false (cleared on rebuild)")
 		}
 		if strings.Contains(mm.View().Content, "No tags yet") {
-			t.Errorf("signpost still rendered in Flat mode:\n%s", mm.View().Content)
+			t.Errorf("signpost still rendered in Recent mode:\n%s", mm.View().Content)
 		}
 	})
 
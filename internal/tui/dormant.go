@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// dormantHeading is the label of the Dormant section that follows the live
+// sessions in every grouping mode.
+const dormantHeading = "Dormant"
+
+// DormantSession is a session Portal saved while it was running that is not
+// live in tmux now: killed, or not recreated by a restore. Windows is the
+// saved window count and SavedAt when it was last saved running.
+type DormantSession struct {
+	Name    string
+	Windows int
+	SavedAt time.Time
+}
+
+// DormantSource lists dormant sessions and resurrects one on demand. The
+// production implementation (cmd/open.go) reads sessions.json and restores
+// through the same marker-bracketed sequence as `snapshot restore`; the
+// seam is nil in the capture harness, which leaves the section unwired.
+type DormantSource interface {
+	// ListDormant returns the saved sessions whose names are not in live.
+	ListDormant(live []tmux.Session) ([]DormantSession, error)
+	// Resurrect recreates the named dormant session on the live server.
+	Resurrect(name string) error
+}
+
+// DormantItem is one row of the Dormant section. It is a distinct item type
+// (not a SessionItem) so kill, rename, preview and multi-select — all keyed
+// on a live SessionItem — pass over it; only Enter acts on it. Age is the
+// "saved 3h" label, fixed when the list is built.
+type DormantItem struct {
+	Session DormantSession
+	Age     string
+}
+
+// FilterValue returns the session name so filtering reaches dormant rows.
+func (i DormantItem) FilterValue() string {
+	return i.Session.Name
+}
+
+// dormantLoadedMsg carries the result of a DormantSource.ListDormant call.
+type dormantLoadedMsg struct {
+	Sessions []DormantSession
+	Err      error
+}
+
+// dormantResurrectedMsg reports the outcome of resurrecting Name.
+type dormantResurrectedMsg struct {
+	Name string
+	Err  error
+}
+
+// formatDormantAge renders the compact "saved <age>" label for a dormant row.
+// The longest form ("saved 99d") fits the attached slot plus right margin it
+// replaces.
+func formatDormantAge(now, savedAt time.Time) string {
+	age := now.Sub(savedAt)
+	switch {
+	case age < time.Minute:
+		return "saved now"
+	case age < time.Hour:
+		return fmt.Sprintf("saved %dm", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("saved %dh", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("saved %dd", min(int(age/(24*time.Hour)), 99))
+	}
+}
+
+// formatResurrectFailedFlash is the warning flash shown when a dormant
+// session could not be brought back.
+func formatResurrectFailedFlash(name string) string {
+	return fmt.Sprintf(`could not resurrect "%s"`, name)
+}
+
+// dormantListItems builds the Dormant section: a HeaderItem followed by one
+// DormantItem per session, or nothing when no session is dormant.
+func dormantListItems(sessions []DormantSession, now time.Time) []list.Item {
+	if len(sessions) == 0 {
+		return nil
+	}
+	items := make([]list.Item, 0, len(sessions)+1)
+	items = append(items, HeaderItem{Heading: dormantHeading, Count: len(sessions), Key: dormantHeading})
+	for _, s := range sessions {
+		items = append(items, DormantItem{Session: s, Age: formatDormantAge(now, s.SavedAt)})
+	}
+	return items
+}
+
+// fetchDormantCmd lists the dormant sessions against the current live set.
+// Nil when no DormantSource is wired.
+func (m Model) fetchDormantCmd() tea.Cmd {
+	if m.dormantSource == nil {
+		return nil
+	}
+	src, live := m.dormantSource, m.sessions
+	return func() tea.Msg {
+		sessions, err := src.ListDormant(live)
+		return dormantLoadedMsg{Sessions: sessions, Err: err}
+	}
+}
+
+// resurrectDormantCmd restores the named dormant session off the Update path.
+func (m Model) resurrectDormantCmd(name string) tea.Cmd {
+	src := m.dormantSource
+	return func() tea.Msg {
+		return dormantResurrectedMsg{Name: name, Err: src.Resurrect(name)}
+	}
+}
+
+// handleDormantLoaded ingests a dormant listing and re-renders. A failed
+// listing keeps the previous section rather than blanking it.
+func (m Model) handleDormantLoaded(msg dormantLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, nil
+	}
+	m.dormant = msg.Sessions
+	return m, m.rebuildSessionList()
+}
+
+// handleDormantResurrected attaches to a resurrected session exactly as
+// Enter on a live row does; a failure stays on the picker with a warning
+// flash and a refreshed listing.
+func (m Model) handleDormantResurrected(msg dormantResurrectedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err == nil {
+		m.selected = msg.Name
+		return m, tea.Quit
+	}
+	m.setFlash(formatResurrectFailedFlash(msg.Name))
+	return m, tea.Batch(m.fetchDormantCmd(), flashTickCmd(m.flashGen))
+}
+
+// renderDormantRow renders a Dormant section row with the session-row
+// anatomy — indent, left bar, flexing name, window count — but dimmed, and
+// with the "saved <age>" label in place of the attached badge. Rows always
+// carry the group indent: they sit under the Dormant heading in every mode.
+func (d SessionDelegate) renderDormantRow(m list.Model, index int, it DormantItem) string {
+	selected := index == m.Index() && m.FilterState() != list.Filtering
+	bg := d.rowBg(selected)
+
+	nameTok, detailTok := theme.MV.TextDetail, theme.MV.TextDim
+	if selected {
+		nameTok, detailTok = theme.MV.TextOnSelection, theme.MV.TextStrong
+	}
+
+	bar := renderLeftBarColumn(bg, d.rowToken(lipgloss.Style{}, theme.MV.AccentViolet, true), selected)
+	trailingWidth := attachedSlotWidth + rowRightMargin
+	used := leftBarColumnWidth + lipgloss.Width(groupRowIndent) + nameGap + countSlotWidth + trailingWidth
+
+	visibleName, namePad := it.Session.Name, ""
+	if total := m.Width(); total > 0 {
+		nameWidth := max(total-used, 1)
+		visibleName = ansi.Truncate(it.Session.Name, nameWidth, "…")
+		namePad = bg.Render(padTo("", nameWidth-lipgloss.Width(visibleName)))
+	}
+
+	countText := windowLabel(it.Session.Windows)
+	return bg.Render(groupRowIndent) + bar +
+		d.rowToken(lipgloss.Style{}, nameTok, selected).Render(visibleName) + namePad +
+		bg.Render(padTo("", nameGap)) +
+		d.rowToken(lipgloss.Style{}, detailTok, selected).Render(countText) +
+		bg.Render(padTo("", countSlotWidth-lipgloss.Width(countText))) +
+		d.rowToken(lipgloss.Style{}, detailTok, selected).Render(it.Age) +
+		bg.Render(padTo("", trailingWidth-lipgloss.Width(it.Age)))
+}
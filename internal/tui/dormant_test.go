@@ -0,0 +1,134 @@
+package tui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tui"
+)
+
+// fakeDormantSource is a tui.DormantSource with a fixed listing that records
+// resurrect requests.
+type fakeDormantSource struct {
+	dormant     []tui.DormantSession
+	resurrectFn func(name string) error
+	resurrected []string
+}
+
+func (f *fakeDormantSource) ListDormant(live []tmux.Session) ([]tui.DormantSession, error) {
+	return f.dormant, nil
+}
+
+func (f *fakeDormantSource) Resurrect(name string) error {
+	f.resurrected = append(f.resurrected, name)
+	if f.resurrectFn != nil {
+		return f.resurrectFn(name)
+	}
+	return nil
+}
+
+// dormantModel returns a model showing live plus src's dormant sessions: the
+// session refresh is applied, then every message its command yields — the
+// dormant listing among them.
+func dormantModel(t *testing.T, live []tmux.Session, src *fakeDormantSource) tea.Model {
+	t.Helper()
+	var model tea.Model = tui.New(&mockSessionLister{sessions: live}, tui.WithDormantSource(src))
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	model, cmd := model.Update(tui.SessionsMsg{Sessions: live})
+	for _, msg := range flattenInitMsgs(cmd) {
+		model, _ = model.Update(msg)
+	}
+	model, _ = model.Update(tui.ProjectsLoadedMsg{})
+	return model
+}
+
+func TestDormantSection(t *testing.T) {
+	live := []tmux.Session{{Name: "dev", Windows: 2}}
+	twoHoursAgo := time.Now().Add(-2*time.Hour - time.Minute)
+
+	t.Run("dormant sessions render under their own heading after the live rows", func(t *testing.T) {
+		src := &fakeDormantSource{dormant: []tui.DormantSession{{Name: "old-work", Windows: 1, SavedAt: twoHoursAgo}}}
+		model := dormantModel(t, live, src)
+
+		view := plainView(model)
+		for _, want := range []string{"Dormant", "old-work", "saved 2h"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("view missing %q:\n%s", want, view)
+			}
+		}
+		if strings.Index(view, "dev") > strings.Index(view, "Dormant") {
+			t.Errorf("live row should precede the Dormant section:\n%s", view)
+		}
+	})
+
+	t.Run("no section without dormant sessions", func(t *testing.T) {
+		model := dormantModel(t, live, &fakeDormantSource{})
+
+		if strings.Contains(plainView(model), "Dormant") {
+			t.Errorf("unexpected Dormant heading:\n%s", plainView(model))
+		}
+	})
+
+	t.Run("enter on a dormant row resurrects it and attaches", func(t *testing.T) {
+		src := &fakeDormantSource{dormant: []tui.DormantSession{{Name: "old-work", Windows: 1, SavedAt: twoHoursAgo}}}
+		model := dormantModel(t, live, src)
+
+		model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("enter on a dormant row returned no command")
+		}
+		model, quit := model.Update(cmd())
+
+		if len(src.resurrected) != 1 || src.resurrected[0] != "old-work" {
+			t.Fatalf("resurrected = %v, want [old-work]", src.resurrected)
+		}
+		if got := model.(tui.Model).Selected(); got != "old-work" {
+			t.Errorf("Selected() = %q, want old-work", got)
+		}
+		if quit == nil {
+			t.Error("expected the picker to quit after resurrecting")
+		}
+	})
+
+	t.Run("a failed resurrect stays on the picker with a warning", func(t *testing.T) {
+		src := &fakeDormantSource{
+			dormant:     []tui.DormantSession{{Name: "old-work", Windows: 1, SavedAt: twoHoursAgo}},
+			resurrectFn: func(string) error { return errors.New("boom") },
+		}
+		model := dormantModel(t, live, src)
+
+		model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+		model, _ = model.Update(cmd())
+
+		if got := model.(tui.Model).Selected(); got != "" {
+			t.Errorf("Selected() = %q, want nothing selected", got)
+		}
+		if view := plainView(model); !strings.Contains(view, `could not resurrect "old-work"`) {
+			t.Errorf("view missing the failure flash:\n%s", view)
+		}
+	})
+
+	t.Run("kill ignores a dormant row", func(t *testing.T) {
+		src := &fakeDormantSource{dormant: []tui.DormantSession{{Name: "old-work", Windows: 1, SavedAt: twoHoursAgo}}}
+		model := dormantModel(t, live, src)
+
+		model, _ = model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		model, _ = model.Update(tea.KeyPressMsg{Code: 'k', Text: "k"})
+
+		if view := plainView(model); strings.Contains(view, "Kill session?") {
+			t.Errorf("k on a dormant row opened the kill confirm:\n%s", view)
+		}
+	})
+}
+
+// plainView renders model's view with escape sequences stripped.
+func plainView(model tea.Model) string {
+	return ansi.Strip(model.View().Content)
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"charm.land/bubbles/v2/list"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/fuzzy"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// rankedFilter is the list.FilterFunc both the Sessions and Projects lists
+// filter through: fuzzy.Find scores every FilterValue against the typed term,
+// so the best match (a consecutive, word-aligned hit) lists first, and each
+// Rank carries the matched rune positions the delegates highlight. Positions are
+// RUNE indexes into the FilterValue — the item name — which is exactly the text
+// the delegates render in the name column.
+func rankedFilter(term string, targets []string) []list.Rank {
+	hits := fuzzy.Find(term, targets)
+	ranks := make([]list.Rank, len(hits))
+	for i, h := range hits {
+		ranks[i] = list.Rank{Index: h.Index, MatchedIndexes: h.Positions}
+	}
+	return ranks
+}
+
+// renderMatchedName renders a row's name column with the runes at matches
+// highlighted. name is the already-truncated visible name: a match past the
+// truncation point, or on the trailing ellipsis that replaced it, draws nothing.
+//
+// base is the row's name style (token + row background). A matched rune is
+// underlined and, off the selected row, recoloured accent.orange — the filter
+// accent the query and footer use. The selected row keeps its on-selection
+// name colour for matched runes (accent.orange does not clear the 4.5 floor on
+// the light bg.selection tint), so there the underline alone marks the match;
+// it also keeps matches visible under the NO_COLOR carve-out, where every hue
+// drops. With no matches the name renders through base unchanged, byte-for-byte
+// what it was before filtering.
+func renderMatchedName(name string, truncated bool, matches []int, base lipgloss.Style, mode theme.Mode, selected, colourless bool) string {
+	if len(matches) == 0 {
+		return base.Render(name)
+	}
+	hi := base.Underline(true)
+	if !selected && !colourless {
+		hi = hi.Foreground(theme.MV.AccentOrange.ColorFor(mode))
+	}
+
+	runes := []rune(name)
+	limit := len(runes)
+	if truncated {
+		limit--
+	}
+	var visible []int
+	for _, idx := range matches {
+		if idx >= 0 && idx < limit {
+			visible = append(visible, idx)
+		}
+	}
+	if len(visible) == 0 {
+		return base.Render(name)
+	}
+	return lipgloss.StyleRunes(name, visible, hi, base)
+}
+
+// truncateName clips name to width cells with the §2.7 ellipsis, reporting
+// whether it was clipped so renderMatchedName can keep the ellipsis plain.
+func truncateName(name string, width int) (string, bool) {
+	visible := ansi.Truncate(name, width, "…")
+	return visible, visible != name
+}
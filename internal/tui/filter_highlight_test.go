@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/list"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/tmux"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// filter_highlight_test.go pins the ranked filter: matches list best-first in
+// both the Sessions and Projects lists, and the delegates draw the matched
+// runes of the name in the filter accent (underlined, so the selected row and
+// NO_COLOR still show them) without disturbing the row's visible text.
+
+// orangeFG is the truecolor foreground SGR fragment for dark accent.orange
+// (#FF9E64).
+const orangeFG = "38;2;255;158;100"
+
+func TestRankedFilter(t *testing.T) {
+	targets := []string{"a-long-project-impl", "nothing", "api-xyz"}
+
+	ranks := rankedFilter("api", targets)
+
+	if len(ranks) != 2 || ranks[0].Index != 2 || ranks[1].Index != 0 {
+		t.Fatalf("rankedFilter(api) = %+v, want api-xyz then a-long-project-impl", ranks)
+	}
+	if !slices.Equal(ranks[0].MatchedIndexes, []int{0, 1, 2}) {
+		t.Errorf("api-xyz matched indexes = %v, want [0 1 2]", ranks[0].MatchedIndexes)
+	}
+}
+
+func TestSessionListFilter_RanksBestMatchFirst(t *testing.T) {
+	m := NewModelWithSessions([]tmux.Session{
+		{Name: "a-long-project-impl", Windows: 1},
+		{Name: "api-xyz", Windows: 1},
+	})
+
+	m.SetSessionListFilter("api")
+
+	var got []string
+	for _, it := range m.SessionListVisibleItems() {
+		got = append(got, it.(SessionItem).Session.Name)
+	}
+	if want := []string{"api-xyz", "a-long-project-impl"}; !slices.Equal(got, want) {
+		t.Errorf("filtered order = %v, want %v", got, want)
+	}
+}
+
+func TestRenderMatchedName(t *testing.T) {
+	base := lipgloss.NewStyle()
+
+	t.Run("no matches renders through the base style unchanged", func(t *testing.T) {
+		if got, want := renderMatchedName("portal", false, nil, base, theme.Dark, false, false), base.Render("portal"); got != want {
+			t.Errorf("renderMatchedName = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("matched runes take the filter accent off the selected row", func(t *testing.T) {
+		got := renderMatchedName("portal", false, []int{0, 1}, base, theme.Dark, false, false)
+		if ansi.Strip(got) != "portal" {
+			t.Errorf("visible text = %q, want portal", ansi.Strip(got))
+		}
+		if !strings.Contains(got, orangeFG) {
+			t.Errorf("renderMatchedName = %q, want matched runes in accent.orange", got)
+		}
+	})
+
+	t.Run("the selected row and NO_COLOR underline without the accent", func(t *testing.T) {
+		for _, tc := range []struct {
+			name                 string
+			selected, colourless bool
+		}{
+			{"selected", true, false},
+			{"colourless", false, true},
+		} {
+			got := renderMatchedName("portal", false, []int{0}, base, theme.Dark, tc.selected, tc.colourless)
+			if strings.Contains(got, orangeFG) {
+				t.Errorf("%s: renderMatchedName = %q, want no accent.orange", tc.name, got)
+			}
+			if got == base.Render("portal") {
+				t.Errorf("%s: renderMatchedName = %q, want the match underlined", tc.name, got)
+			}
+		}
+	})
+
+	t.Run("matches past the truncation point are dropped", func(t *testing.T) {
+		visible, truncated := truncateName("portal-web", 5)
+		got := renderMatchedName(visible, truncated, []int{4, 7}, base, theme.Dark, false, false)
+		if got != base.Render(visible) {
+			t.Errorf("renderMatchedName = %q, want the ellipsis and clipped runes unhighlighted", got)
+		}
+	})
+}
+
+func TestProjectRow_HighlightsMatchedName(t *testing.T) {
+	d := ProjectDelegate{Mode: theme.Dark}
+	l := list.New([]list.Item{
+		ProjectItem{Project: project.Project{Name: "portal", Path: "/code/portal"}},
+		ProjectItem{Project: project.Project{Name: "portal-web", Path: "/code/portal-web"}},
+	}, d, 60, 10)
+	l.Filter = rankedFilter
+	l.SetFilterText("ptl")
+	l.SetFilterState(list.FilterApplied)
+
+	// Row 1 is off the cursor, so its matches take the accent colour.
+	var buf bytes.Buffer
+	d.Render(&buf, l, 1, l.VisibleItems()[1])
+
+	name, path, _ := strings.Cut(buf.String(), "\n")
+	if !strings.Contains(name, orangeFG) {
+		t.Errorf("name line = %q, want the matched runes in accent.orange", name)
+	}
+	if strings.Contains(path, orangeFG) {
+		t.Errorf("path line = %q, want no highlight on the path", path)
+	}
+}
@@ -112,7 +112,7 @@ func commandPendingFooterEntries() []filterFooterEntry {
 // by the delivered Paper frame (design/sessions-multi-select-active.png) and the spec
 // (Multi-Select Mode → Mode affordance):
 //
-//	↑↓ navigate · m toggle · ␣ preview · ⏎ open · esc cancel
+//	↑↓ navigate · m toggle · ␣ preview · t placement · ⏎ open · esc cancel
 //
 // Sourced once here as named constants (mirroring commandBandText) so the wording
 // can't drift from a paraphrase. The glyphs are the codebase canon: nav ↑↓, preview ␣
@@ -126,10 +126,14 @@ const (
 	multiSelectToggleLabel  = "toggle"
 	multiSelectPreviewGlyph = "␣" // U+2423, the sessionsKeymap preview glyph
 	multiSelectPreviewLabel = "preview"
-	multiSelectOpenGlyph    = "⏎" // U+23CE, the sessionsKeymap enter/attach glyph
-	multiSelectOpenLabel    = "open"
-	multiSelectCancelGlyph  = "esc"
-	multiSelectCancelLabel  = "cancel"
+	// The burst placement toggle (windows → tabs → splits) sits beside open: it
+	// changes what ⏎ does, not which rows are marked.
+	multiSelectPlacementGlyph = "t"
+	multiSelectPlacementLabel = "placement"
+	multiSelectOpenGlyph      = "⏎" // U+23CE, the sessionsKeymap enter/attach glyph
+	multiSelectOpenLabel      = "open"
+	multiSelectCancelGlyph    = "esc"
+	multiSelectCancelLabel    = "cancel"
 )
 
 // multiSelectFooterText is the spec-exact §5 mode-footer copy assembled from the
@@ -138,6 +142,7 @@ const (
 const multiSelectFooterText = multiSelectNavGlyph + footerKeyLabelGap + multiSelectNavLabel +
 	footerEntrySeparator + multiSelectToggleGlyph + footerKeyLabelGap + multiSelectToggleLabel +
 	footerEntrySeparator + multiSelectPreviewGlyph + footerKeyLabelGap + multiSelectPreviewLabel +
+	footerEntrySeparator + multiSelectPlacementGlyph + footerKeyLabelGap + multiSelectPlacementLabel +
 	footerEntrySeparator + multiSelectOpenGlyph + footerKeyLabelGap + multiSelectOpenLabel +
 	footerEntrySeparator + multiSelectCancelGlyph + footerKeyLabelGap + multiSelectCancelLabel
 
@@ -150,12 +155,13 @@ func multiSelectFooterEntries() []filterFooterEntry {
 		{Key: []keyGlyph{{multiSelectNavGlyph, theme.MV.AccentBlue}}, Label: multiSelectNavLabel},
 		{Key: []keyGlyph{{multiSelectToggleGlyph, theme.MV.AccentBlue}}, Label: multiSelectToggleLabel},
 		{Key: []keyGlyph{{multiSelectPreviewGlyph, theme.MV.AccentBlue}}, Label: multiSelectPreviewLabel},
+		{Key: []keyGlyph{{multiSelectPlacementGlyph, theme.MV.AccentBlue}}, Label: multiSelectPlacementLabel},
 		{Key: []keyGlyph{{multiSelectOpenGlyph, theme.MV.AccentBlue}}, Label: multiSelectOpenLabel},
 		{Key: []keyGlyph{{multiSelectCancelGlyph, theme.MV.AccentBlue}}, Label: multiSelectCancelLabel},
 	}
 }
 
-// renderMultiSelectFooter renders the §5 multi-select mode footer: the five spec-exact
+// renderMultiSelectFooter renders the §5 multi-select mode footer: the six
 // entries as a dot-separated left cluster over the shared 1px border.footer top rule,
 // with NO right-aligned `? help` anchor (the delivered frame has none). It reuses
 // renderFilterCluster (via fitFilterCluster) for the cluster body so the dot
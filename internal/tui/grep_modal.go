@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"charm.land/bubbles/v2/textinput"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// The find-in-scrollback prompt opened by `f` on the Sessions page. It is the
+// rename modal's shape with a different field: the same shared joined panel
+// (header / body / footer), the same always-editing orange input box, and a
+// detail line under the box explaining how the pattern is matched. Submitting
+// runs the ScrollbackSearcher and opens the results page (pagegrep.go).
+
+const (
+	// grepModalTitle is the header title text (text.primary).
+	grepModalTitle = "Find in scrollback"
+	// grepFieldLabel is the accent.violet label above the pattern input.
+	grepFieldLabel = "PATTERN"
+	// grepModalHint is the text.detail line under the input box.
+	grepModalHint = "regexp · case-insensitive unless it has capitals"
+
+	// Footer copy, matching the rename modal's glyph/label pairing.
+	grepKeyConfirm   = "⏎"
+	grepLabelConfirm = "search"
+	grepKeyCancel    = "esc"
+	grepLabelCancel  = "cancel"
+)
+
+// renderGrepModalContent composes the find prompt for the given input:
+//
+//	header:  Find in scrollback          ◉ EDIT MODE
+//	body:    PATTERN
+//	         ╭──────────────────────╮
+//	         │ <pattern>▌           │
+//	         ╰──────────────────────╯
+//	         regexp · case-insensitive unless it has capitals
+//	footer:  ⏎ search   esc cancel
+//
+// The input box shares the rename modal's width so the two prompts are the same
+// size on screen.
+func renderGrepModalContent(input textinput.Model, mode theme.Mode, colourless bool) string {
+	title := headerStyle(theme.MV.TextPrimary, mode, colourless).Bold(true).Render(grepModalTitle)
+	header := []string{renderHeaderWithBadge(title, renamePanelContentWidth(), true, mode, colourless)}
+
+	body := []string{headerStyle(theme.MV.AccentViolet, mode, colourless).Render(grepFieldLabel)}
+	body = append(body, renderInputBox(renameInputView(input, mode, colourless), inputBoxEditing, true, renameInputInnerWidth, mode, colourless)...)
+	body = append(body, headerStyle(theme.MV.TextDetail, mode, colourless).Render(grepModalHint))
+
+	footer := []string{renderConfirmCancelFooter(grepKeyConfirm, grepLabelConfirm, grepKeyCancel, grepLabelCancel, mode, colourless)}
+	return renderJoinedPanel([][]string{header, body, footer}, theme.MV.BorderSeparator, mode, colourless)
+}
@@ -3,8 +3,11 @@ package tui
 import (
 	"cmp"
 	"slices"
+	"time"
 
 	"charm.land/bubbles/v2/list"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/frecency"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/tmux"
 )
@@ -117,28 +120,12 @@ func buildByTag(sessions []tmux.Session, idx project.Index) []list.Item {
 }
 
 // resolveSessionTags returns the canonical, usable tags for a session's
-// directory. It resolves the session's project (project miss or empty Dir yields
-// no tags) and defensively re-normalises each stored tag through
-// project.NormaliseTag, dropping any that fail (empty/whitespace junk). The
-// result is the set of tags under which the session should appear; an empty
-// result routes the session to the Untagged catch-all.
+// directory via project.Index.Tags (project miss or empty Dir yields no tags;
+// junk stored tags are dropped). The result is the set of tags under which the
+// session should appear; an empty result routes the session to the Untagged
+// catch-all.
 func resolveSessionTags(s tmux.Session, idx project.Index) []string {
-	if s.Dir == "" {
-		return nil
-	}
-
-	matched, _, ok := idx.Match(s.Dir)
-	if !ok {
-		return nil
-	}
-
-	var tags []string
-	for _, raw := range matched.Tags {
-		if tag, ok := project.NormaliseTag(raw); ok {
-			tags = append(tags, tag)
-		}
-	}
-	return tags
+	return idx.Tags(s.Dir)
 }
 
 // untaggedItem builds the catch-all SessionItem for a session that has no usable
@@ -262,3 +249,59 @@ func sessionItemsToList(items []SessionItem) []list.Item {
 func assembleGroups(resolved []SessionItem, catchAll []SessionItem, heading string) []list.Item {
 	return injectGroupHeaders(orderedSessionItems(resolved, catchAll, heading))
 }
+
+// buildRecent orders the live sessions for Recent mode: a flat, header-free list
+// sorted by Portal's frecency score, most-visited-lately first. A session scores
+// the higher of its own attach history and its directory's mint history
+// (Session.Dir, when stamped), so a freshly minted session ranks with its
+// project even before its first attach. Unscored sessions (and ties) keep the
+// incoming alphabetical order, trailing the scored ones.
+//
+// Pure function — the entries are the frecency.json snapshot cached on the
+// model; no I/O. Zero live sessions yields an empty slice.
+func buildRecent(sessions []tmux.Session, entries []frecency.Entry, now time.Time) []list.Item {
+	bySession := frecency.Scores(entries, frecency.KindSession, now)
+	byDir := frecency.Scores(entries, frecency.KindDir, now)
+	score := func(s tmux.Session) float64 {
+		if s.Dir == "" {
+			return bySession[s.Name]
+		}
+		return max(bySession[s.Name], byDir[s.Dir])
+	}
+
+	ordered := slices.Clone(sessions)
+	slices.SortStableFunc(ordered, func(a, b tmux.Session) int {
+		return cmp.Compare(score(b), score(a))
+	})
+	return ToListItems(ordered)
+}
+
+// buildByStatus groups the live sessions for By Status mode by the coding-agent
+// state of their most urgent pane: Needs attention (waiting for the user) first,
+// then Working, Idle and Unclassified, each group's rows by Session.Name. The
+// groups are ordered by agent.State.Urgency rather than by heading, so the
+// sessions that need the user always float to the top. Sessions with no
+// tracked agent form the No agent catch-all, pinned last.
+//
+// Pure function — states is the agent snapshot cached on the model; no I/O.
+// Zero live sessions yields an empty slice.
+func buildByStatus(sessions []tmux.Session, states map[string]agent.State) []list.Item {
+	var tracked, untracked []SessionItem
+	for _, s := range sessions {
+		st, ok := states[s.Name]
+		if !ok || st == "" {
+			untracked = append(untracked, SessionItem{Session: s, GroupHeading: noAgentHeading, CatchAll: true})
+			continue
+		}
+		heading := agentHeading(st)
+		tracked = append(tracked, SessionItem{Session: s, GroupKey: heading, GroupHeading: heading})
+	}
+	slices.SortFunc(tracked, func(a, b SessionItem) int {
+		ua, ub := states[a.Session.Name].Urgency(), states[b.Session.Name].Urgency()
+		if c := cmp.Compare(ub, ua); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Session.Name, b.Session.Name)
+	})
+	return injectGroupHeaders(append(tracked, orderedSessionItems(nil, untracked, noAgentHeading)...))
+}
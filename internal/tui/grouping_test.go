@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"charm.land/bubbles/v2/list"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/frecency"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/tmux"
 )
@@ -926,3 +929,131 @@ func TestBuildByTag(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildRecent(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("orders by frecency score, unscored sessions trailing alphabetically", func(t *testing.T) {
+		sessions := []tmux.Session{
+			{Name: "alpha"},
+			{Name: "bravo"},
+			{Name: "charlie"},
+			{Name: "delta"},
+		}
+		entries := []frecency.Entry{
+			{Kind: frecency.KindSession, Key: "charlie", Rank: 5, LastVisit: now},
+			{Kind: frecency.KindSession, Key: "bravo", Rank: 2, LastVisit: now},
+		}
+
+		items := buildRecent(sessions, entries, now)
+
+		var got []string
+		for _, it := range items {
+			got = append(got, asSessionItem(t, it).Session.Name)
+		}
+		want := []string{"charlie", "bravo", "alpha", "delta"}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("order = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+
+	t.Run("scores a session by its directory's mint history", func(t *testing.T) {
+		sessions := []tmux.Session{
+			{Name: "attached"},
+			{Name: "minted", Dir: "/code/portal"},
+		}
+		entries := []frecency.Entry{
+			{Kind: frecency.KindSession, Key: "attached", Rank: 1, LastVisit: now},
+			{Kind: frecency.KindDir, Key: "/code/portal", Rank: 3, LastVisit: now},
+		}
+
+		items := buildRecent(sessions, entries, now)
+
+		if first := asSessionItem(t, items[0]).Session.Name; first != "minted" {
+			t.Errorf("first = %q, want minted (its directory outscores the other's attaches)", first)
+		}
+	})
+
+	t.Run("a stale favourite decays below a recent visit", func(t *testing.T) {
+		sessions := []tmux.Session{{Name: "old"}, {Name: "new"}}
+		entries := []frecency.Entry{
+			{Kind: frecency.KindSession, Key: "old", Rank: 50, LastVisit: now.Add(-365 * 24 * time.Hour)},
+			{Kind: frecency.KindSession, Key: "new", Rank: 1, LastVisit: now},
+		}
+
+		items := buildRecent(sessions, entries, now)
+
+		if first := asSessionItem(t, items[0]).Session.Name; first != "new" {
+			t.Errorf("first = %q, want new", first)
+		}
+		if len(headerRows(items)) != 0 {
+			t.Error("Recent mode must render no group headers")
+		}
+	})
+}
+
+func TestBuildByStatus(t *testing.T) {
+	t.Run("floats waiting sessions to the top and pins No agent last", func(t *testing.T) {
+		sessions := []tmux.Session{
+			{Name: "alpha"},
+			{Name: "bravo"},
+			{Name: "charlie"},
+			{Name: "delta"},
+			{Name: "echo"},
+			{Name: "foxtrot"},
+		}
+		states := map[string]agent.State{
+			"alpha":   agent.Idle,
+			"charlie": agent.Working,
+			"delta":   agent.Waiting,
+			"echo":    agent.Unknown,
+			"foxtrot": agent.Waiting,
+		}
+
+		items := buildByStatus(sessions, states)
+
+		var got []string
+		for _, it := range items {
+			switch v := it.(type) {
+			case HeaderItem:
+				got = append(got, "# "+v.Heading)
+			case SessionItem:
+				got = append(got, v.Session.Name)
+			}
+		}
+		want := []string{
+			"# Needs attention", "delta", "foxtrot",
+			"# Working", "charlie",
+			"# Idle", "alpha",
+			"# Unclassified", "echo",
+			"# No agent", "bravo",
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("rows = %v, want %v", got, want)
+				break
+			}
+		}
+		if h := headerRows(items)[0]; h.Count != 2 {
+			t.Errorf("Needs attention count = %d, want 2", h.Count)
+		}
+	})
+
+	t.Run("no tracked agents puts every session under No agent", func(t *testing.T) {
+		items := buildByStatus([]tmux.Session{{Name: "alpha"}, {Name: "bravo"}}, nil)
+
+		headers := headerRows(items)
+		if len(headers) != 1 || headers[0].Heading != noAgentHeading || headers[0].Count != 2 {
+			t.Errorf("headers = %+v, want a single No agent group of 2", headers)
+		}
+	})
+}
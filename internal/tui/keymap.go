@@ -93,10 +93,11 @@ func sessionsKeymap() []keymapEntry {
 		{Key: "⏎", HelpKey: "⏎", Action: "attach", HelpAction: "Open / attach session", Core: true},
 		{Key: "/", Action: "filter", HelpAction: "Filter sessions", Core: true},
 		{Key: "␣", HelpKey: "␣", Action: "preview", HelpAction: "Preview scrollback", Core: true},
-		{Key: "s", Action: "switch view", HelpAction: "Switch view — flat / project / tag", Core: true},
+		{Key: "s", Action: "switch view", HelpAction: "Switch view — flat / project / tag / recent / status", Core: true},
 		{Key: "m", Action: "multi-select", HelpAction: "Multi-select mode"},
 		{Key: "n", Action: "new in cwd", HelpAction: "New session in cwd"},
 		{Key: "r", Action: "rename", HelpAction: "Rename session"},
+		{Key: "f", Action: "find", HelpAction: "Find in scrollback"},
 		{Key: "k", Action: "kill", HelpAction: "Kill session", Destructive: true},
 		{Key: "q", Action: "quit", HelpAction: "Quit"},
 		{Key: "x", Action: "projects", HelpAction: "Switch to Projects", Core: true},
@@ -195,3 +196,14 @@ func previewKeymap() []keymapEntry {
 		{Key: "␣", Action: "back", HelpAction: "Back to sessions", Core: true},
 	}
 }
+
+// grepKeymap returns the find-in-scrollback results page footer descriptor:
+// move through the hits, attach the highlighted hit's pane, or go back. The
+// page has no help modal, so every entry is Core.
+func grepKeymap() []keymapEntry {
+	return []keymapEntry{
+		{Key: "↑↓", Action: "navigate", Core: true},
+		{Key: "⏎", Action: "attach pane", Core: true},
+		{Key: "esc", Action: "back", Core: true},
+	}
+}
@@ -160,6 +160,13 @@ func TestSessionsDescriptorDispatchParity(t *testing.T) {
 			m = pressSession(t, m, tea.KeyPressMsg{Code: 'r', Text: "r"})
 			return m.modal == modalRename
 		}},
+		// f find — opens the find-in-scrollback prompt.
+		"f": {press: tea.KeyPressMsg{Code: 'f', Text: "f"}, honour: func(t *testing.T) bool {
+			m := sessionsGuardModel(t)
+			m.searcher = &stubSearcher{}
+			m = pressSession(t, m, tea.KeyPressMsg{Code: 'f', Text: "f"})
+			return m.modal == modalGrep
+		}},
 		// k kill — opens the kill confirm modal.
 		"k": {press: tea.KeyPressMsg{Code: 'k', Text: "k"}, honour: func(t *testing.T) bool {
 			m := sessionsGuardModel(t)
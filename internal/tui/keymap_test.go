@@ -14,7 +14,8 @@ func TestSessionsKeymap(t *testing.T) {
 	t.Run("it enumerates exactly the §12.1 Sessions bindings in the reference help order", func(t *testing.T) {
 		// Reference help order (testdata/vhs/reference/sessions-help-modal-mv.png):
 		// ↑/↓ → ^↑/↓ (page) → ⏎ → / → ␣ → s → m → n → r → k → q → x, then ? last
-		// (the §5 m multi-select entry is help-only, slotted after s).
+		// (the §5 m multi-select entry is help-only, slotted after s; the f
+		// find-in-scrollback entry is help-only, slotted after r).
 		// Post the §3.4 footer-glyph switch the footer reads the glyph Key forms
 		// (nav "↑↓", attach "⏎", preview "␣"); the help body keeps the slashed nav
 		// via the HelpKey override "↑/↓" while page reads its Key "^↑/↓" directly.
@@ -25,10 +26,11 @@ func TestSessionsKeymap(t *testing.T) {
 			{Key: "⏎", HelpKey: "⏎", Action: "attach", HelpAction: "Open / attach session", Core: true},
 			{Key: "/", Action: "filter", HelpAction: "Filter sessions", Core: true},
 			{Key: "␣", HelpKey: "␣", Action: "preview", HelpAction: "Preview scrollback", Core: true},
-			{Key: "s", Action: "switch view", HelpAction: "Switch view — flat / project / tag", Core: true},
+			{Key: "s", Action: "switch view", HelpAction: "Switch view — flat / project / tag / recent / status", Core: true},
 			{Key: "m", Action: "multi-select", HelpAction: "Multi-select mode"},
 			{Key: "n", Action: "new in cwd", HelpAction: "New session in cwd"},
 			{Key: "r", Action: "rename", HelpAction: "Rename session"},
+			{Key: "f", Action: "find", HelpAction: "Find in scrollback"},
 			{Key: "k", Action: "kill", HelpAction: "Kill session", Destructive: true},
 			{Key: "q", Action: "quit", HelpAction: "Quit"},
 			{Key: "x", Action: "projects", HelpAction: "Switch to Projects", Core: true},
@@ -16,6 +16,7 @@ const (
 	modalDeleteProject            // Delete project confirmation
 	modalEditProject              // Edit project with name and alias editing
 	modalHelp                     // §8.5 per-page ? help (descriptor-driven keymap reference)
+	modalGrep                     // Find-in-scrollback pattern prompt
 )
 
 // placeModalOnClearedCanvas is the SINGLE home of the §8.1/§13.5 cleared-canvas
@@ -85,6 +86,14 @@ func renderRenameModalOnClearedCanvas(input textinput.Model, oldName string, wid
 	return placeModalOnClearedCanvas(panel, width, height)
 }
 
+// renderGrepModalOnClearedCanvas composes the find-in-scrollback prompt
+// (renderGrepModalContent) and centres it on the cleared owned canvas via
+// placeModalOnClearedCanvas, like the rename modal it is modelled on.
+func renderGrepModalOnClearedCanvas(input textinput.Model, width, height int, mode theme.Mode, colourless bool) string {
+	panel := renderGrepModalContent(input, mode, colourless)
+	return placeModalOnClearedCanvas(panel, width, height)
+}
+
 // renderEditModalOnClearedCanvas composes the §8.2/§13.1 two-mode edit-project modal
 // panel and centres it on the cleared owned canvas via placeModalOnClearedCanvas,
 // like every modal wrapper but with the edit modal's own hand-drawn single-tone
@@ -17,6 +17,8 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/agent"
+	"github.com/leeovery/portal/internal/frecency"
 	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/resolver"
@@ -38,6 +40,8 @@ const (
 	PageProjects
 	// pagePreview displays the session scrollback preview sub-view.
 	pagePreview
+	// pageGrep displays the find-in-scrollback results.
+	pageGrep
 )
 
 // paginationDotGlyph is the bubbles/list built-in paginator dot glyph (•). The
@@ -209,6 +213,9 @@ type BootstrapFatalMsg struct {
 // SessionCreatedMsg is emitted when a session has been successfully created.
 type SessionCreatedMsg struct {
 	SessionName string
+	// Dir is the directory the session was minted in, surfaced via MintedDir
+	// so the caller can credit the project mint to Portal's frecency.
+	Dir string
 }
 
 // sessionCreateErrMsg is emitted when session creation fails.
@@ -235,6 +242,10 @@ type Model struct {
 	projectIndex    project.Index
 	sessionListMode prefs.SessionListMode
 	modePersister   ModePersister
+	// frecency is the frecency.json snapshot Recent mode orders sessions by,
+	// injected once at construction (WithFrecency). Nil orders Recent mode
+	// alphabetically, exactly like Flat.
+	frecency []frecency.Entry
 	// appearance is the persisted colour-scheme preference read once at TUI
 	// construction (WithAppearance). The model only stores it here; honouring it
 	// (skip detection + first-paint wait) is a later task. AppearanceAuto is the
@@ -270,8 +281,11 @@ type Model struct {
 	// constructed colourless). Foreground hue is stripped FREE by the Bubble Tea v2
 	// writer layer (colorprofile.Detect honours NO_COLOR), so state stays
 	// glyph-distinct (§2.2: ● attached, ▌ selector, spaced headers) + bold/dim.
-	colourless     bool
-	selected       string
+	colourless bool
+	selected   string
+	// mintedDir is the directory of a session the picker minted on quit (the
+	// Projects-page Enter / n paths), empty when it attached an existing one.
+	mintedDir      string
 	sessionLister  SessionLister
 	sessionKiller  SessionKiller
 	sessionRenamer SessionRenamer
@@ -301,6 +315,20 @@ type Model struct {
 	command            []string
 	commandPending     bool
 
+	// dormantSource feeds the Dormant section (saved sessions not running)
+	// and resurrects one on Enter; nil leaves the section unwired. dormant is
+	// its latest listing, refreshed after every live-session refresh.
+	dormantSource DormantSource
+	dormant       []DormantSession
+
+	// agentSource reports each live session's coding-agent state; nil leaves
+	// the glyphs and By Status unwired. agentStates is its latest read,
+	// refreshed every agentRefreshInterval while the picker is open, and
+	// projectAgents the same states rolled up per project path.
+	agentSource   AgentSource
+	agentStates   map[string]agent.State
+	projectAgents map[string]agent.State
+
 	// Bootstrap loading state
 	serverStarted     bool
 	minElapsed        bool
@@ -371,11 +399,24 @@ type Model struct {
 	// previewAttacher is the Enter pre-select + attach pipeline, wired
 	// via WithPreviewAttachPipeline and propagated onto previewModel at
 	// Space-handler construction so the preview page's Enter binding
-	// dispatches without re-resolving the connector.
+	// dispatches without re-resolving the connector. capturer, when wired,
+	// makes the preview capture the focused pane live and auto-refresh;
+	// previewSeq numbers each open so a stale refresh tick is dropped.
 	enumerator      TmuxEnumerator
+	capturer        PaneCapturer
 	reader          ScrollbackReader
 	previewAttacher PreviewAttacher
 	preview         previewModel
+	previewSeq      int
+
+	// Find-in-scrollback seam and state (pagegrep.go). searcher is nil when
+	// not wired, making `f` a no-op. grepInput is the pattern prompt's input;
+	// grepPage the results page; grepSeq numbers each search so a stale
+	// result is dropped.
+	searcher  ScrollbackSearcher
+	grepInput textinput.Model
+	grepPage  grepPage
+	grepSeq   int
 
 	// dirReader and dirRunner are the render-layer directory-resolution seam
 	// consumed by rebuildSessionList's lazy fallback. When both are non-nil,
@@ -436,6 +477,10 @@ type Model struct {
 	// model needs no constructor change; exitMultiSelect nils it back out.
 	multiSelectMode  bool
 	selectedSessions map[string]struct{}
+	// burstPlacement is where an N≥2 multi-select burst opens its external
+	// surfaces — windows (the zero value), tabs, or splits. `t` cycles it while
+	// in the mode; it survives exiting the mode so a second burst reuses it.
+	burstPlacement spawn.Placement
 
 	// §6-7 pre-flight abort state (restore-host-terminal-windows). When an N≥2 Enter
 	// pre-flight finds a marked session gone, the burst aborts atomically (nothing
@@ -565,6 +610,12 @@ func (m Model) Selected() string {
 	return m.selected
 }
 
+// MintedDir returns the directory the picker minted its selected session in,
+// or empty when the selection was an existing session (or none).
+func (m Model) MintedDir() string {
+	return m.mintedDir
+}
+
 // InitialFilter returns the initial filter text for the session list.
 func (m Model) InitialFilter() string {
 	return m.initialFilter
@@ -790,6 +841,23 @@ func WithKiller(k SessionKiller) Option {
 	}
 }
 
+// WithDormantSource wires the Dormant section: saved sessions that are not
+// running, listed after the live rows and resurrected on Enter. A nil source
+// leaves the section unwired.
+func WithDormantSource(src DormantSource) Option {
+	return func(m *Model) {
+		m.dormantSource = src
+	}
+}
+
+// WithAgentSource wires the coding-agent state glyphs and the By Status
+// mode's grouping. A nil source leaves both empty.
+func WithAgentSource(src AgentSource) Option {
+	return func(m *Model) {
+		m.agentSource = src
+	}
+}
+
 // WithRenamer sets the session renamer dependency.
 func WithRenamer(r SessionRenamer) Option {
 	return func(m *Model) {
@@ -820,6 +888,15 @@ func WithModePersister(p ModePersister) Option {
 	}
 }
 
+// WithFrecency sets the frecency.json snapshot Recent mode orders sessions by.
+// Production wiring loads it once in cmd/open.go; omitting it (or a nil slice)
+// leaves Recent mode in the incoming alphabetical order.
+func WithFrecency(entries []frecency.Entry) Option {
+	return func(m *Model) {
+		m.frecency = entries
+	}
+}
+
 // WithAppearance sets the persisted colour-scheme preference (auto/light/dark) the
 // model opens with. Production wiring reads it from prefs.json (via cmd/open.go's
 // loadPrefsStore + Store.LoadAppearance, tolerant to AppearanceAuto) and injects it
@@ -950,6 +1027,27 @@ func WithScrollbackReader(r ScrollbackReader) Option {
 	}
 }
 
+// WithPaneCapturer wires the PaneCapturer seam that lets the scrollback
+// preview show the focused pane live (refreshing while open) rather than only
+// its saved scrollback. Production callers pass a *tmux.Client; tests that do
+// not exercise live capture can omit this option, leaving capturer nil and the
+// preview saved-only.
+func WithPaneCapturer(c PaneCapturer) Option {
+	return func(m *Model) {
+		m.capturer = c
+	}
+}
+
+// WithScrollbackSearcher wires the ScrollbackSearcher seam behind the Sessions
+// page's `f` find-in-scrollback. Production callers pass the cmd/grep.go
+// adapter; tests that do not exercise the search can omit this option, leaving
+// searcher nil and `f` inert.
+func WithScrollbackSearcher(s ScrollbackSearcher) Option {
+	return func(m *Model) {
+		m.searcher = s
+	}
+}
+
 // WithPreviewAttachPipeline wires the PreviewAttacher seam used by the
 // preview page's Enter binding. Production callers pass the pipeline
 // constructed via NewPreviewAttachPipeline (closing over *tmux.Client +
@@ -1104,9 +1202,11 @@ func centrePaginationRow(l *list.Model, base lipgloss.Style) {
 
 // sessionListTitleForMode computes the session list title for the active
 // grouping mode, reconciling it with the inside-tmux current-session
-// decoration. The three base strings come from the spec (§ TUI Rendering &
+// decoration. The base strings come from the spec (§ TUI Rendering &
 // Toggle Behaviour → Mode indication): ModeFlat → "Sessions",
-// ModeByProject → "Sessions — by project", ModeByTag → "Sessions — by tag".
+// ModeByProject → "Sessions — by project", ModeByTag → "Sessions — by tag";
+// ModeRecent → "Sessions — recent" and ModeByStatus → "Sessions — by status"
+// follow the same shape.
 // The separator is " — " (an em-dash U+2014 with surrounding spaces).
 //
 // DIVERGENCE FROM SPEC: the spec's title scheme specifies only those three
@@ -1122,6 +1222,10 @@ func sessionListTitleForMode(mode prefs.SessionListMode, insideTmux bool, curren
 		base = "Sessions — by project"
 	case prefs.ModeByTag:
 		base = "Sessions — by tag"
+	case prefs.ModeRecent:
+		base = "Sessions — recent"
+	case prefs.ModeByStatus:
+		base = "Sessions — by status"
 	default:
 		base = "Sessions"
 	}
@@ -1136,13 +1240,16 @@ func sessionListTitleForMode(mode prefs.SessionListMode, insideTmux bool, curren
 // because the §3.4 condensed keymap footer is rendered manually by
 // renderCondensedFooter over the §12.1 keymapEntry descriptors (see footer.go),
 // which sources its own §2.9 role tokens — nothing in the render path consumes
-// the list's own help.Model, so l.Help.Styles.* is never populated.
+// the list's own help.Model, so l.Help.Styles.* is never populated. Filtering
+// runs through rankedFilter, so matches list best-first and carry the rune
+// positions the delegate highlights.
 func newSessionList(items []list.Item) list.Model {
 	l := list.New(items, SessionDelegate{}, 0, 0)
 	l.Title = "Sessions"
 	l.DisableQuitKeybindings()
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
+	l.Filter = rankedFilter
 	l.SetStatusBarItemName("session", "sessions running")
 	l.SetShowHelp(false)
 	l.KeyMap.ShowFullHelp.Unbind()
@@ -1181,6 +1288,7 @@ func newProjectList() list.Model {
 	l.DisableQuitKeybindings()
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
+	l.Filter = rankedFilter
 	l.SetStatusBarItemName("project", "saved projects")
 	l.SetShowHelp(false)
 	l.KeyMap.ShowFullHelp.Unbind()
@@ -1286,6 +1394,18 @@ func (m *Model) sessionDelegate() SessionDelegate {
 		MultiSelect: m.multiSelectMode,
 		Selected:    m.selectedSessions,
 		GoneFlagged: m.goneFlagged,
+		Agents:      m.agentStates,
+	}
+}
+
+// projectDelegate constructs the ProjectDelegate for the current model state —
+// the resolved canvas Mode, the NO_COLOR carve-out and the per-project agent
+// rollup — mirroring sessionDelegate for the Projects page.
+func (m *Model) projectDelegate() ProjectDelegate {
+	return ProjectDelegate{
+		Mode:       m.canvasMode,
+		Colourless: m.colourless,
+		Agents:     m.projectAgents,
 	}
 }
 
@@ -1365,14 +1485,14 @@ func (m *Model) applyCanvasMode() {
 // blank gap row on line 1, the SAME contract applySectionHeader relies on).
 func (m *Model) applyProjectCanvasMode() {
 	if m.colourless {
-		m.projectList.SetDelegate(ProjectDelegate{Mode: m.canvasMode, Colourless: true})
+		m.projectList.SetDelegate(m.projectDelegate())
 		colourlessHelpStyles(&m.projectList)
 		colourlessPaginationDots(&m.projectList)
 		m.projectList.Styles.TitleBar = m.projectList.Styles.TitleBar.UnsetBackground().PaddingLeft(0).PaddingBottom(1)
 		m.projectList.Styles.Title = m.projectList.Styles.Title.UnsetBackground().UnsetForeground()
 		return
 	}
-	m.projectList.SetDelegate(ProjectDelegate{Mode: m.canvasMode})
+	m.projectList.SetDelegate(m.projectDelegate())
 	canvasHelpStyles(&m.projectList, m.canvasMode)
 	canvasPaginationDots(&m.projectList, m.canvasMode)
 	canvas := theme.MV.Canvas.ColorFor(m.canvasMode)
@@ -1596,6 +1716,7 @@ func (m *Model) applySessions(sessions []tmux.Session) tea.Cmd {
 	// refreshed list (e.g. externally killed during the Space preview) BEFORE
 	// the delegate re-render below, so the pruned-and-refreshed set feeds the ●.
 	m.pruneSelectionToLiveSessions()
+	m.refreshProjectAgents()
 	return m.rebuildSessionList()
 }
 
@@ -1636,11 +1757,12 @@ func (m *Model) pruneSelectionToLiveSessions() {
 // populated from ProjectsLoadedMsg) — no synchronous store read happens in the
 // render path. ModeFlat routes through ToListItems so its output is identical
 // to the pre-grouping behaviour; ModeByProject / ModeByTag route through the
-// grouping builders. Zero live sessions yields an empty list in every mode.
+// grouping builders, ModeRecent through buildRecent over the cached frecency
+// snapshot, and ModeByStatus through buildByStatus over the cached agent states. Zero live sessions yields an empty list in every mode.
 // nextSessionListMode advances the grouping mode one step in the fixed cycle
-// Flat → By Project → By Tag → Flat. The wrap is unconditional (By Tag always
-// advances to Flat). An out-of-range value collapses defensively to Flat so the
-// cycle can never get stuck on an unrecognised mode.
+// Flat → By Project → By Tag → Recent → By Status → Flat. The wrap is
+// unconditional (By Status always advances to Flat). An out-of-range value collapses defensively to Flat
+// so the cycle can never get stuck on an unrecognised mode.
 func nextSessionListMode(mode prefs.SessionListMode) prefs.SessionListMode {
 	switch mode {
 	case prefs.ModeFlat:
@@ -1648,6 +1770,10 @@ func nextSessionListMode(mode prefs.SessionListMode) prefs.SessionListMode {
 	case prefs.ModeByProject:
 		return prefs.ModeByTag
 	case prefs.ModeByTag:
+		return prefs.ModeRecent
+	case prefs.ModeRecent:
+		return prefs.ModeByStatus
+	case prefs.ModeByStatus:
 		return prefs.ModeFlat
 	default:
 		return prefs.ModeFlat
@@ -1674,10 +1800,12 @@ func anyTagsExist(projects []project.Project) bool {
 // setProjects updates the cached project records AND their derived lookup cache
 // (projectIndex) together. It is the single production seam where m.projects
 // changes, so the index can never go stale relative to the records the grouping
-// builders consult. Always mutate projects through this helper.
+// builders consult. Always mutate projects through this helper. The agent
+// rollup keys on the index too, so it is recomputed here.
 func (m *Model) setProjects(projects []project.Project) {
 	m.projects = projects
 	m.projectIndex = project.NewIndex(projects)
+	m.refreshProjectAgents()
 }
 
 // resolveSessionDirs is the render-layer chokepoint over the lazy
@@ -1697,8 +1825,8 @@ func (m *Model) setProjects(projects []project.Project) {
 // m.sessions is rebuilt fresh from ListSessions).
 //
 // It is invoked ONLY from the grouped render arms (ModeByProject / ModeByTag,
-// non-signpost) in rebuildSessionList — the lazy fallback is a grouped-render
-// mechanism. The Flat and byTagSignpost arms render via ToListItems, which
+// non-signpost) in rebuildSessionList, and for agent-tracked sessions from
+// rollUpProjectAgents — the lazy fallback is a grouped-render mechanism. The Flat and byTagSignpost arms render via ToListItems, which
 // ignores Session.Dir, so they consume the un-resolved sessions directly and
 // pay zero pane reads.
 //
@@ -1761,9 +1889,16 @@ func (m *Model) rebuildSessionList() tea.Cmd {
 		items = buildByProject(m.resolveSessionDirs(filtered), m.projectIndex)
 	case m.sessionListMode == prefs.ModeByTag:
 		items = buildByTag(m.resolveSessionDirs(filtered), m.projectIndex)
+	case m.sessionListMode == prefs.ModeRecent:
+		items = buildRecent(filtered, m.frecency, time.Now())
+	case m.sessionListMode == prefs.ModeByStatus:
+		items = buildByStatus(filtered, m.agentStates)
 	default:
 		items = ToListItems(filtered)
 	}
+	// The Dormant section trails the live rows in every mode, under its own
+	// heading, so grouping never mixes saved-only sessions in with live ones.
+	items = append(items, dormantListItems(m.dormant, time.Now())...)
 
 	cmd := m.sessionList.SetItems(items)
 
@@ -2143,6 +2278,10 @@ func (m Model) Init() tea.Cmd {
 	// command at loading-page dismissal on the concurrent route.
 	fetchSessions := m.fetchSessionsCmd()
 	loadProjects := m.loadProjects()
+	// The agent-state read starts its own refresh loop (handleAgentsLoaded), so
+	// the glyphs follow the daemon while the picker stays open. Nil when no
+	// AgentSource is wired.
+	fetchAgents := m.fetchAgentsCmd()
 
 	if m.activePage == PageLoading {
 		loadingPadTick := tea.Tick(LoadingMinDuration, func(time.Time) tea.Msg {
@@ -2171,13 +2310,20 @@ func (m Model) Init() tea.Cmd {
 		if loadProjects != nil {
 			cmds = append(cmds, loadProjects)
 		}
+		if fetchAgents != nil {
+			cmds = append(cmds, fetchAgents)
+		}
 		return tea.Batch(cmds...)
 	}
 
+	cmds := []tea.Cmd{requestBg, detectTimeout, fetchSessions}
 	if loadProjects != nil {
-		return tea.Batch(requestBg, detectTimeout, fetchSessions, loadProjects)
+		cmds = append(cmds, loadProjects)
+	}
+	if fetchAgents != nil {
+		cmds = append(cmds, fetchAgents)
 	}
-	return tea.Batch(requestBg, detectTimeout, fetchSessions)
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and updates the model.
@@ -2243,6 +2389,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// PageLoading, we still ingest the session list so it is ready when
 		// transitionFromLoading runs, but we do not flip sessionsLoaded here:
 		// transitionFromLoading does that as part of evaluateDefaultPage.
+		cmd = tea.Batch(cmd, m.fetchDormantCmd())
 		if m.activePage == PageLoading {
 			return m, cmd
 		}
@@ -2368,7 +2515,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, setItemsCmd
 	case SessionCreatedMsg:
 		m.selected = msg.SessionName
+		m.mintedDir = msg.Dir
 		return m, tea.Quit
+	case dormantLoadedMsg:
+		return m.handleDormantLoaded(msg)
+	case agentsLoadedMsg:
+		return m.handleAgentsLoaded(msg)
+	case agentRefreshMsg:
+		return m, m.fetchAgentsCmd()
+	case dormantResurrectedMsg:
+		return m.handleDormantResurrected(msg)
 	case sessionCreateErrMsg:
 		// On error, return to current page
 		return m, nil
@@ -2429,6 +2585,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// the process post-TUI; same effect either way.
 		m.selected = msg.Session
 		return m, tea.Quit
+	case grepResultsMsg:
+		return m.applyGrepResults(msg), nil
 	case previewSessionsRefreshedMsg:
 		// Lister errors are non-fatal here: the user just dismissed
 		// preview and expects to land on the Sessions list. A tea.Quit
@@ -2441,7 +2599,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmd := m.applySessions(msg.Sessions)
 		m.reanchorSessionCursor(msg.PreserveName)
-		return m, cmd
+		return m, tea.Batch(cmd, m.fetchDormantCmd())
 	case flashTickMsg:
 		// Generation-guard: a tick scheduled for an earlier flash must
 		// not early-clear a flash that has since been replaced by a
@@ -2565,6 +2723,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.preview, cmd = m.preview.Update(insetWindowSizeMsg(msg, m.contentWidth(), m.contentHeight()))
 		return m, cmd
+	case pageGrep:
+		return m.updateGrepPage(msg)
 	default:
 		return m.updateSessionList(msg)
 	}
@@ -2576,7 +2736,7 @@ func (m Model) createSession(dir string) tea.Cmd {
 		if err != nil {
 			return sessionCreateErrMsg{Err: err}
 		}
-		return SessionCreatedMsg{SessionName: name}
+		return SessionCreatedMsg{SessionName: name, Dir: dir}
 	}
 }
 
@@ -3349,8 +3509,9 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Bound before the rest of the keymap so it never collides with
 		// later rune-based handlers. No-op when the list is empty, when
 		// no item is highlighted (committed filter narrowed to zero
-		// matches), or when NewPreviewModel reports ok=false (enumeration
-		// failure or empty enumeration — both observably identical).
+		// matches), or when NewLivePreviewModel reports ok=false
+		// (enumeration failure or empty enumeration — both observably
+		// identical). A live preview starts its auto-refresh tick chain.
 		if keyIsCode(msg, tea.KeySpace) {
 			if len(m.sessionList.Items()) == 0 {
 				return m, nil
@@ -3362,10 +3523,12 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Size the preview to the INSET content region (the §3 gutter folded
 			// into the budget) so its framed chrome sits inside the global gutter
 			// like every other page.
-			pmodel, ok := NewPreviewModel(si.Session.Name, m.enumerator, m.reader, m.previewAttacher, m.contentWidth(), m.contentHeight())
+			pmodel, ok := NewLivePreviewModel(si.Session.Name, m.enumerator, m.capturer, m.reader, m.previewAttacher, m.contentWidth(), m.contentHeight())
 			if !ok {
 				return m, nil
 			}
+			m.previewSeq++
+			pmodel.refreshSeq = m.previewSeq
 			// Propagate the resolved canvas mode + NO_COLOR carve-out so the
 			// §9.1 cyan peek-mode chrome resolves the right token variant (and
 			// drops hue under NO_COLOR, §9.2).
@@ -3373,7 +3536,7 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			pmodel.colourless = m.colourless
 			m.preview = pmodel
 			m.activePage = pagePreview
-			return m, nil
+			return m, tea.Batch(pmodel.captureFocusedPane(false), pmodel.scheduleRefresh())
 		}
 		switch {
 		case keyIsCode(msg, tea.KeyEscape):
@@ -3409,6 +3572,13 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			return m.handleRenameKey()
+		case isRuneKey(msg, "f"):
+			// f opens the find-in-scrollback prompt. A search spans every
+			// session, not the marked set, so multi-select suppresses it like r.
+			if m.multiSelectMode {
+				return m, nil
+			}
+			return m.handleGrepKey()
 		case isRuneKey(msg, "n"):
 			// §5 Multi-select suppresses n (new-session-in-cwd): it is not in the
 			// closed live-set (Space/ / /s) and, unlike the browse keys, it would
@@ -3420,7 +3590,7 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m.handleNewInCWD()
 		// s cycles the session-list grouping mode (Flat → By Project → By Tag
-		// → Flat). This case MUST stay inside this rune switch, which sits below
+		// → Recent → By Status → Flat). This case MUST stay inside this rune switch, which sits below
 		// the `if m.sessionList.SettingFilter() { break }` guard above — that
 		// guard makes s a literal filter character while the / filter input is
 		// focused. Do NOT hoist this case above that guard.
@@ -3432,6 +3602,14 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// while the / filter input is focused. Do NOT hoist above that guard.
 		case isRuneKey(msg, "m"):
 			return m.handleMultiSelectToggle()
+		// t cycles the multi-select burst placement (windows → tabs → splits).
+		// Outside the mode it is an ordinary key delegated to the list. Like m it
+		// stays below the SettingFilter guard so t types into a focused filter.
+		case isRuneKey(msg, "t"):
+			if !m.multiSelectMode {
+				break
+			}
+			return m.handlePlacementToggle()
 		// x is the sole Sessions↔Projects toggle (§12.2). The former p alias
 		// (Sessions → Projects) is dropped so each key has a single meaning.
 		case isRuneKey(msg, "x"):
@@ -3468,7 +3646,7 @@ func (m Model) updateSessionList(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 // handleSwitchViewKey advances the session-list grouping mode one step
-// (Flat → By Project → By Tag → Flat), re-renders the list via the mode-aware
+// (Flat → By Project → By Tag → Recent → By Status → Flat), re-renders the list via the mode-aware
 // core, and persists the new mode through the injected seam. The cycle is
 // unconditional — it fires regardless of session count or tag count.
 //
@@ -3590,6 +3768,22 @@ func (m Model) handleMultiSelectToggle() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handlePlacementToggle advances the multi-select burst placement one step
+// (windows → tabs → splits → windows). When detection has already resolved an
+// adapter that cannot open the new placement, it flashes the shared fallback
+// message so the user learns before Enter that the burst will open windows; the
+// placement is kept regardless, and the burster downgrades it at dispatch.
+func (m Model) handlePlacementToggle() (tea.Model, tea.Cmd) {
+	m.burstPlacement = m.burstPlacement.Next()
+	if m.detectAdapter != nil {
+		if _, ok := spawn.EffectivePlacement(m.detectAdapter, m.burstPlacement); !ok {
+			(&m).setFlash(spawn.PlacementFallbackMessage(m.detectIdentity, m.burstPlacement))
+			return m, flashTickCmd(m.flashGen)
+		}
+	}
+	return m, nil
+}
+
 // exitMultiSelect leaves §5 multi-select mode and clears the whole marked set. It
 // is the shared exit path — Esc invokes it here and the task-5.7 N=0 commit
 // reuses it — so it is a reusable method returning the updated model rather than
@@ -3667,6 +3861,8 @@ func (m Model) updateModal(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateEditProjectModal(msg)
 	case modalHelp:
 		return m.updateHelpModal(msg)
+	case modalGrep:
+		return m.updateGrepModal(msg)
 	default:
 		return m, nil
 	}
@@ -3795,6 +3991,11 @@ func (m Model) createSessionInCWD() tea.Cmd {
 }
 
 func (m Model) handleSessionListEnter() (tea.Model, tea.Cmd) {
+	if di, ok := m.sessionList.SelectedItem().(DormantItem); ok && m.dormantSource != nil {
+		// A dormant row is resurrected first; the picker attaches once the
+		// restore reports back (handleDormantResurrected).
+		return m, m.resurrectDormantCmd(di.Session.Name)
+	}
 	si, ok := m.selectedSessionItem()
 	if !ok {
 		return m, nil
@@ -4346,6 +4547,8 @@ func (m Model) viewString() string {
 		return m.viewProjectList()
 	case pagePreview:
 		return m.preview.View()
+	case pageGrep:
+		return m.viewGrep()
 	default:
 		return m.viewSessionList()
 	}
@@ -4589,6 +4792,8 @@ func (m Model) viewSessionList() string {
 		// The rename flow LOGIC is unchanged (updateRenameModal / renameAndRefresh);
 		// only the rendering is reskinned.
 		return renderRenameModalOnClearedCanvas(m.renameInput, m.renameTarget, m.contentWidth(), m.contentHeight(), m.canvasMode, m.colourless)
+	case modalGrep:
+		return renderGrepModalOnClearedCanvas(m.grepInput, m.contentWidth(), m.contentHeight(), m.canvasMode, m.colourless)
 	case modalHelp:
 		// §8.5 per-page help: the Sessions keymap descriptor, descriptor-driven, in
 		// the help modal's own zero-h-padding panel (FIX 4). §4: the descriptor is
@@ -4854,6 +5059,7 @@ func (m Model) applySectionHeader(listView string) string {
 	if m.multiSelectMode {
 		return replaceHeaderLine(listView, renderMultiSelectHeader(
 			len(m.selectedSessions),
+			m.burstPlacement,
 			m.contentWidth(),
 			m.canvasMode,
 			m.colourless,
@@ -707,6 +707,9 @@ func TestNKeyCreatesSessionInCWD(t *testing.T) {
 		if model.(tui.Model).Selected() != "portal-abc123" {
 			t.Errorf("expected Selected() = %q, got %q", "portal-abc123", model.(tui.Model).Selected())
 		}
+		if got := model.(tui.Model).MintedDir(); got != "/home/user/code/portal" {
+			t.Errorf("expected MintedDir() = %q, got %q", "/home/user/code/portal", got)
+		}
 	})
 
 	t.Run("n with no session creator is no-op", func(t *testing.T) {
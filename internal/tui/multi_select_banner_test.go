@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 	"github.com/leeovery/portal/internal/prefs"
 	"github.com/leeovery/portal/internal/project"
+	"github.com/leeovery/portal/internal/spawn"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/leeovery/portal/internal/tui/theme"
 )
@@ -37,7 +38,7 @@ func TestMultiSelectHeader_CountVioletCancelDetail(t *testing.T) {
 		{"light", theme.Light},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			header := renderMultiSelectHeader(3, sectionHeaderWidth, tc.mode, false)
+			header := renderMultiSelectHeader(3, spawn.PlacementWindows, sectionHeaderWidth, tc.mode, false)
 
 			if !strings.Contains(header, "3 selected") {
 				t.Errorf("banner missing the %q cluster:\n%s", "3 selected", header)
@@ -63,7 +64,7 @@ func TestMultiSelectHeader_CountVioletCancelDetail(t *testing.T) {
 // right-aligned (the left cluster and the hint are separated by a flex spacer to
 // the content width) and the single rendered row is exactly the content width.
 func TestMultiSelectHeader_RightAlignedCancelHint(t *testing.T) {
-	header := renderMultiSelectHeader(2, sectionHeaderWidth, theme.Dark, false)
+	header := renderMultiSelectHeader(2, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, false)
 
 	countIdx := strings.Index(header, "2 selected")
 	hintIdx := strings.LastIndex(header, multiSelectCancelHint)
@@ -83,7 +84,7 @@ func TestMultiSelectHeader_RightAlignedCancelHint(t *testing.T) {
 // delegate pagination budget (§3.5).
 func TestMultiSelectHeader_ExactlyOneRow(t *testing.T) {
 	for _, count := range []int{0, 1, 42} {
-		header := renderMultiSelectHeader(count, sectionHeaderWidth, theme.Dark, false)
+		header := renderMultiSelectHeader(count, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, false)
 		if got := lipgloss.Height(header); got != 1 {
 			t.Errorf("banner for count %d height = %d, want exactly 1 row:\n%s", count, got, header)
 		}
@@ -94,7 +95,7 @@ func TestMultiSelectHeader_ExactlyOneRow(t *testing.T) {
 // `0 selected` (the banner renders even with an empty set — it is a mode
 // affordance, not a count-gated element).
 func TestMultiSelectHeader_ZeroSelected(t *testing.T) {
-	header := renderMultiSelectHeader(0, sectionHeaderWidth, theme.Dark, false)
+	header := renderMultiSelectHeader(0, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, false)
 	if !strings.Contains(ansi.Strip(header), "0 selected") {
 		t.Errorf("banner for N=0 must read %q:\n%s", "0 selected", ansi.Strip(header))
 	}
@@ -115,14 +116,14 @@ func TestMultiSelectHeader_ZeroSelected(t *testing.T) {
 // core).
 func TestMultiSelectHeader_NarrowDegradeDropsHint(t *testing.T) {
 	// Wide: hint present.
-	wide := renderMultiSelectHeader(3, sectionHeaderWidth, theme.Dark, false)
+	wide := renderMultiSelectHeader(3, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, false)
 	if !strings.Contains(wide, multiSelectCancelHint) {
 		t.Fatalf("wide banner missing the hint:\n%s", wide)
 	}
 
 	// Narrow: a width that cannot hold `N selected` + a spacer + `esc cancel`.
 	const narrow = 14
-	narrowHeader := renderMultiSelectHeader(3, narrow, theme.Dark, false)
+	narrowHeader := renderMultiSelectHeader(3, spawn.PlacementWindows, narrow, theme.Dark, false)
 	if strings.Contains(narrowHeader, multiSelectCancelHint) {
 		t.Errorf("narrow banner at width %d still shows the %q hint (degrade failed):\n%s", narrow, multiSelectCancelHint, narrowHeader)
 	}
@@ -141,7 +142,7 @@ func TestMultiSelectHeader_NarrowDegradeDropsHint(t *testing.T) {
 // hue — the `N selected` / `esc cancel` text survives on the terminal's native
 // fg/bg.
 func TestMultiSelectHeader_ColourlessDropsHueAndCanvas(t *testing.T) {
-	header := renderMultiSelectHeader(3, sectionHeaderWidth, theme.Dark, true)
+	header := renderMultiSelectHeader(3, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, true)
 
 	if !strings.Contains(header, "3 selected") || !strings.Contains(header, multiSelectCancelHint) {
 		t.Errorf("colourless banner dropped structure:\n%s", header)
@@ -161,7 +162,7 @@ func TestMultiSelectHeader_ColourlessDropsHueAndCanvas(t *testing.T) {
 // gap is canvas-painted, not a terminal-bg island.
 func TestMultiSelectHeader_PaintsCanvasNoEdgeBleed(t *testing.T) {
 	for _, mode := range []theme.Mode{theme.Dark, theme.Light} {
-		header := renderMultiSelectHeader(3, sectionHeaderWidth, mode, false)
+		header := renderMultiSelectHeader(3, spawn.PlacementWindows, sectionHeaderWidth, mode, false)
 		if seq := canvasSeq(t, mode); !strings.Contains(header, seq) {
 			t.Errorf("banner does not paint the canvas background sequence %q:\n%s", seq, header)
 		}
@@ -32,7 +32,7 @@ func TestMultiSelectFooter_ExactCopy(t *testing.T) {
 		t.Fatalf("multi-select footer must be 2 rows (rule + entry row), got %d:\n%s", len(lines), footer)
 	}
 
-	const want = "↑↓ navigate · m toggle · ␣ preview · ⏎ open · esc cancel"
+	const want = "↑↓ navigate · m toggle · ␣ preview · t placement · ⏎ open · esc cancel"
 	got := strings.TrimRight(footerVisible(lines[1]), " ")
 	if got != want {
 		t.Errorf("multi-select footer entry row = %q, want exactly %q", got, want)
@@ -43,7 +43,7 @@ func TestMultiSelectFooter_ExactCopy(t *testing.T) {
 // constant (mirroring TestCommandBand_FixedTextConstant), so the wording cannot
 // drift from a paraphrase.
 func TestMultiSelectFooter_CopyConstant(t *testing.T) {
-	const want = "↑↓ navigate · m toggle · ␣ preview · ⏎ open · esc cancel"
+	const want = "↑↓ navigate · m toggle · ␣ preview · t placement · ⏎ open · esc cancel"
 	if multiSelectFooterText != want {
 		t.Errorf("multiSelectFooterText = %q, want the spec-exact wording %q", multiSelectFooterText, want)
 	}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/spawn"
+	"github.com/leeovery/portal/internal/spawntest"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// multi_select_placement_test.go pins the multi-select burst placement toggle:
+// `t` cycles windows → tabs → splits in the mode (and is inert outside it), the
+// banner names a non-window placement, an adapter that cannot open the chosen
+// placement flashes the fallback up front, and the burst opens its external
+// surfaces through the chosen capability.
+
+var keyT = tea.KeyPressMsg{Code: 't', Text: "t"}
+
+func TestMultiSelectPlacementToggle(t *testing.T) {
+	t.Run("t cycles the placement only inside the mode", func(t *testing.T) {
+		m := NewModelWithSessions(twoFlatSessions())
+		m = pressSession(t, m, keyT)
+		if m.burstPlacement != "" {
+			t.Fatalf("t outside multi-select set placement %q, want it untouched", m.burstPlacement)
+		}
+
+		m = enterMultiSelect(t, m)
+		var seen []spawn.Placement
+		for range 3 {
+			m = pressSession(t, m, keyT)
+			seen = append(seen, m.burstPlacement)
+		}
+		want := []spawn.Placement{spawn.PlacementTabs, spawn.PlacementSplits, spawn.PlacementWindows}
+		if !slices.Equal(seen, want) {
+			t.Errorf("placements after three t presses = %v, want %v", seen, want)
+		}
+		if !m.MultiSelectActive() {
+			t.Error("t must not leave multi-select mode")
+		}
+	})
+
+	t.Run("t types into a focused filter", func(t *testing.T) {
+		m := enterMultiSelect(t, NewModelWithSessions(twoFlatSessions()))
+		m = pressSession(t, m, keySlash)
+		m = pressSession(t, m, keyT)
+		if m.burstPlacement != "" || m.sessionList.FilterValue() != "t" {
+			t.Errorf("placement = %q, filter = %q; want t typed into the filter", m.burstPlacement, m.sessionList.FilterValue())
+		}
+	})
+
+	t.Run("an unsupported placement flashes the fallback", func(t *testing.T) {
+		m := NewModelWithSessions(twoFlatSessions())
+		wireTOCTOUResolveSeams(&m, &spawntest.FakeAdapter{WindowsOnly: true}, &spawntest.FakeAckChannel{})
+		m = resolveDetection(t, m, ghosttyIdentity())
+		m = enterMultiSelect(t, m)
+
+		m = pressSession(t, m, keyT)
+
+		want := spawn.PlacementFallbackMessage(ghosttyIdentity(), spawn.PlacementTabs)
+		if m.flashText != want {
+			t.Errorf("flashText = %q, want %q", m.flashText, want)
+		}
+	})
+}
+
+func TestMultiSelectHeader_NamesNonWindowPlacement(t *testing.T) {
+	windows := renderMultiSelectHeader(2, spawn.PlacementWindows, sectionHeaderWidth, theme.Dark, true)
+	if strings.Contains(windows, " as ") {
+		t.Errorf("windows placement must leave the banner unchanged, got %q", windows)
+	}
+	tabs := renderMultiSelectHeader(2, spawn.PlacementTabs, sectionHeaderWidth, theme.Dark, true)
+	if !strings.Contains(tabs, "2 selected as tabs") {
+		t.Errorf("tabs banner = %q, want it to read `2 selected as tabs`", tabs)
+	}
+}
+
+func TestBurstDispatch_OpensExternalsWithChosenPlacement(t *testing.T) {
+	ack := &spawntest.FakeAckChannel{}
+	detectAdapter := &spawntest.FakeAdapter{Ack: ack}
+	m := NewModelWithSessions(twoFlatSessions())
+	wireTOCTOUResolveSeams(&m, detectAdapter, ack)
+	m = resolveDetection(t, m, ghosttyIdentity())
+
+	m = markTwo(t, m)
+	m = pressSession(t, m, keyT) // windows → tabs
+	m, cmd := pressEnter(t, m)
+	drainBatchToModel(t, m, cmd)
+
+	if !slices.Equal(detectAdapter.Placements, []spawn.Placement{spawn.PlacementTabs}) {
+		t.Errorf("external placements = %v, want the one external surface opened as a tab", detectAdapter.Placements)
+	}
+}
@@ -0,0 +1,294 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/grep"
+	"github.com/leeovery/portal/internal/tui/theme"
+)
+
+// The find-in-scrollback results page. `f` on the Sessions page opens the
+// pattern prompt (grep_modal.go); submitting runs the ScrollbackSearcher off
+// the UI thread and flips to pageGrep, which lists one row per matching line
+// with the highlighted hit's context lines beneath. Enter jumps straight into
+// the hit's pane through the same PreviewAttacher pipeline the preview page's
+// Enter uses, so the pre-select, the externally-killed bail and the post-TUI
+// connector handoff are all shared. Esc returns to the Sessions page.
+
+// ScrollbackSearcher is the seam through which the picker searches every
+// pane's scrollback for a pattern. Production wiring is an adapter in
+// cmd/grep.go over the internal/grep Live corpus (running panes captured
+// live, dormant sessions read from their saved files); tests substitute
+// canned hits.
+type ScrollbackSearcher interface {
+	Search(pattern string) ([]grep.Hit, error)
+}
+
+// grepResultsMsg carries a finished search back to the model. seq is the
+// grepSeq the search was started under, so a search superseded by a newer one
+// (or abandoned with Esc) is dropped rather than overwriting the page.
+type grepResultsMsg struct {
+	seq  int
+	hits []grep.Hit
+	err  error
+}
+
+// grepPage is the results page state: the submitted pattern, the hits once
+// the search lands, and the cursor/scroll offset into them.
+type grepPage struct {
+	pattern   string
+	searching bool
+	hits      []grep.Hit
+	err       error
+	cursor    int
+	offset    int
+}
+
+// grepContextRows is the number of rows reserved under the hit list for the
+// highlighted hit's context: a blank spacer plus the hit line and up to two
+// lines either side (the production searcher's context).
+const grepContextRows = 6
+
+// handleGrepKey opens the find-in-scrollback prompt. A no-op without a
+// searcher wired.
+func (m Model) handleGrepKey() (tea.Model, tea.Cmd) {
+	if m.searcher == nil {
+		return m, nil
+	}
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.SetValue(m.grepPage.pattern)
+	ti.CursorEnd()
+	ti.Focus()
+	m.grepInput = ti
+	m.modal = modalGrep
+	return m, nil
+}
+
+// updateGrepModal drives the pattern prompt: Enter submits a non-blank
+// pattern, Esc cancels, everything else edits the input.
+func (m Model) updateGrepModal(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyPressMsg); ok {
+		switch keyMsg.Code {
+		case tea.KeyEnter:
+			pattern := m.grepInput.Value()
+			if strings.TrimSpace(pattern) == "" {
+				return m, nil
+			}
+			m.modal = modalNone
+			return m.startGrep(pattern)
+		case tea.KeyEscape:
+			m.modal = modalNone
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.grepInput, cmd = m.grepInput.Update(msg)
+	return m, cmd
+}
+
+// startGrep flips to the results page in its searching state and runs the
+// search off the UI thread.
+func (m Model) startGrep(pattern string) (tea.Model, tea.Cmd) {
+	m.grepSeq++
+	seq := m.grepSeq
+	m.grepPage = grepPage{pattern: pattern, searching: true}
+	m.activePage = pageGrep
+	searcher := m.searcher
+	return m, func() tea.Msg {
+		hits, err := searcher.Search(pattern)
+		return grepResultsMsg{seq: seq, hits: hits, err: err}
+	}
+}
+
+// applyGrepResults lands a finished search on the results page, unless it is
+// stale: superseded by a newer search or abandoned by leaving the page.
+func (m Model) applyGrepResults(msg grepResultsMsg) Model {
+	if msg.seq != m.grepSeq || m.activePage != pageGrep {
+		return m
+	}
+	m.grepPage.searching = false
+	m.grepPage.hits = msg.hits
+	m.grepPage.err = msg.err
+	return m
+}
+
+// updateGrepPage handles keys on the results page. ↑/↓ move the cursor, Enter
+// attaches the highlighted hit's pane, Esc returns to the Sessions page.
+func (m Model) updateGrepPage(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyPressMsg)
+	if !ok {
+		return m, nil
+	}
+	if keyIsCtrlC(keyMsg) {
+		return m, tea.Quit
+	}
+	if m.flashText != "" && isActionableKey(keyMsg) {
+		m.clearFlash()
+	}
+	switch {
+	case keyIsCode(keyMsg, tea.KeyEscape):
+		m.activePage = PageSessions
+		return m, nil
+	case keyIsCode(keyMsg, tea.KeyUp):
+		m.moveGrepCursor(-1)
+	case keyIsCode(keyMsg, tea.KeyDown):
+		m.moveGrepCursor(+1)
+	case keyIsCode(keyMsg, tea.KeyEnter):
+		return m.attachGrepHit()
+	}
+	return m, nil
+}
+
+// moveGrepCursor steps the cursor by delta, clamped to the hits, and scrolls
+// the list so the cursor stays within the visible rows.
+func (m *Model) moveGrepCursor(delta int) {
+	p := &m.grepPage
+	if len(p.hits) == 0 {
+		return
+	}
+	p.cursor = min(max(p.cursor+delta, 0), len(p.hits)-1)
+	rows := m.grepListRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+}
+
+// attachGrepHit hands the highlighted hit to the preview-attach pipeline,
+// which pre-selects its window and pane and quits into the session. A hit in
+// a session that is not running (read from its saved scrollback) cannot be
+// attached and flashes instead.
+func (m Model) attachGrepHit() (tea.Model, tea.Cmd) {
+	p := m.grepPage
+	if p.searching || len(p.hits) == 0 || m.previewAttacher == nil {
+		return m, nil
+	}
+	hit := p.hits[p.cursor]
+	if !hit.Live {
+		m.setFlash(fmt.Sprintf(`session "%s" is not running`, hit.Session))
+		return m, flashTickCmd(m.flashGen)
+	}
+	return m, m.previewAttacher.Run(hit.Session, hit.Window, hit.Pane.Pane)
+}
+
+// grepListRows is the number of hit rows that fit between the section header
+// (plus its spacer) and the context block + footer.
+func (m Model) grepListRows() int {
+	return max(m.contentHeight()-2-grepContextRows-2, 1)
+}
+
+// viewGrep renders the results page into the inset content region: the
+// section header, the hit rows, the highlighted hit's context and the footer.
+func (m Model) viewGrep() string {
+	w := headerWidthOrFallback(m.contentWidth())
+	mode, colourless := m.canvasMode, m.colourless
+	p := m.grepPage
+	detail := headerStyle(theme.MV.TextDetail, mode, colourless)
+
+	rows := []string{m.grepSectionHeader(w), ""}
+	listRows := m.grepListRows()
+
+	var body []string
+	switch {
+	case p.searching:
+		body = []string{detail.Render("  Searching…")}
+	case p.err != nil:
+		body = []string{headerStyle(theme.MV.StateRed, mode, colourless).Render("  " + ansi.Truncate(p.err.Error(), w-2, "…"))}
+	case len(p.hits) == 0:
+		body = []string{detail.Render(fmt.Sprintf("  No scrollback matches %q", p.pattern))}
+	default:
+		end := min(p.offset+listRows, len(p.hits))
+		for i := p.offset; i < end; i++ {
+			body = append(body, m.grepHitRow(p.hits[i], i == p.cursor, w))
+		}
+	}
+	for len(body) < listRows {
+		body = append(body, "")
+	}
+	rows = append(rows, body...)
+	rows = append(rows, m.grepContextBlock(w)...)
+	rows = append(rows, renderCondensedFooter(grepKeymap(), w, mode, colourless))
+	// A flash (the not-running notice) takes the header's spacer row, so the
+	// page height is unchanged.
+	if band := m.renderActiveNoticeBand(); band != "" {
+		rows[1] = band
+	}
+	return strings.Join(rows, "\n")
+}
+
+// grepSectionHeader renders `Scrollback N matching "<pattern>"` with the
+// `esc back` hint right-aligned, through the shared section-row core.
+func (m Model) grepSectionHeader(w int) string {
+	mode, colourless := m.canvasMode, m.colourless
+	p := m.grepPage
+	gap := headerCanvasBg(mode, colourless).Render(" ")
+	left := headerStyle(theme.MV.AccentCyan, mode, colourless).Render("Scrollback")
+	if !p.searching && p.err == nil {
+		left = lipgloss.JoinHorizontal(lipgloss.Top, left, gap,
+			headerStyle(theme.MV.StateGreen, mode, colourless).Render(strconv.Itoa(len(p.hits))))
+	}
+	left = lipgloss.JoinHorizontal(lipgloss.Top, left, gap,
+		headerStyle(theme.MV.TextDetail, mode, colourless).Render(fmt.Sprintf("matching %q", p.pattern)))
+	hint := headerStyle(theme.MV.TextDetail, mode, colourless).Render("esc back")
+	return renderRightAnchoredSectionRow(left, hint, w, mode, colourless)
+}
+
+// grepHitRow renders one hit: the selector bar on the cursor row, the
+// session:window.pane:line location in text.detail, then the matching line
+// truncated to the row width.
+func (m Model) grepHitRow(hit grep.Hit, selected bool, w int) string {
+	mode, colourless := m.canvasMode, m.colourless
+	bar := "  "
+	if selected {
+		bar = headerStyle(theme.MV.AccentViolet, mode, colourless).Render(selectorBar) + " "
+	}
+	loc := fmt.Sprintf("%s:%d  ", hit.Target(), hit.Line)
+	textTok := theme.MV.TextStrong
+	if selected {
+		textTok = theme.MV.TextOnSelection
+	}
+	budget := max(w-leftBarColumnWidth-lipgloss.Width(loc), 1)
+	return bar +
+		headerStyle(theme.MV.TextDetail, mode, colourless).Render(loc) +
+		headerStyle(textTok, mode, colourless).Render(ansi.Truncate(hit.Text, budget, "…"))
+}
+
+// grepContextBlock renders the highlighted hit in place with its context
+// lines either side, line-numbered, the hit line in text.primary and its
+// context in text.dim. It always returns grepContextRows rows so the footer
+// does not move as the cursor does.
+func (m Model) grepContextBlock(w int) []string {
+	mode, colourless := m.canvasMode, m.colourless
+	rows := []string{""}
+	p := m.grepPage
+	if !p.searching && p.err == nil && len(p.hits) > 0 {
+		hit := p.hits[p.cursor]
+		numWidth := len(strconv.Itoa(hit.Line + len(hit.After)))
+		line := func(n int, text string, tok theme.Token) string {
+			prefix := fmt.Sprintf("  %*d  ", numWidth, n)
+			budget := max(w-len(prefix), 1)
+			return headerStyle(theme.MV.TextFaint, mode, colourless).Render(prefix) +
+				headerStyle(tok, mode, colourless).Render(ansi.Truncate(text, budget, "…"))
+		}
+		first := hit.Line - len(hit.Before)
+		for i, text := range hit.Before {
+			rows = append(rows, line(first+i, text, theme.MV.TextDim))
+		}
+		rows = append(rows, line(hit.Line, hit.Text, theme.MV.TextPrimary))
+		for i, text := range hit.After {
+			rows = append(rows, line(hit.Line+1+i, text, theme.MV.TextDim))
+		}
+	}
+	for len(rows) < grepContextRows {
+		rows = append(rows, "")
+	}
+	return rows[:grepContextRows]
+}
@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/grep"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// pagegrep_test.go pins the find-in-scrollback picker mode: f opens the
+// pattern prompt, Enter runs the search and lands its hits on pageGrep, the
+// cursor moves over the hits, and Enter hands the highlighted pane to the
+// PreviewAttacher pipeline.
+
+// stubSearcher returns canned hits and records every pattern searched.
+type stubSearcher struct {
+	hits     []grep.Hit
+	err      error
+	patterns []string
+}
+
+func (s *stubSearcher) Search(pattern string) ([]grep.Hit, error) {
+	s.patterns = append(s.patterns, pattern)
+	return s.hits, s.err
+}
+
+func grepTestHits() []grep.Hit {
+	return []grep.Hit{
+		{Pane: grep.Pane{Session: "api", Window: 1, Pane: 0, Live: true}, Line: 40, Text: "panic: nil map", Before: []string{"starting"}, After: []string{"goroutine 1"}},
+		{Pane: grep.Pane{Session: "web", Window: 0, Pane: 2, Live: true}, Line: 7, Text: "panic: timeout"},
+		{Pane: grep.Pane{Session: "old", Window: 0, Pane: 0}, Line: 3, Text: "panic: saved"},
+	}
+}
+
+// grepModel returns a Sessions-page model with a searcher and attacher wired.
+func grepModel(searcher *stubSearcher, attacher PreviewAttacher) Model {
+	m := modelWithSeams([]tmux.Session{{Name: "api", Windows: 1}}, nil, nil)
+	m.searcher = searcher
+	m.previewAttacher = attacher
+	return m
+}
+
+// searchFor drives f, types pattern, presses Enter, and runs the search cmd
+// back through Update.
+func searchFor(t *testing.T, m Model, pattern string) Model {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	m = updated.(Model)
+	if m.modal != modalGrep {
+		t.Fatalf("modal after f = %v, want modalGrep", m.modal)
+	}
+	for _, r := range pattern {
+		updated, _ = m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.activePage != pageGrep || !m.grepPage.searching {
+		t.Fatalf("after Enter: page = %v searching = %v, want pageGrep searching", m.activePage, m.grepPage.searching)
+	}
+	if cmd == nil {
+		t.Fatal("Enter returned no search cmd")
+	}
+	updated, _ = m.Update(cmd())
+	return updated.(Model)
+}
+
+func TestGrepPage_SearchLandsHits(t *testing.T) {
+	searcher := &stubSearcher{hits: grepTestHits()}
+	m := searchFor(t, grepModel(searcher, nil), "panic")
+
+	if len(searcher.patterns) != 1 || searcher.patterns[0] != "panic" {
+		t.Errorf("searched %v, want [panic]", searcher.patterns)
+	}
+	if m.grepPage.searching || len(m.grepPage.hits) != 3 {
+		t.Fatalf("grepPage = %+v, want three landed hits", m.grepPage)
+	}
+	view := stripANSI(m.viewGrep())
+	for _, want := range []string{`Scrollback 3 matching "panic"`, "api:1.0:40", "panic: nil map", "starting", "goroutine 1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestGrepPage_EmptyAndErrorStates(t *testing.T) {
+	m := searchFor(t, grepModel(&stubSearcher{}, nil), "zzz")
+	if view := stripANSI(m.viewGrep()); !strings.Contains(view, `No scrollback matches "zzz"`) {
+		t.Errorf("empty view = %q", view)
+	}
+
+	m = searchFor(t, grepModel(&stubSearcher{err: errors.New("corrupt sessions.json")}, nil), "x")
+	if view := stripANSI(m.viewGrep()); !strings.Contains(view, "corrupt sessions.json") {
+		t.Errorf("error view = %q", view)
+	}
+}
+
+func TestGrepPage_PromptIgnoresBlankAndEscCancels(t *testing.T) {
+	m := grepModel(&stubSearcher{}, nil)
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+	if m.modal != modalGrep || cmd != nil {
+		t.Errorf("blank Enter: modal = %v cmd = %v, want the prompt kept open", m.modal, cmd)
+	}
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(Model)
+	if m.modal != modalNone || m.activePage != PageSessions {
+		t.Errorf("Esc: modal = %v page = %v, want the prompt closed on Sessions", m.modal, m.activePage)
+	}
+}
+
+func TestGrepPage_FWithoutSearcherIsInert(t *testing.T) {
+	m := grepModel(nil, nil)
+	m.searcher = nil
+	updated, _ := m.Update(tea.KeyPressMsg{Code: 'f', Text: "f"})
+	if updated.(Model).modal != modalNone {
+		t.Error("f opened the prompt with no searcher wired")
+	}
+}
+
+func TestGrepPage_EnterAttachesHighlightedPane(t *testing.T) {
+	attacher := &fakePreviewAttacher{}
+	m := searchFor(t, grepModel(&stubSearcher{hits: grepTestHits()}, attacher), "panic")
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	m = updated.(Model)
+	if m.grepPage.cursor != 1 {
+		t.Fatalf("cursor after ↓ = %d, want 1", m.grepPage.cursor)
+	}
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	if cmd == nil || len(attacher.calls) != 1 {
+		t.Fatalf("attacher calls = %v, want one", attacher.calls)
+	}
+	if got, want := attacher.calls[0], (recordedAttacherCall{session: "web", window: 0, pane: 2}); got != want {
+		t.Errorf("Run(%+v), want %+v", got, want)
+	}
+}
+
+func TestGrepPage_EnterOnSavedOnlyHitFlashes(t *testing.T) {
+	attacher := &fakePreviewAttacher{}
+	m := searchFor(t, grepModel(&stubSearcher{hits: grepTestHits()}, attacher), "panic")
+	for range 5 { // past the end clamps to the last hit
+		updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+		m = updated.(Model)
+	}
+
+	updated, _ := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m = updated.(Model)
+
+	if len(attacher.calls) != 0 {
+		t.Errorf("attacher calls = %v, want none for a session that is not running", attacher.calls)
+	}
+	if !strings.Contains(m.flashText, `"old" is not running`) {
+		t.Errorf("flashText = %q", m.flashText)
+	}
+}
+
+func TestGrepPage_EscReturnsAndDropsLateResults(t *testing.T) {
+	m := grepModel(&stubSearcher{hits: grepTestHits()}, nil)
+	updated, cmd := m.startGrep("panic")
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	m = updated.(Model)
+	if m.activePage != PageSessions {
+		t.Fatalf("page after Esc = %v, want PageSessions", m.activePage)
+	}
+
+	updated, _ = m.Update(cmd())
+	if got := updated.(Model); got.activePage != PageSessions || got.grepPage.hits != nil {
+		t.Errorf("late results changed the model: page = %v hits = %v", got.activePage, got.grepPage.hits)
+	}
+}
+
+func TestGrepPage_StaleSearchIsDropped(t *testing.T) {
+	m := grepModel(&stubSearcher{hits: grepTestHits()}, nil)
+	updated, _ := m.startGrep("first")
+	m = updated.(Model)
+	updated, _ = m.startGrep("second")
+	m = updated.(Model)
+
+	updated, _ = m.Update(grepResultsMsg{seq: m.grepSeq - 1, hits: grepTestHits()})
+	if got := updated.(Model); !got.grepPage.searching {
+		t.Error("a superseded search's results landed on the page")
+	}
+}
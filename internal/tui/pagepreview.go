@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
@@ -71,6 +72,33 @@ const previewPlaceholder = "(no saved content)"
 // read fresh via the dispatcher.
 const previewReadError = "(unable to read scrollback)"
 
+// previewRefreshInterval is how often an open preview with a live PaneCapturer
+// re-captures the focused pane, so a running build or agent can be watched
+// without attaching.
+const previewRefreshInterval = time.Second
+
+// previewRefreshMsg is the auto-refresh tick. seq is the refreshSeq of the
+// preview that scheduled it: a tick outliving its preview (dismissed, or
+// dismissed and reopened within the interval) carries a stale seq and is
+// dropped, so only one tick chain ever drives the open preview.
+type previewRefreshMsg struct {
+	seq int
+}
+
+// previewCapturedMsg carries a finished off-thread read of one pane (see
+// captureFocusedPane): its viewport content and saved command line. seq and
+// target identify the preview and pane it was read for, so a capture landing
+// after a dismiss or a focus change is dropped. tick marks a capture started by
+// the auto-refresh tick, whose arrival schedules the next one — a slow
+// capture-pane therefore delays the chain instead of piling captures up.
+type previewCapturedMsg struct {
+	seq     int
+	target  string
+	content string
+	argv    string
+	tick    bool
+}
+
 // truncateToCells returns s clipped to fit within a budget measured in display
 // cells, appending the single-rune ellipsis "…" only when truncation actually
 // occurred. Cells are measured per runewidth.RuneWidth — ASCII = 1, CJK = 2,
@@ -236,6 +264,23 @@ func composePreviewHeaderRow(contentWidth, windowIdx, windowCount, paneIdx, pane
 	return row
 }
 
+// appendPreviewArgv appends the focused pane's command line to a composed header
+// row as a trailing text.detail segment, truncated to the width the cascade left
+// over. When fewer than minSessionNameCells remain (or there is no argv) the row
+// is returned unchanged.
+func appendPreviewArgv(row string, contentWidth int, argv string, mode theme.Mode, colourless bool) string {
+	if argv == "" {
+		return row
+	}
+	budget := contentWidth - lipgloss.Width(row) - lipgloss.Width(chromeSegSpace)
+	if budget < minSessionNameCells {
+		return row
+	}
+	gap := headerCanvasBg(mode, colourless).Render(chromeSegSpace)
+	seg := headerStyle(theme.MV.TextDetail, mode, colourless).Render(truncateToCells(argv, budget))
+	return lipgloss.JoinHorizontal(lipgloss.Top, row, gap, seg)
+}
+
 // previewFooterGroups derives the §9.1 footer nav-hint groups from the Core
 // entries of the shared previewKeymap descriptor (§12.1) — the single source of
 // truth that also feeds the ? help. Each Core entry's glyph is the descriptor
@@ -306,13 +351,15 @@ func previewFooterFromGroups(groups []footerHintGroup, labelled bool, mode theme
 	return strings.Join(rendered, gap)
 }
 
-// previewModel renders a single tmux pane's saved scrollback inside a
-// viewport, wrapped in the §9.1 full-screen accent.cyan joined panel (header,
+// previewModel renders a single tmux pane's content — captured live when a
+// PaneCapturer is wired and the server answers, the saved scrollback
+// otherwise — inside a viewport, wrapped in the §9.1 full-screen accent.cyan joined panel (header,
 // body, footer compartments) composed by View().
 //
-// Construction is performed via NewPreviewModel — the type is intentionally
-// unexported so the constructor is the only way to wire one up. Both seams
-// (TmuxEnumerator and ScrollbackReader) are constructor-injected; there is no
+// Construction is performed via NewLivePreviewModel (or NewPreviewModel, its
+// saved-scrollback-only form) — the type is intentionally unexported so the
+// constructors are the only way to wire one up. The seams (TmuxEnumerator,
+// PaneCapturer and ScrollbackReader) are constructor-injected; there is no
 // package-level seam variable for preview.
 //
 // Zero value reserved for "between opens"; methods must not be called on a
@@ -321,6 +368,7 @@ func previewFooterFromGroups(groups []footerHintGroup, labelled bool, mode theme
 type previewModel struct {
 	session    string
 	enumerator TmuxEnumerator
+	capturer   PaneCapturer
 	reader     ScrollbackReader
 	attacher   PreviewAttacher
 	groups     []tmux.WindowGroup
@@ -344,6 +392,13 @@ type previewModel struct {
 	// and the preview is key-exclusive: `?` toggles it closed, `Esc` dismisses it
 	// (without backing out of the preview), and every other preview key is inert.
 	helpOpen bool
+	// refreshSeq identifies this preview's auto-refresh tick chain. The parent
+	// model assigns a fresh value per open; see previewRefreshMsg.
+	refreshSeq int
+	// argv is the focused pane's saved command line, space-joined, or "" when
+	// the reader has none. Re-read on every focus change and refresh tick so
+	// View never touches the index.
+	argv string
 }
 
 // NewPreviewModel performs the initial-open ordering inline:
@@ -359,7 +414,18 @@ type previewModel struct {
 // The (nil, err) error-string branch is owned by Phase 4 task 4-2; this
 // constructor does not encode error wording itself — it delegates to the
 // shared helper.
+//
+// NewPreviewModel never captures live: it is NewLivePreviewModel with a nil
+// PaneCapturer, reading only the saved scrollback.
 func NewPreviewModel(session string, enumerator TmuxEnumerator, reader ScrollbackReader, attacher PreviewAttacher, width, height int) (previewModel, bool) {
+	return NewLivePreviewModel(session, enumerator, nil, reader, attacher, width, height)
+}
+
+// NewLivePreviewModel is NewPreviewModel with a PaneCapturer: the focused pane
+// opens on its saved scrollback and captureFocusedPane replaces it with a live
+// capture off the UI thread. A nil capturer yields the saved-scrollback-only
+// preview.
+func NewLivePreviewModel(session string, enumerator TmuxEnumerator, capturer PaneCapturer, reader ScrollbackReader, attacher PreviewAttacher, width, height int) (previewModel, bool) {
 	groups, err := enumerator.ListWindowsAndPanesInSession(session)
 	if err != nil {
 		return previewModel{}, false
@@ -375,6 +441,7 @@ func NewPreviewModel(session string, enumerator TmuxEnumerator, reader Scrollbac
 	m := previewModel{
 		session:    session,
 		enumerator: enumerator,
+		capturer:   capturer,
 		reader:     reader,
 		attacher:   attacher,
 		groups:     groups,
@@ -396,6 +463,7 @@ func NewPreviewModel(session string, enumerator TmuxEnumerator, reader Scrollbac
 	// position at the top, so the helper jumps explicitly to satisfy the
 	// "anchored at scroll-tail" contract.
 	m.viewport = m.readFocusedPaneIntoViewport()
+	m.argv = m.focusedPaneArgv()
 
 	return m, true
 }
@@ -480,26 +548,121 @@ func (m previewModel) innerHeight() int {
 // keeps every method on previewModel a value receiver, eliminating the
 // latent bug class where future non-copyable fields could silently desync
 // after a value copy.
+//
+// It never captures live: with a PaneCapturer wired, the live capture lands
+// afterwards through captureFocusedPane.
 func (m previewModel) readFocusedPaneIntoViewport() viewport.Model {
 	vp := m.viewport
-	bytes, err := m.reader.Tail(m.currentPaneKey())
+	vp.SetContent(savedPaneContent(m.reader, m.currentPaneKey()))
+	vp.GotoBottom()
+	return vp
+}
+
+// focusedPaneTarget is the exact tmux target of the focused pane.
+func (m previewModel) focusedPaneTarget() string {
+	rawWindow, rawPane := m.currentRawIndices()
+	return tmux.PaneTargetExact(m.session, rawWindow, rawPane)
+}
+
+// captureFocusedPane reads the focused pane off the UI thread — capture-pane
+// forks tmux, which must not stall Update — and delivers the result as a
+// previewCapturedMsg. It returns nil when no PaneCapturer is wired. tick marks
+// the auto-refresh chain's capture; see previewCapturedMsg.
+func (m previewModel) captureFocusedPane(tick bool) tea.Cmd {
+	if m.capturer == nil {
+		return nil
+	}
+	seq, target, key := m.refreshSeq, m.focusedPaneTarget(), m.currentPaneKey()
+	capturer, reader := m.capturer, m.reader
+	return func() tea.Msg {
+		return previewCapturedMsg{
+			seq:     seq,
+			target:  target,
+			content: livePaneContent(capturer, reader, target, key),
+			argv:    paneArgv(reader, key),
+			tick:    tick,
+		}
+	}
+}
+
+// livePaneContent returns the viewport content for a pane: its live capture
+// when the capturer answers with anything but blank lines, else the
+// saved-scrollback outcome (savedPaneContent). A blank live capture (a pane
+// that has printed nothing yet) defers to the saved file rather than rendering
+// an empty body.
+func livePaneContent(capturer PaneCapturer, reader ScrollbackReader, target, key string) string {
+	out, err := capturer.CapturePaneTail(target, previewTailLines)
+	// capture-pane pads the unused rows of the screen with empty lines;
+	// trimming them keeps scroll-tail on the last printed line.
+	if live := strings.TrimRight(out, "\n"); err == nil && live != "" {
+		return live
+	}
+	return savedPaneContent(reader, key)
+}
+
+// savedPaneContent returns the viewport content for a pane's saved scrollback
+// per the three-shape contract documented on readFocusedPaneIntoViewport.
+func savedPaneContent(reader ScrollbackReader, key string) string {
+	bytes, err := reader.Tail(key)
 	switch {
 	case bytes == nil && err == nil:
-		vp.SetContent(previewPlaceholder)
+		return previewPlaceholder
 	// The (nil, nil) arm above takes precedence so the (nil, err) shape from
 	// the spec's three-shape contract lands here cleanly. The helper never
 	// returns (bytes != nil, err != nil); this arm is shaped defensively to
 	// route any such future drift to the user-visible error string rather
 	// than silently rendering bytes alongside an ignored error.
 	case err != nil:
-		vp.SetContent(previewReadError)
+		return previewReadError
 	default:
-		vp.SetContent(string(bytes))
+		return string(bytes)
+	}
+}
+
+// focusedPaneArgv returns the focused pane's saved command line for the header,
+// or "" when the reader does not implement PaneArgvReader or saved none.
+func (m previewModel) focusedPaneArgv() string {
+	return paneArgv(m.reader, m.currentPaneKey())
+}
+
+// paneArgv is focusedPaneArgv for an explicit pane key, usable off the UI
+// thread.
+func paneArgv(reader ScrollbackReader, key string) string {
+	r, ok := reader.(PaneArgvReader)
+	if !ok {
+		return ""
+	}
+	return strings.Join(r.Argv(key), " ")
+}
+
+// applyCapture lands a finished capture in the viewport. Unlike
+// readFocusedPaneIntoViewport it keeps the reader's place: a viewport parked
+// at scroll-tail follows new output, one scrolled up stays where it is.
+func (m previewModel) applyCapture(content string) viewport.Model {
+	vp := m.viewport
+	atBottom, yOffset := vp.AtBottom(), vp.YOffset()
+	vp.SetContent(content)
+	if atBottom {
+		vp.GotoBottom()
+	} else {
+		vp.SetYOffset(yOffset)
 	}
-	vp.GotoBottom()
 	return vp
 }
 
+// scheduleRefresh returns the next auto-refresh tick, or nil when no
+// PaneCapturer is wired — the saved scrollback only changes on a daemon tick,
+// so a saved-only preview has nothing to poll for.
+func (m previewModel) scheduleRefresh() tea.Cmd {
+	if m.capturer == nil {
+		return nil
+	}
+	seq := m.refreshSeq
+	return tea.Tick(previewRefreshInterval, func(time.Time) tea.Msg {
+		return previewRefreshMsg{seq: seq}
+	})
+}
+
 // previewDismissedMsg is emitted when the user presses Esc inside the
 // preview page. The top-level Update consumes it to flip activePage back
 // to PageSessions without mutating the underlying sessionList — preserving
@@ -542,9 +705,29 @@ type previewSessionsRefreshedMsg struct {
 // the already-loaded N-line buffer per § Refresh Semantics (resize is not a
 // read trigger; viewport-internal scroll does not re-read). The window/pane
 // cycle IS a read trigger — each lands a single synchronous Tail for the newly
-// focused pane.
+// focused pane, then (with a PaneCapturer) an off-thread live capture. So is a
+// previewRefreshMsg carrying this preview's refreshSeq, which starts a capture
+// whose previewCapturedMsg updates the pane in place and schedules the next
+// tick.
 func (m previewModel) Update(msg tea.Msg) (previewModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case previewRefreshMsg:
+		if msg.seq != m.refreshSeq {
+			return m, nil
+		}
+		return m, m.captureFocusedPane(true)
+	case previewCapturedMsg:
+		if msg.seq != m.refreshSeq {
+			return m, nil
+		}
+		if msg.target == m.focusedPaneTarget() {
+			m.viewport = m.applyCapture(msg.content)
+			m.argv = msg.argv
+		}
+		if msg.tick {
+			return m, m.scheduleRefresh()
+		}
+		return m, nil
 	case tea.WindowSizeMsg:
 		// bubbles v2 exposes viewport.SetWidth / SetHeight (the spec's
 		// `viewport.SetSize(W, H)` — the v1.0.0 TODO that this upgrade
@@ -627,13 +810,16 @@ func (m previewModel) handlePreviewKey(msg tea.KeyPressMsg) (handled bool, next
 	// embedded viewport never swallows Tab. Degenerate single-pane windows are a
 	// silent no-op (cyclePane guards paneCount <= 1).
 	case keyIsCode(msg, tea.KeyTab):
-		return true, m.cyclePane(+1), nil
+		next := m.cyclePane(+1)
+		return true, next, next.captureFocusedPane(false)
 	// ← / → — prev / next window (REPLACES the former ]/[). Intercepted before
 	// the viewport sees them so window nav wins over horizontal scroll.
 	case keyIsCode(msg, tea.KeyLeft):
-		return true, m.cycleWindow(-1), nil
+		next := m.cycleWindow(-1)
+		return true, next, next.captureFocusedPane(false)
 	case keyIsCode(msg, tea.KeyRight):
-		return true, m.cycleWindow(+1), nil
+		next := m.cycleWindow(+1)
+		return true, next, next.captureFocusedPane(false)
 	}
 	return false, previewModel{}, nil
 }
@@ -651,6 +837,7 @@ func (m previewModel) cycleWindow(delta int) previewModel {
 	m.windowIdx = (m.windowIdx + delta + len(m.groups)) % len(m.groups)
 	m.paneIdx = 0
 	m.viewport = m.readFocusedPaneIntoViewport()
+	m.argv = m.focusedPaneArgv()
 	return m
 }
 
@@ -664,6 +851,7 @@ func (m previewModel) cyclePane(delta int) previewModel {
 	}
 	m.paneIdx = (m.paneIdx + delta + paneCount) % paneCount
 	m.viewport = m.readFocusedPaneIntoViewport()
+	m.argv = m.focusedPaneArgv()
 	return m
 }
 
@@ -672,7 +860,8 @@ func (m previewModel) cyclePane(delta int) previewModel {
 // via renderJoinedPanel) with THREE compartments:
 //
 //   - Header: `◉ preview` (accent.cyan) + session (text.primary) +
-//     `Window x/y · Pane x/y` (text.detail), width-cascaded to fit.
+//     `Window x/y · Pane x/y` (text.detail), width-cascaded to fit, then the
+//     focused pane's saved command line (text.detail) in whatever width is left.
 //   - Body: the untouched captured ANSI scrollback (§9.2) — passed through
 //     injectSGRResets so unterminated SGR sequences cannot bleed into the right
 //     border, and NEVER themed. The viewport is sized to innerHeight so the body
@@ -695,6 +884,11 @@ func (m previewModel) View() string {
 		m.session,
 		m.mode, m.colourless,
 	)
+	// The argv segment ranks below the counters: once the cascade drops them
+	// there is no room worth spending on a command line.
+	if segs := selectPreviewHeaderTier(contentWidth, m.session, m.windowIdx, len(m.groups), m.paneIdx, len(m.currentGroup().PaneIndices)); segs.counters != "" {
+		header = appendPreviewArgv(header, contentWidth, m.argv, m.mode, m.colourless)
+	}
 	footer := composePreviewFooterRow(contentWidth, m.mode, m.colourless)
 
 	// The body rows are the untouched captured ANSI — split per line so each is a
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/leeovery/portal/internal/tmux"
+)
+
+// pagepreview_live_test.go pins the live preview: with a PaneCapturer wired the
+// focused pane is captured live off the UI thread (bounded to
+// previewTailLines), a failed or blank capture falls back to the saved
+// scrollback, and an open live preview re-captures on every refresh tick
+// carrying its own refreshSeq while keeping the reader's scroll position.
+
+// stubCapturer returns the configured capture and records every target.
+type stubCapturer struct {
+	out     string
+	err     error
+	targets []string
+	lines   []int
+}
+
+func (c *stubCapturer) CapturePaneTail(target string, lines int) (string, error) {
+	c.targets = append(c.targets, target)
+	c.lines = append(c.lines, lines)
+	return c.out, c.err
+}
+
+func twoPaneEnumerator() *stubEnumerator {
+	return &stubEnumerator{groups: []tmux.WindowGroup{
+		{WindowIndex: 1, WindowName: "main", PaneIndices: []int{1, 2}},
+	}}
+}
+
+// runCapture runs a preview cmd (a capture) and feeds its message back through
+// Update, as the Bubble Tea runtime would.
+func runCapture(t *testing.T, m previewModel, cmd tea.Cmd) (previewModel, tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a capture cmd, got nil")
+	}
+	msg, ok := cmd().(previewCapturedMsg)
+	if !ok {
+		t.Fatalf("cmd produced %T, want previewCapturedMsg", msg)
+	}
+	return m.Update(msg)
+}
+
+func TestLivePreview_CapturesFocusedPane(t *testing.T) {
+	capturer := &stubCapturer{out: "live build output\n\n\n"}
+	reader := &recordingReader{bytes: []byte("saved")}
+
+	m, ok := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, reader, nil, 80, 24)
+	if !ok {
+		t.Fatal("NewLivePreviewModel returned ok=false")
+	}
+	if len(capturer.targets) != 0 {
+		t.Errorf("captures = %v, want none on the UI thread", capturer.targets)
+	}
+	if view := stripANSI(m.viewport.View()); !strings.Contains(view, "saved") {
+		t.Errorf("viewport = %q, want the saved scrollback until the capture lands", view)
+	}
+
+	m, _ = runCapture(t, m, m.captureFocusedPane(false))
+
+	if len(capturer.targets) != 1 || capturer.targets[0] != "=work:1.1" || capturer.lines[0] != previewTailLines {
+		t.Errorf("captures = %v (lines %v), want one =work:1.1 capture of %d lines", capturer.targets, capturer.lines, previewTailLines)
+	}
+	view := stripANSI(m.viewport.View())
+	if !strings.Contains(view, "live build output") || strings.Contains(view, "saved") {
+		t.Errorf("viewport = %q, want the live capture", view)
+	}
+
+	m, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	if len(capturer.targets) != 1 {
+		t.Errorf("captures = %v, want Tab to leave the capture to its cmd", capturer.targets)
+	}
+	runCapture(t, m, cmd)
+	if got := capturer.targets[len(capturer.targets)-1]; got != "=work:1.2" {
+		t.Errorf("capture after Tab = %q, want =work:1.2", got)
+	}
+}
+
+func TestLivePreview_DropsCaptureForUnfocusedPane(t *testing.T) {
+	capturer := &stubCapturer{out: "pane one"}
+	m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, &recordingReader{bytes: []byte("saved")}, nil, 80, 24)
+	stale := m.captureFocusedPane(false)
+
+	m = m.cyclePane(+1)
+	m, _ = runCapture(t, m, stale)
+
+	if view := stripANSI(m.viewport.View()); strings.Contains(view, "pane one") {
+		t.Errorf("viewport = %q, want the capture of the previously focused pane dropped", view)
+	}
+}
+
+func TestLivePreview_FallsBackToSavedScrollback(t *testing.T) {
+	cases := []struct {
+		name     string
+		capturer *stubCapturer
+	}{
+		{"capture error (no server)", &stubCapturer{err: errors.New("no server running")}},
+		{"blank capture (fresh pane)", &stubCapturer{out: "\n\n\n"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &recordingReader{bytes: []byte("saved content")}
+
+			m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), tc.capturer, reader, nil, 80, 24)
+			m, _ = runCapture(t, m, m.captureFocusedPane(false))
+
+			if len(reader.calls) != 2 {
+				t.Errorf("reader.Tail calls = %v, want the open read plus one fallback read", reader.calls)
+			}
+			if view := stripANSI(m.viewport.View()); !strings.Contains(view, "saved content") {
+				t.Errorf("viewport = %q, want the saved scrollback", view)
+			}
+		})
+	}
+}
+
+func TestLivePreview_Refresh(t *testing.T) {
+	t.Run("Space on a live-wired model schedules the refresh tick", func(t *testing.T) {
+		m := modelWithSeams([]tmux.Session{{Name: "work", Windows: 1}}, twoPaneEnumerator(), &recordingReader{})
+		m.capturer = &stubCapturer{out: "x"}
+
+		updated, cmd := m.Update(keySpaceMsg())
+
+		if cmd == nil {
+			t.Fatal("expected a refresh tick cmd from a live preview open")
+		}
+		if seq := updated.(Model).preview.refreshSeq; seq == 0 {
+			t.Error("preview.refreshSeq = 0, want a fresh per-open sequence")
+		}
+	})
+
+	t.Run("a saved-only preview never schedules a tick", func(t *testing.T) {
+		m, _ := NewPreviewModel("work", twoPaneEnumerator(), &recordingReader{}, nil, 80, 24)
+		if cmd := m.scheduleRefresh(); cmd != nil {
+			t.Error("scheduleRefresh() on a saved-only preview returned a cmd, want nil")
+		}
+	})
+
+	t.Run("a matching tick re-captures and reschedules", func(t *testing.T) {
+		capturer := &stubCapturer{out: "first"}
+		m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, &recordingReader{}, nil, 80, 24)
+		m.refreshSeq = 3
+
+		capturer.out = "second"
+		m, cmd := m.Update(previewRefreshMsg{seq: 3})
+		if len(capturer.targets) != 0 {
+			t.Errorf("captures = %v, want the tick to leave the capture to its cmd", capturer.targets)
+		}
+		m, cmd = runCapture(t, m, cmd)
+
+		if view := stripANSI(m.viewport.View()); !strings.Contains(view, "second") {
+			t.Errorf("viewport = %q, want the refreshed capture", view)
+		}
+		if cmd == nil {
+			t.Error("expected the next tick to be scheduled")
+		}
+	})
+
+	t.Run("a stale tick is dropped", func(t *testing.T) {
+		capturer := &stubCapturer{out: "first"}
+		m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, &recordingReader{}, nil, 80, 24)
+		m.refreshSeq = 3
+
+		m, cmd := m.Update(previewRefreshMsg{seq: 2})
+
+		if len(capturer.targets) != 0 || cmd != nil {
+			t.Errorf("captures = %d, cmd = %v; want the stale tick ignored", len(capturer.targets), cmd)
+		}
+	})
+
+	t.Run("a refresh keeps a scrolled-up viewport in place", func(t *testing.T) {
+		capturer := &stubCapturer{out: strings.Repeat("line\n", 100)}
+		m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, &recordingReader{}, nil, 80, 24)
+		m, _ = runCapture(t, m, m.captureFocusedPane(false))
+		m.viewport.SetYOffset(10)
+
+		capturer.out += "more"
+		_, cmd := m.Update(previewRefreshMsg{seq: m.refreshSeq})
+		m, _ = runCapture(t, m, cmd)
+
+		if got := m.viewport.YOffset(); got != 10 {
+			t.Errorf("YOffset after refresh = %d, want 10", got)
+		}
+	})
+
+	t.Run("a refresh at scroll-tail follows new output", func(t *testing.T) {
+		capturer := &stubCapturer{out: strings.Repeat("line\n", 100) + "last"}
+		m, _ := NewLivePreviewModel("work", twoPaneEnumerator(), capturer, &recordingReader{}, nil, 80, 24)
+		m, _ = runCapture(t, m, m.captureFocusedPane(false))
+
+		capturer.out += "\nnewest"
+		_, cmd := m.Update(previewRefreshMsg{seq: m.refreshSeq})
+		m, _ = runCapture(t, m, cmd)
+
+		if !m.viewport.AtBottom() || !strings.Contains(stripANSI(m.viewport.View()), "newest") {
+			t.Errorf("viewport = %q, want it following the newest line", stripANSI(m.viewport.View()))
+		}
+	})
+}
@@ -305,3 +305,45 @@ func TestPreviewPeekChrome_ColourlessKeepsStructureDropsHue(t *testing.T) {
 		t.Errorf("colourless View() carries a foreground SGR; chrome must be colourless. out=%q", out)
 	}
 }
+
+// argvReader is a recordingReader that also implements PaneArgvReader, keyed by
+// paneKey.
+type argvReader struct {
+	recordingReader
+	argv map[string][]string
+}
+
+func (r *argvReader) Argv(paneKey string) []string { return r.argv[paneKey] }
+
+// TestPreviewPeekChrome_HeaderShowsFocusedPaneArgv pins that the focused
+// pane's saved command line trails the counters, follows Tab to the next pane,
+// and is dropped when the header has no room left for it.
+func TestPreviewPeekChrome_HeaderShowsFocusedPaneArgv(t *testing.T) {
+	groups := []tmux.WindowGroup{{WindowIndex: 0, WindowName: "main", PaneIndices: []int{0, 1}}}
+	reader := &argvReader{
+		recordingReader: recordingReader{bytes: []byte("hello\n")},
+		argv: map[string][]string{
+			"work__0.0": {"node", "server.js", "--port", "3000"},
+			"work__0.1": {"vim", "main.go"},
+		},
+	}
+	m, ok := NewPreviewModel("work", &stubEnumerator{groups: groups}, reader, nil, 120, 24)
+	if !ok {
+		t.Fatal("setup: expected ok=true from NewPreviewModel")
+	}
+
+	if header := stripANSI(headerLine(m.View())); !strings.Contains(header, "Pane 1/2 node server.js --port 3000") {
+		t.Errorf("header = %q; want the first pane's argv after the counters", header)
+	}
+
+	m, _ = m.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	header := stripANSI(headerLine(m.View()))
+	if !strings.Contains(header, "Pane 2/2 vim main.go") || strings.Contains(header, "node") {
+		t.Errorf("header after Tab = %q; want only the second pane's argv", header)
+	}
+
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 40, Height: 24})
+	if header := stripANSI(headerLine(m.View())); strings.Contains(header, "vim") {
+		t.Errorf("narrow header = %q; want the argv segment dropped", header)
+	}
+}
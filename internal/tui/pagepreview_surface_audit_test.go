@@ -14,8 +14,8 @@ import (
 //
 //   - internal/tmux: one new read-only listing method
 //     (ListWindowsAndPanesInSession) plus its WindowGroup result type and
-//     the unexported field-separator helper. NO new capture wrappers.
-//     CapturePane signature unchanged.
+//     the unexported field-separator helper. NO new capture wrappers
+//     beyond CapturePaneTail (below). CapturePane signature unchanged.
 //   - internal/state: one new tail-N helper, packaged alongside the
 //     existing scrollback writers. Existing writer surface preserved.
 //   - internal/restore, cmd/bootstrap, internal/hooks: untouched —
@@ -76,6 +76,12 @@ func readSourceFiles(t *testing.T, dir string) map[string]string {
 //	CapturePaneTail(target, n)) would have been net-new code. Always-disk
 //	avoids that addition entirely."
 //
+// The live-preview feature later reversed the always-disk decision
+// deliberately: the preview now captures the focused pane live through the
+// bounded CapturePaneTail (falling back to disk), so that one name is no
+// longer forbidden. The rest stay pinned — a second bounded variant would
+// duplicate it.
+//
 // The forbidden list captures the most plausible names a build phase might
 // have introduced. They are scoped to symbol shape ("func (... ) Name(" or
 // "func Name(") so a comment that happens to mention the word does not
@@ -94,7 +100,6 @@ func TestSurfaceAudit_TmuxNoNewCaptureWrapper(t *testing.T) {
 	// "(c *Client)" is the only declaration shape used in this file for
 	// public methods.
 	forbiddenSymbols := []string{
-		"CapturePaneTail",
 		"CapturePaneN",
 		"CaptureTail",
 		"CapturePaneLastN",
@@ -112,8 +117,9 @@ func TestSurfaceAudit_TmuxNoNewCaptureWrapper(t *testing.T) {
 				t.Errorf(
 					"%s declares forbidden capture-wrapper symbol %q; "+
 						"per spec § Source of Preview Bytes, preview must not "+
-						"introduce new tmux capture wrappers — the read pipeline "+
-						"is always-disk via state.ScrollbackFile + tail-N helper.",
+						"introduce new tmux capture wrappers — the live read goes "+
+						"through CapturePaneTail, the saved read via "+
+						"state.ScrollbackFile + tail-N helper.",
 					tmuxPath, sym,
 				)
 			}
@@ -290,16 +296,40 @@ func TestSurfaceAudit_NoNewPackageForPreview(t *testing.T) {
 	// allow-list must be updated deliberately — at which point the
 	// reviewer is forced to confirm it is not a preview package.
 	preExistingPackages := map[string]struct{}{
+		// agent: added by the agent-state tracking feature (coding-agent hook
+		// events normalised into per-pane states); unrelated to
+		// scrollback-preview, allow-listed per this audit's own guidance.
+		"agent":            {},
 		"alias":            {},
 		"bootstrapadapter": {},
 		// capture: added by the spectrum-tui-design visual-reskin feature
 		// (the offline vhs capture harness's in-memory fakes + fixtures);
 		// unrelated to scrollback-preview, allow-listed per this audit's own
 		// guidance.
-		"capture":       {},
-		"fileutil":      {},
-		"fuzzy":         {},
-		"hooks":         {},
+		"capture": {},
+		// control: added by the daemon control-socket feature (the state
+		// daemon's versioned line protocol, server and client); unrelated to
+		// scrollback-preview, allow-listed per this audit's own guidance.
+		"control": {},
+		// export: added by the scrollback-export feature (xctl export's
+		// txt/ansi/html/cast renderers); a CLI-only reader of the saved
+		// files, allow-listed per this audit's own guidance.
+		"export":   {},
+		"fileutil": {},
+		// frecency: added by the frecency-ranking feature (visit history
+		// store); unrelated to scrollback-preview, allow-listed per this
+		// audit's own guidance.
+		"frecency": {},
+		"fuzzy":    {},
+		// grep: added by the scrollback-search feature (xctl grep and the
+		// picker's find mode); it reads the same saved files preview does
+		// but is unrelated to it, allow-listed per this audit's own guidance.
+		"grep":  {},
+		"hooks": {},
+		// layout: added by the per-project session templates feature
+		// (.portal.yml parsing and capture); unrelated to scrollback-preview,
+		// allow-listed per this audit's own guidance.
+		"layout":        {},
 		"log":           {},
 		"logtest":       {},
 		"portalbintest": {},
@@ -41,10 +41,35 @@ func (a scrollbackReaderAdapter) Tail(paneKey string) ([]byte, error) {
 	return state.TailScrollback(path, a.n)
 }
 
+// Argv returns the saved argv of the live-session pane whose canonical key is
+// paneKey, read from sessions.json. An absent or unreadable index yields nil;
+// the command segment is decoration, never an error surface.
+func (a scrollbackReaderAdapter) Argv(paneKey string) []string {
+	idx, _, err := state.ReadIndex(a.stateDir)
+	if err != nil {
+		return nil
+	}
+	for _, s := range idx.Sessions {
+		if s.Dormant {
+			continue
+		}
+		for _, w := range s.Windows {
+			for _, p := range w.Panes {
+				if state.SanitizePaneKey(s.Name, w.Index, p.Index) == paneKey {
+					return p.Argv
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // Compile-time assertions that the production seams remain satisfied.
 // Placing them in this non-test file means a regression in either
 // direction breaks the production build, not only the test build.
 var (
 	_ TmuxEnumerator   = (*tmux.Client)(nil)
+	_ PaneCapturer     = (*tmux.Client)(nil)
 	_ ScrollbackReader = scrollbackReaderAdapter{}
+	_ PaneArgvReader   = scrollbackReaderAdapter{}
 )
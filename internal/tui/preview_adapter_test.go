@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/leeovery/portal/internal/state"
@@ -71,6 +72,32 @@ func TestScrollbackReaderAdapter_TailReturnsNilNilForMissingBin(t *testing.T) {
 	}
 }
 
+func TestScrollbackReaderAdapter_ArgvReadsLivePaneFromIndex(t *testing.T) {
+	stateDir := t.TempDir()
+	idx := state.Index{Sessions: []state.Session{
+		{Name: "old", Dormant: true, Windows: []state.Window{{Index: 0, Panes: []state.Pane{{Index: 0, Argv: []string{"stale"}}}}}},
+		{Name: "work", Windows: []state.Window{{Index: 2, Panes: []state.Pane{{Index: 1, Argv: []string{"node", "server.js"}}}}}},
+	}}
+	data, err := state.EncodeIndex(idx)
+	if err != nil {
+		t.Fatalf("EncodeIndex: %v", err)
+	}
+	if err := os.WriteFile(state.SessionsJSON(stateDir), data, 0o600); err != nil {
+		t.Fatalf("write sessions.json: %v", err)
+	}
+
+	adapter := scrollbackReaderAdapter{stateDir: stateDir, n: previewTailLines}
+	if got := adapter.Argv(state.SanitizePaneKey("work", 2, 1)); strings.Join(got, " ") != "node server.js" {
+		t.Errorf("Argv(work 2.1) = %q, want [node server.js]", got)
+	}
+	if got := adapter.Argv(state.SanitizePaneKey("old", 0, 0)); got != nil {
+		t.Errorf("Argv(dormant pane) = %q, want nil", got)
+	}
+	if got := (scrollbackReaderAdapter{stateDir: t.TempDir()}).Argv("work__0.0"); got != nil {
+		t.Errorf("Argv without an index = %q, want nil", got)
+	}
+}
+
 func TestScrollbackReaderAdapter_TailReturnsErrForPermissionDenied(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("file mode 0o000 has no equivalent on Windows")
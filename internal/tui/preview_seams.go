@@ -39,3 +39,24 @@ type TmuxEnumerator interface {
 type ScrollbackReader interface {
 	Tail(paneKey string) ([]byte, error)
 }
+
+// PaneCapturer is the seam through which the preview page captures a pane's
+// live content from the running tmux server. It mirrors
+// *tmux.Client.CapturePaneTail so production wiring is the client itself.
+//
+// A live capture is preferred over the saved scrollback because the .bin file
+// trails the pane by up to a daemon tick and is empty for a pane the daemon
+// has not yet saved. Any capture error (no server, pane gone between
+// enumeration and capture) falls back to ScrollbackReader.Tail, so the
+// preview still shows the saved content when the server is down.
+type PaneCapturer interface {
+	CapturePaneTail(target string, lines int) (string, error)
+}
+
+// PaneArgvReader is an optional capability of a ScrollbackReader: the saved
+// command line (state.Pane.Argv) of the pane keyed by paneKey, or nil when the
+// index holds none. The preview type-asserts its reader for it, so a reader
+// without saved argv — every test double — simply renders no command segment.
+type PaneArgvReader interface {
+	Argv(paneKey string) []string
+}
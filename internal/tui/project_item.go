@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/agent"
 	"github.com/leeovery/portal/internal/project"
 	"github.com/leeovery/portal/internal/tui/theme"
 )
@@ -57,6 +58,11 @@ type ProjectDelegate struct {
 	// unchanged, so selection stays glyph-distinct (§2.2). Set from the model's
 	// single colourless flag (applyCanvasMode), mirroring SessionDelegate.
 	Colourless bool
+	// Agents is the rolled-up coding-agent state per project path: the most
+	// urgent state among the live sessions running in the project. While it is
+	// non-empty the name line reserves a trailing agentSlotWidth slot for the
+	// glyph; an empty map leaves both lines as they were.
+	Agents map[string]agent.State
 }
 
 // Height returns 2, matching the two-line item display (name + path). The uniform
@@ -118,8 +124,10 @@ func (d ProjectDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		pathTok = theme.MV.TextMutedBright
 	}
 
-	line1 := d.renderRowLine(m, selected, d.rowToken(projectNameBase, nameTok, selected), pi.Project.Name)
-	line2 := d.renderRowLine(m, selected, d.rowToken(lipgloss.Style{}, pathTok, selected), pi.Project.Path)
+	// Only the name line carries filter highlights: the matches index the
+	// FilterValue, which is the project name.
+	line1 := d.renderRowLine(m, selected, d.rowToken(projectNameBase, nameTok, selected), pi.Project.Name, m.MatchesForItem(index), d.agentTrailing(pi.Project.Path, selected))
+	line2 := d.renderRowLine(m, selected, d.rowToken(lipgloss.Style{}, pathTok, selected), pi.Project.Path, nil, "")
 
 	_, _ = fmt.Fprintf(w, "%s\n%s", line1, line2)
 }
@@ -130,8 +138,11 @@ func (d ProjectDelegate) Render(w io.Writer, m list.Model, index int, item list.
 // (§2.7) and padded so the line is exactly the width — every cell carrying the row
 // background (bg.selection on the selected row, canvas otherwise). textStyle already
 // carries the run's foreground + row background. Both the name line and the path
-// line share this shape so the full-height bar + tint span both uniformly.
-func (d ProjectDelegate) renderRowLine(m list.Model, selected bool, textStyle lipgloss.Style, text string) string {
+// line share this shape so the full-height bar + tint span both uniformly. matches
+// are the filter's matched rune positions in text, highlighted via
+// renderMatchedName (nil renders text plain). trailing is a pre-rendered
+// fixed-width run pinned to the right edge (the agent glyph slot), or "".
+func (d ProjectDelegate) renderRowLine(m list.Model, selected bool, textStyle lipgloss.Style, text string, matches []int, trailing string) string {
 	bg := d.rowBg(selected)
 
 	// Left-bar column (§3.3 / §6.2): the violet ▌ + a trailing cell on the selected
@@ -146,15 +157,15 @@ func (d ProjectDelegate) renderRowLine(m list.Model, selected bool, textStyle li
 	// trailing pad (mirrors SessionDelegate's zero-width fallback).
 	total := m.Width()
 	if total <= 0 {
-		return bar + textStyle.Render(text)
+		return bar + renderMatchedName(text, false, matches, textStyle, d.Mode, selected, d.Colourless) + trailing
 	}
 
-	textWidth := max(total-leftBarColumnWidth, 1)
-	visible := ansi.Truncate(text, textWidth, "…")
-	body := textStyle.Render(visible)
+	textWidth := max(total-leftBarColumnWidth-lipgloss.Width(trailing), 1)
+	visible, truncated := truncateName(text, textWidth)
+	body := renderMatchedName(visible, truncated, matches, textStyle, d.Mode, selected, d.Colourless)
 	pad := bg.Render(padTo("", textWidth-lipgloss.Width(visible)))
 
-	line := bar + body + pad
+	line := bar + body + pad + trailing
 
 	// Safety clamp (§2.7 / §3.5): at pathological narrow widths the fixed 2-cell bar
 	// column plus a 1-cell floored text could assemble to more than total; truncate
@@ -163,6 +174,22 @@ func (d ProjectDelegate) renderRowLine(m list.Model, selected bool, textStyle li
 	return ansi.Truncate(line, total, "…")
 }
 
+// agentTrailing renders the name line's trailing agent slot: the project's
+// rolled-up state glyph followed by the right margin, blank for a project with
+// no agent, and nothing at all while no project has one.
+func (d ProjectDelegate) agentTrailing(path string, selected bool) string {
+	if len(d.Agents) == 0 {
+		return ""
+	}
+	bg := d.rowBg(selected)
+	glyph, tok, ok := agentGlyph(d.Agents[path])
+	if !ok {
+		return bg.Render(padTo("", agentSlotWidth+rowRightMargin))
+	}
+	return d.rowToken(lipgloss.Style{}, tok, selected).Render(glyph) +
+		bg.Render(padTo("", agentSlotWidth+rowRightMargin-lipgloss.Width(glyph)))
+}
+
 // ProjectsToListItems converts a slice of projects to a slice of list.Item.
 func ProjectsToListItems(projects []project.Project) []list.Item {
 	items := make([]list.Item, len(projects))
@@ -7,6 +7,7 @@ import (
 
 	"charm.land/lipgloss/v2"
 	"github.com/leeovery/portal/internal/prefs"
+	"github.com/leeovery/portal/internal/spawn"
 	"github.com/leeovery/portal/internal/tui/theme"
 )
 
@@ -127,8 +128,15 @@ func renderProjectsSectionHeader(count, width int, canvasMode theme.Mode, colour
 // cancel`) differ. Under the NO_COLOR carve-out (§2.5) every hue and the canvas
 // drop; the `N selected` / `esc cancel` text renders intact on the terminal's
 // native fg/bg. The single rendered row is exactly one line.
-func renderMultiSelectHeader(count, width int, mode theme.Mode, colourless bool) string {
+//
+// A non-window burst placement (the `t` toggle) appends ` as tabs` / ` as
+// splits` to the count in text.detail; the default windows placement adds
+// nothing, so the banner is unchanged until the user toggles.
+func renderMultiSelectHeader(count int, placement spawn.Placement, width int, mode theme.Mode, colourless bool) string {
 	left := headerStyle(theme.MV.AccentViolet, mode, colourless).Render(strconv.Itoa(count) + " selected")
+	if placement != "" && placement != spawn.PlacementWindows {
+		left += headerStyle(theme.MV.TextDetail, mode, colourless).Render(" as " + string(placement))
+	}
 	hint := headerStyle(theme.MV.TextDetail, mode, colourless).Render(multiSelectCancelHint)
 	return renderRightAnchoredSectionRow(left, hint, width, mode, colourless)
 }
@@ -8,6 +8,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/leeovery/portal/internal/agent"
 	"github.com/leeovery/portal/internal/tmux"
 	"github.com/leeovery/portal/internal/tui/theme"
 )
@@ -222,6 +223,12 @@ type SessionDelegate struct {
 	// dismiss/refresh; nil-tolerant like Selected. Propagated via the single
 	// sessionDelegate() chokepoint (like Selected).
 	GoneFlagged map[string]struct{}
+	// Agents is the coding-agent state per session name, rolled up to each
+	// session's most urgent pane. While it is non-empty every row reserves an
+	// agentSlotWidth slot between the count and the attached badge, so the
+	// glyphs column-align; an empty map leaves the row layout as it was. Set
+	// from the model's agentStates via sessionDelegate().
+	Agents map[string]agent.State
 }
 
 // isSelected reports whether name is in the marked set. It is nil-safe: a nil set
@@ -305,6 +312,8 @@ func (d SessionDelegate) Render(w io.Writer, m list.Model, index int, item list.
 		row = bg.Render(groupHeaderIndent) + heading + count
 	case SessionItem:
 		row = d.renderSessionRow(m, index, it)
+	case DormantItem:
+		row = d.renderDormantRow(m, index, it)
 	default:
 		return
 	}
@@ -511,17 +520,25 @@ func (d SessionDelegate) renderSessionRow(m list.Model, index int, it SessionIte
 	// no truncation, no right-pinning.
 	total := m.Width()
 	used := leftBarColumnWidth + lipgloss.Width(indent) + nameGap + countSlotWidth + attachedSlotWidth + rowRightMargin
+	if len(d.Agents) > 0 {
+		used += agentSlotWidth
+	}
+
+	// An active filter's matched runes are highlighted in the name column; the
+	// matches index the FilterValue, which is the session name.
+	nameStyle := d.rowToken(nameBase, nameTok, selected)
+	matches := m.MatchesForItem(index)
 
 	var name, namePad string
 	if total <= 0 {
-		name = d.rowToken(nameBase, nameTok, selected).Render(it.Session.Name)
+		name = renderMatchedName(it.Session.Name, false, matches, nameStyle, d.Mode, selected, d.Colourless)
 		namePad = ""
 	} else {
 		// Truncate to the flex width with an ellipsis (§2.7), then pad the remainder
 		// so the gap and the fixed slots are right-pinned and column-aligned.
 		nameWidth := max(total-used, 1)
-		visibleName := ansi.Truncate(it.Session.Name, nameWidth, "…")
-		name = d.rowToken(nameBase, nameTok, selected).Render(visibleName)
+		visibleName, truncated := truncateName(it.Session.Name, nameWidth)
+		name = renderMatchedName(visibleName, truncated, matches, nameStyle, d.Mode, selected, d.Colourless)
 		namePad = bg.Render(padTo("", nameWidth-lipgloss.Width(visibleName)))
 	}
 
@@ -557,7 +574,7 @@ func (d SessionDelegate) renderSessionRow(m list.Model, index int, it SessionIte
 		rightMargin := bg.Render(padTo("", rowRightMargin))
 		trailing = attached + rightMargin
 	}
-	row := indentCell + bar + name + namePad + gap + count + trailing
+	row := indentCell + bar + name + namePad + gap + count + d.renderAgentSlot(d.Agents[it.Session.Name], selected) + trailing
 
 	// Safety clamp (§2.7 / §3.5): the trailing slots are a FIXED 25 cells (bar +
 	// gap + count + attached + indent); at pathological narrow widths the flex name
@@ -572,6 +589,22 @@ func (d SessionDelegate) renderSessionRow(m list.Model, index int, it SessionIte
 	return row
 }
 
+// renderAgentSlot renders the fixed agent-state slot: the state's glyph in its
+// colour, padded to agentSlotWidth, or an empty slot of the same width for a
+// session with no agent. It renders nothing while no session is tracked.
+func (d SessionDelegate) renderAgentSlot(s agent.State, selected bool) string {
+	if len(d.Agents) == 0 {
+		return ""
+	}
+	bg := d.rowBg(selected)
+	glyph, tok, ok := agentGlyph(s)
+	if !ok {
+		return bg.Render(padTo("", agentSlotWidth))
+	}
+	return d.rowToken(lipgloss.Style{}, tok, selected).Render(glyph) +
+		bg.Render(padTo("", agentSlotWidth-lipgloss.Width(glyph)))
+}
+
 // padTo returns s padded on the right with spaces to exactly n cells (or s
 // unchanged when it already meets/exceeds n). A non-positive n yields the empty
 // string. Rendered through a background style by the caller, the spaces carry
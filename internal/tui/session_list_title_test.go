@@ -30,6 +30,16 @@ func TestSessionListTitleForMode(t *testing.T) {
 			mode: prefs.ModeByTag,
 			want: "Sessions — by tag",
 		},
+		{
+			name: "Recent outside tmux",
+			mode: prefs.ModeRecent,
+			want: "Sessions — recent",
+		},
+		{
+			name: "By Status outside tmux",
+			mode: prefs.ModeByStatus,
+			want: "Sessions — by status",
+		},
 		{
 			name:           "Flat inside tmux preserves current decoration",
 			mode:           prefs.ModeFlat,
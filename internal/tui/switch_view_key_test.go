@@ -54,7 +54,9 @@ func TestNextSessionListMode(t *testing.T) {
 	}{
 		{prefs.ModeFlat, prefs.ModeByProject},
 		{prefs.ModeByProject, prefs.ModeByTag},
-		{prefs.ModeByTag, prefs.ModeFlat},
+		{prefs.ModeByTag, prefs.ModeRecent},
+		{prefs.ModeRecent, prefs.ModeByStatus},
+		{prefs.ModeByStatus, prefs.ModeFlat},
 		// Out-of-range value collapses defensively to Flat.
 		{prefs.SessionListMode(99), prefs.ModeFlat},
 	}
@@ -66,11 +68,11 @@ func TestNextSessionListMode(t *testing.T) {
 }
 
 func TestSwitchViewKey(t *testing.T) {
-	t.Run("cycles Flat to By Project to By Tag to Flat on successive s presses", func(t *testing.T) {
+	t.Run("cycles Flat to By Project to By Tag to Recent to By Status to Flat on successive s presses", func(t *testing.T) {
 		persister := &fakeModePersister{}
 		m := newSwitchViewTestModel(prefs.ModeFlat, persister, nil, nil)
 
-		want := []prefs.SessionListMode{prefs.ModeByProject, prefs.ModeByTag, prefs.ModeFlat}
+		want := []prefs.SessionListMode{prefs.ModeByProject, prefs.ModeByTag, prefs.ModeRecent, prefs.ModeByStatus, prefs.ModeFlat}
 		var cur tea.Model = m
 		for i, expected := range want {
 			updated, _ := cur.Update(keyS)
@@ -71,7 +71,7 @@ type Theme struct {
 	AccentCyan   Token // Sessions header, Preview chrome, active tick
 	StateGreen   Token // ● attached, Sessions count, Projects label, ✓ done, success flash
 	StateRed     Token // kill/delete emphasis, ▲
-	AccentOrange Token // filter query / / / type, warning flash ⚠
+	AccentOrange Token // filter query / / / type, filter match highlight, warning flash ⚠
 
 	// Surfaces (tints / borders).
 	Canvas          Token // owned mode-matched canvas (painted on every cell)